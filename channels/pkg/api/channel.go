@@ -80,6 +80,21 @@ type AddonSpec struct {
 
 	// PruneSpec specifies how old objects should be removed (pruned).
 	Prune *PruneSpec `json:"prune,omitempty"`
+
+	// DependsOn lists the names of addons that must be applied and ready before this addon is applied.
+	// An addon is ready once its CustomResourceDefinitions are Established and its workloads are rolled out.
+	// Dependencies on addons that are not part of the channel are ignored.
+	DependsOn []string `json:"dependsOn,omitempty"`
+
+	// DependsOnCRDs lists the names of addons whose CustomResourceDefinitions must be Established before this addon is applied.
+	// Unlike DependsOn, the workloads of these addons need not be ready, so that an addon the workloads need in order
+	// to start, such as the CNI, can itself depend on them.
+	DependsOnCRDs []string `json:"dependsOnCRDs,omitempty"`
+
+	// RequiresCRDs lists the names of CustomResourceDefinitions that must be installed before this addon is applied.
+	// Unlike DependsOnCRDs, the CRDs are usually installed by the user rather than by an addon in the channel;
+	// the addon is skipped until they are present.
+	RequiresCRDs []string `json:"requiresCRDs,omitempty"`
 }
 
 // PruneSpec specifies how old objects should be removed (pruned).
@@ -114,6 +129,11 @@ func (a *Addons) Verify() error {
 		if addon.KubernetesVersion != "" {
 			return fmt.Errorf("bootstrap addon %q has a KubernetesVersion", values.StringValue(addon.Name))
 		}
+		for _, dependency := range append(addon.DependsOn, addon.DependsOnCRDs...) {
+			if dependency == values.StringValue(addon.Name) {
+				return fmt.Errorf("bootstrap addon %q depends on itself", dependency)
+			}
+		}
 	}
 
 	return nil
@@ -35,6 +35,7 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 	"k8s.io/kops/channels/pkg/api"
+	"k8s.io/kops/channels/pkg/signature"
 
 	cmv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -51,6 +52,9 @@ type Addon struct {
 	ChannelName     string
 	ChannelLocation url.URL
 	Spec            *api.AddonSpec
+
+	// Verifier, if set, is used to check the signature of the manifest.
+	Verifier *signature.Verifier
 }
 
 // AddonUpdate holds data about a proposed update to an addon
@@ -192,7 +196,7 @@ func (a *Addon) updateAddon(ctx context.Context, k8sClient kubernetes.Interface,
 	klog.Infof("Applying update from %q", manifestURL)
 
 	// We copy the manifest to a temp file because it is likely e.g. an s3 URL, which kubectl can't read
-	data, err := vfsContext.ReadFile(manifestURL.String())
+	data, err := readVerified(vfsContext, manifestURL.String(), a.Verifier)
 	if err != nil {
 		return fmt.Errorf("error reading manifest: %w", err)
 	}
@@ -234,6 +238,24 @@ func (a *Addon) updateAddon(ctx context.Context, k8sClient kubernetes.Interface,
 	return nil
 }
 
+// WaitForReady blocks until the objects in the addon manifest are ready in the cluster.
+func (a *Addon) WaitForReady(ctx context.Context, vfsContext *vfs.VFSContext, checker *ReadinessChecker) error {
+	manifestURL, err := a.GetManifestFullUrl()
+	if err != nil {
+		return err
+	}
+
+	data, err := readVerified(vfsContext, manifestURL.String(), a.Verifier)
+	if err != nil {
+		return fmt.Errorf("error reading manifest: %w", err)
+	}
+
+	if err := checker.WaitForReady(ctx, data); err != nil {
+		return fmt.Errorf("addon %q is not ready: %w", a.Name, err)
+	}
+	return nil
+}
+
 func (a *Addon) AddNeedsUpdateLabel(ctx context.Context, k8sClient kubernetes.Interface, required *AddonUpdate) error {
 	if required.ExistingVersion != nil {
 		if a.Spec.NeedsRollingUpdate != "" {
@@ -24,6 +24,7 @@ import (
 	"github.com/blang/semver/v4"
 	"k8s.io/klog/v2"
 	"k8s.io/kops/channels/pkg/api"
+	"k8s.io/kops/channels/pkg/signature"
 	"k8s.io/kops/upup/pkg/fi/utils"
 	"k8s.io/kops/util/pkg/vfs"
 )
@@ -32,16 +33,46 @@ type Addons struct {
 	ChannelName     string
 	ChannelLocation url.URL
 	APIObject       *api.Addons
+
+	// Verifier, if set, is used to check the signatures of the channel and its manifests.
+	Verifier *signature.Verifier
 }
 
-func LoadAddons(vfsContext *vfs.VFSContext, name string, location *url.URL) (*Addons, error) {
+// LoadAddons reads the channel from location. If verifier is not nil, the channel must have a valid
+// signature, as must the manifests of its addons when they are read.
+func LoadAddons(vfsContext *vfs.VFSContext, name string, location *url.URL, verifier *signature.Verifier) (*Addons, error) {
 	klog.V(2).Infof("Loading addons channel from %q", location)
-	data, err := vfsContext.ReadFile(location.String())
+	data, err := readVerified(vfsContext, location.String(), verifier)
 	if err != nil {
 		return nil, fmt.Errorf("error reading addons from %q: %v", location, err)
 	}
 
-	return ParseAddons(name, location, data)
+	addons, err := ParseAddons(name, location, data)
+	if err != nil {
+		return nil, err
+	}
+	addons.Verifier = verifier
+	return addons, nil
+}
+
+// readVerified reads the file at location and, if verifier is not nil, checks its detached signature.
+func readVerified(vfsContext *vfs.VFSContext, location string, verifier *signature.Verifier) ([]byte, error) {
+	data, err := vfsContext.ReadFile(location)
+	if err != nil {
+		return nil, err
+	}
+	if verifier == nil {
+		return data, nil
+	}
+
+	sig, err := vfsContext.ReadFile(location + signature.Extension)
+	if err != nil {
+		return nil, fmt.Errorf("reading signature: %w", err)
+	}
+	if err := verifier.Verify(data, sig); err != nil {
+		return nil, fmt.Errorf("verifying signature of %q: %w", location, err)
+	}
+	return data, nil
 }
 
 func ParseAddons(name string, location *url.URL, data []byte) (*Addons, error) {
@@ -95,6 +126,7 @@ func (a *Addons) wrapInAddons() ([]*Addon, error) {
 			ChannelLocation: a.ChannelLocation,
 			Spec:            s,
 			Name:            name,
+			Verifier:        a.Verifier,
 		}
 
 		addons = append(addons, addon)
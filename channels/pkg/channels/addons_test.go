@@ -18,6 +18,14 @@ package channels
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"net/url"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/blang/semver/v4"
@@ -27,7 +35,9 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	fakekubernetes "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/kops/channels/pkg/api"
+	"k8s.io/kops/channels/pkg/signature"
 	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/vfs"
 )
 
 func Test_Filtering(t *testing.T) {
@@ -363,3 +373,76 @@ func Test_InstallPKI(t *testing.T) {
 		t.Errorf("unexpected error: %v", err)
 	}
 }
+
+func Test_LoadAddonsVerified(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier, err := signature.ParseVerifier(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	channel := []byte("kind: Addons\nspec:\n  addons:\n  - name: test\n    manifest: test.yaml\n")
+	manifest := []byte("kind: ConfigMap\n")
+	writeFile := func(name string, data []byte) {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sign := func(data []byte) []byte {
+		sig, err := signature.Sign(key, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sig
+	}
+	writeFile("channel.yaml", channel)
+	writeFile("test.yaml", manifest)
+
+	vfsContext := vfs.NewTestingVFSContext()
+	location, err := url.Parse("file://" + filepath.Join(dir, "channel.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadAddons(vfsContext, "test", location, verifier); err == nil {
+		t.Fatalf("expected error loading unsigned channel")
+	}
+	if _, err := LoadAddons(vfsContext, "test", location, nil); err != nil {
+		t.Fatalf("unexpected error loading channel without verification: %v", err)
+	}
+
+	writeFile("channel.yaml.sig", sign(channel))
+	addons, err := LoadAddons(vfsContext, "test", location, verifier)
+	if err != nil {
+		t.Fatalf("unexpected error loading signed channel: %v", err)
+	}
+	menu, err := addons.GetCurrent(semver.MustParse("1.34.0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	addon := menu.Addons["test"]
+	manifestURL, err := addon.GetManifestFullUrl()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := readVerified(vfsContext, manifestURL.String(), addon.Verifier); err == nil {
+		t.Errorf("expected error reading unsigned manifest")
+	}
+	writeFile("test.yaml.sig", sign([]byte("kind: Secret\n")))
+	if _, err := readVerified(vfsContext, manifestURL.String(), addon.Verifier); err == nil {
+		t.Errorf("expected error reading manifest with mismatched signature")
+	}
+	writeFile("test.yaml.sig", sign(manifest))
+	if _, err := readVerified(vfsContext, manifestURL.String(), addon.Verifier); err != nil {
+		t.Errorf("unexpected error reading signed manifest: %v", err)
+	}
+}
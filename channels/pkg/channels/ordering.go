@@ -0,0 +1,135 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channels
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SortAddons returns the addons in the order in which they should be applied.
+// Dependencies are always applied before the addons that depend on them;
+// otherwise addons are ordered by name so that the order is stable.
+// An error is returned if a dependency is not in the channel, or if the dependencies contain a cycle.
+func SortAddons(addons []*Addon) ([]*Addon, error) {
+	byName := make(map[string]*Addon, len(addons))
+	for _, addon := range addons {
+		byName[addon.Name] = addon
+	}
+
+	// dependencies is the set of in-menu dependencies for each addon, keyed by name
+	dependencies := make(map[string]map[string]bool, len(addons))
+	for _, addon := range addons {
+		deps := make(map[string]bool)
+		for _, dependency := range append(addon.Spec.DependsOn, addon.Spec.DependsOnCRDs...) {
+			if byName[dependency] == nil {
+				return nil, fmt.Errorf("addon %q depends on %q, which is not in the channel", addon.Name, dependency)
+			}
+			deps[dependency] = true
+		}
+		dependencies[addon.Name] = deps
+	}
+
+	var pending []*Addon
+	pending = append(pending, addons...)
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].Name < pending[j].Name
+	})
+
+	applied := make(map[string]bool, len(addons))
+	var sorted []*Addon
+	for len(pending) != 0 {
+		next := -1
+		for i, addon := range pending {
+			ready := true
+			for dependency := range dependencies[addon.Name] {
+				if !applied[dependency] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				next = i
+				break
+			}
+		}
+		if next == -1 {
+			return nil, fmt.Errorf("addon dependency cycle detected: %s", findCycle(pending, dependencies))
+		}
+
+		addon := pending[next]
+		sorted = append(sorted, addon)
+		applied[addon.Name] = true
+		pending = append(pending[:next], pending[next+1:]...)
+	}
+
+	return sorted, nil
+}
+
+// findCycle returns a human-readable description of a dependency cycle among the pending addons.
+func findCycle(pending []*Addon, dependencies map[string]map[string]bool) string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int)
+
+	var path []string
+	var visit func(name string) []string
+	visit = func(name string) []string {
+		state[name] = visiting
+		path = append(path, name)
+
+		var deps []string
+		for dependency := range dependencies[name] {
+			deps = append(deps, dependency)
+		}
+		sort.Strings(deps)
+
+		for _, dependency := range deps {
+			switch state[dependency] {
+			case visiting:
+				for i := range path {
+					if path[i] == dependency {
+						cycle := append([]string{}, path[i:]...)
+						return append(cycle, dependency)
+					}
+				}
+			case unvisited:
+				if cycle := visit(dependency); cycle != nil {
+					return cycle
+				}
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[name] = visited
+		return nil
+	}
+
+	for _, addon := range pending {
+		if state[addon.Name] != unvisited {
+			continue
+		}
+		if cycle := visit(addon.Name); cycle != nil {
+			return strings.Join(cycle, " -> ")
+		}
+	}
+	return "unknown"
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channels
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/kops/channels/pkg/api"
+)
+
+func Test_SortAddons(t *testing.T) {
+	grid := []struct {
+		Name     string
+		Addons   []*Addon
+		Expected []string
+		Error    string
+	}{
+		{
+			Name: "sorted by name",
+			Addons: []*Addon{
+				{Name: "c", Spec: &api.AddonSpec{}},
+				{Name: "a", Spec: &api.AddonSpec{}},
+				{Name: "b", Spec: &api.AddonSpec{}},
+			},
+			Expected: []string{"a", "b", "c"},
+		},
+		{
+			Name: "dependencies take precedence",
+			Addons: []*Addon{
+				{Name: "coredns", Spec: &api.AddonSpec{DependsOn: []string{"networking"}}},
+				{Name: "networking", Spec: &api.AddonSpec{}},
+				{Name: "aws-load-balancer-controller", Spec: &api.AddonSpec{DependsOn: []string{"certmanager"}}},
+				{Name: "certmanager", Spec: &api.AddonSpec{DependsOn: []string{"networking"}}},
+			},
+			Expected: []string{"networking", "certmanager", "aws-load-balancer-controller", "coredns"},
+		},
+		{
+			Name: "CRD dependencies are ordered",
+			Addons: []*Addon{
+				{Name: "a-networking", Spec: &api.AddonSpec{DependsOnCRDs: []string{"certmanager"}}},
+				{Name: "certmanager", Spec: &api.AddonSpec{}},
+			},
+			Expected: []string{"certmanager", "a-networking"},
+		},
+		{
+			Name: "unknown dependencies are rejected",
+			Addons: []*Addon{
+				{Name: "b", Spec: &api.AddonSpec{DependsOn: []string{"missing"}}},
+				{Name: "a", Spec: &api.AddonSpec{}},
+			},
+			Error: `addon "b" depends on "missing", which is not in the channel`,
+		},
+		{
+			Name: "unknown CRD dependencies are rejected",
+			Addons: []*Addon{
+				{Name: "b", Spec: &api.AddonSpec{DependsOnCRDs: []string{"missing"}}},
+			},
+			Error: `addon "b" depends on "missing", which is not in the channel`,
+		},
+		{
+			Name: "cycle",
+			Addons: []*Addon{
+				{Name: "a", Spec: &api.AddonSpec{DependsOn: []string{"b"}}},
+				{Name: "b", Spec: &api.AddonSpec{DependsOn: []string{"c"}}},
+				{Name: "c", Spec: &api.AddonSpec{DependsOn: []string{"a"}}},
+				{Name: "d", Spec: &api.AddonSpec{}},
+			},
+			Error: "addon dependency cycle detected: a -> b -> c -> a",
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.Name, func(t *testing.T) {
+			sorted, err := SortAddons(g.Addons)
+			if g.Error != "" {
+				if err == nil || !strings.Contains(err.Error(), g.Error) {
+					t.Fatalf("expected error %q, got %v", g.Error, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var actual []string
+			for _, addon := range sorted {
+				actual = append(actual, addon.Name)
+			}
+			if !reflect.DeepEqual(actual, g.Expected) {
+				t.Errorf("unexpected order: got %v, expected %v", actual, g.Expected)
+			}
+		})
+	}
+}
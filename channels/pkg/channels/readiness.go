@@ -0,0 +1,180 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channels
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/kubemanifest"
+)
+
+var crdGroupKind = schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}
+
+// ReadinessChecker determines whether the objects in an addon manifest are ready,
+// so that addons which depend on them can be applied.
+type ReadinessChecker struct {
+	Client     dynamic.Interface
+	RESTMapper meta.RESTMapper
+
+	// Interval is how often we poll for readiness.
+	Interval time.Duration
+	// Timeout is how long we wait for readiness before giving up.
+	Timeout time.Duration
+
+	// CRDsOnly limits the check to the CustomResourceDefinitions in the manifest.
+	CRDsOnly bool
+}
+
+// WaitForReady blocks until all the objects in the manifest are ready, or the timeout is reached.
+func (c *ReadinessChecker) WaitForReady(ctx context.Context, manifest []byte) error {
+	objects, err := kubemanifest.LoadObjectsFrom(manifest)
+	if err != nil {
+		return fmt.Errorf("failed to parse objects: %w", err)
+	}
+
+	interval := c.Interval
+	if interval == 0 {
+		interval = 5 * time.Second
+	}
+	timeout := c.Timeout
+	if timeout == 0 {
+		timeout = 5 * time.Minute
+	}
+
+	var notReady string
+	err = wait.PollUntilContextTimeout(ctx, interval, timeout, true, func(ctx context.Context) (bool, error) {
+		for _, object := range objects {
+			if c.CRDsOnly && object.GroupVersionKind().GroupKind() != crdGroupKind {
+				continue
+			}
+			ready, err := c.isReady(ctx, object.GroupVersionKind(), object.GetNamespace(), object.GetName())
+			if err != nil {
+				return false, err
+			}
+			if !ready {
+				notReady = object.GetNamespace() + "/" + object.GetName()
+				klog.V(2).Infof("waiting for %s %s to become ready", object.Kind(), notReady)
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		if notReady != "" {
+			return fmt.Errorf("waiting for %s to become ready: %w", notReady, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// isReady reports whether the object in the cluster is ready.
+// Only kinds that other addons commonly depend on are checked; all other kinds are considered ready once they exist.
+func (c *ReadinessChecker) isReady(ctx context.Context, gvk schema.GroupVersionKind, namespace, name string) (bool, error) {
+	switch gvk.GroupKind() {
+	case crdGroupKind,
+		schema.GroupKind{Group: "apps", Kind: "Deployment"},
+		schema.GroupKind{Group: "apps", Kind: "DaemonSet"},
+		schema.GroupKind{Group: "apps", Kind: "StatefulSet"}:
+	default:
+		return true, nil
+	}
+
+	mapping, err := c.RESTMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return false, fmt.Errorf("unable to find resource for %v: %w", gvk, err)
+	}
+
+	var resource dynamic.ResourceInterface
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		resource = c.Client.Resource(mapping.Resource).Namespace(namespace)
+	} else {
+		resource = c.Client.Resource(mapping.Resource)
+	}
+
+	u, err := resource.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("getting %v %s/%s: %w", gvk, namespace, name, err)
+	}
+
+	return isObjectReady(u), nil
+}
+
+// isObjectReady reports whether a CustomResourceDefinition is established, or a workload has fully rolled out.
+func isObjectReady(u *unstructured.Unstructured) bool {
+	if u.GetDeletionTimestamp() != nil {
+		return false
+	}
+
+	switch u.GroupVersionKind().Kind {
+	case "CustomResourceDefinition":
+		conditions, _, _ := unstructured.NestedSlice(u.Object, "status", "conditions")
+		for _, c := range conditions {
+			condition, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if condition["type"] == "Established" && condition["status"] == "True" {
+				return true
+			}
+		}
+		return false
+
+	case "Deployment", "StatefulSet":
+		if !observedCurrentGeneration(u) {
+			return false
+		}
+		replicas, found, _ := unstructured.NestedInt64(u.Object, "spec", "replicas")
+		if !found {
+			replicas = 1
+		}
+		updated, _, _ := unstructured.NestedInt64(u.Object, "status", "updatedReplicas")
+		ready, _, _ := unstructured.NestedInt64(u.Object, "status", "readyReplicas")
+		return updated >= replicas && ready >= replicas
+
+	case "DaemonSet":
+		if !observedCurrentGeneration(u) {
+			return false
+		}
+		// Pods on nodes that are still joining the cluster do not hold up the dependents,
+		// as long as all pods run the current template and some of them are available.
+		desired, _, _ := unstructured.NestedInt64(u.Object, "status", "desiredNumberScheduled")
+		updated, _, _ := unstructured.NestedInt64(u.Object, "status", "updatedNumberScheduled")
+		unavailable, _, _ := unstructured.NestedInt64(u.Object, "status", "numberUnavailable")
+		return updated >= desired && (desired == 0 || unavailable < desired)
+	}
+
+	return true
+}
+
+func observedCurrentGeneration(u *unstructured.Unstructured) bool {
+	observedGeneration, found, _ := unstructured.NestedInt64(u.Object, "status", "observedGeneration")
+	return found && observedGeneration >= u.GetGeneration()
+}
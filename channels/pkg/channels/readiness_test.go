@@ -0,0 +1,286 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channels
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"sigs.k8s.io/yaml"
+)
+
+func parseObject(t *testing.T, s string) *unstructured.Unstructured {
+	b, err := yaml.YAMLToJSON([]byte(s))
+	if err != nil {
+		t.Fatalf("error parsing object: %v", err)
+	}
+	u := &unstructured.Unstructured{}
+	if err := u.UnmarshalJSON(b); err != nil {
+		t.Fatalf("error parsing object: %v", err)
+	}
+	return u
+}
+
+func TestIsObjectReady(t *testing.T) {
+	grid := []struct {
+		Name     string
+		Object   string
+		Expected bool
+	}{
+		{
+			Name: "established crd",
+			Object: `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+status:
+  conditions:
+  - type: NamesAccepted
+    status: "True"
+  - type: Established
+    status: "True"
+`,
+			Expected: true,
+		},
+		{
+			Name: "crd not established",
+			Object: `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+status:
+  conditions:
+  - type: Established
+    status: "False"
+`,
+		},
+		{
+			Name: "deployment rolled out",
+			Object: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  generation: 2
+spec:
+  replicas: 2
+status:
+  observedGeneration: 2
+  updatedReplicas: 2
+  readyReplicas: 2
+`,
+			Expected: true,
+		},
+		{
+			Name: "deployment generation not observed",
+			Object: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  generation: 3
+spec:
+  replicas: 2
+status:
+  observedGeneration: 2
+  updatedReplicas: 2
+  readyReplicas: 2
+`,
+		},
+		{
+			Name: "deployment not ready",
+			Object: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  generation: 1
+spec:
+  replicas: 2
+status:
+  observedGeneration: 1
+  updatedReplicas: 2
+  readyReplicas: 1
+`,
+		},
+		{
+			Name: "daemonset rolled out",
+			Object: `
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  generation: 1
+status:
+  observedGeneration: 1
+  desiredNumberScheduled: 3
+  updatedNumberScheduled: 3
+  numberAvailable: 3
+`,
+			Expected: true,
+		},
+		{
+			Name: "daemonset scaling up",
+			Object: `
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  generation: 1
+status:
+  observedGeneration: 1
+  desiredNumberScheduled: 5
+  updatedNumberScheduled: 5
+  numberAvailable: 3
+  numberUnavailable: 2
+`,
+			Expected: true,
+		},
+		{
+			Name: "daemonset rolling out",
+			Object: `
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  generation: 2
+status:
+  observedGeneration: 2
+  desiredNumberScheduled: 3
+  updatedNumberScheduled: 1
+  numberAvailable: 3
+`,
+		},
+		{
+			Name: "daemonset with no available pods",
+			Object: `
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  generation: 1
+status:
+  observedGeneration: 1
+  desiredNumberScheduled: 2
+  updatedNumberScheduled: 2
+  numberUnavailable: 2
+`,
+		},
+		{
+			Name: "daemonset with no nodes",
+			Object: `
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  generation: 1
+status:
+  observedGeneration: 1
+`,
+			Expected: true,
+		},
+		{
+			Name: "deleting",
+			Object: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  deletionTimestamp: "2026-01-01T00:00:00Z"
+`,
+		},
+		{
+			Name: "other kinds",
+			Object: `
+apiVersion: v1
+kind: ConfigMap
+`,
+			Expected: true,
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.Name, func(t *testing.T) {
+			if actual := isObjectReady(parseObject(t, g.Object)); actual != g.Expected {
+				t.Errorf("expected ready=%v, got %v", g.Expected, actual)
+			}
+		})
+	}
+}
+
+func TestReadinessChecker(t *testing.T) {
+	manifest := `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: issuers.cert-manager.io
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: cert-manager
+  namespace: cert-manager
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: cert-manager
+  namespace: cert-manager
+`
+	crd := parseObject(t, `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: issuers.cert-manager.io
+status:
+  conditions:
+  - type: Established
+    status: "True"
+`)
+	// The pods of the deployment cannot start before the CNI
+	deployment := parseObject(t, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: cert-manager
+  namespace: cert-manager
+  generation: 1
+spec:
+  replicas: 1
+status:
+  observedGeneration: 1
+`)
+
+	restMapper := meta.NewDefaultRESTMapper(nil)
+	restMapper.Add(schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"}, meta.RESTScopeRoot)
+	restMapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), crd, deployment)
+
+	checker := &ReadinessChecker{
+		Client:     client,
+		RESTMapper: restMapper,
+		Interval:   10 * time.Millisecond,
+		Timeout:    100 * time.Millisecond,
+	}
+
+	ctx := context.TODO()
+	err := checker.WaitForReady(ctx, []byte(manifest))
+	if err == nil || !strings.Contains(err.Error(), "cert-manager/cert-manager") {
+		t.Errorf("expected deployment not to be ready, got %v", err)
+	}
+
+	checker.CRDsOnly = true
+	if err := checker.WaitForReady(ctx, []byte(manifest)); err != nil {
+		t.Errorf("expected CRDs to be ready, got %v", err)
+	}
+}
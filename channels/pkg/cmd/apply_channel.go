@@ -22,27 +22,42 @@ import (
 	"io"
 	"net/url"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/blang/semver/v4"
 	certmanager "github.com/cert-manager/cert-manager/pkg/client/clientset/versioned"
 	"github.com/spf13/cobra"
 	"go.uber.org/multierr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/restmapper"
+	"k8s.io/klog/v2"
 
 	"k8s.io/kops/channels/pkg/channels"
+	"k8s.io/kops/channels/pkg/signature"
 	"k8s.io/kops/util/pkg/tables"
 	"k8s.io/kops/util/pkg/vfs"
 )
 
 type ApplyChannelOptions struct {
 	Yes bool
+
+	// DependencyTimeout is how long we wait for the dependencies of an addon to become ready.
+	DependencyTimeout time.Duration
+
+	// VerifyKeys is the location of PEM-encoded public keys; if set, the channel and
+	// every manifest must have a detached signature made by one of these keys.
+	VerifyKeys string
 }
 
 func NewCmdApplyChannel(f *ChannelsFactory, out io.Writer) *cobra.Command {
-	var options ApplyChannelOptions
+	options := ApplyChannelOptions{
+		DependencyTimeout: 5 * time.Minute,
+	}
 
 	cmd := &cobra.Command{
 		Use:   "channel CHANNEL",
@@ -54,6 +69,8 @@ func NewCmdApplyChannel(f *ChannelsFactory, out io.Writer) *cobra.Command {
 	}
 
 	cmd.Flags().BoolVar(&options.Yes, "yes", false, "Apply update")
+	cmd.Flags().DurationVar(&options.DependencyTimeout, "dependency-timeout", options.DependencyTimeout, "Maximum time to wait for the dependencies of an addon to become ready")
+	cmd.Flags().StringVar(&options.VerifyKeys, "verify-keys", options.VerifyKeys, "Location of PEM-encoded public keys; if set, refuse to apply channels and manifests that are not signed by one of them")
 
 	return cmd
 }
@@ -107,16 +124,28 @@ func RunApplyChannel(ctx context.Context, f *ChannelsFactory, out io.Writer, opt
 
 	channelLocation := args[0]
 
+	var verifier *signature.Verifier
+	if options.VerifyKeys != "" {
+		keys, err := f.VFSContext().ReadFile(options.VerifyKeys)
+		if err != nil {
+			return fmt.Errorf("reading verification keys: %w", err)
+		}
+		verifier, err = signature.ParseVerifier(keys)
+		if err != nil {
+			return fmt.Errorf("parsing verification keys from %q: %w", options.VerifyKeys, err)
+		}
+	}
+
 	// menu is the expected list of addons in the cluster and their configurations.
-	menu, err := buildMenu(f.VFSContext(), kubernetesVersion, channelLocation)
+	menu, err := buildMenu(f.VFSContext(), kubernetesVersion, channelLocation, verifier)
 	if err != nil {
 		return fmt.Errorf("cannot build the addon menu from args: %w", err)
 	}
 
-	return applyMenu(ctx, menu, f.VFSContext(), k8sClient, cmClient, dynamicClient, restMapper, options.Yes)
+	return applyMenu(ctx, menu, f.VFSContext(), k8sClient, cmClient, dynamicClient, restMapper, options.Yes, options.DependencyTimeout)
 }
 
-func applyMenu(ctx context.Context, menu *channels.AddonMenu, vfsContext *vfs.VFSContext, k8sClient kubernetes.Interface, cmClient certmanager.Interface, dynamicClient dynamic.Interface, restMapper *restmapper.DeferredDiscoveryRESTMapper, apply bool) error {
+func applyMenu(ctx context.Context, menu *channels.AddonMenu, vfsContext *vfs.VFSContext, k8sClient kubernetes.Interface, cmClient certmanager.Interface, dynamicClient dynamic.Interface, restMapper *restmapper.DeferredDiscoveryRESTMapper, apply bool, dependencyTimeout time.Duration) error {
 	// channelVersions is the list of installed addons in the cluster.
 	// It is keyed by <namespace>:<addon name>.
 	channelVersions, err := getChannelVersions(ctx, k8sClient)
@@ -129,6 +158,11 @@ func applyMenu(ctx context.Context, menu *channels.AddonMenu, vfsContext *vfs.VF
 		return fmt.Errorf("failed to get updates: %w", err)
 	}
 
+	updates, needUpdates, err = skipMissingCRDs(ctx, dynamicClient, updates, needUpdates)
+	if err != nil {
+		return err
+	}
+
 	if len(updates) == 0 {
 		fmt.Printf("No update required\n")
 		return nil
@@ -180,11 +214,30 @@ func applyMenu(ctx context.Context, menu *channels.AddonMenu, vfsContext *vfs.VF
 		RESTMapper: restMapper,
 	}
 
+	checker := &channels.ReadinessChecker{
+		Client:     dynamicClient,
+		RESTMapper: restMapper,
+		Timeout:    dependencyTimeout,
+	}
+
 	var merr error
 
+	// failed records the addons that could not be updated, so that we don't apply their dependents.
+	failed := make(map[string]bool)
+	// ready records the dependencies we have already found to be ready.
+	ready := make(map[string]bool)
+
+	// needUpdates is sorted, so dependencies are always updated before the addons that depend on them.
 	for _, needUpdate := range needUpdates {
+		if err := waitForDependencies(ctx, vfsContext, menu, needUpdate, checker, failed, ready); err != nil {
+			failed[needUpdate.Name] = true
+			merr = multierr.Append(merr, fmt.Errorf("updating %q: %w", needUpdate.Name, err))
+			continue
+		}
+
 		update, err := needUpdate.EnsureUpdated(ctx, vfsContext, k8sClient, cmClient, pruner, applier, channelVersions[needUpdate.GetNamespace()+":"+needUpdate.Name])
 		if err != nil {
+			failed[needUpdate.Name] = true
 			merr = multierr.Append(merr, fmt.Errorf("updating %q: %w", needUpdate.Name, err))
 		} else if update != nil {
 			fmt.Printf("Updated %q\n", update.Name)
@@ -194,10 +247,61 @@ func applyMenu(ctx context.Context, menu *channels.AddonMenu, vfsContext *vfs.VF
 	return merr
 }
 
+// waitForDependencies blocks until all the dependencies of the addon are ready.
+func waitForDependencies(ctx context.Context, vfsContext *vfs.VFSContext, menu *channels.AddonMenu, addon *channels.Addon, checker *channels.ReadinessChecker, failed map[string]bool, ready map[string]bool) error {
+	for _, name := range addon.Spec.DependsOn {
+		if err := waitForDependency(ctx, vfsContext, menu, addon, name, checker, failed, ready); err != nil {
+			return err
+		}
+	}
+
+	crdChecker := *checker
+	crdChecker.CRDsOnly = true
+	for _, name := range addon.Spec.DependsOnCRDs {
+		if err := waitForDependency(ctx, vfsContext, menu, addon, name, &crdChecker, failed, ready); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// waitForDependency blocks until the named dependency of the addon is ready, as determined by the checker.
+// An addon that is fully ready is also ready for a check limited to its CustomResourceDefinitions.
+func waitForDependency(ctx context.Context, vfsContext *vfs.VFSContext, menu *channels.AddonMenu, addon *channels.Addon, name string, checker *channels.ReadinessChecker, failed map[string]bool, ready map[string]bool) error {
+	key := name
+	if checker.CRDsOnly {
+		key = name + "/crds"
+	}
+
+	dependency := menu.Addons[name]
+	if dependency == nil || ready[name] || ready[key] {
+		return nil
+	}
+	if failed[name] {
+		return fmt.Errorf("dependency %q was not applied", name)
+	}
+
+	klog.Infof("waiting for dependency %q of %q to become ready", name, addon.Name)
+	if err := dependency.WaitForReady(ctx, vfsContext, checker); err != nil {
+		return err
+	}
+	ready[key] = true
+	return nil
+}
+
 func getUpdates(ctx context.Context, menu *channels.AddonMenu, k8sClient kubernetes.Interface, cmClient certmanager.Interface, channelVersions map[string]*channels.ChannelVersion) ([]*channels.AddonUpdate, []*channels.Addon, error) {
+	var all []*channels.Addon
+	for _, addon := range menu.Addons {
+		all = append(all, addon)
+	}
+	sorted, err := channels.SortAddons(all)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	var updates []*channels.AddonUpdate
 	var needUpdates []*channels.Addon
-	for _, addon := range menu.Addons {
+	for _, addon := range sorted {
 		update, err := addon.GetRequiredUpdates(ctx, k8sClient, cmClient, channelVersions[addon.GetNamespace()+":"+addon.Name])
 		if err != nil {
 			return nil, nil, fmt.Errorf("error checking for required update: %v", err)
@@ -210,6 +314,58 @@ func getUpdates(ctx context.Context, menu *channels.AddonMenu, k8sClient kuberne
 	return updates, needUpdates, nil
 }
 
+// skipMissingCRDs removes the updates of addons whose required CustomResourceDefinitions are not installed,
+// and of the addons that depend on them, as needUpdates is sorted.
+// They are applied by a later run, once the CRDs have been installed.
+func skipMissingCRDs(ctx context.Context, dynamicClient dynamic.Interface, updates []*channels.AddonUpdate, needUpdates []*channels.Addon) ([]*channels.AddonUpdate, []*channels.Addon, error) {
+	crds := dynamicClient.Resource(schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"})
+
+	// installed caches the CRDs we have already looked up.
+	installed := make(map[string]bool)
+	// skipped records the addons we are not applying, so that their dependents are skipped too.
+	skipped := make(map[string]bool)
+
+	var keptUpdates []*channels.AddonUpdate
+	var keptNeedUpdates []*channels.Addon
+	for i, addon := range needUpdates {
+		var skippedDependencies []string
+		for _, dependency := range append(addon.Spec.DependsOn, addon.Spec.DependsOnCRDs...) {
+			if skipped[dependency] {
+				skippedDependencies = append(skippedDependencies, dependency)
+			}
+		}
+		if len(skippedDependencies) != 0 {
+			klog.Infof("skipping %q until its dependencies %s are applied", addon.Name, strings.Join(skippedDependencies, ", "))
+			skipped[addon.Name] = true
+			continue
+		}
+
+		var missing []string
+		for _, name := range addon.Spec.RequiresCRDs {
+			found, ok := installed[name]
+			if !ok {
+				_, err := crds.Get(ctx, name, metav1.GetOptions{})
+				if err != nil && !apierrors.IsNotFound(err) {
+					return nil, nil, fmt.Errorf("getting CustomResourceDefinition %q: %w", name, err)
+				}
+				found = err == nil
+				installed[name] = found
+			}
+			if !found {
+				missing = append(missing, name)
+			}
+		}
+		if len(missing) != 0 {
+			klog.Infof("skipping %q until the CustomResourceDefinitions %s are installed", addon.Name, strings.Join(missing, ", "))
+			skipped[addon.Name] = true
+			continue
+		}
+		keptUpdates = append(keptUpdates, updates[i])
+		keptNeedUpdates = append(keptNeedUpdates, addon)
+	}
+	return keptUpdates, keptNeedUpdates, nil
+}
+
 func getChannelVersions(ctx context.Context, k8sClient kubernetes.Interface) (map[string]*channels.ChannelVersion, error) {
 	namespaces, err := k8sClient.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
 	if err != nil {
@@ -227,7 +383,7 @@ func getChannelVersions(ctx context.Context, k8sClient kubernetes.Interface) (ma
 	return channelVersions, nil
 }
 
-func buildMenu(vfsContext *vfs.VFSContext, kubernetesVersion semver.Version, channelLocation string) (*channels.AddonMenu, error) {
+func buildMenu(vfsContext *vfs.VFSContext, kubernetesVersion semver.Version, channelLocation string, verifier *signature.Verifier) (*channels.AddonMenu, error) {
 	menu := channels.NewAddonMenu()
 
 	location, err := url.Parse(channelLocation)
@@ -240,7 +396,7 @@ func buildMenu(vfsContext *vfs.VFSContext, kubernetesVersion semver.Version, cha
 		// https://raw.githubusercontent.com/kubernetes/kops/master/addons/<name>/addon.yaml
 		return nil, fmt.Errorf("legacy addons are deprecated and unmaintained, use managed addons instead of %s", expanded)
 	}
-	o, err := channels.LoadAddons(vfsContext, channelLocation, location)
+	o, err := channels.LoadAddons(vfsContext, channelLocation, location, verifier)
 	if err != nil {
 		return nil, fmt.Errorf("error loading channel %q: %v", location, err)
 	}
@@ -18,11 +18,16 @@ package cmd
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	cmfake "github.com/cert-manager/cert-manager/pkg/client/clientset/versioned/fake"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
 	fakek8s "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/kops/channels/pkg/api"
 	"k8s.io/kops/channels/pkg/channels"
@@ -77,3 +82,53 @@ func TestGetUpdates(t *testing.T) {
 		t.Errorf("expected update in kube-system, but update applied to %q", needUpdates[0].GetNamespace())
 	}
 }
+
+func TestSkipMissingCRDs(t *testing.T) {
+	crd := &unstructured.Unstructured{}
+	crd.SetAPIVersion("apiextensions.k8s.io/v1")
+	crd.SetKind("CustomResourceDefinition")
+	crd.SetName("podmonitors.monitoring.coreos.com")
+
+	scheme := runtime.NewScheme()
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme, map[schema.GroupVersionResource]string{
+		{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}: "CustomResourceDefinitionList",
+	}, crd)
+
+	addon := func(name string, requiresCRDs ...string) *channels.Addon {
+		return &channels.Addon{
+			Name: name,
+			Spec: &api.AddonSpec{
+				Name:         fi.PtrTo(name),
+				RequiresCRDs: requiresCRDs,
+			},
+		}
+	}
+	dependent := addon("dependent")
+	dependent.Spec.DependsOn = []string{"missing"}
+	needUpdates := []*channels.Addon{
+		addon("plain"),
+		addon("installed", "podmonitors.monitoring.coreos.com"),
+		addon("missing", "podmonitors.monitoring.coreos.com", "servicemonitors.monitoring.coreos.com"),
+		dependent,
+	}
+	var updates []*channels.AddonUpdate
+	for _, needUpdate := range needUpdates {
+		updates = append(updates, &channels.AddonUpdate{Name: needUpdate.Name})
+	}
+
+	updates, needUpdates, err := skipMissingCRDs(context.Background(), dynamicClient, updates, needUpdates)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var names []string
+	for i, needUpdate := range needUpdates {
+		if updates[i].Name != needUpdate.Name {
+			t.Errorf("update %q does not match addon %q", updates[i].Name, needUpdate.Name)
+		}
+		names = append(names, needUpdate.Name)
+	}
+	if got, want := strings.Join(names, ","), "plain,installed"; got != want {
+		t.Errorf("expected addons %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package signature implements detached signatures for addon channels and manifests.
+//
+// Signatures are base64-encoded and stored next to the signed file, with Extension appended
+// to its location. The format matches `cosign sign-blob --key`: ECDSA and RSA keys sign the
+// SHA-256 digest of the file (ASN.1 and PKCS#1 v1.5 respectively), Ed25519 keys sign the file itself.
+package signature
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+// Extension is appended to the location of a channel or manifest to find its detached signature.
+const Extension = ".sig"
+
+// Sign returns the base64-encoded detached signature of data.
+func Sign(signer crypto.Signer, data []byte) ([]byte, error) {
+	var sig []byte
+	var err error
+	switch signer.Public().(type) {
+	case ed25519.PublicKey:
+		sig, err = signer.Sign(rand.Reader, data, crypto.Hash(0))
+	case *rsa.PublicKey, *ecdsa.PublicKey:
+		digest := sha256.Sum256(data)
+		sig, err = signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", signer.Public())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("signing: %w", err)
+	}
+
+	encoded := make([]byte, base64.StdEncoding.EncodedLen(len(sig)))
+	base64.StdEncoding.Encode(encoded, sig)
+	return encoded, nil
+}
+
+// Verifier checks detached signatures against a set of trusted public keys.
+type Verifier struct {
+	keys []crypto.PublicKey
+}
+
+// ParseVerifier builds a Verifier from PEM-encoded public keys or certificates.
+func ParseVerifier(data []byte) (*Verifier, error) {
+	v := &Verifier{}
+
+	rest := data
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+
+		switch block.Type {
+		case "CERTIFICATE":
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("parsing certificate: %w", err)
+			}
+			v.keys = append(v.keys, cert.PublicKey)
+		default:
+			// Accept both PKIX and PKCS#1 encodings, whatever the block type says;
+			// the keystore writes PKIX keys in "RSA PUBLIC KEY" blocks.
+			if key, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+				v.keys = append(v.keys, key)
+			} else if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+				v.keys = append(v.keys, key)
+			} else {
+				return nil, fmt.Errorf("parsing %q block: unrecognized public key", block.Type)
+			}
+		}
+	}
+
+	if len(v.keys) == 0 {
+		return nil, errors.New("no public keys found")
+	}
+	return v, nil
+}
+
+// Verify returns nil if sig is a valid signature of data by one of the trusted keys.
+func (v *Verifier) Verify(data []byte, sig []byte) error {
+	decoded, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(sig)))
+	if err != nil {
+		return fmt.Errorf("decoding signature: %w", err)
+	}
+
+	digest := sha256.Sum256(data)
+	for _, key := range v.keys {
+		switch key := key.(type) {
+		case *rsa.PublicKey:
+			if rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], decoded) == nil {
+				return nil
+			}
+		case *ecdsa.PublicKey:
+			if ecdsa.VerifyASN1(key, digest[:], decoded) {
+				return nil
+			}
+		case ed25519.PublicKey:
+			if ed25519.Verify(key, data, decoded) {
+				return nil
+			}
+		}
+	}
+	return errors.New("signature was not made by a trusted key")
+}
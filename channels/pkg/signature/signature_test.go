@@ -0,0 +1,98 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signature
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+)
+
+func TestSignVerify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	untrustedKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var trusted []byte
+	for i, signer := range []crypto.Signer{rsaKey, ecdsaKey, ed25519Key} {
+		der, err := x509.MarshalPKIXPublicKey(signer.Public())
+		if err != nil {
+			t.Fatal(err)
+		}
+		blockType := "PUBLIC KEY"
+		if i == 0 {
+			// The keystore uses this block type for PKIX keys.
+			blockType = "RSA PUBLIC KEY"
+		}
+		trusted = append(trusted, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})...)
+	}
+
+	verifier, err := ParseVerifier(trusted)
+	if err != nil {
+		t.Fatalf("ParseVerifier failed: %v", err)
+	}
+
+	data := []byte("kind: Addons\n")
+	for _, signer := range []crypto.Signer{rsaKey, ecdsaKey, ed25519Key} {
+		sig, err := Sign(signer, data)
+		if err != nil {
+			t.Fatalf("Sign failed for %T: %v", signer, err)
+		}
+		if err := verifier.Verify(data, append(sig, '\n')); err != nil {
+			t.Errorf("Verify failed for %T: %v", signer, err)
+		}
+		if err := verifier.Verify([]byte("kind: Tampered\n"), sig); err == nil {
+			t.Errorf("Verify of tampered data succeeded for %T", signer)
+		}
+	}
+
+	sig, err := Sign(untrustedKey, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := verifier.Verify(data, sig); err == nil {
+		t.Errorf("Verify with untrusted key succeeded")
+	}
+}
+
+func TestParseVerifierErrors(t *testing.T) {
+	if _, err := ParseVerifier([]byte("not pem")); err == nil {
+		t.Errorf("expected error for data without keys")
+	}
+	if _, err := ParseVerifier(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: []byte("garbage")})); err == nil {
+		t.Errorf("expected error for invalid key")
+	}
+}
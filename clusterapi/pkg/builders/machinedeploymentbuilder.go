@@ -21,9 +21,12 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/kops/pkg/apis/kops"
+	nodeidentitygce "k8s.io/kops/pkg/nodeidentity/gce"
 	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
 	"k8s.io/kops/upup/pkg/fi/cloudup/gce/gcemetadata"
 )
@@ -62,10 +65,22 @@ type MachineDeploymentBuilder struct {
 	// AdditionalMetadata is additional metadata to add to the instances
 	AdditionalMetadata map[string]string
 
+	// InstanceGroupName, if set, is the kops InstanceGroup that the machines replace.
+	// The nodes then get their configuration (including labels and taints) from the InstanceGroup.
+	InstanceGroupName string
+
+	// RollingUpdate, if set, configures how the MachineDeployments roll out changes, as for the kops InstanceGroup.
+	RollingUpdate *kops.RollingUpdate
+
+	// HealthCheck, if set, creates a MachineHealthCheck to remediate unhealthy machines.
+	HealthCheck *HealthCheck
+
 	// capiMachineDeployments holds the MachineDeployment objects,
 	// we create one per zone
 	capiMachineDeployments map[string]*unstructured.Unstructured
 
+	capiMachineHealthCheck *unstructured.Unstructured
+
 	capiInfra          *unstructured.Unstructured
 	capiConfigTemplate *unstructured.Unstructured
 }
@@ -108,6 +123,20 @@ func (b *MachineDeploymentBuilder) buildMachineDeployments() error {
 			"infrastructureRef": infrastructureRef,
 		}
 
+		mdSpec := map[string]any{
+			"clusterName": escapedClusterName,
+			"replicas":    replicasByZone[i],
+			"template": map[string]any{
+				"metadata": map[string]any{
+					"labels": b.machineLabels(),
+				},
+				"spec": spec,
+			},
+		}
+		if b.RollingUpdate != nil {
+			mdSpec["strategy"] = buildStrategy(b.RollingUpdate)
+		}
+
 		obj := map[string]any{
 			"apiVersion": "cluster.x-k8s.io/v1beta1",
 			"kind":       "MachineDeployment",
@@ -115,13 +144,7 @@ func (b *MachineDeploymentBuilder) buildMachineDeployments() error {
 				"name":      name,
 				"namespace": b.Namespace,
 			},
-			"spec": map[string]any{
-				"clusterName": escapedClusterName,
-				"replicas":    replicasByZone[i],
-				"template": map[string]any{
-					"spec": spec,
-				},
-			},
+			"spec": mdSpec,
 		}
 
 		u := &unstructured.Unstructured{Object: obj}
@@ -132,6 +155,98 @@ func (b *MachineDeploymentBuilder) buildMachineDeployments() error {
 
 }
 
+// machineLabels are the labels on the Machines, by which the MachineHealthCheck selects them.
+func (b *MachineDeploymentBuilder) machineLabels() map[string]any {
+	return map[string]any{
+		kops.NodeLabelInstanceGroup: b.Name,
+	}
+}
+
+// buildStrategy maps the kops rolling update settings to the rollout strategy of a MachineDeployment.
+// The defaults are those of kops rolling-update on GCE: no surge, and one machine unavailable at a time.
+// Percentages are resolved by Cluster API for each MachineDeployment, that is for each zone.
+func buildStrategy(rollingUpdate *kops.RollingUpdate) map[string]any {
+	if rollingUpdate.DrainAndTerminate != nil && !*rollingUpdate.DrainAndTerminate {
+		// kops rolling-update does not replace these instances, so neither does Cluster API, until the Machines are deleted.
+		return map[string]any{
+			"type": "OnDelete",
+		}
+	}
+
+	maxSurge := intstr.FromInt32(0)
+	if rollingUpdate.MaxSurge != nil {
+		maxSurge = *rollingUpdate.MaxSurge
+	}
+	maxUnavailable := intstr.FromInt32(0)
+	if maxSurge.Type == intstr.Int && maxSurge.IntVal == 0 {
+		maxUnavailable = intstr.FromInt32(1)
+	}
+	if rollingUpdate.MaxUnavailable != nil {
+		maxUnavailable = *rollingUpdate.MaxUnavailable
+	}
+
+	return map[string]any{
+		"type": "RollingUpdate",
+		"rollingUpdate": map[string]any{
+			"maxSurge":       intOrStringValue(maxSurge),
+			"maxUnavailable": intOrStringValue(maxUnavailable),
+		},
+	}
+}
+
+func intOrStringValue(v intstr.IntOrString) any {
+	if v.Type == intstr.String {
+		return v.StrVal
+	}
+	return int64(v.IntVal)
+}
+
+// HealthCheck configures the MachineHealthCheck for the machines.
+type HealthCheck struct {
+	// UnhealthyTimeout is how long a node can be not Ready before its machine is remediated.
+	UnhealthyTimeout time.Duration
+	// NodeStartupTimeout is how long a machine can take to join the cluster before it is remediated.
+	NodeStartupTimeout time.Duration
+	// MaxUnhealthy stops remediation while more than this many machines are unhealthy, so that a wider outage is not made worse.
+	MaxUnhealthy intstr.IntOrString
+}
+
+func (b *MachineDeploymentBuilder) buildMachineHealthCheck() error {
+	if b.HealthCheck == nil {
+		return nil
+	}
+
+	var unhealthyConditions []any
+	for _, status := range []string{"False", "Unknown"} {
+		unhealthyConditions = append(unhealthyConditions, map[string]any{
+			"type":    "Ready",
+			"status":  status,
+			"timeout": b.HealthCheck.UnhealthyTimeout.String(),
+		})
+	}
+
+	obj := map[string]any{
+		"apiVersion": "cluster.x-k8s.io/v1beta1",
+		"kind":       "MachineHealthCheck",
+		"metadata": map[string]any{
+			"name":      b.Name,
+			"namespace": b.Namespace,
+		},
+		"spec": map[string]any{
+			"clusterName": gce.SafeClusterName(b.ClusterName),
+			"selector": map[string]any{
+				"matchLabels": b.machineLabels(),
+			},
+			"unhealthyConditions": unhealthyConditions,
+			"maxUnhealthy":        intOrStringValue(b.HealthCheck.MaxUnhealthy),
+			"nodeStartupTimeout":  b.HealthCheck.NodeStartupTimeout.String(),
+		},
+	}
+
+	b.capiMachineHealthCheck = &unstructured.Unstructured{Object: obj}
+	return nil
+}
+
 func (b *MachineDeploymentBuilder) createKopsConfigTemplate(ctx context.Context) error {
 	templateSpec := map[string]any{}
 
@@ -159,6 +274,9 @@ func (b *MachineDeploymentBuilder) createGCPMachineTemplate(ctx context.Context)
 	metadata := map[string]string{
 		gcemetadata.MetadataKeyClusterName: b.ClusterName,
 	}
+	if b.InstanceGroupName != "" {
+		metadata[nodeidentitygce.MetadataKeyInstanceGroupName] = b.InstanceGroupName
+	}
 	for k, v := range b.AdditionalMetadata {
 		metadata[k] = v
 	}
@@ -225,6 +343,10 @@ func (b *MachineDeploymentBuilder) BuildObjects(ctx context.Context) ([]*unstruc
 		return nil, fmt.Errorf("error building machine deployments: %w", err)
 	}
 
+	if err := b.buildMachineHealthCheck(); err != nil {
+		return nil, fmt.Errorf("error building machine health check: %w", err)
+	}
+
 	var objects []*unstructured.Unstructured
 	objects = append(objects, b.capiConfigTemplate)
 	objects = append(objects, b.capiInfra)
@@ -239,5 +361,9 @@ func (b *MachineDeploymentBuilder) BuildObjects(ctx context.Context) ([]*unstruc
 
 	objects = append(objects, machineDeployments...)
 
+	if b.capiMachineHealthCheck != nil {
+		objects = append(objects, b.capiMachineHealthCheck)
+	}
+
 	return objects, nil
 }
@@ -0,0 +1,164 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builders
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/kops/pkg/apis/kops"
+	nodeidentitygce "k8s.io/kops/pkg/nodeidentity/gce"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func TestBuildStrategy(t *testing.T) {
+	percent := intstr.FromString("20%")
+	zero := intstr.FromInt32(0)
+	two := intstr.FromInt32(2)
+
+	grid := []struct {
+		name          string
+		rollingUpdate kops.RollingUpdate
+		want          map[string]any
+	}{
+		{
+			name:          "defaults",
+			rollingUpdate: kops.RollingUpdate{},
+			want: map[string]any{
+				"type":          "RollingUpdate",
+				"rollingUpdate": map[string]any{"maxSurge": int64(0), "maxUnavailable": int64(1)},
+			},
+		},
+		{
+			name:          "surge",
+			rollingUpdate: kops.RollingUpdate{MaxSurge: &percent},
+			want: map[string]any{
+				"type":          "RollingUpdate",
+				"rollingUpdate": map[string]any{"maxSurge": "20%", "maxUnavailable": int64(0)},
+			},
+		},
+		{
+			name:          "unavailable",
+			rollingUpdate: kops.RollingUpdate{MaxSurge: &zero, MaxUnavailable: &two},
+			want: map[string]any{
+				"type":          "RollingUpdate",
+				"rollingUpdate": map[string]any{"maxSurge": int64(0), "maxUnavailable": int64(2)},
+			},
+		},
+		{
+			name:          "no drain and terminate",
+			rollingUpdate: kops.RollingUpdate{DrainAndTerminate: fi.PtrTo(false), MaxSurge: &percent},
+			want:          map[string]any{"type": "OnDelete"},
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			got := buildStrategy(&g.rollingUpdate)
+			if !reflect.DeepEqual(got, g.want) {
+				t.Errorf("unexpected strategy; got %v, want %v", got, g.want)
+			}
+		})
+	}
+}
+
+func TestBuildObjectsForInstanceGroup(t *testing.T) {
+	b := &MachineDeploymentBuilder{
+		ClusterName:       "minimal.example.com",
+		Name:              "nodes",
+		Namespace:         "kube-system",
+		Replicas:          3,
+		Zones:             []string{"us-test1-a", "us-test1-b"},
+		MachineType:       "e2-medium",
+		Subnet:            "us-test1",
+		Image:             "ubuntu-os-cloud/ubuntu-2404",
+		Role:              kops.InstanceGroupRoleNode,
+		KubernetesVersion: "v1.35.0",
+		InstanceGroupName: "nodes",
+		RollingUpdate:     &kops.RollingUpdate{},
+		HealthCheck: &HealthCheck{
+			UnhealthyTimeout:   5 * time.Minute,
+			NodeStartupTimeout: 10 * time.Minute,
+			MaxUnhealthy:       intstr.FromString("40%"),
+		},
+	}
+
+	objects, err := b.BuildObjects(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var kinds []string
+	for _, obj := range objects {
+		kinds = append(kinds, obj.GetKind()+"/"+obj.GetName())
+	}
+	wantKinds := []string{
+		"KopsConfigTemplate/nodes",
+		"GCPMachineTemplate/nodes",
+		"MachineDeployment/nodes-us-test1-a",
+		"MachineDeployment/nodes-us-test1-b",
+		"MachineHealthCheck/nodes",
+	}
+	if !reflect.DeepEqual(kinds, wantKinds) {
+		t.Fatalf("unexpected objects; got %v, want %v", kinds, wantKinds)
+	}
+
+	metadata, _, _ := unstructured.NestedFieldNoCopy(objects[1].Object, "spec", "template", "spec", "additionalMetadata")
+	if findMetadata(metadata.([]map[string]string), nodeidentitygce.MetadataKeyInstanceGroupName) != "nodes" {
+		t.Errorf("machines are not configured from the instance group; metadata is %v", metadata)
+	}
+
+	var replicas int64
+	for _, md := range objects[2:4] {
+		r, _, _ := unstructured.NestedFieldNoCopy(md.Object, "spec", "replicas")
+		replicas += int64(r.(int))
+		labels, _, _ := unstructured.NestedMap(md.Object, "spec", "template", "metadata", "labels")
+		if labels[kops.NodeLabelInstanceGroup] != "nodes" {
+			t.Errorf("MachineDeployment %s does not label its machines; labels are %v", md.GetName(), labels)
+		}
+		strategyType, _, _ := unstructured.NestedString(md.Object, "spec", "strategy", "type")
+		if strategyType != "RollingUpdate" {
+			t.Errorf("MachineDeployment %s has strategy %q", md.GetName(), strategyType)
+		}
+	}
+	if replicas != 3 {
+		t.Errorf("expected 3 replicas across zones, got %d", replicas)
+	}
+
+	mhc := objects[4]
+	selector, _, _ := unstructured.NestedMap(mhc.Object, "spec", "selector", "matchLabels")
+	if !reflect.DeepEqual(selector, map[string]any{kops.NodeLabelInstanceGroup: "nodes"}) {
+		t.Errorf("unexpected MachineHealthCheck selector %v", selector)
+	}
+	maxUnhealthy, _, _ := unstructured.NestedString(mhc.Object, "spec", "maxUnhealthy")
+	nodeStartupTimeout, _, _ := unstructured.NestedString(mhc.Object, "spec", "nodeStartupTimeout")
+	if maxUnhealthy != "40%" || nodeStartupTimeout != "10m0s" {
+		t.Errorf("unexpected MachineHealthCheck spec %v", mhc.Object["spec"])
+	}
+}
+
+func findMetadata(metadata []map[string]string, key string) string {
+	for _, item := range metadata {
+		if item["key"] == key {
+			return item["value"]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,562 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/kops/cmd/kops-controller/pkg/config"
+	"k8s.io/kops/pkg/acls"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/registry"
+	"k8s.io/kops/pkg/apis/kops/util"
+	kopsapi "k8s.io/kops/pkg/apis/kops/v1alpha2"
+	"k8s.io/kops/pkg/client/simple"
+	"k8s.io/kops/pkg/client/simple/vfsclientset"
+	"k8s.io/kops/pkg/commands"
+	"k8s.io/kops/pkg/kopscodecs"
+	"k8s.io/kops/pkg/statelock"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/util/pkg/vfs"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// reconcilerNamespace is the namespace holding the Cluster and InstanceGroup objects that we reconcile.
+const reconcilerNamespace = "kube-system"
+
+// instanceGroupFinalizer is set on the InstanceGroup objects written to the state store,
+// so that deleting an object removes the instance group from the state store.
+const instanceGroupFinalizer = "kops.k8s.io/state-store"
+
+const (
+	// lockRetryInterval is how long we wait for another operation to release the state store lock.
+	lockRetryInterval = time.Minute
+	// pendingRecheckInterval is how often we check the cloud again while changes remain to be applied,
+	// so that the conditions are updated after the changes are applied with the kops CLI.
+	pendingRecheckInterval = 10 * time.Minute
+)
+
+// Condition types reported on InstanceGroup objects.
+const (
+	// conditionSynced reports whether the instance group has been validated and written to the state store.
+	conditionSynced = "Synced"
+	// conditionApplied reports whether the cloud resources match the state store.
+	conditionApplied = "Applied"
+	// conditionUpToDate reports whether all the instances of the instance group run the current configuration.
+	conditionUpToDate = "UpToDate"
+)
+
+// ClusterReconciler reconciles the state store from the Cluster and InstanceGroup objects in the kube-system namespace.
+// Changes are validated and written to the state store, as with `kops replace`.
+// The control plane is not granted the permissions to manage the cloud resources, so the cloud is only checked, as with
+// `kops update cluster` without `--yes`: the changes that remain to be applied with `kops update cluster --yes`, and the
+// instances that `kops rolling-update cluster` would replace, are reported in the status of the InstanceGroup objects.
+// The cloud is only checked on AWS, the only cloud provider where the control plane is granted read access to it.
+type ClusterReconciler struct {
+	// clusterName identifies the kOps cluster
+	clusterName string
+
+	// client is the controller-runtime client
+	client client.Client
+
+	// log is a logr
+	log logr.Logger
+
+	// recorder records events on the Cluster object
+	recorder record.EventRecorder
+
+	// clientset reads and writes the state store
+	clientset simple.Clientset
+
+	// k8sClient is used to list the nodes of the cluster
+	k8sClient kubernetes.Interface
+
+	// checkCloud compares the cloud resources with the state store; if nil, the control plane cannot read the cloud resources
+	checkCloud func(ctx context.Context, cluster *kops.Cluster) (*cloudStatus, error)
+}
+
+// cloudStatus is the result of comparing the cloud resources with the state store.
+type cloudStatus struct {
+	// changes is the number of cloud resources that `kops update cluster --yes` would create, update or delete.
+	changes int
+	// outOfDate is the number of out-of-date instances of each instance group.
+	outOfDate map[string]int
+}
+
+// NewClusterReconciler is the constructor for a ClusterReconciler
+func NewClusterReconciler(mgr manager.Manager, vfsContext *vfs.VFSContext, opt *config.Options) (*ClusterReconciler, error) {
+	if opt.ConfigBase == "" {
+		return nil, fmt.Errorf("must specify configBase")
+	}
+
+	// The state store holds the configuration of each cluster in a directory named after the cluster.
+	stateStore, found := strings.CutSuffix(strings.TrimSuffix(opt.ConfigBase, "/"), "/"+opt.ClusterName)
+	if !found {
+		return nil, fmt.Errorf("configBase %q is not a cluster directory in a state store", opt.ConfigBase)
+	}
+	basePath, err := vfsContext.BuildVfsPath(stateStore)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse state store %q: %w", stateStore, err)
+	}
+
+	k8sClient, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return nil, fmt.Errorf("building kubernetes client: %w", err)
+	}
+
+	r := &ClusterReconciler{
+		clusterName: opt.ClusterName,
+		client:      mgr.GetClient(),
+		log:         ctrl.Log.WithName("controllers").WithName("Cluster"),
+		recorder:    mgr.GetEventRecorderFor("kops-controller"),
+		clientset:   vfsclientset.NewVFSClientset(vfsContext, basePath),
+		k8sClient:   k8sClient,
+	}
+	// The control plane is only granted read access to the cloud resources on AWS.
+	if opt.Cloud == string(kops.CloudProviderAWS) {
+		r.checkCloud = r.dryRun
+	}
+	return r, nil
+}
+
+// +kubebuilder:rbac:groups=kops.k8s.io,resources=clusters,verbs=get;list;watch
+// +kubebuilder:rbac:groups=kops.k8s.io,resources=instancegroups,verbs=get;list;watch;update
+// +kubebuilder:rbac:groups=kops.k8s.io,resources=instancegroups/status,verbs=get;update;patch
+
+// Reconcile is the main reconciler function that observes changes to the Cluster and InstanceGroup objects.
+// Every change reconciles the whole cluster, because the cloud is compared for every instance group.
+func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	cluster := &kopsapi.Cluster{}
+	if err := r.client.Get(ctx, types.NamespacedName{Namespace: reconcilerNamespace, Name: r.clusterName}, cluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.log.V(2).Info("cluster object not found, nothing to reconcile", "cluster", r.clusterName)
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	igList := &kopsapi.InstanceGroupList{}
+	if err := r.client.List(ctx, igList, client.InNamespace(reconcilerNamespace)); err != nil {
+		return ctrl.Result{}, err
+	}
+	var igs []*kopsapi.InstanceGroup
+	for i := range igList.Items {
+		igs = append(igs, &igList.Items[i])
+	}
+
+	// Take the state store lock, so that we do not write the state store while the kops CLI is applying it
+	lock, err := r.lock(ctx)
+	if err != nil {
+		var locked *statelock.LockedError
+		if errors.As(err, &locked) {
+			r.log.Info("state store is locked, will retry", "holder", locked.Lock.Holder, "operation", locked.Lock.Operation)
+			return ctrl.Result{RequeueAfter: lockRetryInterval}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	defer lock.Release(ctx)
+	// The state store is only read and written while the lock is held
+	lockCtx := lock.Context()
+
+	changed, err := r.syncStateStore(lockCtx, cluster, igs)
+	if lost := lock.Err(); lost != nil {
+		return ctrl.Result{}, lost
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	var live []*kopsapi.InstanceGroup
+	for _, ig := range igs {
+		if ig.DeletionTimestamp.IsZero() && isSynced(ig) {
+			live = append(live, ig)
+		}
+	}
+
+	if r.checkCloud == nil {
+		for _, ig := range live {
+			r.setCondition(ctx, ig, conditionApplied, metav1.ConditionUnknown, "CloudNotChecked", "kops-controller cannot read the cloud resources of this cloud provider; run kops update cluster to see the changes to apply")
+			r.setCondition(ctx, ig, conditionUpToDate, metav1.ConditionUnknown, "CloudNotChecked", "kops-controller cannot read the cloud resources of this cloud provider; run kops rolling-update cluster to see the instances to replace")
+		}
+		return ctrl.Result{}, nil
+	}
+
+	storeCluster, err := r.clientset.GetCluster(lockCtx, r.clusterName)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("reading cluster from state store: %w", err)
+	}
+
+	status, err := r.checkCloud(lockCtx, storeCluster)
+	if lost := lock.Err(); lost != nil {
+		return ctrl.Result{}, lost
+	}
+	if err != nil {
+		r.recorder.Eventf(cluster, corev1.EventTypeWarning, "DryRunFailed", "Comparing the cloud resources with the state store failed: %v", err)
+		for _, ig := range live {
+			r.setCondition(ctx, ig, conditionApplied, metav1.ConditionUnknown, "DryRunFailed", err.Error())
+		}
+		return ctrl.Result{}, err
+	}
+
+	pending := status.changes != 0
+	if pending && changed {
+		r.recorder.Eventf(cluster, corev1.EventTypeNormal, "ApplyRequired", "%d cloud resources differ from the state store; run kops update cluster --yes to apply the changes", status.changes)
+	}
+	for _, ig := range live {
+		if status.changes == 0 {
+			r.setCondition(ctx, ig, conditionApplied, metav1.ConditionTrue, "Applied", "The cloud resources match the state store")
+		} else {
+			r.setCondition(ctx, ig, conditionApplied, metav1.ConditionFalse, "ApplyRequired", fmt.Sprintf("%d cloud resources differ from the state store; run kops update cluster --yes to apply the changes", status.changes))
+		}
+
+		if outOfDate := status.outOfDate[ig.Name]; outOfDate == 0 {
+			r.setCondition(ctx, ig, conditionUpToDate, metav1.ConditionTrue, "UpToDate", "All instances run the current configuration")
+		} else {
+			pending = true
+			r.setCondition(ctx, ig, conditionUpToDate, metav1.ConditionFalse, "RollingUpdateRequired", fmt.Sprintf("%d instances are out of date; run kops rolling-update cluster to replace them", outOfDate))
+		}
+	}
+
+	if pending {
+		return ctrl.Result{RequeueAfter: pendingRecheckInterval}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// lock takes the state store lock of the cluster, which the kops CLI takes for long operations such as `kops update cluster --yes`.
+func (r *ClusterReconciler) lock(ctx context.Context) (*statelock.Held, error) {
+	storeCluster, err := r.clientset.GetCluster(ctx, r.clusterName)
+	if err != nil {
+		return nil, fmt.Errorf("reading cluster from state store: %w", err)
+	}
+	configBase, err := registry.ConfigBase(r.clientset.VFSContext(), storeCluster)
+	if err != nil {
+		return nil, err
+	}
+	lockPath := configBase.Join(registry.PathLock)
+	acl, err := acls.GetACL(ctx, lockPath, storeCluster)
+	if err != nil {
+		return nil, err
+	}
+	return statelock.Acquire(ctx, lockPath, acl, "kops-controller reconcile", statelock.DefaultTTL)
+}
+
+// syncStateStore validates the Cluster and InstanceGroup objects and writes those that changed to the state store.
+// Objects that fail validation are reported in their status, and the state store keeps their previous version.
+// Deleted InstanceGroup objects are removed from the state store; instance groups that were never written
+// from an InstanceGroup object are left alone.
+func (r *ClusterReconciler) syncStateStore(ctx context.Context, cluster *kopsapi.Cluster, igs []*kopsapi.InstanceGroup) (bool, error) {
+	changed := false
+
+	storeCluster, err := r.clientset.GetCluster(ctx, r.clusterName)
+	if err != nil {
+		return false, fmt.Errorf("reading cluster from state store: %w", err)
+	}
+	storeIGs, err := commands.ReadAllInstanceGroups(ctx, r.clientset, storeCluster)
+	if err != nil {
+		return false, fmt.Errorf("reading instance groups from state store: %w", err)
+	}
+
+	// Apply the defaults that are applied when the cluster is read from the state store
+	cluster = cluster.DeepCopy()
+	kopscodecs.Scheme.Default(cluster)
+
+	desiredCluster := storeCluster.DeepCopy()
+	if err := kopscodecs.Scheme.Convert(&cluster.Spec, &desiredCluster.Spec, nil); err != nil {
+		return false, fmt.Errorf("converting cluster object: %w", err)
+	}
+	cloud, err := cloudup.BuildCloud(desiredCluster)
+	if err != nil {
+		return false, err
+	}
+	// Compare with the assignments made when the cluster is written, so that they are not seen as changes.
+	if err := cloudup.PerformAssignments(desiredCluster, r.clientset.VFSContext(), cloud); err != nil {
+		return false, fmt.Errorf("error populating configuration: %w", err)
+	}
+
+	if !apiequality.Semantic.DeepEqual(desiredCluster.Spec, storeCluster.Spec) {
+		r.log.Info("writing cluster to state store", "cluster", r.clusterName)
+		if err := commands.UpdateCluster(ctx, r.clientset, desiredCluster, storeIGs); err != nil {
+			r.recorder.Eventf(cluster, corev1.EventTypeWarning, "InvalidSpec", "The cluster was not written to the state store: %v", err)
+			for _, ig := range igs {
+				if ig.DeletionTimestamp.IsZero() {
+					r.setCondition(ctx, ig, conditionSynced, metav1.ConditionFalse, "InvalidClusterSpec", err.Error())
+				}
+			}
+			return false, nil
+		}
+		storeCluster = desiredCluster
+		changed = true
+	}
+
+	storeIGsByName := make(map[string]*kops.InstanceGroup)
+	for _, ig := range storeIGs {
+		storeIGsByName[ig.Name] = ig
+	}
+
+	for _, ig := range igs {
+		if !ig.DeletionTimestamp.IsZero() {
+			deleted, err := r.deleteInstanceGroup(ctx, cluster, storeCluster, storeIGsByName[ig.Name], ig)
+			if err != nil {
+				return changed, err
+			}
+			if deleted {
+				delete(storeIGsByName, ig.Name)
+				changed = true
+			}
+			continue
+		}
+
+		if controllerutil.AddFinalizer(ig, instanceGroupFinalizer) {
+			if err := r.client.Update(ctx, ig); err != nil {
+				return changed, fmt.Errorf("adding finalizer to instance group %q: %w", ig.Name, err)
+			}
+		}
+
+		desired := &kops.InstanceGroup{}
+		if err := kopscodecs.Scheme.Convert(ig, desired, nil); err != nil {
+			return changed, fmt.Errorf("converting instance group %q: %w", ig.Name, err)
+		}
+
+		existing := storeIGsByName[ig.Name]
+		if existing != nil && apiequality.Semantic.DeepEqual(desired.Spec, existing.Spec) {
+			if !isSynced(ig) {
+				r.setCondition(ctx, ig, conditionSynced, metav1.ConditionTrue, "Synced", "The instance group matches the state store")
+			}
+			continue
+		}
+
+		r.log.Info("writing instance group to state store", "instanceGroup", ig.Name)
+		var updated *kops.InstanceGroup
+		if existing != nil {
+			updated = existing.DeepCopy()
+			updated.Spec = desired.Spec
+		} else {
+			updated = &kops.InstanceGroup{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   ig.Name,
+					Labels: ig.Labels,
+				},
+				Spec: desired.Spec,
+			}
+		}
+
+		allInstanceGroups := []*kops.InstanceGroup{updated}
+		for name, storeIG := range storeIGsByName {
+			if name != updated.Name {
+				allInstanceGroups = append(allInstanceGroups, storeIG)
+			}
+		}
+
+		if existing != nil {
+			err = commands.UpdateInstanceGroup(ctx, r.clientset, storeCluster, allInstanceGroups, updated)
+		} else {
+			err = commands.CreateInstanceGroup(ctx, r.clientset, storeCluster, allInstanceGroups, updated)
+		}
+		if err != nil {
+			r.setCondition(ctx, ig, conditionSynced, metav1.ConditionFalse, "InvalidSpec", err.Error())
+			continue
+		}
+		storeIGsByName[ig.Name] = updated
+		r.setCondition(ctx, ig, conditionSynced, metav1.ConditionTrue, "Synced", "The instance group matches the state store")
+		changed = true
+	}
+
+	return changed, nil
+}
+
+// deleteInstanceGroup removes the instance group of a deleted InstanceGroup object from the state store, and then the finalizer.
+// Control plane instance groups are left in the state store, because removing the wrong one breaks the cluster;
+// they are deleted with `kops delete instancegroup`.
+func (r *ClusterReconciler) deleteInstanceGroup(ctx context.Context, cluster *kopsapi.Cluster, storeCluster *kops.Cluster, existing *kops.InstanceGroup, ig *kopsapi.InstanceGroup) (bool, error) {
+	if !controllerutil.ContainsFinalizer(ig, instanceGroupFinalizer) {
+		return false, nil
+	}
+
+	deleted := false
+	if existing != nil {
+		if existing.IsControlPlane() {
+			r.recorder.Eventf(cluster, corev1.EventTypeWarning, "DeleteSkipped", "The control plane instance group %q was not removed from the state store; use kops delete instancegroup", ig.Name)
+		} else {
+			r.log.Info("deleting instance group from state store", "instanceGroup", ig.Name)
+			if err := r.clientset.InstanceGroupsFor(storeCluster).Delete(ctx, ig.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+				return false, fmt.Errorf("deleting instance group %q from state store: %w", ig.Name, err)
+			}
+			deleted = true
+		}
+	}
+
+	controllerutil.RemoveFinalizer(ig, instanceGroupFinalizer)
+	if err := r.client.Update(ctx, ig); err != nil && !apierrors.IsNotFound(err) {
+		return deleted, fmt.Errorf("removing finalizer from instance group %q: %w", ig.Name, err)
+	}
+	return deleted, nil
+}
+
+// dryRun compares the cloud resources with the state store, as `kops update cluster` and `kops rolling-update cluster` do without `--yes`.
+func (r *ClusterReconciler) dryRun(ctx context.Context, cluster *kops.Cluster) (*cloudStatus, error) {
+	cloud, err := cloudup.BuildCloud(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	controlPlaneRunningVersion, err := r.controlPlaneRunningVersion(ctx, cluster.Spec.KubernetesVersion)
+	if err != nil {
+		r.log.Error(err, "error checking control plane running version, assuming no kubernetes upgrade in progress")
+	}
+
+	runTasksOptions := &fi.RunTasksOptions{}
+	runTasksOptions.InitDefaults()
+
+	applyCmd := &cloudup.ApplyClusterCmd{
+		Cloud:                      cloud,
+		Clientset:                  r.clientset,
+		Cluster:                    cluster.DeepCopy(),
+		TargetName:                 cloudup.TargetDryRun,
+		DryRun:                     true,
+		DryRunOutput:               io.Discard,
+		RunTasksOptions:            runTasksOptions,
+		DeletionProcessing:         fi.DeletionProcessingModeDeleteIfNotDeferrred,
+		ControlPlaneRunningVersion: controlPlaneRunningVersion,
+	}
+	if _, err := applyCmd.Run(ctx); err != nil {
+		return nil, err
+	}
+	target := applyCmd.Target.(*fi.CloudupDryRunTarget)
+	creates, updates := target.Changes()
+	status := &cloudStatus{
+		changes:   len(creates) + len(updates) + len(target.Deletions()),
+		outOfDate: make(map[string]int),
+	}
+
+	list, err := r.clientset.InstanceGroupsFor(cluster).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var instanceGroups []*kops.InstanceGroup
+	for i := range list.Items {
+		instanceGroups = append(instanceGroups, &list.Items[i])
+	}
+
+	nodeList, err := r.k8sClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("listing nodes: %w", err)
+	}
+
+	groups, err := cloud.GetCloudGroups(cluster, instanceGroups, false, nodeList.Items)
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range groups {
+		group.AdjustNeedUpdate()
+		status.outOfDate[group.InstanceGroup.Name] = len(group.NeedUpdate)
+	}
+
+	return status, nil
+}
+
+// controlPlaneRunningVersion returns the newest kubelet version of the control plane nodes, if newer than version.
+func (r *ClusterReconciler) controlPlaneRunningVersion(ctx context.Context, version string) (string, error) {
+	parsedVersion, err := util.ParseKubernetesVersion(version)
+	if err != nil {
+		return version, fmt.Errorf("cannot parse kubernetes version %q: %w", version, err)
+	}
+	nodeList, err := r.k8sClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{
+		LabelSelector: "node-role.kubernetes.io/control-plane",
+	})
+	if err != nil {
+		return version, fmt.Errorf("cannot list control plane nodes: %w", err)
+	}
+	for _, node := range nodeList.Items {
+		if util.IsKubernetesGTE(node.Status.NodeInfo.KubeletVersion, *parsedVersion) {
+			version = node.Status.NodeInfo.KubeletVersion
+			parsedVersion, _ = util.ParseKubernetesVersion(version)
+		}
+	}
+	return strings.TrimPrefix(version, "v"), nil
+}
+
+// setCondition records a condition in the status of the InstanceGroup object.
+// Failures are logged, because the status is informational and will be updated on the next reconcile.
+func (r *ClusterReconciler) setCondition(ctx context.Context, ig *kopsapi.InstanceGroup, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	if ig.Status == nil {
+		ig.Status = &kopsapi.InstanceGroupStatus{}
+	}
+	ig.Status.ObservedGeneration = ig.Generation
+	meta.SetStatusCondition(&ig.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: ig.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+	if err := r.client.Status().Update(ctx, ig); err != nil {
+		r.log.Error(err, "error updating instance group status", "instanceGroup", ig.Name)
+	}
+}
+
+// isSynced returns true if the current generation of the InstanceGroup object has been written to the state store.
+func isSynced(ig *kopsapi.InstanceGroup) bool {
+	if ig.Status == nil {
+		return false
+	}
+	condition := meta.FindStatusCondition(ig.Status.Conditions, conditionSynced)
+	return condition != nil && condition.Status == metav1.ConditionTrue && condition.ObservedGeneration == ig.Generation
+}
+
+// mapToCluster maps changes to the Cluster and InstanceGroup objects to a reconcile of the cluster.
+func (r *ClusterReconciler) mapToCluster(ctx context.Context, obj client.Object) []reconcile.Request {
+	if obj.GetNamespace() != reconcilerNamespace {
+		return nil
+	}
+	return []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Namespace: reconcilerNamespace, Name: r.clusterName}},
+	}
+}
+
+// SetupWithManager registers the reconciler; deleting an InstanceGroup with a finalizer also changes its generation.
+func (r *ClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("reconciler").
+		Watches(&kopsapi.Cluster{}, handler.EnqueueRequestsFromMapFunc(r.mapToCluster)).
+		Watches(&kopsapi.InstanceGroup{}, handler.EnqueueRequestsFromMapFunc(r.mapToCluster)).
+		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		WithOptions(controller.Options{MaxConcurrentReconciles: 1}).
+		Complete(r)
+}
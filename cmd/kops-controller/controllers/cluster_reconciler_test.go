@@ -0,0 +1,301 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/registry"
+	kopsapi "k8s.io/kops/pkg/apis/kops/v1alpha2"
+	"k8s.io/kops/pkg/client/simple"
+	"k8s.io/kops/pkg/client/simple/vfsclientset"
+	"k8s.io/kops/pkg/kopscodecs"
+	"k8s.io/kops/pkg/testutils"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/util/pkg/vfs"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testClusterName = "reconciler.example.com"
+
+// reconcilerTest holds a reconciler using a fake client and a memfs state store
+type reconcilerTest struct {
+	t          *testing.T
+	reconciler *ClusterReconciler
+	client     client.Client
+	clientset  simple.Clientset
+	cluster    *kops.Cluster
+	status     *cloudStatus
+}
+
+func newReconcilerTest(t *testing.T, storeIGs []*kops.InstanceGroup, objects ...client.Object) *reconcilerTest {
+	t.Setenv("SKIP_REGION_CHECK", "1")
+	h := testutils.NewIntegrationTestHarness(t)
+	t.Cleanup(h.Close)
+	cloud := h.SetupMockAWS()
+
+	channelPath, err := filepath.Abs("../../../channels/")
+	if err != nil {
+		t.Fatalf("error resolving stable channel path: %v", err)
+	}
+	kops.DefaultChannelBase = "file://" + filepath.ToSlash(channelPath) + "/"
+
+	ctx := context.Background()
+	basePath, err := vfs.Context.BuildVfsPath("memfs://tests")
+	if err != nil {
+		t.Fatalf("error building state store path: %v", err)
+	}
+	clientset := vfsclientset.NewVFSClientset(vfs.Context, basePath)
+
+	// Create the cluster as `kops create cluster` does
+	cluster := testutils.BuildMinimalCluster(testClusterName)
+	if err := cloudup.PerformAssignments(cluster, vfs.Context, cloud); err != nil {
+		t.Fatalf("error populating cluster: %v", err)
+	}
+	cluster, err = clientset.CreateCluster(ctx, cluster)
+	if err != nil {
+		t.Fatalf("error creating cluster: %v", err)
+	}
+	for _, ig := range storeIGs {
+		if _, err := clientset.InstanceGroupsFor(cluster).Create(ctx, ig, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("error creating instance group: %v", err)
+		}
+	}
+
+	clusterObject := &kopsapi.Cluster{}
+	if err := kopscodecs.Scheme.Convert(cluster, clusterObject, nil); err != nil {
+		t.Fatalf("error converting cluster: %v", err)
+	}
+	clusterObject.ObjectMeta = metav1.ObjectMeta{Name: testClusterName, Namespace: reconcilerNamespace}
+
+	scheme := runtime.NewScheme()
+	if err := kopsapi.AddToScheme(scheme); err != nil {
+		t.Fatalf("error building scheme: %v", err)
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(append([]client.Object{clusterObject}, objects...)...).
+		WithStatusSubresource(&kopsapi.InstanceGroup{}).
+		Build()
+
+	rt := &reconcilerTest{
+		t:         t,
+		client:    fakeClient,
+		clientset: clientset,
+		cluster:   cluster,
+		status:    &cloudStatus{},
+	}
+	rt.reconciler = &ClusterReconciler{
+		clusterName: testClusterName,
+		client:      fakeClient,
+		log:         logr.Discard(),
+		recorder:    record.NewFakeRecorder(100),
+		clientset:   clientset,
+		checkCloud: func(ctx context.Context, cluster *kops.Cluster) (*cloudStatus, error) {
+			return rt.status, nil
+		},
+	}
+	return rt
+}
+
+func (rt *reconcilerTest) reconcile() ctrl.Result {
+	result, err := rt.reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: reconcilerNamespace, Name: testClusterName}})
+	if err != nil {
+		rt.t.Fatalf("unexpected error from reconcile: %v", err)
+	}
+	return result
+}
+
+// instanceGroupObject gets the InstanceGroup object, or returns nil if it does not exist
+func (rt *reconcilerTest) instanceGroupObject(name string) *kopsapi.InstanceGroup {
+	ig := &kopsapi.InstanceGroup{}
+	if err := rt.client.Get(context.Background(), types.NamespacedName{Namespace: reconcilerNamespace, Name: name}, ig); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		rt.t.Fatalf("error getting instance group object: %v", err)
+	}
+	return ig
+}
+
+// storeInstanceGroup reads the instance group from the state store, or returns nil if it does not exist
+func (rt *reconcilerTest) storeInstanceGroup(name string) *kops.InstanceGroup {
+	ig, err := rt.clientset.InstanceGroupsFor(rt.cluster).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		rt.t.Fatalf("error reading instance group from state store: %v", err)
+	}
+	return ig
+}
+
+func (rt *reconcilerTest) expectCondition(ig *kopsapi.InstanceGroup, conditionType string, status metav1.ConditionStatus, reason string) {
+	rt.t.Helper()
+	if ig.Status == nil {
+		rt.t.Fatalf("instance group %q has no status", ig.Name)
+	}
+	condition := meta.FindStatusCondition(ig.Status.Conditions, conditionType)
+	if condition == nil {
+		rt.t.Fatalf("instance group %q has no %s condition", ig.Name, conditionType)
+	}
+	if condition.Status != status || condition.Reason != reason {
+		rt.t.Errorf("instance group %q condition %s: got %s/%s, want %s/%s", ig.Name, conditionType, condition.Status, condition.Reason, status, reason)
+	}
+}
+
+func buildInstanceGroupObject(t *testing.T, ig kops.InstanceGroup) *kopsapi.InstanceGroup {
+	obj := &kopsapi.InstanceGroup{}
+	if err := kopscodecs.Scheme.Convert(&ig, obj, nil); err != nil {
+		t.Fatalf("error converting instance group: %v", err)
+	}
+	obj.Namespace = reconcilerNamespace
+	obj.Generation = 1
+	return obj
+}
+
+func TestReconcileCreatesInstanceGroup(t *testing.T) {
+	nodes := buildInstanceGroupObject(t, testutils.BuildMinimalNodeInstanceGroup("nodes", "subnet-us-test-1a"))
+	rt := newReconcilerTest(t, nil, nodes)
+
+	if result := rt.reconcile(); result.RequeueAfter != 0 {
+		t.Errorf("unexpected requeue after %v", result.RequeueAfter)
+	}
+
+	if rt.storeInstanceGroup("nodes") == nil {
+		t.Fatalf("instance group was not written to the state store")
+	}
+	obj := rt.instanceGroupObject("nodes")
+	if len(obj.Finalizers) != 1 || obj.Finalizers[0] != instanceGroupFinalizer {
+		t.Errorf("unexpected finalizers %v", obj.Finalizers)
+	}
+	rt.expectCondition(obj, conditionSynced, metav1.ConditionTrue, "Synced")
+	rt.expectCondition(obj, conditionApplied, metav1.ConditionTrue, "Applied")
+	rt.expectCondition(obj, conditionUpToDate, metav1.ConditionTrue, "UpToDate")
+}
+
+func TestReconcileRejectsInvalidInstanceGroup(t *testing.T) {
+	ig := testutils.BuildMinimalNodeInstanceGroup("nodes", "subnet-us-test-1a")
+	ig.Spec.MinSize = fi.PtrTo(int32(5))
+	ig.Spec.MaxSize = fi.PtrTo(int32(2))
+	rt := newReconcilerTest(t, nil, buildInstanceGroupObject(t, ig))
+
+	rt.reconcile()
+
+	if rt.storeInstanceGroup("nodes") != nil {
+		t.Errorf("invalid instance group was written to the state store")
+	}
+	rt.expectCondition(rt.instanceGroupObject("nodes"), conditionSynced, metav1.ConditionFalse, "InvalidSpec")
+}
+
+func TestReconcileReportsPendingChanges(t *testing.T) {
+	nodes := buildInstanceGroupObject(t, testutils.BuildMinimalNodeInstanceGroup("nodes", "subnet-us-test-1a"))
+	rt := newReconcilerTest(t, nil, nodes)
+	rt.status = &cloudStatus{changes: 3, outOfDate: map[string]int{"nodes": 2}}
+
+	if result := rt.reconcile(); result.RequeueAfter != pendingRecheckInterval {
+		t.Errorf("got requeue after %v, want %v", result.RequeueAfter, pendingRecheckInterval)
+	}
+
+	obj := rt.instanceGroupObject("nodes")
+	rt.expectCondition(obj, conditionApplied, metav1.ConditionFalse, "ApplyRequired")
+	rt.expectCondition(obj, conditionUpToDate, metav1.ConditionFalse, "RollingUpdateRequired")
+}
+
+func TestReconcileWithoutCloudAccess(t *testing.T) {
+	nodes := buildInstanceGroupObject(t, testutils.BuildMinimalNodeInstanceGroup("nodes", "subnet-us-test-1a"))
+	rt := newReconcilerTest(t, nil, nodes)
+	rt.reconciler.checkCloud = nil
+
+	if result := rt.reconcile(); result.RequeueAfter != 0 {
+		t.Errorf("unexpected requeue after %v", result.RequeueAfter)
+	}
+
+	if rt.storeInstanceGroup("nodes") == nil {
+		t.Fatalf("instance group was not written to the state store")
+	}
+	obj := rt.instanceGroupObject("nodes")
+	rt.expectCondition(obj, conditionSynced, metav1.ConditionTrue, "Synced")
+	rt.expectCondition(obj, conditionApplied, metav1.ConditionUnknown, "CloudNotChecked")
+	rt.expectCondition(obj, conditionUpToDate, metav1.ConditionUnknown, "CloudNotChecked")
+}
+
+func TestReconcileDeletesInstanceGroup(t *testing.T) {
+	nodes := testutils.BuildMinimalNodeInstanceGroup("nodes", "subnet-us-test-1a")
+	master := testutils.BuildMinimalMasterInstanceGroup("subnet-us-test-1a")
+
+	now := metav1.NewTime(time.Now())
+	var objects []client.Object
+	for _, ig := range []kops.InstanceGroup{nodes, master} {
+		obj := buildInstanceGroupObject(t, ig)
+		obj.Finalizers = []string{instanceGroupFinalizer}
+		obj.DeletionTimestamp = &now
+		objects = append(objects, obj)
+	}
+	rt := newReconcilerTest(t, []*kops.InstanceGroup{&nodes, &master}, objects...)
+
+	rt.reconcile()
+
+	if rt.storeInstanceGroup("nodes") != nil {
+		t.Errorf("deleted instance group was not removed from the state store")
+	}
+	if rt.storeInstanceGroup(master.Name) == nil {
+		t.Errorf("control plane instance group was removed from the state store")
+	}
+	for _, name := range []string{"nodes", master.Name} {
+		if rt.instanceGroupObject(name) != nil {
+			t.Errorf("finalizer was not removed from instance group %q", name)
+		}
+	}
+}
+
+func TestReconcileWaitsForLock(t *testing.T) {
+	nodes := buildInstanceGroupObject(t, testutils.BuildMinimalNodeInstanceGroup("nodes", "subnet-us-test-1a"))
+	rt := newReconcilerTest(t, nil, nodes)
+
+	ctx := context.Background()
+	configBase, err := registry.ConfigBase(vfs.Context, rt.cluster)
+	if err != nil {
+		t.Fatalf("error getting config base: %v", err)
+	}
+	lock := "holder: someone@workstation\noperation: update cluster\nacquiredAt: 2026-01-01T00:00:00Z\nexpiresAt: 2999-01-01T00:00:00Z\n"
+	if err := configBase.Join(registry.PathLock).WriteFile(ctx, bytes.NewReader([]byte(lock)), nil); err != nil {
+		t.Fatalf("error writing lock: %v", err)
+	}
+
+	if result := rt.reconcile(); result.RequeueAfter != lockRetryInterval {
+		t.Errorf("got requeue after %v, want %v", result.RequeueAfter, lockRetryInterval)
+	}
+	if rt.storeInstanceGroup("nodes") != nil {
+		t.Errorf("instance group was written to the state store while it was locked")
+	}
+}
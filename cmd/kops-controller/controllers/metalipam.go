@@ -18,7 +18,10 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
+	"slices"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
@@ -26,18 +29,35 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/klog/v2"
+	"k8s.io/kops/cmd/kops-controller/pkg/config"
 	kopsapi "k8s.io/kops/pkg/apis/kops/v1alpha2"
+	"k8s.io/kops/pkg/util/subnet"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
 
-// NewMetalIPAMReconciler is the constructor for a MetalIPAMReconciler
-func NewMetalIPAMReconciler(ctx context.Context, mgr manager.Manager) (*MetalIPAMReconciler, error) {
+// NewMetalIPAMReconciler is the constructor for a MetalIPAMReconciler.
+// If options is not nil, pod CIDRs are allocated to hosts that don't specify them.
+func NewMetalIPAMReconciler(ctx context.Context, mgr manager.Manager, options *config.MetalIPAMOptions) (*MetalIPAMReconciler, error) {
 	klog.Info("starting metal ipam controller")
 	r := &MetalIPAMReconciler{
-		client: mgr.GetClient(),
-		log:    ctrl.Log.WithName("controllers").WithName("metal_ipam"),
+		client:    mgr.GetClient(),
+		apiReader: mgr.GetAPIReader(),
+		log:       ctrl.Log.WithName("controllers").WithName("metal_ipam"),
+	}
+
+	if options != nil {
+		_, podCIDR, err := net.ParseCIDR(options.PodCIDR)
+		if err != nil {
+			return nil, fmt.Errorf("parsing pod CIDR %q: %w", options.PodCIDR, err)
+		}
+		ones, bits := podCIDR.Mask.Size()
+		if options.NodeCIDRMaskSize < ones || options.NodeCIDRMaskSize > bits {
+			return nil, fmt.Errorf("node CIDR mask size %d is not valid for pod CIDR %q", options.NodeCIDRMaskSize, options.PodCIDR)
+		}
+		r.podCIDR = podCIDR
+		r.nodeCIDRMask = net.CIDRMask(options.NodeCIDRMaskSize, bits)
 	}
 
 	coreClient, err := corev1client.NewForConfig(mgr.GetConfig())
@@ -49,11 +69,22 @@ func NewMetalIPAMReconciler(ctx context.Context, mgr manager.Manager) (*MetalIPA
 	return r, nil
 }
 
-// MetalIPAMReconciler observes Node objects, assigning their `PodCIDRs` from the instance's `ExternalIpv6`.
+// MetalIPAMReconciler observes Node objects, assigning their `PodCIDRs` from the Host object.
+// Hosts that don't specify their pod CIDRs are allocated one from the cluster's pod CIDR,
+// recorded in the Host status so that the allocation survives changes of leader.
 type MetalIPAMReconciler struct {
 	// client is the controller-runtime client
 	client client.Client
 
+	// apiReader reads directly from the apiserver, so we see our own recent allocations
+	apiReader client.Reader
+
+	// podCIDR is the range we allocate pod CIDRs from; if nil, we don't allocate pod CIDRs
+	podCIDR *net.IPNet
+
+	// nodeCIDRMask is the size of the pod CIDR we allocate to each host
+	nodeCIDRMask net.IPMask
+
 	// log is a logr
 	log logr.Logger
 
@@ -69,10 +100,8 @@ func (r *MetalIPAMReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	node := &corev1.Node{}
 	if err := r.client.Get(ctx, req.NamespacedName, node); err != nil {
 		if apierrors.IsNotFound(err) {
-			// we'll ignore not-found errors, since they can't be fixed by an immediate
-			// requeue (we'll need to wait for a new notification), and we can get them
-			// on deleted requests.
-			return ctrl.Result{}, nil
+			// The node has been deleted, so we can release any pod CIDRs we allocated to it.
+			return ctrl.Result{}, r.release(ctx, req.Name)
 		}
 		log.Error(err, "unable to fetch node", "node.name", node.Name)
 		return ctrl.Result{}, err
@@ -88,18 +117,31 @@ func (r *MetalIPAMReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, err
 	}
 
-	if len(node.Spec.PodCIDRs) != 0 && len(host.Spec.PodCIDRs) > 0 {
+	if len(node.Spec.PodCIDRs) != 0 {
 		log.V(2).Info("node has pod cidrs, skipping", "node.name", node.Name)
 		return ctrl.Result{}, nil
 	}
 
-	if len(host.Spec.PodCIDRs) == 0 {
+	podCIDRs := host.Spec.PodCIDRs
+	if len(podCIDRs) == 0 {
+		podCIDRs = host.Status.PodCIDRs
+	}
+	if len(podCIDRs) == 0 && r.podCIDR != nil {
+		allocated, err := r.allocate(ctx, host)
+		if err != nil {
+			log.Error(err, "unable to allocate pod cidr", "node.name", node.Name)
+			return ctrl.Result{}, err
+		}
+		podCIDRs = allocated
+	}
+
+	if len(podCIDRs) == 0 {
 		log.Info("host record has no pod cidrs, cannot assign pod cidrs to node", "node.name", node.Name)
 		return ctrl.Result{}, nil
 	}
 
-	log.Info("assigning pod cidrs to node", "node.name", node.Name, "pod.cidrs", host.Spec.PodCIDRs)
-	if err := patchNodePodCIDRs(r.coreV1Client, ctx, node, host.Spec.PodCIDRs); err != nil {
+	log.Info("assigning pod cidrs to node", "node.name", node.Name, "pod.cidrs", podCIDRs)
+	if err := patchNodePodCIDRs(r.coreV1Client, ctx, node, podCIDRs); err != nil {
 		log.Error(err, "unable to patch node", "node.name", node.Name)
 		return ctrl.Result{}, err
 	}
@@ -107,7 +149,109 @@ func (r *MetalIPAMReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	return ctrl.Result{}, nil
 }
 
+// allocate allocates a pod CIDR that is not used by any other host, and records it in the host's status.
+// The controller only runs on the leader and reconciles one node at a time, so allocations cannot race.
+func (r *MetalIPAMReconciler) allocate(ctx context.Context, host *kopsapi.Host) ([]string, error) {
+	hosts := &kopsapi.HostList{}
+	if err := r.apiReader.List(ctx, hosts, client.InNamespace(host.Namespace)); err != nil {
+		return nil, fmt.Errorf("listing hosts: %w", err)
+	}
+
+	cidrs := &subnet.CIDRMap{}
+	for _, h := range hosts.Items {
+		for _, podCIDR := range slices.Concat(h.Spec.PodCIDRs, h.Status.PodCIDRs) {
+			if err := cidrs.MarkInUse(podCIDR); err != nil {
+				klog.Warningf("ignoring pod cidr of host %s/%s: %v", h.Namespace, h.Name, err)
+			}
+		}
+	}
+
+	allocated, err := cidrs.Allocate(r.podCIDR.String(), r.nodeCIDRMask)
+	if err != nil {
+		return nil, fmt.Errorf("allocating from %v: %w", r.podCIDR, err)
+	}
+
+	host.Status.PodCIDRs = []string{allocated.String()}
+	if err := r.client.Status().Update(ctx, host); err != nil {
+		return nil, fmt.Errorf("recording pod cidr in host %s/%s: %w", host.Namespace, host.Name, err)
+	}
+	klog.Infof("allocated pod cidr %v to host %s/%s", allocated, host.Namespace, host.Name)
+	return host.Status.PodCIDRs, nil
+}
+
+// release releases the pod CIDRs allocated to the host of a deleted node.
+func (r *MetalIPAMReconciler) release(ctx context.Context, nodeName string) error {
+	host := &kopsapi.Host{}
+	id := types.NamespacedName{
+		Namespace: "kops-system",
+		Name:      nodeName,
+	}
+	if err := r.client.Get(ctx, id, host); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("fetching host %v: %w", id, err)
+	}
+	return r.releaseHost(ctx, host)
+}
+
+// releaseHost clears the pod CIDRs allocated to the host, whose node no longer exists.
+func (r *MetalIPAMReconciler) releaseHost(ctx context.Context, host *kopsapi.Host) error {
+	if len(host.Status.PodCIDRs) == 0 {
+		return nil
+	}
+
+	klog.Infof("releasing pod cidrs %v of deleted node %q", host.Status.PodCIDRs, host.Name)
+	host.Status.PodCIDRs = nil
+	if err := r.client.Status().Update(ctx, host); err != nil {
+		return fmt.Errorf("releasing pod cidrs of host %s/%s: %w", host.Namespace, host.Name, err)
+	}
+	return nil
+}
+
+// releaseOrphans releases the pod CIDRs of hosts whose node was deleted while we were not watching,
+// for example while kops-controller was restarting or changing leader.
+func (r *MetalIPAMReconciler) releaseOrphans(ctx context.Context) error {
+	hosts := &kopsapi.HostList{}
+	if err := r.apiReader.List(ctx, hosts, client.InNamespace("kops-system")); err != nil {
+		return fmt.Errorf("listing hosts: %w", err)
+	}
+
+	var errs []error
+	for i := range hosts.Items {
+		host := &hosts.Items[i]
+		if len(host.Status.PodCIDRs) == 0 {
+			continue
+		}
+
+		node := &corev1.Node{}
+		if err := r.apiReader.Get(ctx, types.NamespacedName{Name: host.Name}, node); err == nil {
+			continue
+		} else if !apierrors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("fetching node %q: %w", host.Name, err))
+			continue
+		}
+
+		// If the node registers in the meantime, the update conflicts with the allocation by Reconcile.
+		if err := r.releaseHost(ctx, host); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
 func (r *MetalIPAMReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// Runnables run only on the leader, like the controller.
+	if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		if err := r.releaseOrphans(ctx); err != nil {
+			// We retry the next time kops-controller starts or becomes the leader.
+			klog.Warningf("unable to release pod cidrs of deleted nodes: %v", err)
+		}
+		return nil
+	})); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		Named("metal_ipam").
 		For(&corev1.Node{}).
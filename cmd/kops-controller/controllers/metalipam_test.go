@@ -0,0 +1,82 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	kopsapi "k8s.io/kops/pkg/apis/kops/v1alpha2"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestMetalIPAMReleaseOrphans(t *testing.T) {
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	if err := kopsapi.AddToScheme(scheme); err != nil {
+		t.Fatalf("error building scheme: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("error building scheme: %v", err)
+	}
+
+	host := func(name string, specCIDRs, statusCIDRs []string) *kopsapi.Host {
+		h := &kopsapi.Host{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "kops-system"}}
+		h.Spec.PodCIDRs = specCIDRs
+		h.Status.PodCIDRs = statusCIDRs
+		return h
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "running"}},
+			host("running", nil, []string{"100.96.0.0/24"}),
+			host("deleted", nil, []string{"100.96.1.0/24"}),
+			host("static", []string{"100.96.2.0/24"}, nil),
+		).
+		WithStatusSubresource(&kopsapi.Host{}).
+		Build()
+
+	r := &MetalIPAMReconciler{
+		client:    fakeClient,
+		apiReader: fakeClient,
+	}
+	if err := r.releaseOrphans(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []*kopsapi.Host{
+		host("running", nil, []string{"100.96.0.0/24"}),
+		host("deleted", nil, nil),
+		host("static", []string{"100.96.2.0/24"}, nil),
+	} {
+		got := &kopsapi.Host{}
+		if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: want.Namespace, Name: want.Name}, got); err != nil {
+			t.Fatalf("error getting host %q: %v", want.Name, err)
+		}
+		if !slices.Equal(got.Spec.PodCIDRs, want.Spec.PodCIDRs) || !slices.Equal(got.Status.PodCIDRs, want.Status.PodCIDRs) {
+			t.Errorf("host %q: got pod cidrs %v/%v, want %v/%v", want.Name, got.Spec.PodCIDRs, got.Status.PodCIDRs, want.Spec.PodCIDRs, want.Status.PodCIDRs)
+		}
+	}
+}
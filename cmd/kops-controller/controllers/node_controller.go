@@ -20,6 +20,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
@@ -28,19 +30,28 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/bootstrap"
 	"k8s.io/kops/pkg/nodeidentity"
 	"k8s.io/kops/pkg/nodelabels"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
-// NewNodeReconciler is the constructor for a NodeReconciler
-func NewNodeReconciler(mgr manager.Manager, identifier nodeidentity.Identifier) (*NodeReconciler, error) {
+// revocationPollInterval is how often we check for newly revoked nodes.
+const revocationPollInterval = time.Minute
+
+// NewNodeReconciler is the constructor for a NodeReconciler.
+// If revocations is not nil, nodes whose identity has been revoked are cordoned and tainted.
+func NewNodeReconciler(mgr manager.Manager, identifier nodeidentity.Identifier, revocations *bootstrap.RevocationList) (*NodeReconciler, error) {
 	r := &NodeReconciler{
-		client:     mgr.GetClient(),
-		log:        ctrl.Log.WithName("controllers").WithName("Node"),
-		identifier: identifier,
+		client:      mgr.GetClient(),
+		log:         ctrl.Log.WithName("controllers").WithName("Node"),
+		identifier:  identifier,
+		revocations: revocations,
 	}
 
 	coreClient, err := corev1client.NewForConfig(mgr.GetConfig())
@@ -66,6 +77,14 @@ type NodeReconciler struct {
 
 	// identifier is a provider that can securely map node ProviderIDs to labels
 	identifier nodeidentity.Identifier
+
+	// revocations is the list of nodes whose identity has been revoked
+	revocations *bootstrap.RevocationList
+
+	// revokedMutex guards revoked
+	revokedMutex sync.Mutex
+	// revoked caches the revocation list by node name; it is refreshed every revocationPollInterval
+	revoked map[string]*bootstrap.RevokedNode
 }
 
 // +kubebuilder:rbac:groups=,resources=nodes,verbs=get;list;watch;patch
@@ -77,6 +96,12 @@ func (r *NodeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 	if err := r.client.Get(ctx, req.NamespacedName, node); err != nil {
 		klog.Warningf("unable to fetch node %s: %v", node.Name, err)
 		if apierrors.IsNotFound(err) {
+			// A revoked node that has been deleted can be replaced by a node with the same name.
+			if r.revocations != nil {
+				if err := r.removeStaleRevocation(ctx, req.Name, ""); err != nil {
+					return ctrl.Result{}, err
+				}
+			}
 			// we'll ignore not-found errors, since they can't be fixed by an immediate
 			// requeue (we'll need to wait for a new notification), and we can get them
 			// on deleted requests.
@@ -85,6 +110,12 @@ func (r *NodeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 		return ctrl.Result{}, err
 	}
 
+	if r.revocations != nil {
+		if err := r.quarantineIfRevoked(ctx, node); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
 	info, err := r.identifier.IdentifyNode(ctx, node)
 	if err != nil {
 		return ctrl.Result{}, fmt.Errorf("error identifying node %q: %v", node.Name, err)
@@ -124,11 +155,118 @@ func (r *NodeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 	return ctrl.Result{}, nil
 }
 
+// cachedRevocation returns the revocation of the node from the cache, or nil if it has not been revoked.
+func (r *NodeReconciler) cachedRevocation(nodeName string) *bootstrap.RevokedNode {
+	r.revokedMutex.Lock()
+	defer r.revokedMutex.Unlock()
+	return r.revoked[nodeName]
+}
+
+// removeStaleRevocation removes the revocation of a Node object that no longer exists,
+// because it was deleted (uid is empty) or replaced by a Node with the same name.
+// Revocations that were recorded before the node registered are kept until they are removed with kops.
+func (r *NodeReconciler) removeStaleRevocation(ctx context.Context, nodeName string, uid types.UID) error {
+	revoked := r.cachedRevocation(nodeName)
+	if revoked == nil || revoked.UID == "" || revoked.UID == uid {
+		return nil
+	}
+	klog.Infof("removing revocation of node %q, as Node %s no longer exists", nodeName, revoked.UID)
+	if err := r.revocations.Remove(ctx, nodeName); err != nil {
+		return fmt.Errorf("error removing revocation of node %q: %w", nodeName, err)
+	}
+	r.revokedMutex.Lock()
+	delete(r.revoked, nodeName)
+	r.revokedMutex.Unlock()
+	return nil
+}
+
+// quarantineIfRevoked cordons and taints the node if its identity has been revoked.
+func (r *NodeReconciler) quarantineIfRevoked(ctx context.Context, node *corev1.Node) error {
+	revoked := r.cachedRevocation(node.Name)
+	if revoked == nil {
+		return nil
+	}
+	if !revoked.AppliesTo(node.UID) {
+		return r.removeStaleRevocation(ctx, node.Name, node.UID)
+	}
+
+	tainted := false
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == bootstrap.QuarantineTaintKey {
+			tainted = true
+		}
+	}
+	if node.Spec.Unschedulable && tainted {
+		return nil
+	}
+
+	patch := client.MergeFromWithOptions(node.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	node.Spec.Unschedulable = true
+	if !tainted {
+		node.Spec.Taints = append(node.Spec.Taints, corev1.Taint{
+			Key:       bootstrap.QuarantineTaintKey,
+			Value:     "revoked",
+			Effect:    corev1.TaintEffectNoExecute,
+			TimeAdded: &metav1.Time{Time: time.Now()},
+		})
+	}
+	klog.Infof("quarantining node %q, which was revoked at %v: %s", node.Name, revoked.RevokedAt, revoked.Reason)
+	if err := r.client.Patch(ctx, node, patch); err != nil {
+		return fmt.Errorf("error quarantining node %q: %w", node.Name, err)
+	}
+	return nil
+}
+
+// pollRevocations periodically refreshes the cached revocation list, and queues the revoked nodes,
+// so that they are quarantined soon after they are revoked.
+func (r *NodeReconciler) pollRevocations(ctx context.Context, events chan<- event.GenericEvent) error {
+	ticker := time.NewTicker(revocationPollInterval)
+	defer ticker.Stop()
+	for {
+		list, err := r.revocations.List(ctx)
+		if err != nil {
+			klog.Warningf("error listing revoked nodes: %v", err)
+		} else {
+			revoked := make(map[string]*bootstrap.RevokedNode)
+			for _, n := range list {
+				revoked[n.Name] = n
+			}
+			r.revokedMutex.Lock()
+			r.revoked = revoked
+			r.revokedMutex.Unlock()
+		}
+		for _, n := range list {
+			node := &corev1.Node{}
+			node.Name = n.Name
+			select {
+			case events <- event.GenericEvent{Object: node}:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
 func (r *NodeReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	builder := ctrl.NewControllerManagedBy(mgr).
 		Named("node").
-		For(&corev1.Node{}).
-		Complete(r)
+		For(&corev1.Node{})
+	if r.revocations != nil {
+		events := make(chan event.GenericEvent)
+		if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+			return r.pollRevocations(ctx, events)
+		})); err != nil {
+			return err
+		}
+		builder = builder.WatchesRawSource(source.Channel(events, &handler.EnqueueRequestForObject{}))
+	}
+	return builder.Complete(r)
 }
 
 type nodePatch struct {
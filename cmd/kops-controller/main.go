@@ -18,6 +18,8 @@ package main
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
 	"flag"
 	"fmt"
 	"os"
@@ -33,12 +35,16 @@ import (
 	controlplaneapi "k8s.io/kops/clusterapi/controlplane/kops/api/v1beta1"
 	"k8s.io/kops/cmd/kops-controller/controllers"
 	"k8s.io/kops/cmd/kops-controller/pkg/config"
+	"k8s.io/kops/cmd/kops-controller/pkg/controllerclientset"
 	"k8s.io/kops/cmd/kops-controller/pkg/server"
 	"k8s.io/kops/pkg/apis/kops/v1alpha2"
 	"k8s.io/kops/pkg/bootstrap"
 	"k8s.io/kops/pkg/bootstrap/pkibootstrap"
+	"k8s.io/kops/pkg/bootstrap/tpmbootstrap"
 	"k8s.io/kops/pkg/client/simple"
 	"k8s.io/kops/pkg/controllers/clusterapi"
+	// Register the providers of CA keys held in PKCS#11 modules and cloud KMS.
+	_ "k8s.io/kops/pkg/keysigner"
 	"k8s.io/kops/pkg/nodeidentity"
 	nodeidentityaws "k8s.io/kops/pkg/nodeidentity/aws"
 	nodeidentityazure "k8s.io/kops/pkg/nodeidentity/azure"
@@ -46,9 +52,11 @@ import (
 	nodeidentitydo "k8s.io/kops/pkg/nodeidentity/do"
 	nodeidentitygce "k8s.io/kops/pkg/nodeidentity/gce"
 	nodeidentityhetzner "k8s.io/kops/pkg/nodeidentity/hetzner"
+	"k8s.io/kops/pkg/nodeidentity/identitycache"
 	nodeidentitymetal "k8s.io/kops/pkg/nodeidentity/metal"
 	nodeidentityos "k8s.io/kops/pkg/nodeidentity/openstack"
 	nodeidentityscw "k8s.io/kops/pkg/nodeidentity/scaleway"
+	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/azure"
 	"k8s.io/kops/upup/pkg/fi/cloudup/do"
@@ -56,6 +64,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi/cloudup/hetzner"
 	"k8s.io/kops/upup/pkg/fi/cloudup/openstack"
 	"k8s.io/kops/upup/pkg/fi/cloudup/scaleway"
+	"k8s.io/kops/upup/pkg/fi/secrets"
 	"k8s.io/kops/util/pkg/vfs"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -78,10 +87,6 @@ func main() {
 
 	klog.InitFlags(nil)
 
-	// Disable metrics by default (avoid port conflicts, also risky because we are host network)
-	metricsAddress := ":0"
-	// flag.StringVar(&metricsAddr, "metrics-addr", metricsAddress, "The address the metric endpoint binds to.")
-
 	configPath := "/etc/kubernetes/kops-controller/config.yaml"
 	flag.StringVar(&configPath, "conf", configPath, "Location of yaml configuration file")
 
@@ -107,6 +112,12 @@ func main() {
 
 	ctrl.SetLogger(klogr.New())
 
+	// Disable metrics by default (avoid port conflicts, also risky because we are host network)
+	metricsAddress := ":0"
+	if opt.MetricsAddress != "" {
+		metricsAddress = opt.MetricsAddress
+	}
+
 	scheme, err := buildScheme(&opt)
 	if err != nil {
 		setupLog.Error(err, "error building scheme")
@@ -141,7 +152,19 @@ func main() {
 
 	if opt.Server != nil {
 		var verifiers []bootstrap.Verifier
-		var err error
+
+		configBase, err := vfsContext.BuildVfsPath(opt.ConfigBase)
+		if err != nil {
+			setupLog.Error(err, "cannot parse ConfigBase")
+			os.Exit(1)
+		}
+		// The cluster configures the encryption of the secrets in the state store.
+		cluster, err := controllerclientset.LoadCluster(ctx, configBase)
+		if err != nil {
+			setupLog.Error(err, "unable to load cluster")
+			os.Exit(1)
+		}
+
 		if opt.Server.Provider.AWS != nil {
 			verifier, err := awsup.NewAWSVerifier(ctx, opt.Server.Provider.AWS)
 			if err != nil {
@@ -199,8 +222,17 @@ func main() {
 			verifiers = append(verifiers, verifier)
 		}
 
+		// Join tokens and the key for TPM credentials are kept in the secret store.
+		secretStorePath, err := vfsContext.BuildVfsPath(opt.SecretStore)
+		if err != nil {
+			setupLog.Error(err, "cannot parse SecretStore")
+			os.Exit(1)
+		}
+		secretStore := secrets.NewVFSSecretStore(cluster, secretStorePath)
+
 		if opt.Server.PKI != nil {
-			verifier, err := pkibootstrap.NewVerifier(opt.Server.PKI, mgr.GetClient())
+			// The use of each join token is recorded in the state store, so that it is shared by all kops-controller instances.
+			verifier, err := pkibootstrap.NewVerifier(opt.Server.PKI, mgr.GetClient(), secretStore, configBase.Join("join-tokens", "claimed"))
 			if err != nil {
 				setupLog.Error(err, "unable to create verifier")
 				os.Exit(1)
@@ -208,6 +240,21 @@ func main() {
 			verifiers = append(verifiers, verifier)
 		}
 
+		var tpmVerifier *tpmbootstrap.Verifier
+		if opt.Server.TPM != nil {
+			credentialKey, err := tpmCredentialKey(secretStore)
+			if err != nil {
+				setupLog.Error(err, "unable to create verifier")
+				os.Exit(1)
+			}
+			tpmVerifier, err = tpmbootstrap.NewVerifier(opt.Server.TPM, mgr.GetClient(), credentialKey)
+			if err != nil {
+				setupLog.Error(err, "unable to create verifier")
+				os.Exit(1)
+			}
+			verifiers = append(verifiers, tpmVerifier)
+		}
+
 		if len(verifiers) == 0 {
 			klog.Fatalf("server verifiers not provided")
 		}
@@ -223,11 +270,14 @@ func main() {
 
 		verifier := bootstrap.NewChainVerifier(verifiers...)
 
-		srv, err := server.NewServer(vfsContext, &opt, verifier, uncachedClient)
+		srv, err := server.NewServer(vfsContext, &opt, cluster, verifier, uncachedClient)
 		if err != nil {
 			setupLog.Error(err, "unable to create server")
 			os.Exit(1)
 		}
+		if tpmVerifier != nil {
+			srv.HandleTPMCredentials(tpmVerifier)
+		}
 		clientset = srv.GetClientset()
 		mgr.Add(srv)
 	}
@@ -250,6 +300,11 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err := addClusterReconciler(mgr, vfsContext, &opt); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ClusterReconciler")
+		os.Exit(1)
+	}
+
 	// +kubebuilder:scaffold:builder
 
 	if opt.CAPI.IsEnabled() {
@@ -305,7 +360,7 @@ func addNodeController(ctx context.Context, mgr manager.Manager, vfsContext *vfs
 	var err error
 	switch opt.Cloud {
 	case "aws":
-		identifier, err = nodeidentityaws.New(ctx, opt.CacheNodeidentityInfo)
+		identifier, err = nodeidentityaws.New(ctx)
 		if err != nil {
 			return fmt.Errorf("error building node identifier: %w", err)
 		}
@@ -360,7 +415,33 @@ func addNodeController(ctx context.Context, mgr manager.Manager, vfsContext *vfs
 	}
 
 	if identifier != nil {
-		nodeController, err := controllers.NewNodeReconciler(mgr, identifier)
+		identifier = identitycache.New(identifier, func(ctx context.Context) ([]*corev1.Node, error) {
+			var nodeList corev1.NodeList
+			if err := mgr.GetClient().List(ctx, &nodeList); err != nil {
+				return nil, err
+			}
+			nodes := make([]*corev1.Node, 0, len(nodeList.Items))
+			for i := range nodeList.Items {
+				nodes = append(nodes, &nodeList.Items[i])
+			}
+			return nodes, nil
+		}, identitycache.Options{
+			TTL:       opt.NodeIdentity.CacheTTL.Duration,
+			QPS:       opt.NodeIdentity.QPS,
+			Burst:     opt.NodeIdentity.Burst,
+			BatchSize: opt.NodeIdentity.BatchSize,
+		})
+
+		var revocations *bootstrap.RevocationList
+		if opt.NodeIdentityRevocation && opt.ConfigBase != "" {
+			configBase, err := vfsContext.BuildVfsPath(opt.ConfigBase)
+			if err != nil {
+				return fmt.Errorf("cannot parse ConfigBase %q: %w", opt.ConfigBase, err)
+			}
+			revocations = bootstrap.NewRevocationList(configBase)
+		}
+
+		nodeController, err := controllers.NewNodeReconciler(mgr, identifier, revocations)
 		if err != nil {
 			return err
 		}
@@ -409,6 +490,23 @@ func addGossipController(mgr manager.Manager, opt *config.Options) error {
 	return nil
 }
 
+func addClusterReconciler(mgr manager.Manager, vfsContext *vfs.VFSContext, opt *config.Options) error {
+	if opt.Reconciler == nil {
+		return nil
+	}
+
+	controller, err := controllers.NewClusterReconciler(mgr, vfsContext, opt)
+	if err != nil {
+		return err
+	}
+
+	if err := controller.SetupWithManager(mgr); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // Reconciler is the interface for a standard Reconciler.
 type Reconciler interface {
 	SetupWithManager(mgr manager.Manager) error
@@ -431,7 +529,7 @@ func setupCloudIPAM(ctx context.Context, mgr manager.Manager, opt *config.Option
 		}
 		controller = ipamController
 	case "metal":
-		ipamController, err := controllers.NewMetalIPAMReconciler(ctx, mgr)
+		ipamController, err := controllers.NewMetalIPAMReconciler(ctx, mgr, opt.MetalIPAM)
 		if err != nil {
 			return fmt.Errorf("creating metal IPAM controller: %w", err)
 		}
@@ -446,3 +544,18 @@ func setupCloudIPAM(ctx context.Context, mgr manager.Manager, opt *config.Option
 
 	return nil
 }
+
+// tpmCredentialKey derives the key for TPM credentials from a random secret created by kops for this purpose,
+// so that every kops-controller instance can verify the credentials issued by the others.
+func tpmCredentialKey(secretStore fi.SecretStoreReader) ([]byte, error) {
+	secret, err := secretStore.FindSecret(fi.SecretNameTPMCredentials)
+	if err != nil {
+		return nil, fmt.Errorf("reading secret %q: %w", fi.SecretNameTPMCredentials, err)
+	}
+	if secret == nil || len(secret.Data) == 0 {
+		return nil, fmt.Errorf("secret %q not found; run \"kops update cluster\" to create it", fi.SecretNameTPMCredentials)
+	}
+	mac := hmac.New(sha256.New, secret.Data)
+	mac.Write([]byte("kops-controller tpm credentials"))
+	return mac.Sum(nil), nil
+}
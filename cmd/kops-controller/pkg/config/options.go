@@ -17,7 +17,11 @@ limitations under the License.
 package config
 
 import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/kops/pkg/bootstrap/pkibootstrap"
+	"k8s.io/kops/pkg/bootstrap/tpmbootstrap"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/azure"
 	"k8s.io/kops/upup/pkg/fi/cloudup/do"
@@ -35,19 +39,73 @@ type Options struct {
 	Server                *ServerOptions `json:"server,omitempty"`
 	CacheNodeidentityInfo bool           `json:"cacheNodeidentityInfo,omitempty"`
 
+	// MetricsAddress is the address the metrics endpoint binds to; metrics are disabled when empty.
+	MetricsAddress string `json:"metricsAddress,omitempty"`
+
 	// EnableCloudIPAM enables the cloud IPAM controller.
 	EnableCloudIPAM bool `json:"enableCloudIPAM,omitempty"`
 
+	// MetalIPAM configures the allocation of pod CIDRs to bare-metal nodes by the IPAM controller.
+	MetalIPAM *MetalIPAMOptions `json:"metalIPAM,omitempty"`
+
 	// Discovery configures options relating to discovery, particularly for gossip mode.
 	Discovery *DiscoveryOptions `json:"discovery,omitempty"`
 
 	// CAPI configures Cluster API (CAPI) support.
 	CAPI *CAPIOptions `json:"capi,omitempty"`
+
+	// NodeIdentity configures the caching and rate limiting of the cloud lookups that identify nodes.
+	NodeIdentity *NodeIdentityOptions `json:"nodeIdentity,omitempty"`
+
+	// Reconciler configures the reconciliation of the cluster from the Cluster and InstanceGroup objects in the cluster.
+	Reconciler *ReconcilerOptions `json:"reconciler,omitempty"`
+
+	// NodeIdentityRevocation rejects and quarantines the nodes in the revocation list in the state store.
+	NodeIdentityRevocation bool `json:"nodeIdentityRevocation,omitempty"`
 }
 
 func (o *Options) PopulateDefaults() {
+	if o.NodeIdentity == nil {
+		o.NodeIdentity = &NodeIdentityOptions{}
+	}
+	if o.NodeIdentity.CacheTTL == nil {
+		ttl := 5 * time.Minute
+		if o.CacheNodeidentityInfo {
+			ttl = 60 * time.Minute
+		}
+		o.NodeIdentity.CacheTTL = &metav1.Duration{Duration: ttl}
+	}
+	if o.NodeIdentity.QPS == 0 {
+		o.NodeIdentity.QPS = 5
+	}
+	if o.NodeIdentity.Burst == 0 {
+		o.NodeIdentity.Burst = 10
+	}
+	if o.NodeIdentity.BatchSize == 0 {
+		o.NodeIdentity.BatchSize = 50
+	}
 }
 
+type NodeIdentityOptions struct {
+	// CacheTTL is how long the identity of a node is cached.
+	CacheTTL *metav1.Duration `json:"cacheTTL,omitempty"`
+	// QPS is the sustained rate of cloud lookups.
+	QPS float64 `json:"qps,omitempty"`
+	// Burst is the number of cloud lookups allowed above the sustained rate.
+	Burst int `json:"burst,omitempty"`
+	// BatchSize is the maximum number of nodes identified by one cloud lookup, on clouds that support it.
+	BatchSize int `json:"batchSize,omitempty"`
+}
+
+type MetalIPAMOptions struct {
+	// PodCIDR is the range from which pod CIDRs are allocated to nodes.
+	PodCIDR string `json:"podCIDR,omitempty"`
+	// NodeCIDRMaskSize is the prefix length of the pod CIDR allocated to each node.
+	NodeCIDRMaskSize int `json:"nodeCIDRMaskSize,omitempty"`
+}
+
+type ReconcilerOptions struct{}
+
 type CAPIOptions struct {
 	// Enabled specifies whether CAPI support is enabled.
 	Enabled *bool `json:"enabled,omitempty"`
@@ -71,6 +129,9 @@ type ServerOptions struct {
 	// PKI configures private/public key node authentication.
 	PKI *pkibootstrap.Options `json:"pki,omitempty"`
 
+	// TPM configures TPM 2.0 attestation node authentication.
+	TPM *tpmbootstrap.Options `json:"tpm,omitempty"`
+
 	// ServerKeyPath is the path to our TLS serving private key.
 	ServerKeyPath string `json:"serverKeyPath,omitempty"`
 	// ServerCertificatePath is the path to our TLS serving certificate.
@@ -82,6 +143,25 @@ type ServerOptions struct {
 	SigningCAs []string `json:"signingCAs"`
 	// CertNames is the list of active certificate names.
 	CertNames []string `json:"certNames"`
+
+	// AuditLog configures the audit log of bootstrap requests.
+	AuditLog *AuditLogOptions `json:"auditLog,omitempty"`
+
+	// NodeConfigMaxUnavailable is the maximum number of nodes that may apply a change to their configuration
+	// with the node configuration agent at the same time. It is only set if the agent is enabled.
+	NodeConfigMaxUnavailable int `json:"nodeConfigMaxUnavailable,omitempty"`
+}
+
+// NodeConfigLeasePrefix is the prefix of the names of the Leases in kube-system that limit how many nodes
+// apply a change to their configuration at the same time; there is one Lease for each of NodeConfigMaxUnavailable slots.
+const NodeConfigLeasePrefix = "kops-node-config-"
+
+// AuditLogOptions configures where bootstrap audit records are written.
+type AuditLogOptions struct {
+	// Path is the local file that audit records are appended to, as JSON lines.
+	Path string `json:"path,omitempty"`
+	// Store is a VFS location that each audit record is also written to, if set.
+	Store string `json:"store,omitempty"`
 }
 
 type ServerProviderOptions struct {
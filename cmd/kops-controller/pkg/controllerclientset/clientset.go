@@ -58,7 +58,12 @@ func (c *client) GetCluster(ctx context.Context, name string) (*kops.Cluster, er
 		return nil, fmt.Errorf("clientset bound to cluster %q, got cluster %q", c.clusterName, name)
 	}
 
-	p := c.clusterBasePath.Join("config")
+	return LoadCluster(ctx, c.clusterBasePath)
+}
+
+// LoadCluster reads the cluster from the base of the configuration storage.
+func LoadCluster(ctx context.Context, clusterBasePath vfs.Path) (*kops.Cluster, error) {
+	p := clusterBasePath.Join("config")
 	b, err := p.ReadFile(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("reading file %v: %w", p, err)
@@ -124,6 +129,36 @@ func (c *client) AddonsFor(cluster *kops.Cluster) simple.AddonsClient {
 	return newAddonsClient(basePath, cluster)
 }
 
+// HistoryFor returns the client for the revisions of the specs of a particular Cluster.
+// The server-side client does not read the history, so every method returns an error.
+func (c *client) HistoryFor(cluster *kops.Cluster) simple.ClusterHistoryClient {
+	return unsupportedHistory{}
+}
+
+// unsupportedHistory implements simple.ClusterHistoryClient for the server-side client
+type unsupportedHistory struct{}
+
+var _ simple.ClusterHistoryClient = unsupportedHistory{}
+
+// errHistoryUnsupported is returned by every method of unsupportedHistory
+var errHistoryUnsupported = fmt.Errorf("cluster history is not supported in server-side client")
+
+func (unsupportedHistory) List(ctx context.Context) ([]*simple.ClusterRevision, error) {
+	return nil, errHistoryUnsupported
+}
+
+func (unsupportedHistory) Get(ctx context.Context, revision int) (*simple.ClusterRevision, error) {
+	return nil, errHistoryUnsupported
+}
+
+func (unsupportedHistory) Rollback(ctx context.Context, revision int) (*simple.ClusterRevision, error) {
+	return nil, errHistoryUnsupported
+}
+
+func (unsupportedHistory) InterruptedRollback(ctx context.Context) (int, error) {
+	return 0, errHistoryUnsupported
+}
+
 // SecretStore builds the secret store for the specified cluster
 func (c *client) SecretStore(cluster *kops.Cluster) (fi.SecretStore, error) {
 	clusterName := cluster.Name
@@ -104,6 +104,10 @@ func (c *instanceGroups) Update(ctx context.Context, g *kopsapi.InstanceGroup, o
 	return nil, fmt.Errorf("InstanceGroups::Update not supported for server-side client")
 }
 
+func (c *instanceGroups) UpdateStatus(ctx context.Context, g *kopsapi.InstanceGroup, opts metav1.UpdateOptions) (*kopsapi.InstanceGroup, error) {
+	return nil, fmt.Errorf("InstanceGroups::UpdateStatus not supported for server-side client")
+}
+
 func (c *instanceGroups) Delete(ctx context.Context, name string, options metav1.DeleteOptions) error {
 	return fmt.Errorf("InstanceGroups::Delete not supported for server-side client")
 }
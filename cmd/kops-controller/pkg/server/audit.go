@@ -0,0 +1,155 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/cmd/kops-controller/pkg/config"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// auditRecord is a single entry in the bootstrap audit log.
+type auditRecord struct {
+	Time       time.Time `json:"time"`
+	Request    string    `json:"request"`
+	RemoteAddr string    `json:"remoteAddr"`
+	Verifier   string    `json:"verifier"`
+	Result     string    `json:"result"`
+
+	NodeName          string `json:"nodeName,omitempty"`
+	InstanceGroupName string `json:"instanceGroupName,omitempty"`
+	NodeConfig        bool   `json:"nodeConfig,omitempty"`
+
+	Certificates []auditCertificate `json:"certificates,omitempty"`
+
+	// verified is set once the identity of the node has been verified.
+	// Only verified records are written to the state store, so that unauthenticated requests cannot create objects there.
+	verified bool
+}
+
+// auditCertificate identifies a certificate issued in a bootstrap or renewal request.
+type auditCertificate struct {
+	Name         string    `json:"name"`
+	Signer       string    `json:"signer"`
+	SerialNumber string    `json:"serialNumber"`
+	Subject      string    `json:"subject"`
+	NotAfter     time.Time `json:"notAfter"`
+}
+
+func newAuditCertificate(name string, signer string, cert *pki.Certificate) auditCertificate {
+	return auditCertificate{
+		Name:         name,
+		Signer:       signer,
+		SerialNumber: cert.Certificate.SerialNumber.String(),
+		Subject:      cert.Subject.String(),
+		NotAfter:     cert.Certificate.NotAfter,
+	}
+}
+
+// maxAuditLogSize is the size at which the local audit log is rotated.
+// A single previous file is kept, with a ".1" suffix.
+const maxAuditLogSize = 10 * 1024 * 1024
+
+// auditLog appends bootstrap audit records to a local file as JSON lines,
+// and optionally writes the records of verified requests to a VFS location as well.
+type auditLog struct {
+	path  string
+	store vfs.Path
+
+	// mutex serializes writes to the local file
+	mutex sync.Mutex
+}
+
+func newAuditLog(vfsContext *vfs.VFSContext, opt *config.AuditLogOptions) (*auditLog, error) {
+	a := &auditLog{}
+	if opt == nil {
+		return a, nil
+	}
+
+	a.path = opt.Path
+	if opt.Store != "" {
+		store, err := vfsContext.BuildVfsPath(opt.Store)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse audit log store %q: %w", opt.Store, err)
+		}
+		a.store = store
+	}
+	return a, nil
+}
+
+// record writes the record to the audit log. Failures are logged and counted, but do not fail the request.
+func (a *auditLog) record(ctx context.Context, rec *auditRecord) {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		klog.Errorf("error encoding audit record: %v", err)
+		return
+	}
+	data = append(data, '\n')
+
+	if a.path != "" {
+		if err := a.appendLocal(data); err != nil {
+			klog.Warningf("error writing audit record to %q: %v", a.path, err)
+			auditErrors.WithLabelValues("file").Inc()
+		}
+	}
+
+	if a.store != nil && rec.verified {
+		// Objects in the state store cannot be appended to, so each record is written as its own object,
+		// named so that listing a day's records returns them in order.
+		who := rec.NodeName
+		if who == "" {
+			who = rec.RemoteAddr
+		}
+		t := rec.Time.UTC()
+		p := a.store.Join(t.Format("2006-01-02"), fmt.Sprintf("%s-%s.json", t.Format("150405.000000000"), who))
+		if err := p.WriteFile(ctx, bytes.NewReader(data), nil); err != nil {
+			klog.Warningf("error writing audit record to %q: %v", p, err)
+			auditErrors.WithLabelValues("store").Inc()
+		}
+	}
+}
+
+func (a *auditLog) appendLocal(data []byte) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if stat, err := os.Stat(a.path); err == nil && stat.Size()+int64(len(data)) > maxAuditLogSize {
+		if err := os.Rename(a.path, a.path+".1"); err != nil {
+			return fmt.Errorf("rotating audit log: %w", err)
+		}
+	}
+
+	// We reopen the file for every record; bootstrap requests are infrequent, and this lets the log be rotated.
+	f, err := os.OpenFile(a.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
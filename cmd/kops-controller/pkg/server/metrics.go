@@ -0,0 +1,212 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/bootstrap/pkibootstrap"
+	"k8s.io/kops/pkg/bootstrap/tpmbootstrap"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azure"
+	"k8s.io/kops/upup/pkg/fi/cloudup/do"
+	gcetpm "k8s.io/kops/upup/pkg/fi/cloudup/gce/tpm"
+	"k8s.io/kops/upup/pkg/fi/cloudup/hetzner"
+	"k8s.io/kops/upup/pkg/fi/cloudup/openstack"
+	"k8s.io/kops/upup/pkg/fi/cloudup/scaleway"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Types of request, used in the audit log.
+const (
+	requestBootstrap = "bootstrap"
+	requestRenew     = "renew"
+)
+
+// verifierClientCertificate is the verifier type reported for requests authenticated with a node's client certificate.
+const verifierClientCertificate = "client-certificate"
+
+// verifierTPM is the verifier type reported for requests authenticated with TPM attestation.
+const verifierTPM = "tpm"
+
+// Results of a bootstrap or renewal request, used as the result label and in the audit log.
+const (
+	resultSuccess          = "success"
+	resultBadRequest       = "bad_request"
+	resultVerifyFailed     = "verify_failed"
+	resultRevoked          = "revoked"
+	resultAlreadyExists    = "already_exists"
+	resultCallbackFailed   = "callback_failed"
+	resultNodeConfigFailed = "node_config_failed"
+	resultIssueFailed      = "issue_failed"
+	resultInternalError    = "internal_error"
+	// resultUnchanged is reported when a running node already has the current configuration.
+	resultUnchanged = "unchanged"
+	// resultThrottled is reported when a running node must wait for other nodes to apply a configuration change.
+	resultThrottled = "throttled"
+)
+
+var (
+	// bootstrapRequests counts bootstrap requests, by result and verifier type
+	bootstrapRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kops_controller_bootstrap_requests_total",
+		Help: "Number of node bootstrap requests, by result and verifier type.",
+	}, []string{"result", "verifier"})
+
+	// bootstrapVerifyDuration measures how long it takes to verify the identity of a node
+	bootstrapVerifyDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "kops_controller_bootstrap_verify_duration_seconds",
+		Help:    "Time taken to verify the identity of a node, by verifier type.",
+		Buckets: prometheus.ExponentialBuckets(0.01, 2, 12),
+	}, []string{"verifier"})
+
+	// certificatesIssued counts the certificates issued to nodes, by certificate name and signing keypair
+	certificatesIssued = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kops_controller_certificates_issued_total",
+		Help: "Number of certificates issued to nodes, by certificate name and signing keypair.",
+	}, []string{"name", "signer"})
+
+	// nodeConfigRequests counts the node configurations served to nodes, by result
+	nodeConfigRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kops_controller_node_config_requests_total",
+		Help: "Number of node configuration fetches, by result.",
+	}, []string{"result"})
+
+	// certificateRenewals counts certificate renewal requests, by result
+	certificateRenewals = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kops_controller_certificate_renewals_total",
+		Help: "Number of node certificate renewal requests, by result.",
+	}, []string{"result"})
+
+	// nodeConfigPolls counts the requests from the node configuration agent on running nodes, by result
+	nodeConfigPolls = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kops_controller_node_config_polls_total",
+		Help: "Number of node configuration polls from running nodes, by result.",
+	}, []string{"result"})
+
+	// certificateExpiration reports when the certificates in the cluster keystore and the serving certificate expire
+	certificateExpiration = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kops_controller_certificate_expiration_timestamp_seconds",
+		Help: "Time at which the keypairs in the cluster keystore and the serving certificate of kops-controller expire, as seconds since the Unix epoch, by keyset name and keypair ID.",
+	}, []string{"name", "id"})
+
+	// auditErrors counts the audit records that could not be written, by destination
+	auditErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kops_controller_bootstrap_audit_errors_total",
+		Help: "Number of bootstrap audit records that could not be written, by destination.",
+	}, []string{"destination"})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(bootstrapRequests, bootstrapVerifyDuration, certificatesIssued, nodeConfigRequests, certificateRenewals, nodeConfigPolls, certificateExpiration, auditErrors)
+}
+
+// verifierTypes maps the authentication token prefixes to the verifier type reported in metrics and audit records.
+// Unknown prefixes are reported as "unknown", so that callers cannot create arbitrary label values.
+var verifierTypes = map[string]string{
+	awsup.AWSAuthenticationTokenPrefixV1:         "aws",
+	awsup.AWSAuthenticationTokenPrefixV2:         "aws",
+	azure.AzureAuthenticationTokenPrefix:         "azure",
+	do.DOAuthenticationTokenPrefix:               "digitalocean",
+	gcetpm.GCETPMAuthenticationTokenPrefix:       "gce-tpm",
+	hetzner.HetznerAuthenticationTokenPrefix:     "hetzner",
+	openstack.OpenstackAuthenticationTokenPrefix: "openstack",
+	pkibootstrap.AuthenticationTokenPrefix:       "pki",
+	scaleway.ScalewayAuthenticationTokenPrefix:   "scaleway",
+	tpmbootstrap.AuthenticationTokenPrefix:       verifierTPM,
+}
+
+// verifierType returns the type of verifier that the authentication token is intended for.
+func verifierType(token string) string {
+	prefix, _, _ := strings.Cut(token, " ")
+	if t, ok := verifierTypes[prefix+" "]; ok {
+		return t
+	}
+	return "unknown"
+}
+
+// certificateExpirationInterval is how often the expiry of the certificates is refreshed.
+const certificateExpirationInterval = 10 * time.Minute
+
+// refreshCertificateExpiration periodically reports the expiry of the certificates, so that keypairs
+// that are added to or removed from the keystore and a renewed serving certificate are reflected.
+func (s *Server) refreshCertificateExpiration(ctx context.Context) {
+	ticker := time.NewTicker(certificateExpirationInterval)
+	defer ticker.Stop()
+	for {
+		if err := s.recordCertificateExpiration(); err != nil {
+			klog.Warningf("unable to report certificate expiration: %v", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// recordCertificateExpiration reports the expiry of every keypair in the cluster keystore and of the serving certificate.
+// The signing keypairs that kops-controller loaded are reported even if the cluster keystore cannot be read.
+func (s *Server) recordCertificateExpiration() error {
+	certificateExpiration.Reset()
+
+	for name, entry := range s.keystore.keys {
+		certificateExpiration.WithLabelValues(name, s.keypairIDs[name]).Set(float64(entry.certificate.Certificate.NotAfter.Unix()))
+	}
+
+	var errs []error
+	keysets, err := s.clusterKeystore.ListKeysets()
+	if err != nil {
+		errs = append(errs, fmt.Errorf("listing keysets: %w", err))
+	}
+	for name, keyset := range keysets {
+		for id, item := range keyset.Items {
+			if item.Certificate == nil {
+				continue
+			}
+			certificateExpiration.WithLabelValues(name, id).Set(float64(item.Certificate.Certificate.NotAfter.Unix()))
+		}
+	}
+
+	if err := recordServingCertificateExpiration(s.opt.Server.ServerCertificatePath); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// recordServingCertificateExpiration reports the expiry of the serving certificate.
+func recordServingCertificateExpiration(servingCertificatePath string) error {
+	certBytes, err := os.ReadFile(servingCertificatePath)
+	if err != nil {
+		return fmt.Errorf("reading serving certificate: %w", err)
+	}
+	certificate, err := pki.ParsePEMCertificate(certBytes)
+	if err != nil {
+		return fmt.Errorf("parsing serving certificate: %w", err)
+	}
+	certificateExpiration.WithLabelValues("kops-controller", certificate.Certificate.SerialNumber.String()).Set(float64(certificate.Certificate.NotAfter.Unix()))
+	return nil
+}
@@ -18,8 +18,12 @@ package server
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
 
 	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/apis/kops"
@@ -106,6 +110,107 @@ func (s *Server) getNodeConfig(ctx context.Context, req *nodeup.BootstrapRequest
 	return nodeConfig, nil
 }
 
+// nodeConfig serves the current configuration of running nodes to the node configuration agent.
+// Nodes authenticate with their kubelet client certificate, and the configuration is only sent if it changed.
+func (s *Server) nodeConfig(w http.ResponseWriter, r *http.Request) {
+	var result string
+	defer func() {
+		nodeConfigPolls.WithLabelValues(result).Inc()
+	}()
+
+	ctx := r.Context()
+
+	nodeName, node, result := s.authenticateNode(w, r, "node-config")
+	if node == nil {
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		klog.Infof("node-config %s read err: %v", r.RemoteAddr, err)
+		result = resultBadRequest
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	req := &nodeup.NodeConfigRequest{}
+	if err := json.Unmarshal(body, req); err != nil {
+		klog.Infof("node-config %s decode err: %v", r.RemoteAddr, err)
+		result = resultBadRequest
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = fmt.Fprintf(w, "failed to decode: %v", err)
+		return
+	}
+
+	if req.APIVersion != nodeup.BootstrapAPIVersion {
+		klog.Infof("node-config %s wrong APIVersion", r.RemoteAddr)
+		result = resultBadRequest
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("unexpected APIVersion"))
+		return
+	}
+
+	id := &bootstrap.VerifyResult{
+		NodeName:          nodeName,
+		InstanceGroupName: node.Labels[kops.NodeLabelInstanceGroup],
+	}
+	nodeConfig, err := s.getNodeConfig(ctx, nil, id)
+	if err != nil {
+		klog.Infof("node-config %s node %q error getting node config: %v", r.RemoteAddr, nodeName, err)
+		result = resultNodeConfigFailed
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("failed to get node config"))
+		return
+	}
+
+	resp := &nodeup.NodeConfigResponse{}
+	resp.Generation, err = nodeConfigGeneration(nodeConfig)
+	if err != nil {
+		klog.Infof("node-config %s node %q error hashing node config: %v", r.RemoteAddr, nodeName, err)
+		result = resultInternalError
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("internal error"))
+		return
+	}
+	if req.Generation == resp.Generation {
+		result = resultUnchanged
+		if err := s.releaseNodeConfigSlot(ctx, node); err != nil {
+			klog.Warningf("node-config %s node %q: %v", r.RemoteAddr, nodeName, err)
+		}
+	} else {
+		acquired, err := s.acquireNodeConfigSlot(ctx, nodeName)
+		if err != nil {
+			klog.Infof("node-config %s node %q error acquiring slot: %v", r.RemoteAddr, nodeName, err)
+			result = resultInternalError
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte("internal error"))
+			return
+		}
+		if acquired {
+			klog.Infof("node-config %s node %q has generation %q; sending generation %q", r.RemoteAddr, nodeName, req.Generation, resp.Generation)
+			result = resultSuccess
+			resp.NodeConfig = nodeConfig
+		} else {
+			// Too many nodes are applying a change; the node asks again at its next poll.
+			klog.Infof("node-config %s node %q has generation %q; waiting for other nodes to apply generation %q", r.RemoteAddr, nodeName, req.Generation, resp.Generation)
+			result = resultThrottled
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// nodeConfigGeneration identifies a node configuration, so that nodes only download and apply it when it changes.
+func nodeConfigGeneration(nodeConfig *nodeup.NodeConfig) (string, error) {
+	data, err := json.Marshal(nodeConfig)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:]), nil
+}
+
 // buildInstanceGroupFromCAPI builds an InstanceGroup from a CAPI Machine, for building bootstrap data.
 // It builds a minimal instanceGroup, because many fields (e.g. image, machineType, minSize, maxSize)
 // are not relevant for building the bootstrap data.
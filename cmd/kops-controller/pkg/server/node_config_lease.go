@@ -0,0 +1,139 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/cmd/kops-controller/pkg/config"
+	"k8s.io/kops/upup/pkg/fi"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// nodeConfigLeaseDuration is how long a node can hold a slot to apply a configuration change.
+// If it does not become Ready again in that time, for example because it failed, the slot is given to another node.
+const nodeConfigLeaseDuration = 15 * time.Minute
+
+// acquireNodeConfigSlot returns true if the node may apply a configuration change,
+// because it holds or has acquired one of the NodeConfigMaxUnavailable slots.
+// The slots are Lease objects, which are updated with optimistic concurrency,
+// so the limit holds across all kops-controller instances.
+func (s *Server) acquireNodeConfigSlot(ctx context.Context, nodeName string) (bool, error) {
+	maxUnavailable := s.opt.Server.NodeConfigMaxUnavailable
+	if maxUnavailable == 0 {
+		klog.Infof("node configuration agent is not enabled; not sending configuration changes to node %q", nodeName)
+		return false, nil
+	}
+
+	leases := make([]*coordinationv1.Lease, maxUnavailable)
+	for i := range leases {
+		lease := &coordinationv1.Lease{}
+		id := types.NamespacedName{Namespace: "kube-system", Name: config.NodeConfigLeasePrefix + strconv.Itoa(i)}
+		if err := s.uncachedClient.Get(ctx, id, lease); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return false, fmt.Errorf("getting lease %s: %w", id, err)
+			}
+			lease.Namespace = id.Namespace
+			lease.Name = id.Name
+		} else if holder := lease.Spec.HolderIdentity; holder != nil && *holder == nodeName {
+			return true, nil
+		}
+		leases[i] = lease
+	}
+
+	now := metav1.NowMicro()
+	for _, lease := range leases {
+		if lease.ResourceVersion != "" && !nodeConfigLeaseExpired(lease, now.Time) {
+			continue
+		}
+
+		lease.Spec.HolderIdentity = &nodeName
+		lease.Spec.LeaseDurationSeconds = fi.PtrTo(int32(nodeConfigLeaseDuration.Seconds()))
+		lease.Spec.AcquireTime = &now
+		lease.Spec.RenewTime = &now
+		var err error
+		if lease.ResourceVersion == "" {
+			err = s.uncachedClient.Create(ctx, lease)
+		} else {
+			err = s.uncachedClient.Update(ctx, lease)
+		}
+		if err != nil {
+			if apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err) {
+				// Another kops-controller gave the slot to another node.
+				continue
+			}
+			return false, fmt.Errorf("acquiring lease %s/%s: %w", lease.Namespace, lease.Name, err)
+		}
+		klog.Infof("node %q acquired lease %s/%s to apply a configuration change", nodeName, lease.Namespace, lease.Name)
+		return true, nil
+	}
+	return false, nil
+}
+
+// releaseNodeConfigSlot releases the slot held by the node, once it has applied the configuration and is Ready again.
+// The node must have become Ready after it acquired the slot: kubelet may not yet have reported NotReady after restarting.
+func (s *Server) releaseNodeConfigSlot(ctx context.Context, node *corev1.Node) error {
+	for i := 0; i < s.opt.Server.NodeConfigMaxUnavailable; i++ {
+		lease := &coordinationv1.Lease{}
+		id := types.NamespacedName{Namespace: "kube-system", Name: config.NodeConfigLeasePrefix + strconv.Itoa(i)}
+		if err := s.uncachedClient.Get(ctx, id, lease); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("getting lease %s: %w", id, err)
+		}
+		if holder := lease.Spec.HolderIdentity; holder == nil || *holder != node.Name {
+			continue
+		}
+		if lease.Spec.AcquireTime == nil || !nodeReadySince(node, lease.Spec.AcquireTime.Time) {
+			continue
+		}
+		if err := s.uncachedClient.Delete(ctx, lease, client.Preconditions{ResourceVersion: &lease.ResourceVersion}); err != nil && !apierrors.IsNotFound(err) && !apierrors.IsConflict(err) {
+			return fmt.Errorf("releasing lease %s: %w", id, err)
+		}
+		klog.Infof("node %q released lease %s after applying a configuration change", node.Name, id)
+	}
+	return nil
+}
+
+// nodeConfigLeaseExpired returns true if the holder of the lease did not become Ready in time.
+func nodeConfigLeaseExpired(lease *coordinationv1.Lease, now time.Time) bool {
+	if lease.Spec.HolderIdentity == nil || lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return true
+	}
+	expiry := lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+	return now.After(expiry)
+}
+
+// nodeReadySince returns true if the node has the Ready condition, and it became Ready after the given time.
+func nodeReadySince(node *corev1.Node, since time.Time) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue && condition.LastTransitionTime.After(since)
+		}
+	}
+	return false
+}
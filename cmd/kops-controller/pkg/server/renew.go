@@ -0,0 +1,214 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/nodeup"
+	"k8s.io/kops/pkg/bootstrap"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/pkg/rbac"
+)
+
+// renew handles certificate renewal requests from nodes that authenticate with their current kubelet client certificate.
+func (s *Server) renew(w http.ResponseWriter, r *http.Request) {
+	audit := &auditRecord{
+		Time:       time.Now(),
+		Request:    requestRenew,
+		RemoteAddr: r.RemoteAddr,
+		Verifier:   verifierClientCertificate,
+	}
+	defer func() {
+		certificateRenewals.WithLabelValues(audit.Result).Inc()
+		s.auditLog.record(r.Context(), audit)
+	}()
+
+	ctx := r.Context()
+
+	nodeName, node, result := s.authenticateNode(w, r, "renew")
+	audit.NodeName = nodeName
+	if node == nil {
+		audit.Result = result
+		return
+	}
+	audit.InstanceGroupName = node.Labels[kops.NodeLabelInstanceGroup]
+	audit.verified = true
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		klog.Infof("renew %s read err: %v", r.RemoteAddr, err)
+		audit.Result = resultBadRequest
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	req := &nodeup.RenewRequest{}
+	if err := json.Unmarshal(body, req); err != nil {
+		klog.Infof("renew %s decode err: %v", r.RemoteAddr, err)
+		audit.Result = resultBadRequest
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = fmt.Fprintf(w, "failed to decode: %v", err)
+		return
+	}
+
+	if req.APIVersion != nodeup.BootstrapAPIVersion {
+		klog.Infof("renew %s wrong APIVersion", r.RemoteAddr)
+		audit.Result = resultBadRequest
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("unexpected APIVersion"))
+		return
+	}
+
+	id := &bootstrap.VerifyResult{
+		NodeName:          nodeName,
+		InstanceGroupName: audit.InstanceGroupName,
+	}
+	if _, found := req.Certs["kubelet-server"]; found {
+		names, err := s.kubeletServerNames(nodeName, req.Current["kubelet-server"])
+		if err != nil {
+			klog.Infof("renew %s kubelet-server err: %v", r.RemoteAddr, err)
+			audit.Result = resultBadRequest
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = fmt.Fprintf(w, "cannot renew kubelet-server certificate: %v", err)
+			return
+		}
+		id.CertificateNames = names
+	}
+
+	resp := &nodeup.BootstrapResponse{}
+	resp.Certs, err = s.issueCerts(ctx, req.Certs, id, certificateValidHours(nodeName), req.KeypairIDs, audit)
+	if err != nil {
+		klog.Infof("renew %s issue err: %v", r.RemoteAddr, err)
+		audit.Result = resultIssueFailed
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = fmt.Fprintf(w, "failed to issue: %v", err)
+		return
+	}
+
+	audit.Result = resultSuccess
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+	klog.Infof("renew %s node %q (req.certs.#: %d) success", r.RemoteAddr, nodeName, len(req.Certs))
+}
+
+// authenticateNode identifies the node that sent the request with its kubelet client certificate,
+// and checks that the node has not been revoked and is still registered.
+// If the node is not allowed, it writes the response and returns a nil node, with the result to report.
+func (s *Server) authenticateNode(w http.ResponseWriter, r *http.Request, requestType string) (string, *corev1.Node, string) {
+	ctx := r.Context()
+
+	nodeName, err := nodeNameFromClientCertificate(r.TLS)
+	if err != nil {
+		klog.Infof("%s %s verify err: %v", requestType, r.RemoteAddr, err)
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte("failed to verify client certificate"))
+		return "", nil, resultVerifyFailed
+	}
+
+	// Only nodes that are still part of the cluster are served.
+	node := &corev1.Node{}
+	if err := s.uncachedClient.Get(ctx, types.NamespacedName{Name: nodeName}, node); err != nil {
+		if errors.IsNotFound(err) {
+			klog.Infof("%s %s node %q not found; denying", requestType, r.RemoteAddr, nodeName)
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte("node not registered"))
+			return nodeName, nil, resultVerifyFailed
+		}
+		klog.Infof("%s %s error querying for node %q: %v", requestType, r.RemoteAddr, nodeName, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("internal error"))
+		return nodeName, nil, resultInternalError
+	}
+
+	if s.revocations == nil {
+		return nodeName, node, ""
+	}
+	revoked, err := s.revocations.Get(ctx, nodeName)
+	if err != nil {
+		klog.Infof("%s %s error checking revocation of node %q: %v", requestType, r.RemoteAddr, nodeName, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("internal error"))
+		return nodeName, nil, resultInternalError
+	}
+	if revoked != nil && revoked.AppliesTo(node.UID) {
+		klog.Infof("%s %s node %q was revoked at %v; denying", requestType, r.RemoteAddr, nodeName, revoked.RevokedAt)
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte("failed to verify client certificate"))
+		return nodeName, nil, resultRevoked
+	}
+
+	return nodeName, node, ""
+}
+
+// nodeNameFromClientCertificate returns the name of the node that the verified client certificate was issued to.
+func nodeNameFromClientCertificate(state *tls.ConnectionState) (string, error) {
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return "", fmt.Errorf("no verified client certificate")
+	}
+	cert := state.VerifiedChains[0][0]
+
+	nodeName, found := strings.CutPrefix(cert.Subject.CommonName, "system:node:")
+	if !found || nodeName == "" || !slices.Contains(cert.Subject.Organization, rbac.NodesGroup) {
+		return "", fmt.Errorf("client certificate %q is not a node certificate", cert.Subject.CommonName)
+	}
+	return nodeName, nil
+}
+
+// kubeletServerNames returns the alternate names of the node's current kubelet-server certificate,
+// so that a renewed certificate cannot claim names that the node was not given when it bootstrapped.
+func (s *Server) kubeletServerNames(nodeName string, current string) ([]string, error) {
+	if current == "" {
+		return nil, fmt.Errorf("the current certificate is required")
+	}
+	cert, err := pki.ParsePEMCertificate([]byte(current))
+	if err != nil {
+		return nil, fmt.Errorf("parsing current certificate: %w", err)
+	}
+
+	// The current certificate may have expired; we only need to know that we issued it.
+	_, err = cert.Certificate.Verify(x509.VerifyOptions{
+		Roots:       s.nodeCAs,
+		CurrentTime: cert.Certificate.NotBefore,
+		KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("verifying current certificate: %w", err)
+	}
+	if cert.Subject.CommonName != nodeName {
+		return nil, fmt.Errorf("current certificate was issued to %q", cert.Subject.CommonName)
+	}
+
+	names := append([]string{}, cert.Certificate.DNSNames...)
+	for _, ip := range cert.Certificate.IPAddresses {
+		names = append(names, ip.String())
+	}
+	return names, nil
+}
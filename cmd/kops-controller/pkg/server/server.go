@@ -23,10 +23,13 @@ import (
 	"crypto/x509/pkix"
 	"encoding/json"
 	"encoding/pem"
+	stderrors "errors"
 	"fmt"
 	"hash/fnv"
 	"io"
 	"net/http"
+	"os"
+	"path"
 	"runtime/debug"
 	"time"
 
@@ -56,9 +59,20 @@ type Server struct {
 	certNames   sets.Set[string]
 	keypairIDs  map[string]string
 	server      *http.Server
+	mux         *http.ServeMux
 	verifier    bootstrap.Verifier
 	keystore    *keystore
 	secretStore fi.SecretStore
+	auditLog    *auditLog
+
+	// clusterKeystore is the cluster's keystore in the state store, whose keypairs are reported in metrics.
+	clusterKeystore fi.CAStore
+
+	// revocations is the list of nodes whose identity has been revoked, or nil if revocation is not enabled.
+	revocations *bootstrap.RevocationList
+
+	// nodeCAs verifies the client certificates that nodes present when renewing their certificates.
+	nodeCAs *x509.CertPool
 
 	clientset simple.Clientset
 
@@ -74,7 +88,8 @@ type Server struct {
 
 var _ manager.LeaderElectionRunnable = &Server{}
 
-func NewServer(vfsContext *vfs.VFSContext, opt *config.Options, verifier bootstrap.Verifier, uncachedClient client.Client) (*Server, error) {
+// NewServer builds the server; the cluster configures the encryption of the secrets in the state store.
+func NewServer(vfsContext *vfs.VFSContext, opt *config.Options, cluster *kops.Cluster, verifier bootstrap.Verifier, uncachedClient client.Client) (*Server, error) {
 	server := &http.Server{
 		Addr: opt.Server.Listen,
 		TLSConfig: &tls.Config{
@@ -96,16 +111,44 @@ func NewServer(vfsContext *vfs.VFSContext, opt *config.Options, verifier bootstr
 	}
 	s.configBase = configBase
 
+	// Nodes whose identity has been revoked are rejected, however they authenticate.
+	if opt.NodeIdentityRevocation {
+		s.revocations = bootstrap.NewRevocationList(configBase)
+		s.verifier = bootstrap.NewRevocationVerifier(verifier, s.revocations)
+	}
+
+	s.auditLog, err = newAuditLog(vfsContext, opt.Server.AuditLog)
+	if err != nil {
+		return nil, err
+	}
+
 	s.keystore, s.keypairIDs, err = newKeystore(opt.Server.CABasePath, opt.Server.SigningCAs)
 	if err != nil {
 		return nil, err
 	}
 
+	// Nodes renewing their certificates authenticate with the kubelet client certificate we issued them.
+	s.nodeCAs, err = loadCertPool(path.Join(opt.Server.CABasePath, fi.CertificateIDCA+".crt"))
+	if err != nil {
+		return nil, err
+	}
+	server.TLSConfig.ClientCAs = s.nodeCAs
+	server.TLSConfig.ClientAuth = tls.VerifyClientCertIfGiven
+
 	p, err := vfsContext.BuildVfsPath(opt.SecretStore)
 	if err != nil {
 		return nil, fmt.Errorf("cannot parse SecretStore %q: %w", opt.SecretStore, err)
 	}
-	s.secretStore = secrets.NewVFSSecretStore(nil, p)
+	s.secretStore = secrets.NewVFSSecretStore(cluster, p)
+
+	keypairsPath := configBase.Join("pki")
+	if cluster.Spec.ConfigStore.Keypairs != "" {
+		keypairsPath, err = vfsContext.BuildVfsPath(cluster.Spec.ConfigStore.Keypairs)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse keypairs store %q: %w", cluster.Spec.ConfigStore.Keypairs, err)
+		}
+	}
+	s.clusterKeystore = fi.NewVFSCAStore(cluster, keypairsPath)
 
 	clientset, err := controllerclientset.New(vfsContext, configBase, opt.ClusterName, s.keystore, s.secretStore)
 	if err != nil {
@@ -119,9 +162,11 @@ func NewServer(vfsContext *vfs.VFSContext, opt *config.Options, verifier bootstr
 	}
 	s.challengeClient = challengeClient
 
-	r := http.NewServeMux()
-	r.Handle("/bootstrap", http.HandlerFunc(s.bootstrap))
-	server.Handler = recovery(r)
+	s.mux = http.NewServeMux()
+	s.mux.Handle("/bootstrap", http.HandlerFunc(s.bootstrap))
+	s.mux.Handle("/renew", http.HandlerFunc(s.renew))
+	s.mux.Handle("/node-config", http.HandlerFunc(s.nodeConfig))
+	server.Handler = recovery(s.mux)
 
 	return s, nil
 }
@@ -135,6 +180,8 @@ func (s *Server) NeedLeaderElection() bool {
 }
 
 func (s *Server) Start(ctx context.Context) error {
+	go s.refreshCertificateExpiration(ctx)
+
 	go func() {
 		<-ctx.Done()
 
@@ -155,8 +202,21 @@ func (s *Server) Start(ctx context.Context) error {
 }
 
 func (s *Server) bootstrap(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get("Authorization")
+	audit := &auditRecord{
+		Time:       time.Now(),
+		Request:    requestBootstrap,
+		RemoteAddr: r.RemoteAddr,
+		Verifier:   verifierType(token),
+	}
+	defer func() {
+		bootstrapRequests.WithLabelValues(audit.Result, audit.Verifier).Inc()
+		s.auditLog.record(r.Context(), audit)
+	}()
+
 	if r.Body == nil {
 		klog.Infof("bootstrap %s no body", r.RemoteAddr)
+		audit.Result = resultBadRequest
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
@@ -164,6 +224,7 @@ func (s *Server) bootstrap(w http.ResponseWriter, r *http.Request) {
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
 		klog.Infof("bootstrap %s read err: %v", r.RemoteAddr, err)
+		audit.Result = resultBadRequest
 		w.WriteHeader(http.StatusBadRequest)
 		_, _ = fmt.Fprintf(w, "bootstrap %s failed to read body: %v", r.RemoteAddr, err)
 		return
@@ -171,20 +232,30 @@ func (s *Server) bootstrap(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
 
-	id, err := s.verifier.VerifyToken(ctx, r, r.Header.Get("Authorization"), body)
+	verifyStart := time.Now()
+	id, err := s.verifier.VerifyToken(ctx, r, token, body)
+	bootstrapVerifyDuration.WithLabelValues(audit.Verifier).Observe(time.Since(verifyStart).Seconds())
 	if err != nil {
 		// means that we should exit nodeup gracefully
 		if err == bootstrap.ErrAlreadyExists {
+			audit.Result = resultAlreadyExists
 			w.WriteHeader(http.StatusConflict)
 			klog.Infof("%s: %v", r.RemoteAddr, err)
 			return
 		}
 		klog.Infof("bootstrap %s verify err: %v", r.RemoteAddr, err)
+		audit.Result = resultVerifyFailed
+		if stderrors.Is(err, bootstrap.ErrRevoked) {
+			audit.Result = resultRevoked
+		}
 		w.WriteHeader(http.StatusForbidden)
 		// don't return the error; this allows us to have richer errors without security implications
 		_, _ = w.Write([]byte("failed to verify token"))
 		return
 	}
+	audit.NodeName = id.NodeName
+	audit.InstanceGroupName = id.InstanceGroupName
+	audit.verified = true
 
 	// Once the node is registered, we don't allow further registrations, this protects against a pod or escaped workload attempting to impersonate the node.
 	{
@@ -194,6 +265,7 @@ func (s *Server) bootstrap(w http.ResponseWriter, r *http.Request) {
 			for _, condition := range node.Status.Conditions {
 				if condition.Type == corev1.NodeReady && condition.Status == corev1.ConditionTrue {
 					klog.Infof("bootstrap %s node %q already exists; denying to avoid node-impersonation attacks", r.RemoteAddr, id.NodeName)
+					audit.Result = resultAlreadyExists
 					w.WriteHeader(http.StatusConflict)
 					_, _ = w.Write([]byte("node already registered"))
 					return
@@ -202,6 +274,7 @@ func (s *Server) bootstrap(w http.ResponseWriter, r *http.Request) {
 		}
 		if err != nil && !errors.IsNotFound(err) {
 			klog.Infof("bootstrap %s error querying for node %q: %v", r.RemoteAddr, id.NodeName, err)
+			audit.Result = resultInternalError
 			w.WriteHeader(http.StatusInternalServerError)
 			_, _ = w.Write([]byte("internal error"))
 			return
@@ -211,6 +284,7 @@ func (s *Server) bootstrap(w http.ResponseWriter, r *http.Request) {
 	req := &nodeup.BootstrapRequest{}
 	if err := json.Unmarshal(body, req); err != nil {
 		klog.Infof("bootstrap %s decode err: %v", r.RemoteAddr, err)
+		audit.Result = resultBadRequest
 		w.WriteHeader(http.StatusBadRequest)
 		_, _ = fmt.Fprintf(w, "failed to decode: %v", err)
 		return
@@ -218,20 +292,24 @@ func (s *Server) bootstrap(w http.ResponseWriter, r *http.Request) {
 
 	if req.APIVersion != nodeup.BootstrapAPIVersion {
 		klog.Infof("bootstrap %s wrong APIVersion", r.RemoteAddr)
+		audit.Result = resultBadRequest
 		w.WriteHeader(http.StatusBadRequest)
 		_, _ = w.Write([]byte("unexpected APIVersion"))
 		return
 	}
 
-	if model.UseChallengeCallback(kops.CloudProviderID(s.opt.Cloud)) {
+	// A TPM quote already binds the request to the node's hardware, so we don't need the callback challenge.
+	if model.UseChallengeCallback(kops.CloudProviderID(s.opt.Cloud)) && audit.Verifier != verifierTPM {
 		if id.ChallengeEndpoint == "" {
 			klog.Infof("cannot determine endpoint for bootstrap callback challenge from %q", r.RemoteAddr)
+			audit.Result = resultCallbackFailed
 			w.WriteHeader(http.StatusBadRequest)
 			_, _ = w.Write([]byte("callback failed"))
 			return
 		}
 		if err := s.challengeClient.DoCallbackChallenge(ctx, s.opt.ClusterName, id.ChallengeEndpoint, req); err != nil {
 			klog.Infof("bootstrap %s callback challenge failed: %v", r.RemoteAddr, err)
+			audit.Result = resultCallbackFailed
 			w.WriteHeader(http.StatusBadRequest)
 			_, _ = w.Write([]byte("callback failed"))
 			return
@@ -240,53 +318,78 @@ func (s *Server) bootstrap(w http.ResponseWriter, r *http.Request) {
 		klog.Infof("performed successful callback challenge with %s; identified as %s", id.ChallengeEndpoint, id.NodeName)
 	}
 
-	resp := &nodeup.BootstrapResponse{
-		Certs: map[string]string{},
-	}
+	resp := &nodeup.BootstrapResponse{}
 
 	// Support for nodes that have no access to the state store
 	if req.IncludeNodeConfig {
 		nodeConfig, err := s.getNodeConfig(r.Context(), req, id)
 		if err != nil {
 			klog.Infof("bootstrap failed to build node config: %v", err)
+			nodeConfigRequests.WithLabelValues("error").Inc()
+			audit.Result = resultNodeConfigFailed
 			w.WriteHeader(http.StatusBadRequest)
 			_, _ = w.Write([]byte("failed to build node config"))
 			return
 		}
+		nodeConfigRequests.WithLabelValues("success").Inc()
 		resp.NodeConfig = nodeConfig
+		audit.NodeConfig = true
 	}
 
-	// Skew the certificate lifetime by up to 30 days based on information about the requesting node.
-	// This is so that different nodes created at the same time have the certificates they generated
-	// expire at different times, but all certificates on a given node expire around the same time.
-	hash := fnv.New32()
-	_, _ = hash.Write([]byte(r.RemoteAddr))
-	validHours := (455 * 24) + (hash.Sum32() % (30 * 24))
-
-	for name, pubKey := range req.Certs {
-		cert, err := s.issueCert(ctx, name, pubKey, id, validHours, req.KeypairIDs)
-		if err != nil {
-			klog.Infof("bootstrap %s cert %q issue err: %v", r.RemoteAddr, name, err)
-			w.WriteHeader(http.StatusBadRequest)
-			_, _ = fmt.Fprintf(w, "failed to issue %q: %v", name, err)
-			return
-		}
-		resp.Certs[name] = cert
+	resp.Certs, err = s.issueCerts(ctx, req.Certs, id, certificateValidHours(r.RemoteAddr), req.KeypairIDs, audit)
+	if err != nil {
+		klog.Infof("bootstrap %s issue err: %v", r.RemoteAddr, err)
+		audit.Result = resultIssueFailed
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = fmt.Fprintf(w, "failed to issue: %v", err)
+		return
 	}
 
+	audit.Result = resultSuccess
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(resp)
 	klog.Infof("bootstrap %s (req.includeNodeConfig: %t, req.certs.#: %d, req.keypairs.#: %d) success", r.RemoteAddr, req.IncludeNodeConfig, len(req.Certs), len(req.KeypairIDs))
 }
 
-func (s *Server) issueCert(ctx context.Context, name string, pubKey string, id *bootstrap.VerifyResult, validHours uint32, keypairIDs map[string]string) (string, error) {
+// certificateValidHours returns the lifetime of the certificates issued in a request.
+// The lifetime is skewed by up to 30 days based on information about the requesting node.
+// This is so that different nodes created at the same time have the certificates they generated
+// expire at different times, but all certificates on a given node expire around the same time.
+func certificateValidHours(key string) uint32 {
+	hash := fnv.New32()
+	_, _ = hash.Write([]byte(key))
+	return (455 * 24) + (hash.Sum32() % (30 * 24))
+}
+
+// issueCerts issues the requested certificates for the node, recording them in the audit record.
+func (s *Server) issueCerts(ctx context.Context, pubKeys map[string]string, id *bootstrap.VerifyResult, validHours uint32, keypairIDs map[string]string, audit *auditRecord) (map[string]string, error) {
+	certs := map[string]string{}
+	for name, pubKey := range pubKeys {
+		cert, signer, err := s.issueCert(ctx, name, pubKey, id, validHours, keypairIDs)
+		if err == nil {
+			certs[name], err = cert.AsString()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cert %q: %w", name, err)
+		}
+		certificatesIssued.WithLabelValues(name, signer).Inc()
+		audit.Certificates = append(audit.Certificates, newAuditCertificate(name, signer, cert))
+	}
+	return certs, nil
+}
+
+// issueCert issues the named certificate for the node, returning it along with the name of the signing keypair.
+func (s *Server) issueCert(ctx context.Context, name string, pubKey string, id *bootstrap.VerifyResult, validHours uint32, keypairIDs map[string]string) (*pki.Certificate, string, error) {
 	block, _ := pem.Decode([]byte(pubKey))
+	if block == nil {
+		return nil, "", fmt.Errorf("no PEM data found")
+	}
 	if block.Type != "RSA PUBLIC KEY" {
-		return "", fmt.Errorf("unexpected key type %q", block.Type)
+		return nil, "", fmt.Errorf("unexpected key type %q", block.Type)
 	}
 	key, err := x509.ParsePKIXPublicKey(block.Bytes)
 	if err != nil {
-		return "", fmt.Errorf("parsing key: %v", err)
+		return nil, "", fmt.Errorf("parsing key: %v", err)
 	}
 
 	issueReq := &pki.IssueCertRequest{
@@ -297,7 +400,7 @@ func (s *Server) issueCert(ctx context.Context, name string, pubKey string, id *
 	}
 
 	if !s.certNames.Has(name) {
-		return "", fmt.Errorf("key name not enabled")
+		return nil, "", fmt.Errorf("key name not enabled")
 	}
 	switch name {
 	case "etcd-client-cilium":
@@ -325,22 +428,35 @@ func (s *Server) issueCert(ctx context.Context, name string, pubKey string, id *
 			CommonName: rbac.KubeRouter,
 		}
 	default:
-		return "", fmt.Errorf("unexpected key name")
+		return nil, "", fmt.Errorf("unexpected key name")
 	}
 
 	// This field was added to the protocol in kOps 1.22.
 	if len(keypairIDs) > 0 {
 		if keypairIDs[issueReq.Signer] != s.keypairIDs[issueReq.Signer] {
-			return "", fmt.Errorf("request's keypair ID %q for %s didn't match server's %q", keypairIDs[issueReq.Signer], issueReq.Signer, s.keypairIDs[issueReq.Signer])
+			return nil, "", fmt.Errorf("request's keypair ID %q for %s didn't match server's %q", keypairIDs[issueReq.Signer], issueReq.Signer, s.keypairIDs[issueReq.Signer])
 		}
 	}
 
 	cert, _, _, err := pki.IssueCert(ctx, issueReq, s.keystore)
 	if err != nil {
-		return "", fmt.Errorf("issuing certificate: %v", err)
+		return nil, "", fmt.Errorf("issuing certificate: %v", err)
 	}
 
-	return cert.AsString()
+	return cert, issueReq.Signer, nil
+}
+
+// loadCertPool reads a bundle of PEM-encoded certificates into a pool.
+func loadCertPool(p string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return nil, fmt.Errorf("reading %q: %w", p, err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates found in %q", p)
+	}
+	return pool, nil
 }
 
 // recovery is responsible for ensuring we don't exit on a panic.
@@ -0,0 +1,58 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/bootstrap/tpmbootstrap"
+)
+
+// maxTPMCredentialRequestSize bounds the size of credential requests, which are not authenticated.
+const maxTPMCredentialRequestSize = 64 * 1024
+
+// HandleTPMCredentials serves the credentials that nodes activate with their TPM before bootstrapping.
+func (s *Server) HandleTPMCredentials(verifier *tpmbootstrap.Verifier) {
+	s.mux.Handle(tpmbootstrap.CredentialPath, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.issueTPMCredential(verifier, w, r)
+	}))
+}
+
+func (s *Server) issueTPMCredential(verifier *tpmbootstrap.Verifier, w http.ResponseWriter, r *http.Request) {
+	req := &tpmbootstrap.CredentialRequest{}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxTPMCredentialRequestSize)).Decode(req); err != nil {
+		klog.Infof("tpm credential %s decode err: %v", r.RemoteAddr, err)
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = fmt.Fprintf(w, "failed to decode: %v", err)
+		return
+	}
+
+	resp, err := verifier.IssueCredential(r.Context(), req)
+	if err != nil {
+		klog.Infof("tpm credential %s err: %v", r.RemoteAddr, err)
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = fmt.Fprintf(w, "failed to issue credential: %v", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+	klog.Infof("tpm credential %s issued", r.RemoteAddr)
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/bootstrap/pkibootstrap"
+	"k8s.io/kops/pkg/commands"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/featureflag"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+const (
+	joinTokenOutputToken  = "token"
+	joinTokenOutputScript = "script"
+)
+
+var (
+	createJoinTokenLong = templates.LongDesc(i18n.T(`
+	Create a join token for enrolling a bare-metal machine into an instance group.
+
+	A join token can be used once, before it expires, by the machine named with --host to
+	register its own public key with kops-controller. The name must match the hostname of the machine. This allows machines to join the cluster without
+	an operator connecting to them over SSH.
+
+	With --output script, a self-contained script is printed that installs the token
+	and runs nodeup when it is run on the new machine.`))
+
+	createJoinTokenExample = templates.Examples(i18n.T(`
+	# Create a join token for the nodes instance group, valid for one hour
+	kops create join-token --name k8s-cluster.example.com --instance-group nodes --host node-1 --ttl 1h
+
+	# Enroll a machine without SSH
+	kops create join-token --name k8s-cluster.example.com --instance-group nodes --host node-1 -o script > join.sh
+	# ... then copy join.sh to the machine and run it as root
+	`))
+
+	createJoinTokenShort = i18n.T(`Create a join token for enrolling a machine.`)
+)
+
+type CreateJoinTokenOptions struct {
+	ClusterName   string
+	InstanceGroup string
+	Host          string
+	TTL           time.Duration
+	Output        string
+}
+
+func NewCmdCreateJoinToken(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &CreateJoinTokenOptions{
+		TTL:    time.Hour,
+		Output: joinTokenOutputToken,
+	}
+
+	cmd := &cobra.Command{
+		Use:               "join-token [CLUSTER] --instance-group NAME --host HOSTNAME",
+		Short:             createJoinTokenShort,
+		Long:              createJoinTokenLong,
+		Example:           createJoinTokenExample,
+		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
+		ValidArgsFunction: commandutils.CompleteClusterName(f, true, false),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunCreateJoinToken(cmd.Context(), f, out, options)
+		},
+	}
+
+	cmd.Flags().StringVar(&options.InstanceGroup, "instance-group", options.InstanceGroup, "Instance group that the machine joins")
+	cmd.MarkFlagRequired("instance-group")
+	cmd.Flags().StringVar(&options.Host, "host", options.Host, "Hostname of the machine that can use the token")
+	cmd.MarkFlagRequired("host")
+	cmd.Flags().DurationVar(&options.TTL, "ttl", options.TTL, "How long the token can be used for")
+	cmd.Flags().StringVarP(&options.Output, "output", "o", options.Output, "Output format. One of: token, script")
+	cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{joinTokenOutputToken, joinTokenOutputScript}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
+
+func RunCreateJoinToken(ctx context.Context, f commandutils.Factory, out io.Writer, options *CreateJoinTokenOptions) error {
+	if !featureflag.Metal.Enabled() {
+		return fmt.Errorf("join tokens require the Metal feature flag to be enabled")
+	}
+	if errs := validation.IsDNS1123Subdomain(options.Host); len(errs) != 0 {
+		return fmt.Errorf("invalid host %q: %s", options.Host, strings.Join(errs, ", "))
+	}
+	if options.TTL <= 0 {
+		return fmt.Errorf("ttl must be positive")
+	}
+	if options.Output != joinTokenOutputToken && options.Output != joinTokenOutputScript {
+		return fmt.Errorf("unknown output format %q", options.Output)
+	}
+
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	clientset, err := f.KopsClient()
+	if err != nil {
+		return err
+	}
+
+	configBuilder := &commands.ConfigBuilder{
+		Clientset:         clientset,
+		ClusterName:       cluster.ObjectMeta.Name,
+		InstanceGroupName: options.InstanceGroup,
+	}
+	ig, err := configBuilder.GetInstanceGroup(ctx)
+	if err != nil {
+		return err
+	}
+	if ig.IsControlPlane() {
+		// Control plane machines need files that cannot be fetched from kops-controller.
+		return fmt.Errorf("join tokens cannot be used for control plane instance group %q", ig.ObjectMeta.Name)
+	}
+
+	// Build the script before minting the token, so we don't leave unused tokens behind on error.
+	var nodeupScript []byte
+	if options.Output == joinTokenOutputScript {
+		bootstrapData, err := configBuilder.GetBootstrapData(ctx)
+		if err != nil {
+			return err
+		}
+		nodeupScript = bootstrapData.NodeupScript
+	}
+
+	secretStore, err := clientset.SecretStore(cluster)
+	if err != nil {
+		return err
+	}
+
+	token, id, record, err := pkibootstrap.NewJoinToken(ig.ObjectMeta.Name, options.Host, options.TTL)
+	if err != nil {
+		return err
+	}
+	secret, err := record.Encode()
+	if err != nil {
+		return err
+	}
+	if _, created, err := secretStore.GetOrCreateSecret(ctx, pkibootstrap.JoinTokenSecretName(id), secret); err != nil {
+		return fmt.Errorf("storing join token: %w", err)
+	} else if !created {
+		return fmt.Errorf("join token %q already exists", id)
+	}
+
+	if options.Output == joinTokenOutputToken {
+		_, err := fmt.Fprintln(out, token)
+		return err
+	}
+	_, err = io.WriteString(out, buildJoinScript(token, nodeupScript))
+	return err
+}
+
+// buildJoinScript returns a script that installs the join token and runs nodeup on the machine.
+func buildJoinScript(token string, nodeupScript []byte) string {
+	var sb strings.Builder
+	sb.WriteString("#!/bin/bash\n")
+	sb.WriteString("set -o errexit\nset -o nounset\nset -o pipefail\n\n")
+	fmt.Fprintf(&sb, "mkdir -p %s\n", path.Dir(pkibootstrap.JoinTokenPath))
+	fmt.Fprintf(&sb, "(umask 077 && echo %q > %s)\n\n", token, pkibootstrap.JoinTokenPath)
+	sb.WriteString("NODEUP_SCRIPT=$(mktemp)\n")
+	sb.WriteString("cat > \"${NODEUP_SCRIPT}\" <<'KOPS_NODEUP_EOF'\n")
+	sb.Write(nodeupScript)
+	if len(nodeupScript) != 0 && nodeupScript[len(nodeupScript)-1] != '\n' {
+		sb.WriteString("\n")
+	}
+	sb.WriteString("KOPS_NODEUP_EOF\n")
+	sb.WriteString("bash \"${NODEUP_SCRIPT}\"\n")
+	sb.WriteString("rm -f \"${NODEUP_SCRIPT}\"\n")
+	return sb.String()
+}
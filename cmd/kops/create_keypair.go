@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
@@ -52,6 +53,13 @@ var (
 	will be added to the keyset without a private key. Such a certificate
 	cannot be made primary.
 
+	The private key of the "kubernetes-ca", "apiserver-aggregator-ca" and
+	"addon-signing" keysets can instead be held in a PKCS#11 module or a
+	cloud KMS, and referenced with --key-uri. The key is never exported: kops
+	stores the reference, and signs through the module or KMS. PKCS#11
+	keys need kops, nodeup and kops-controller to be built with cgo and
+	-tags pkcs11.
+
 	One of the certificate/private key pairs in each keyset must be primary.
 	The primary keypair is the one used to issue certificates (or, for the
 	"service-account" keyset, service-account tokens). As a consequence, a
@@ -67,6 +75,16 @@ var (
 		--cert ~/ca.pem --key ~/ca-key.pem \
 		--name k8s-cluster.example.com --state s3://my-state-store
 
+	# Add a CA whose private key is held in an HSM, with a self-signed certificate.
+	kops create keypair kubernetes-ca --primary \
+		--key-uri "pkcs11:token=kops;object=kubernetes-ca?module-path=/usr/lib/softhsm/libsofthsm2.so&pin-source=/etc/kops/pin" \
+		--name k8s-cluster.example.com --state s3://my-state-store
+
+	# Sign the addon channels and manifests with a key held in AWS KMS.
+	kops create keypair addon-signing --primary \
+		--key-uri aws-kms://arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab \
+		--name k8s-cluster.example.com --state s3://my-state-store
+
 	# Add a newly generated certificate and private key to each rotatable keyset.
 	kops create keypair all \
 		--name k8s-cluster.example.com --state s3://my-state-store
@@ -79,10 +97,17 @@ type CreateKeypairOptions struct {
 	ClusterName    string
 	Keyset         string
 	PrivateKeyPath string
-	CertPath       string
-	Primary        bool
+	// PrivateKeyURI references a private key held in a PKCS#11 module or a cloud KMS.
+	PrivateKeyURI string
+	CertPath      string
+	Primary       bool
 }
 
+// keyReferenceKeysets are the keysets whose private key can be held outside kops.
+// The keys of the other keysets are read by components that need the key material,
+// such as etcd-manager or kube-apiserver.
+var keyReferenceKeysets = []string{fi.CertificateIDCA, "apiserver-aggregator-ca", fi.CertificateIDAddonSigning}
+
 func rotatableKeysetFilter(name string, _ *fi.Keyset) bool {
 	return name == "all" || name == "service-account" || strings.Contains(name, "-ca")
 }
@@ -120,11 +145,23 @@ func NewCmdCreateKeypair(f *util.Factory, out io.Writer) *cobra.Command {
 				if options.PrivateKeyPath != "" {
 					return fmt.Errorf("cannot specify --key with \"all\"")
 				}
+				if options.PrivateKeyURI != "" {
+					return fmt.Errorf("cannot specify --key-uri with \"all\"")
+				}
 				if options.Primary {
 					return fmt.Errorf("cannot specify --primary with \"all\"")
 				}
 			}
 
+			if options.PrivateKeyURI != "" {
+				if options.PrivateKeyPath != "" {
+					return fmt.Errorf("cannot specify both --key and --key-uri")
+				}
+				if !slices.Contains(keyReferenceKeysets, options.Keyset) {
+					return fmt.Errorf("--key-uri is only supported for the %s keysets", strings.Join(keyReferenceKeysets, ", "))
+				}
+			}
+
 			return nil
 		},
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -137,6 +174,7 @@ func NewCmdCreateKeypair(f *util.Factory, out io.Writer) *cobra.Command {
 
 	cmd.Flags().StringVar(&options.CertPath, "cert", options.CertPath, "Path to CA certificate")
 	cmd.Flags().StringVar(&options.PrivateKeyPath, "key", options.PrivateKeyPath, "Path to CA private key")
+	cmd.Flags().StringVar(&options.PrivateKeyURI, "key-uri", options.PrivateKeyURI, "URI of a CA private key held in a PKCS#11 module or a cloud KMS")
 	cmd.Flags().BoolVar(&options.Primary, "primary", options.Primary, "Make the keypair the one used to issue certificates")
 
 	return cmd
@@ -144,7 +182,7 @@ func NewCmdCreateKeypair(f *util.Factory, out io.Writer) *cobra.Command {
 
 // RunCreateKeypair adds a custom CA certificate and private key.
 func RunCreateKeypair(ctx context.Context, f *util.Factory, out io.Writer, options *CreateKeypairOptions) error {
-	if !rotatableKeysetFilter(options.Keyset, nil) {
+	if !rotatableKeysetFilter(options.Keyset, nil) && options.Keyset != fi.CertificateIDAddonSigning {
 		return fmt.Errorf("adding keypair to %q is not supported", options.Keyset)
 	}
 
@@ -164,7 +202,8 @@ func RunCreateKeypair(ctx context.Context, f *util.Factory, out io.Writer, optio
 	}
 
 	if options.Keyset != "all" {
-		return createKeypair(ctx, out, options, options.Keyset, keyStore)
+		_, err := createKeypair(ctx, out, options, options.Keyset, keyStore)
+		return err
 	}
 
 	keysets, err := keyStore.ListKeysets()
@@ -174,7 +213,7 @@ func RunCreateKeypair(ctx context.Context, f *util.Factory, out io.Writer, optio
 
 	for name := range keysets {
 		if rotatableKeysetFilter(name, nil) {
-			if err := createKeypair(ctx, out, options, name, keyStore); err != nil {
+			if _, err := createKeypair(ctx, out, options, name, keyStore); err != nil {
 				return fmt.Errorf("creating keypair for %s: %v", name, err)
 			}
 		}
@@ -183,19 +222,29 @@ func RunCreateKeypair(ctx context.Context, f *util.Factory, out io.Writer, optio
 	return nil
 }
 
-func createKeypair(ctx context.Context, out io.Writer, options *CreateKeypairOptions, name string, keyStore fi.CAStore) error {
+// createKeypair adds a keypair to the keyset, returning its ID.
+func createKeypair(ctx context.Context, out io.Writer, options *CreateKeypairOptions, name string, keyStore fi.CAStore) (string, error) {
 	var err error
 	var privateKey *pki.PrivateKey
 	if options.PrivateKeyPath != "" {
 		options.PrivateKeyPath = utils.ExpandPath(options.PrivateKeyPath)
 		privateKeyBytes, err := os.ReadFile(options.PrivateKeyPath)
 		if err != nil {
-			return fmt.Errorf("error reading user provided private key %q: %v", options.PrivateKeyPath, err)
+			return "", fmt.Errorf("error reading user provided private key %q: %v", options.PrivateKeyPath, err)
 		}
 
 		privateKey, err = pki.ParsePEMPrivateKey(privateKeyBytes)
 		if err != nil {
-			return fmt.Errorf("error loading private key %q: %v", privateKeyBytes, err)
+			return "", fmt.Errorf("error loading private key %q: %v", privateKeyBytes, err)
+		}
+	} else if options.PrivateKeyURI != "" {
+		privateKey, err = pki.NewPrivateKeyReference(options.PrivateKeyURI)
+		if err != nil {
+			return "", err
+		}
+		// Load the key now, to report a missing or inaccessible key before it is stored.
+		if err := privateKey.Load(); err != nil {
+			return "", err
 		}
 	}
 
@@ -204,7 +253,7 @@ func createKeypair(ctx context.Context, out io.Writer, options *CreateKeypairOpt
 		if privateKey == nil {
 			privateKey, err = pki.GeneratePrivateKey()
 			if err != nil {
-				return fmt.Errorf("error generating private key: %v", err)
+				return "", fmt.Errorf("error generating private key: %v", err)
 			}
 		}
 
@@ -217,18 +266,18 @@ func createKeypair(ctx context.Context, out io.Writer, options *CreateKeypairOpt
 		}
 		cert, _, _, err = pki.IssueCert(ctx, &req, nil)
 		if err != nil {
-			return fmt.Errorf("error issuing certificate: %v", err)
+			return "", fmt.Errorf("error issuing certificate: %v", err)
 		}
 	} else {
 		options.CertPath = utils.ExpandPath(options.CertPath)
 		certBytes, err := os.ReadFile(options.CertPath)
 		if err != nil {
-			return fmt.Errorf("error reading user provided cert %q: %v", options.CertPath, err)
+			return "", fmt.Errorf("error reading user provided cert %q: %v", options.CertPath, err)
 		}
 
 		cert, err = pki.ParsePEMCertificate(certBytes)
 		if err != nil {
-			return fmt.Errorf("error loading certificate %q: %v", options.CertPath, err)
+			return "", fmt.Errorf("error loading certificate %q: %v", options.CertPath, err)
 		}
 	}
 
@@ -237,24 +286,24 @@ func createKeypair(ctx context.Context, out io.Writer, options *CreateKeypairOpt
 	if os.IsNotExist(err) || (err == nil && keyset == nil) {
 		if options.Primary {
 			if keyset, err = fi.NewKeyset(cert, privateKey); err != nil {
-				return err
+				return "", err
 			}
 		} else {
-			return fmt.Errorf("the first keypair added to a keyset must be primary")
+			return "", fmt.Errorf("the first keypair added to a keyset must be primary")
 		}
 		item = keyset.Primary
 	} else if err != nil {
-		return fmt.Errorf("reading existing keyset: %v", err)
+		return "", fmt.Errorf("reading existing keyset: %v", err)
 	} else {
 		item, err = keyset.AddItem(cert, privateKey, options.Primary)
 	}
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	err = keyStore.StoreKeyset(ctx, name, keyset)
 	if err != nil {
-		return fmt.Errorf("error storing user provided keys %q %q: %v", options.CertPath, options.PrivateKeyPath, err)
+		return "", fmt.Errorf("error storing user provided keys %q %q: %v", options.CertPath, options.PrivateKeyPath, err)
 	}
 
 	if options.CertPath != "" {
@@ -264,7 +313,7 @@ func createKeypair(ctx context.Context, out io.Writer, options *CreateKeypairOpt
 		fmt.Fprintf(out, "using user provided private key: %v\n", options.PrivateKeyPath)
 	}
 	fmt.Fprintf(out, "Created %s %s\n", name, item.Id)
-	return nil
+	return item.Id, nil
 }
 
 func completeKeyset(ctx context.Context, cluster *kopsapi.Cluster, clientSet simple.Clientset, args []string, filter func(name string, keyset *fi.Keyset) bool) (keyset *fi.Keyset, keyStore fi.CAStore, completions []string, directive cobra.ShellCompDirective) {
@@ -71,6 +71,7 @@ func NewCmdDelete(f *util.Factory, out io.Writer) *cobra.Command {
 	cmd.AddCommand(NewCmdDeleteCluster(f, out))
 	cmd.AddCommand(NewCmdDeleteInstance(f, out))
 	cmd.AddCommand(NewCmdDeleteInstanceGroup(f, out))
+	cmd.AddCommand(NewCmdDeleteNodeIdentity(f, out))
 	cmd.AddCommand(NewCmdDeleteSecret(f, out))
 	cmd.AddCommand(NewCmdDeleteSSHPublicKey(f, out))
 
@@ -0,0 +1,218 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/acls"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/bootstrap"
+	"k8s.io/kops/pkg/kubeconfig"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	deleteNodeIdentityLong = templates.LongDesc(i18n.T(`
+	Revoke the identity of a node.
+
+	kops-controller rejects any further bootstrap, configuration or certificate renewal
+	requests from the node, however it authenticates. The node is also cordoned and
+	tainted with kops.k8s.io/quarantined:NoExecute, so that workloads are moved off it.
+
+	The revocation applies to the Node object that is registered when the node is revoked.
+	kops-controller removes it when that Node is deleted, so that a replacement node
+	can reuse the name. A node that is not registered stays revoked until it is restored
+	with --restore.
+
+	This does not terminate the instance; use kops delete instance for that once you
+	have finished investigating it.
+
+	Revocation must be enabled with spec.nodeIdentityRevocation in the cluster spec.`))
+
+	deleteNodeIdentityExample = templates.Examples(i18n.T(`
+	# Revoke the identity of a compromised node
+	kops delete node-identity i-0a5ed581b862d3425 --name k8s-cluster.example.com \
+		--reason "unexpected outbound connections" --yes
+
+	# Restore the identity of a node that was revoked by mistake
+	kops delete node-identity i-0a5ed581b862d3425 --name k8s-cluster.example.com --restore --yes
+	`))
+
+	deleteNodeIdentityShort = i18n.T(`Revoke the identity of a node.`)
+)
+
+type DeleteNodeIdentityOptions struct {
+	ClusterName string
+	NodeName    string
+	Reason      string
+	Restore     bool
+	Yes         bool
+
+	kubeconfig.CreateKubecfgOptions
+}
+
+func NewCmdDeleteNodeIdentity(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &DeleteNodeIdentityOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "node-identity NODE",
+		Short:   deleteNodeIdentityShort,
+		Long:    deleteNodeIdentityLong,
+		Example: deleteNodeIdentityExample,
+		Args: func(cmd *cobra.Command, args []string) error {
+			options.ClusterName = rootCommand.ClusterName(true)
+			if options.ClusterName == "" {
+				return fmt.Errorf("--name is required")
+			}
+
+			if len(args) != 1 {
+				return fmt.Errorf("must specify the name of the node to revoke")
+			}
+			options.NodeName = args[0]
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunDeleteNodeIdentity(cmd.Context(), f, out, options)
+		},
+	}
+
+	cmd.Flags().StringVar(&options.Reason, "reason", options.Reason, "Reason the node is being revoked, recorded in the state store")
+	cmd.Flags().BoolVar(&options.Restore, "restore", options.Restore, "Restore the identity of a revoked node, instead of revoking it")
+	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Specify --yes to immediately revoke the node identity")
+	options.CreateKubecfgOptions.AddCommonFlags(cmd.Flags())
+
+	return cmd
+}
+
+func RunDeleteNodeIdentity(ctx context.Context, f *util.Factory, out io.Writer, options *DeleteNodeIdentityOptions) error {
+	clientset, err := f.KopsClient()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	if cluster.Spec.NodeIdentityRevocation == nil {
+		return fmt.Errorf("node identity revocation is not enabled; set spec.nodeIdentityRevocation and run kops update cluster first")
+	}
+
+	configBase, err := clientset.ConfigBaseFor(cluster)
+	if err != nil {
+		return err
+	}
+	revocations := bootstrap.NewRevocationList(configBase)
+
+	existing, err := revocations.Get(ctx, options.NodeName)
+	if err != nil {
+		return err
+	}
+
+	if options.Restore {
+		if existing == nil {
+			fmt.Fprintf(out, "Node %q is not revoked\n", options.NodeName)
+			return nil
+		}
+		if !options.Yes {
+			fmt.Fprintf(out, "Node %q would be restored; it was revoked at %v.\n", options.NodeName, existing.RevokedAt)
+			fmt.Fprintf(out, "\nMust specify --yes to restore the node identity\n")
+			return nil
+		}
+		if err := revocations.Remove(ctx, options.NodeName); err != nil {
+			return fmt.Errorf("error restoring node identity: %w", err)
+		}
+		fmt.Fprintf(out, "Restored the identity of node %q; uncordon it and remove the %s taint to schedule workloads on it again\n", options.NodeName, bootstrap.QuarantineTaintKey)
+		return nil
+	}
+
+	if existing != nil {
+		fmt.Fprintf(out, "Node %q was already revoked at %v\n", options.NodeName, existing.RevokedAt)
+		return nil
+	}
+
+	if !options.Yes {
+		fmt.Fprintf(out, "Node %q would be revoked and quarantined.\n", options.NodeName)
+		fmt.Fprintf(out, "\nMust specify --yes to revoke the node identity\n")
+		return nil
+	}
+
+	acl, err := acls.GetACL(ctx, revocations.Path(options.NodeName), cluster)
+	if err != nil {
+		return err
+	}
+	revoked := &bootstrap.RevokedNode{
+		Name:      options.NodeName,
+		Reason:    options.Reason,
+		RevokedAt: time.Now().UTC(),
+	}
+
+	// Tie the revocation to the registered Node, so that it does not apply to a replacement with the same name.
+	// The API server may be unavailable during an incident, so we revoke the node by name if we cannot find it.
+	node, err := getRegisteredNode(ctx, f, cluster, options)
+	if err != nil {
+		klog.Warningf("unable to find Node %q, revoking any node with the name: %v", options.NodeName, err)
+	} else {
+		revoked.UID = node.UID
+		revoked.ProviderID = node.Spec.ProviderID
+	}
+
+	if err := revocations.Revoke(ctx, revoked, acl); err != nil {
+		return fmt.Errorf("error revoking node identity: %w", err)
+	}
+
+	fmt.Fprintf(out, "Revoked the identity of node %q; kops-controller will cordon and taint it\n", options.NodeName)
+	return nil
+}
+
+// getRegisteredNode returns the Node object of the node being revoked.
+func getRegisteredNode(ctx context.Context, f *util.Factory, cluster *kops.Cluster, options *DeleteNodeIdentityOptions) (*corev1.Node, error) {
+	restConfig, err := f.RESTConfig(ctx, cluster, options.CreateKubecfgOptions)
+	if err != nil {
+		return nil, fmt.Errorf("getting rest config: %w", err)
+	}
+	httpClient, err := f.HTTPClient(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("getting http client: %w", err)
+	}
+	k8sClient, err := kubernetes.NewForConfigAndClient(restConfig, httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("cannot build kube client: %w", err)
+	}
+	node, err := k8sClient.CoreV1().Nodes().Get(ctx, options.NodeName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("node is not registered")
+		}
+		return nil, err
+	}
+	return node, nil
+}
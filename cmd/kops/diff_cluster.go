@@ -0,0 +1,220 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/client/simple"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/diff"
+	"k8s.io/kops/pkg/kopscodecs"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	diffClusterLong = templates.LongDesc(i18n.T(`
+	Show the differences in the cluster and instance group specs between two revisions of a cluster.
+
+	A revision is recorded each time the cluster or one of its instance groups is changed.
+	Use ` + "`kops get cluster --history`" + ` to list the revisions.`))
+
+	diffClusterExample = templates.Examples(i18n.T(`
+	# Show the changes between revision 3 and revision 5
+	kops diff cluster k8s-cluster.example.com --from rev3 --to rev5
+
+	# Show the changes since revision 3
+	kops diff cluster k8s-cluster.example.com --from rev3
+	`))
+
+	diffClusterShort = i18n.T(`Show the differences between revisions of a cluster.`)
+)
+
+type DiffClusterOptions struct {
+	ClusterName string
+	// From is the revision to compare from
+	From string
+	// To is the revision to compare to; if empty, the current state is used
+	To string
+}
+
+func NewCmdDiffCluster(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &DiffClusterOptions{}
+
+	cmd := &cobra.Command{
+		Use:               "cluster [CLUSTER]",
+		Short:             diffClusterShort,
+		Long:              diffClusterLong,
+		Example:           diffClusterExample,
+		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
+		ValidArgsFunction: commandutils.CompleteClusterName(f, true, false),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunDiffCluster(cmd.Context(), f, out, options)
+		},
+	}
+
+	cmd.Flags().StringVar(&options.From, "from", options.From, "Revision to compare from, such as rev3")
+	cmd.Flags().StringVar(&options.To, "to", options.To, "Revision to compare to, such as rev5; defaults to the current state")
+	cmd.MarkFlagRequired("from")
+
+	return cmd
+}
+
+func RunDiffCluster(ctx context.Context, f *util.Factory, out io.Writer, options *DiffClusterOptions) error {
+	clientset, err := f.KopsClient()
+	if err != nil {
+		return err
+	}
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+	history := clientset.HistoryFor(cluster)
+
+	from, err := getClusterRevision(ctx, history, options.From)
+	if err != nil {
+		return err
+	}
+	fromObjects, err := clusterObjectsYAML(from.Cluster, from.InstanceGroups)
+	if err != nil {
+		return err
+	}
+
+	var toObjects map[string]string
+	if options.To == "" {
+		toObjects, err = currentClusterObjectsYAML(ctx, clientset, cluster)
+	} else {
+		var to *simple.ClusterRevision
+		to, err = getClusterRevision(ctx, history, options.To)
+		if err == nil {
+			toObjects, err = clusterObjectsYAML(to.Cluster, to.InstanceGroups)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	return writeClusterObjectsDiff(out, fromObjects, toObjects)
+}
+
+// parseRevision parses a revision number, optionally prefixed with "rev"
+func parseRevision(s string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimPrefix(s, "rev"))
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid revision %q, expected a revision such as rev3", s)
+	}
+	return n, nil
+}
+
+func getClusterRevision(ctx context.Context, history simple.ClusterHistoryClient, s string) (*simple.ClusterRevision, error) {
+	revision, err := parseRevision(s)
+	if err != nil {
+		return nil, err
+	}
+	return history.Get(ctx, revision)
+}
+
+// currentClusterObjectsYAML returns the versioned YAML of the current cluster and instance groups
+func currentClusterObjectsYAML(ctx context.Context, clientset simple.Clientset, cluster *kops.Cluster) (map[string]string, error) {
+	list, err := clientset.InstanceGroupsFor(cluster).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var instanceGroups []*kops.InstanceGroup
+	for i := range list.Items {
+		instanceGroups = append(instanceGroups, &list.Items[i])
+	}
+	return clusterObjectsYAML(cluster, instanceGroups)
+}
+
+// clusterObjectsYAML returns the versioned YAML of the cluster and instance groups, keyed by kind and name
+func clusterObjectsYAML(cluster *kops.Cluster, instanceGroups []*kops.InstanceGroup) (map[string]string, error) {
+	objects := make(map[string]string)
+	b, err := kopscodecs.ToVersionedYaml(cluster)
+	if err != nil {
+		return nil, fmt.Errorf("error serializing cluster: %w", err)
+	}
+	objects["Cluster/"+cluster.Name] = string(b)
+	for _, ig := range instanceGroups {
+		// The cluster label is added when reading instance groups, it is not recorded in revisions
+		ig = ig.DeepCopy()
+		delete(ig.Labels, kops.LabelClusterName)
+		if len(ig.Labels) == 0 {
+			ig.Labels = nil
+		}
+		b, err := kopscodecs.ToVersionedYaml(ig)
+		if err != nil {
+			return nil, fmt.Errorf("error serializing instance group %q: %w", ig.Name, err)
+		}
+		objects["InstanceGroup/"+ig.Name] = string(b)
+	}
+	return objects, nil
+}
+
+// changedClusterObjects returns the keys of the objects that differ, prefixed with "+" if added and "-" if removed
+func changedClusterObjects(from, to map[string]string) []string {
+	keys := make(map[string]bool)
+	for k := range from {
+		keys[k] = true
+	}
+	for k := range to {
+		keys[k] = true
+	}
+
+	var changed []string
+	for k := range keys {
+		fromYAML, inFrom := from[k]
+		toYAML, inTo := to[k]
+		switch {
+		case !inFrom:
+			changed = append(changed, "+"+k)
+		case !inTo:
+			changed = append(changed, "-"+k)
+		case fromYAML != toYAML:
+			changed = append(changed, k)
+		}
+	}
+	sort.Slice(changed, func(i, j int) bool {
+		return strings.TrimLeft(changed[i], "+-") < strings.TrimLeft(changed[j], "+-")
+	})
+	return changed
+}
+
+func writeClusterObjectsDiff(out io.Writer, from, to map[string]string) error {
+	changed := changedClusterObjects(from, to)
+	if len(changed) == 0 {
+		_, err := fmt.Fprintf(out, "No changes\n")
+		return err
+	}
+	for _, k := range changed {
+		key := strings.TrimLeft(k, "+-")
+		if _, err := fmt.Fprintf(out, "%s:\n%s\n", key, diff.FormatDiff(from[key], to[key])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseRevision(t *testing.T) {
+	grid := []struct {
+		Input    string
+		Expected int
+		Error    bool
+	}{
+		{Input: "rev3", Expected: 3},
+		{Input: "3", Expected: 3},
+		{Input: "rev12", Expected: 12},
+		{Input: "rev0", Error: true},
+		{Input: "-1", Error: true},
+		{Input: "rev", Error: true},
+		{Input: "latest", Error: true},
+	}
+	for _, g := range grid {
+		t.Run(g.Input, func(t *testing.T) {
+			revision, err := parseRevision(g.Input)
+			if g.Error {
+				if err == nil {
+					t.Fatalf("expected error, got %d", revision)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if revision != g.Expected {
+				t.Errorf("expected %d, got %d", g.Expected, revision)
+			}
+		})
+	}
+}
+
+func TestChangedClusterObjects(t *testing.T) {
+	from := map[string]string{
+		"Cluster/c":             "a",
+		"InstanceGroup/nodes":   "b",
+		"InstanceGroup/removed": "c",
+	}
+	to := map[string]string{
+		"Cluster/c":           "changed",
+		"InstanceGroup/added": "d",
+		"InstanceGroup/nodes": "b",
+	}
+	expected := []string{"Cluster/c", "+InstanceGroup/added", "-InstanceGroup/removed"}
+	if actual := changedClusterObjects(from, to); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+	if actual := changedClusterObjects(from, from); len(actual) != 0 {
+		t.Errorf("expected no changes, got %v", actual)
+	}
+}
@@ -48,6 +48,9 @@ var (
 
 	# export using the internal DNS name, bypassing the cloud load balancer
 	kops export kubeconfig k8s-cluster.example.com --internal
+
+	# export a user that logs in to the cluster's OIDC issuer using the device flow
+	kops export kubeconfig k8s-cluster.example.com --oidc
 	`))
 
 	exportKubeconfigShort = i18n.T(`Export kubeconfig.`)
@@ -73,6 +76,9 @@ func NewCmdExportKubeconfig(f *util.Factory, out io.Writer) *cobra.Command {
 			if options.Admin != 0 && options.User != "" {
 				return fmt.Errorf("cannot use both --admin and --user")
 			}
+			if options.UseOIDCDeviceFlow && (options.Admin != 0 || options.User != "" || options.UseKopsAuthenticationPlugin) {
+				return fmt.Errorf("cannot use --oidc with --admin, --user or --auth-plugin")
+			}
 			if options.all {
 				if len(args) != 0 {
 					return fmt.Errorf("cannot use both --all flag and positional arguments")
@@ -97,6 +103,7 @@ func NewCmdExportKubeconfig(f *util.Factory, out io.Writer) *cobra.Command {
 
 	cmd.Flags().BoolVar(&options.Internal, "internal", options.Internal, "Use the cluster's internal DNS name")
 	cmd.Flags().BoolVar(&options.UseKopsAuthenticationPlugin, "auth-plugin", options.UseKopsAuthenticationPlugin, "Use the kOps authentication plugin")
+	cmd.Flags().BoolVar(&options.UseOIDCDeviceFlow, "oidc", options.UseOIDCDeviceFlow, "Use a user that logs in to the cluster's OIDC issuer with the device flow")
 
 	options.CreateKubecfgOptions.AddFlagsForExport(cmd.Flags())
 
@@ -0,0 +1,512 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/kubeconfig"
+	"k8s.io/kops/pkg/model/components"
+	"k8s.io/kops/pkg/nodelabels"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/tables"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	getCertificatesLong = templates.LongDesc(i18n.T(`
+	List the certificates managed by kops.
+
+	This includes the keypairs in the keystore, and the certificates that are issued
+	from them to the nodes: the kube-apiserver, kubelet, aggregator and kops-controller
+	certificates and the certificates issued by etcd-manager.
+
+	Certificates issued to nodes are not stored in the state store. Their expiry is
+	estimated from the age of the oldest node that holds them, which requires access
+	to the Kubernetes API, and is never later than the expiry of the signing keypair.
+
+	With --warn-within, the command fails if any trusted certificate expires within the
+	given period, which is useful for alerting from CI.`))
+
+	getCertificatesExample = templates.Examples(i18n.T(`
+	# List the certificates of a cluster.
+	kops get certificates --name k8s-cluster.example.com
+
+	# Fail if any certificate expires within the next 30 days.
+	kops get certificates --name k8s-cluster.example.com --warn-within 30d`))
+
+	getCertificatesShort = i18n.T(`Get the certificates managed by kops and their expiry.`)
+)
+
+const (
+	// certificateStatusPrimary is the status of the primary keypair of a keyset.
+	certificateStatusPrimary = "primary"
+	// certificateStatusSecondary is the status of a trusted keypair that is not the primary.
+	certificateStatusSecondary = "secondary"
+	// certificateStatusDistrusted is the status of a distrusted keypair.
+	certificateStatusDistrusted = "distrusted"
+	// certificateStatusIssued is the status of a certificate issued from a keypair to the nodes.
+	certificateStatusIssued = "issued"
+)
+
+// nodeIssuedCertificateValidity is the minimum validity of the certificates issued to nodes when they boot.
+const nodeIssuedCertificateValidity = 455 * 24 * time.Hour
+
+type GetCertificatesOptions struct {
+	*GetOptions
+	kubeconfig.CreateKubecfgOptions
+
+	// WarnWithin makes the command fail if any trusted certificate expires within this period.
+	WarnWithin string
+}
+
+func NewCmdGetCertificates(f *util.Factory, out io.Writer, getOptions *GetOptions) *cobra.Command {
+	options := &GetCertificatesOptions{
+		GetOptions: getOptions,
+	}
+	cmd := &cobra.Command{
+		Use:               "certificates [CLUSTER]",
+		Aliases:           []string{"certificate", "certs"},
+		Short:             getCertificatesShort,
+		Long:              getCertificatesLong,
+		Example:           getCertificatesExample,
+		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
+		ValidArgsFunction: commandutils.CompleteClusterName(f, true, false),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunGetCertificates(cmd.Context(), f, out, options)
+		},
+	}
+
+	cmd.Flags().StringVar(&options.WarnWithin, "warn-within", options.WarnWithin, "Fail if any trusted certificate expires within this period, for example 30d or 720h")
+	options.CreateKubecfgOptions.AddCommonFlags(cmd.Flags())
+
+	return cmd
+}
+
+type certificateItem struct {
+	Name           string     `json:"name"`
+	ID             string     `json:"id,omitempty"`
+	Status         string     `json:"status"`
+	Subject        string     `json:"subject,omitempty"`
+	Issuer         string     `json:"issuer,omitempty"`
+	Signer         string     `json:"signer,omitempty"`
+	AlternateNames []string   `json:"alternateNames,omitempty"`
+	NotAfter       *time.Time `json:"notAfter,omitempty"`
+	// Estimated is set when NotAfter is an estimate rather than read from the certificate.
+	Estimated bool `json:"estimated,omitempty"`
+}
+
+// derivedCertificate describes a certificate that is issued from a keypair in the keystore.
+type derivedCertificate struct {
+	// Name is the name of the certificate.
+	Name string
+	// Signer is the name of the keyset that signs the certificate.
+	Signer string
+	// Subject is the subject of the certificate.
+	Subject string
+	// AllNodes is set for certificates that are issued to every node, rather than only to control-plane nodes.
+	AllNodes bool
+	// IssuedByEtcdManager is set for certificates that etcd-manager issues itself, with a validity kops does not control.
+	IssuedByEtcdManager bool
+	// AlternateNames are the alternate names that are known from the cluster spec.
+	AlternateNames []string
+}
+
+// derivedCertificates returns the certificates that are issued from the keystore to the nodes of the cluster.
+func derivedCertificates(cluster *kops.Cluster) []derivedCertificate {
+	clusterDNSDomain := cluster.Spec.ClusterDNSDomain
+	if clusterDNSDomain == "" {
+		clusterDNSDomain = "cluster.local"
+	}
+
+	var apiserverNames []string
+	apiserverNames = append(apiserverNames, "kubernetes", "kubernetes.default", "kubernetes.default.svc", "kubernetes.default.svc."+clusterDNSDomain)
+	if cluster.Spec.API.PublicName != "" {
+		apiserverNames = append(apiserverNames, cluster.Spec.API.PublicName)
+	}
+	apiserverNames = append(apiserverNames, cluster.APIInternalName())
+	apiserverNames = append(apiserverNames, cluster.Spec.API.AdditionalSANs...)
+	if ip, err := components.WellKnownServiceIP(&cluster.Spec.Networking, 1); err == nil {
+		apiserverNames = append(apiserverNames, ip.String())
+	}
+	apiserverNames = append(apiserverNames, "127.0.0.1")
+
+	certs := []derivedCertificate{
+		{
+			Name:           "master",
+			Signer:         fi.CertificateIDCA,
+			Subject:        "CN=kubernetes-master",
+			AlternateNames: apiserverNames,
+		},
+		{
+			Name:    "kubelet-api",
+			Signer:  fi.CertificateIDCA,
+			Subject: "CN=kubelet-api",
+		},
+		{
+			Name:    "apiserver-aggregator",
+			Signer:  "apiserver-aggregator-ca",
+			Subject: "CN=aggregator",
+		},
+		{
+			Name:    "etcd-client",
+			Signer:  "etcd-clients-ca",
+			Subject: "CN=kube-apiserver",
+		},
+		{
+			Name:           "kops-controller",
+			Signer:         fi.CertificateIDCA,
+			Subject:        "CN=kops-controller",
+			AlternateNames: []string{"kops-controller.internal." + cluster.ObjectMeta.Name},
+		},
+		{
+			Name:           "kube-controller-manager-server",
+			Signer:         fi.CertificateIDCA,
+			Subject:        "CN=kube-controller-manager",
+			AlternateNames: []string{"kube-controller-manager.kube-system.svc." + clusterDNSDomain},
+		},
+		{
+			Name:           "kube-scheduler-server",
+			Signer:         fi.CertificateIDCA,
+			Subject:        "CN=kube-scheduler",
+			AlternateNames: []string{"kube-scheduler.kube-system.svc." + clusterDNSDomain},
+		},
+		{
+			Name:     "kubelet",
+			Signer:   fi.CertificateIDCA,
+			Subject:  "CN=system:node:<node>",
+			AllNodes: true,
+		},
+		{
+			Name:     "kubelet-server",
+			Signer:   fi.CertificateIDCA,
+			Subject:  "CN=<node>",
+			AllNodes: true,
+		},
+	}
+
+	for _, etcdCluster := range cluster.Spec.EtcdClusters {
+		certs = append(certs,
+			derivedCertificate{
+				Name:                "etcd-manager-" + etcdCluster.Name,
+				Signer:              "etcd-manager-ca-" + etcdCluster.Name,
+				IssuedByEtcdManager: true,
+			},
+			derivedCertificate{
+				Name:                "etcd-peers-" + etcdCluster.Name,
+				Signer:              "etcd-peers-ca-" + etcdCluster.Name,
+				IssuedByEtcdManager: true,
+			},
+			derivedCertificate{
+				Name:                "etcd-clients-" + etcdCluster.Name,
+				Signer:              "etcd-clients-ca",
+				IssuedByEtcdManager: true,
+			},
+		)
+	}
+
+	return certs
+}
+
+// listCertificates returns the keypairs in the keysets, followed by the certificates derived from them.
+// nodes may be nil if the Kubernetes API is not available, in which case the expiry of derived certificates
+// is bounded only by the expiry of their signer.
+func listCertificates(cluster *kops.Cluster, keysets map[string]*fi.Keyset, nodes []v1.Node) []*certificateItem {
+	var items []*certificateItem
+
+	var keysetNames []string
+	for name := range keysets {
+		keysetNames = append(keysetNames, name)
+	}
+	sort.Strings(keysetNames)
+
+	for _, name := range keysetNames {
+		keyset := keysets[name]
+		var ids []string
+		for id := range keyset.Items {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+
+		for _, id := range ids {
+			keysetItem := keyset.Items[id]
+			item := &certificateItem{
+				Name:   name,
+				ID:     id,
+				Status: certificateStatusSecondary,
+			}
+			if keysetItem.DistrustTimestamp != nil {
+				item.Status = certificateStatusDistrusted
+			} else if keyset.Primary != nil && keyset.Primary.Id == id {
+				item.Status = certificateStatusPrimary
+			}
+			if cert := keysetItem.Certificate; cert != nil {
+				item.Subject = cert.Subject.String()
+				item.Issuer = cert.Certificate.Issuer.String()
+				item.AlternateNames = certificateAlternateNames(cert.Certificate.DNSNames, cert.Certificate.EmailAddresses, cert.Certificate.IPAddresses)
+				t := cert.Certificate.NotAfter.UTC()
+				item.NotAfter = &t
+			}
+			items = append(items, item)
+		}
+	}
+
+	// The oldest node in each role bounds the expiry of the certificates issued when the nodes booted.
+	var oldestNode, oldestControlPlaneNode *time.Time
+	for i := range nodes {
+		created := nodes[i].CreationTimestamp.UTC()
+		if oldestNode == nil || created.Before(*oldestNode) {
+			oldestNode = &created
+		}
+		if _, ok := nodes[i].Labels[nodelabels.RoleLabelControlPlane20]; ok {
+			if oldestControlPlaneNode == nil || created.Before(*oldestControlPlaneNode) {
+				oldestControlPlaneNode = &created
+			}
+		}
+	}
+
+	for _, derived := range derivedCertificates(cluster) {
+		keyset := keysets[derived.Signer]
+		if keyset == nil || keyset.Primary == nil || keyset.Primary.Certificate == nil {
+			continue
+		}
+		signer := keyset.Primary.Certificate
+
+		item := &certificateItem{
+			Name:           derived.Name,
+			ID:             keyset.Primary.Id,
+			Status:         certificateStatusIssued,
+			Subject:        derived.Subject,
+			Issuer:         signer.Subject.String(),
+			Signer:         derived.Signer,
+			AlternateNames: derived.AlternateNames,
+		}
+		notAfter := signer.Certificate.NotAfter.UTC()
+		item.NotAfter = &notAfter
+
+		if !derived.IssuedByEtcdManager {
+			oldest := oldestControlPlaneNode
+			if derived.AllNodes {
+				oldest = oldestNode
+			}
+			if oldest != nil {
+				if estimate := oldest.Add(nodeIssuedCertificateValidity); estimate.Before(notAfter) {
+					item.NotAfter = &estimate
+					item.Estimated = true
+				}
+			}
+		}
+
+		items = append(items, item)
+	}
+
+	return items
+}
+
+// certificateAlternateNames returns the sorted alternate names of a certificate.
+func certificateAlternateNames(dnsNames []string, emailAddresses []string, ips []net.IP) []string {
+	var alternateNames []string
+	alternateNames = append(alternateNames, dnsNames...)
+	alternateNames = append(alternateNames, emailAddresses...)
+	for _, ip := range ips {
+		alternateNames = append(alternateNames, ip.String())
+	}
+	sort.Strings(alternateNames)
+	return alternateNames
+}
+
+// parseWarnWithin parses a period such as "30d" or "720h".
+func parseWarnWithin(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid period %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid period %q", s)
+	}
+	return d, nil
+}
+
+// expiringCertificates returns the trusted certificates that expire before the deadline.
+func expiringCertificates(items []*certificateItem, deadline time.Time) []*certificateItem {
+	var expiring []*certificateItem
+	for _, item := range items {
+		if item.Status == certificateStatusDistrusted || item.NotAfter == nil {
+			continue
+		}
+		if item.NotAfter.Before(deadline) {
+			expiring = append(expiring, item)
+		}
+	}
+	return expiring
+}
+
+func RunGetCertificates(ctx context.Context, f *util.Factory, out io.Writer, options *GetCertificatesOptions) error {
+	var warnWithin time.Duration
+	if options.WarnWithin != "" {
+		d, err := parseWarnWithin(options.WarnWithin)
+		if err != nil {
+			return fmt.Errorf("parsing --warn-within: %w", err)
+		}
+		warnWithin = d
+	}
+
+	clientset, err := f.KopsClient()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := clientset.GetCluster(ctx, options.ClusterName)
+	if err != nil {
+		return err
+	}
+	if cluster == nil {
+		return fmt.Errorf("cluster not found %q", options.ClusterName)
+	}
+
+	keyStore, err := clientset.KeyStore(cluster)
+	if err != nil {
+		return err
+	}
+
+	keysets, err := keyStore.ListKeysets()
+	if err != nil {
+		return fmt.Errorf("error listing Keysets: %v", err)
+	}
+
+	items := listCertificates(cluster, keysets, listNodesForCertificates(ctx, f, cluster, options))
+	if len(items) == 0 {
+		return fmt.Errorf("no certificates found")
+	}
+
+	switch options.Output {
+	case OutputTable:
+		t := &tables.Table{}
+		t.AddColumn("NAME", func(i *certificateItem) string {
+			return i.Name
+		})
+		t.AddColumn("ID", func(i *certificateItem) string {
+			return i.ID
+		})
+		t.AddColumn("ISSUER", func(i *certificateItem) string {
+			return i.Issuer
+		})
+		t.AddColumn("ALTERNATE-NAMES", func(i *certificateItem) string {
+			return strings.Join(i.AlternateNames, ",")
+		})
+		t.AddColumn("EXPIRES", func(i *certificateItem) string {
+			if i.NotAfter == nil {
+				return ""
+			}
+			s := i.NotAfter.Local().Format("2006-01-02")
+			if i.Estimated {
+				s = "~" + s
+			}
+			return s
+		})
+		t.AddColumn("STATUS", func(i *certificateItem) string {
+			return i.Status
+		})
+		if err := t.Render(items, out, "NAME", "ID", "ISSUER", "ALTERNATE-NAMES", "EXPIRES", "STATUS"); err != nil {
+			return err
+		}
+
+	case OutputYaml:
+		y, err := yaml.Marshal(items)
+		if err != nil {
+			return fmt.Errorf("unable to marshal YAML: %v", err)
+		}
+		if _, err := out.Write(y); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+	case OutputJSON:
+		j, err := json.Marshal(items)
+		if err != nil {
+			return fmt.Errorf("unable to marshal JSON: %v", err)
+		}
+		if _, err := out.Write(j); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+
+	default:
+		return fmt.Errorf("unknown output format: %q", options.Output)
+	}
+
+	if options.WarnWithin != "" {
+		if expiring := expiringCertificates(items, time.Now().Add(warnWithin)); len(expiring) != 0 {
+			names := sets.New[string]()
+			for _, item := range expiring {
+				names.Insert(item.Name)
+			}
+			return fmt.Errorf("%d certificates expire within %s: %s", len(expiring), options.WarnWithin, strings.Join(sets.List(names), ", "))
+		}
+	}
+
+	return nil
+}
+
+// listNodesForCertificates lists the nodes of the cluster, to estimate the expiry of the certificates issued to them.
+// It returns nil if the Kubernetes API is not available.
+func listNodesForCertificates(ctx context.Context, f *util.Factory, cluster *kops.Cluster, options *GetCertificatesOptions) []v1.Node {
+	restConfig, err := f.RESTConfig(ctx, cluster, options.CreateKubecfgOptions)
+	if err != nil {
+		klog.Warningf("cannot estimate the expiry of node certificates, Kubernetes API unavailable: %v", err)
+		return nil
+	}
+
+	httpClient, err := f.HTTPClient(restConfig)
+	if err != nil {
+		klog.Warningf("cannot estimate the expiry of node certificates, Kubernetes API unavailable: %v", err)
+		return nil
+	}
+
+	k8sClient, err := kubernetes.NewForConfigAndClient(restConfig, httpClient)
+	if err != nil {
+		klog.Warningf("cannot estimate the expiry of node certificates, Kubernetes API unavailable: %v", err)
+		return nil
+	}
+
+	nodeList, err := k8sClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		klog.Warningf("cannot estimate the expiry of node certificates, Kubernetes API unavailable: %v", err)
+		return nil
+	}
+	return nodeList.Items
+}
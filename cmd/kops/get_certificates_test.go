@@ -0,0 +1,201 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"crypto/x509/pkix"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/nodelabels"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func TestParseWarnWithin(t *testing.T) {
+	grid := []struct {
+		Input    string
+		Expected time.Duration
+		Error    bool
+	}{
+		{Input: "30d", Expected: 30 * 24 * time.Hour},
+		{Input: "0d", Expected: 0},
+		{Input: "720h", Expected: 720 * time.Hour},
+		{Input: "1h30m", Expected: 90 * time.Minute},
+		{Input: "d", Error: true},
+		{Input: "-1d", Error: true},
+		{Input: "-5h", Error: true},
+		{Input: "30", Error: true},
+	}
+	for _, g := range grid {
+		t.Run(g.Input, func(t *testing.T) {
+			d, err := parseWarnWithin(g.Input)
+			if g.Error {
+				if err == nil {
+					t.Fatalf("expected error, got %v", d)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if d != g.Expected {
+				t.Errorf("expected %v, got %v", g.Expected, d)
+			}
+		})
+	}
+}
+
+func newTestCertificatesKeyset(t *testing.T, name string, validity time.Duration) *fi.Keyset {
+	cert, key, _, err := pki.IssueCert(context.Background(), &pki.IssueCertRequest{
+		Type:     "ca",
+		Subject:  pkix.Name{CommonName: name},
+		Validity: validity,
+	}, nil)
+	if err != nil {
+		t.Fatalf("issuing %s: %v", name, err)
+	}
+	keyset, err := fi.NewKeyset(cert, key)
+	if err != nil {
+		t.Fatalf("building keyset %s: %v", name, err)
+	}
+	return keyset
+}
+
+func findCertificateItem(items []*certificateItem, name string) *certificateItem {
+	for _, item := range items {
+		if item.Name == name {
+			return item
+		}
+	}
+	return nil
+}
+
+func TestListCertificates(t *testing.T) {
+	cluster := &kops.Cluster{}
+	cluster.ObjectMeta.Name = "minimal.example.com"
+	cluster.Spec.Networking.ServiceClusterIPRange = "100.64.0.0/13"
+	cluster.Spec.API.PublicName = "api.minimal.example.com"
+	cluster.Spec.EtcdClusters = []kops.EtcdClusterSpec{{Name: "main"}}
+
+	kubernetesCA := newTestCertificatesKeyset(t, fi.CertificateIDCA, 10*365*24*time.Hour)
+	distrustedCert, distrustedKey, _, err := pki.IssueCert(context.Background(), &pki.IssueCertRequest{
+		Type:     "ca",
+		Subject:  pkix.Name{CommonName: fi.CertificateIDCA},
+		Validity: time.Hour,
+	}, nil)
+	if err != nil {
+		t.Fatalf("issuing CA: %v", err)
+	}
+	distrusted, err := kubernetesCA.AddItem(distrustedCert, distrustedKey, false)
+	if err != nil {
+		t.Fatalf("adding item: %v", err)
+	}
+	distrusted.DistrustTimestamp = fi.PtrTo(time.Now())
+
+	keysets := map[string]*fi.Keyset{
+		fi.CertificateIDCA:        kubernetesCA,
+		"etcd-manager-ca-main":    newTestCertificatesKeyset(t, "etcd-manager-ca-main", 24*time.Hour),
+		"apiserver-aggregator-ca": newTestCertificatesKeyset(t, "apiserver-aggregator-ca", 10*365*24*time.Hour),
+	}
+
+	controlPlaneCreated := time.Now().Add(-400 * 24 * time.Hour).Truncate(time.Second)
+	nodeCreated := time.Now().Add(-450 * 24 * time.Hour).Truncate(time.Second)
+	nodes := []v1.Node{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "control-plane",
+				CreationTimestamp: metav1.NewTime(controlPlaneCreated),
+				Labels:            map[string]string{nodelabels.RoleLabelControlPlane20: ""},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "node",
+				CreationTimestamp: metav1.NewTime(nodeCreated),
+			},
+		},
+	}
+
+	items := listCertificates(cluster, keysets, nodes)
+
+	statuses := map[string]int{}
+	for _, item := range items {
+		if item.Name == fi.CertificateIDCA {
+			statuses[item.Status]++
+		}
+	}
+	if statuses[certificateStatusPrimary] != 1 || statuses[certificateStatusDistrusted] != 1 {
+		t.Errorf("unexpected statuses for %s: %v", fi.CertificateIDCA, statuses)
+	}
+
+	master := findCertificateItem(items, "master")
+	if master == nil {
+		t.Fatalf("master certificate not listed")
+	}
+	if !master.Estimated || !master.NotAfter.Equal(controlPlaneCreated.Add(nodeIssuedCertificateValidity)) {
+		t.Errorf("unexpected expiry for master: %v (estimated %v)", master.NotAfter, master.Estimated)
+	}
+	expectedNames := map[string]bool{"api.minimal.example.com": false, "api.internal.minimal.example.com": false, "100.64.0.1": false}
+	for _, name := range master.AlternateNames {
+		if _, ok := expectedNames[name]; ok {
+			expectedNames[name] = true
+		}
+	}
+	for name, found := range expectedNames {
+		if !found {
+			t.Errorf("expected alternate name %q for master, got %v", name, master.AlternateNames)
+		}
+	}
+
+	kubelet := findCertificateItem(items, "kubelet")
+	if kubelet == nil || !kubelet.NotAfter.Equal(nodeCreated.Add(nodeIssuedCertificateValidity)) {
+		t.Errorf("unexpected kubelet certificate: %+v", kubelet)
+	}
+
+	etcdManager := findCertificateItem(items, "etcd-manager-main")
+	if etcdManager == nil || etcdManager.Estimated || !etcdManager.NotAfter.Equal(keysets["etcd-manager-ca-main"].Primary.Certificate.Certificate.NotAfter) {
+		t.Errorf("expected etcd-manager-main to be bounded by its signer, got %+v", etcdManager)
+	}
+
+	if findCertificateItem(items, "etcd-client") != nil {
+		t.Errorf("expected no etcd-client certificate without the etcd-clients-ca keyset")
+	}
+
+	expiring := expiringCertificates(items, time.Now().Add(30*24*time.Hour))
+	var expiringNames []string
+	for _, item := range expiring {
+		expiringNames = append(expiringNames, item.Name)
+	}
+	for _, item := range expiring {
+		if item.Status == certificateStatusDistrusted {
+			t.Errorf("distrusted certificates should not be reported as expiring")
+		}
+	}
+	for _, name := range []string{"etcd-manager-ca-main", "etcd-manager-main", "kubelet", "kubelet-server"} {
+		if findCertificateItem(expiring, name) == nil {
+			t.Errorf("expected %s to be expiring, got %v", name, expiringNames)
+		}
+	}
+	if findCertificateItem(expiring, "master") != nil {
+		t.Errorf("did not expect master to be expiring, got %v", expiringNames)
+	}
+}
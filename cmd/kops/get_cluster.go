@@ -18,9 +18,11 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -30,12 +32,14 @@ import (
 	"k8s.io/kops/cmd/kops/util"
 	kopsapi "k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/apis/kops/registry"
+	"k8s.io/kops/pkg/client/simple"
 	"k8s.io/kops/pkg/commands/commandutils"
 	"k8s.io/kops/pkg/kopscodecs"
 	"k8s.io/kops/util/pkg/tables"
 	"k8s.io/kops/util/pkg/vfs"
 	"k8s.io/kubectl/pkg/util/i18n"
 	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
 )
 
 var (
@@ -54,6 +58,9 @@ var (
 
 	# Save a cluster desired configuration to YAML file
 	kops get cluster k8s-cluster.example.com -o yaml > cluster-desired-config.yaml
+
+	# List the revisions of a cluster's spec
+	kops get cluster k8s-cluster.example.com --history
 	`))
 
 	getClusterShort = i18n.T(`Get one or many clusters.`)
@@ -80,6 +87,9 @@ type GetClusterOptions struct {
 	// FullSpec determines if we should output the completed (fully populated) spec
 	FullSpec bool
 
+	// History determines if we should list the recorded revisions of the cluster instead
+	History bool
+
 	// ClusterNames is a list of cluster names to show; if not specified all clusters will be shown
 	ClusterNames []string
 }
@@ -114,6 +124,8 @@ func NewCmdGetCluster(f *util.Factory, out io.Writer, getOptions *GetOptions) *c
 	}
 
 	cmd.Flags().BoolVar(&options.FullSpec, "full", options.FullSpec, "Show fully populated configuration")
+	cmd.Flags().BoolVar(&options.History, "history", options.History, "List the recorded revisions of the cluster and instance group specs")
+	cmd.MarkFlagsMutuallyExclusive("full", "history")
 
 	return cmd
 }
@@ -124,6 +136,13 @@ func RunGetClusters(ctx context.Context, f commandutils.Factory, out io.Writer,
 		return err
 	}
 
+	if options.History {
+		if len(options.ClusterNames) != 1 {
+			return fmt.Errorf("--history requires a single cluster")
+		}
+		return runGetClusterHistory(ctx, client, out, options)
+	}
+
 	singleClusterSelected := false
 	var clusterList []*kopsapi.Cluster
 	if len(options.ClusterNames) == 1 {
@@ -234,6 +253,87 @@ func clusterOutputTable(clusters []*kopsapi.Cluster, out io.Writer) error {
 	return t.Render(clusters, out, "NAME", "CLOUD", "ZONES")
 }
 
+// clusterRevisionItem is a recorded revision of a cluster, as listed by --history
+type clusterRevisionItem struct {
+	Revision    int       `json:"revision"`
+	Timestamp   time.Time `json:"timestamp"`
+	Author      string    `json:"author"`
+	KopsVersion string    `json:"kopsVersion"`
+	// Changes are the objects changed from the previous revision
+	Changes []string `json:"changes,omitempty"`
+}
+
+func runGetClusterHistory(ctx context.Context, client simple.Clientset, out io.Writer, options *GetClusterOptions) error {
+	cluster, err := client.GetCluster(ctx, options.ClusterNames[0])
+	if err != nil {
+		return err
+	}
+	revisions, err := client.HistoryFor(cluster).List(ctx)
+	if err != nil {
+		return err
+	}
+	if len(revisions) == 0 {
+		return fmt.Errorf("no revisions recorded for cluster %q", cluster.Name)
+	}
+
+	var items []*clusterRevisionItem
+	var previous map[string]string
+	for _, revision := range revisions {
+		objects, err := clusterObjectsYAML(revision.Cluster, revision.InstanceGroups)
+		if err != nil {
+			return fmt.Errorf("error reading revision %d: %w", revision.Revision, err)
+		}
+		items = append(items, &clusterRevisionItem{
+			Revision:    revision.Revision,
+			Timestamp:   revision.Timestamp,
+			Author:      revision.Author,
+			KopsVersion: revision.KopsVersion,
+			Changes:     changedClusterObjects(previous, objects),
+		})
+		previous = objects
+	}
+
+	switch options.Output {
+	case OutputTable:
+		t := &tables.Table{}
+		t.AddColumn("REVISION", func(i *clusterRevisionItem) string {
+			return fmt.Sprintf("rev%d", i.Revision)
+		})
+		t.AddColumn("TIME", func(i *clusterRevisionItem) string {
+			return i.Timestamp.UTC().Format(time.RFC3339)
+		})
+		t.AddColumn("AUTHOR", func(i *clusterRevisionItem) string {
+			return i.Author
+		})
+		t.AddColumn("KOPS-VERSION", func(i *clusterRevisionItem) string {
+			return i.KopsVersion
+		})
+		t.AddColumn("CHANGES", func(i *clusterRevisionItem) string {
+			return strings.Join(i.Changes, ",")
+		})
+		return t.Render(items, out, "REVISION", "TIME", "AUTHOR", "KOPS-VERSION", "CHANGES")
+	case OutputYaml:
+		y, err := yaml.Marshal(items)
+		if err != nil {
+			return fmt.Errorf("unable to marshal YAML: %v", err)
+		}
+		if _, err := out.Write(y); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+	case OutputJSON:
+		j, err := json.Marshal(items)
+		if err != nil {
+			return fmt.Errorf("unable to marshal JSON: %v", err)
+		}
+		if _, err := out.Write(j); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+	default:
+		return fmt.Errorf("unknown output format: %q", options.Output)
+	}
+	return nil
+}
+
 // fullOutputJSON outputs the marshalled JSON of a list of clusters and instance groups.  It will handle
 // nils for clusters and instanceGroups slices.
 func fullOutputJSON(out io.Writer, singleObject bool, args ...runtime.Object) error {
@@ -21,9 +21,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
 	"strings"
 
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	kopsclientset "k8s.io/kops/pkg/client/clientset_generated/clientset"
 	"k8s.io/kops/pkg/cloudinstances"
 	"k8s.io/kops/pkg/commands/commandutils"
 	"k8s.io/kops/pkg/kubeconfig"
@@ -63,6 +66,7 @@ type renderableCloudInstance struct {
 	InstanceGroup string   `json:"instanceGroup"`
 	MachineType   string   `json:"machineType"`
 	State         string   `json:"state"`
+	PodCIDRs      []string `json:"podCIDRs,omitempty"`
 }
 
 type GetInstancesOptions struct {
@@ -153,11 +157,20 @@ func RunGetInstances(ctx context.Context, f *util.Factory, out io.Writer, option
 		cg.AdjustNeedUpdate()
 	}
 
+	// On bare metal, kops-controller allocates the pod CIDRs of nodes and records them in their Host objects.
+	var hostPodCIDRs map[string][]string
+	if cluster.GetCloudProvider() == kops.CloudProviderMetal {
+		hostPodCIDRs, err = getHostPodCIDRs(ctx, restConfig, httpClient)
+		if err != nil {
+			klog.Warningf("cannot list hosts: %v", err)
+		}
+	}
+
 	switch options.Output {
 	case OutputTable:
-		return instanceOutputTable(cloudInstances, out)
+		return instanceOutputTable(cloudInstances, out, hostPodCIDRs)
 	case OutputYaml:
-		y, err := yaml.Marshal(asRenderable(cloudInstances))
+		y, err := yaml.Marshal(asRenderable(cloudInstances, hostPodCIDRs))
 		if err != nil {
 			return fmt.Errorf("unable to marshal YAML: %v", err)
 		}
@@ -166,7 +179,7 @@ func RunGetInstances(ctx context.Context, f *util.Factory, out io.Writer, option
 		}
 		return nil
 	case OutputJSON:
-		j, err := json.Marshal(asRenderable(cloudInstances))
+		j, err := json.Marshal(asRenderable(cloudInstances, hostPodCIDRs))
 		if err != nil {
 			return fmt.Errorf("unable to marshal JSON: %v", err)
 		}
@@ -179,7 +192,9 @@ func RunGetInstances(ctx context.Context, f *util.Factory, out io.Writer, option
 	}
 }
 
-func instanceOutputTable(instances []*cloudinstances.CloudInstance, out io.Writer) error {
+// instanceOutputTable renders the instances as a table.
+// If hostPodCIDRs is not nil, the pod CIDRs of each node's Host are added.
+func instanceOutputTable(instances []*cloudinstances.CloudInstance, out io.Writer, hostPodCIDRs map[string][]string) error {
 	fmt.Println("")
 	t := &tables.Table{}
 	t.AddColumn("ID", func(i *cloudinstances.CloudInstance) string {
@@ -215,11 +230,18 @@ func instanceOutputTable(instances []*cloudinstances.CloudInstance, out io.Write
 		return string(i.State)
 	})
 
+	t.AddColumn("POD-CIDRS", func(i *cloudinstances.CloudInstance) string {
+		return strings.Join(instancePodCIDRs(i, hostPodCIDRs), ", ")
+	})
+
 	columns := []string{"ID", "NODE-NAME", "STATUS", "ROLES", "STATE", "INTERNAL-IP", "EXTERNAL-IP", "INSTANCE-GROUP", "MACHINE-TYPE"}
+	if hostPodCIDRs != nil {
+		columns = append(columns, "POD-CIDRS")
+	}
 	return t.Render(instances, out, columns...)
 }
 
-func asRenderable(instances []*cloudinstances.CloudInstance) []*renderableCloudInstance {
+func asRenderable(instances []*cloudinstances.CloudInstance, hostPodCIDRs map[string][]string) []*renderableCloudInstance {
 	arr := make([]*renderableCloudInstance, len(instances))
 	for i, ci := range instances {
 		arr[i] = &renderableCloudInstance{
@@ -235,6 +257,38 @@ func asRenderable(instances []*cloudinstances.CloudInstance) []*renderableCloudI
 		if ci.Node != nil {
 			arr[i].NodeName = ci.Node.Name
 		}
+		arr[i].PodCIDRs = instancePodCIDRs(ci, hostPodCIDRs)
 	}
 	return arr
 }
+
+// getHostPodCIDRs returns the pod CIDRs of the Host objects, by name, which is the name of their node.
+// These are the pod CIDRs set by the user, or else those allocated by kops-controller.
+func getHostPodCIDRs(ctx context.Context, restConfig *rest.Config, httpClient *http.Client) (map[string][]string, error) {
+	kopsClient, err := kopsclientset.NewForConfigAndClient(restConfig, httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("building kops client: %w", err)
+	}
+	hosts, err := kopsClient.KopsV1alpha2().Hosts("kops-system").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	podCIDRs := make(map[string][]string)
+	for _, host := range hosts.Items {
+		if len(host.Spec.PodCIDRs) != 0 {
+			podCIDRs[host.Name] = host.Spec.PodCIDRs
+		} else {
+			podCIDRs[host.Name] = host.Status.PodCIDRs
+		}
+	}
+	return podCIDRs, nil
+}
+
+// instancePodCIDRs returns the pod CIDRs of the Host of the instance's node.
+func instancePodCIDRs(i *cloudinstances.CloudInstance, hostPodCIDRs map[string][]string) []string {
+	if i.Node == nil {
+		return nil
+	}
+	return hostPodCIDRs[i.Node.Name]
+}
@@ -22,7 +22,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"sort"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -142,16 +141,7 @@ func listKeypairs(keyStore fi.CAStore, names []string, includeDistrusted bool) (
 						t := cert.Certificate.NotAfter.UTC()
 						keypair.NotAfter = &t
 					}
-					{
-						var alternateNames []string
-						alternateNames = append(alternateNames, item.Certificate.Certificate.DNSNames...)
-						alternateNames = append(alternateNames, item.Certificate.Certificate.EmailAddresses...)
-						for _, ip := range item.Certificate.Certificate.IPAddresses {
-							alternateNames = append(alternateNames, ip.String())
-						}
-						sort.Strings(alternateNames)
-						keypair.AlternateNames = alternateNames
-					}
+					keypair.AlternateNames = certificateAlternateNames(cert.Certificate.DNSNames, cert.Certificate.EmailAddresses, cert.Certificate.IPAddresses)
 					if rsaKey, ok := cert.PublicKey.(*rsa.PublicKey); ok {
 						keypair.KeyLength = fi.PtrTo(rsaKey.N.BitLen())
 					}
@@ -825,6 +825,9 @@ func TestManyAddons(t *testing.T) {
 			dnsControllerAddon,
 			awsCCMAddon,
 			nodeProblemDetectorAddon,
+			"monitoring.addons.k8s.io-k8s-1.25",
+			"monitoring-servicemonitors.addons.k8s.io-k8s-1.25",
+			"gateway-api.addons.k8s.io-v1.4.0-experimental",
 		).
 		runTestTerraformAWS(t)
 }
@@ -22,6 +22,8 @@ import (
 	"os"
 
 	"k8s.io/kops"
+	// Register the providers of CA keys held in PKCS#11 modules and cloud KMS.
+	_ "k8s.io/kops/pkg/keysigner"
 )
 
 func main() {
@@ -61,8 +61,13 @@ func NewCmdReconcileCluster(f *util.Factory, out io.Writer) *cobra.Command {
 		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
 		ValidArgsFunction: commandutils.CompleteClusterName(f, true, false),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			err := RunReconcileCluster(cmd.Context(), f, out, &options.CoreUpdateClusterOptions)
-			return err
+			run := func(ctx context.Context) error {
+				return RunReconcileCluster(ctx, f, out, &options.CoreUpdateClusterOptions)
+			}
+			if options.Yes {
+				return withClusterLock(cmd.Context(), f, options.ClusterName, "reconcile cluster", run)
+			}
+			return run(cmd.Context())
 		},
 	}
 
@@ -0,0 +1,127 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	rollbackClusterLong = templates.LongDesc(i18n.T(`
+	Restore the cluster and instance group specs of a previous revision of a cluster.
+
+	Instance groups that did not exist in the revision are deleted. The rollback is recorded
+	as a new revision, so it can itself be rolled back. If the rollback is interrupted, run it
+	again to complete it; ` + "`kops update cluster`" + ` refuses to apply the specs until then.
+
+	Like ` + "`kops edit cluster`" + `, the rollback only changes the specs in the state store;
+	use ` + "`kops update cluster`" + ` and ` + "`kops rolling-update cluster`" + ` to apply them.`))
+
+	rollbackClusterExample = templates.Examples(i18n.T(`
+	# Show the changes a rollback to revision 3 would make
+	kops rollback cluster k8s-cluster.example.com --to rev3
+
+	# Roll back to revision 3
+	kops rollback cluster k8s-cluster.example.com --to rev3 --yes
+	`))
+
+	rollbackClusterShort = i18n.T(`Roll back a cluster to a previous revision.`)
+)
+
+type RollbackClusterOptions struct {
+	ClusterName string
+	// To is the revision to roll back to
+	To  string
+	Yes bool
+}
+
+func NewCmdRollbackCluster(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &RollbackClusterOptions{}
+
+	cmd := &cobra.Command{
+		Use:               "cluster [CLUSTER]",
+		Short:             rollbackClusterShort,
+		Long:              rollbackClusterLong,
+		Example:           rollbackClusterExample,
+		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
+		ValidArgsFunction: commandutils.CompleteClusterName(f, true, false),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			run := func(ctx context.Context) error {
+				return RunRollbackCluster(ctx, f, out, options)
+			}
+			if options.Yes {
+				return withClusterLock(cmd.Context(), f, options.ClusterName, "rollback cluster", run)
+			}
+			return run(cmd.Context())
+		},
+	}
+
+	cmd.Flags().StringVar(&options.To, "to", options.To, "Revision to roll back to, such as rev3")
+	cmd.MarkFlagRequired("to")
+	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Perform the rollback; without this flag, only show the changes")
+
+	return cmd
+}
+
+func RunRollbackCluster(ctx context.Context, f *util.Factory, out io.Writer, options *RollbackClusterOptions) error {
+	clientset, err := f.KopsClient()
+	if err != nil {
+		return err
+	}
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+	history := clientset.HistoryFor(cluster)
+
+	target, err := getClusterRevision(ctx, history, options.To)
+	if err != nil {
+		return err
+	}
+
+	if !options.Yes {
+		current, err := currentClusterObjectsYAML(ctx, clientset, cluster)
+		if err != nil {
+			return err
+		}
+		objects, err := clusterObjectsYAML(target.Cluster, target.InstanceGroups)
+		if err != nil {
+			return err
+		}
+		if err := writeClusterObjectsDiff(out, current, objects); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "\nMust specify --yes to roll back cluster\n")
+		return nil
+	}
+
+	revision, err := history.Rollback(ctx, target.Revision)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Rolled back cluster %q to revision %d, recorded as revision %d\n", cluster.Name, target.Revision, revision.Revision)
+	fmt.Fprintf(out, "\nApply the changes with `kops update cluster --name %s --yes`, then `kops rolling-update cluster --name %s --yes` if instances need replacing\n", cluster.Name, cluster.Name)
+	return nil
+}
@@ -185,7 +185,13 @@ func NewCmdRollingUpdateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
 		ValidArgsFunction: commandutils.CompleteClusterName(f, true, false),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return RunRollingUpdateCluster(cmd.Context(), f, out, &options)
+			run := func(ctx context.Context) error {
+				return RunRollingUpdateCluster(ctx, f, out, &options)
+			}
+			if options.Yes {
+				return withClusterLock(cmd.Context(), f, options.ClusterName, "rolling-update cluster", run)
+			}
+			return run(cmd.Context())
 		},
 	}
 
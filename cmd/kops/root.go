@@ -35,10 +35,13 @@ import (
 	"k8s.io/client-go/util/homedir"
 	"k8s.io/klog/v2"
 	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/acls"
 	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/registry"
 	"k8s.io/kops/pkg/client/simple"
 	"k8s.io/kops/pkg/commands"
 	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/statelock"
 	"k8s.io/kubectl/pkg/util/i18n"
 	"k8s.io/kubectl/pkg/util/templates"
 )
@@ -164,6 +167,7 @@ func NewCmdRoot(f *util.Factory, out io.Writer) *cobra.Command {
 	// create subcommands
 	cmd.AddCommand(NewCmdCreate(f, out))
 	cmd.AddCommand(NewCmdDelete(f, out))
+	cmd.AddCommand(NewCmdDiff(f, out))
 	cmd.AddCommand(NewCmdDistrust(f, out))
 	cmd.AddCommand(NewCmdEdit(f, out))
 	cmd.AddCommand(NewCmdExport(f, out))
@@ -173,7 +177,9 @@ func NewCmdRoot(f *util.Factory, out io.Writer) *cobra.Command {
 	cmd.AddCommand(NewCmdPromote(f, out))
 	cmd.AddCommand(NewCmdReconcile(f, out))
 	cmd.AddCommand(NewCmdReplace(f, out))
+	cmd.AddCommand(NewCmdRollback(f, out))
 	cmd.AddCommand(NewCmdRollingUpdate(f, out))
+	cmd.AddCommand(NewCmdRotate(f, out))
 	cmd.AddCommand(NewCmdToolbox(f, out))
 	cmd.AddCommand(NewCmdTrust(f, out))
 	cmd.AddCommand(NewCmdUpdate(f, out))
@@ -354,6 +360,41 @@ func GetCluster(ctx context.Context, factory commandutils.Factory, clusterName s
 	return cluster, nil
 }
 
+// withClusterLock runs a long operation while holding the advisory lock on the cluster's state store.
+// The operation's context is cancelled if the lock is lost, and the loss is returned as the error.
+func withClusterLock(ctx context.Context, factory commandutils.Factory, clusterName string, operation string, run func(ctx context.Context) error) error {
+	cluster, err := GetCluster(ctx, factory, clusterName)
+	if err != nil {
+		return err
+	}
+
+	clientset, err := factory.KopsClient()
+	if err != nil {
+		return err
+	}
+
+	configBase, err := registry.ConfigBase(clientset.VFSContext(), cluster)
+	if err != nil {
+		return err
+	}
+	lockPath := configBase.Join(registry.PathLock)
+	acl, err := acls.GetACL(ctx, lockPath, cluster)
+	if err != nil {
+		return err
+	}
+	lock, err := statelock.Acquire(ctx, lockPath, acl, operation, statelock.DefaultTTL)
+	if err != nil {
+		return err
+	}
+	defer lock.Release(ctx)
+
+	err = run(lock.Context())
+	if lost := lock.Err(); lost != nil {
+		return lost
+	}
+	return err
+}
+
 func GetClusterNameForCompletionNoKubeconfig(clusterArgs []string) (clusterName string, completions []string, directive cobra.ShellCompDirective) {
 	if len(clusterArgs) > 0 {
 		return clusterArgs[0], nil, 0
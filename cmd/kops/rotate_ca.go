@@ -0,0 +1,371 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/acls"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/registry"
+	"k8s.io/kops/pkg/carotation"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	rotateCALong = templates.LongDesc(i18n.T(`
+	Rotate the keypairs of the cluster's CAs and the service account signing key.
+
+	The rotation creates new keypairs, then updates and rolling-updates the cluster to trust them,
+	promotes them to primary, updates and rolling-updates the cluster again, and finally distrusts
+	the previous keypairs, validating the cluster between each phase.
+
+	The progress of the rotation is recorded in the state store, so the command can be run
+	again to resume the rotation after it stops. When the "kubernetes-ca" keyset is rotated, the
+	command stops when the clients of the Kubernetes API need new kubeconfig files; distribute them,
+	then run the command again to continue.
+
+	When the "service-account" keyset is rotated, the previous keypair is only distrusted an hour after
+	the new one is in use, so that the service account tokens of the pods have been refreshed.`))
+
+	rotateCAExample = templates.Examples(i18n.T(`
+	# Show the steps of a rotation of all the rotatable keysets
+	kops rotate ca --name k8s-cluster.example.com --state s3://my-state-store --dry-run
+
+	# Rotate all the rotatable keysets, or resume the rotation in progress
+	kops rotate ca --name k8s-cluster.example.com --state s3://my-state-store --yes
+
+	# Rotate only the etcd CAs
+	kops rotate ca --name k8s-cluster.example.com --state s3://my-state-store \
+		--keysets etcd-manager-ca-main,etcd-manager-ca-events --yes
+	`))
+
+	rotateCAShort = i18n.T(`Rotate the cluster's CAs and service account signing key.`)
+)
+
+type RotateCAOptions struct {
+	ClusterName string
+	// Keysets are the names of the keysets to rotate, or "all"
+	Keysets []string
+	Yes     bool
+	DryRun  bool
+	// ValidationTimeout is the timeout for the cluster to validate after each phase
+	ValidationTimeout time.Duration
+
+	// keysetsChanged is true if the keysets were specified on the command line
+	keysetsChanged bool
+}
+
+func (o *RotateCAOptions) InitDefaults() {
+	o.Keysets = []string{"all"}
+	o.Yes = false
+	o.DryRun = false
+	o.ValidationTimeout = 15 * time.Minute
+}
+
+func NewCmdRotateCA(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &RotateCAOptions{}
+	options.InitDefaults()
+
+	cmd := &cobra.Command{
+		Use:               "ca [CLUSTER]",
+		Short:             rotateCAShort,
+		Long:              rotateCALong,
+		Example:           rotateCAExample,
+		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
+		ValidArgsFunction: commandutils.CompleteClusterName(f, true, false),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.keysetsChanged = cmd.Flags().Changed("keysets")
+			run := func(ctx context.Context) error {
+				return RunRotateCA(ctx, f, out, options)
+			}
+			if options.Yes && !options.DryRun {
+				return withClusterLock(cmd.Context(), f, options.ClusterName, "rotate ca", run)
+			}
+			return run(cmd.Context())
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&options.Keysets, "keysets", options.Keysets, "Keysets to rotate, or \"all\" for all the rotatable keysets")
+	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Perform the rotation; without this flag, only show the steps")
+	cmd.Flags().BoolVar(&options.DryRun, "dry-run", options.DryRun, "Only show the remaining steps of the rotation")
+	cmd.Flags().DurationVar(&options.ValidationTimeout, "validation-timeout", options.ValidationTimeout, "Maximum time to wait for the cluster to validate after each phase")
+
+	return cmd
+}
+
+// RunRotateCA rotates the keysets, resuming the rotation in progress if there is one.
+func RunRotateCA(ctx context.Context, f *util.Factory, out io.Writer, options *RotateCAOptions) error {
+	clientset, err := f.KopsClient()
+	if err != nil {
+		return err
+	}
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+	keyStore, err := clientset.KeyStore(cluster)
+	if err != nil {
+		return err
+	}
+	configBase, err := registry.ConfigBase(clientset.VFSContext(), cluster)
+	if err != nil {
+		return err
+	}
+	statePath := configBase.Join(registry.PathCARotation)
+
+	state, err := carotation.Load(ctx, statePath)
+	if err != nil {
+		return err
+	}
+	if state == nil {
+		keysets, err := resolveRotationKeysets(keyStore, options.Keysets)
+		if err != nil {
+			return err
+		}
+		state = carotation.New(keysets, time.Now())
+		fmt.Fprintf(out, "Rotating keysets %s\n", strings.Join(state.Keysets, ", "))
+	} else {
+		if options.keysetsChanged {
+			keysets, err := resolveRotationKeysets(keyStore, options.Keysets)
+			if err != nil {
+				return err
+			}
+			if !slices.Equal(keysets, state.Keysets) {
+				return fmt.Errorf("a rotation of keysets %s is already in progress", strings.Join(state.Keysets, ", "))
+			}
+		}
+		fmt.Fprintf(out, "Resuming rotation of keysets %s, started at %s\n", strings.Join(state.Keysets, ", "), state.StartedAt.Format(time.RFC3339))
+	}
+
+	remaining := state.Remaining()
+	if options.DryRun || !options.Yes {
+		fmt.Fprintf(out, "\nRemaining steps:\n")
+		for i, step := range remaining {
+			fmt.Fprintf(out, "  %d. %s\n", i+1, carotation.Description(step))
+		}
+		if !options.DryRun {
+			fmt.Fprintf(out, "\nMust specify --yes to rotate\n")
+		}
+		return nil
+	}
+
+	acl, err := acls.GetACL(ctx, statePath, cluster)
+	if err != nil {
+		return err
+	}
+	save := func() error {
+		return carotation.Save(ctx, statePath, acl, state)
+	}
+
+	r := &caRotation{
+		f:        f,
+		out:      out,
+		options:  options,
+		cluster:  cluster,
+		keyStore: keyStore,
+		state:    state,
+		save:     save,
+	}
+
+	for i, step := range remaining {
+		fmt.Fprintf(out, "\nStep %d of %d: %s\n", len(state.Completed)+1, len(state.Completed)+len(remaining)-i, carotation.Description(step))
+		done, err := r.run(ctx, step)
+		if err != nil {
+			return fmt.Errorf("rotation step %q failed: %w; fix the problem and run \"kops rotate ca\" again to resume", step, err)
+		}
+		if !done {
+			return nil
+		}
+		state.MarkCompleted(step, time.Now())
+		if err := save(); err != nil {
+			return err
+		}
+		if carotation.IsPause(step) && i != len(remaining)-1 {
+			fmt.Fprintf(out, "\nRun \"kops rotate ca --yes\" again to continue the rotation.\n")
+			return nil
+		}
+	}
+
+	if err := statePath.Remove(ctx); err != nil {
+		return fmt.Errorf("error removing rotation state %q: %w", statePath, err)
+	}
+	fmt.Fprintf(out, "\nRotation of keysets %s is complete\n", strings.Join(state.Keysets, ", "))
+	return nil
+}
+
+// resolveRotationKeysets returns the sorted names of the keysets to rotate, expanding "all".
+func resolveRotationKeysets(keyStore fi.CAStore, names []string) ([]string, error) {
+	keysets, err := keyStore.ListKeysets()
+	if err != nil {
+		return nil, fmt.Errorf("listing keysets: %w", err)
+	}
+
+	var result []string
+	for _, name := range names {
+		if name == "all" {
+			for keyset := range keysets {
+				if rotatableKeysetFilter(keyset, nil) && !slices.Contains(result, keyset) {
+					result = append(result, keyset)
+				}
+			}
+			continue
+		}
+		if !rotatableKeysetFilter(name, nil) {
+			return nil, fmt.Errorf("rotating keyset %q is not supported", name)
+		}
+		if keysets[name] == nil {
+			return nil, fmt.Errorf("keyset %q not found", name)
+		}
+		if !slices.Contains(result, name) {
+			result = append(result, name)
+		}
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("no keysets to rotate")
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// caRotation runs the steps of a rotation.
+type caRotation struct {
+	f        *util.Factory
+	out      io.Writer
+	options  *RotateCAOptions
+	cluster  *kops.Cluster
+	keyStore fi.CAStore
+	state    *carotation.State
+	save     func() error
+}
+
+// run performs the step, returning false if the step cannot be completed yet.
+func (r *caRotation) run(ctx context.Context, step carotation.Step) (bool, error) {
+	switch step {
+	case carotation.StepCreateKeypairs:
+		for _, name := range r.state.Keysets {
+			if r.state.NewKeypairs[name] != "" {
+				continue
+			}
+			// A generated keypair would replace a key that is held in an HSM or a cloud KMS with one held by kops.
+			keyset, err := r.keyStore.FindKeyset(ctx, name)
+			if err != nil {
+				return false, fmt.Errorf("reading keyset %s: %w", name, err)
+			}
+			if keyset != nil && keyset.Primary != nil && keyset.Primary.PrivateKey != nil {
+				if ref := keyset.Primary.PrivateKey.Reference(); ref != "" {
+					return false, fmt.Errorf("the private key of %s is held in %q; add the new keypair with \"kops create keypair %s --key-uri\" and rotate it by hand", name, ref, name)
+				}
+			}
+			id, err := createKeypair(ctx, r.out, &CreateKeypairOptions{Keyset: name}, name, r.keyStore)
+			if err != nil {
+				return false, fmt.Errorf("creating keypair for %s: %w", name, err)
+			}
+			r.state.NewKeypairs[name] = id
+			if err := r.save(); err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+
+	case carotation.StepPromoteKeypairs:
+		for _, name := range r.state.Keysets {
+			if err := promoteKeypair(ctx, r.out, name, r.state.NewKeypairs[name], r.keyStore); err != nil {
+				return false, fmt.Errorf("promoting keypair for %s: %w", name, err)
+			}
+		}
+		return true, nil
+
+	case carotation.StepDistrustKeypairs:
+		if delay := r.state.DistrustDelay(time.Now()); delay > 0 {
+			fmt.Fprintf(r.out, "The service account tokens of the pods may still be signed by the previous key.\n")
+			fmt.Fprintf(r.out, "Run \"kops rotate ca --yes\" again after %s to continue the rotation.\n", time.Now().Add(delay).Format(time.RFC3339))
+			return false, nil
+		}
+		for _, name := range r.state.Keysets {
+			if err := distrustKeypair(ctx, r.out, name, nil, r.keyStore); err != nil {
+				return false, fmt.Errorf("distrusting keypairs for %s: %w", name, err)
+			}
+		}
+		return true, nil
+
+	case carotation.StepStageUpdate, carotation.StepPromoteUpdate, carotation.StepDistrustUpdate:
+		opt := &CoreUpdateClusterOptions{}
+		opt.InitDefaults()
+		opt.ClusterName = r.cluster.Name
+		opt.Yes = true
+		if _, err := RunCoreUpdateCluster(ctx, r.f, r.out, opt); err != nil {
+			return false, err
+		}
+		return true, nil
+
+	case carotation.StepStageRollingUpdate, carotation.StepPromoteRollingUpdate, carotation.StepDistrustRollingUpdate:
+		opt := &RollingUpdateOptions{}
+		opt.InitDefaults()
+		opt.ClusterName = r.cluster.Name
+		opt.ValidationTimeout = r.options.ValidationTimeout
+		// Every node must get certificates from the new keypairs, even those kops does not consider changed
+		opt.Force = true
+		opt.Yes = true
+		if err := RunRollingUpdateCluster(ctx, r.f, r.out, opt); err != nil {
+			return false, err
+		}
+		return true, nil
+
+	case carotation.StepStageValidate, carotation.StepPromoteValidate, carotation.StepDistrustValidate:
+		opt := &ValidateClusterOptions{}
+		opt.InitDefaults()
+		opt.ClusterName = r.cluster.Name
+		opt.wait = r.options.ValidationTimeout
+		if _, err := RunValidateCluster(ctx, r.f, r.out, opt); err != nil {
+			return false, err
+		}
+		return true, nil
+
+	case carotation.StepDistributeCA:
+		fmt.Fprintf(r.out, "The cluster now trusts the new %s keypair.\n", carotation.KeysetKubernetesCA)
+		fmt.Fprintf(r.out, "Export a kubeconfig with \"kops export kubecfg\" and distribute its certificate-authority-data\n")
+		fmt.Fprintf(r.out, "to all clients of the Kubernetes API, unless the API is served with a certificate of its own.\n")
+		return true, nil
+
+	case carotation.StepDistributeCredentials:
+		fmt.Fprintf(r.out, "The new keypairs are now the primary keypairs.\n")
+		fmt.Fprintf(r.out, "Export new admin credentials with \"kops export kubecfg --admin=DURATION\" and distribute them\n")
+		fmt.Fprintf(r.out, "to all clients that need them; credentials issued by the previous CA will stop working.\n")
+		return true, nil
+
+	case carotation.StepDistributeFinalCA:
+		fmt.Fprintf(r.out, "The previous keypairs are no longer trusted.\n")
+		fmt.Fprintf(r.out, "Export a kubeconfig with \"kops export kubecfg\" and distribute its certificate-authority-data,\n")
+		fmt.Fprintf(r.out, "which no longer includes the previous CA certificate, to all clients of the Kubernetes API.\n")
+		return true, nil
+
+	default:
+		return false, fmt.Errorf("unknown rotation step %q", step)
+	}
+}
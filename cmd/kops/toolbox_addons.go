@@ -46,6 +46,7 @@ func NewCmdToolboxAddons(out io.Writer) *cobra.Command {
 		},
 	}
 	applyCmd.Flags().BoolVar(&applyOptions.Yes, "yes", false, "Apply update")
+	applyCmd.Flags().StringVar(&applyOptions.VerifyKeys, "verify-keys", "", "Location of PEM-encoded public keys; if set, refuse to apply channels and manifests that are not signed by one of them")
 
 	cmd.AddCommand(applyCmd)
 	cmd.AddCommand(&cobra.Command{
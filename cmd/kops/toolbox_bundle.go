@@ -0,0 +1,311 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/assets"
+	"k8s.io/kops/pkg/commands"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/upup/pkg/fi/fitasks"
+	"k8s.io/kops/util/pkg/vfs"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	toolboxBundleCreateLong = templates.LongDesc(i18n.T(`
+	Creates an offline bundle for a cluster, for use in air-gapped environments.
+
+	The bundle is a gzipped tarball containing the container images (as an OCI image layout)
+	and the file assets (including nodeup and protokube) used by the cluster.`))
+
+	toolboxBundleCreateExample = templates.Examples(i18n.T(`
+	# Create a bundle for a cluster
+	kops toolbox bundle create k8s-cluster.example.com --out kops-bundle.tar.gz
+	`))
+
+	toolboxBundleCreateShort = i18n.T(`Create an offline bundle of the assets for a cluster.`)
+
+	toolboxBundlePushLong = templates.LongDesc(i18n.T(`
+	Loads an offline bundle into a private container registry and file repository, and
+	updates the cluster's assets configuration to use them.
+
+	The registry and file repository default to the values already in the cluster spec.
+	If a state store mirror is specified, the bootstrap channel and addon manifests are
+	rendered from the updated cluster spec and written below it.`))
+
+	toolboxBundlePushExample = templates.Examples(i18n.T(`
+	# Push a bundle to a private registry and file repository
+	kops toolbox bundle push k8s-cluster.example.com --bundle kops-bundle.tar.gz \
+		--container-registry registry.example.com/kops \
+		--file-repository https://s3.us-east-1.amazonaws.com/kops-assets/files
+	`))
+
+	toolboxBundlePushShort = i18n.T(`Push an offline bundle to private repositories.`)
+)
+
+type ToolboxBundleCreateOptions struct {
+	ClusterName string
+	Out         string
+}
+
+type ToolboxBundlePushOptions struct {
+	ClusterName       string
+	Bundle            string
+	ContainerRegistry string
+	FileRepository    string
+	StateStoreMirror  string
+}
+
+func NewCmdToolboxBundle(f *util.Factory, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bundle",
+		Short: i18n.T(`Manage offline asset bundles.`),
+	}
+
+	cmd.AddCommand(NewCmdToolboxBundleCreate(f, out))
+	cmd.AddCommand(NewCmdToolboxBundlePush(f, out))
+
+	return cmd
+}
+
+func NewCmdToolboxBundleCreate(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxBundleCreateOptions{
+		Out: "kops-bundle.tar.gz",
+	}
+
+	cmd := &cobra.Command{
+		Use:               "create [CLUSTER]",
+		Short:             toolboxBundleCreateShort,
+		Long:              toolboxBundleCreateLong,
+		Example:           toolboxBundleCreateExample,
+		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
+		ValidArgsFunction: commandutils.CompleteClusterName(f, true, false),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunToolboxBundleCreate(cmd.Context(), f, out, options)
+		},
+	}
+
+	cmd.Flags().StringVar(&options.Out, "out", options.Out, "Path of the bundle to create")
+	cmd.MarkFlagFilename("out", "tar.gz")
+
+	return cmd
+}
+
+func RunToolboxBundleCreate(ctx context.Context, f *util.Factory, out io.Writer, options *ToolboxBundleCreateOptions) error {
+	updateClusterResults, err := RunUpdateCluster(ctx, f, out, &UpdateClusterOptions{
+		CoreUpdateClusterOptions: CoreUpdateClusterOptions{
+			Target:      cloudup.TargetDryRun,
+			GetAssets:   true,
+			ClusterName: options.ClusterName,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	contents := &assets.BundleContents{
+		ClusterName: options.ClusterName,
+		ImageAssets: updateClusterResults.ImageAssets,
+		FileAssets:  updateClusterResults.FileAssets,
+	}
+
+	bundleFile, err := os.Create(options.Out)
+	if err != nil {
+		return fmt.Errorf("creating bundle: %w", err)
+	}
+	if err := assets.WriteBundle(f.VFSContext(), contents, bundleFile); err != nil {
+		bundleFile.Close()
+		return err
+	}
+	if err := bundleFile.Close(); err != nil {
+		return fmt.Errorf("writing bundle: %w", err)
+	}
+
+	fmt.Fprintf(out, "Wrote bundle for cluster %q to %s\n", options.ClusterName, options.Out)
+	return nil
+}
+
+func NewCmdToolboxBundlePush(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxBundlePushOptions{
+		Bundle: "kops-bundle.tar.gz",
+	}
+
+	cmd := &cobra.Command{
+		Use:               "push [CLUSTER]",
+		Short:             toolboxBundlePushShort,
+		Long:              toolboxBundlePushLong,
+		Example:           toolboxBundlePushExample,
+		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
+		ValidArgsFunction: commandutils.CompleteClusterName(f, true, false),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunToolboxBundlePush(cmd.Context(), f, out, options)
+		},
+	}
+
+	cmd.Flags().StringVar(&options.Bundle, "bundle", options.Bundle, "Path of the bundle to push")
+	cmd.MarkFlagFilename("bundle", "tar.gz")
+	cmd.Flags().StringVar(&options.ContainerRegistry, "container-registry", options.ContainerRegistry, "Container registry to push the images to. Defaults to spec.assets.containerRegistry")
+	cmd.Flags().StringVar(&options.FileRepository, "file-repository", options.FileRepository, "File repository to push the files to. Defaults to spec.assets.fileRepository")
+	cmd.Flags().StringVar(&options.StateStoreMirror, "state-store-mirror", options.StateStoreMirror, "Location to write the bootstrap channel and addon manifests to")
+
+	return cmd
+}
+
+func RunToolboxBundlePush(ctx context.Context, f *util.Factory, out io.Writer, options *ToolboxBundlePushOptions) error {
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	clientset, err := f.KopsClient()
+	if err != nil {
+		return err
+	}
+
+	assetsLocation := &kops.AssetsSpec{}
+	if cluster.Spec.Assets != nil {
+		assetsLocation.ContainerRegistry = cluster.Spec.Assets.ContainerRegistry
+		assetsLocation.FileRepository = cluster.Spec.Assets.FileRepository
+	}
+	if options.ContainerRegistry != "" {
+		assetsLocation.ContainerRegistry = fi.PtrTo(strings.TrimSuffix(options.ContainerRegistry, "/"))
+	}
+	if options.FileRepository != "" {
+		assetsLocation.FileRepository = fi.PtrTo(options.FileRepository)
+	}
+
+	var stateStoreMirror vfs.Path
+	if options.StateStoreMirror != "" {
+		stateStoreMirror, err = f.VFSContext().BuildVfsPath(options.StateStoreMirror)
+		if err != nil {
+			return fmt.Errorf("error building path %q: %w", options.StateStoreMirror, err)
+		}
+	}
+
+	bundleFile, err := os.Open(options.Bundle)
+	if err != nil {
+		return fmt.Errorf("opening bundle: %w", err)
+	}
+	defer bundleFile.Close()
+
+	dir, err := os.MkdirTemp("", "kops-bundle")
+	if err != nil {
+		return fmt.Errorf("creating temp directory: %w", err)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			klog.Warningf("error removing temp directory %q: %v", dir, err)
+		}
+	}()
+
+	bundle, err := assets.ExtractBundle(bundleFile, dir)
+	if err != nil {
+		return err
+	}
+	if bundle.Manifest.ClusterName != cluster.ObjectMeta.Name {
+		klog.Warningf("bundle was created for cluster %q, pushing for cluster %q", bundle.Manifest.ClusterName, cluster.ObjectMeta.Name)
+	}
+
+	if err := bundle.Push(ctx, f.VFSContext(), assetsLocation, cluster); err != nil {
+		return err
+	}
+
+	instanceGroups, err := commands.ReadAllInstanceGroups(ctx, clientset, cluster)
+	if err != nil {
+		return err
+	}
+
+	// The images and files are now mirrored, so point the cluster at the mirrors.
+	// A pull-through proxy cannot be combined with a container registry.
+	if len(bundle.Manifest.Images) != 0 || len(bundle.Manifest.Files) != 0 {
+		if cluster.Spec.Assets == nil {
+			cluster.Spec.Assets = &kops.AssetsSpec{}
+		}
+		if len(bundle.Manifest.Images) != 0 {
+			cluster.Spec.Assets.ContainerRegistry = assetsLocation.ContainerRegistry
+			cluster.Spec.Assets.ContainerProxy = nil
+		}
+		if len(bundle.Manifest.Files) != 0 {
+			cluster.Spec.Assets.FileRepository = assetsLocation.FileRepository
+		}
+		if err := commands.UpdateCluster(ctx, clientset, cluster, instanceGroups); err != nil {
+			return err
+		}
+	}
+
+	// The addon manifests reference the images in the registry, so they are rendered from the updated cluster spec.
+	addons := 0
+	if stateStoreMirror != nil {
+		rendered, err := renderAddons(ctx, f, cluster.ObjectMeta.Name)
+		if err != nil {
+			return err
+		}
+		if err := assets.WriteAddons(ctx, cluster, stateStoreMirror, rendered); err != nil {
+			return err
+		}
+		addons = len(rendered)
+	}
+
+	fmt.Fprintf(out, "Pushed %d images, %d files and %d addon manifests\n", len(bundle.Manifest.Images), len(bundle.Manifest.Files), addons)
+	fmt.Fprintf(out, "\nRun \"kops update cluster --name %s\" to apply the new assets configuration.\n", cluster.ObjectMeta.Name)
+	return nil
+}
+
+// renderAddons renders the bootstrap channel and addon manifests of the cluster as stored in the state store.
+// They are keyed by their location relative to the cluster's state store path.
+func renderAddons(ctx context.Context, f *util.Factory, clusterName string) (map[string][]byte, error) {
+	updateClusterResults, err := RunUpdateCluster(ctx, f, io.Discard, &UpdateClusterOptions{
+		CoreUpdateClusterOptions: CoreUpdateClusterOptions{
+			Target:      cloudup.TargetDryRun,
+			ClusterName: clusterName,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	addons := make(map[string][]byte)
+	for _, task := range updateClusterResults.TaskMap {
+		managedFile, ok := task.(*fitasks.ManagedFile)
+		if !ok || managedFile.Base != nil {
+			continue
+		}
+		location := fi.ValueOf(managedFile.Location)
+		if !strings.HasPrefix(location, "addons/") {
+			continue
+		}
+		data, err := fi.ResourceAsBytes(managedFile.Contents)
+		if err != nil {
+			return nil, fmt.Errorf("rendering %q: %w", location, err)
+		}
+		addons[location] = data
+	}
+	return addons, nil
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/acls"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/envelope"
+	"k8s.io/kops/util/pkg/vfs"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	toolboxEncryptStateStoreLong = templates.LongDesc(i18n.T(`
+	Encrypts the secrets and private keys of a cluster that are stored in plaintext in the state store.
+
+	Objects are encrypted as configured by spec.configStore.encryption, which must be set first.
+	Objects that are already encrypted are left as they are. Once the state store is encrypted,
+	run "kops update cluster --yes" to also encrypt the copies mirrored for the nodes.`))
+
+	toolboxEncryptStateStoreExample = templates.Examples(i18n.T(`
+	# List the objects that would be encrypted
+	kops toolbox encrypt-state-store --name k8s-cluster.example.com
+
+	# Encrypt the objects
+	kops toolbox encrypt-state-store --name k8s-cluster.example.com --yes
+	`))
+
+	toolboxEncryptStateStoreShort = i18n.T(`Encrypt the secrets and private keys in the state store.`)
+)
+
+type ToolboxEncryptStateStoreOptions struct {
+	ClusterName string
+	Yes         bool
+}
+
+func NewCmdToolboxEncryptStateStore(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxEncryptStateStoreOptions{}
+
+	cmd := &cobra.Command{
+		Use:               "encrypt-state-store [CLUSTER]",
+		Short:             toolboxEncryptStateStoreShort,
+		Long:              toolboxEncryptStateStoreLong,
+		Example:           toolboxEncryptStateStoreExample,
+		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
+		ValidArgsFunction: commandutils.CompleteClusterName(f, true, false),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunToolboxEncryptStateStore(cmd.Context(), f, out, options)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Encrypt the objects; without this flag, only list them")
+
+	return cmd
+}
+
+func RunToolboxEncryptStateStore(ctx context.Context, f *util.Factory, out io.Writer, options *ToolboxEncryptStateStoreOptions) error {
+	clientset, err := f.KopsClient()
+	if err != nil {
+		return err
+	}
+	cluster, err := clientset.GetCluster(ctx, options.ClusterName)
+	if err != nil {
+		return err
+	}
+	if cluster == nil {
+		return fmt.Errorf("cluster %q not found", options.ClusterName)
+	}
+	if cluster.Spec.ConfigStore.Encryption == nil {
+		return fmt.Errorf("spec.configStore.encryption is not set; configure it with \"kops edit cluster\" first")
+	}
+
+	keyStore, err := clientset.KeyStore(cluster)
+	if err != nil {
+		return err
+	}
+	secretStore, err := clientset.SecretStore(cluster)
+	if err != nil {
+		return err
+	}
+
+	var objects []vfs.Path
+	for _, store := range []any{keyStore, secretStore} {
+		vfsStore, ok := store.(interface{ VFSPath() vfs.Path })
+		if !ok {
+			return fmt.Errorf("state store encryption is not supported for %T", store)
+		}
+		basedir := vfsStore.VFSPath()
+		files, err := basedir.ReadTree(ctx)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return fmt.Errorf("error listing %q: %w", basedir, err)
+		}
+		for _, file := range files {
+			if store == keyStore && !strings.HasSuffix(file.Path(), "/keyset.yaml") {
+				// SSH public keys are not secret
+				continue
+			}
+			objects = append(objects, file)
+		}
+	}
+
+	encrypted := 0
+	for _, p := range objects {
+		changed, err := encryptStateStoreObject(ctx, cluster, p, options.Yes)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			continue
+		}
+		encrypted++
+		if options.Yes {
+			fmt.Fprintf(out, "Encrypted %s\n", p)
+		} else {
+			fmt.Fprintf(out, "Will encrypt %s\n", p)
+		}
+	}
+
+	if encrypted == 0 {
+		fmt.Fprintf(out, "No plaintext secrets or private keys found\n")
+	} else if !options.Yes {
+		fmt.Fprintf(out, "\nMust specify --yes to encrypt\n")
+	}
+	return nil
+}
+
+// encryptStateStoreObject encrypts the object if it is not encrypted, returning whether it was (or would be) changed.
+func encryptStateStoreObject(ctx context.Context, cluster *kops.Cluster, p vfs.Path, yes bool) (bool, error) {
+	data, err := p.ReadFile(ctx)
+	if err != nil {
+		return false, fmt.Errorf("error reading %q: %w", p, err)
+	}
+	if envelope.IsEncrypted(data) {
+		return false, nil
+	}
+	if !yes {
+		return true, nil
+	}
+
+	data, err = envelope.Encrypt(ctx, cluster.Spec.ConfigStore.Encryption, p.Path(), data)
+	if err != nil {
+		return false, fmt.Errorf("error encrypting %q: %w", p, err)
+	}
+	acl, err := acls.GetACL(ctx, p, cluster)
+	if err != nil {
+		return false, err
+	}
+	if err := p.WriteFile(ctx, bytes.NewReader(data), acl); err != nil {
+		return false, fmt.Errorf("error writing %q: %w", p, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/channels/pkg/channels"
+	"k8s.io/kops/channels/pkg/signature"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/vfs"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	toolboxSignAddonsLong = templates.LongDesc(i18n.T(`
+	Signs addon channels and the manifests they reference.
+
+	A detached signature is written next to each file, with ".sig" appended to its location.
+	Clusters with addon signing enabled only apply channels and manifests with a valid
+	signature from the cluster's addon-signing key or one of spec.addonSigning.additionalTrustedKeys.
+
+	By default the primary key of the cluster's addon-signing keyset is used. To keep the
+	signing key offline, sign with --key-uri, a key held in a PKCS#11 module or a cloud KMS,
+	or with cosign sign-blob, and trust its public key in spec.addonSigning.additionalTrustedKeys.`))
+
+	toolboxSignAddonsExample = templates.Examples(i18n.T(`
+	# Sign a custom channel with the cluster's addon-signing key
+	kops toolbox sign-addons s3://my-addons/example/channel.yaml --name k8s-cluster.example.com
+
+	# Sign a custom channel with a private key
+	kops toolbox sign-addons s3://my-addons/example/channel.yaml --key ~/addon-signing-key.pem
+
+	# Sign a custom channel with a key held in AWS KMS
+	kops toolbox sign-addons s3://my-addons/example/channel.yaml --key-uri aws-kms://alias/kops-addons
+	`))
+
+	toolboxSignAddonsShort = i18n.T(`Sign addon channels and manifests.`)
+)
+
+type ToolboxSignAddonsOptions struct {
+	ClusterName    string
+	PrivateKeyPath string
+	// PrivateKeyURI references a private key held in a PKCS#11 module or a cloud KMS.
+	PrivateKeyURI string
+	Channels      []string
+}
+
+func NewCmdToolboxSignAddons(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxSignAddonsOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "sign-addons CHANNEL...",
+		Short:   toolboxSignAddonsShort,
+		Long:    toolboxSignAddonsLong,
+		Example: toolboxSignAddonsExample,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return fmt.Errorf("must specify at least one channel to sign")
+			}
+			options.Channels = args
+
+			if options.PrivateKeyPath != "" && options.PrivateKeyURI != "" {
+				return fmt.Errorf("cannot specify both --key and --key-uri")
+			}
+			if options.PrivateKeyPath == "" && options.PrivateKeyURI == "" {
+				options.ClusterName = rootCommand.ClusterName(true)
+				if options.ClusterName == "" {
+					return fmt.Errorf("--name, --key or --key-uri is required")
+				}
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunToolboxSignAddons(cmd.Context(), f, out, options)
+		},
+	}
+
+	cmd.Flags().StringVar(&options.PrivateKeyPath, "key", options.PrivateKeyPath, "Path to the private key to sign with, instead of the cluster's addon-signing key")
+	cmd.MarkFlagFilename("key", "pem", "key")
+	cmd.Flags().StringVar(&options.PrivateKeyURI, "key-uri", options.PrivateKeyURI, "URI of a private key held in a PKCS#11 module or a cloud KMS to sign with, instead of the cluster's addon-signing key")
+
+	return cmd
+}
+
+func RunToolboxSignAddons(ctx context.Context, f *util.Factory, out io.Writer, options *ToolboxSignAddonsOptions) error {
+	signer, err := loadAddonSigner(ctx, f, options)
+	if err != nil {
+		return err
+	}
+
+	vfsContext := f.VFSContext()
+	signed := make(map[string]bool)
+	for _, channel := range options.Channels {
+		location, err := channelURL(channel)
+		if err != nil {
+			return err
+		}
+
+		data, err := vfsContext.ReadFile(location.String())
+		if err != nil {
+			return fmt.Errorf("reading channel %q: %w", location, err)
+		}
+		addons, err := channels.ParseAddons(channel, location, data)
+		if err != nil {
+			return err
+		}
+		if err := signAddonFile(ctx, vfsContext, signer, location.String(), data); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Signed %s\n", location)
+
+		for _, spec := range addons.APIObject.Spec.Addons {
+			addon := &channels.Addon{ChannelLocation: *location, Spec: spec}
+			manifestURL, err := addon.GetManifestFullUrl()
+			if err != nil {
+				return fmt.Errorf("addon %q in channel %q: %w", fi.ValueOf(spec.Name), location, err)
+			}
+			manifest := manifestURL.String()
+			if signed[manifest] {
+				continue
+			}
+			signed[manifest] = true
+
+			data, err := vfsContext.ReadFile(manifest)
+			if err != nil {
+				return fmt.Errorf("reading manifest %q: %w", manifest, err)
+			}
+			if err := signAddonFile(ctx, vfsContext, signer, manifest, data); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "Signed %s\n", manifest)
+		}
+	}
+
+	return nil
+}
+
+// loadAddonSigner returns the key from --key or --key-uri, or else the primary key of the cluster's addon-signing keyset.
+func loadAddonSigner(ctx context.Context, f *util.Factory, options *ToolboxSignAddonsOptions) (crypto.Signer, error) {
+	if options.PrivateKeyURI != "" {
+		privateKey, err := pki.NewPrivateKeyReference(options.PrivateKeyURI)
+		if err != nil {
+			return nil, err
+		}
+		if err := privateKey.Load(); err != nil {
+			return nil, err
+		}
+		return privateKey.Key, nil
+	}
+
+	if options.PrivateKeyPath != "" {
+		data, err := os.ReadFile(options.PrivateKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("reading private key %q: %w", options.PrivateKeyPath, err)
+		}
+		privateKey, err := pki.ParsePEMPrivateKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("parsing private key %q: %w", options.PrivateKeyPath, err)
+		}
+		return privateKey.Key, nil
+	}
+
+	clientset, err := f.KopsClient()
+	if err != nil {
+		return nil, err
+	}
+	cluster, err := clientset.GetCluster(ctx, options.ClusterName)
+	if err != nil {
+		return nil, err
+	}
+	keyStore, err := clientset.KeyStore(cluster)
+	if err != nil {
+		return nil, err
+	}
+	keyset, err := keyStore.FindKeyset(ctx, fi.CertificateIDAddonSigning)
+	if err != nil {
+		return nil, err
+	}
+	if keyset == nil || keyset.Primary == nil || keyset.Primary.PrivateKey == nil {
+		return nil, fmt.Errorf("keyset %q not found; enable spec.addonSigning and run \"kops update cluster\", or use --key", fi.CertificateIDAddonSigning)
+	}
+	return keyset.Primary.PrivateKey.Key, nil
+}
+
+// channelURL parses a channel location, treating anything that is not a URL as a local file.
+func channelURL(channel string) (*url.URL, error) {
+	location, err := url.Parse(channel)
+	if err == nil && location.IsAbs() {
+		return location, nil
+	}
+	abs, err := filepath.Abs(channel)
+	if err != nil {
+		return nil, fmt.Errorf("resolving %q: %w", channel, err)
+	}
+	return &url.URL{Scheme: "file", Path: abs}, nil
+}
+
+func signAddonFile(ctx context.Context, vfsContext *vfs.VFSContext, signer crypto.Signer, location string, data []byte) error {
+	sig, err := signature.Sign(signer, data)
+	if err != nil {
+		return fmt.Errorf("signing %q: %w", location, err)
+	}
+	p, err := vfsContext.BuildVfsPath(location + signature.Extension)
+	if err != nil {
+		return fmt.Errorf("error building path for %q: %w", location, err)
+	}
+	if err := p.WriteFile(ctx, bytes.NewReader(sig), nil); err != nil {
+		return fmt.Errorf("writing signature for %q: %w", location, err)
+	}
+	return nil
+}
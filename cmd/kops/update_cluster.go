@@ -154,8 +154,14 @@ func NewCmdUpdateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
 		ValidArgsFunction: commandutils.CompleteClusterName(f, true, false),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			_, err := RunUpdateCluster(cmd.Context(), f, out, options)
-			return err
+			run := func(ctx context.Context) error {
+				_, err := RunUpdateCluster(ctx, f, out, options)
+				return err
+			}
+			if options.Yes {
+				return withClusterLock(cmd.Context(), f, options.ClusterName, "update cluster", run)
+			}
+			return run(cmd.Context())
 		},
 	}
 
@@ -274,6 +280,13 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Up
 		return results, err
 	}
 
+	// The specs of an interrupted rollback are partially restored
+	if revision, err := clientset.HistoryFor(cluster).InterruptedRollback(ctx); err != nil {
+		return results, err
+	} else if revision != 0 {
+		return results, fmt.Errorf("the rollback of cluster %q to revision %d was interrupted, complete it with `kops rollback cluster %s --to rev%d --yes`", cluster.Name, revision, cluster.Name, revision)
+	}
+
 	keyStore, err := clientset.KeyStore(cluster)
 	if err != nil {
 		return results, err
@@ -20,7 +20,6 @@ import (
 	"context"
 	"fmt"
 	"net/http"
-	"net/url"
 	"strings"
 	"sync"
 
@@ -101,20 +100,18 @@ func (f *Factory) KopsClient() (simple.Clientset, error) {
 			return nil, field.Required(field.NewPath("State Store"), STATE_ERROR)
 		}
 
-		// We recognize a `k8s` scheme; this might change in future so we won't document it yet
-		// In practice nobody is going to hit this accidentally, so I don't think we need a feature flag.
+		// The `k8s` scheme stores the cluster in a management cluster, as k8s://<context>/<namespace>
 		if strings.HasPrefix(registryPath, "k8s://") {
+			baseURL, err := api.ParseStateStoreURL(registryPath)
+			if err != nil {
+				return nil, err
+			}
+
 			loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
 
 			configOverrides := &clientcmd.ConfigOverrides{}
-
-			if registryPath == "k8s://" {
-			} else {
-				u, err := url.Parse(registryPath)
-				if err != nil {
-					return nil, fmt.Errorf("invalid kops server url: %q", registryPath)
-				}
-				configOverrides.CurrentContext = u.Host
+			if baseURL.Host != "" {
+				configOverrides.CurrentContext = baseURL.Host
 			}
 
 			kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, configOverrides)
@@ -128,13 +125,7 @@ func (f *Factory) KopsClient() (simple.Clientset, error) {
 				return nil, fmt.Errorf("error building kops API client: %v", err)
 			}
 
-			f.clientset = api.NewRESTClientset(
-				f.VFSContext(),
-				&url.URL{
-					Scheme: "k8s",
-				},
-				kopsClient.Kops(),
-			)
+			f.clientset = api.NewRESTClientset(f.VFSContext(), baseURL, kopsClient.Kops())
 		} else {
 			basePath, err := f.VFSContext().BuildVfsPath(registryPath)
 			if err != nil {
@@ -25,14 +25,30 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"strconv"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/wellknownports"
 )
 
+// proxiedRequests counts the health checks we have proxied to the apiserver, by path and response code
+var proxiedRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "kube_apiserver_healthcheck_requests_total",
+	Help: "Number of health checks proxied to kube-apiserver, by path and response code.",
+}, []string{"path", "code"})
+
+func init() {
+	prometheus.MustRegister(proxiedRequests)
+}
+
 // healthCheckServer is the http server
 type healthCheckServer struct {
 	transport *http.Transport
+
+	// metrics enables serving our own metrics
+	metrics bool
 }
 
 // handler processes a single http request
@@ -44,6 +60,12 @@ func (s *healthCheckServer) handler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if s.metrics && r.Method == "GET" && r.URL.Path == "/.kube-apiserver-healthcheck/metrics" {
+		// These are our own metrics, including the results of the health checks we have proxied
+		promhttp.Handler().ServeHTTP(w, r)
+		return
+	}
+
 	if proxyRequest := mapToProxyRequest(r); proxyRequest != nil {
 		s.proxyRequest(w, proxyRequest)
 		return
@@ -108,12 +130,15 @@ func (s *healthCheckServer) proxyRequest(w http.ResponseWriter, forwardRequest *
 	resp, err := httpClient.Do(forwardRequest)
 	if err != nil {
 		klog.Infof("error from %s: %v", forwardRequest.URL, err)
+		proxiedRequests.WithLabelValues(forwardRequest.URL.Path, strconv.Itoa(http.StatusBadGateway)).Inc()
 		http.Error(w, "internal error", http.StatusBadGateway)
 		return
 	}
 
 	defer resp.Body.Close()
 
+	proxiedRequests.WithLabelValues(forwardRequest.URL.Path, strconv.Itoa(resp.StatusCode)).Inc()
+
 	w.WriteHeader(resp.StatusCode)
 	if _, err := io.Copy(w, resp.Body); err != nil {
 		klog.Warningf("error writing response body: %v", err)
@@ -134,10 +159,12 @@ func run() error {
 	clientCert := ""
 	clientKey := ""
 	caCert := ""
+	metrics := false
 
 	flag.StringVar(&clientCert, "client-cert", clientCert, "path to client certificate")
 	flag.StringVar(&clientKey, "client-key", clientKey, "path to client key")
 	flag.StringVar(&caCert, "ca-cert", caCert, "path to ca certificate")
+	flag.BoolVar(&metrics, "metrics", metrics, "serve metrics on /.kube-apiserver-healthcheck/metrics")
 
 	klog.InitFlags(nil)
 
@@ -170,6 +197,7 @@ func run() error {
 
 	s := &healthCheckServer{
 		transport: transport,
+		metrics:   metrics,
 	}
 
 	http.HandleFunc("/", s.handler)
@@ -17,14 +17,23 @@ limitations under the License.
 package main // import "k8s.io/kops/cmd/nodeup"
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"k8s.io/klog/v2"
 	"k8s.io/kops"
 	"k8s.io/kops/nodeup/pkg/bootstrap"
+	"k8s.io/kops/nodeup/pkg/configagent"
+	"k8s.io/kops/nodeup/pkg/renewal"
+	nodeupapi "k8s.io/kops/pkg/apis/nodeup"
+	// Register the providers of CA keys held in PKCS#11 modules and cloud KMS.
+	_ "k8s.io/kops/pkg/keysigner"
 	"k8s.io/kops/upup/pkg/fi/nodeup"
 )
 
@@ -38,7 +47,8 @@ func main() {
 
 	var flagConf, flagCacheDir, gitVersion string
 	var flagRetries int
-	var dryrun, installSystemdUnit bool
+	var dryrun, installSystemdUnit, renewCertificates, watchConfig bool
+	renewBefore := renewal.DefaultRenewBefore
 	target := "direct"
 
 	if kops.GitVersion != "" {
@@ -51,6 +61,9 @@ func main() {
 	flag.BoolVar(&dryrun, "dryrun", false, "Don't create cloud resources; just show what would be done")
 	flag.StringVar(&target, "target", target, "Target - direct, dryrun")
 	flag.BoolVar(&installSystemdUnit, "install-systemd-unit", installSystemdUnit, "If true, will install a systemd unit instead of running directly")
+	flag.BoolVar(&renewCertificates, "renew-certificates", renewCertificates, "If true, will renew the certificates issued by kops-controller if they are close to expiry, instead of running directly")
+	flag.DurationVar(&renewBefore, "renew-before", renewBefore, "How long before expiry to renew certificates, with --renew-certificates")
+	flag.BoolVar(&watchConfig, "watch-config", watchConfig, "If true, will run the node configuration agent, applying changes to the node configuration from kops-controller, instead of running directly")
 
 	if dryrun {
 		target = "dryrun"
@@ -63,6 +76,20 @@ func main() {
 		klog.Exitf("--conf is required")
 	}
 
+	if renewCertificates {
+		if err := runRenewCertificates(renewBefore); err != nil {
+			klog.Exitf("error renewing certificates: %v", err)
+		}
+		os.Exit(0)
+	}
+
+	if watchConfig {
+		if err := runConfigAgent(flagConf, flagCacheDir); err != nil {
+			klog.Exitf("error running node configuration agent: %v", err)
+		}
+		os.Exit(0)
+	}
+
 	retries := flagRetries
 
 	for {
@@ -134,3 +161,37 @@ func main() {
 		time.Sleep(retryInterval)
 	}
 }
+
+// runRenewCertificates renews the certificates that the node obtained from kops-controller.
+func runRenewCertificates(renewBefore time.Duration) error {
+	config, err := renewal.LoadConfig(renewal.ConfigPath)
+	if err != nil {
+		return err
+	}
+	if config == nil {
+		klog.Infof("no certificate renewal configuration at %s; nothing to renew", renewal.ConfigPath)
+		return nil
+	}
+	return renewal.NewRenewer(config, renewBefore).Run(context.Background())
+}
+
+// runConfigAgent applies the changes to the node configuration that are safe on a running node, until it is stopped.
+func runConfigAgent(conf, cacheDir string) error {
+	agent := configagent.NewAgent(configagent.ConfigPath, func(ctx context.Context, nodeConfig *nodeupapi.NodeConfig) error {
+		cmd := &nodeup.NodeUpCommand{
+			ConfigLocation: conf,
+			Target:         "direct",
+			CacheDir:       cacheDir,
+			NodeConfig:     nodeConfig,
+			Live:           true,
+		}
+		return cmd.Run(os.Stdout)
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := agent.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+#!/usr/bin/env bash
+
+# Copyright 2026 The Kubernetes Authors.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+# Vendors the gateway.k8s.aws CRDs of the aws-load-balancer-controller release
+# that the addon manifest is sourced from, replacing the block between the
+# "BEGIN gateway.k8s.aws CRDs" and "END gateway.k8s.aws CRDs" markers.
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+. "$(dirname "${BASH_SOURCE[0]}")/common.sh"
+
+cd "${KOPS_ROOT}"
+
+TEMPLATE=upup/models/cloudup/resources/addons/aws-load-balancer-controller.addons.k8s.io/k8s-1.19.yaml.template
+
+VERSION="$(sed -n 's|^# sourced from https://github.com/kubernetes-sigs/aws-load-balancer-controller/releases/download/\(v[^/]*\)/.*_full.yaml$|\1|p' "${TEMPLATE}")"
+if [[ -z "${VERSION}" ]]; then
+  echo "unable to find the aws-load-balancer-controller version in ${TEMPLATE}" >&2
+  exit 1
+fi
+
+URL="https://raw.githubusercontent.com/kubernetes-sigs/aws-load-balancer-controller/${VERSION}/config/crd/gateway/gateway-crds.yaml"
+
+CRDS="$(mktemp)"
+OUT="$(mktemp)"
+trap 'rm -f "${CRDS}" "${OUT}"' EXIT
+
+curl --fail --silent --show-error --location "${URL}" | sed '1{/^---$/d}' > "${CRDS}"
+
+awk -v crds="${CRDS}" -v url="${URL}" '
+  /^# BEGIN gateway.k8s.aws CRDs/ {
+    print
+    getline
+    print
+    print "# sourced from " url
+    while ((getline line < crds) > 0) print line
+    skip = 1
+    next
+  }
+  /^# END gateway.k8s.aws CRDs/ { skip = 0 }
+  !skip { print }
+' "${TEMPLATE}" > "${OUT}"
+cp "${OUT}" "${TEMPLATE}"
+
+echo "Vendored the gateway.k8s.aws CRDs of aws-load-balancer-controller ${VERSION}; run hack/update-expected.sh to update the test outputs"
@@ -23,6 +23,7 @@ import (
 	"strings"
 
 	"k8s.io/klog/v2"
+	"k8s.io/kops/nodeup/pkg/configagent"
 	"k8s.io/kops/pkg/systemd"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/scaleway"
@@ -75,6 +76,10 @@ func (i *Installation) Run() error {
 func (i *Installation) Build(c *fi.InstallModelBuilderContext) {
 	c.AddTask(i.buildEnvFile())
 	c.AddTask(i.buildSystemdJob())
+	c.AddTask(i.buildCertificateRenewalJob())
+	c.AddTask(i.buildCertificateRenewalTimer())
+	c.AddTask(i.buildConfigAgentJob())
+	c.AddTask(i.buildConfigAgentPath())
 }
 
 func (i *Installation) buildEnvFile() *nodetasks.InstallFile {
@@ -181,3 +186,116 @@ func (i *Installation) buildSystemdJob() *nodetasks.InstallService {
 
 	return service
 }
+
+// buildCertificateRenewalJob builds the service that renews the certificates the node obtained from kops-controller.
+// It does nothing on nodes that have no such certificates.
+func (i *Installation) buildCertificateRenewalJob() *nodetasks.InstallService {
+	serviceName := "kops-cert-renewal.service"
+
+	manifest := &systemd.Manifest{}
+	manifest.Set("Unit", "Description", "Renew kOps node certificates")
+	manifest.Set("Unit", "Documentation", "https://github.com/kubernetes/kops")
+	manifest.Set("Unit", "After", "kops-configuration.service")
+
+	manifest.Set("Service", "EnvironmentFile", "/etc/sysconfig/kops-configuration")
+	manifest.Set("Service", "EnvironmentFile", "/etc/environment")
+	manifest.Set("Service", "ExecStart", i.Command[0]+" --renew-certificates")
+	manifest.Set("Service", "Type", "oneshot")
+
+	manifestString := manifest.Render()
+	klog.V(8).Infof("Built service manifest %q\n%s", serviceName, manifestString)
+
+	service := &nodetasks.InstallService{Service: nodetasks.Service{
+		Name:       serviceName,
+		Definition: fi.PtrTo(manifestString),
+		// The service is started by its timer
+		Running: fi.PtrTo(false),
+	}}
+
+	service.InitDefaults()
+
+	return service
+}
+
+func (i *Installation) buildCertificateRenewalTimer() *nodetasks.InstallService {
+	serviceName := "kops-cert-renewal.timer"
+
+	manifest := &systemd.Manifest{}
+	manifest.Set("Unit", "Description", "Daily renewal of kOps node certificates")
+	manifest.Set("Unit", "Documentation", "https://github.com/kubernetes/kops")
+
+	manifest.Set("Timer", "OnCalendar", "daily")
+	manifest.Set("Timer", "RandomizedDelaySec", "1h")
+	manifest.Set("Timer", "Persistent", "true")
+
+	manifest.Set("Install", "WantedBy", "timers.target")
+
+	manifestString := manifest.Render()
+	klog.V(8).Infof("Built service manifest %q\n%s", serviceName, manifestString)
+
+	service := &nodetasks.InstallService{Service: nodetasks.Service{
+		Name:       serviceName,
+		Definition: fi.PtrTo(manifestString),
+	}}
+
+	service.InitDefaults()
+
+	return service
+}
+
+// buildConfigAgentJob builds the service that applies changes to the node configuration without replacing the node.
+// It only runs on nodes where nodeup wrote the agent configuration, because the cluster enabled the agent.
+func (i *Installation) buildConfigAgentJob() *nodetasks.InstallService {
+	serviceName := "kops-config-agent.service"
+
+	manifest := &systemd.Manifest{}
+	manifest.Set("Unit", "Description", "Apply kOps node configuration changes")
+	manifest.Set("Unit", "Documentation", "https://github.com/kubernetes/kops")
+	manifest.Set("Unit", "After", "kops-configuration.service")
+	manifest.Set("Unit", "ConditionPathExists", configagent.ConfigPath)
+
+	manifest.Set("Service", "EnvironmentFile", "/etc/sysconfig/kops-configuration")
+	manifest.Set("Service", "EnvironmentFile", "/etc/environment")
+	manifest.Set("Service", "ExecStart", strings.Join(i.Command, " ")+" --watch-config")
+	manifest.Set("Service", "Restart", "on-failure")
+	manifest.Set("Service", "RestartSec", "30s")
+
+	manifestString := manifest.Render()
+	klog.V(8).Infof("Built service manifest %q\n%s", serviceName, manifestString)
+
+	service := &nodetasks.InstallService{Service: nodetasks.Service{
+		Name:       serviceName,
+		Definition: fi.PtrTo(manifestString),
+		// The service is started by its path unit
+		Running: fi.PtrTo(false),
+	}}
+
+	service.InitDefaults()
+
+	return service
+}
+
+// buildConfigAgentPath starts the node configuration agent once nodeup has written its configuration.
+func (i *Installation) buildConfigAgentPath() *nodetasks.InstallService {
+	serviceName := "kops-config-agent.path"
+
+	manifest := &systemd.Manifest{}
+	manifest.Set("Unit", "Description", "Start the kOps node configuration agent when it is configured")
+	manifest.Set("Unit", "Documentation", "https://github.com/kubernetes/kops")
+
+	manifest.Set("Path", "PathExists", configagent.ConfigPath)
+
+	manifest.Set("Install", "WantedBy", "multi-user.target")
+
+	manifestString := manifest.Render()
+	klog.V(8).Infof("Built service manifest %q\n%s", serviceName, manifestString)
+
+	service := &nodetasks.InstallService{Service: nodetasks.Service{
+		Name:       serviceName,
+		Definition: fi.PtrTo(manifestString),
+	}}
+
+	service.InitDefaults()
+
+	return service
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package configagent keeps the configuration of a running node up to date, without replacing the node.
+// It polls kops-controller for the node's configuration, applies the changes that are safe on a running node,
+// and records the generation it applied on the Node object.
+package configagent
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/nodeup"
+	"k8s.io/kops/pkg/kopscontrollerclient"
+	"k8s.io/kops/pkg/kubeconfig"
+	"sigs.k8s.io/yaml"
+)
+
+// ConfigPath is the location of the agent configuration written by nodeup.
+// The agent only runs on nodes where it exists.
+const ConfigPath = "/etc/kubernetes/kops/config-agent.yaml"
+
+// GenerationAnnotation is the annotation on the Node object with the generation of the configuration applied by the agent.
+const GenerationAnnotation = "kops.k8s.io/node-config-generation"
+
+// Config configures the agent.
+type Config struct {
+	// Server is the base URL of kops-controller.
+	Server string `json:"server"`
+	// CA is the CA certificate bundle for kops-controller.
+	CA string `json:"ca"`
+	// KubeConfig is the path of the kubelet kubeconfig.
+	// Its client certificate authenticates the node to kops-controller and to the API server.
+	KubeConfig string `json:"kubeConfig"`
+	// Interval is how often to poll kops-controller for changes, on average.
+	Interval metav1.Duration `json:"interval"`
+}
+
+// LoadConfig reads the agent configuration, returning nil if there is none.
+func LoadConfig(p string) (*Config, error) {
+	data, err := os.ReadFile(p)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading %q: %w", p, err)
+	}
+	config := &Config{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("parsing %q: %w", p, err)
+	}
+	return config, nil
+}
+
+// ApplyFunc applies a node configuration to the running node.
+type ApplyFunc func(ctx context.Context, nodeConfig *nodeup.NodeConfig) error
+
+// Agent polls kops-controller for changes to the node configuration and applies them.
+type Agent struct {
+	// ConfigPath is the location of the agent configuration.
+	// It is read again before each poll, because applying a configuration can change it.
+	ConfigPath string
+	// Apply applies a changed node configuration.
+	Apply ApplyFunc
+
+	// generation is the generation of the configuration that was last applied.
+	generation string
+
+	// nodesClient can be replaced in tests.
+	nodesClient func(kubeconfigPath string) (corev1client.NodeInterface, error)
+}
+
+// NewAgent builds an Agent.
+func NewAgent(configPath string, apply ApplyFunc) *Agent {
+	return &Agent{
+		ConfigPath:  configPath,
+		Apply:       apply,
+		nodesClient: nodesClient,
+	}
+}
+
+// Run polls kops-controller until the context is cancelled, or the agent configuration is removed.
+// Errors are logged and retried at the next poll.
+func (a *Agent) Run(ctx context.Context) error {
+	for {
+		config, err := LoadConfig(a.ConfigPath)
+		if err != nil {
+			return err
+		}
+		if config == nil {
+			klog.Infof("no node configuration agent configuration at %s; stopping", a.ConfigPath)
+			return nil
+		}
+
+		if err := a.Poll(ctx, config); err != nil {
+			klog.Warningf("error updating node configuration: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pollInterval(config.Interval.Duration)):
+		}
+	}
+}
+
+// pollInterval returns a random time between half and one and a half intervals,
+// so that nodes started at the same time do not keep polling kops-controller at the same time.
+func pollInterval(interval time.Duration) time.Duration {
+	return wait.Jitter(interval/2, 2.0)
+}
+
+// Poll asks kops-controller for the node configuration, and applies it if it changed since it was last applied.
+func (a *Agent) Poll(ctx context.Context, config *Config) error {
+	client, nodeName, err := buildClient(config)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	nodes, err := a.nodesClient(config.KubeConfig)
+	if err != nil {
+		return err
+	}
+
+	if a.generation == "" {
+		// The agent may have been restarted; the last applied generation is recorded on the node.
+		node, err := nodes.Get(ctx, nodeName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("getting node %q: %w", nodeName, err)
+		}
+		a.generation = node.Annotations[GenerationAnnotation]
+	}
+
+	req := &nodeup.NodeConfigRequest{
+		APIVersion: nodeup.BootstrapAPIVersion,
+		Generation: a.generation,
+	}
+	resp := &nodeup.NodeConfigResponse{}
+	if err := client.GetNodeConfig(ctx, req, resp); err != nil {
+		return fmt.Errorf("getting node configuration: %w", err)
+	}
+	if resp.NodeConfig == nil {
+		klog.V(2).Infof("node configuration generation %q is unchanged", a.generation)
+		return nil
+	}
+
+	klog.Infof("applying node configuration generation %q (previously %q)", resp.Generation, a.generation)
+	if err := a.Apply(ctx, resp.NodeConfig); err != nil {
+		return fmt.Errorf("applying node configuration generation %q: %w", resp.Generation, err)
+	}
+	a.generation = resp.Generation
+
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"annotations": map[string]string{
+				GenerationAnnotation: resp.Generation,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := nodes.Patch(ctx, nodeName, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("recording node configuration generation on node %q: %w", nodeName, err)
+	}
+	klog.Infof("applied node configuration generation %q", resp.Generation)
+	return nil
+}
+
+// buildClient builds a kops-controller client that authenticates with the kubelet client certificate,
+// returning the name of the node that the certificate was issued to.
+func buildClient(config *Config) (*kopscontrollerclient.Client, string, error) {
+	baseURL, err := url.Parse(config.Server)
+	if err != nil {
+		return nil, "", fmt.Errorf("parsing server %q: %w", config.Server, err)
+	}
+
+	data, err := os.ReadFile(config.KubeConfig)
+	if err != nil {
+		return nil, "", err
+	}
+	kubeConfig := &kubeconfig.KubectlConfig{}
+	if err := yaml.Unmarshal(data, kubeConfig); err != nil {
+		return nil, "", fmt.Errorf("parsing %q: %w", config.KubeConfig, err)
+	}
+	if len(kubeConfig.Users) != 1 {
+		return nil, "", fmt.Errorf("expected one user in %q, found %d", config.KubeConfig, len(kubeConfig.Users))
+	}
+	user := kubeConfig.Users[0].User
+	clientCertificate, err := tls.X509KeyPair(user.ClientCertificateData, user.ClientKeyData)
+	if err != nil {
+		return nil, "", fmt.Errorf("loading client certificate from %q: %w", config.KubeConfig, err)
+	}
+
+	cert, err := x509.ParseCertificate(clientCertificate.Certificate[0])
+	if err != nil {
+		return nil, "", fmt.Errorf("parsing client certificate from %q: %w", config.KubeConfig, err)
+	}
+	nodeName, found := strings.CutPrefix(cert.Subject.CommonName, "system:node:")
+	if !found || nodeName == "" {
+		return nil, "", fmt.Errorf("client certificate %q is not a node certificate", cert.Subject.CommonName)
+	}
+
+	client := &kopscontrollerclient.Client{
+		CAs:               []byte(config.CA),
+		ClientCertificate: &clientCertificate,
+		BaseURL:           *baseURL,
+	}
+	return client, nodeName, nil
+}
+
+func nodesClient(kubeconfigPath string) (corev1client.NodeInterface, error) {
+	restConfig, err := clientcmd.BuildConfigFromFlags("", kubeconfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("loading %q: %w", kubeconfigPath, err)
+	}
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("building kubernetes client: %w", err)
+	}
+	return clientset.CoreV1().Nodes(), nil
+}
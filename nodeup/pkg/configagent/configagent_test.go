@@ -0,0 +1,196 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configagent
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/nodeup"
+	"k8s.io/kops/pkg/kubeconfig"
+	"k8s.io/kops/pkg/pki"
+)
+
+type testKeystore struct {
+	cert *pki.Certificate
+	key  *pki.PrivateKey
+}
+
+func (k *testKeystore) FindPrimaryKeypair(ctx context.Context, name string) (*pki.Certificate, *pki.PrivateKey, error) {
+	return k.cert, k.key, nil
+}
+
+func TestAgentPoll(t *testing.T) {
+	ctx := context.Background()
+
+	caCert, caKey, _, err := pki.IssueCert(ctx, &pki.IssueCertRequest{
+		Type:    "ca",
+		Subject: pkix.Name{CommonName: "kubernetes-ca"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("issuing CA: %v", err)
+	}
+	kubeletCert, kubeletKey, _, err := pki.IssueCert(ctx, &pki.IssueCertRequest{
+		Signer:  "kubernetes-ca",
+		Type:    "client",
+		Subject: pkix.Name{CommonName: "system:node:node1", Organization: []string{"system:nodes"}},
+	}, &testKeystore{cert: caCert, key: caKey})
+	if err != nil {
+		t.Fatalf("issuing kubelet certificate: %v", err)
+	}
+	certPEM, _ := kubeletCert.AsBytes()
+	keyPEM, _ := kubeletKey.AsBytes()
+
+	kubeconfigPath := filepath.Join(t.TempDir(), "kubeconfig")
+	kubeconfigData, err := kops.ToRawYaml(&kubeconfig.KubectlConfig{
+		ApiVersion: "v1",
+		Kind:       "Config",
+		Users: []*kubeconfig.KubectlUserWithName{{
+			Name: "kubelet",
+			User: kubeconfig.KubectlUser{
+				ClientCertificateData: certPEM,
+				ClientKeyData:         keyPEM,
+			},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("encoding kubeconfig: %v", err)
+	}
+	if err := os.WriteFile(kubeconfigPath, kubeconfigData, 0o400); err != nil {
+		t.Fatalf("writing kubeconfig: %v", err)
+	}
+
+	// A fake kops-controller, which requires the kubelet client certificate
+	var requests []*nodeup.NodeConfigRequest
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/node-config" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		if cn := r.TLS.PeerCertificates[0].Subject.CommonName; cn != "system:node:node1" {
+			t.Errorf("unexpected client certificate %q", cn)
+		}
+		req := &nodeup.NodeConfigRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			t.Errorf("decoding request: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		requests = append(requests, req)
+
+		resp := &nodeup.NodeConfigResponse{Generation: "gen2"}
+		if req.Generation != resp.Generation {
+			resp.NodeConfig = &nodeup.NodeConfig{NodeupConfig: "config2"}
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(caCert.Certificate)
+	server.TLS = &tls.Config{ClientCAs: clientCAs, ClientAuth: tls.RequireAndVerifyClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	config := &Config{
+		Server:     server.URL,
+		CA:         string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})),
+		KubeConfig: kubeconfigPath,
+	}
+
+	clientset := fake.NewClientset(&corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "node1",
+			Annotations: map[string]string{GenerationAnnotation: "gen1"},
+		},
+	})
+	var applied []string
+	newAgent := func() *Agent {
+		agent := NewAgent("", func(ctx context.Context, nodeConfig *nodeup.NodeConfig) error {
+			applied = append(applied, nodeConfig.NodeupConfig)
+			return nil
+		})
+		agent.nodesClient = func(string) (corev1client.NodeInterface, error) {
+			return clientset.CoreV1().Nodes(), nil
+		}
+		return agent
+	}
+
+	// The changed configuration is applied, and recorded on the node
+	agent := newAgent()
+	if err := agent.Poll(ctx, config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(requests) != 1 || requests[0].Generation != "gen1" {
+		t.Errorf("expected a request for changes since gen1, got %v", requests)
+	}
+	if len(applied) != 1 || applied[0] != "config2" {
+		t.Errorf("expected config2 to be applied, got %v", applied)
+	}
+	node, err := clientset.CoreV1().Nodes().Get(ctx, "node1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("getting node: %v", err)
+	}
+	if got := node.Annotations[GenerationAnnotation]; got != "gen2" {
+		t.Errorf("expected generation gen2 on the node, got %q", got)
+	}
+
+	// An unchanged configuration is not applied again, even after a restart
+	if err := agent.Poll(ctx, config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := newAgent().Poll(ctx, config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(requests) != 3 || requests[1].Generation != "gen2" || requests[2].Generation != "gen2" {
+		t.Errorf("expected requests for changes since gen2, got %v", requests)
+	}
+	if len(applied) != 1 {
+		t.Errorf("expected no further configuration to be applied, got %v", applied)
+	}
+}
+
+func TestLoadConfigMissing(t *testing.T) {
+	config, err := LoadConfig(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config != nil {
+		t.Errorf("expected no config, got %v", config)
+	}
+}
+
+func TestPollInterval(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		d := pollInterval(time.Minute)
+		if d < 30*time.Second || d > 90*time.Second {
+			t.Fatalf("expected a poll interval between 30s and 90s, got %v", d)
+		}
+	}
+}
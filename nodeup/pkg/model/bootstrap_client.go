@@ -20,11 +20,17 @@ import (
 	"fmt"
 	"net"
 	"net/url"
+	"os"
+	"path/filepath"
 	"strconv"
 
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/kops/nodeup/pkg/configagent"
+	"k8s.io/kops/nodeup/pkg/renewal"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/bootstrap"
 	"k8s.io/kops/pkg/bootstrap/pkibootstrap"
+	"k8s.io/kops/pkg/bootstrap/tpmbootstrap/tpmsigner"
 	"k8s.io/kops/pkg/kopscontrollerclient"
 	"k8s.io/kops/pkg/wellknownports"
 	"k8s.io/kops/upup/pkg/fi"
@@ -48,89 +54,199 @@ func (b BootstrapClientBuilder) Build(c *fi.NodeupModelBuilderContext) error {
 		return nil
 	}
 
+	// Nodes using TPM attestation get their authenticator once the client is built.
 	var authenticator bootstrap.Authenticator
+	if !b.NodeupConfig.UseTPMAttestation {
+		a, err := b.cloudAuthenticator(c)
+		if err != nil {
+			return err
+		}
+		authenticator = a
+	}
+
+	baseURL := url.URL{
+		Scheme: "https",
+		Host:   net.JoinHostPort("kops-controller.internal."+b.NodeupConfig.ClusterName, strconv.Itoa(wellknownports.KopsControllerPort)),
+		Path:   "/",
+	}
+
+	bootstrapClient := &kopscontrollerclient.Client{
+		Authenticator: authenticator,
+		CAs:           []byte(b.NodeupConfig.CAs[fi.CertificateIDCA]),
+		BaseURL:       baseURL,
+	}
+
+	if b.NodeupConfig.UseTPMAttestation {
+		// The TPM authenticator asks kops-controller for a credential to activate.
+		hostname, err := os.Hostname()
+		if err != nil {
+			return fmt.Errorf("couldn't determine hostname: %w", err)
+		}
+		a, err := tpmsigner.NewAuthenticator(hostname, bootstrapClient)
+		if err != nil {
+			return err
+		}
+		bootstrapClient.Authenticator = a
+	}
+
+	bootstrapClientTask := &nodetasks.BootstrapClientTask{
+		Client:     bootstrapClient,
+		Certs:      b.bootstrapCerts,
+		KeypairIDs: b.bootstrapKeypairIDs,
+	}
+	bootstrapClientTask.UseChallengeCallback = b.UseChallengeCallback(b.CloudProvider())
+	bootstrapClientTask.ClusterName = b.NodeupConfig.ClusterName
+
+	for _, cert := range b.bootstrapCerts {
+		cert.Cert.Task = bootstrapClientTask
+		cert.Key.Task = bootstrapClientTask
+	}
+
+	c.AddTask(bootstrapClientTask)
 
+	if err := b.buildCertificateRenewal(c, baseURL); err != nil {
+		return err
+	}
+	return b.buildConfigAgent(c, baseURL)
+}
+
+// cloudAuthenticator returns the authenticator that proves the node's identity with the cloud provider.
+func (b BootstrapClientBuilder) cloudAuthenticator(c *fi.NodeupModelBuilderContext) (bootstrap.Authenticator, error) {
 	switch b.CloudProvider() {
 	case kops.CloudProviderAWS:
 		a, err := awsup.NewAWSAuthenticator(c.Context(), b.Cloud.Region())
 		if err != nil {
-			return err
+			return nil, err
 		}
-		authenticator = a
+		return a, nil
 	case kops.CloudProviderGCE:
 		a, err := gcetpmsigner.NewTPMAuthenticator()
 		if err != nil {
-			return err
+			return nil, err
 		}
-		authenticator = a
+		return a, nil
 	case kops.CloudProviderHetzner:
 		a, err := hetzner.NewHetznerAuthenticator()
 		if err != nil {
-			return err
+			return nil, err
 		}
-		authenticator = a
+		return a, nil
 	case kops.CloudProviderOpenstack:
 		a, err := openstack.NewOpenstackAuthenticator()
 		if err != nil {
-			return err
+			return nil, err
 		}
-		authenticator = a
+		return a, nil
 	case kops.CloudProviderDO:
 		a, err := do.NewAuthenticator()
 		if err != nil {
-			return err
+			return nil, err
 		}
-		authenticator = a
+		return a, nil
 	case kops.CloudProviderScaleway:
 		a, err := scaleway.NewScalewayAuthenticator()
 		if err != nil {
-			return err
+			return nil, err
 		}
-		authenticator = a
+		return a, nil
 	case kops.CloudProviderAzure:
 		a, err := azure.NewAzureAuthenticator()
 		if err != nil {
-			return err
+			return nil, err
 		}
-		authenticator = a
+		return a, nil
 
 	case kops.CloudProviderMetal:
-		a, err := pkibootstrap.NewAuthenticatorFromFile("/etc/kubernetes/kops/pki/machine/private.pem")
+		a, err := pkibootstrap.NewMachineAuthenticator()
 		if err != nil {
-			return err
+			return nil, err
 		}
-		authenticator = a
+		return a, nil
 
 	default:
-		return fmt.Errorf("unsupported cloud provider for authenticator %q", b.CloudProvider())
+		return nil, fmt.Errorf("unsupported cloud provider for authenticator %q", b.CloudProvider())
 	}
+}
 
-	baseURL := url.URL{
-		Scheme: "https",
-		Host:   net.JoinHostPort("kops-controller.internal."+b.NodeupConfig.ClusterName, strconv.Itoa(wellknownports.KopsControllerPort)),
-		Path:   "/",
+// buildCertificateRenewal writes the configuration used by "nodeup --renew-certificates"
+// to renew the certificates obtained from kops-controller before they expire.
+func (b BootstrapClientBuilder) buildCertificateRenewal(c *fi.NodeupModelBuilderContext, baseURL url.URL) error {
+	if _, found := b.bootstrapCerts["kubelet"]; !found {
+		// The node authenticates renewals with its kubelet certificate.
+		return nil
 	}
 
-	bootstrapClient := &kopscontrollerclient.Client{
-		Authenticator: authenticator,
-		CAs:           []byte(b.NodeupConfig.CAs[fi.CertificateIDCA]),
-		BaseURL:       baseURL,
+	config := &renewal.Config{
+		Server:     baseURL.String(),
+		CA:         b.NodeupConfig.CAs[fi.CertificateIDCA],
+		KeypairIDs: b.bootstrapKeypairIDs,
+	}
+	for _, name := range sets.List(sets.KeySet(b.bootstrapCerts)) {
+		cert := renewal.Certificate{Name: name}
+		switch name {
+		case "kubelet":
+			cert.KubeConfig = b.KubeletKubeConfig()
+			cert.Services = []string{kubeletService}
+		case "kubelet-server":
+			cert.CertPath = filepath.Join(b.PathSrvKubernetes(), name+".crt")
+			cert.KeyPath = filepath.Join(b.PathSrvKubernetes(), name+".key")
+			cert.Services = []string{kubeletService}
+		case "kube-proxy":
+			cert.KubeConfig = "/var/lib/kube-proxy/kubeconfig"
+			cert.Containers = []string{"kube-proxy"}
+		case "kube-router":
+			cert.KubeConfig = "/var/lib/kube-router/kubeconfig"
+			cert.Containers = []string{"kube-router"}
+		case "etcd-client-cilium":
+			cert.CertPath = filepath.Join("/etc/kubernetes/pki/cilium", name+".crt")
+			cert.KeyPath = filepath.Join("/etc/kubernetes/pki/cilium", name+".key")
+			cert.Containers = []string{"cilium-agent"}
+		default:
+			return fmt.Errorf("unable to renew unknown bootstrap certificate %q", name)
+		}
+		config.Certificates = append(config.Certificates, cert)
 	}
 
-	bootstrapClientTask := &nodetasks.BootstrapClientTask{
-		Client:     bootstrapClient,
-		Certs:      b.bootstrapCerts,
-		KeypairIDs: b.bootstrapKeypairIDs,
+	data, err := kops.ToRawYaml(config)
+	if err != nil {
+		return fmt.Errorf("error marshaling certificate renewal config: %w", err)
 	}
-	bootstrapClientTask.UseChallengeCallback = b.UseChallengeCallback(b.CloudProvider())
-	bootstrapClientTask.ClusterName = b.NodeupConfig.ClusterName
+	c.AddTask(&nodetasks.File{
+		Path:     renewal.ConfigPath,
+		Contents: fi.NewBytesResource(data),
+		Type:     nodetasks.FileType_File,
+		Mode:     fi.PtrTo("0600"),
+	})
+	return nil
+}
 
-	for _, cert := range b.bootstrapCerts {
-		cert.Cert.Task = bootstrapClientTask
-		cert.Key.Task = bootstrapClientTask
+// buildConfigAgent writes the configuration used by "nodeup --watch-config",
+// which applies changes to the node configuration without replacing the node.
+func (b BootstrapClientBuilder) buildConfigAgent(c *fi.NodeupModelBuilderContext, baseURL url.URL) error {
+	if b.NodeupConfig.NodeConfigAgentInterval == nil {
+		return nil
+	}
+	if _, found := b.bootstrapCerts["kubelet"]; !found {
+		// The agent authenticates with the kubelet certificate.
+		return nil
 	}
 
-	c.AddTask(bootstrapClientTask)
+	config := &configagent.Config{
+		Server:     baseURL.String(),
+		CA:         b.NodeupConfig.CAs[fi.CertificateIDCA],
+		KubeConfig: b.KubeletKubeConfig(),
+		Interval:   *b.NodeupConfig.NodeConfigAgentInterval,
+	}
+	data, err := kops.ToRawYaml(config)
+	if err != nil {
+		return fmt.Errorf("error marshaling node configuration agent config: %w", err)
+	}
+	c.AddTask(&nodetasks.File{
+		Path:     configagent.ConfigPath,
+		Contents: fi.NewBytesResource(data),
+		Type:     nodetasks.FileType_File,
+		Mode:     fi.PtrTo("0600"),
+	})
 	return nil
 }
 
@@ -435,6 +435,24 @@ func (c *NodeupModelContext) buildCertificatePairTask(ctx *fi.NodeupModelBuilder
 	return nil
 }
 
+// PrivateKeyReference returns the URI of the private key of the keypair used by the node,
+// if the key is held in a PKCS#11 module or a cloud KMS rather than by kops.
+func (c *NodeupModelContext) PrivateKeyReference(ctx context.Context, name string) (string, error) {
+	keyset, err := c.KeyStore.FindKeyset(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	if keyset == nil {
+		return "", fmt.Errorf("keyset %q not found", name)
+	}
+
+	item := keyset.Items[c.NodeupConfig.KeypairIDs[name]]
+	if item == nil || item.PrivateKey == nil {
+		return "", nil
+	}
+	return item.PrivateKey.Reference(), nil
+}
+
 // BuildCertificateTask builds a task to create a certificate file.
 func (c *NodeupModelContext) BuildCertificateTask(ctx *fi.NodeupModelBuilderContext, name, filename string, owner *string) error {
 	keyset, err := c.KeyStore.FindKeyset(ctx.Context(), name)
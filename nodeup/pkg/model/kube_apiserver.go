@@ -506,6 +506,17 @@ func (b *KubeAPIServerBuilder) writeServerCertificate(c *fi.NodeupModelBuilderCo
 		}
 	}
 
+	// Admin credentials are signed by a separate CA, which is trusted alongside the cluster CA
+	if adminCA := b.NodeupConfig.CAs[fi.CertificateIDAdminCA]; adminCA != "" && kubeAPIServer.ClientCAFile == "" {
+		c.AddTask(&nodetasks.File{
+			Path:     filepath.Join(pathSrvKAPI, "client-ca.crt"),
+			Contents: fi.NewStringResource(b.NodeupConfig.CAs[fi.CertificateIDCA] + adminCA),
+			Type:     nodetasks.FileType_File,
+			Mode:     fi.PtrTo("0644"),
+		})
+		kubeAPIServer.ClientCAFile = filepath.Join(pathSrvKAPI, "client-ca.crt")
+	}
+
 	// If clientCAFile is not specified, set it to the default value ${PathSrvKubernetes}/ca.crt
 	if kubeAPIServer.ClientCAFile == "" {
 		kubeAPIServer.ClientCAFile = filepath.Join(b.PathSrvKubernetes(), "ca.crt")
@@ -21,6 +21,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/flagbuilder"
 	"k8s.io/kops/pkg/k8scodecs"
@@ -55,11 +56,20 @@ func (b *KubeControllerManagerBuilder) Build(c *fi.NodeupModelBuilderContext) er
 	kcm := b.NodeupConfig.ControlPlaneConfig.KubeControllerManager
 	kcm.RootCAFile = filepath.Join(b.PathSrvKubernetes(), "ca.crt")
 
-	// Include the CA Key
-	// @TODO: use a per-machine key?  use KMS?
-	if err := b.BuildCertificatePairTask(c, fi.CertificateIDCA, pathSrvKCM, "ca", nil, nil); err != nil {
+	// Include the CA Key, so that kube-controller-manager signs certificate signing requests.
+	// A CA key held in a PKCS#11 module or a cloud KMS cannot be used by kube-controller-manager.
+	caKeyReference, err := b.PrivateKeyReference(c.Context(), fi.CertificateIDCA)
+	if err != nil {
 		return err
 	}
+	clusterSigning := caKeyReference == ""
+	if clusterSigning {
+		if err := b.BuildCertificatePairTask(c, fi.CertificateIDCA, pathSrvKCM, "ca", nil, nil); err != nil {
+			return err
+		}
+	} else {
+		klog.Warningf("CA key is held in %q, kube-controller-manager will not sign certificate signing requests", caKeyReference)
+	}
 
 	if err := b.BuildPrivateKeyTask(c, "service-account", pathSrvKCM, "service-account", nil, nil); err != nil {
 		return err
@@ -71,7 +81,7 @@ func (b *KubeControllerManagerBuilder) Build(c *fi.NodeupModelBuilderContext) er
 	}
 
 	{
-		pod, err := b.buildPod(&kcm)
+		pod, err := b.buildPod(&kcm, clusterSigning)
 		if err != nil {
 			return fmt.Errorf("error building kube-controller-manager pod: %v", err)
 		}
@@ -143,7 +153,8 @@ func (b *KubeControllerManagerBuilder) writeServerCertificate(c *fi.NodeupModelB
 }
 
 // buildPod is responsible for building the kubernetes manifest for the controller-manager
-func (b *KubeControllerManagerBuilder) buildPod(kcm *kops.KubeControllerManagerConfig) (*v1.Pod, error) {
+// If clusterSigning is set, kube-controller-manager signs certificate signing requests with the cluster CA.
+func (b *KubeControllerManagerBuilder) buildPod(kcm *kops.KubeControllerManagerConfig, clusterSigning bool) (*v1.Pod, error) {
 	pathSrvKCM := filepath.Join(b.PathSrvKubernetes(), "kube-controller-manager")
 
 	flags, err := flagbuilder.BuildFlagsList(kcm)
@@ -159,10 +170,12 @@ func (b *KubeControllerManagerBuilder) buildPod(kcm *kops.KubeControllerManagerC
 	}
 
 	// Configure CA certificate to be used to sign keys
-	flags = append(flags, []string{
-		"--cluster-signing-cert-file=" + filepath.Join(pathSrvKCM, "ca.crt"),
-		"--cluster-signing-key-file=" + filepath.Join(pathSrvKCM, "ca.key"),
-	}...)
+	if clusterSigning {
+		flags = append(flags, []string{
+			"--cluster-signing-cert-file=" + filepath.Join(pathSrvKCM, "ca.crt"),
+			"--cluster-signing-key-file=" + filepath.Join(pathSrvKCM, "ca.key"),
+		}...)
+	}
 
 	pod := &v1.Pod{
 		TypeMeta: metav1.TypeMeta{
@@ -0,0 +1,85 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"k8s.io/kops/pkg/wellknownusers"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+// MonitoringBuilder installs the client certificates used by the monitoring agent to scrape the control plane.
+type MonitoringBuilder struct {
+	*NodeupModelContext
+}
+
+var _ fi.NodeupModelBuilder = &MonitoringBuilder{}
+
+// Build is responsible for configuring keys that will be used by the monitoring agent (via hostPath)
+func (b *MonitoringBuilder) Build(c *fi.NodeupModelBuilderContext) error {
+	if b.NodeupConfig.ControlPlaneConfig == nil || !b.NodeupConfig.ControlPlaneConfig.EnableMonitoring {
+		return nil
+	}
+
+	secretsDir := "/etc/kubernetes/kops-monitoring/secrets"
+	userName := wellknownusers.KopsMonitoringName
+
+	// We run the monitoring agent under an unprivileged user (wellknownusers.KopsMonitoringID),
+	// which owns the certificates it needs to read.
+	c.AddTask(&nodetasks.UserTask{
+		Name:  userName,
+		UID:   wellknownusers.KopsMonitoringID,
+		Shell: "/sbin/nologin",
+		Home:  secretsDir,
+	})
+
+	// The client certificate for kube-apiserver, which is bound to the metrics ClusterRole in the addon
+	{
+		issueCert := &nodetasks.IssueCert{
+			Name:      "kops-monitoring",
+			Signer:    fi.CertificateIDCA,
+			KeypairID: b.NodeupConfig.KeypairIDs[fi.CertificateIDCA],
+			Type:      "client",
+			Subject: nodetasks.PKIXName{
+				CommonName: "kops-monitoring",
+			},
+		}
+		c.AddTask(issueCert)
+		if err := issueCert.AddFileTasks(c, secretsDir, "client", "ca", s(userName)); err != nil {
+			return err
+		}
+	}
+
+	// The client certificate for etcd
+	{
+		issueCert := &nodetasks.IssueCert{
+			Name:      "kops-monitoring-etcd",
+			Signer:    "etcd-clients-ca",
+			KeypairID: b.NodeupConfig.KeypairIDs["etcd-clients-ca"],
+			Type:      "client",
+			Subject: nodetasks.PKIXName{
+				CommonName: "kops-monitoring",
+			},
+		}
+		c.AddTask(issueCert)
+		if err := issueCert.AddFileTasks(c, secretsDir, "etcd-client", "etcd-ca", s(userName)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
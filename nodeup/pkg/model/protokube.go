@@ -35,6 +35,9 @@ import (
 	"k8s.io/kops/util/pkg/proxy"
 )
 
+// channelsVerifyKeysPath holds the public keys trusted to sign channels and addon manifests
+const channelsVerifyKeysPath = "/etc/kubernetes/kops/channels-verify-keys.pem"
+
 // ProtokubeBuilder configures protokube
 type ProtokubeBuilder struct {
 	*NodeupModelContext
@@ -93,6 +96,15 @@ func (t *ProtokubeBuilder) Build(c *fi.NodeupModelBuilderContext) error {
 		})
 	}
 
+	if t.NodeupConfig.ChannelsVerifyKeys != "" {
+		c.AddTask(&nodetasks.File{
+			Path:     channelsVerifyKeysPath,
+			Contents: fi.NewStringResource(t.NodeupConfig.ChannelsVerifyKeys),
+			Type:     nodetasks.FileType_File,
+			Mode:     s("0444"),
+		})
+	}
+
 	envFile, err := t.buildEnvFile()
 	if err != nil {
 		return err
@@ -145,15 +157,16 @@ func (t *ProtokubeBuilder) buildSystemdService() (*nodetasks.Service, error) {
 
 // ProtokubeFlags are the flags for protokube
 type ProtokubeFlags struct {
-	ClusterID         *string  `json:"clusterID,omitempty" flag:"cluster-id"`
-	Channels          []string `json:"channels,omitempty" flag:"channels"`
-	Cloud             *string  `json:"cloud,omitempty" flag:"cloud"`
-	Containerized     *bool    `json:"containerized,omitempty" flag:"containerized"`
-	DNSInternalSuffix *string  `json:"dnsInternalSuffix,omitempty" flag:"dns-internal-suffix"`
-	Gossip            *bool    `json:"gossip,omitempty" flag:"gossip"`
-	LogLevel          *int32   `json:"logLevel,omitempty" flag:"v"`
-	Master            *bool    `json:"master,omitempty" flag:"master"`
-	Zone              []string `json:"zone,omitempty" flag:"zone"`
+	ClusterID          *string  `json:"clusterID,omitempty" flag:"cluster-id"`
+	Channels           []string `json:"channels,omitempty" flag:"channels"`
+	ChannelsVerifyKeys *string  `json:"channelsVerifyKeys,omitempty" flag:"channels-verify-keys"`
+	Cloud              *string  `json:"cloud,omitempty" flag:"cloud"`
+	Containerized      *bool    `json:"containerized,omitempty" flag:"containerized"`
+	DNSInternalSuffix  *string  `json:"dnsInternalSuffix,omitempty" flag:"dns-internal-suffix"`
+	Gossip             *bool    `json:"gossip,omitempty" flag:"gossip"`
+	LogLevel           *int32   `json:"logLevel,omitempty" flag:"v"`
+	Master             *bool    `json:"master,omitempty" flag:"master"`
+	Zone               []string `json:"zone,omitempty" flag:"zone"`
 
 	// BootstrapMasterNodeLabels applies the critical node-role labels to our node,
 	// which lets us bring up the controllers that can only run on masters, which are then
@@ -184,6 +197,10 @@ func (t *ProtokubeBuilder) ProtokubeFlags() (*ProtokubeFlags, error) {
 
 	f.ClusterID = fi.PtrTo(t.NodeupConfig.ClusterName)
 
+	if t.NodeupConfig.ChannelsVerifyKeys != "" {
+		f.ChannelsVerifyKeys = fi.PtrTo(channelsVerifyKeysPath)
+	}
+
 	zone := t.NodeupConfig.DNSZone
 	if zone != "" {
 		if strings.Contains(zone, ".") {
@@ -0,0 +1,383 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package renewal renews the certificates that a node obtained from kops-controller when it bootstrapped.
+// The node authenticates to kops-controller with its current kubelet client certificate,
+// so renewal does not depend on the node's cloud identity.
+package renewal
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/nodeup"
+	"k8s.io/kops/pkg/kopscontrollerclient"
+	"k8s.io/kops/pkg/kubeconfig"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/upup/pkg/fi"
+	"sigs.k8s.io/yaml"
+)
+
+// ConfigPath is the location of the renewal configuration written by nodeup.
+const ConfigPath = "/etc/kubernetes/kops/cert-renewal.yaml"
+
+// DefaultRenewBefore is how long before expiry certificates are renewed by default.
+const DefaultRenewBefore = 30 * 24 * time.Hour
+
+// identityCertificate is the certificate the node authenticates with.
+const identityCertificate = "kubelet"
+
+// Config describes the certificates to renew, and where to find them.
+type Config struct {
+	// Server is the base URL of kops-controller.
+	Server string `json:"server"`
+	// CA is the CA certificate bundle for kops-controller.
+	CA string `json:"ca"`
+	// KeypairIDs are the keypair IDs of the CAs to use for issuing certificates.
+	KeypairIDs map[string]string `json:"keypairIDs,omitempty"`
+	// Certificates are the certificates to renew.
+	Certificates []Certificate `json:"certificates"`
+}
+
+// Certificate is a certificate issued by kops-controller, stored either in a kubeconfig or in a pair of PEM files.
+type Certificate struct {
+	// Name is the name of the certificate in the bootstrap protocol, e.g. "kubelet".
+	Name string `json:"name"`
+	// KubeConfig is the path of a kubeconfig with the certificate and key embedded.
+	KubeConfig string `json:"kubeConfig,omitempty"`
+	// CertPath is the path of the PEM-encoded certificate, if KubeConfig is not set.
+	CertPath string `json:"certPath,omitempty"`
+	// KeyPath is the path of the PEM-encoded private key, if KubeConfig is not set.
+	KeyPath string `json:"keyPath,omitempty"`
+	// Services are the systemd units to restart once the certificate has been replaced.
+	Services []string `json:"services,omitempty"`
+	// Containers are the names of the containers to stop once the certificate has been replaced, so that the kubelet restarts them.
+	Containers []string `json:"containers,omitempty"`
+}
+
+// LoadConfig reads the renewal configuration, returning nil if there is none.
+func LoadConfig(p string) (*Config, error) {
+	data, err := os.ReadFile(p)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading %q: %w", p, err)
+	}
+	config := &Config{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("parsing %q: %w", p, err)
+	}
+	return config, nil
+}
+
+// Renewer renews the node's certificates when they are close to expiry.
+type Renewer struct {
+	Config *Config
+	// RenewBefore is how long before expiry the certificates are renewed.
+	RenewBefore time.Duration
+
+	// now and runCommand can be replaced in tests.
+	now        func() time.Time
+	runCommand func(ctx context.Context, args ...string) (string, error)
+}
+
+// NewRenewer builds a Renewer for the configuration.
+func NewRenewer(config *Config, renewBefore time.Duration) *Renewer {
+	return &Renewer{
+		Config:      config,
+		RenewBefore: renewBefore,
+		now:         time.Now,
+		runCommand:  runCommand,
+	}
+}
+
+// current is a certificate and key as currently found on disk.
+type current struct {
+	cert *pki.Certificate
+	key  *pki.PrivateKey
+}
+
+// Run renews the certificates if any of them expires within RenewBefore.
+// All the certificates are renewed together, so that they continue to expire around the same time.
+func (r *Renewer) Run(ctx context.Context) error {
+	certs := make(map[string]*current)
+	renew := false
+	for _, c := range r.Config.Certificates {
+		cur, err := c.read()
+		if err != nil {
+			return fmt.Errorf("reading %q certificate: %w", c.Name, err)
+		}
+		certs[c.Name] = cur
+
+		notAfter := cur.cert.Certificate.NotAfter
+		if r.now().Add(r.RenewBefore).After(notAfter) {
+			klog.Infof("certificate %q expires at %v; renewing", c.Name, notAfter)
+			renew = true
+		}
+	}
+	if !renew {
+		klog.Infof("no certificates due for renewal")
+		return nil
+	}
+
+	identity, ok := certs[identityCertificate]
+	if !ok {
+		return fmt.Errorf("cannot renew certificates without a %q certificate", identityCertificate)
+	}
+	if r.now().After(identity.cert.Certificate.NotAfter) {
+		return fmt.Errorf("the %q certificate expired at %v; the node must be replaced or bootstrapped again", identityCertificate, identity.cert.Certificate.NotAfter)
+	}
+
+	client, err := r.buildClient(identity)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	req := &nodeup.RenewRequest{
+		APIVersion: nodeup.BootstrapAPIVersion,
+		Certs:      map[string]string{},
+		KeypairIDs: r.Config.KeypairIDs,
+		Current:    map[string]string{},
+	}
+	keys := make(map[string]*pki.PrivateKey)
+	for _, c := range r.Config.Certificates {
+		key, err := pki.GeneratePrivateKey()
+		if err != nil {
+			return fmt.Errorf("generating private key: %w", err)
+		}
+		keys[c.Name] = key
+
+		pkData, err := x509.MarshalPKIXPublicKey(key.Key.Public())
+		if err != nil {
+			return fmt.Errorf("marshalling public key: %w", err)
+		}
+		req.Certs[c.Name] = string(pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: pkData}))
+
+		if c.Name == "kubelet-server" {
+			req.Current[c.Name], err = certs[c.Name].cert.AsString()
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	resp := &nodeup.BootstrapResponse{}
+	if err := client.Renew(ctx, req, resp); err != nil {
+		return fmt.Errorf("renewing certificates: %w", err)
+	}
+
+	// Parse everything before writing anything, so that we don't leave a partial renewal behind.
+	renewed := make(map[string]*pki.Certificate)
+	for _, c := range r.Config.Certificates {
+		data, ok := resp.Certs[c.Name]
+		if !ok {
+			return fmt.Errorf("kops-controller did not return a %q certificate", c.Name)
+		}
+		cert, err := pki.ParsePEMCertificate([]byte(data))
+		if err != nil {
+			return fmt.Errorf("parsing %q certificate: %w", c.Name, err)
+		}
+		renewed[c.Name] = cert
+	}
+
+	var errs []error
+	for _, c := range r.Config.Certificates {
+		if err := c.write(renewed[c.Name], keys[c.Name]); err != nil {
+			errs = append(errs, fmt.Errorf("writing %q certificate: %w", c.Name, err))
+			continue
+		}
+		klog.Infof("renewed certificate %q, now expiring at %v", c.Name, renewed[c.Name].Certificate.NotAfter)
+	}
+
+	// We restart the consumers even if some certificates could not be written, so that the renewed ones are used.
+	if err := r.restart(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+func (r *Renewer) buildClient(identity *current) (*kopscontrollerclient.Client, error) {
+	baseURL, err := url.Parse(r.Config.Server)
+	if err != nil {
+		return nil, fmt.Errorf("parsing server %q: %w", r.Config.Server, err)
+	}
+
+	certPEM, err := identity.cert.AsBytes()
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := identity.key.AsBytes()
+	if err != nil {
+		return nil, err
+	}
+	clientCertificate, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("loading %q certificate: %w", identityCertificate, err)
+	}
+
+	return &kopscontrollerclient.Client{
+		CAs:               []byte(r.Config.CA),
+		ClientCertificate: &clientCertificate,
+		BaseURL:           *baseURL,
+	}, nil
+}
+
+// restart restarts the services and containers that use the renewed certificates.
+func (r *Renewer) restart(ctx context.Context) error {
+	var services, containers []string
+	for _, c := range r.Config.Certificates {
+		for _, s := range c.Services {
+			if !fi.ArrayContains(services, s) {
+				services = append(services, s)
+			}
+		}
+		for _, s := range c.Containers {
+			if !fi.ArrayContains(containers, s) {
+				containers = append(containers, s)
+			}
+		}
+	}
+
+	var errs []error
+	for _, service := range services {
+		klog.Infof("restarting %s", service)
+		if _, err := r.runCommand(ctx, "systemctl", "restart", service); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, container := range containers {
+		out, err := r.runCommand(ctx, "crictl", "ps", "--quiet", "--name", container)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, id := range strings.Fields(out) {
+			klog.Infof("stopping container %s (%s)", container, id)
+			if _, err := r.runCommand(ctx, "crictl", "stop", id); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func runCommand(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("running %q: %w: %s", strings.Join(args, " "), err, out)
+	}
+	return string(out), nil
+}
+
+func (c *Certificate) read() (*current, error) {
+	var certPEM, keyPEM []byte
+	if c.KubeConfig != "" {
+		config, err := readKubeConfig(c.KubeConfig)
+		if err != nil {
+			return nil, err
+		}
+		if len(config.Users) != 1 {
+			return nil, fmt.Errorf("expected one user in %q, found %d", c.KubeConfig, len(config.Users))
+		}
+		certPEM = config.Users[0].User.ClientCertificateData
+		keyPEM = config.Users[0].User.ClientKeyData
+	} else {
+		var err error
+		if certPEM, err = os.ReadFile(c.CertPath); err != nil {
+			return nil, err
+		}
+		if keyPEM, err = os.ReadFile(c.KeyPath); err != nil {
+			return nil, err
+		}
+	}
+
+	cert, err := pki.ParsePEMCertificate(certPEM)
+	if err != nil {
+		return nil, err
+	}
+	key, err := pki.ParsePEMPrivateKey(keyPEM)
+	if err != nil {
+		return nil, err
+	}
+	return &current{cert: cert, key: key}, nil
+}
+
+func (c *Certificate) write(cert *pki.Certificate, key *pki.PrivateKey) error {
+	certPEM, err := cert.AsBytes()
+	if err != nil {
+		return err
+	}
+	keyPEM, err := key.AsBytes()
+	if err != nil {
+		return err
+	}
+
+	if c.KubeConfig != "" {
+		config, err := readKubeConfig(c.KubeConfig)
+		if err != nil {
+			return err
+		}
+		config.Users[0].User.ClientCertificateData = certPEM
+		config.Users[0].User.ClientKeyData = keyPEM
+		data, err := kops.ToRawYaml(config)
+		if err != nil {
+			return fmt.Errorf("error marshaling kubeconfig to yaml: %w", err)
+		}
+		return replaceFile(c.KubeConfig, data)
+	}
+
+	// We write the key first; a key that doesn't match the certificate is detected by its consumers,
+	// whereas a certificate for a key that we then fail to write would look valid.
+	if err := replaceFile(c.KeyPath, keyPEM); err != nil {
+		return err
+	}
+	return replaceFile(c.CertPath, certPEM)
+}
+
+func readKubeConfig(p string) (*kubeconfig.KubectlConfig, error) {
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+	config := &kubeconfig.KubectlConfig{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("parsing %q: %w", p, err)
+	}
+	return config, nil
+}
+
+// replaceFile atomically replaces the contents of an existing file, keeping its mode.
+func replaceFile(p string, data []byte) error {
+	stat, err := os.Stat(p)
+	if err != nil {
+		return err
+	}
+	return fi.WriteFile(p, fi.NewBytesResource(data), stat.Mode().Perm(), 0o755, "", "")
+}
@@ -0,0 +1,256 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package renewal
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/nodeup"
+	"k8s.io/kops/pkg/kubeconfig"
+	"k8s.io/kops/pkg/pki"
+)
+
+type testKeystore struct {
+	cert *pki.Certificate
+	key  *pki.PrivateKey
+}
+
+func (k *testKeystore) FindPrimaryKeypair(ctx context.Context, name string) (*pki.Certificate, *pki.PrivateKey, error) {
+	return k.cert, k.key, nil
+}
+
+func newTestKeystore(t *testing.T) *testKeystore {
+	cert, key, _, err := pki.IssueCert(context.Background(), &pki.IssueCertRequest{
+		Type:    "ca",
+		Subject: pkix.Name{CommonName: "kubernetes-ca"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("issuing CA: %v", err)
+	}
+	return &testKeystore{cert: cert, key: key}
+}
+
+func issue(t *testing.T, keystore *testKeystore, req *pki.IssueCertRequest) (*pki.Certificate, *pki.PrivateKey) {
+	req.Signer = "kubernetes-ca"
+	cert, key, _, err := pki.IssueCert(context.Background(), req, keystore)
+	if err != nil {
+		t.Fatalf("issuing certificate: %v", err)
+	}
+	return cert, key
+}
+
+func asBytes(t *testing.T, v interface{ AsBytes() ([]byte, error) }) []byte {
+	b, err := v.AsBytes()
+	if err != nil {
+		t.Fatalf("encoding: %v", err)
+	}
+	return b
+}
+
+func TestRenewer(t *testing.T) {
+	ctx := context.Background()
+	keystore := newTestKeystore(t)
+	dir := t.TempDir()
+
+	// The node's current certificates, expiring in 20 days
+	validity := 20 * 24 * time.Hour
+	kubeletCert, kubeletKey := issue(t, keystore, &pki.IssueCertRequest{
+		Type:     "client",
+		Subject:  pkix.Name{CommonName: "system:node:node1", Organization: []string{"system:nodes"}},
+		Validity: validity,
+	})
+	serverCert, serverKey := issue(t, keystore, &pki.IssueCertRequest{
+		Type:           "server",
+		Subject:        pkix.Name{CommonName: "node1"},
+		AlternateNames: []string{"node1", "10.0.0.1"},
+		Validity:       validity,
+	})
+
+	kubeconfigPath := filepath.Join(dir, "kubeconfig")
+	kubeconfigData, err := kops.ToRawYaml(&kubeconfig.KubectlConfig{
+		ApiVersion: "v1",
+		Kind:       "Config",
+		Users: []*kubeconfig.KubectlUserWithName{{
+			Name: "kubelet",
+			User: kubeconfig.KubectlUser{
+				ClientCertificateData: asBytes(t, kubeletCert),
+				ClientKeyData:         asBytes(t, kubeletKey),
+			},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("encoding kubeconfig: %v", err)
+	}
+	if err := os.WriteFile(kubeconfigPath, kubeconfigData, 0o400); err != nil {
+		t.Fatalf("writing kubeconfig: %v", err)
+	}
+	certPath := filepath.Join(dir, "kubelet-server.crt")
+	keyPath := filepath.Join(dir, "kubelet-server.key")
+	if err := os.WriteFile(certPath, asBytes(t, serverCert), 0o644); err != nil {
+		t.Fatalf("writing certificate: %v", err)
+	}
+	if err := os.WriteFile(keyPath, asBytes(t, serverKey), 0o400); err != nil {
+		t.Fatalf("writing key: %v", err)
+	}
+
+	// A fake kops-controller, which requires the kubelet client certificate
+	requests := 0
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/renew" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		if cn := r.TLS.PeerCertificates[0].Subject.CommonName; cn != "system:node:node1" {
+			t.Errorf("unexpected client certificate %q", cn)
+		}
+
+		req := &nodeup.RenewRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			t.Errorf("decoding request: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if req.Current["kubelet-server"] == "" {
+			t.Errorf("current kubelet-server certificate not sent")
+		}
+
+		resp := &nodeup.BootstrapResponse{Certs: map[string]string{}}
+		for name, pubKey := range req.Certs {
+			block, _ := pem.Decode([]byte(pubKey))
+			key, err := x509.ParsePKIXPublicKey(block.Bytes)
+			if err != nil {
+				t.Errorf("parsing public key: %v", err)
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			cert, _ := issue(t, keystore, &pki.IssueCertRequest{
+				Type:      "client",
+				Subject:   pkix.Name{CommonName: name},
+				PublicKey: key,
+				Validity:  365 * 24 * time.Hour,
+			})
+			resp.Certs[name], _ = cert.AsString()
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(keystore.cert.Certificate)
+	server.TLS = &tls.Config{ClientCAs: clientCAs, ClientAuth: tls.RequireAndVerifyClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	config := &Config{
+		Server: server.URL,
+		CA:     string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})),
+		Certificates: []Certificate{
+			{
+				Name:       "kubelet",
+				KubeConfig: kubeconfigPath,
+				Services:   []string{"kubelet.service"},
+			},
+			{
+				Name:     "kubelet-server",
+				CertPath: certPath,
+				KeyPath:  keyPath,
+				Services: []string{"kubelet.service"},
+			},
+			{
+				Name:       "kube-proxy",
+				KubeConfig: kubeconfigPath,
+				Containers: []string{"kube-proxy"},
+			},
+		},
+	}
+
+	var commands []string
+	renewer := NewRenewer(config, 0)
+	renewer.runCommand = func(ctx context.Context, args ...string) (string, error) {
+		commands = append(commands, strings.Join(args, " "))
+		if args[0] == "crictl" && args[1] == "ps" {
+			return "abc123\n", nil
+		}
+		return "", nil
+	}
+
+	// Certificates are not renewed before they are due
+	renewer.RenewBefore = 10 * 24 * time.Hour
+	if err := renewer.Run(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requests != 0 || len(commands) != 0 {
+		t.Fatalf("expected no renewal, got %d requests and commands %v", requests, commands)
+	}
+
+	renewer.RenewBefore = DefaultRenewBefore
+	if err := renewer.Run(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("expected 1 request, got %d", requests)
+	}
+
+	wantCommands := []string{
+		"systemctl restart kubelet.service",
+		"crictl ps --quiet --name kube-proxy",
+		"crictl stop abc123",
+	}
+	if !reflect.DeepEqual(commands, wantCommands) {
+		t.Errorf("unexpected commands; got %v, want %v", commands, wantCommands)
+	}
+
+	renewed, err := (&Certificate{CertPath: certPath, KeyPath: keyPath}).read()
+	if err != nil {
+		t.Fatalf("reading renewed certificate: %v", err)
+	}
+	if renewed.cert.Subject.CommonName != "kubelet-server" {
+		t.Errorf("certificate was not replaced; subject is %q", renewed.cert.Subject.CommonName)
+	}
+	if !reflect.DeepEqual(renewed.cert.PublicKey, renewed.key.Key.Public()) {
+		t.Errorf("renewed certificate does not match the renewed key")
+	}
+	stat, err := os.Stat(keyPath)
+	if err != nil {
+		t.Fatalf("stat %q: %v", keyPath, err)
+	}
+	if stat.Mode().Perm() != 0o400 {
+		t.Errorf("expected key mode 0400, got %v", stat.Mode().Perm())
+	}
+}
+
+func TestLoadConfigMissing(t *testing.T) {
+	config, err := LoadConfig(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config != nil {
+		t.Errorf("expected no config, got %v", config)
+	}
+}
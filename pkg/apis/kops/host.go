@@ -28,7 +28,8 @@ type Host struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec HostSpec `json:"spec,omitempty"`
+	Spec   HostSpec   `json:"spec,omitempty"`
+	Status HostStatus `json:"status,omitempty"`
 }
 
 type HostSpec struct {
@@ -37,6 +38,22 @@ type HostSpec struct {
 
 	// PodCIDRs configures the IP ranges to be used for pods on this node/host.
 	PodCIDRs []string `json:"podCIDRs,omitempty"`
+
+	// TPM identifies the host's TPM, for hosts that authenticate with TPM attestation.
+	TPM *HostTPMSpec `json:"tpm,omitempty"`
+}
+
+// HostTPMSpec identifies the TPM of a host.
+type HostTPMSpec struct {
+	// EKPublicKeyHash is the hex-encoded SHA-256 hash of the DER-encoded (PKIX) public endorsement key.
+	EKPublicKeyHash string `json:"ekPublicKeyHash,omitempty"`
+}
+
+// HostStatus is the observed state of a host.
+type HostStatus struct {
+	// PodCIDRs are the IP ranges that kops-controller allocated for pods on this host,
+	// when spec.podCIDRs is not set. They are released when the Node is deleted.
+	PodCIDRs []string `json:"podCIDRs,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -39,6 +39,18 @@ type InstanceGroup struct {
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
 	Spec InstanceGroupSpec `json:"spec,omitempty"`
+
+	// Status is the observed state of the instance group.
+	// It is reported by kops-controller when it reconciles the cluster from the objects in the cluster, and is not kept in the state store.
+	Status *InstanceGroupStatus `json:"status,omitempty"`
+}
+
+// InstanceGroupStatus is the observed state of an instance group.
+type InstanceGroupStatus struct {
+	// ObservedGeneration is the generation of the instance group that was most recently reconciled.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions describe the progress of reconciling the instance group.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
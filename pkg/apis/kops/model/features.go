@@ -17,7 +17,10 @@ limitations under the License.
 package model
 
 import (
+	"strings"
+
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/featureflag"
 )
 
 // UseChallengeCallback is true if we should use a callback challenge during node provisioning with kops-controller.
@@ -67,6 +70,45 @@ func UseCiliumEtcd(cluster *kops.Cluster) bool {
 	return false
 }
 
+// UseMonitoring is true if the kOps-managed monitoring agent is enabled.
+func UseMonitoring(cluster *kops.Cluster) bool {
+	monitoring := cluster.Spec.Monitoring
+	return monitoring != nil && monitoring.Enabled != nil && *monitoring.Enabled
+}
+
+// UseMonitoringServiceMonitors is true if ServiceMonitors are installed for the components scraped by the monitoring agent.
+func UseMonitoringServiceMonitors(cluster *kops.Cluster) bool {
+	return UseMonitoring(cluster) && cluster.Spec.Monitoring.ServiceMonitors != nil && *cluster.Spec.Monitoring.ServiceMonitors
+}
+
+// BootstrapAuditStore returns the location in the state store that kops-controller writes bootstrap audit records to,
+// or "" if the records are only kept on the control plane nodes.
+func BootstrapAuditStore(cluster *kops.Cluster) string {
+	audit := cluster.Spec.BootstrapAudit
+	if audit == nil || !audit.StateStore || cluster.Spec.ConfigStore.Base == "" {
+		return ""
+	}
+	return strings.TrimSuffix(cluster.Spec.ConfigStore.Base, "/") + "/audit/bootstrap/"
+}
+
+// UseJoinTokens is true if kops-controller accepts join tokens, which it does whenever it verifies machines by their public keys.
+func UseJoinTokens(cluster *kops.Cluster) bool {
+	return featureflag.Metal.Enabled() || cluster.GetCloudProvider() == kops.CloudProviderMetal
+}
+
+// NodeConfigMaxUnavailable returns the maximum number of nodes that apply a change with the node configuration agent
+// at the same time, or 0 if the agent is not enabled.
+func NodeConfigMaxUnavailable(cluster *kops.Cluster) int {
+	agent := cluster.Spec.NodeConfigAgent
+	if agent == nil || !UseKopsControllerForNodeConfig(cluster) {
+		return 0
+	}
+	if agent.MaxUnavailable != nil {
+		return int(*agent.MaxUnavailable)
+	}
+	return 1
+}
+
 // Configures a Kubelet Credential Provider if Kubernetes is newer than a specific version
 func UseExternalKubeletCredentialProvider(k8sVersion *KubernetesVersion, cloudProvider kops.CloudProviderID) bool {
 	switch cloudProvider {
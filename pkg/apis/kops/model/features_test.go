@@ -80,3 +80,36 @@ func TestUseCiliumEtcd(t *testing.T) {
 		}
 	}
 }
+
+func TestBootstrapAuditStore(t *testing.T) {
+	for _, tc := range []struct {
+		spec     kops.ClusterSpec
+		expected string
+	}{
+		{
+			spec: kops.ClusterSpec{
+				ConfigStore: kops.ConfigStoreSpec{Base: "s3://bucket/cluster.example.com"},
+			},
+			expected: "",
+		},
+		{
+			spec: kops.ClusterSpec{
+				ConfigStore:    kops.ConfigStoreSpec{Base: "s3://bucket/cluster.example.com"},
+				BootstrapAudit: &kops.BootstrapAuditSpec{},
+			},
+			expected: "",
+		},
+		{
+			spec: kops.ClusterSpec{
+				ConfigStore:    kops.ConfigStoreSpec{Base: "s3://bucket/cluster.example.com/"},
+				BootstrapAudit: &kops.BootstrapAuditSpec{StateStore: true},
+			},
+			expected: "s3://bucket/cluster.example.com/audit/bootstrap/",
+		},
+	} {
+		actual := BootstrapAuditStore(&kops.Cluster{Spec: tc.spec})
+		if actual != tc.expected {
+			t.Errorf("expected %q, but got %q", tc.expected, actual)
+		}
+	}
+}
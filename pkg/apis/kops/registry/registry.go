@@ -31,6 +31,10 @@ const (
 	PathClusterCompleted = "cluster-completed.spec"
 	// PathKopsVersionUpdated is the path for the version of kops last used to apply the cluster.
 	PathKopsVersionUpdated = "kops-version.txt"
+	// PathCARotation is the path for the progress of a rotation of the keypairs by "kops rotate ca".
+	PathCARotation = "ca-rotation.yaml"
+	// PathLock is the path for the advisory lock taken by long operations such as "kops update cluster --yes".
+	PathLock = "lock.yaml"
 )
 
 func ConfigBase(vfsContext *vfs.VFSContext, c *api.Cluster) (vfs.Path, error) {
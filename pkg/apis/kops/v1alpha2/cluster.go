@@ -49,8 +49,10 @@ type ClusterSpec struct {
 	// The Channel we are following
 	Channel string `json:"channel,omitempty"`
 	// Additional addons that should be installed on the cluster
-	Addons      []AddonSpec          `json:"addons,omitempty"`
-	ConfigStore kops.ConfigStoreSpec `json:"-"`
+	Addons []AddonSpec `json:"addons,omitempty"`
+	// AddonSigning configures signing and verification of the addon channels and manifests.
+	AddonSigning *AddonSigningSpec    `json:"addonSigning,omitempty"`
+	ConfigStore  kops.ConfigStoreSpec `json:"-"`
 	// ConfigBase is the path where we store configuration for the cluster
 	// This might be different that the location when the cluster spec itself is stored,
 	// both because this must be accessible to the cluster,
@@ -106,6 +108,9 @@ type ClusterSpec struct {
 	// ConfigStore is unused.
 	// +k8s:conversion-gen=false
 	LegacyConfigStore string `json:"configStore,omitempty"`
+	// StateStoreEncryption configures the envelope encryption of the secrets and private keys in the state store.
+	// +k8s:conversion-gen=false
+	StateStoreEncryption *ConfigStoreEncryptionSpec `json:"stateStoreEncryption,omitempty"`
 	// DNSZone is the DNS zone we should use when configuring DNS
 	// This is because some clouds let us define a managed zone foo.bar, and then have
 	// kubernetes.dev.foo.bar, without needing to define dev.foo.bar as a hosted zone.
@@ -198,6 +203,10 @@ type ClusterSpec struct {
 	MetricsServer *MetricsServerConfig `json:"metricsServer,omitempty"`
 	// CertManager determines the metrics server configuration.
 	CertManager *CertManagerConfig `json:"certManager,omitempty"`
+	// Monitoring configures the monitoring of the components managed by kOps.
+	Monitoring *MonitoringSpec `json:"monitoring,omitempty"`
+	// GatewayAPI configures the Gateway API CRDs and the Gateway implementations managed by kOps.
+	GatewayAPI *GatewayAPISpec `json:"gatewayAPI,omitempty"`
 	// AWSLoadbalancerControllerConfig determines the AWS LB controller configuration.
 	// +k8s:conversion-gen=false
 	AWSLoadBalancerController *LoadBalancerControllerSpec `json:"awsLoadBalancerController,omitempty"`
@@ -216,6 +225,18 @@ type ClusterSpec struct {
 	Authorization *AuthorizationSpec `json:"authorization,omitempty"`
 	// NodeAuthorization defined the custom node authorization configuration
 	NodeAuthorization *NodeAuthorizationSpec `json:"nodeAuthorization,omitempty"`
+	// BootstrapAudit configures the audit log of node bootstrap requests handled by kops-controller.
+	BootstrapAudit *BootstrapAuditSpec `json:"bootstrapAudit,omitempty"`
+	// TPMAttestation makes nodes authenticate to kops-controller with TPM 2.0 attestation instead of their cloud identity.
+	TPMAttestation *TPMAttestationSpec `json:"tpmAttestation,omitempty"`
+	// NodeConfigAgent runs an agent on nodes that bootstrap through kops-controller, which applies configuration changes without replacing the nodes.
+	NodeConfigAgent *NodeConfigAgentSpec `json:"nodeConfigAgent,omitempty"`
+	// Reconciler makes kops-controller reconcile the cluster from the Cluster and InstanceGroup objects in the kube-system namespace,
+	// writing changes to the state store and reporting the changes that remain to be applied.
+	Reconciler *ReconcilerSpec `json:"reconciler,omitempty"`
+	// NodeIdentityRevocation lets `kops delete node-identity` revoke the identity of nodes,
+	// which kops-controller then refuses to issue credentials or node configuration to.
+	NodeIdentityRevocation *NodeIdentityRevocationSpec `json:"nodeIdentityRevocation,omitempty"`
 	// CloudLabels defines additional tags or labels on cloud provider resources
 	CloudLabels map[string]string `json:"cloudLabels,omitempty"`
 	// Hooks for custom actions e.g. on first installation
@@ -256,6 +277,16 @@ type ClusterSpec struct {
 	PodIdentityWebhook *PodIdentityWebhookSpec `json:"podIdentityWebhook,omitempty"`
 }
 
+// ConfigStoreEncryptionSpec configures the client-side envelope encryption of the secrets and private keys in the state store.
+// Each object is encrypted with its own data key, which is wrapped with the key encryption key of the provider.
+type ConfigStoreEncryptionSpec struct {
+	// Provider is the service that wraps the data keys: aws-kms, gcp-kms, azure-keyvault, vault-transit or local.
+	Provider string `json:"provider,omitempty"`
+	// KeyID identifies the key encryption key: the ARN of an AWS KMS key, the resource name of a GCP KMS crypto key,
+	// the URL of an Azure Key Vault key, the <mount>/<name> of a Vault Transit key, or the path to a local key file.
+	KeyID string `json:"keyID,omitempty"`
+}
+
 // PodIdentityWebhookSpec configures an EKS Pod Identity Webhook.
 type PodIdentityWebhookSpec struct {
 	Enabled  bool `json:"enabled,omitempty"`
@@ -333,6 +364,67 @@ type AddonSpec struct {
 	Manifest string `json:"manifest,omitempty"`
 }
 
+// AddonSigningSpec configures signing and verification of the addon channels and manifests applied to the cluster.
+type AddonSigningSpec struct {
+	// Enabled signs the kOps-managed channel and addon manifests with the addon-signing keyset,
+	// and makes the control plane refuse to apply channels or manifests without a trusted signature.
+	Enabled bool `json:"enabled,omitempty"`
+	// AdditionalTrustedKeys are PEM-encoded public keys trusted, in addition to the addon-signing keyset,
+	// to sign the channels and manifests listed in addons. Use them for keys kept offline or in a KMS, such as cosign keys.
+	AdditionalTrustedKeys []string `json:"additionalTrustedKeys,omitempty"`
+}
+
+// BootstrapAuditSpec configures the audit log of node bootstrap requests.
+// kops-controller always appends the records to /var/log/kops-controller/audit.log on the control plane nodes.
+type BootstrapAuditSpec struct {
+	// StateStore also writes each record to the state store, below audit/bootstrap/.
+	StateStore bool `json:"stateStore,omitempty"`
+}
+
+// TPMAttestationSpec configures node authentication with TPM 2.0 attestation.
+// Each node must be registered as a Host with the hash of its endorsement key.
+type TPMAttestationSpec struct {
+	// EKRootCAs are PEM-encoded certificates of TPM manufacturer CAs.
+	// If set, nodes must present an endorsement key certificate issued by one of them.
+	EKRootCAs []string `json:"ekRootCAs,omitempty"`
+	// PCRs are the expected values of PCRs in the SHA-256 bank.
+	// A node is only accepted if each listed PCR has one of the expected values.
+	PCRs []TPMPCRSpec `json:"pcrs,omitempty"`
+}
+
+// NodeConfigAgentSpec configures the node configuration agent.
+// The agent polls kops-controller and applies the changes that are safe on a running node:
+// files, sysctls, and the containerd and kubelet configuration, restarting the services that use them.
+// Other changes still require a rolling update.
+// Like a rolling update, kops-controller only lets a limited number of nodes apply a change at the same time.
+type NodeConfigAgentSpec struct {
+	// Interval is how often the agent asks kops-controller for changes. Defaults to 5m.
+	// Each node waits a random time between half and one and a half intervals between polls.
+	Interval *metav1.Duration `json:"interval,omitempty"`
+	// MaxUnavailable is the maximum number of nodes that apply a change at the same time.
+	// A node counts until it is Ready after applying the change. Defaults to 1.
+	MaxUnavailable *int32 `json:"maxUnavailable,omitempty"`
+}
+
+// ReconcilerSpec configures the reconciliation of the cluster by kops-controller.
+// Changes to the Cluster and InstanceGroup objects in the cluster are validated and written to the state store.
+// kops-controller does not change cloud resources: the changes that remain to be applied with
+// `kops update cluster --yes`, and the instances that `kops rolling-update cluster` would replace,
+// are reported in the status conditions of the InstanceGroup objects.
+type ReconcilerSpec struct{}
+
+// NodeIdentityRevocationSpec configures the revocation of node identities.
+// The revoked nodes are kept in the state store, below node-identity/revoked/.
+type NodeIdentityRevocationSpec struct{}
+
+// TPMPCRSpec lists the accepted values of a PCR.
+type TPMPCRSpec struct {
+	// Index is the index of the PCR.
+	Index int32 `json:"index"`
+	// Values are the accepted hex-encoded SHA-256 values of the PCR.
+	Values []string `json:"values,omitempty"`
+}
+
 // FileAssetSpec defines the structure for a file asset
 type FileAssetSpec struct {
 	// Name is a shortened reference to the asset
@@ -407,6 +499,8 @@ type AuthenticationSpec struct {
 	Kopeio *KopeioAuthenticationSpec    `json:"kopeio,omitempty"`
 	AWS    *AWSAuthenticationSpec       `json:"aws,omitempty"`
 	OIDC   *kops.OIDCAuthenticationSpec `json:"-"`
+	// AdminCredentials is the policy for the cluster admin credentials issued by kOps.
+	AdminCredentials *AdminCredentialsSpec `json:"adminCredentials,omitempty"`
 }
 
 func (s *AuthenticationSpec) IsEmpty() bool {
@@ -415,6 +509,15 @@ func (s *AuthenticationSpec) IsEmpty() bool {
 
 type KopeioAuthenticationSpec struct{}
 
+// AdminCredentialsSpec is the policy for the cluster admin client certificates issued by kOps,
+// such as by `kops export kubeconfig --admin`. When set, the certificates are signed by the
+// kubernetes-admin-ca keyset, a separate root CA, which can be distrusted without rotating kubernetes-ca,
+// and each issuance is recorded in the state store.
+type AdminCredentialsSpec struct {
+	// MaxLifetime is the maximum lifetime of an admin certificate. Longer requests are shortened.
+	MaxLifetime *metav1.Duration `json:"maxLifetime,omitempty"`
+}
+
 type AWSAuthenticationSpec struct {
 	// Image is the AWS IAM Authenticator container image to use.
 	Image string `json:"image,omitempty"`
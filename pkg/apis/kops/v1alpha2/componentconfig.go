@@ -1280,6 +1280,49 @@ type CertManagerConfig struct {
 	FeatureGates map[string]bool `json:"featureGates,omitempty"`
 }
 
+// MonitoringSpec configures the monitoring of the components managed by kOps.
+type MonitoringSpec struct {
+	// Enabled deploys a Prometheus agent on the control plane that scrapes the components managed by kOps:
+	// kube-apiserver, kube-apiserver-healthcheck, etcd, kops-controller and dns-controller.
+	// Default: false
+	Enabled *bool `json:"enabled,omitempty"`
+	// Image is the Prometheus container image used.
+	Image *string `json:"image,omitempty"`
+	// RemoteWriteURL is the URL of the Prometheus remote-write compatible endpoint that scraped metrics are forwarded to.
+	RemoteWriteURL string `json:"remoteWriteURL,omitempty"`
+	// ScrapeInterval is how frequently the components are scraped.
+	// Default: 30s
+	ScrapeInterval *metav1.Duration `json:"scrapeInterval,omitempty"`
+	// MemoryRequest of the Prometheus container.
+	// Default: 200Mi
+	MemoryRequest *resource.Quantity `json:"memoryRequest,omitempty"`
+	// CPURequest of the Prometheus container.
+	// Default: 50m
+	CPURequest *resource.Quantity `json:"cpuRequest,omitempty"`
+	// MemoryLimit of the Prometheus container.
+	// Default: 400Mi
+	MemoryLimit *resource.Quantity `json:"memoryLimit,omitempty"`
+	// ServiceMonitors also installs Prometheus Operator ServiceMonitors for the components, so that an in-cluster
+	// Prometheus can scrape them. The ServiceMonitors are applied once the monitoring.coreos.com CRDs are installed.
+	// kops-controller and dns-controller then serve their metrics on the node address rather than on localhost.
+	// Default: false
+	ServiceMonitors *bool `json:"serviceMonitors,omitempty"`
+}
+
+// GatewayAPISpec configures the Gateway API CRDs managed by kOps.
+type GatewayAPISpec struct {
+	// Enabled installs the Gateway API CRDs as a kOps-managed addon,
+	// and enables Gateway support in the Gateway implementations managed by kOps.
+	// Default: false
+	Enabled *bool `json:"enabled,omitempty"`
+	// Channel is the Gateway API release channel of the CRDs; either "standard" or "experimental".
+	// Default: experimental when Cilium Gateway API support or the AWS Load Balancer Controller is enabled, otherwise standard
+	Channel string `json:"channel,omitempty"`
+	// Version is the Gateway API release of the CRDs.
+	// Default: v1.4.0
+	Version string `json:"version,omitempty"`
+}
+
 // LoadBalancerControllerSpec determines the AWS LB controller configuration.
 type LoadBalancerControllerSpec struct {
 	// Enabled enables the loadbalancer controller.
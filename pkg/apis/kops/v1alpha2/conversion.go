@@ -100,6 +100,14 @@ func Convert_v1alpha2_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *kops
 	}
 	out.ConfigStore.Secrets = in.SecretStore
 	out.ConfigStore.Keypairs = in.KeyStore
+	if in.StateStoreEncryption != nil {
+		out.ConfigStore.Encryption = &kops.ConfigStoreEncryptionSpec{}
+		if err := Convert_v1alpha2_ConfigStoreEncryptionSpec_To_kops_ConfigStoreEncryptionSpec(in.StateStoreEncryption, out.ConfigStore.Encryption, s); err != nil {
+			return err
+		}
+	} else {
+		out.ConfigStore.Encryption = nil
+	}
 	if in.KubeAPIServer != nil {
 		kube := in.KubeAPIServer
 		if kube.OIDCClientID != nil ||
@@ -396,6 +404,12 @@ func Convert_kops_ClusterSpec_To_v1alpha2_ClusterSpec(in *kops.ClusterSpec, out
 	out.ConfigBase = in.ConfigStore.Base
 	out.KeyStore = in.ConfigStore.Keypairs
 	out.SecretStore = in.ConfigStore.Secrets
+	if in.ConfigStore.Encryption != nil {
+		out.StateStoreEncryption = &ConfigStoreEncryptionSpec{}
+		if err := Convert_kops_ConfigStoreEncryptionSpec_To_v1alpha2_ConfigStoreEncryptionSpec(in.ConfigStore.Encryption, out.StateStoreEncryption, s); err != nil {
+			return err
+		}
+	}
 	if in.ExternalPolicies != nil {
 		out.ExternalPolicies = make(map[string][]string, len(in.ExternalPolicies))
 		for k, v := range in.ExternalPolicies {
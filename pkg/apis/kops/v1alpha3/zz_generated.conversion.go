@@ -104,6 +104,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*AddonSigningSpec)(nil), (*kops.AddonSigningSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_AddonSigningSpec_To_kops_AddonSigningSpec(a.(*AddonSigningSpec), b.(*kops.AddonSigningSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.AddonSigningSpec)(nil), (*AddonSigningSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_AddonSigningSpec_To_v1alpha3_AddonSigningSpec(a.(*kops.AddonSigningSpec), b.(*AddonSigningSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*AddonSpec)(nil), (*kops.AddonSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_AddonSpec_To_kops_AddonSpec(a.(*AddonSpec), b.(*kops.AddonSpec), scope)
 	}); err != nil {
@@ -114,6 +124,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*AdminCredentialsSpec)(nil), (*kops.AdminCredentialsSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_AdminCredentialsSpec_To_kops_AdminCredentialsSpec(a.(*AdminCredentialsSpec), b.(*kops.AdminCredentialsSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.AdminCredentialsSpec)(nil), (*AdminCredentialsSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_AdminCredentialsSpec_To_v1alpha3_AdminCredentialsSpec(a.(*kops.AdminCredentialsSpec), b.(*AdminCredentialsSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*AlwaysAllowAuthorizationSpec)(nil), (*kops.AlwaysAllowAuthorizationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_AlwaysAllowAuthorizationSpec_To_kops_AlwaysAllowAuthorizationSpec(a.(*AlwaysAllowAuthorizationSpec), b.(*kops.AlwaysAllowAuthorizationSpec), scope)
 	}); err != nil {
@@ -194,6 +214,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*BootstrapAuditSpec)(nil), (*kops.BootstrapAuditSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_BootstrapAuditSpec_To_kops_BootstrapAuditSpec(a.(*BootstrapAuditSpec), b.(*kops.BootstrapAuditSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.BootstrapAuditSpec)(nil), (*BootstrapAuditSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_BootstrapAuditSpec_To_v1alpha3_BootstrapAuditSpec(a.(*kops.BootstrapAuditSpec), b.(*BootstrapAuditSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*CNINetworkingSpec)(nil), (*kops.CNINetworkingSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_CNINetworkingSpec_To_kops_CNINetworkingSpec(a.(*CNINetworkingSpec), b.(*kops.CNINetworkingSpec), scope)
 	}); err != nil {
@@ -344,6 +374,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ConfigStoreEncryptionSpec)(nil), (*kops.ConfigStoreEncryptionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_ConfigStoreEncryptionSpec_To_kops_ConfigStoreEncryptionSpec(a.(*ConfigStoreEncryptionSpec), b.(*kops.ConfigStoreEncryptionSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.ConfigStoreEncryptionSpec)(nil), (*ConfigStoreEncryptionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_ConfigStoreEncryptionSpec_To_v1alpha3_ConfigStoreEncryptionSpec(a.(*kops.ConfigStoreEncryptionSpec), b.(*ConfigStoreEncryptionSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ConfigStoreSpec)(nil), (*kops.ConfigStoreSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_ConfigStoreSpec_To_kops_ConfigStoreSpec(a.(*ConfigStoreSpec), b.(*kops.ConfigStoreSpec), scope)
 	}); err != nil {
@@ -564,6 +604,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*GatewayAPISpec)(nil), (*kops.GatewayAPISpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_GatewayAPISpec_To_kops_GatewayAPISpec(a.(*GatewayAPISpec), b.(*kops.GatewayAPISpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.GatewayAPISpec)(nil), (*GatewayAPISpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_GatewayAPISpec_To_v1alpha3_GatewayAPISpec(a.(*kops.GatewayAPISpec), b.(*GatewayAPISpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*GossipConfig)(nil), (*kops.GossipConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_GossipConfig_To_kops_GossipConfig(a.(*GossipConfig), b.(*kops.GossipConfig), scope)
 	}); err != nil {
@@ -644,6 +694,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*HostStatus)(nil), (*kops.HostStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_HostStatus_To_kops_HostStatus(a.(*HostStatus), b.(*kops.HostStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.HostStatus)(nil), (*HostStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_HostStatus_To_v1alpha3_HostStatus(a.(*kops.HostStatus), b.(*HostStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*HostTPMSpec)(nil), (*kops.HostTPMSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_HostTPMSpec_To_kops_HostTPMSpec(a.(*HostTPMSpec), b.(*kops.HostTPMSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.HostTPMSpec)(nil), (*HostTPMSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_HostTPMSpec_To_v1alpha3_HostTPMSpec(a.(*kops.HostTPMSpec), b.(*HostTPMSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*HubbleSpec)(nil), (*kops.HubbleSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_HubbleSpec_To_kops_HubbleSpec(a.(*HubbleSpec), b.(*kops.HubbleSpec), scope)
 	}); err != nil {
@@ -704,6 +774,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*InstanceGroupStatus)(nil), (*kops.InstanceGroupStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_InstanceGroupStatus_To_kops_InstanceGroupStatus(a.(*InstanceGroupStatus), b.(*kops.InstanceGroupStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.InstanceGroupStatus)(nil), (*InstanceGroupStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_InstanceGroupStatus_To_v1alpha3_InstanceGroupStatus(a.(*kops.InstanceGroupStatus), b.(*InstanceGroupStatus), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*InstanceMetadataOptions)(nil), (*kops.InstanceMetadataOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_InstanceMetadataOptions_To_kops_InstanceMetadataOptions(a.(*InstanceMetadataOptions), b.(*kops.InstanceMetadataOptions), scope)
 	}); err != nil {
@@ -984,6 +1064,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*MonitoringSpec)(nil), (*kops.MonitoringSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_MonitoringSpec_To_kops_MonitoringSpec(a.(*MonitoringSpec), b.(*kops.MonitoringSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.MonitoringSpec)(nil), (*MonitoringSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_MonitoringSpec_To_v1alpha3_MonitoringSpec(a.(*kops.MonitoringSpec), b.(*MonitoringSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*NRIConfig)(nil), (*kops.NRIConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_NRIConfig_To_kops_NRIConfig(a.(*NRIConfig), b.(*kops.NRIConfig), scope)
 	}); err != nil {
@@ -1014,6 +1104,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*NodeConfigAgentSpec)(nil), (*kops.NodeConfigAgentSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_NodeConfigAgentSpec_To_kops_NodeConfigAgentSpec(a.(*NodeConfigAgentSpec), b.(*kops.NodeConfigAgentSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.NodeConfigAgentSpec)(nil), (*NodeConfigAgentSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_NodeConfigAgentSpec_To_v1alpha3_NodeConfigAgentSpec(a.(*kops.NodeConfigAgentSpec), b.(*NodeConfigAgentSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NodeIdentityRevocationSpec)(nil), (*kops.NodeIdentityRevocationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_NodeIdentityRevocationSpec_To_kops_NodeIdentityRevocationSpec(a.(*NodeIdentityRevocationSpec), b.(*kops.NodeIdentityRevocationSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.NodeIdentityRevocationSpec)(nil), (*NodeIdentityRevocationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_NodeIdentityRevocationSpec_To_v1alpha3_NodeIdentityRevocationSpec(a.(*kops.NodeIdentityRevocationSpec), b.(*NodeIdentityRevocationSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*NodeLocalDNSConfig)(nil), (*kops.NodeLocalDNSConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_NodeLocalDNSConfig_To_kops_NodeLocalDNSConfig(a.(*NodeLocalDNSConfig), b.(*kops.NodeLocalDNSConfig), scope)
 	}); err != nil {
@@ -1174,6 +1284,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ReconcilerSpec)(nil), (*kops.ReconcilerSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_ReconcilerSpec_To_kops_ReconcilerSpec(a.(*ReconcilerSpec), b.(*kops.ReconcilerSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.ReconcilerSpec)(nil), (*ReconcilerSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_ReconcilerSpec_To_v1alpha3_ReconcilerSpec(a.(*kops.ReconcilerSpec), b.(*ReconcilerSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*RollingUpdate)(nil), (*kops.RollingUpdate)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_RollingUpdate_To_kops_RollingUpdate(a.(*RollingUpdate), b.(*kops.RollingUpdate), scope)
 	}); err != nil {
@@ -1274,6 +1394,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*TPMAttestationSpec)(nil), (*kops.TPMAttestationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_TPMAttestationSpec_To_kops_TPMAttestationSpec(a.(*TPMAttestationSpec), b.(*kops.TPMAttestationSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.TPMAttestationSpec)(nil), (*TPMAttestationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_TPMAttestationSpec_To_v1alpha3_TPMAttestationSpec(a.(*kops.TPMAttestationSpec), b.(*TPMAttestationSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*TPMPCRSpec)(nil), (*kops.TPMPCRSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_TPMPCRSpec_To_kops_TPMPCRSpec(a.(*TPMPCRSpec), b.(*kops.TPMPCRSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.TPMPCRSpec)(nil), (*TPMPCRSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_TPMPCRSpec_To_v1alpha3_TPMPCRSpec(a.(*kops.TPMPCRSpec), b.(*TPMPCRSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*TargetSpec)(nil), (*kops.TargetSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_TargetSpec_To_kops_TargetSpec(a.(*TargetSpec), b.(*kops.TargetSpec), scope)
 	}); err != nil {
@@ -1683,6 +1823,28 @@ func Convert_kops_AccessLogSpec_To_v1alpha3_AccessLogSpec(in *kops.AccessLogSpec
 	return autoConvert_kops_AccessLogSpec_To_v1alpha3_AccessLogSpec(in, out, s)
 }
 
+func autoConvert_v1alpha3_AddonSigningSpec_To_kops_AddonSigningSpec(in *AddonSigningSpec, out *kops.AddonSigningSpec, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.AdditionalTrustedKeys = in.AdditionalTrustedKeys
+	return nil
+}
+
+// Convert_v1alpha3_AddonSigningSpec_To_kops_AddonSigningSpec is an autogenerated conversion function.
+func Convert_v1alpha3_AddonSigningSpec_To_kops_AddonSigningSpec(in *AddonSigningSpec, out *kops.AddonSigningSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha3_AddonSigningSpec_To_kops_AddonSigningSpec(in, out, s)
+}
+
+func autoConvert_kops_AddonSigningSpec_To_v1alpha3_AddonSigningSpec(in *kops.AddonSigningSpec, out *AddonSigningSpec, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.AdditionalTrustedKeys = in.AdditionalTrustedKeys
+	return nil
+}
+
+// Convert_kops_AddonSigningSpec_To_v1alpha3_AddonSigningSpec is an autogenerated conversion function.
+func Convert_kops_AddonSigningSpec_To_v1alpha3_AddonSigningSpec(in *kops.AddonSigningSpec, out *AddonSigningSpec, s conversion.Scope) error {
+	return autoConvert_kops_AddonSigningSpec_To_v1alpha3_AddonSigningSpec(in, out, s)
+}
+
 func autoConvert_v1alpha3_AddonSpec_To_kops_AddonSpec(in *AddonSpec, out *kops.AddonSpec, s conversion.Scope) error {
 	out.Manifest = in.Manifest
 	return nil
@@ -1703,6 +1865,26 @@ func Convert_kops_AddonSpec_To_v1alpha3_AddonSpec(in *kops.AddonSpec, out *Addon
 	return autoConvert_kops_AddonSpec_To_v1alpha3_AddonSpec(in, out, s)
 }
 
+func autoConvert_v1alpha3_AdminCredentialsSpec_To_kops_AdminCredentialsSpec(in *AdminCredentialsSpec, out *kops.AdminCredentialsSpec, s conversion.Scope) error {
+	out.MaxLifetime = in.MaxLifetime
+	return nil
+}
+
+// Convert_v1alpha3_AdminCredentialsSpec_To_kops_AdminCredentialsSpec is an autogenerated conversion function.
+func Convert_v1alpha3_AdminCredentialsSpec_To_kops_AdminCredentialsSpec(in *AdminCredentialsSpec, out *kops.AdminCredentialsSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha3_AdminCredentialsSpec_To_kops_AdminCredentialsSpec(in, out, s)
+}
+
+func autoConvert_kops_AdminCredentialsSpec_To_v1alpha3_AdminCredentialsSpec(in *kops.AdminCredentialsSpec, out *AdminCredentialsSpec, s conversion.Scope) error {
+	out.MaxLifetime = in.MaxLifetime
+	return nil
+}
+
+// Convert_kops_AdminCredentialsSpec_To_v1alpha3_AdminCredentialsSpec is an autogenerated conversion function.
+func Convert_kops_AdminCredentialsSpec_To_v1alpha3_AdminCredentialsSpec(in *kops.AdminCredentialsSpec, out *AdminCredentialsSpec, s conversion.Scope) error {
+	return autoConvert_kops_AdminCredentialsSpec_To_v1alpha3_AdminCredentialsSpec(in, out, s)
+}
+
 func autoConvert_v1alpha3_AlwaysAllowAuthorizationSpec_To_kops_AlwaysAllowAuthorizationSpec(in *AlwaysAllowAuthorizationSpec, out *kops.AlwaysAllowAuthorizationSpec, s conversion.Scope) error {
 	return nil
 }
@@ -1819,6 +2001,15 @@ func autoConvert_v1alpha3_AuthenticationSpec_To_kops_AuthenticationSpec(in *Auth
 	} else {
 		out.OIDC = nil
 	}
+	if in.AdminCredentials != nil {
+		in, out := &in.AdminCredentials, &out.AdminCredentials
+		*out = new(kops.AdminCredentialsSpec)
+		if err := Convert_v1alpha3_AdminCredentialsSpec_To_kops_AdminCredentialsSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.AdminCredentials = nil
+	}
 	return nil
 }
 
@@ -1855,6 +2046,15 @@ func autoConvert_kops_AuthenticationSpec_To_v1alpha3_AuthenticationSpec(in *kops
 	} else {
 		out.OIDC = nil
 	}
+	if in.AdminCredentials != nil {
+		in, out := &in.AdminCredentials, &out.AdminCredentials
+		*out = new(AdminCredentialsSpec)
+		if err := Convert_kops_AdminCredentialsSpec_To_v1alpha3_AdminCredentialsSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.AdminCredentials = nil
+	}
 	return nil
 }
 
@@ -2007,6 +2207,26 @@ func Convert_kops_BastionSpec_To_v1alpha3_BastionSpec(in *kops.BastionSpec, out
 	return autoConvert_kops_BastionSpec_To_v1alpha3_BastionSpec(in, out, s)
 }
 
+func autoConvert_v1alpha3_BootstrapAuditSpec_To_kops_BootstrapAuditSpec(in *BootstrapAuditSpec, out *kops.BootstrapAuditSpec, s conversion.Scope) error {
+	out.StateStore = in.StateStore
+	return nil
+}
+
+// Convert_v1alpha3_BootstrapAuditSpec_To_kops_BootstrapAuditSpec is an autogenerated conversion function.
+func Convert_v1alpha3_BootstrapAuditSpec_To_kops_BootstrapAuditSpec(in *BootstrapAuditSpec, out *kops.BootstrapAuditSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha3_BootstrapAuditSpec_To_kops_BootstrapAuditSpec(in, out, s)
+}
+
+func autoConvert_kops_BootstrapAuditSpec_To_v1alpha3_BootstrapAuditSpec(in *kops.BootstrapAuditSpec, out *BootstrapAuditSpec, s conversion.Scope) error {
+	out.StateStore = in.StateStore
+	return nil
+}
+
+// Convert_kops_BootstrapAuditSpec_To_v1alpha3_BootstrapAuditSpec is an autogenerated conversion function.
+func Convert_kops_BootstrapAuditSpec_To_v1alpha3_BootstrapAuditSpec(in *kops.BootstrapAuditSpec, out *BootstrapAuditSpec, s conversion.Scope) error {
+	return autoConvert_kops_BootstrapAuditSpec_To_v1alpha3_BootstrapAuditSpec(in, out, s)
+}
+
 func autoConvert_v1alpha3_CNINetworkingSpec_To_kops_CNINetworkingSpec(in *CNINetworkingSpec, out *kops.CNINetworkingSpec, s conversion.Scope) error {
 	out.UsesSecondaryIP = in.UsesSecondaryIP
 	return nil
@@ -2778,6 +2998,15 @@ func autoConvert_v1alpha3_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 	} else {
 		out.Addons = nil
 	}
+	if in.AddonSigning != nil {
+		in, out := &in.AddonSigning, &out.AddonSigning
+		*out = new(kops.AddonSigningSpec)
+		if err := Convert_v1alpha3_AddonSigningSpec_To_kops_AddonSigningSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.AddonSigning = nil
+	}
 	if err := Convert_v1alpha3_ConfigStoreSpec_To_kops_ConfigStoreSpec(&in.ConfigStore, &out.ConfigStore, s); err != nil {
 		return err
 	}
@@ -2979,6 +3208,24 @@ func autoConvert_v1alpha3_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 	} else {
 		out.CertManager = nil
 	}
+	if in.Monitoring != nil {
+		in, out := &in.Monitoring, &out.Monitoring
+		*out = new(kops.MonitoringSpec)
+		if err := Convert_v1alpha3_MonitoringSpec_To_kops_MonitoringSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Monitoring = nil
+	}
+	if in.GatewayAPI != nil {
+		in, out := &in.GatewayAPI, &out.GatewayAPI
+		*out = new(kops.GatewayAPISpec)
+		if err := Convert_v1alpha3_GatewayAPISpec_To_kops_GatewayAPISpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.GatewayAPI = nil
+	}
 	if err := Convert_v1alpha3_NetworkingSpec_To_kops_NetworkingSpec(&in.Networking, &out.Networking, s); err != nil {
 		return err
 	}
@@ -3004,6 +3251,51 @@ func autoConvert_v1alpha3_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 		out.Authorization = nil
 	}
 	out.NodeAuthorization = in.NodeAuthorization
+	if in.BootstrapAudit != nil {
+		in, out := &in.BootstrapAudit, &out.BootstrapAudit
+		*out = new(kops.BootstrapAuditSpec)
+		if err := Convert_v1alpha3_BootstrapAuditSpec_To_kops_BootstrapAuditSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.BootstrapAudit = nil
+	}
+	if in.TPMAttestation != nil {
+		in, out := &in.TPMAttestation, &out.TPMAttestation
+		*out = new(kops.TPMAttestationSpec)
+		if err := Convert_v1alpha3_TPMAttestationSpec_To_kops_TPMAttestationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.TPMAttestation = nil
+	}
+	if in.NodeConfigAgent != nil {
+		in, out := &in.NodeConfigAgent, &out.NodeConfigAgent
+		*out = new(kops.NodeConfigAgentSpec)
+		if err := Convert_v1alpha3_NodeConfigAgentSpec_To_kops_NodeConfigAgentSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NodeConfigAgent = nil
+	}
+	if in.Reconciler != nil {
+		in, out := &in.Reconciler, &out.Reconciler
+		*out = new(kops.ReconcilerSpec)
+		if err := Convert_v1alpha3_ReconcilerSpec_To_kops_ReconcilerSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Reconciler = nil
+	}
+	if in.NodeIdentityRevocation != nil {
+		in, out := &in.NodeIdentityRevocation, &out.NodeIdentityRevocation
+		*out = new(kops.NodeIdentityRevocationSpec)
+		if err := Convert_v1alpha3_NodeIdentityRevocationSpec_To_kops_NodeIdentityRevocationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NodeIdentityRevocation = nil
+	}
 	out.CloudLabels = in.CloudLabels
 	if in.Hooks != nil {
 		in, out := &in.Hooks, &out.Hooks
@@ -3112,6 +3404,15 @@ func autoConvert_kops_ClusterSpec_To_v1alpha3_ClusterSpec(in *kops.ClusterSpec,
 	} else {
 		out.Addons = nil
 	}
+	if in.AddonSigning != nil {
+		in, out := &in.AddonSigning, &out.AddonSigning
+		*out = new(AddonSigningSpec)
+		if err := Convert_kops_AddonSigningSpec_To_v1alpha3_AddonSigningSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.AddonSigning = nil
+	}
 	if err := Convert_kops_ConfigStoreSpec_To_v1alpha3_ConfigStoreSpec(&in.ConfigStore, &out.ConfigStore, s); err != nil {
 		return err
 	}
@@ -3313,6 +3614,24 @@ func autoConvert_kops_ClusterSpec_To_v1alpha3_ClusterSpec(in *kops.ClusterSpec,
 	} else {
 		out.CertManager = nil
 	}
+	if in.Monitoring != nil {
+		in, out := &in.Monitoring, &out.Monitoring
+		*out = new(MonitoringSpec)
+		if err := Convert_kops_MonitoringSpec_To_v1alpha3_MonitoringSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Monitoring = nil
+	}
+	if in.GatewayAPI != nil {
+		in, out := &in.GatewayAPI, &out.GatewayAPI
+		*out = new(GatewayAPISpec)
+		if err := Convert_kops_GatewayAPISpec_To_v1alpha3_GatewayAPISpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.GatewayAPI = nil
+	}
 	if err := Convert_kops_NetworkingSpec_To_v1alpha3_NetworkingSpec(&in.Networking, &out.Networking, s); err != nil {
 		return err
 	}
@@ -3338,6 +3657,51 @@ func autoConvert_kops_ClusterSpec_To_v1alpha3_ClusterSpec(in *kops.ClusterSpec,
 		out.Authorization = nil
 	}
 	out.NodeAuthorization = in.NodeAuthorization
+	if in.BootstrapAudit != nil {
+		in, out := &in.BootstrapAudit, &out.BootstrapAudit
+		*out = new(BootstrapAuditSpec)
+		if err := Convert_kops_BootstrapAuditSpec_To_v1alpha3_BootstrapAuditSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.BootstrapAudit = nil
+	}
+	if in.TPMAttestation != nil {
+		in, out := &in.TPMAttestation, &out.TPMAttestation
+		*out = new(TPMAttestationSpec)
+		if err := Convert_kops_TPMAttestationSpec_To_v1alpha3_TPMAttestationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.TPMAttestation = nil
+	}
+	if in.NodeConfigAgent != nil {
+		in, out := &in.NodeConfigAgent, &out.NodeConfigAgent
+		*out = new(NodeConfigAgentSpec)
+		if err := Convert_kops_NodeConfigAgentSpec_To_v1alpha3_NodeConfigAgentSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NodeConfigAgent = nil
+	}
+	if in.Reconciler != nil {
+		in, out := &in.Reconciler, &out.Reconciler
+		*out = new(ReconcilerSpec)
+		if err := Convert_kops_ReconcilerSpec_To_v1alpha3_ReconcilerSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Reconciler = nil
+	}
+	if in.NodeIdentityRevocation != nil {
+		in, out := &in.NodeIdentityRevocation, &out.NodeIdentityRevocation
+		*out = new(NodeIdentityRevocationSpec)
+		if err := Convert_kops_NodeIdentityRevocationSpec_To_v1alpha3_NodeIdentityRevocationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NodeIdentityRevocation = nil
+	}
 	out.CloudLabels = in.CloudLabels
 	if in.Hooks != nil {
 		in, out := &in.Hooks, &out.Hooks
@@ -3491,10 +3855,41 @@ func Convert_kops_ClusterSubnetSpec_To_v1alpha3_ClusterSubnetSpec(in *kops.Clust
 	return autoConvert_kops_ClusterSubnetSpec_To_v1alpha3_ClusterSubnetSpec(in, out, s)
 }
 
+func autoConvert_v1alpha3_ConfigStoreEncryptionSpec_To_kops_ConfigStoreEncryptionSpec(in *ConfigStoreEncryptionSpec, out *kops.ConfigStoreEncryptionSpec, s conversion.Scope) error {
+	out.Provider = in.Provider
+	out.KeyID = in.KeyID
+	return nil
+}
+
+// Convert_v1alpha3_ConfigStoreEncryptionSpec_To_kops_ConfigStoreEncryptionSpec is an autogenerated conversion function.
+func Convert_v1alpha3_ConfigStoreEncryptionSpec_To_kops_ConfigStoreEncryptionSpec(in *ConfigStoreEncryptionSpec, out *kops.ConfigStoreEncryptionSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha3_ConfigStoreEncryptionSpec_To_kops_ConfigStoreEncryptionSpec(in, out, s)
+}
+
+func autoConvert_kops_ConfigStoreEncryptionSpec_To_v1alpha3_ConfigStoreEncryptionSpec(in *kops.ConfigStoreEncryptionSpec, out *ConfigStoreEncryptionSpec, s conversion.Scope) error {
+	out.Provider = in.Provider
+	out.KeyID = in.KeyID
+	return nil
+}
+
+// Convert_kops_ConfigStoreEncryptionSpec_To_v1alpha3_ConfigStoreEncryptionSpec is an autogenerated conversion function.
+func Convert_kops_ConfigStoreEncryptionSpec_To_v1alpha3_ConfigStoreEncryptionSpec(in *kops.ConfigStoreEncryptionSpec, out *ConfigStoreEncryptionSpec, s conversion.Scope) error {
+	return autoConvert_kops_ConfigStoreEncryptionSpec_To_v1alpha3_ConfigStoreEncryptionSpec(in, out, s)
+}
+
 func autoConvert_v1alpha3_ConfigStoreSpec_To_kops_ConfigStoreSpec(in *ConfigStoreSpec, out *kops.ConfigStoreSpec, s conversion.Scope) error {
 	out.Base = in.Base
 	out.Keypairs = in.Keypairs
 	out.Secrets = in.Secrets
+	if in.Encryption != nil {
+		in, out := &in.Encryption, &out.Encryption
+		*out = new(kops.ConfigStoreEncryptionSpec)
+		if err := Convert_v1alpha3_ConfigStoreEncryptionSpec_To_kops_ConfigStoreEncryptionSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Encryption = nil
+	}
 	return nil
 }
 
@@ -3507,6 +3902,15 @@ func autoConvert_kops_ConfigStoreSpec_To_v1alpha3_ConfigStoreSpec(in *kops.Confi
 	out.Base = in.Base
 	out.Keypairs = in.Keypairs
 	out.Secrets = in.Secrets
+	if in.Encryption != nil {
+		in, out := &in.Encryption, &out.Encryption
+		*out = new(ConfigStoreEncryptionSpec)
+		if err := Convert_kops_ConfigStoreEncryptionSpec_To_v1alpha3_ConfigStoreEncryptionSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Encryption = nil
+	}
 	return nil
 }
 
@@ -4345,6 +4749,30 @@ func Convert_kops_GCPNetworkingSpec_To_v1alpha3_GCPNetworkingSpec(in *kops.GCPNe
 	return autoConvert_kops_GCPNetworkingSpec_To_v1alpha3_GCPNetworkingSpec(in, out, s)
 }
 
+func autoConvert_v1alpha3_GatewayAPISpec_To_kops_GatewayAPISpec(in *GatewayAPISpec, out *kops.GatewayAPISpec, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.Channel = in.Channel
+	out.Version = in.Version
+	return nil
+}
+
+// Convert_v1alpha3_GatewayAPISpec_To_kops_GatewayAPISpec is an autogenerated conversion function.
+func Convert_v1alpha3_GatewayAPISpec_To_kops_GatewayAPISpec(in *GatewayAPISpec, out *kops.GatewayAPISpec, s conversion.Scope) error {
+	return autoConvert_v1alpha3_GatewayAPISpec_To_kops_GatewayAPISpec(in, out, s)
+}
+
+func autoConvert_kops_GatewayAPISpec_To_v1alpha3_GatewayAPISpec(in *kops.GatewayAPISpec, out *GatewayAPISpec, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.Channel = in.Channel
+	out.Version = in.Version
+	return nil
+}
+
+// Convert_kops_GatewayAPISpec_To_v1alpha3_GatewayAPISpec is an autogenerated conversion function.
+func Convert_kops_GatewayAPISpec_To_v1alpha3_GatewayAPISpec(in *kops.GatewayAPISpec, out *GatewayAPISpec, s conversion.Scope) error {
+	return autoConvert_kops_GatewayAPISpec_To_v1alpha3_GatewayAPISpec(in, out, s)
+}
+
 func autoConvert_v1alpha3_GossipConfig_To_kops_GossipConfig(in *GossipConfig, out *kops.GossipConfig, s conversion.Scope) error {
 	out.Protocol = in.Protocol
 	out.Listen = in.Listen
@@ -4522,6 +4950,9 @@ func autoConvert_v1alpha3_Host_To_kops_Host(in *Host, out *kops.Host, s conversi
 	if err := Convert_v1alpha3_HostSpec_To_kops_HostSpec(&in.Spec, &out.Spec, s); err != nil {
 		return err
 	}
+	if err := Convert_v1alpha3_HostStatus_To_kops_HostStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -4535,6 +4966,9 @@ func autoConvert_kops_Host_To_v1alpha3_Host(in *kops.Host, out *Host, s conversi
 	if err := Convert_kops_HostSpec_To_v1alpha3_HostSpec(&in.Spec, &out.Spec, s); err != nil {
 		return err
 	}
+	if err := Convert_kops_HostStatus_To_v1alpha3_HostStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -4589,6 +5023,15 @@ func autoConvert_v1alpha3_HostSpec_To_kops_HostSpec(in *HostSpec, out *kops.Host
 	out.PublicKey = in.PublicKey
 	out.InstanceGroup = in.InstanceGroup
 	out.PodCIDRs = in.PodCIDRs
+	if in.TPM != nil {
+		in, out := &in.TPM, &out.TPM
+		*out = new(kops.HostTPMSpec)
+		if err := Convert_v1alpha3_HostTPMSpec_To_kops_HostTPMSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.TPM = nil
+	}
 	return nil
 }
 
@@ -4601,6 +5044,15 @@ func autoConvert_kops_HostSpec_To_v1alpha3_HostSpec(in *kops.HostSpec, out *Host
 	out.PublicKey = in.PublicKey
 	out.InstanceGroup = in.InstanceGroup
 	out.PodCIDRs = in.PodCIDRs
+	if in.TPM != nil {
+		in, out := &in.TPM, &out.TPM
+		*out = new(HostTPMSpec)
+		if err := Convert_kops_HostTPMSpec_To_v1alpha3_HostTPMSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.TPM = nil
+	}
 	return nil
 }
 
@@ -4609,6 +5061,46 @@ func Convert_kops_HostSpec_To_v1alpha3_HostSpec(in *kops.HostSpec, out *HostSpec
 	return autoConvert_kops_HostSpec_To_v1alpha3_HostSpec(in, out, s)
 }
 
+func autoConvert_v1alpha3_HostStatus_To_kops_HostStatus(in *HostStatus, out *kops.HostStatus, s conversion.Scope) error {
+	out.PodCIDRs = in.PodCIDRs
+	return nil
+}
+
+// Convert_v1alpha3_HostStatus_To_kops_HostStatus is an autogenerated conversion function.
+func Convert_v1alpha3_HostStatus_To_kops_HostStatus(in *HostStatus, out *kops.HostStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha3_HostStatus_To_kops_HostStatus(in, out, s)
+}
+
+func autoConvert_kops_HostStatus_To_v1alpha3_HostStatus(in *kops.HostStatus, out *HostStatus, s conversion.Scope) error {
+	out.PodCIDRs = in.PodCIDRs
+	return nil
+}
+
+// Convert_kops_HostStatus_To_v1alpha3_HostStatus is an autogenerated conversion function.
+func Convert_kops_HostStatus_To_v1alpha3_HostStatus(in *kops.HostStatus, out *HostStatus, s conversion.Scope) error {
+	return autoConvert_kops_HostStatus_To_v1alpha3_HostStatus(in, out, s)
+}
+
+func autoConvert_v1alpha3_HostTPMSpec_To_kops_HostTPMSpec(in *HostTPMSpec, out *kops.HostTPMSpec, s conversion.Scope) error {
+	out.EKPublicKeyHash = in.EKPublicKeyHash
+	return nil
+}
+
+// Convert_v1alpha3_HostTPMSpec_To_kops_HostTPMSpec is an autogenerated conversion function.
+func Convert_v1alpha3_HostTPMSpec_To_kops_HostTPMSpec(in *HostTPMSpec, out *kops.HostTPMSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha3_HostTPMSpec_To_kops_HostTPMSpec(in, out, s)
+}
+
+func autoConvert_kops_HostTPMSpec_To_v1alpha3_HostTPMSpec(in *kops.HostTPMSpec, out *HostTPMSpec, s conversion.Scope) error {
+	out.EKPublicKeyHash = in.EKPublicKeyHash
+	return nil
+}
+
+// Convert_kops_HostTPMSpec_To_v1alpha3_HostTPMSpec is an autogenerated conversion function.
+func Convert_kops_HostTPMSpec_To_v1alpha3_HostTPMSpec(in *kops.HostTPMSpec, out *HostTPMSpec, s conversion.Scope) error {
+	return autoConvert_kops_HostTPMSpec_To_v1alpha3_HostTPMSpec(in, out, s)
+}
+
 func autoConvert_v1alpha3_HubbleSpec_To_kops_HubbleSpec(in *HubbleSpec, out *kops.HubbleSpec, s conversion.Scope) error {
 	out.Enabled = in.Enabled
 	out.Metrics = in.Metrics
@@ -4704,6 +5196,15 @@ func autoConvert_v1alpha3_InstanceGroup_To_kops_InstanceGroup(in *InstanceGroup,
 	if err := Convert_v1alpha3_InstanceGroupSpec_To_kops_InstanceGroupSpec(&in.Spec, &out.Spec, s); err != nil {
 		return err
 	}
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(kops.InstanceGroupStatus)
+		if err := Convert_v1alpha3_InstanceGroupStatus_To_kops_InstanceGroupStatus(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Status = nil
+	}
 	return nil
 }
 
@@ -4717,6 +5218,15 @@ func autoConvert_kops_InstanceGroup_To_v1alpha3_InstanceGroup(in *kops.InstanceG
 	if err := Convert_kops_InstanceGroupSpec_To_v1alpha3_InstanceGroupSpec(&in.Spec, &out.Spec, s); err != nil {
 		return err
 	}
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(InstanceGroupStatus)
+		if err := Convert_kops_InstanceGroupStatus_To_v1alpha3_InstanceGroupStatus(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Status = nil
+	}
 	return nil
 }
 
@@ -5145,6 +5655,28 @@ func Convert_kops_InstanceGroupSpec_To_v1alpha3_InstanceGroupSpec(in *kops.Insta
 	return autoConvert_kops_InstanceGroupSpec_To_v1alpha3_InstanceGroupSpec(in, out, s)
 }
 
+func autoConvert_v1alpha3_InstanceGroupStatus_To_kops_InstanceGroupStatus(in *InstanceGroupStatus, out *kops.InstanceGroupStatus, s conversion.Scope) error {
+	out.ObservedGeneration = in.ObservedGeneration
+	out.Conditions = in.Conditions
+	return nil
+}
+
+// Convert_v1alpha3_InstanceGroupStatus_To_kops_InstanceGroupStatus is an autogenerated conversion function.
+func Convert_v1alpha3_InstanceGroupStatus_To_kops_InstanceGroupStatus(in *InstanceGroupStatus, out *kops.InstanceGroupStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha3_InstanceGroupStatus_To_kops_InstanceGroupStatus(in, out, s)
+}
+
+func autoConvert_kops_InstanceGroupStatus_To_v1alpha3_InstanceGroupStatus(in *kops.InstanceGroupStatus, out *InstanceGroupStatus, s conversion.Scope) error {
+	out.ObservedGeneration = in.ObservedGeneration
+	out.Conditions = in.Conditions
+	return nil
+}
+
+// Convert_kops_InstanceGroupStatus_To_v1alpha3_InstanceGroupStatus is an autogenerated conversion function.
+func Convert_kops_InstanceGroupStatus_To_v1alpha3_InstanceGroupStatus(in *kops.InstanceGroupStatus, out *InstanceGroupStatus, s conversion.Scope) error {
+	return autoConvert_kops_InstanceGroupStatus_To_v1alpha3_InstanceGroupStatus(in, out, s)
+}
+
 func autoConvert_v1alpha3_InstanceMetadataOptions_To_kops_InstanceMetadataOptions(in *InstanceMetadataOptions, out *kops.InstanceMetadataOptions, s conversion.Scope) error {
 	out.HTTPPutResponseHopLimit = in.HTTPPutResponseHopLimit
 	out.HTTPTokens = in.HTTPTokens
@@ -6677,6 +7209,40 @@ func Convert_kops_MixedInstancesPolicySpec_To_v1alpha3_MixedInstancesPolicySpec(
 	return autoConvert_kops_MixedInstancesPolicySpec_To_v1alpha3_MixedInstancesPolicySpec(in, out, s)
 }
 
+func autoConvert_v1alpha3_MonitoringSpec_To_kops_MonitoringSpec(in *MonitoringSpec, out *kops.MonitoringSpec, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.Image = in.Image
+	out.RemoteWriteURL = in.RemoteWriteURL
+	out.ScrapeInterval = in.ScrapeInterval
+	out.MemoryRequest = in.MemoryRequest
+	out.CPURequest = in.CPURequest
+	out.MemoryLimit = in.MemoryLimit
+	out.ServiceMonitors = in.ServiceMonitors
+	return nil
+}
+
+// Convert_v1alpha3_MonitoringSpec_To_kops_MonitoringSpec is an autogenerated conversion function.
+func Convert_v1alpha3_MonitoringSpec_To_kops_MonitoringSpec(in *MonitoringSpec, out *kops.MonitoringSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha3_MonitoringSpec_To_kops_MonitoringSpec(in, out, s)
+}
+
+func autoConvert_kops_MonitoringSpec_To_v1alpha3_MonitoringSpec(in *kops.MonitoringSpec, out *MonitoringSpec, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.Image = in.Image
+	out.RemoteWriteURL = in.RemoteWriteURL
+	out.ScrapeInterval = in.ScrapeInterval
+	out.MemoryRequest = in.MemoryRequest
+	out.CPURequest = in.CPURequest
+	out.MemoryLimit = in.MemoryLimit
+	out.ServiceMonitors = in.ServiceMonitors
+	return nil
+}
+
+// Convert_kops_MonitoringSpec_To_v1alpha3_MonitoringSpec is an autogenerated conversion function.
+func Convert_kops_MonitoringSpec_To_v1alpha3_MonitoringSpec(in *kops.MonitoringSpec, out *MonitoringSpec, s conversion.Scope) error {
+	return autoConvert_kops_MonitoringSpec_To_v1alpha3_MonitoringSpec(in, out, s)
+}
+
 func autoConvert_v1alpha3_NRIConfig_To_kops_NRIConfig(in *NRIConfig, out *kops.NRIConfig, s conversion.Scope) error {
 	out.Enabled = in.Enabled
 	out.PluginRegistrationTimeout = in.PluginRegistrationTimeout
@@ -7053,6 +7619,46 @@ func Convert_kops_NetworkingSpec_To_v1alpha3_NetworkingSpec(in *kops.NetworkingS
 	return autoConvert_kops_NetworkingSpec_To_v1alpha3_NetworkingSpec(in, out, s)
 }
 
+func autoConvert_v1alpha3_NodeConfigAgentSpec_To_kops_NodeConfigAgentSpec(in *NodeConfigAgentSpec, out *kops.NodeConfigAgentSpec, s conversion.Scope) error {
+	out.Interval = in.Interval
+	out.MaxUnavailable = in.MaxUnavailable
+	return nil
+}
+
+// Convert_v1alpha3_NodeConfigAgentSpec_To_kops_NodeConfigAgentSpec is an autogenerated conversion function.
+func Convert_v1alpha3_NodeConfigAgentSpec_To_kops_NodeConfigAgentSpec(in *NodeConfigAgentSpec, out *kops.NodeConfigAgentSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha3_NodeConfigAgentSpec_To_kops_NodeConfigAgentSpec(in, out, s)
+}
+
+func autoConvert_kops_NodeConfigAgentSpec_To_v1alpha3_NodeConfigAgentSpec(in *kops.NodeConfigAgentSpec, out *NodeConfigAgentSpec, s conversion.Scope) error {
+	out.Interval = in.Interval
+	out.MaxUnavailable = in.MaxUnavailable
+	return nil
+}
+
+// Convert_kops_NodeConfigAgentSpec_To_v1alpha3_NodeConfigAgentSpec is an autogenerated conversion function.
+func Convert_kops_NodeConfigAgentSpec_To_v1alpha3_NodeConfigAgentSpec(in *kops.NodeConfigAgentSpec, out *NodeConfigAgentSpec, s conversion.Scope) error {
+	return autoConvert_kops_NodeConfigAgentSpec_To_v1alpha3_NodeConfigAgentSpec(in, out, s)
+}
+
+func autoConvert_v1alpha3_NodeIdentityRevocationSpec_To_kops_NodeIdentityRevocationSpec(in *NodeIdentityRevocationSpec, out *kops.NodeIdentityRevocationSpec, s conversion.Scope) error {
+	return nil
+}
+
+// Convert_v1alpha3_NodeIdentityRevocationSpec_To_kops_NodeIdentityRevocationSpec is an autogenerated conversion function.
+func Convert_v1alpha3_NodeIdentityRevocationSpec_To_kops_NodeIdentityRevocationSpec(in *NodeIdentityRevocationSpec, out *kops.NodeIdentityRevocationSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha3_NodeIdentityRevocationSpec_To_kops_NodeIdentityRevocationSpec(in, out, s)
+}
+
+func autoConvert_kops_NodeIdentityRevocationSpec_To_v1alpha3_NodeIdentityRevocationSpec(in *kops.NodeIdentityRevocationSpec, out *NodeIdentityRevocationSpec, s conversion.Scope) error {
+	return nil
+}
+
+// Convert_kops_NodeIdentityRevocationSpec_To_v1alpha3_NodeIdentityRevocationSpec is an autogenerated conversion function.
+func Convert_kops_NodeIdentityRevocationSpec_To_v1alpha3_NodeIdentityRevocationSpec(in *kops.NodeIdentityRevocationSpec, out *NodeIdentityRevocationSpec, s conversion.Scope) error {
+	return autoConvert_kops_NodeIdentityRevocationSpec_To_v1alpha3_NodeIdentityRevocationSpec(in, out, s)
+}
+
 func autoConvert_v1alpha3_NodeLocalDNSConfig_To_kops_NodeLocalDNSConfig(in *NodeLocalDNSConfig, out *kops.NodeLocalDNSConfig, s conversion.Scope) error {
 	out.Enabled = in.Enabled
 	out.ExternalCoreFile = in.ExternalCoreFile
@@ -7639,6 +8245,24 @@ func Convert_kops_RBACAuthorizationSpec_To_v1alpha3_RBACAuthorizationSpec(in *ko
 	return autoConvert_kops_RBACAuthorizationSpec_To_v1alpha3_RBACAuthorizationSpec(in, out, s)
 }
 
+func autoConvert_v1alpha3_ReconcilerSpec_To_kops_ReconcilerSpec(in *ReconcilerSpec, out *kops.ReconcilerSpec, s conversion.Scope) error {
+	return nil
+}
+
+// Convert_v1alpha3_ReconcilerSpec_To_kops_ReconcilerSpec is an autogenerated conversion function.
+func Convert_v1alpha3_ReconcilerSpec_To_kops_ReconcilerSpec(in *ReconcilerSpec, out *kops.ReconcilerSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha3_ReconcilerSpec_To_kops_ReconcilerSpec(in, out, s)
+}
+
+func autoConvert_kops_ReconcilerSpec_To_v1alpha3_ReconcilerSpec(in *kops.ReconcilerSpec, out *ReconcilerSpec, s conversion.Scope) error {
+	return nil
+}
+
+// Convert_kops_ReconcilerSpec_To_v1alpha3_ReconcilerSpec is an autogenerated conversion function.
+func Convert_kops_ReconcilerSpec_To_v1alpha3_ReconcilerSpec(in *kops.ReconcilerSpec, out *ReconcilerSpec, s conversion.Scope) error {
+	return autoConvert_kops_ReconcilerSpec_To_v1alpha3_ReconcilerSpec(in, out, s)
+}
+
 func autoConvert_v1alpha3_RollingUpdate_To_kops_RollingUpdate(in *RollingUpdate, out *kops.RollingUpdate, s conversion.Scope) error {
 	out.DrainAndTerminate = in.DrainAndTerminate
 	out.MaxUnavailable = in.MaxUnavailable
@@ -7915,6 +8539,70 @@ func Convert_kops_SnapshotControllerConfig_To_v1alpha3_SnapshotControllerConfig(
 	return autoConvert_kops_SnapshotControllerConfig_To_v1alpha3_SnapshotControllerConfig(in, out, s)
 }
 
+func autoConvert_v1alpha3_TPMAttestationSpec_To_kops_TPMAttestationSpec(in *TPMAttestationSpec, out *kops.TPMAttestationSpec, s conversion.Scope) error {
+	out.EKRootCAs = in.EKRootCAs
+	if in.PCRs != nil {
+		in, out := &in.PCRs, &out.PCRs
+		*out = make([]kops.TPMPCRSpec, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha3_TPMPCRSpec_To_kops_TPMPCRSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.PCRs = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha3_TPMAttestationSpec_To_kops_TPMAttestationSpec is an autogenerated conversion function.
+func Convert_v1alpha3_TPMAttestationSpec_To_kops_TPMAttestationSpec(in *TPMAttestationSpec, out *kops.TPMAttestationSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha3_TPMAttestationSpec_To_kops_TPMAttestationSpec(in, out, s)
+}
+
+func autoConvert_kops_TPMAttestationSpec_To_v1alpha3_TPMAttestationSpec(in *kops.TPMAttestationSpec, out *TPMAttestationSpec, s conversion.Scope) error {
+	out.EKRootCAs = in.EKRootCAs
+	if in.PCRs != nil {
+		in, out := &in.PCRs, &out.PCRs
+		*out = make([]TPMPCRSpec, len(*in))
+		for i := range *in {
+			if err := Convert_kops_TPMPCRSpec_To_v1alpha3_TPMPCRSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.PCRs = nil
+	}
+	return nil
+}
+
+// Convert_kops_TPMAttestationSpec_To_v1alpha3_TPMAttestationSpec is an autogenerated conversion function.
+func Convert_kops_TPMAttestationSpec_To_v1alpha3_TPMAttestationSpec(in *kops.TPMAttestationSpec, out *TPMAttestationSpec, s conversion.Scope) error {
+	return autoConvert_kops_TPMAttestationSpec_To_v1alpha3_TPMAttestationSpec(in, out, s)
+}
+
+func autoConvert_v1alpha3_TPMPCRSpec_To_kops_TPMPCRSpec(in *TPMPCRSpec, out *kops.TPMPCRSpec, s conversion.Scope) error {
+	out.Index = in.Index
+	out.Values = in.Values
+	return nil
+}
+
+// Convert_v1alpha3_TPMPCRSpec_To_kops_TPMPCRSpec is an autogenerated conversion function.
+func Convert_v1alpha3_TPMPCRSpec_To_kops_TPMPCRSpec(in *TPMPCRSpec, out *kops.TPMPCRSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha3_TPMPCRSpec_To_kops_TPMPCRSpec(in, out, s)
+}
+
+func autoConvert_kops_TPMPCRSpec_To_v1alpha3_TPMPCRSpec(in *kops.TPMPCRSpec, out *TPMPCRSpec, s conversion.Scope) error {
+	out.Index = in.Index
+	out.Values = in.Values
+	return nil
+}
+
+// Convert_kops_TPMPCRSpec_To_v1alpha3_TPMPCRSpec is an autogenerated conversion function.
+func Convert_kops_TPMPCRSpec_To_v1alpha3_TPMPCRSpec(in *kops.TPMPCRSpec, out *TPMPCRSpec, s conversion.Scope) error {
+	return autoConvert_kops_TPMPCRSpec_To_v1alpha3_TPMPCRSpec(in, out, s)
+}
+
 func autoConvert_v1alpha3_TargetSpec_To_kops_TargetSpec(in *TargetSpec, out *kops.TargetSpec, s conversion.Scope) error {
 	if in.Terraform != nil {
 		in, out := &in.Terraform, &out.Terraform
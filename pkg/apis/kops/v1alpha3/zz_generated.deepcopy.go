@@ -263,6 +263,27 @@ func (in *AccessLogSpec) DeepCopy() *AccessLogSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AddonSigningSpec) DeepCopyInto(out *AddonSigningSpec) {
+	*out = *in
+	if in.AdditionalTrustedKeys != nil {
+		in, out := &in.AdditionalTrustedKeys, &out.AdditionalTrustedKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddonSigningSpec.
+func (in *AddonSigningSpec) DeepCopy() *AddonSigningSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AddonSigningSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AddonSpec) DeepCopyInto(out *AddonSpec) {
 	*out = *in
@@ -279,6 +300,27 @@ func (in *AddonSpec) DeepCopy() *AddonSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdminCredentialsSpec) DeepCopyInto(out *AdminCredentialsSpec) {
+	*out = *in
+	if in.MaxLifetime != nil {
+		in, out := &in.MaxLifetime, &out.MaxLifetime
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdminCredentialsSpec.
+func (in *AdminCredentialsSpec) DeepCopy() *AdminCredentialsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AdminCredentialsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AlwaysAllowAuthorizationSpec) DeepCopyInto(out *AlwaysAllowAuthorizationSpec) {
 	*out = *in
@@ -365,6 +407,11 @@ func (in *AuthenticationSpec) DeepCopyInto(out *AuthenticationSpec) {
 		*out = new(OIDCAuthenticationSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.AdminCredentials != nil {
+		in, out := &in.AdminCredentials, &out.AdminCredentials
+		*out = new(AdminCredentialsSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -462,6 +509,22 @@ func (in *BastionSpec) DeepCopy() *BastionSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BootstrapAuditSpec) DeepCopyInto(out *BootstrapAuditSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapAuditSpec.
+func (in *BootstrapAuditSpec) DeepCopy() *BootstrapAuditSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BootstrapAuditSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CNINetworkingSpec) DeepCopyInto(out *CNINetworkingSpec) {
 	*out = *in
@@ -1104,7 +1167,12 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = make([]AddonSpec, len(*in))
 		copy(*out, *in)
 	}
-	out.ConfigStore = in.ConfigStore
+	if in.AddonSigning != nil {
+		in, out := &in.AddonSigning, &out.AddonSigning
+		*out = new(AddonSigningSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ConfigStore.DeepCopyInto(&out.ConfigStore)
 	in.CloudProvider.DeepCopyInto(&out.CloudProvider)
 	if in.GossipConfig != nil {
 		in, out := &in.GossipConfig, &out.GossipConfig
@@ -1257,6 +1325,16 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(CertManagerConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Monitoring != nil {
+		in, out := &in.Monitoring, &out.Monitoring
+		*out = new(MonitoringSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GatewayAPI != nil {
+		in, out := &in.GatewayAPI, &out.GatewayAPI
+		*out = new(GatewayAPISpec)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Networking.DeepCopyInto(&out.Networking)
 	in.API.DeepCopyInto(&out.API)
 	if in.Authentication != nil {
@@ -1274,6 +1352,31 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(kops.NodeAuthorizationSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.BootstrapAudit != nil {
+		in, out := &in.BootstrapAudit, &out.BootstrapAudit
+		*out = new(BootstrapAuditSpec)
+		**out = **in
+	}
+	if in.TPMAttestation != nil {
+		in, out := &in.TPMAttestation, &out.TPMAttestation
+		*out = new(TPMAttestationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeConfigAgent != nil {
+		in, out := &in.NodeConfigAgent, &out.NodeConfigAgent
+		*out = new(NodeConfigAgentSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Reconciler != nil {
+		in, out := &in.Reconciler, &out.Reconciler
+		*out = new(ReconcilerSpec)
+		**out = **in
+	}
+	if in.NodeIdentityRevocation != nil {
+		in, out := &in.NodeIdentityRevocation, &out.NodeIdentityRevocation
+		*out = new(NodeIdentityRevocationSpec)
+		**out = **in
+	}
 	if in.CloudLabels != nil {
 		in, out := &in.CloudLabels, &out.CloudLabels
 		*out = make(map[string]string, len(*in))
@@ -1377,9 +1480,30 @@ func (in *ClusterSubnetSpec) DeepCopy() *ClusterSubnetSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigStoreEncryptionSpec) DeepCopyInto(out *ConfigStoreEncryptionSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigStoreEncryptionSpec.
+func (in *ConfigStoreEncryptionSpec) DeepCopy() *ConfigStoreEncryptionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigStoreEncryptionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigStoreSpec) DeepCopyInto(out *ConfigStoreSpec) {
 	*out = *in
+	if in.Encryption != nil {
+		in, out := &in.Encryption, &out.Encryption
+		*out = new(ConfigStoreEncryptionSpec)
+		**out = **in
+	}
 	return
 }
 
@@ -2203,6 +2327,27 @@ func (in *GCPNetworkingSpec) DeepCopy() *GCPNetworkingSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayAPISpec) DeepCopyInto(out *GatewayAPISpec) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayAPISpec.
+func (in *GatewayAPISpec) DeepCopy() *GatewayAPISpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayAPISpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GossipConfig) DeepCopyInto(out *GossipConfig) {
 	*out = *in
@@ -2349,6 +2494,7 @@ func (in *Host) DeepCopyInto(out *Host) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -2411,6 +2557,11 @@ func (in *HostSpec) DeepCopyInto(out *HostSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.TPM != nil {
+		in, out := &in.TPM, &out.TPM
+		*out = new(HostTPMSpec)
+		**out = **in
+	}
 	return
 }
 
@@ -2424,6 +2575,43 @@ func (in *HostSpec) DeepCopy() *HostSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostStatus) DeepCopyInto(out *HostStatus) {
+	*out = *in
+	if in.PodCIDRs != nil {
+		in, out := &in.PodCIDRs, &out.PodCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostStatus.
+func (in *HostStatus) DeepCopy() *HostStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HostStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostTPMSpec) DeepCopyInto(out *HostTPMSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostTPMSpec.
+func (in *HostTPMSpec) DeepCopy() *HostTPMSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HostTPMSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HubbleSpec) DeepCopyInto(out *HubbleSpec) {
 	*out = *in
@@ -2510,6 +2698,11 @@ func (in *InstanceGroup) DeepCopyInto(out *InstanceGroup) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(InstanceGroupStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -2794,6 +2987,29 @@ func (in *InstanceGroupSpec) DeepCopy() *InstanceGroupSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceGroupStatus) DeepCopyInto(out *InstanceGroupStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceGroupStatus.
+func (in *InstanceGroupStatus) DeepCopy() *InstanceGroupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceGroupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InstanceMetadataOptions) DeepCopyInto(out *InstanceMetadataOptions) {
 	*out = *in
@@ -4747,6 +4963,57 @@ func (in *MixedInstancesPolicySpec) DeepCopy() *MixedInstancesPolicySpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitoringSpec) DeepCopyInto(out *MonitoringSpec) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(string)
+		**out = **in
+	}
+	if in.ScrapeInterval != nil {
+		in, out := &in.ScrapeInterval, &out.ScrapeInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MemoryRequest != nil {
+		in, out := &in.MemoryRequest, &out.MemoryRequest
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.CPURequest != nil {
+		in, out := &in.CPURequest, &out.CPURequest
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MemoryLimit != nil {
+		in, out := &in.MemoryLimit, &out.MemoryLimit
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.ServiceMonitors != nil {
+		in, out := &in.ServiceMonitors, &out.ServiceMonitors
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringSpec.
+func (in *MonitoringSpec) DeepCopy() *MonitoringSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitoringSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NRIConfig) DeepCopyInto(out *NRIConfig) {
 	*out = *in
@@ -4927,6 +5194,48 @@ func (in *NetworkingSpec) DeepCopy() *NetworkingSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeConfigAgentSpec) DeepCopyInto(out *NodeConfigAgentSpec) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeConfigAgentSpec.
+func (in *NodeConfigAgentSpec) DeepCopy() *NodeConfigAgentSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeConfigAgentSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeIdentityRevocationSpec) DeepCopyInto(out *NodeIdentityRevocationSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeIdentityRevocationSpec.
+func (in *NodeIdentityRevocationSpec) DeepCopy() *NodeIdentityRevocationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeIdentityRevocationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeLocalDNSConfig) DeepCopyInto(out *NodeLocalDNSConfig) {
 	*out = *in
@@ -5631,6 +5940,22 @@ func (in *RBACAuthorizationSpec) DeepCopy() *RBACAuthorizationSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReconcilerSpec) DeepCopyInto(out *ReconcilerSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReconcilerSpec.
+func (in *ReconcilerSpec) DeepCopy() *ReconcilerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ReconcilerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RollingUpdate) DeepCopyInto(out *RollingUpdate) {
 	*out = *in
@@ -5859,6 +6184,55 @@ func (in *SnapshotControllerConfig) DeepCopy() *SnapshotControllerConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TPMAttestationSpec) DeepCopyInto(out *TPMAttestationSpec) {
+	*out = *in
+	if in.EKRootCAs != nil {
+		in, out := &in.EKRootCAs, &out.EKRootCAs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PCRs != nil {
+		in, out := &in.PCRs, &out.PCRs
+		*out = make([]TPMPCRSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TPMAttestationSpec.
+func (in *TPMAttestationSpec) DeepCopy() *TPMAttestationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TPMAttestationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TPMPCRSpec) DeepCopyInto(out *TPMPCRSpec) {
+	*out = *in
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TPMPCRSpec.
+func (in *TPMPCRSpec) DeepCopy() *TPMPCRSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TPMPCRSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TargetSpec) DeepCopyInto(out *TargetSpec) {
 	*out = *in
@@ -17,6 +17,8 @@ limitations under the License.
 package validation
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"net"
@@ -40,9 +42,13 @@ import (
 	"k8s.io/kops/pkg/util/subnet"
 	netutils "k8s.io/utils/net"
 
+	"k8s.io/kops/channels/pkg/signature"
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/model"
+	"k8s.io/kops/pkg/envelope"
 	"k8s.io/kops/pkg/model/components"
 	"k8s.io/kops/pkg/model/iam"
+	"k8s.io/kops/pkg/pki"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/utils"
 )
@@ -296,6 +302,34 @@ func validateClusterSpec(spec *kops.ClusterSpec, c *kops.Cluster, fieldPath *fie
 		allErrs = append(allErrs, validateCertManager(c, spec.CertManager, fieldPath.Child("certManager"))...)
 	}
 
+	if spec.Monitoring != nil && fi.ValueOf(spec.Monitoring.Enabled) {
+		allErrs = append(allErrs, validateMonitoring(spec.Monitoring, fieldPath.Child("monitoring"))...)
+	}
+
+	if spec.GatewayAPI != nil && fi.ValueOf(spec.GatewayAPI.Enabled) {
+		allErrs = append(allErrs, validateGatewayAPI(c, spec.GatewayAPI, fieldPath.Child("gatewayAPI"))...)
+	}
+
+	if spec.Authentication != nil && spec.Authentication.AdminCredentials != nil {
+		allErrs = append(allErrs, validateAdminCredentials(spec.Authentication.AdminCredentials, fieldPath.Child("authentication", "adminCredentials"))...)
+	}
+
+	if spec.AddonSigning != nil {
+		allErrs = append(allErrs, validateAddonSigning(spec.AddonSigning, fieldPath.Child("addonSigning"))...)
+	}
+
+	if spec.ConfigStore.Encryption != nil {
+		allErrs = append(allErrs, validateConfigStoreEncryption(spec.ConfigStore.Encryption, fieldPath.Child("configStore", "encryption"))...)
+	}
+
+	if spec.TPMAttestation != nil {
+		allErrs = append(allErrs, validateTPMAttestation(spec.TPMAttestation, fieldPath.Child("tpmAttestation"))...)
+	}
+
+	if spec.NodeConfigAgent != nil {
+		allErrs = append(allErrs, validateNodeConfigAgent(c, spec.NodeConfigAgent, fieldPath.Child("nodeConfigAgent"))...)
+	}
+
 	return allErrs
 }
 
@@ -1980,6 +2014,113 @@ func validatePodIdentityWebhook(cluster *kops.Cluster, spec *kops.PodIdentityWeb
 	return allErrs
 }
 
+func validateMonitoring(spec *kops.MonitoringSpec, fldPath *field.Path) (allErrs field.ErrorList) {
+	if spec.RemoteWriteURL == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("remoteWriteURL"), "remoteWriteURL is required when monitoring is enabled"))
+	} else if u, err := url.Parse(spec.RemoteWriteURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("remoteWriteURL"), spec.RemoteWriteURL, "must be an http or https URL"))
+	}
+	if spec.ScrapeInterval != nil && spec.ScrapeInterval.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("scrapeInterval"), spec.ScrapeInterval.Duration.String(), "must be greater than zero"))
+	}
+	return allErrs
+}
+
+func validateGatewayAPI(cluster *kops.Cluster, spec *kops.GatewayAPISpec, fldPath *field.Path) (allErrs field.ErrorList) {
+	if spec.Channel != "" {
+		allErrs = append(allErrs, IsValidValue(fldPath.Child("channel"), &spec.Channel, []string{kops.GatewayAPIChannelStandard, kops.GatewayAPIChannelExperimental})...)
+	}
+	if spec.Version != "" {
+		allErrs = append(allErrs, IsValidValue(fldPath.Child("version"), &spec.Version, components.GatewayAPIVersions)...)
+	}
+
+	// The experimental resources are needed by some Gateway implementations; managing the standard channel
+	// would remove CRDs that they watch, such as the TCPRoute and TLSRoute CRDs.
+	if requiredBy := components.GatewayAPIExperimentalRequiredBy(cluster); requiredBy != "" && spec.Channel == kops.GatewayAPIChannelStandard {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("channel"), requiredBy+" requires the experimental channel"))
+	}
+
+	// kOps manages no Gateway implementation of its own on GCE
+	if cluster.GetCloudProvider() == kops.CloudProviderGCE {
+		if cilium := cluster.Spec.Networking.Cilium; cilium == nil || cilium.GatewayAPI == nil || !fi.ValueOf(cilium.GatewayAPI.Enabled) {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("enabled"), "Gateway API is only supported on GCE with Cilium Gateway API support"))
+		}
+	}
+
+	return allErrs
+}
+
+func validateAdminCredentials(spec *kops.AdminCredentialsSpec, fldPath *field.Path) (allErrs field.ErrorList) {
+	if spec.MaxLifetime != nil && spec.MaxLifetime.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxLifetime"), spec.MaxLifetime.Duration.String(), "must be greater than zero"))
+	}
+	return allErrs
+}
+
+func validateAddonSigning(spec *kops.AddonSigningSpec, fldPath *field.Path) (allErrs field.ErrorList) {
+	if !spec.Enabled && len(spec.AdditionalTrustedKeys) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("additionalTrustedKeys"), "additionalTrustedKeys requires addon signing to be enabled"))
+	}
+	for i, key := range spec.AdditionalTrustedKeys {
+		if _, err := signature.ParseVerifier([]byte(key)); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("additionalTrustedKeys").Index(i), key, fmt.Sprintf("must be a PEM-encoded public key: %v", err)))
+		}
+	}
+	return allErrs
+}
+
+func validateConfigStoreEncryption(spec *kops.ConfigStoreEncryptionSpec, fldPath *field.Path) (allErrs field.ErrorList) {
+	allErrs = append(allErrs, IsValidValue(fldPath.Child("provider"), &spec.Provider, envelope.Providers)...)
+	if spec.KeyID == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("keyID"), ""))
+	} else if spec.Provider == envelope.ProviderAWSKMS && strings.HasPrefix(spec.KeyID, "arn:") {
+		if _, err := arn.Parse(spec.KeyID); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("keyID"), spec.KeyID, "must be a valid ARN"))
+		}
+	}
+	return allErrs
+}
+
+func validateTPMAttestation(spec *kops.TPMAttestationSpec, fldPath *field.Path) (allErrs field.ErrorList) {
+	for i, ca := range spec.EKRootCAs {
+		if _, err := pki.ParsePEMCertificate([]byte(ca)); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("ekRootCAs").Index(i), ca, fmt.Sprintf("must be a PEM-encoded certificate: %v", err)))
+		}
+	}
+	indexes := sets.New[int32]()
+	for i, pcr := range spec.PCRs {
+		pcrPath := fldPath.Child("pcrs").Index(i)
+		if pcr.Index < 0 || pcr.Index > 23 {
+			allErrs = append(allErrs, field.Invalid(pcrPath.Child("index"), pcr.Index, "must be between 0 and 23"))
+		} else if indexes.Has(pcr.Index) {
+			allErrs = append(allErrs, field.Duplicate(pcrPath.Child("index"), pcr.Index))
+		}
+		indexes.Insert(pcr.Index)
+		if len(pcr.Values) == 0 {
+			allErrs = append(allErrs, field.Required(pcrPath.Child("values"), ""))
+		}
+		for j, value := range pcr.Values {
+			if b, err := hex.DecodeString(value); err != nil || len(b) != sha256.Size {
+				allErrs = append(allErrs, field.Invalid(pcrPath.Child("values").Index(j), value, "must be a hex-encoded SHA-256 digest"))
+			}
+		}
+	}
+	return allErrs
+}
+
+func validateNodeConfigAgent(cluster *kops.Cluster, spec *kops.NodeConfigAgentSpec, fldPath *field.Path) (allErrs field.ErrorList) {
+	if !model.UseKopsControllerForNodeConfig(cluster) {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "the node configuration agent requires nodes to get their configuration from kops-controller"))
+	}
+	if spec.Interval != nil && spec.Interval.Duration < 30*time.Second {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("interval"), spec.Interval.Duration.String(), "must be at least 30s"))
+	}
+	if spec.MaxUnavailable != nil && *spec.MaxUnavailable < 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxUnavailable"), *spec.MaxUnavailable, "must be at least 1"))
+	}
+	return allErrs
+}
+
 func validateCertManager(cluster *kops.Cluster, spec *kops.CertManagerConfig, fldPath *field.Path) (allErrs field.ErrorList) {
 	if len(spec.HostedZoneIDs) > 0 {
 		if !fi.ValueOf(cluster.Spec.IAM.UseServiceAccountExternalPermissions) {
@@ -17,6 +17,11 @@ limitations under the License.
 package validation
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
 	"net"
 	"testing"
 	"time"
@@ -1851,3 +1856,360 @@ func Test_Validate_NriConfig(t *testing.T) {
 		testErrors(t, g.Input.Containerd, errs, g.ExpectedErrors)
 	}
 }
+
+func Test_Validate_Monitoring(t *testing.T) {
+	grid := []struct {
+		Input          kops.MonitoringSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.MonitoringSpec{
+				RemoteWriteURL: "https://prometheus.example.com/api/v1/write",
+			},
+		},
+		{
+			Input:          kops.MonitoringSpec{},
+			ExpectedErrors: []string{"Required value::monitoring.remoteWriteURL"},
+		},
+		{
+			Input: kops.MonitoringSpec{
+				RemoteWriteURL: "prometheus.example.com",
+			},
+			ExpectedErrors: []string{"Invalid value::monitoring.remoteWriteURL"},
+		},
+		{
+			Input: kops.MonitoringSpec{
+				RemoteWriteURL: "https://prometheus.example.com/api/v1/write",
+				ScrapeInterval: &metav1.Duration{},
+			},
+			ExpectedErrors: []string{"Invalid value::monitoring.scrapeInterval"},
+		},
+	}
+	for _, g := range grid {
+		errs := validateMonitoring(&g.Input, field.NewPath("monitoring"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_GatewayAPI(t *testing.T) {
+	grid := []struct {
+		Input          kops.GatewayAPISpec
+		Cilium         *kops.CiliumNetworkingSpec
+		CloudProvider  kops.CloudProviderSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.GatewayAPISpec{
+				Channel: kops.GatewayAPIChannelStandard,
+				Version: "v1.4.0",
+			},
+		},
+		{
+			Input: kops.GatewayAPISpec{
+				Channel: "alpha",
+			},
+			ExpectedErrors: []string{"Unsupported value::gatewayAPI.channel"},
+		},
+		{
+			Input: kops.GatewayAPISpec{
+				Version: "v0.1.0",
+			},
+			ExpectedErrors: []string{"Unsupported value::gatewayAPI.version"},
+		},
+		{
+			Input: kops.GatewayAPISpec{
+				Channel: kops.GatewayAPIChannelStandard,
+			},
+			Cilium: &kops.CiliumNetworkingSpec{
+				GatewayAPI: &kops.CiliumGatewayAPISpec{Enabled: fi.PtrTo(true)},
+			},
+			ExpectedErrors: []string{"Forbidden::gatewayAPI.channel"},
+		},
+		{
+			Input: kops.GatewayAPISpec{
+				Channel: kops.GatewayAPIChannelExperimental,
+			},
+			Cilium: &kops.CiliumNetworkingSpec{
+				GatewayAPI: &kops.CiliumGatewayAPISpec{Enabled: fi.PtrTo(true)},
+			},
+		},
+		{
+			Input: kops.GatewayAPISpec{
+				Channel: kops.GatewayAPIChannelStandard,
+			},
+			CloudProvider: kops.CloudProviderSpec{
+				AWS: &kops.AWSSpec{
+					LoadBalancerController: &kops.LoadBalancerControllerSpec{Enabled: fi.PtrTo(true)},
+				},
+			},
+			ExpectedErrors: []string{"Forbidden::gatewayAPI.channel"},
+		},
+		{
+			Input: kops.GatewayAPISpec{
+				Channel: kops.GatewayAPIChannelExperimental,
+			},
+			CloudProvider: kops.CloudProviderSpec{
+				AWS: &kops.AWSSpec{
+					LoadBalancerController: &kops.LoadBalancerControllerSpec{Enabled: fi.PtrTo(true)},
+				},
+			},
+		},
+		{
+			Input: kops.GatewayAPISpec{
+				Channel: kops.GatewayAPIChannelStandard,
+			},
+			CloudProvider: kops.CloudProviderSpec{
+				GCE: &kops.GCESpec{},
+			},
+			ExpectedErrors: []string{"Forbidden::gatewayAPI.enabled"},
+		},
+		{
+			Input: kops.GatewayAPISpec{
+				Channel: kops.GatewayAPIChannelExperimental,
+			},
+			Cilium: &kops.CiliumNetworkingSpec{
+				GatewayAPI: &kops.CiliumGatewayAPISpec{Enabled: fi.PtrTo(true)},
+			},
+			CloudProvider: kops.CloudProviderSpec{
+				GCE: &kops.GCESpec{},
+			},
+		},
+	}
+	for _, g := range grid {
+		cluster := &kops.Cluster{
+			Spec: kops.ClusterSpec{
+				CloudProvider: g.CloudProvider,
+				Networking: kops.NetworkingSpec{
+					Cilium: g.Cilium,
+				},
+			},
+		}
+		errs := validateGatewayAPI(cluster, &g.Input, field.NewPath("gatewayAPI"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_AddonSigning(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKey := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+
+	grid := []struct {
+		Input          kops.AddonSigningSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.AddonSigningSpec{
+				Enabled: true,
+			},
+		},
+		{
+			Input: kops.AddonSigningSpec{
+				Enabled:               true,
+				AdditionalTrustedKeys: []string{publicKey},
+			},
+		},
+		{
+			Input: kops.AddonSigningSpec{
+				AdditionalTrustedKeys: []string{publicKey},
+			},
+			ExpectedErrors: []string{"Forbidden::addonSigning.additionalTrustedKeys"},
+		},
+		{
+			Input: kops.AddonSigningSpec{
+				Enabled:               true,
+				AdditionalTrustedKeys: []string{"not a key"},
+			},
+			ExpectedErrors: []string{"Invalid value::addonSigning.additionalTrustedKeys[0]"},
+		},
+	}
+	for _, g := range grid {
+		errs := validateAddonSigning(&g.Input, field.NewPath("addonSigning"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_AdminCredentials(t *testing.T) {
+	grid := []struct {
+		Input          kops.AdminCredentialsSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.AdminCredentialsSpec{},
+		},
+		{
+			Input: kops.AdminCredentialsSpec{
+				MaxLifetime: &metav1.Duration{Duration: 8 * time.Hour},
+			},
+		},
+		{
+			Input: kops.AdminCredentialsSpec{
+				MaxLifetime: &metav1.Duration{},
+			},
+			ExpectedErrors: []string{"Invalid value::authentication.adminCredentials.maxLifetime"},
+		},
+	}
+	for _, g := range grid {
+		errs := validateAdminCredentials(&g.Input, field.NewPath("authentication", "adminCredentials"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_ConfigStoreEncryption(t *testing.T) {
+	grid := []struct {
+		Input          kops.ConfigStoreEncryptionSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.ConfigStoreEncryptionSpec{
+				Provider: "aws-kms",
+				KeyID:    "arn:aws:kms:us-test-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab",
+			},
+		},
+		{
+			Input: kops.ConfigStoreEncryptionSpec{
+				Provider: "vault-transit",
+				KeyID:    "transit/kops",
+			},
+		},
+		{
+			Input: kops.ConfigStoreEncryptionSpec{
+				Provider: "aws-kms",
+				KeyID:    "arn:aws:kms",
+			},
+			ExpectedErrors: []string{"Invalid value::configStore.encryption.keyID"},
+		},
+		{
+			Input: kops.ConfigStoreEncryptionSpec{
+				Provider: "gcp-kms",
+			},
+			ExpectedErrors: []string{"Required value::configStore.encryption.keyID"},
+		},
+		{
+			Input: kops.ConfigStoreEncryptionSpec{
+				Provider: "rot13",
+				KeyID:    "key",
+			},
+			ExpectedErrors: []string{"Unsupported value::configStore.encryption.provider"},
+		},
+	}
+	for _, g := range grid {
+		errs := validateConfigStoreEncryption(&g.Input, field.NewPath("configStore", "encryption"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_TPMAttestation(t *testing.T) {
+	pcrValue := "5f9a9d4e2c0b1e8f7a6d3c2b1a0f9e8d7c6b5a4938271605f4e3d2c1b0a99887"
+
+	grid := []struct {
+		Input          kops.TPMAttestationSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.TPMAttestationSpec{},
+		},
+		{
+			Input: kops.TPMAttestationSpec{
+				PCRs: []kops.TPMPCRSpec{{Index: 7, Values: []string{pcrValue}}},
+			},
+		},
+		{
+			Input: kops.TPMAttestationSpec{
+				EKRootCAs: []string{"not a certificate"},
+			},
+			ExpectedErrors: []string{"Invalid value::tpmAttestation.ekRootCAs[0]"},
+		},
+		{
+			Input: kops.TPMAttestationSpec{
+				PCRs: []kops.TPMPCRSpec{{Index: 24, Values: []string{pcrValue}}},
+			},
+			ExpectedErrors: []string{"Invalid value::tpmAttestation.pcrs[0].index"},
+		},
+		{
+			Input: kops.TPMAttestationSpec{
+				PCRs: []kops.TPMPCRSpec{
+					{Index: 7, Values: []string{pcrValue}},
+					{Index: 7, Values: []string{pcrValue}},
+				},
+			},
+			ExpectedErrors: []string{"Duplicate value::tpmAttestation.pcrs[1].index"},
+		},
+		{
+			Input: kops.TPMAttestationSpec{
+				PCRs: []kops.TPMPCRSpec{{Index: 0, Values: []string{"abcd"}}},
+			},
+			ExpectedErrors: []string{"Invalid value::tpmAttestation.pcrs[0].values[0]"},
+		},
+		{
+			Input: kops.TPMAttestationSpec{
+				PCRs: []kops.TPMPCRSpec{{Index: 0}},
+			},
+			ExpectedErrors: []string{"Required value::tpmAttestation.pcrs[0].values"},
+		},
+	}
+	for _, g := range grid {
+		errs := validateTPMAttestation(&g.Input, field.NewPath("tpmAttestation"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_NodeConfigAgent(t *testing.T) {
+	grid := []struct {
+		ClusterName    string
+		Input          kops.NodeConfigAgentSpec
+		ExpectedErrors []string
+	}{
+		{
+			ClusterName: "example.com",
+			Input:       kops.NodeConfigAgentSpec{},
+		},
+		{
+			ClusterName: "example.com",
+			Input: kops.NodeConfigAgentSpec{
+				Interval: &metav1.Duration{Duration: time.Minute},
+			},
+		},
+		{
+			ClusterName: "example.com",
+			Input: kops.NodeConfigAgentSpec{
+				Interval: &metav1.Duration{Duration: time.Second},
+			},
+			ExpectedErrors: []string{"Invalid value::nodeConfigAgent.interval"},
+		},
+		{
+			ClusterName: "example.com",
+			Input: kops.NodeConfigAgentSpec{
+				MaxUnavailable: fi.PtrTo(int32(3)),
+			},
+		},
+		{
+			ClusterName: "example.com",
+			Input: kops.NodeConfigAgentSpec{
+				MaxUnavailable: fi.PtrTo(int32(0)),
+			},
+			ExpectedErrors: []string{"Invalid value::nodeConfigAgent.maxUnavailable"},
+		},
+		{
+			ClusterName:    "example.k8s.local",
+			Input:          kops.NodeConfigAgentSpec{},
+			ExpectedErrors: []string{"Forbidden::nodeConfigAgent"},
+		},
+	}
+	for _, g := range grid {
+		cluster := &kops.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: g.ClusterName},
+			Spec: kops.ClusterSpec{
+				CloudProvider: kops.CloudProviderSpec{AWS: &kops.AWSSpec{}},
+			},
+		}
+		errs := validateNodeConfigAgent(cluster, &g.Input, field.NewPath("nodeConfigAgent"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
@@ -52,6 +52,36 @@ type BootstrapResponse struct {
 	NodeConfig *NodeConfig `json:"nodeConfig,omitempty"`
 }
 
+// RenewRequest is a request from a node to kops-controller to renew its certificates.
+// The node authenticates with its current kubelet client certificate instead of its cloud identity.
+type RenewRequest struct {
+	// APIVersion defines the versioned schema of this representation of a request.
+	APIVersion string `json:"apiVersion"`
+	// Certs are the requested certificates and their respective public keys.
+	Certs map[string]string `json:"certs"`
+	// KeypairIDs are the keypair IDs of the CAs to use for issuing certificates.
+	KeypairIDs map[string]string `json:"keypairIDs"`
+	// Current are the certificates being replaced, for those whose names are specific to the node (kubelet-server).
+	Current map[string]string `json:"current,omitempty"`
+}
+
+// NodeConfigRequest is a request from a running node to kops-controller for its current configuration.
+// The node authenticates with its current kubelet client certificate.
+type NodeConfigRequest struct {
+	// APIVersion defines the versioned schema of this representation of a request.
+	APIVersion string `json:"apiVersion"`
+	// Generation is the generation of the configuration that the node last applied, if any.
+	Generation string `json:"generation,omitempty"`
+}
+
+// NodeConfigResponse is the response to a NodeConfigRequest.
+type NodeConfigResponse struct {
+	// Generation identifies the node's current configuration.
+	Generation string `json:"generation"`
+	// NodeConfig is the node's current configuration; it is omitted if the node already applied this generation.
+	NodeConfig *NodeConfig `json:"nodeConfig,omitempty"`
+}
+
 // NodeConfig holds configuration needed to boot a node (without the kops state store)
 type NodeConfig struct {
 	// NodeupConfig holds the nodeup.Config for the node's instance group.
@@ -18,8 +18,10 @@ package nodeup
 
 import (
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/apis/kops/model"
 	"k8s.io/kops/util/pkg/architectures"
@@ -37,6 +39,8 @@ type Config struct {
 	ClusterName string `json:",omitempty"`
 	// Channels is a list of channels that we should apply
 	Channels []string `json:"channels,omitempty"`
+	// ChannelsVerifyKeys are PEM-encoded public keys; if set, channels and their manifests must be signed by one of them.
+	ChannelsVerifyKeys string `json:"channelsVerifyKeys,omitempty"`
 	// ApiserverAdditionalIPs are additional IP address to put in the apiserver server cert.
 	ApiserverAdditionalIPs []string `json:",omitempty"`
 	// KubernetesVersion is the version of Kubernetes to install.
@@ -77,6 +81,11 @@ type Config struct {
 	UsesKubenet bool `json:",omitempty"`
 	// NTPUnmanaged is true when NTP is not managed by kOps.
 	NTPUnmanaged bool `json:",omitempty"`
+	// UseTPMAttestation is true when the node authenticates to kops-controller with TPM 2.0 attestation.
+	UseTPMAttestation bool `json:",omitempty"`
+	// NodeConfigAgentInterval is how often the node configuration agent polls kops-controller for changes.
+	// The agent only runs if it is set.
+	NodeConfigAgentInterval *metav1.Duration `json:",omitempty"`
 	// ServiceNodePortRange is the service NodePort range.
 	ServiceNodePortRange string `json:",omitempty"`
 	// SysctlParameters will configure kernel parameters using sysctl(8).
@@ -214,6 +223,8 @@ type ControlPlaneConfig struct {
 	KubeControllerManager kops.KubeControllerManagerConfig
 	// KubeScheduler is the configuration for the kube-scheduler.
 	KubeScheduler kops.KubeSchedulerConfig
+	// EnableMonitoring is true when the monitoring agent runs on control-plane nodes and needs client certificates.
+	EnableMonitoring bool `json:",omitempty"`
 }
 
 func NewConfig(cluster *kops.Cluster, instanceGroup *kops.InstanceGroup) (*Config, *BootConfig) {
@@ -261,6 +272,18 @@ func NewConfig(cluster *kops.Cluster, instanceGroup *kops.InstanceGroup) (*Confi
 		config.NTPUnmanaged = true
 	}
 
+	if cluster.Spec.TPMAttestation != nil {
+		config.UseTPMAttestation = true
+	}
+
+	if agent := cluster.Spec.NodeConfigAgent; agent != nil && !instanceGroup.HasAPIServer() && model.UseKopsControllerForNodeConfig(cluster) {
+		interval := metav1.Duration{Duration: 5 * time.Minute}
+		if agent.Interval != nil {
+			interval = *agent.Interval
+		}
+		config.NodeConfigAgentInterval = &interval
+	}
+
 	if cluster.Spec.CloudProvider.AWS != nil {
 		aws := cluster.Spec.CloudProvider.AWS
 		warmPool := aws.WarmPool.ResolveDefaults(instanceGroup)
@@ -374,8 +397,9 @@ func NewConfig(cluster *kops.Cluster, instanceGroup *kops.InstanceGroup) (*Confi
 
 	if instanceGroup.HasAPIServer() || !model.UseKopsControllerForNodeConfig(cluster) {
 		config.ConfigStore = &kops.ConfigStoreSpec{
-			Keypairs: cluster.Spec.ConfigStore.Keypairs,
-			Secrets:  cluster.Spec.ConfigStore.Secrets,
+			Keypairs:   cluster.Spec.ConfigStore.Keypairs,
+			Secrets:    cluster.Spec.ConfigStore.Secrets,
+			Encryption: cluster.Spec.ConfigStore.Encryption,
 		}
 	}
 
@@ -396,6 +420,9 @@ func NewConfig(cluster *kops.Cluster, instanceGroup *kops.InstanceGroup) (*Confi
 			KubeControllerManager: *cluster.Spec.KubeControllerManager,
 			KubeScheduler:         *cluster.Spec.KubeScheduler,
 		}
+		if model.UseMonitoring(cluster) {
+			config.ControlPlaneConfig.EnableMonitoring = true
+		}
 	}
 
 	if len(instanceGroup.Spec.SysctlParameters) > 0 {
@@ -0,0 +1,530 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"k8s.io/klog/v2"
+	kopsbase "k8s.io/kops"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/util/pkg/hashing"
+	"k8s.io/kops/util/pkg/vfs"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// BundleManifestPath is the location of the BundleManifest inside a bundle.
+	BundleManifestPath = "bundle.yaml"
+
+	bundleFilesDir  = "files"
+	bundleImagesDir = "images"
+
+	// addonsDir is the directory of the channel and addon manifests below the cluster's state store path.
+	addonsDir = "addons"
+
+	// imageRefAnnotation is the OCI annotation holding the canonical location of each image in the bundle's image layout.
+	imageRefAnnotation = "org.opencontainers.image.ref.name"
+)
+
+// BundleManifest is the index of an offline asset bundle.
+type BundleManifest struct {
+	// KopsVersion is the version of kOps that created the bundle.
+	KopsVersion string `json:"kopsVersion"`
+	// ClusterName is the name of the cluster the bundle was created for.
+	ClusterName string `json:"clusterName"`
+	// Images are the container images in the bundle.
+	Images []BundleImage `json:"images,omitempty"`
+	// Files are the file assets in the bundle.
+	Files []BundleFile `json:"files,omitempty"`
+}
+
+// BundleImage is a container image stored in the bundle's OCI image layout.
+type BundleImage struct {
+	// Canonical is the upstream location of the image.
+	Canonical string `json:"canonical"`
+	// Digest is the digest of the image manifest or index.
+	Digest string `json:"digest"`
+}
+
+// BundleFile is a file asset stored in the bundle.
+type BundleFile struct {
+	// Canonical is the upstream location of the file.
+	Canonical string `json:"canonical"`
+	// SHA is the hash of the file.
+	SHA string `json:"sha"`
+	// Path is the location of the file inside the bundle.
+	Path string `json:"path"`
+}
+
+// BundleContents are the inputs for creating a bundle.
+type BundleContents struct {
+	ClusterName string
+	ImageAssets []*ImageAsset
+	FileAssets  []*FileAsset
+}
+
+// Bundle is a bundle that has been extracted to a local directory.
+type Bundle struct {
+	Dir      string
+	Manifest *BundleManifest
+}
+
+// WriteBundle downloads the assets and writes them, as a gzipped tarball, to w.
+// Images are stored as a single OCI image layout; files are verified against their hashes.
+func WriteBundle(vfsContext *vfs.VFSContext, contents *BundleContents, w io.Writer) error {
+	workDir, err := os.MkdirTemp("", "kops-bundle")
+	if err != nil {
+		return fmt.Errorf("creating temp directory: %w", err)
+	}
+	defer func() {
+		if err := os.RemoveAll(workDir); err != nil {
+			klog.Warningf("error removing temp directory %q: %v", workDir, err)
+		}
+	}()
+
+	manifest := &BundleManifest{
+		KopsVersion: kopsbase.Version,
+		ClusterName: contents.ClusterName,
+	}
+
+	images, err := writeBundleImages(filepath.Join(workDir, bundleImagesDir), contents.ImageAssets)
+	if err != nil {
+		return err
+	}
+	manifest.Images = images
+
+	files, err := writeBundleFiles(vfsContext, workDir, contents.FileAssets)
+	if err != nil {
+		return err
+	}
+	manifest.Files = files
+
+	manifestYAML, err := yaml.Marshal(manifest)
+	if err != nil {
+		return fmt.Errorf("marshaling bundle manifest: %w", err)
+	}
+	if err := writeBundleFile(workDir, BundleManifestPath, manifestYAML); err != nil {
+		return err
+	}
+
+	return writeTarball(workDir, w)
+}
+
+func writeBundleImages(dir string, imageAssets []*ImageAsset) ([]BundleImage, error) {
+	canonicals := map[string]bool{}
+	for _, imageAsset := range imageAssets {
+		canonicals[imageAsset.CanonicalLocation] = true
+	}
+	if len(canonicals) == 0 {
+		return nil, nil
+	}
+
+	imageLayout, err := layout.Write(dir, empty.Index)
+	if err != nil {
+		return nil, fmt.Errorf("creating image layout: %w", err)
+	}
+
+	var images []BundleImage
+	options := []remote.Option{remote.WithAuthFromKeychain(authn.DefaultKeychain)}
+	for _, canonical := range sortedKeys(canonicals) {
+		ref, err := name.ParseReference(canonical)
+		if err != nil {
+			return nil, fmt.Errorf("parsing reference %q: %v", canonical, err)
+		}
+
+		klog.Infof("adding image %v to bundle", ref)
+		desc, err := remote.Get(ref, options...)
+		if err != nil {
+			return nil, fmt.Errorf("fetching %q: %v", canonical, err)
+		}
+
+		annotations := layout.WithAnnotations(map[string]string{imageRefAnnotation: canonical})
+		switch desc.MediaType {
+		case types.OCIImageIndex, types.DockerManifestList:
+			idx, err := desc.ImageIndex()
+			if err != nil {
+				return nil, fmt.Errorf("reading index %q: %v", canonical, err)
+			}
+			if err := imageLayout.AppendIndex(idx, annotations); err != nil {
+				return nil, fmt.Errorf("writing index %q: %v", canonical, err)
+			}
+		default:
+			// Assume anything else is an image, since some registries don't set mediaTypes properly.
+			img, err := desc.Image()
+			if err != nil {
+				return nil, fmt.Errorf("reading image %q: %v", canonical, err)
+			}
+			if err := imageLayout.AppendImage(img, annotations); err != nil {
+				return nil, fmt.Errorf("writing image %q: %v", canonical, err)
+			}
+		}
+
+		images = append(images, BundleImage{
+			Canonical: canonical,
+			Digest:    desc.Digest.String(),
+		})
+	}
+
+	return images, nil
+}
+
+func writeBundleFiles(vfsContext *vfs.VFSContext, workDir string, fileAssets []*FileAsset) ([]BundleFile, error) {
+	byCanonical := map[string]*FileAsset{}
+	for _, fileAsset := range fileAssets {
+		canonical := fileAsset.CanonicalURL.String()
+		if existing, ok := byCanonical[canonical]; ok && !existing.SHAValue.Equal(fileAsset.SHAValue) {
+			return nil, fmt.Errorf("different sha for same file %s: %s vs %s", canonical, fileAsset.SHAValue.Hex(), existing.SHAValue.Hex())
+		}
+		byCanonical[canonical] = fileAsset
+	}
+
+	var files []BundleFile
+	for _, canonical := range sortedKeys(byCanonical) {
+		fileAsset := byCanonical[canonical]
+
+		klog.Infof("adding file %q to bundle", canonical)
+		data, err := vfsContext.ReadFile(canonical)
+		if err != nil {
+			return nil, fmt.Errorf("error downloading file %q: %v", canonical, err)
+		}
+		if err := verifyHash(canonical, data, fileAsset.SHAValue); err != nil {
+			return nil, err
+		}
+
+		p := path.Join(bundleFilesDir, fileAsset.CanonicalURL.Host, path.Clean("/"+fileAsset.CanonicalURL.Path))
+		if err := writeBundleFile(workDir, p, data); err != nil {
+			return nil, err
+		}
+		files = append(files, BundleFile{
+			Canonical: canonical,
+			SHA:       fileAsset.SHAValue.Hex(),
+			Path:      p,
+		})
+	}
+
+	return files, nil
+}
+
+func verifyHash(source string, data []byte, expected *hashing.Hash) error {
+	actual, err := expected.Algorithm.Hash(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("unable to hash file %q: %v", source, err)
+	}
+	if !expected.Equal(actual) {
+		return fmt.Errorf("the sha value of %q (%s) does not match the expected value %s", source, actual.Hex(), expected.Hex())
+	}
+	return nil
+}
+
+func writeBundleFile(workDir string, p string, data []byte) error {
+	target := filepath.Join(workDir, filepath.FromSlash(p))
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return fmt.Errorf("creating directory for %q: %w", p, err)
+	}
+	if err := os.WriteFile(target, data, 0o644); err != nil {
+		return fmt.Errorf("writing %q: %w", p, err)
+	}
+	return nil
+}
+
+// writeTarball writes the contents of dir as a gzipped tarball, with the bundle manifest first.
+func writeTarball(dir string, w io.Writer) error {
+	var paths []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		paths = append(paths, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("listing bundle contents: %w", err)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		if paths[i] == BundleManifestPath || paths[j] == BundleManifestPath {
+			return paths[i] == BundleManifestPath
+		}
+		return paths[i] < paths[j]
+	})
+
+	gzw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gzw)
+	for _, p := range paths {
+		if err := addTarFile(tw, filepath.Join(dir, filepath.FromSlash(p)), p); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("closing tar writer: %w", err)
+	}
+	if err := gzw.Close(); err != nil {
+		return fmt.Errorf("closing gzip writer: %w", err)
+	}
+	return nil
+}
+
+func addTarFile(tw *tar.Writer, src string, p string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	header := &tar.Header{
+		Name:     p,
+		Mode:     0o644,
+		Size:     stat.Size(),
+		Typeflag: tar.TypeReg,
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("writing tar header for %q: %w", p, err)
+	}
+	if _, err := io.Copy(tw, f); err != nil {
+		return fmt.Errorf("writing %q to tarball: %w", p, err)
+	}
+	return nil
+}
+
+// ExtractBundle extracts the gzipped tarball bundle from r into dir.
+func ExtractBundle(r io.Reader, dir string) (*Bundle, error) {
+	gzr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading bundle: %w", err)
+	}
+	defer gzr.Close()
+
+	tr := tar.NewReader(gzr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading bundle: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if !isBundlePath(header.Name) {
+			return nil, fmt.Errorf("invalid path %q in bundle", header.Name)
+		}
+
+		target := filepath.Join(dir, filepath.FromSlash(header.Name))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return nil, fmt.Errorf("creating directory for %q: %w", header.Name, err)
+		}
+		f, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("creating %q: %w", target, err)
+		}
+		if _, err := io.Copy(f, tr); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("extracting %q: %w", header.Name, err)
+		}
+		if err := f.Close(); err != nil {
+			return nil, fmt.Errorf("extracting %q: %w", header.Name, err)
+		}
+	}
+
+	manifestYAML, err := os.ReadFile(filepath.Join(dir, BundleManifestPath))
+	if err != nil {
+		return nil, fmt.Errorf("reading bundle manifest: %w", err)
+	}
+	manifest := &BundleManifest{}
+	if err := yaml.Unmarshal(manifestYAML, manifest); err != nil {
+		return nil, fmt.Errorf("parsing bundle manifest: %w", err)
+	}
+
+	return &Bundle{Dir: dir, Manifest: manifest}, nil
+}
+
+// isBundlePath returns true if p is a relative path that stays inside the bundle.
+func isBundlePath(p string) bool {
+	if p == "" || path.IsAbs(p) || strings.Contains(p, "\\") {
+		return false
+	}
+	cleaned := path.Clean(p)
+	return cleaned == p && cleaned != ".." && !strings.HasPrefix(cleaned, "../")
+}
+
+// Push uploads the bundle's images to the container registry and its files to the file repository
+// configured in assetsLocation, using the same naming as the AssetBuilder.
+func (b *Bundle) Push(ctx context.Context, vfsContext *vfs.VFSContext, assetsLocation *kops.AssetsSpec, cluster *kops.Cluster) error {
+	a := NewAssetBuilder(vfsContext, assetsLocation, false)
+
+	if len(b.Manifest.Images) != 0 {
+		if assetsLocation == nil || assetsLocation.ContainerRegistry == nil {
+			return fmt.Errorf("a container registry is required to push the images in the bundle")
+		}
+		if err := b.pushImages(a); err != nil {
+			return err
+		}
+	}
+
+	if len(b.Manifest.Files) != 0 {
+		if assetsLocation == nil || assetsLocation.FileRepository == nil {
+			return fmt.Errorf("a file repository is required to push the files in the bundle")
+		}
+		if err := b.pushFiles(ctx, a, cluster); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriteAddons writes the channel and addon manifests below mirror. addons maps the location of
+// each manifest relative to the cluster's state store path, e.g. addons/bootstrap-channel.yaml, to its contents.
+func WriteAddons(ctx context.Context, cluster *kops.Cluster, mirror vfs.Path, addons map[string][]byte) error {
+	for _, location := range sortedKeys(addons) {
+		if !isBundlePath(location) || !strings.HasPrefix(location, addonsDir+"/") {
+			return fmt.Errorf("unexpected addon location %q", location)
+		}
+		p := mirror.Join(location)
+		klog.Infof("uploading %q to %q", location, p)
+		if err := writeFile(ctx, cluster, p, addons[location]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *Bundle) pushImages(a *AssetBuilder) error {
+	imageLayout, err := layout.FromPath(filepath.Join(b.Dir, bundleImagesDir))
+	if err != nil {
+		return fmt.Errorf("reading image layout from bundle: %w", err)
+	}
+	index, err := imageLayout.ImageIndex()
+	if err != nil {
+		return fmt.Errorf("reading image layout from bundle: %w", err)
+	}
+	indexManifest, err := index.IndexManifest()
+	if err != nil {
+		return fmt.Errorf("reading image layout from bundle: %w", err)
+	}
+
+	descriptors := map[string]v1.Descriptor{}
+	for _, desc := range indexManifest.Manifests {
+		descriptors[desc.Annotations[imageRefAnnotation]] = desc
+	}
+
+	options := []remote.Option{remote.WithAuthFromKeychain(authn.DefaultKeychain)}
+	for _, image := range b.Manifest.Images {
+		desc, ok := descriptors[image.Canonical]
+		if !ok {
+			return fmt.Errorf("image %q not found in bundle", image.Canonical)
+		}
+
+		target := NormalizeImage(a, image.Canonical)
+		targetRef, err := name.ParseReference(target)
+		if err != nil {
+			return fmt.Errorf("parsing reference for %q: %v", target, err)
+		}
+
+		if existing, err := remote.Head(targetRef, options...); err == nil && existing.Digest == desc.Digest {
+			klog.Infof("image %v is already present", targetRef)
+			continue
+		}
+
+		klog.Infof("pushing image %v to %v", image.Canonical, targetRef)
+		switch desc.MediaType {
+		case types.OCIImageIndex, types.DockerManifestList:
+			idx, err := index.ImageIndex(desc.Digest)
+			if err != nil {
+				return fmt.Errorf("reading index %q from bundle: %v", image.Canonical, err)
+			}
+			if err := remote.WriteIndex(targetRef, idx, options...); err != nil {
+				return fmt.Errorf("pushing index %q: %v", target, err)
+			}
+		default:
+			img, err := index.Image(desc.Digest)
+			if err != nil {
+				return fmt.Errorf("reading image %q from bundle: %v", image.Canonical, err)
+			}
+			if err := remote.Write(targetRef, img, options...); err != nil {
+				return fmt.Errorf("pushing image %q: %v", target, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (b *Bundle) pushFiles(ctx context.Context, a *AssetBuilder, cluster *kops.Cluster) error {
+	for _, file := range b.Manifest.Files {
+		if !isBundlePath(file.Path) {
+			return fmt.Errorf("invalid file path %q in bundle", file.Path)
+		}
+		canonicalURL, err := url.Parse(file.Canonical)
+		if err != nil {
+			return fmt.Errorf("parsing %q: %w", file.Canonical, err)
+		}
+		target, err := a.remapURL(canonicalURL)
+		if err != nil {
+			return err
+		}
+
+		data, err := os.ReadFile(filepath.Join(b.Dir, filepath.FromSlash(file.Path)))
+		if err != nil {
+			return fmt.Errorf("reading %q from bundle: %w", file.Path, err)
+		}
+		if err := uploadFile(ctx, a.vfsContext, cluster, file.Canonical, data, target.String(), file.SHA); err != nil {
+			return fmt.Errorf("unable to upload %q to %q: %v", file.Canonical, target, err)
+		}
+	}
+	return nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/util/pkg/hashing"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func TestBundleRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	vfsContext := vfs.NewTestingVFSContext()
+
+	srcDir := t.TempDir()
+	srcFile := filepath.Join(srcDir, "release", "v1.2.3", "bin", "kubelet")
+	if err := os.MkdirAll(filepath.Dir(srcFile), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	data := []byte("kubelet binary")
+	if err := os.WriteFile(srcFile, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sha, err := hashing.HashAlgorithmSHA256.Hash(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	canonicalURL, err := url.Parse("file://" + srcFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	channel := []byte("kind: Addons\n")
+	manifest := []byte("kind: ConfigMap\n")
+	contents := &BundleContents{
+		ClusterName: "minimal.example.com",
+		FileAssets: []*FileAsset{
+			{CanonicalURL: canonicalURL, DownloadURL: canonicalURL, SHAValue: sha},
+			{CanonicalURL: canonicalURL, DownloadURL: canonicalURL, SHAValue: sha},
+		},
+	}
+
+	var bundle bytes.Buffer
+	if err := WriteBundle(vfsContext, contents, &bundle); err != nil {
+		t.Fatalf("WriteBundle failed: %v", err)
+	}
+
+	b, err := ExtractBundle(&bundle, t.TempDir())
+	if err != nil {
+		t.Fatalf("ExtractBundle failed: %v", err)
+	}
+	if b.Manifest.ClusterName != "minimal.example.com" {
+		t.Errorf("unexpected cluster name %q", b.Manifest.ClusterName)
+	}
+	if len(b.Manifest.Files) != 1 {
+		t.Fatalf("expected 1 file, got %v", b.Manifest.Files)
+	}
+
+	repoDir := t.TempDir()
+	mirrorDir := t.TempDir()
+	fileRepository := "file://" + repoDir
+	assetsLocation := &kops.AssetsSpec{
+		FileRepository: &fileRepository,
+	}
+	if err := b.Push(ctx, vfsContext, assetsLocation, nil); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+	addons := map[string][]byte{
+		"addons/bootstrap-channel.yaml":          channel,
+		"addons/example.addons.k8s.io/v1.0.yaml": manifest,
+	}
+	if err := WriteAddons(ctx, nil, vfs.NewFSPath(mirrorDir), addons); err != nil {
+		t.Fatalf("WriteAddons failed: %v", err)
+	}
+
+	pushed := filepath.Join(repoDir, srcFile)
+	if got, err := os.ReadFile(pushed); err != nil || !bytes.Equal(got, data) {
+		t.Errorf("unexpected pushed file %q: %q, %v", pushed, got, err)
+	}
+	if got, err := os.ReadFile(pushed + ".sha256"); err != nil || string(got) != sha.Hex() {
+		t.Errorf("unexpected pushed hash file: %q, %v", got, err)
+	}
+	if got, err := os.ReadFile(filepath.Join(mirrorDir, "addons", "bootstrap-channel.yaml")); err != nil || !bytes.Equal(got, channel) {
+		t.Errorf("unexpected mirrored channel: %q, %v", got, err)
+	}
+	if got, err := os.ReadFile(filepath.Join(mirrorDir, "addons", "example.addons.k8s.io", "v1.0.yaml")); err != nil || !bytes.Equal(got, manifest) {
+		t.Errorf("unexpected mirrored manifest: %q, %v", got, err)
+	}
+}
+
+func TestBundleHashMismatch(t *testing.T) {
+	srcFile := filepath.Join(t.TempDir(), "kubelet")
+	if err := os.WriteFile(srcFile, []byte("tampered"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sha, err := hashing.HashAlgorithmSHA256.Hash(strings.NewReader("original"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	canonicalURL, err := url.Parse("file://" + srcFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	contents := &BundleContents{
+		FileAssets: []*FileAsset{{CanonicalURL: canonicalURL, DownloadURL: canonicalURL, SHAValue: sha}},
+	}
+	var bundle bytes.Buffer
+	err = WriteBundle(vfs.NewTestingVFSContext(), contents, &bundle)
+	if err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Errorf("expected hash mismatch error, got %v", err)
+	}
+}
+
+func TestIsBundlePath(t *testing.T) {
+	grid := []struct {
+		path     string
+		expected bool
+	}{
+		{"bundle.yaml", true},
+		{"addons/bootstrap-channel.yaml", true},
+		{"files/dl.k8s.io/release/v1.34.0/bin/linux/amd64/kubelet", true},
+		{"", false},
+		{"/etc/passwd", false},
+		{"../escape", false},
+		{"addons/../../escape", false},
+		{"addons/./channel.yaml", false},
+		{`addons\channel.yaml`, false},
+	}
+	for _, g := range grid {
+		if actual := isBundlePath(g.path); actual != g.expected {
+			t.Errorf("isBundlePath(%q) = %v, expected %v", g.path, actual, g.expected)
+		}
+	}
+}
@@ -114,6 +114,11 @@ func transferFile(ctx context.Context, vfsContext *vfs.VFSContext, cluster *kops
 		return fmt.Errorf("error downloading file %q: %v", source, err)
 	}
 
+	return uploadFile(ctx, vfsContext, cluster, source, data, target, sha)
+}
+
+// uploadFile validates that data matches the SHA, and uploads it alongside its hash file to the target location.
+func uploadFile(ctx context.Context, vfsContext *vfs.VFSContext, cluster *kops.Cluster, source string, data []byte, target string, sha string) error {
 	objectStore, err := buildVFSPath(target)
 	if err != nil {
 		return err
@@ -0,0 +1,129 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pkibootstrap
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+// joinTokenSecretPrefix is the prefix of the names of join tokens in the secret store.
+const joinTokenSecretPrefix = "join-token-"
+
+// joinTokenPattern is the format of join tokens, in the same form as kubeadm bootstrap tokens: <id>.<secret>
+var joinTokenPattern = regexp.MustCompile(`^([a-z0-9]{6})\.([a-z0-9]{16})$`)
+
+// JoinToken is the record of a join token that we keep in the secret store.
+// A join token lets a machine register its own public key, instead of an operator creating the Host object.
+type JoinToken struct {
+	// InstanceGroup is the instance group that machines using the token join.
+	InstanceGroup string `json:"instanceGroup"`
+	// Host is the name of the only machine that can use the token, which is the name of the Host object it creates.
+	Host string `json:"host"`
+	// Expiration is the time after which the token is no longer accepted.
+	Expiration time.Time `json:"expiration"`
+	// SecretHash is the hex-encoded SHA-256 hash of the secret part of the token.
+	SecretHash string `json:"secretHash"`
+}
+
+// JoinTokenSecretName returns the name of the secret that holds the join token with the given id.
+func JoinTokenSecretName(id string) string {
+	return joinTokenSecretPrefix + id
+}
+
+// NewJoinToken generates a join token for the host to join the instance group, returning the token and the record to store.
+func NewJoinToken(instanceGroup string, host string, ttl time.Duration) (string, string, *JoinToken, error) {
+	id, err := randomTokenString(6)
+	if err != nil {
+		return "", "", nil, err
+	}
+	secret, err := randomTokenString(16)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	hash := sha256.Sum256([]byte(secret))
+	record := &JoinToken{
+		InstanceGroup: instanceGroup,
+		Host:          host,
+		Expiration:    time.Now().Add(ttl).UTC().Truncate(time.Second),
+		SecretHash:    hex.EncodeToString(hash[:]),
+	}
+	return id + "." + secret, id, record, nil
+}
+
+// ParseJoinToken splits a join token into its id and secret.
+func ParseJoinToken(token string) (string, string, error) {
+	match := joinTokenPattern.FindStringSubmatch(strings.TrimSpace(token))
+	if match == nil {
+		return "", "", fmt.Errorf("join token is not in the format <id>.<secret>")
+	}
+	return match[1], match[2], nil
+}
+
+// Encode serializes the join token for the secret store.
+func (t *JoinToken) Encode() (*fi.Secret, error) {
+	b, err := json.Marshal(t)
+	if err != nil {
+		return nil, fmt.Errorf("encoding join token: %w", err)
+	}
+	return &fi.Secret{Data: b}, nil
+}
+
+// DecodeJoinToken parses a join token from the secret store.
+func DecodeJoinToken(secret *fi.Secret) (*JoinToken, error) {
+	t := &JoinToken{}
+	if err := json.Unmarshal(secret.Data, t); err != nil {
+		return nil, fmt.Errorf("decoding join token: %w", err)
+	}
+	return t, nil
+}
+
+// Check returns an error if the secret does not match the token, or the token has expired.
+func (t *JoinToken) Check(secret string, now time.Time) error {
+	hash := sha256.Sum256([]byte(secret))
+	if subtle.ConstantTimeCompare([]byte(hex.EncodeToString(hash[:])), []byte(t.SecretHash)) != 1 {
+		return fmt.Errorf("join token secret does not match")
+	}
+	if now.After(t.Expiration) {
+		return fmt.Errorf("join token expired at %v", t.Expiration)
+	}
+	return nil
+}
+
+func randomTokenString(n int) (string, error) {
+	const alphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+	b := make([]byte, n)
+	for i := range b {
+		v, err := rand.Int(rand.Reader, big.NewInt(int64(len(alphabet))))
+		if err != nil {
+			return "", fmt.Errorf("generating join token: %w", err)
+		}
+		b[i] = alphabet[v.Int64()]
+	}
+	return string(b), nil
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pkibootstrap
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJoinToken(t *testing.T) {
+	token, id, record, err := NewJoinToken("nodes", "node1", time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if record.InstanceGroup != "nodes" {
+		t.Errorf("unexpected instance group %q", record.InstanceGroup)
+	}
+	if record.Host != "node1" {
+		t.Errorf("unexpected host %q", record.Host)
+	}
+
+	secret, err := record.Encode()
+	if err != nil {
+		t.Fatalf("unexpected error encoding: %v", err)
+	}
+	decoded, err := DecodeJoinToken(secret)
+	if err != nil {
+		t.Fatalf("unexpected error decoding: %v", err)
+	}
+
+	parsedID, parsedSecret, err := ParseJoinToken(token + "\n")
+	if err != nil {
+		t.Fatalf("unexpected error parsing %q: %v", token, err)
+	}
+	if parsedID != id {
+		t.Errorf("unexpected id; got %q, want %q", parsedID, id)
+	}
+
+	now := time.Now()
+	if err := decoded.Check(parsedSecret, now); err != nil {
+		t.Errorf("unexpected error checking token: %v", err)
+	}
+	if err := decoded.Check("0123456789abcdef", now); err == nil {
+		t.Errorf("expected error for wrong secret")
+	}
+	if err := decoded.Check(parsedSecret, now.Add(2*time.Hour)); err == nil {
+		t.Errorf("expected error for expired token")
+	}
+}
+
+func TestParseJoinTokenInvalid(t *testing.T) {
+	for _, token := range []string{
+		"",
+		"abcdef",
+		"abcdef.0123456789abcde",
+		"ABCDEF.0123456789abcdef",
+		"abcdef:0123456789abcdef",
+	} {
+		if _, _, err := ParseJoinToken(token); err == nil {
+			t.Errorf("expected error parsing %q", token)
+		}
+	}
+}
@@ -19,14 +19,19 @@ package pkibootstrap
 import (
 	"bytes"
 	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	cryptorand "crypto/rand"
 	"crypto/sha256"
 	"crypto/x509"
 	"encoding/base64"
 	"encoding/json"
 	"encoding/pem"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"k8s.io/klog/v2"
@@ -35,11 +40,18 @@ import (
 )
 
 type pkiAuthenticator struct {
-	signer   crypto.Signer
-	keyID    string
-	hostname string
+	signer    crypto.Signer
+	keyID     string
+	hostname  string
+	joinToken string
 }
 
+// MachineKeyPath is the path to the private key that identifies the machine.
+const MachineKeyPath = "/etc/kubernetes/kops/pki/machine/private.pem"
+
+// JoinTokenPath is the path to the join token the machine uses to register its key, if it was not enrolled.
+const JoinTokenPath = "/etc/kubernetes/kops/pki/machine/join-token"
+
 // AuthTokenData is the code data that is signed as part of the header.
 type AuthTokenData struct {
 	// Instance is the name/id of the instance we are claiming
@@ -56,6 +68,9 @@ type AuthTokenData struct {
 
 	// Audience is the audience for this request (to help prevent replay attacks)
 	Audience string `json:"audience,omitempty"`
+
+	// JoinToken is a one-time token that lets us register our public key, if the instance has not been enrolled.
+	JoinToken string `json:"joinToken,omitempty"`
 }
 
 var _ bootstrap.Authenticator = &pkiAuthenticator{}
@@ -101,6 +116,62 @@ func NewAuthenticatorFromFile(p string) (bootstrap.Authenticator, error) {
 	return NewAuthenticator(hostname, key.Key)
 }
 
+// NewMachineAuthenticator builds the authenticator for the machine key.
+// If the machine has a join token, the machine key is created if needed, and the token is presented
+// so that kops-controller registers the key.
+func NewMachineAuthenticator() (bootstrap.Authenticator, error) {
+	joinToken, err := os.ReadFile(JoinTokenPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("error reading %q: %w", JoinTokenPath, err)
+	}
+	if len(joinToken) != 0 {
+		if err := ensureMachineKey(MachineKeyPath); err != nil {
+			return nil, err
+		}
+	}
+
+	authenticator, err := NewAuthenticatorFromFile(MachineKeyPath)
+	if err != nil {
+		return nil, err
+	}
+	authenticator.(*pkiAuthenticator).joinToken = strings.TrimSpace(string(joinToken))
+	return authenticator, nil
+}
+
+// ensureMachineKey creates the machine key and the matching public key, if they don't exist.
+func ensureMachineKey(p string) error {
+	if _, err := os.Stat(p); err == nil {
+		return nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error checking %q: %w", p, err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), cryptorand.Reader)
+	if err != nil {
+		return fmt.Errorf("error generating machine key: %w", err)
+	}
+	privateKey, err := (&pki.PrivateKey{Key: key}).AsBytes()
+	if err != nil {
+		return err
+	}
+	publicKey, err := computeKeyID(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return fmt.Errorf("error creating directory for %q: %w", p, err)
+	}
+	if err := os.WriteFile(filepath.Join(filepath.Dir(p), "public.pem"), []byte(publicKey), 0o644); err != nil {
+		return fmt.Errorf("error writing public key: %w", err)
+	}
+	if err := os.WriteFile(p, privateKey, 0o600); err != nil {
+		return fmt.Errorf("error writing %q: %w", p, err)
+	}
+	klog.Infof("created machine key %q", p)
+	return nil
+}
+
 func (a *pkiAuthenticator) CreateToken(body []byte) (string, error) {
 	requestHash := sha256.Sum256(body)
 
@@ -109,8 +180,9 @@ func (a *pkiAuthenticator) CreateToken(body []byte) (string, error) {
 		Audience:    AudienceNodeAuthentication,
 		RequestHash: requestHash[:],
 
-		KeyID:    a.keyID,
-		Instance: a.hostname,
+		KeyID:     a.keyID,
+		Instance:  a.hostname,
+		JoinToken: a.joinToken,
 	}
 
 	payload, err := json.Marshal(&data)
@@ -24,35 +24,60 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"net/http"
+	"os"
 	"strings"
+	"sync"
 	"time"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/klog/v2"
 	kops "k8s.io/kops/pkg/apis/kops/v1alpha2"
 	"k8s.io/kops/pkg/bootstrap"
 	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/vfs"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type verifier struct {
 	opt    Options
 	client client.Client
+
+	// joinTokens holds the join tokens; if nil, join tokens are not accepted.
+	joinTokens fi.SecretStore
+	// joinTokenClaims is the directory where we record the join tokens that have been used.
+	// The claims are shared by all kops-controller instances, so each token is only used once.
+	joinTokenClaims vfs.Path
+	// joinMutex serializes the use of join tokens by this instance,
+	// for state stores that don't support conditional writes.
+	joinMutex sync.Mutex
 }
 
+// errHostNotFound is returned when there is no Host object for the instance.
+var errHostNotFound = errors.New("host not found")
+
 // NewVerifier constructs a new verifier.
-func NewVerifier(options *Options, client client.Client) (bootstrap.Verifier, error) {
+// If joinTokens is not nil, instances can register their public key with a join token from it,
+// and the use of each token is recorded in joinTokenClaims.
+func NewVerifier(options *Options, client client.Client, joinTokens fi.SecretStore, joinTokenClaims vfs.Path) (bootstrap.Verifier, error) {
 	opt := *options
 	if opt.MaxTimeSkew == 0 {
 		opt.MaxTimeSkew = 300
 	}
+	if joinTokens != nil && joinTokenClaims == nil {
+		return nil, fmt.Errorf("join token claims path is required")
+	}
 	return &verifier{
-		opt:    opt,
-		client: client,
+		opt:             opt,
+		client:          client,
+		joinTokens:      joinTokens,
+		joinTokenClaims: joinTokenClaims,
 	}, nil
 }
 
@@ -115,6 +140,9 @@ func (v *verifier) VerifyToken(ctx context.Context, rawRequest *http.Request, au
 	// Verify the token has a valid signature.
 	result, signingKey, err := v.getSigningKey(ctx, tokenData)
 	if err != nil {
+		if errors.Is(err, errHostNotFound) && tokenData.JoinToken != "" {
+			return v.join(ctx, token, tokenData)
+		}
 		return nil, err
 	}
 
@@ -134,7 +162,7 @@ func (v *verifier) getSigningKey(ctx context.Context, tokenData *AuthTokenData)
 	var host kops.Host
 	if err := v.client.Get(ctx, id, &host); err != nil {
 		if apierrors.IsNotFound(err) {
-			return nil, nil, fmt.Errorf("host not found for %v", id)
+			return nil, nil, fmt.Errorf("%w for %v", errHostNotFound, id)
 		}
 		return nil, nil, fmt.Errorf("error getting host %v: %w", id, err)
 	}
@@ -164,6 +192,100 @@ func (v *verifier) getSigningKey(ctx context.Context, tokenData *AuthTokenData)
 	return result, pubKey.Key, nil
 }
 
+// join registers the public key of an instance that presents a valid join token, by creating its Host object.
+// The join token is deleted when it is used, even if the host cannot be registered.
+func (v *verifier) join(ctx context.Context, token *AuthToken, tokenData *AuthTokenData) (*bootstrap.VerifyResult, error) {
+	if v.joinTokens == nil {
+		return nil, fmt.Errorf("join tokens are not enabled")
+	}
+
+	nodeName := tokenData.Instance
+	if errs := validation.IsDNS1123Subdomain(nodeName); len(errs) != 0 {
+		return nil, fmt.Errorf("invalid instance name %q: %s", nodeName, strings.Join(errs, ", "))
+	}
+
+	// The instance proves it holds the key it is registering.
+	pubKey, err := pki.ParsePEMPublicKey([]byte(tokenData.KeyID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+	if !verifySignature(pubKey.Key, token.Data, token.Signature) {
+		return nil, fmt.Errorf("failed to verify claim signature for node")
+	}
+
+	id, secret, err := ParseJoinToken(tokenData.JoinToken)
+	if err != nil {
+		return nil, err
+	}
+
+	v.joinMutex.Lock()
+	defer v.joinMutex.Unlock()
+
+	stored, err := v.joinTokens.FindSecret(JoinTokenSecretName(id))
+	if err != nil {
+		return nil, fmt.Errorf("error reading join token %q: %w", id, err)
+	}
+	if stored == nil {
+		return nil, fmt.Errorf("join token %q not found", id)
+	}
+	joinToken, err := DecodeJoinToken(stored)
+	if err != nil {
+		return nil, err
+	}
+	if err := joinToken.Check(secret, time.Now()); err != nil {
+		return nil, fmt.Errorf("join token %q: %w", id, err)
+	}
+	// The name of the Host comes from the token, not from the requester, so a token cannot be used to replace another machine.
+	if joinToken.Host != nodeName {
+		return nil, fmt.Errorf("join token %q was not issued for instance %q", id, nodeName)
+	}
+
+	// Consume the token before registering the host, so that it can never be used twice.
+	if err := v.claimJoinToken(ctx, id, nodeName); err != nil {
+		return nil, err
+	}
+	if err := v.joinTokens.DeleteSecret(JoinTokenSecretName(id)); err != nil {
+		return nil, fmt.Errorf("error deleting join token %q: %w", id, err)
+	}
+
+	host := &kops.Host{}
+	host.Namespace = "kops-system"
+	host.Name = joinToken.Host
+	host.Spec.InstanceGroup = joinToken.InstanceGroup
+	host.Spec.PublicKey = tokenData.KeyID
+	if err := v.client.Create(ctx, host); err != nil {
+		return nil, fmt.Errorf("error creating host %s/%s: %w", host.Namespace, host.Name, err)
+	}
+	klog.Infof("registered host %s/%s in instance group %q with join token %q", host.Namespace, host.Name, joinToken.InstanceGroup, id)
+
+	return &bootstrap.VerifyResult{
+		NodeName:          nodeName,
+		InstanceGroupName: joinToken.InstanceGroup,
+	}, nil
+}
+
+// claimJoinToken records that the join token has been used.
+// The claim is created with a conditional write, so if several kops-controller instances
+// accept the same token concurrently, only one of them succeeds.
+func (v *verifier) claimJoinToken(ctx context.Context, id string, nodeName string) error {
+	p := v.joinTokenClaims.Join(id)
+
+	// Conditional writes are not supported by all state stores, so we also check for an existing claim.
+	if _, err := p.ReadFile(ctx); err == nil {
+		return fmt.Errorf("join token %q has already been used", id)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error reading claim for join token %q: %w", id, err)
+	}
+
+	if _, err := vfs.WriteFileIfVersion(ctx, p, bytes.NewReader([]byte(nodeName)), nil, ""); err != nil {
+		if errors.Is(err, vfs.ErrVersionConflict) {
+			return fmt.Errorf("join token %q has already been used", id)
+		}
+		return fmt.Errorf("error claiming join token %q: %w", id, err)
+	}
+	return nil
+}
+
 func verifySignature(signingKey crypto.PublicKey, payload []byte, signature []byte) bool {
 	attestHash := sha256.Sum256(payload)
 	switch signingKey := signingKey.(type) {
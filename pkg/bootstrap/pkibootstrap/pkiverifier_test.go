@@ -0,0 +1,190 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pkibootstrap
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kops "k8s.io/kops/pkg/apis/kops/v1alpha2"
+	"k8s.io/kops/upup/pkg/fi/secrets"
+	"k8s.io/kops/util/pkg/vfs"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// fakeClient records the Host objects that are created, but never finds them,
+// as if the instance joins through several kops-controller instances at the same time.
+type fakeClient struct {
+	client.Client
+	created []*kops.Host
+}
+
+func (c *fakeClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	return apierrors.NewNotFound(schema.GroupResource{Group: "kops.k8s.io", Resource: "hosts"}, key.Name)
+}
+
+func (c *fakeClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	c.created = append(c.created, obj.(*kops.Host))
+	return nil
+}
+
+func TestJoinTokenUsedOnce(t *testing.T) {
+	ctx := context.Background()
+
+	memfs := vfs.NewMemFSContext()
+	secretStore := secrets.NewVFSSecretStore(nil, vfs.NewMemFSPath(memfs, "cluster/secrets"))
+	claims := vfs.NewMemFSPath(memfs, "cluster/join-tokens/claimed")
+
+	joinToken, id, record, err := NewJoinToken("nodes", "node1", time.Hour)
+	if err != nil {
+		t.Fatalf("creating join token: %v", err)
+	}
+	secret, err := record.Encode()
+	if err != nil {
+		t.Fatalf("encoding join token: %v", err)
+	}
+	storeToken := func() {
+		if _, err := secretStore.ReplaceSecret(JoinTokenSecretName(id), secret); err != nil {
+			t.Fatalf("storing join token: %v", err)
+		}
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	authenticator, err := NewAuthenticator("node1", key)
+	if err != nil {
+		t.Fatalf("building authenticator: %v", err)
+	}
+	authenticator.(*pkiAuthenticator).joinToken = joinToken
+
+	// Each verifier is a separate kops-controller instance, sharing the state store.
+	kube := &fakeClient{}
+	var verifiers []*verifier
+	for i := 0; i < 2; i++ {
+		v, err := NewVerifier(&Options{}, kube, secretStore, claims)
+		if err != nil {
+			t.Fatalf("building verifier: %v", err)
+		}
+		verifiers = append(verifiers, v.(*verifier))
+	}
+
+	body := []byte("{}")
+	token, err := authenticator.CreateToken(body)
+	if err != nil {
+		t.Fatalf("creating token: %v", err)
+	}
+
+	storeToken()
+	result, err := verifiers[0].VerifyToken(ctx, &http.Request{}, token, body)
+	if err != nil {
+		t.Fatalf("verifying token: %v", err)
+	}
+	if result.NodeName != "node1" || result.InstanceGroupName != "nodes" {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	// The second instance read the join token before the first deleted it.
+	storeToken()
+	if _, err := verifiers[1].VerifyToken(ctx, &http.Request{}, token, body); err == nil || !strings.Contains(err.Error(), "has already been used") {
+		t.Errorf("expected join token to be rejected, got %v", err)
+	}
+
+	if len(kube.created) != 1 {
+		t.Errorf("expected one host to be created, got %d", len(kube.created))
+	}
+}
+
+func TestJoinTokenBoundToHost(t *testing.T) {
+	ctx := context.Background()
+
+	memfs := vfs.NewMemFSContext()
+	secretStore := secrets.NewVFSSecretStore(nil, vfs.NewMemFSPath(memfs, "cluster/secrets"))
+	claims := vfs.NewMemFSPath(memfs, "cluster/join-tokens/claimed")
+
+	joinToken, id, record, err := NewJoinToken("nodes", "node1", time.Hour)
+	if err != nil {
+		t.Fatalf("creating join token: %v", err)
+	}
+	secret, err := record.Encode()
+	if err != nil {
+		t.Fatalf("encoding join token: %v", err)
+	}
+	if _, err := secretStore.ReplaceSecret(JoinTokenSecretName(id), secret); err != nil {
+		t.Fatalf("storing join token: %v", err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	authenticator, err := NewAuthenticator("node2", key)
+	if err != nil {
+		t.Fatalf("building authenticator: %v", err)
+	}
+	authenticator.(*pkiAuthenticator).joinToken = joinToken
+
+	kube := &fakeClient{}
+	v, err := NewVerifier(&Options{}, kube, secretStore, claims)
+	if err != nil {
+		t.Fatalf("building verifier: %v", err)
+	}
+
+	body := []byte("{}")
+	token, err := authenticator.CreateToken(body)
+	if err != nil {
+		t.Fatalf("creating token: %v", err)
+	}
+	if _, err := v.VerifyToken(ctx, &http.Request{}, token, body); err == nil || !strings.Contains(err.Error(), "was not issued for instance") {
+		t.Errorf("expected join token to be rejected, got %v", err)
+	}
+	if len(kube.created) != 0 {
+		t.Errorf("expected no host to be created, got %d", len(kube.created))
+	}
+
+	// The token is not consumed, so the machine it was issued for can still join.
+	if stored, err := secretStore.FindSecret(JoinTokenSecretName(id)); err != nil || stored == nil {
+		t.Errorf("expected join token to be kept, got %v, %v", stored, err)
+	}
+}
+
+func TestClaimJoinTokenConditionalWrite(t *testing.T) {
+	ctx := context.Background()
+
+	claims := vfs.NewMemFSPath(vfs.NewMemFSContext(), "cluster/join-tokens/claimed")
+	v := &verifier{joinTokenClaims: claims}
+	if err := v.claimJoinToken(ctx, "abcdef", "node1"); err != nil {
+		t.Fatalf("claiming join token: %v", err)
+	}
+
+	// Another instance that checked for a claim before the first one wrote it must still lose.
+	if _, err := vfs.WriteFileIfVersion(ctx, claims.Join("abcdef"), strings.NewReader("node2"), nil, ""); err == nil {
+		t.Errorf("expected conditional write of an existing claim to fail")
+	}
+	if err := v.claimJoinToken(ctx, "abcdef", "node2"); err == nil || !strings.Contains(err.Error(), "has already been used") {
+		t.Errorf("expected join token to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// ErrRevoked is returned when the identity of a node has been revoked.
+var ErrRevoked = errors.New("node identity has been revoked")
+
+// QuarantineTaintKey is the key of the taint that is applied to nodes whose identity has been revoked.
+const QuarantineTaintKey = "kops.k8s.io/quarantined"
+
+// RevokedNode records that the identity of a node has been revoked.
+type RevokedNode struct {
+	// Name is the name of the node.
+	Name string `json:"name"`
+	// UID is the UID of the Node object that was revoked.
+	// If empty, the node was not registered when it was revoked, and the revocation applies to any node with the name.
+	UID types.UID `json:"uid,omitempty"`
+	// ProviderID is the provider ID of the Node object that was revoked, for reference.
+	ProviderID string `json:"providerID,omitempty"`
+	// Reason is a human-readable explanation of why the node was revoked.
+	Reason string `json:"reason,omitempty"`
+	// RevokedAt is when the node was revoked.
+	RevokedAt time.Time `json:"revokedAt"`
+}
+
+// AppliesTo returns true if the revocation applies to the Node object with the given UID.
+// A replacement node that reuses the name of a revoked node is a different Node object.
+func (n *RevokedNode) AppliesTo(uid types.UID) bool {
+	return n.UID == "" || n.UID == uid
+}
+
+// RevocationList is the list of nodes whose identity has been revoked, kept in the state store.
+// Revoked nodes cannot bootstrap, fetch their configuration or renew their certificates.
+type RevocationList struct {
+	base vfs.Path
+}
+
+// NewRevocationList returns the revocation list for the cluster with the given config base.
+func NewRevocationList(configBase vfs.Path) *RevocationList {
+	return &RevocationList{base: RevocationListPath(configBase)}
+}
+
+// RevocationListPath returns the directory of the revocation list for the cluster with the given config base.
+func RevocationListPath(configBase vfs.Path) vfs.Path {
+	return configBase.Join("node-identity", "revoked")
+}
+
+// Path returns the path at which the revocation of the node is stored.
+func (l *RevocationList) Path(nodeName string) vfs.Path {
+	return l.base.Join(nodeName)
+}
+
+// Revoke adds the node to the revocation list.
+func (l *RevocationList) Revoke(ctx context.Context, node *RevokedNode, acl vfs.ACL) error {
+	if errs := validation.IsDNS1123Subdomain(node.Name); len(errs) != 0 {
+		return fmt.Errorf("invalid node name %q: %v", node.Name, errs)
+	}
+	b, err := json.Marshal(node)
+	if err != nil {
+		return fmt.Errorf("encoding revoked node: %w", err)
+	}
+	p := l.Path(node.Name)
+	if err := p.WriteFile(ctx, bytes.NewReader(b), acl); err != nil {
+		return fmt.Errorf("writing %s: %w", p, err)
+	}
+	return nil
+}
+
+// Get returns the revocation of the node, or nil if it has not been revoked.
+func (l *RevocationList) Get(ctx context.Context, nodeName string) (*RevokedNode, error) {
+	if errs := validation.IsDNS1123Subdomain(nodeName); len(errs) != 0 {
+		return nil, fmt.Errorf("invalid node name %q: %v", nodeName, errs)
+	}
+	p := l.Path(nodeName)
+	b, err := p.ReadFile(ctx)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading %s: %w", p, err)
+	}
+	node := &RevokedNode{}
+	if err := json.Unmarshal(b, node); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", p, err)
+	}
+	return node, nil
+}
+
+// Remove removes the node from the revocation list, restoring its identity.
+func (l *RevocationList) Remove(ctx context.Context, nodeName string) error {
+	if errs := validation.IsDNS1123Subdomain(nodeName); len(errs) != 0 {
+		return fmt.Errorf("invalid node name %q: %v", nodeName, errs)
+	}
+	p := l.Path(nodeName)
+	if err := p.Remove(ctx); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing %s: %w", p, err)
+	}
+	return nil
+}
+
+// List returns all the revoked nodes.
+func (l *RevocationList) List(ctx context.Context) ([]*RevokedNode, error) {
+	files, err := l.base.ReadDir()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("listing %s: %w", l.base, err)
+	}
+	var nodes []*RevokedNode
+	for _, f := range files {
+		node, err := l.Get(ctx, f.Base())
+		if err != nil {
+			return nil, err
+		}
+		// The node may have been removed since we listed it.
+		if node != nil {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes, nil
+}
+
+// NewRevocationVerifier wraps the verifier, rejecting nodes whose identity has been revoked.
+// A node cannot bootstrap with the name of a revoked node until the revocation is removed,
+// which kops-controller does when the revoked Node object is deleted.
+func NewRevocationVerifier(verifier Verifier, revocations *RevocationList) Verifier {
+	return &revocationVerifier{verifier: verifier, revocations: revocations}
+}
+
+type revocationVerifier struct {
+	verifier    Verifier
+	revocations *RevocationList
+}
+
+func (v *revocationVerifier) VerifyToken(ctx context.Context, rawRequest *http.Request, token string, body []byte) (*VerifyResult, error) {
+	result, err := v.verifier.VerifyToken(ctx, rawRequest, token, body)
+	if err != nil {
+		return nil, err
+	}
+	revoked, err := v.revocations.Get(ctx, result.NodeName)
+	if err != nil {
+		return nil, err
+	}
+	if revoked != nil {
+		return nil, fmt.Errorf("%w: node %q was revoked at %v", ErrRevoked, result.NodeName, revoked.RevokedAt)
+	}
+	return result, nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+type fixedVerifier struct {
+	result *VerifyResult
+}
+
+func (v *fixedVerifier) VerifyToken(ctx context.Context, rawRequest *http.Request, token string, body []byte) (*VerifyResult, error) {
+	return v.result, nil
+}
+
+func TestRevocationVerifier(t *testing.T) {
+	ctx := context.Background()
+	configBase := vfs.NewMemFSPath(vfs.NewMemFSContext(), "memfs://tests/cluster.example.com")
+	revocations := NewRevocationList(configBase)
+
+	revoked, err := revocations.List(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error listing: %v", err)
+	}
+	if len(revoked) != 0 {
+		t.Errorf("expected no revoked nodes, got %v", revoked)
+	}
+
+	good := NewRevocationVerifier(&fixedVerifier{result: &VerifyResult{NodeName: "node-a"}}, revocations)
+	bad := NewRevocationVerifier(&fixedVerifier{result: &VerifyResult{NodeName: "node-b"}}, revocations)
+
+	if _, err := bad.VerifyToken(ctx, nil, "", nil); err != nil {
+		t.Errorf("unexpected error before revocation: %v", err)
+	}
+
+	revokedAt := time.Now().UTC().Truncate(time.Second)
+	if err := revocations.Revoke(ctx, &RevokedNode{Name: "node-b", UID: "uid-b", Reason: "test", RevokedAt: revokedAt}, nil); err != nil {
+		t.Fatalf("unexpected error revoking: %v", err)
+	}
+
+	revoked, err = revocations.List(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error listing: %v", err)
+	}
+	expected := []*RevokedNode{{Name: "node-b", UID: "uid-b", Reason: "test", RevokedAt: revokedAt}}
+	if !reflect.DeepEqual(revoked, expected) {
+		t.Errorf("unexpected revoked nodes %v", revoked)
+	}
+	if !revoked[0].AppliesTo("uid-b") {
+		t.Errorf("expected revocation to apply to the revoked Node")
+	}
+	if revoked[0].AppliesTo("uid-replacement") {
+		t.Errorf("expected revocation not to apply to a replacement Node")
+	}
+
+	if _, err := good.VerifyToken(ctx, nil, "", nil); err != nil {
+		t.Errorf("unexpected error for node that was not revoked: %v", err)
+	}
+	if _, err := bad.VerifyToken(ctx, nil, "", nil); !errors.Is(err, ErrRevoked) {
+		t.Errorf("expected ErrRevoked, got %v", err)
+	}
+
+	if err := revocations.Remove(ctx, "node-b"); err != nil {
+		t.Fatalf("unexpected error removing: %v", err)
+	}
+	if _, err := bad.VerifyToken(ctx, nil, "", nil); err != nil {
+		t.Errorf("unexpected error after restoring node: %v", err)
+	}
+	if err := revocations.Remove(ctx, "node-b"); err != nil {
+		t.Errorf("unexpected error removing node that was not revoked: %v", err)
+	}
+
+	if err := revocations.Revoke(ctx, &RevokedNode{Name: "../config"}, nil); err == nil {
+		t.Errorf("expected error revoking invalid node name")
+	}
+}
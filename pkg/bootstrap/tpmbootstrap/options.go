@@ -0,0 +1,44 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tpmbootstrap
+
+// Options describes how we authenticate instances with TPM 2.0 attestation.
+type Options struct {
+	// MaxTimeSkew is the maximum time skew to allow (in seconds)
+	MaxTimeSkew int64 `json:"maxTimeSkew,omitempty"`
+
+	// EKRootCAs are the PEM-encoded CA certificates that endorsement key certificates must chain to.
+	// If empty, endorsement keys are only checked against the allowlist in the Host objects.
+	EKRootCAs []string `json:"ekRootCAs,omitempty"`
+
+	// PCRs are the expected values of PCRs in the SHA-256 bank.
+	PCRs []PCR `json:"pcrs,omitempty"`
+}
+
+// PCR lists the accepted values of a PCR.
+type PCR struct {
+	// Index is the index of the PCR.
+	Index int `json:"index"`
+	// Values are the accepted hex-encoded SHA-256 values.
+	Values []string `json:"values,omitempty"`
+}
+
+// AuthenticationTokenPrefix is the prefix used for authentication using TPM attestation
+const AuthenticationTokenPrefix = "x-tpm-attestation "
+
+// CredentialPath is the path of the kops-controller endpoint that issues credentials to TPMs.
+const CredentialPath = "/tpm/credential"
@@ -0,0 +1,82 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tpmbootstrap
+
+// CredentialRequest asks kops-controller for a credential that only the node's TPM can activate.
+// This proves that the attestation key lives in the same TPM as the endorsement key.
+type CredentialRequest struct {
+	// EKCertificate is the DER-encoded endorsement key certificate, if the TPM has one.
+	EKCertificate []byte `json:"ekCertificate,omitempty"`
+	// EKPublicKey is the DER-encoded (PKIX) public endorsement key.
+	EKPublicKey []byte `json:"ekPublicKey,omitempty"`
+	// AKPublic is the TPM-encoded public area (TPMT_PUBLIC) of the attestation key.
+	AKPublic []byte `json:"akPublic,omitempty"`
+}
+
+// CredentialResponse is an encrypted credential for a CredentialRequest.
+type CredentialResponse struct {
+	// Timestamp is the time the credential was issued; it must be echoed back in the AuthToken.
+	Timestamp int64 `json:"timestamp,omitempty"`
+	// CredentialBlob is the TPM2B_ID_OBJECT for TPM2_ActivateCredential.
+	CredentialBlob []byte `json:"credentialBlob,omitempty"`
+	// EncryptedSecret is the TPM2B_ENCRYPTED_SECRET for TPM2_ActivateCredential.
+	EncryptedSecret []byte `json:"encryptedSecret,omitempty"`
+}
+
+// AuthToken describes the authentication header data when using TPM attestation.
+type AuthToken struct {
+	// Data is the data we are attesting to.
+	// It is a JSON encoded form of AuthTokenData.
+	Data []byte `json:"data,omitempty"`
+
+	// EKCertificate is the DER-encoded endorsement key certificate, if the TPM has one.
+	EKCertificate []byte `json:"ekCertificate,omitempty"`
+	// EKPublicKey is the DER-encoded (PKIX) public endorsement key.
+	EKPublicKey []byte `json:"ekPublicKey,omitempty"`
+	// AKPublic is the TPM-encoded public area (TPMT_PUBLIC) of the attestation key.
+	AKPublic []byte `json:"akPublic,omitempty"`
+
+	// CredentialTimestamp is the timestamp of the activated credential.
+	CredentialTimestamp int64 `json:"credentialTimestamp,omitempty"`
+	// CredentialSecret is the secret recovered by activating the credential.
+	CredentialSecret []byte `json:"credentialSecret,omitempty"`
+
+	// Quote is the TPMS_ATTEST structure of a quote over the PCRs, with the hash of Data as the qualifying data.
+	Quote []byte `json:"quote,omitempty"`
+	// QuoteSignature is the TPMT_SIGNATURE of the quote by the attestation key.
+	QuoteSignature []byte `json:"quoteSignature,omitempty"`
+	// PCRs are the values of the quoted PCRs in the SHA-256 bank.
+	PCRs map[int][]byte `json:"pcrs,omitempty"`
+}
+
+// AuthTokenData is the code data that is attested to as part of the header.
+type AuthTokenData struct {
+	// NodeName is the name of the node, which must match a Host object.
+	NodeName string `json:"nodeName,omitempty"`
+
+	// RequestHash is the hash of the request
+	RequestHash []byte `json:"requestHash,omitempty"`
+
+	// Timestamp is the time of this request (to help prevent replay attacks)
+	Timestamp int64 `json:"timestamp,omitempty"`
+
+	// Audience is the audience for this request (to help prevent replay attacks)
+	Audience string `json:"audience,omitempty"`
+}
+
+// AudienceNodeAuthentication is used in case we have multiple audiences using the TPM in future
+const AudienceNodeAuthentication = "kops.k8s.io/node-bootstrap"
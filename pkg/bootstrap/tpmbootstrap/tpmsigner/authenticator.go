@@ -0,0 +1,231 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tpmsigner
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/go-tpm-tools/client"
+	"github.com/google/go-tpm/legacy/tpm2"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/bootstrap"
+	"k8s.io/kops/pkg/bootstrap/tpmbootstrap"
+)
+
+// CredentialClient requests credentials from kops-controller.
+type CredentialClient interface {
+	IssueTPMCredential(ctx context.Context, req any, resp any) error
+}
+
+// tpmDevice is the subset of TPM operations we need, so that tests can use a software TPM.
+type tpmDevice interface {
+	// EndorsementKey returns the DER-encoded EK certificate (if any) and the DER-encoded (PKIX) public EK.
+	EndorsementKey() ([]byte, []byte, error)
+	// AttestationKey returns the TPM-encoded public area of the AK.
+	AttestationKey() ([]byte, error)
+	// ActivateCredential recovers the secret of a credential issued for the EK and AK.
+	ActivateCredential(credentialBlob []byte, encryptedSecret []byte) ([]byte, error)
+	// Quote returns the TPMS_ATTEST and TPMT_SIGNATURE of a quote over the SHA-256 PCRs, along with the PCR values.
+	Quote(extraData []byte) ([]byte, []byte, map[int][]byte, error)
+	Close() error
+}
+
+type tpmAuthenticator struct {
+	nodeName string
+	client   CredentialClient
+	openTPM  func() (tpmDevice, error)
+}
+
+var _ bootstrap.Authenticator = &tpmAuthenticator{}
+
+// NewAuthenticator constructs an authenticator that attests to the node's identity with its TPM.
+// The client is used to obtain the credential that proves the attestation key is in the TPM.
+func NewAuthenticator(nodeName string, client CredentialClient) (bootstrap.Authenticator, error) {
+	return &tpmAuthenticator{nodeName: nodeName, client: client, openTPM: openTPMDevice}, nil
+}
+
+func (a *tpmAuthenticator) CreateToken(body []byte) (string, error) {
+	ctx := context.TODO()
+
+	tpm, err := a.openTPM()
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err := tpm.Close(); err != nil {
+			klog.Warningf("error closing TPM: %v", err)
+		}
+	}()
+
+	ekCertificate, ekPublicKey, err := tpm.EndorsementKey()
+	if err != nil {
+		return "", err
+	}
+	akPublic, err := tpm.AttestationKey()
+	if err != nil {
+		return "", err
+	}
+
+	credentialRequest := &tpmbootstrap.CredentialRequest{
+		EKCertificate: ekCertificate,
+		EKPublicKey:   ekPublicKey,
+		AKPublic:      akPublic,
+	}
+	credential := &tpmbootstrap.CredentialResponse{}
+	if err := a.client.IssueTPMCredential(ctx, credentialRequest, credential); err != nil {
+		return "", fmt.Errorf("requesting credential: %w", err)
+	}
+	secret, err := tpm.ActivateCredential(credential.CredentialBlob, credential.EncryptedSecret)
+	if err != nil {
+		return "", err
+	}
+
+	requestHash := sha256.Sum256(body)
+	data := tpmbootstrap.AuthTokenData{
+		Timestamp:   time.Now().Unix(),
+		Audience:    tpmbootstrap.AudienceNodeAuthentication,
+		RequestHash: requestHash[:],
+		NodeName:    a.nodeName,
+	}
+	payload, err := json.Marshal(&data)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal token data: %w", err)
+	}
+
+	payloadHash := sha256.Sum256(payload)
+	quote, quoteSignature, pcrs, err := tpm.Quote(payloadHash[:])
+	if err != nil {
+		return "", err
+	}
+
+	token := &tpmbootstrap.AuthToken{
+		Data:                payload,
+		EKCertificate:       ekCertificate,
+		EKPublicKey:         ekPublicKey,
+		AKPublic:            akPublic,
+		CredentialTimestamp: credential.Timestamp,
+		CredentialSecret:    secret,
+		Quote:               quote,
+		QuoteSignature:      quoteSignature,
+		PCRs:                pcrs,
+	}
+	b, err := json.Marshal(token)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal token: %w", err)
+	}
+	return tpmbootstrap.AuthenticationTokenPrefix + base64.StdEncoding.EncodeToString(b), nil
+}
+
+// hardwareTPM implements tpmDevice using a TPM 2.0 device.
+type hardwareTPM struct {
+	rw io.ReadWriteCloser
+	ek *client.Key
+	ak *client.Key
+}
+
+func newHardwareTPM(rw io.ReadWriteCloser) (*hardwareTPM, error) {
+	t := &hardwareTPM{rw: rw}
+
+	var err error
+	t.ek, err = client.EndorsementKeyRSA(rw)
+	if err != nil {
+		t.Close()
+		return nil, fmt.Errorf("loading endorsement key: %w", err)
+	}
+	t.ak, err = client.AttestationKeyRSA(rw)
+	if err != nil {
+		t.Close()
+		return nil, fmt.Errorf("creating attestation key: %w", err)
+	}
+	return t, nil
+}
+
+func (t *hardwareTPM) EndorsementKey() ([]byte, []byte, error) {
+	ekPublicKey, err := x509.MarshalPKIXPublicKey(t.ek.PublicKey())
+	if err != nil {
+		return nil, nil, fmt.Errorf("encoding endorsement key: %w", err)
+	}
+	return t.ek.CertDERBytes(), ekPublicKey, nil
+}
+
+func (t *hardwareTPM) AttestationKey() ([]byte, error) {
+	akPublic, err := t.ak.PublicArea().Encode()
+	if err != nil {
+		return nil, fmt.Errorf("encoding attestation key: %w", err)
+	}
+	return akPublic, nil
+}
+
+func (t *hardwareTPM) ActivateCredential(credentialBlob []byte, encryptedSecret []byte) ([]byte, error) {
+	// The credential and secret are TPM2B structures; the TPM command adds the size prefix itself.
+	if len(credentialBlob) < 2 || len(encryptedSecret) < 2 {
+		return nil, fmt.Errorf("invalid credential")
+	}
+
+	session, err := client.NewEKSession(t.rw)
+	if err != nil {
+		return nil, fmt.Errorf("starting endorsement key session: %w", err)
+	}
+	defer session.Close()
+	ekAuth, err := session.Auth()
+	if err != nil {
+		return nil, fmt.Errorf("authorizing endorsement key: %w", err)
+	}
+
+	auth := []tpm2.AuthCommand{
+		{Session: tpm2.HandlePasswordSession, Attributes: tpm2.AttrContinueSession},
+		ekAuth,
+	}
+	secret, err := tpm2.ActivateCredentialUsingAuth(t.rw, auth, t.ak.Handle(), t.ek.Handle(), credentialBlob[2:], encryptedSecret[2:])
+	if err != nil {
+		return nil, fmt.Errorf("activating credential: %w", err)
+	}
+	return secret, nil
+}
+
+func (t *hardwareTPM) Quote(extraData []byte) ([]byte, []byte, map[int][]byte, error) {
+	selection := tpm2.PCRSelection{Hash: tpm2.AlgSHA256}
+	for i := 0; i < 24; i++ {
+		selection.PCRs = append(selection.PCRs, i)
+	}
+	quote, err := t.ak.Quote(selection, extraData)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("quoting PCRs: %w", err)
+	}
+	pcrs := make(map[int][]byte)
+	for index, value := range quote.GetPcrs().GetPcrs() {
+		pcrs[int(index)] = value
+	}
+	return quote.GetQuote(), quote.GetRawSig(), pcrs, nil
+}
+
+func (t *hardwareTPM) Close() error {
+	if t.ak != nil {
+		t.ak.Close()
+	}
+	if t.ek != nil {
+		t.ek.Close()
+	}
+	return t.rw.Close()
+}
@@ -0,0 +1,41 @@
+//go:build !windows
+
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tpmsigner
+
+import (
+	"fmt"
+
+	"github.com/google/go-tpm/legacy/tpm2"
+)
+
+// tpmPaths are the TPM devices we try, preferring the kernel resource manager.
+var tpmPaths = []string{"/dev/tpmrm0", "/dev/tpm0"}
+
+func openTPMDevice() (tpmDevice, error) {
+	var errs []error
+	for _, p := range tpmPaths {
+		rw, err := tpm2.OpenTPM(p)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("tpm2.OpenTPM(%q): %w", p, err))
+			continue
+		}
+		return newHardwareTPM(rw)
+	}
+	return nil, fmt.Errorf("opening TPM: %v", errs)
+}
@@ -0,0 +1,466 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tpmsigner
+
+import (
+	"context"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-tpm/legacy/tpm2"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kops "k8s.io/kops/pkg/apis/kops/v1alpha2"
+	"k8s.io/kops/pkg/bootstrap"
+	"k8s.io/kops/pkg/bootstrap/tpmbootstrap"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// fakeTPM is a software implementation of tpmDevice.
+type fakeTPM struct {
+	ek     *rsa.PrivateKey
+	ekCert []byte
+	ak     *rsa.PrivateKey
+	pcrs   map[int][]byte
+}
+
+func newFakeTPM(t *testing.T) *fakeTPM {
+	ek, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating EK: %v", err)
+	}
+	ak, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating AK: %v", err)
+	}
+	pcrs := make(map[int][]byte)
+	for i := 0; i < 24; i++ {
+		pcrs[i] = make([]byte, sha256.Size)
+	}
+	return &fakeTPM{ek: ek, ak: ak, pcrs: pcrs}
+}
+
+func (f *fakeTPM) EndorsementKey() ([]byte, []byte, error) {
+	ekPublicKey, err := x509.MarshalPKIXPublicKey(&f.ek.PublicKey)
+	return f.ekCert, ekPublicKey, err
+}
+
+func (f *fakeTPM) akPublic() tpm2.Public {
+	return tpm2.Public{
+		Type:       tpm2.AlgRSA,
+		NameAlg:    tpm2.AlgSHA256,
+		Attributes: tpm2.FlagRestricted | tpm2.FlagSign | tpm2.FlagFixedTPM | tpm2.FlagFixedParent | tpm2.FlagSensitiveDataOrigin | tpm2.FlagUserWithAuth,
+		RSAParameters: &tpm2.RSAParams{
+			Sign:       &tpm2.SigScheme{Alg: tpm2.AlgRSASSA, Hash: tpm2.AlgSHA256},
+			KeyBits:    2048,
+			ModulusRaw: f.ak.N.Bytes(),
+		},
+	}
+}
+
+func (f *fakeTPM) AttestationKey() ([]byte, error) {
+	return f.akPublic().Encode()
+}
+
+// ActivateCredential implements TPM2_ActivateCredential, as described in part 1 of the TPM 2.0 specification.
+func (f *fakeTPM) ActivateCredential(credentialBlob []byte, encryptedSecret []byte) ([]byte, error) {
+	seed, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, f.ek, encryptedSecret[2:], []byte("IDENTITY\x00"))
+	if err != nil {
+		return nil, fmt.Errorf("decrypting seed: %w", err)
+	}
+	akName, err := f.akPublic().Name()
+	if err != nil {
+		return nil, err
+	}
+	name, err := akName.Digest.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	blob := credentialBlob[2:]
+	hmacSize := int(binary.BigEndian.Uint16(blob))
+	integrity, encIdentity := blob[2:2+hmacSize], blob[2+hmacSize:]
+
+	macKey, err := tpm2.KDFa(tpm2.AlgSHA256, seed, "INTEGRITY", nil, nil, sha256.Size*8)
+	if err != nil {
+		return nil, err
+	}
+	mac := hmac.New(sha256.New, macKey)
+	mac.Write(encIdentity)
+	mac.Write(name)
+	if !hmac.Equal(mac.Sum(nil), integrity) {
+		return nil, fmt.Errorf("credential integrity check failed")
+	}
+
+	symmetricKey, err := tpm2.KDFa(tpm2.AlgSHA256, seed, "STORAGE", name, nil, 128)
+	if err != nil {
+		return nil, err
+	}
+	c, err := aes.NewCipher(symmetricKey)
+	if err != nil {
+		return nil, err
+	}
+	cv := make([]byte, len(encIdentity))
+	cipher.NewCFBDecrypter(c, make([]byte, len(symmetricKey))).XORKeyStream(cv, encIdentity)
+	return cv[2:], nil
+}
+
+func (f *fakeTPM) Quote(extraData []byte) ([]byte, []byte, map[int][]byte, error) {
+	selection := tpm2.PCRSelection{Hash: tpm2.AlgSHA256}
+	digest := sha256.New()
+	for i := 0; i < 24; i++ {
+		selection.PCRs = append(selection.PCRs, i)
+		digest.Write(f.pcrs[i])
+	}
+	akName, err := f.akPublic().Name()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	quote, err := tpm2.AttestationData{
+		Magic:           0xff544347,
+		Type:            tpm2.TagAttestQuote,
+		QualifiedSigner: akName,
+		ExtraData:       extraData,
+		AttestedQuoteInfo: &tpm2.QuoteInfo{
+			PCRSelection: selection,
+			PCRDigest:    digest.Sum(nil),
+		},
+	}.Encode()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	quoteHash := sha256.Sum256(quote)
+	rawSig, err := rsa.SignPKCS1v15(rand.Reader, f.ak, crypto.SHA256, quoteHash[:])
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	sig, err := tpm2.Signature{
+		Alg: tpm2.AlgRSASSA,
+		RSA: &tpm2.SignatureRSA{HashAlg: tpm2.AlgSHA256, Signature: rawSig},
+	}.Encode()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	pcrs := make(map[int][]byte)
+	for i, v := range f.pcrs {
+		pcrs[i] = v
+	}
+	return quote, sig, pcrs, nil
+}
+
+func (f *fakeTPM) Close() error {
+	return nil
+}
+
+// fakeClient serves Host objects.
+type fakeClient struct {
+	client.Client
+	hosts map[string]*kops.Host
+}
+
+func (c *fakeClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	host, found := c.hosts[key.String()]
+	if !found {
+		return apierrors.NewNotFound(schema.GroupResource{Group: "kops.k8s.io", Resource: "hosts"}, key.Name)
+	}
+	*obj.(*kops.Host) = *host
+	return nil
+}
+
+// credentialClient passes credential requests to the verifier, as kops-controller would.
+type credentialClient struct {
+	verifier *tpmbootstrap.Verifier
+}
+
+func (c *credentialClient) IssueTPMCredential(ctx context.Context, req any, resp any) error {
+	b, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	credentialRequest := &tpmbootstrap.CredentialRequest{}
+	if err := json.Unmarshal(b, credentialRequest); err != nil {
+		return err
+	}
+	credentialResponse, err := c.verifier.IssueCredential(ctx, credentialRequest)
+	if err != nil {
+		return err
+	}
+	b, err = json.Marshal(credentialResponse)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, resp)
+}
+
+func ekHash(t *testing.T, tpm tpmDevice) string {
+	_, ekPublicKey, err := tpm.EndorsementKey()
+	if err != nil {
+		t.Fatalf("reading EK: %v", err)
+	}
+	hash := sha256.Sum256(ekPublicKey)
+	return hex.EncodeToString(hash[:])
+}
+
+func newEKCA(t *testing.T) (*x509.Certificate, *rsa.PrivateKey) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating CA key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "tpm-manufacturer"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("creating CA certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("parsing CA certificate: %v", err)
+	}
+	return cert, key
+}
+
+func issueEKCertificate(t *testing.T, ca *x509.Certificate, caKey *rsa.PrivateKey, tpm *fakeTPM) {
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageKeyEncipherment,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &tpm.ek.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("creating EK certificate: %v", err)
+	}
+	tpm.ekCert = der
+}
+
+func TestTPMAttestation(t *testing.T) {
+	ctx := context.Background()
+
+	ca, caKey := newEKCA(t)
+	caPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Raw}))
+	otherCA, _ := newEKCA(t)
+	otherCAPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: otherCA.Raw}))
+
+	zeroPCR := strings.Repeat("00", sha256.Size)
+	otherPCR := strings.Repeat("ab", sha256.Size)
+
+	grid := []struct {
+		name        string
+		options     tpmbootstrap.Options
+		registerEK  bool
+		tamperBody  bool
+		expectError string
+	}{
+		{
+			name:       "success",
+			options:    tpmbootstrap.Options{EKRootCAs: []string{caPEM}, PCRs: []tpmbootstrap.PCR{{Index: 7, Values: []string{otherPCR, zeroPCR}}}},
+			registerEK: true,
+		},
+		{
+			name:        "pcr mismatch",
+			options:     tpmbootstrap.Options{PCRs: []tpmbootstrap.PCR{{Index: 7, Values: []string{otherPCR}}}},
+			registerEK:  true,
+			expectError: "PCR 7 has unexpected value",
+		},
+		{
+			name:        "ek not issued by allowed CA",
+			options:     tpmbootstrap.Options{EKRootCAs: []string{otherCAPEM}},
+			registerEK:  true,
+			expectError: "verifying endorsement key certificate",
+		},
+		{
+			name:        "ek not registered",
+			options:     tpmbootstrap.Options{},
+			expectError: "is not registered for host",
+		},
+		{
+			name:        "tampered body",
+			options:     tpmbootstrap.Options{},
+			registerEK:  true,
+			tamperBody:  true,
+			expectError: "incorrect RequestHash",
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			tpm := newFakeTPM(t)
+			issueEKCertificate(t, ca, caKey, tpm)
+
+			host := &kops.Host{}
+			host.Spec.InstanceGroup = "nodes"
+			host.Spec.TPM = &kops.HostTPMSpec{EKPublicKeyHash: strings.Repeat("00", sha256.Size)}
+			if g.registerEK {
+				host.Spec.TPM.EKPublicKeyHash = ekHash(t, tpm)
+			}
+			kube := &fakeClient{hosts: map[string]*kops.Host{"kops-system/node1": host}}
+
+			verifier, err := tpmbootstrap.NewVerifier(&g.options, kube, []byte("credential-key"))
+			if err != nil {
+				t.Fatalf("building verifier: %v", err)
+			}
+			authenticator := &tpmAuthenticator{
+				nodeName: "node1",
+				client:   &credentialClient{verifier: verifier},
+				openTPM:  func() (tpmDevice, error) { return tpm, nil },
+			}
+
+			// Unacceptable endorsement keys are rejected when the credential is requested.
+			var result *bootstrap.VerifyResult
+			body := []byte(`{"apiVersion":"bootstrap.kops.k8s.io/v1alpha1"}`)
+			token, err := authenticator.CreateToken(body)
+			if err == nil {
+				if g.tamperBody {
+					body = append(body, ' ')
+				}
+				result, err = verifier.VerifyToken(ctx, &http.Request{}, token, body)
+			}
+			if g.expectError != "" {
+				if err == nil || !strings.Contains(err.Error(), g.expectError) {
+					t.Fatalf("expected error containing %q, got %v", g.expectError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("verifying token: %v", err)
+			}
+			if result.NodeName != "node1" || result.InstanceGroupName != "nodes" {
+				t.Errorf("unexpected result %+v", result)
+			}
+		})
+	}
+}
+
+func TestTPMAttestationWrongCredential(t *testing.T) {
+	tpm := newFakeTPM(t)
+	host := &kops.Host{}
+	host.Spec.InstanceGroup = "nodes"
+	host.Spec.TPM = &kops.HostTPMSpec{EKPublicKeyHash: ekHash(t, tpm)}
+	kube := &fakeClient{hosts: map[string]*kops.Host{"kops-system/node1": host}}
+
+	// The credential is issued by a kops-controller with a different key.
+	issuer, err := tpmbootstrap.NewVerifier(&tpmbootstrap.Options{}, kube, []byte("other-key"))
+	if err != nil {
+		t.Fatalf("building verifier: %v", err)
+	}
+	verifier, err := tpmbootstrap.NewVerifier(&tpmbootstrap.Options{}, kube, []byte("credential-key"))
+	if err != nil {
+		t.Fatalf("building verifier: %v", err)
+	}
+	authenticator := &tpmAuthenticator{
+		nodeName: "node1",
+		client:   &credentialClient{verifier: issuer},
+		openTPM:  func() (tpmDevice, error) { return tpm, nil },
+	}
+
+	body := []byte("{}")
+	token, err := authenticator.CreateToken(body)
+	if err != nil {
+		t.Fatalf("creating token: %v", err)
+	}
+	if _, err := verifier.VerifyToken(context.Background(), &http.Request{}, token, body); err == nil || !strings.Contains(err.Error(), "credential was not activated") {
+		t.Fatalf("expected credential error, got %v", err)
+	}
+}
+
+// TestHardwareTPM runs against a real or emulated TPM, for example:
+// swtpm chardev --vtpm-proxy --tpm2 --tpmstate dir=/tmp/swtpm
+// KOPS_TEST_TPM=/dev/tpmrm1 go test ./pkg/bootstrap/tpmbootstrap/tpmsigner/
+func TestHardwareTPM(t *testing.T) {
+	tpmPath := os.Getenv("KOPS_TEST_TPM")
+	if tpmPath == "" {
+		t.Skip("KOPS_TEST_TPM not set")
+	}
+
+	openTPM := func() (tpmDevice, error) {
+		rw, err := tpm2.OpenTPM(tpmPath)
+		if err != nil {
+			return nil, err
+		}
+		return newHardwareTPM(rw)
+	}
+	tpm, err := openTPM()
+	if err != nil {
+		t.Fatalf("opening TPM: %v", err)
+	}
+	hash := ekHash(t, tpm)
+	if err := tpm.Close(); err != nil {
+		t.Fatalf("closing TPM: %v", err)
+	}
+
+	host := &kops.Host{}
+	host.Spec.InstanceGroup = "nodes"
+	host.Spec.TPM = &kops.HostTPMSpec{EKPublicKeyHash: hash}
+	kube := &fakeClient{hosts: map[string]*kops.Host{"kops-system/node1": host}}
+	verifier, err := tpmbootstrap.NewVerifier(&tpmbootstrap.Options{}, kube, []byte("credential-key"))
+	if err != nil {
+		t.Fatalf("building verifier: %v", err)
+	}
+	authenticator := &tpmAuthenticator{
+		nodeName: "node1",
+		client:   &credentialClient{verifier: verifier},
+		openTPM:  openTPM,
+	}
+
+	body := []byte("{}")
+	token, err := authenticator.CreateToken(body)
+	if err != nil {
+		t.Fatalf("creating token: %v", err)
+	}
+	if _, err := verifier.VerifyToken(context.Background(), &http.Request{}, token, body); err != nil {
+		t.Fatalf("verifying token: %v", err)
+	}
+}
+
+func TestVerifyTokenWrongPrefix(t *testing.T) {
+	verifier, err := tpmbootstrap.NewVerifier(&tpmbootstrap.Options{}, &fakeClient{}, []byte("credential-key"))
+	if err != nil {
+		t.Fatalf("building verifier: %v", err)
+	}
+	_, err = verifier.VerifyToken(context.Background(), &http.Request{}, "x-pki-tpm abc", nil)
+	if !errors.Is(err, bootstrap.ErrNotThisVerifier) {
+		t.Errorf("expected ErrNotThisVerifier, got %v", err)
+	}
+}
@@ -0,0 +1,378 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tpmbootstrap
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/go-tpm/legacy/tpm2"
+	"github.com/google/go-tpm/legacy/tpm2/credactivation"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	kops "k8s.io/kops/pkg/apis/kops/v1alpha2"
+	"k8s.io/kops/pkg/bootstrap"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// requiredAKAttributes are the attributes of a key that the TPM generated, cannot export and only signs data it generated itself.
+const requiredAKAttributes = tpm2.FlagRestricted | tpm2.FlagSign | tpm2.FlagFixedTPM | tpm2.FlagFixedParent | tpm2.FlagSensitiveDataOrigin
+
+// Verifier verifies TPM attestation tokens, and issues the credentials that nodes activate to prove
+// that their attestation key and endorsement key are in the same TPM.
+type Verifier struct {
+	opt    Options
+	client client.Client
+
+	// credentialKey is the key we use to compute the credential secrets.
+	// It must be the same for all kops-controller instances.
+	credentialKey []byte
+
+	ekRoots *x509.CertPool
+	pcrs    map[int][][]byte
+}
+
+var _ bootstrap.Verifier = &Verifier{}
+
+// NewVerifier constructs a new verifier.
+// The credentialKey is used to derive the credential secrets, and must be shared by all instances of kops-controller.
+func NewVerifier(options *Options, client client.Client, credentialKey []byte) (*Verifier, error) {
+	opt := *options
+	if opt.MaxTimeSkew == 0 {
+		opt.MaxTimeSkew = 300
+	}
+	if len(credentialKey) == 0 {
+		return nil, fmt.Errorf("credential key is required")
+	}
+
+	v := &Verifier{
+		opt:           opt,
+		client:        client,
+		credentialKey: credentialKey,
+		pcrs:          make(map[int][][]byte),
+	}
+
+	if len(opt.EKRootCAs) != 0 {
+		v.ekRoots = x509.NewCertPool()
+		for _, ca := range opt.EKRootCAs {
+			if !v.ekRoots.AppendCertsFromPEM([]byte(ca)) {
+				return nil, fmt.Errorf("no certificates found in endorsement key root CA %q", ca)
+			}
+		}
+	}
+
+	for _, pcr := range opt.PCRs {
+		for _, value := range pcr.Values {
+			b, err := hex.DecodeString(value)
+			if err != nil || len(b) != sha256.Size {
+				return nil, fmt.Errorf("invalid value %q for PCR %d", value, pcr.Index)
+			}
+			v.pcrs[pcr.Index] = append(v.pcrs[pcr.Index], b)
+		}
+	}
+
+	return v, nil
+}
+
+// IssueCredential creates a credential for the attestation key, encrypted to the endorsement key.
+// Only the TPM holding both keys can activate it.
+func (v *Verifier) IssueCredential(ctx context.Context, req *CredentialRequest) (*CredentialResponse, error) {
+	ekHash, ekPub, err := v.verifyEndorsementKey(req.EKCertificate, req.EKPublicKey)
+	if err != nil {
+		return nil, err
+	}
+	_, akName, err := parseAttestationKey(req.AKPublic)
+	if err != nil {
+		return nil, err
+	}
+
+	timestamp := time.Now().Unix()
+	secret := v.credentialSecret(ekHash, akName, timestamp)
+
+	credentialBlob, encryptedSecret, err := credactivation.Generate(akName.Digest, ekPub, 16, secret)
+	if err != nil {
+		return nil, fmt.Errorf("generating credential: %w", err)
+	}
+
+	return &CredentialResponse{
+		Timestamp:       timestamp,
+		CredentialBlob:  credentialBlob,
+		EncryptedSecret: encryptedSecret,
+	}, nil
+}
+
+func (v *Verifier) VerifyToken(ctx context.Context, rawRequest *http.Request, authToken string, body []byte) (*bootstrap.VerifyResult, error) {
+	if !strings.HasPrefix(authToken, AuthenticationTokenPrefix) {
+		return nil, bootstrap.ErrNotThisVerifier
+	}
+
+	token, tokenData, err := v.parseTokenData(authToken, body)
+	if err != nil {
+		return nil, err
+	}
+
+	ekHash, _, err := v.verifyEndorsementKey(token.EKCertificate, token.EKPublicKey)
+	if err != nil {
+		return nil, err
+	}
+	akPub, akName, err := parseAttestationKey(token.AKPublic)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify that the node activated a credential we issued for this EK and AK, which proves they are in the same TPM.
+	timeSkew := math.Abs(time.Since(time.Unix(token.CredentialTimestamp, 0)).Seconds())
+	if timeSkew > float64(v.opt.MaxTimeSkew) {
+		return nil, fmt.Errorf("credential has expired")
+	}
+	expectedSecret := v.credentialSecret(ekHash, akName, token.CredentialTimestamp)
+	if !hmac.Equal(expectedSecret, token.CredentialSecret) {
+		return nil, fmt.Errorf("credential was not activated by the endorsement key")
+	}
+
+	// Verify that the EK is the one registered for the host.
+	result, err := v.getHost(ctx, tokenData.NodeName, ekHash)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify that the AK has quoted the token data and the PCRs.
+	if err := v.verifyQuote(token, akPub); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// TODO: Dedup with pkibootstrap
+func (v *Verifier) parseTokenData(authToken string, body []byte) (*AuthToken, *AuthTokenData, error) {
+	authToken = strings.TrimPrefix(authToken, AuthenticationTokenPrefix)
+
+	tokenBytes, err := base64.StdEncoding.DecodeString(authToken)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decoding authorization token: %w", err)
+	}
+
+	token := &AuthToken{}
+	if err = json.Unmarshal(tokenBytes, token); err != nil {
+		return nil, nil, fmt.Errorf("unmarshalling authorization token: %w", err)
+	}
+
+	tokenData := &AuthTokenData{}
+	if err := json.Unmarshal(token.Data, tokenData); err != nil {
+		return nil, nil, fmt.Errorf("unmarshalling authorization token data: %w", err)
+	}
+
+	// Guard against replay attacks
+	if tokenData.Audience != AudienceNodeAuthentication {
+		return nil, nil, fmt.Errorf("incorrect Audience")
+	}
+	timeSkew := math.Abs(time.Since(time.Unix(tokenData.Timestamp, 0)).Seconds())
+	if timeSkew > float64(v.opt.MaxTimeSkew) {
+		return nil, nil, fmt.Errorf("incorrect Timestamp %v", tokenData.Timestamp)
+	}
+
+	// Verify the token has signed the body content.
+	requestHash := sha256.Sum256(body)
+	if !bytes.Equal(requestHash[:], tokenData.RequestHash) {
+		return nil, nil, fmt.Errorf("incorrect RequestHash")
+	}
+
+	return token, tokenData, nil
+}
+
+// verifyEndorsementKey checks the endorsement key against the configured root CAs,
+// returning the hex-encoded SHA-256 hash of the key used to identify it in Host objects.
+func (v *Verifier) verifyEndorsementKey(ekCertificate []byte, ekPublicKey []byte) (string, crypto.PublicKey, error) {
+	var ekPub crypto.PublicKey
+	if len(ekCertificate) != 0 {
+		cert, err := x509.ParseCertificate(ekCertificate)
+		if err != nil {
+			return "", nil, fmt.Errorf("parsing endorsement key certificate: %w", err)
+		}
+		ekPub = cert.PublicKey
+		ekPublicKey, err = x509.MarshalPKIXPublicKey(ekPub)
+		if err != nil {
+			return "", nil, fmt.Errorf("encoding endorsement key: %w", err)
+		}
+
+		if v.ekRoots != nil {
+			// EK certificates mark the TPM specification attributes in the SAN as critical, which we don't parse.
+			cert.UnhandledCriticalExtensions = nil
+			if _, err := cert.Verify(x509.VerifyOptions{
+				Roots:       v.ekRoots,
+				CurrentTime: cert.NotBefore,
+				KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+			}); err != nil {
+				return "", nil, fmt.Errorf("verifying endorsement key certificate: %w", err)
+			}
+		}
+	} else {
+		if v.ekRoots != nil {
+			return "", nil, fmt.Errorf("endorsement key certificate is required")
+		}
+		if len(ekPublicKey) == 0 {
+			return "", nil, fmt.Errorf("endorsement key is required")
+		}
+		var err error
+		ekPub, err = x509.ParsePKIXPublicKey(ekPublicKey)
+		if err != nil {
+			return "", nil, fmt.Errorf("parsing endorsement key: %w", err)
+		}
+	}
+
+	if _, ok := ekPub.(*rsa.PublicKey); !ok {
+		return "", nil, fmt.Errorf("endorsement key type %T not supported", ekPub)
+	}
+
+	ekHash := sha256.Sum256(ekPublicKey)
+	return hex.EncodeToString(ekHash[:]), ekPub, nil
+}
+
+// parseAttestationKey decodes the public area of the attestation key, and checks that it is only usable for attestation.
+func parseAttestationKey(akPublic []byte) (*rsa.PublicKey, tpm2.Name, error) {
+	pub, err := tpm2.DecodePublic(akPublic)
+	if err != nil {
+		return nil, tpm2.Name{}, fmt.Errorf("decoding attestation key: %w", err)
+	}
+	if pub.Attributes&requiredAKAttributes != requiredAKAttributes {
+		return nil, tpm2.Name{}, fmt.Errorf("attestation key has attributes %v, must have %v", pub.Attributes, requiredAKAttributes)
+	}
+	if pub.Type != tpm2.AlgRSA {
+		return nil, tpm2.Name{}, fmt.Errorf("attestation key type %v not supported", pub.Type)
+	}
+	key, err := pub.Key()
+	if err != nil {
+		return nil, tpm2.Name{}, fmt.Errorf("decoding attestation key: %w", err)
+	}
+	name, err := pub.Name()
+	if err != nil {
+		return nil, tpm2.Name{}, fmt.Errorf("computing attestation key name: %w", err)
+	}
+	return key.(*rsa.PublicKey), name, nil
+}
+
+// credentialSecret computes the secret for a credential, so we don't need to remember the credentials we have issued.
+func (v *Verifier) credentialSecret(ekHash string, akName tpm2.Name, timestamp int64) []byte {
+	mac := hmac.New(sha256.New, v.credentialKey)
+	mac.Write([]byte(ekHash))
+	mac.Write(akName.Digest.Value)
+	_ = binary.Write(mac, binary.BigEndian, timestamp)
+	return mac.Sum(nil)
+}
+
+func (v *Verifier) getHost(ctx context.Context, nodeName string, ekHash string) (*bootstrap.VerifyResult, error) {
+	id := types.NamespacedName{
+		Namespace: "kops-system",
+		Name:      nodeName,
+	}
+	var host kops.Host
+	if err := v.client.Get(ctx, id, &host); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("host not found for %v", id)
+		}
+		return nil, fmt.Errorf("error getting host %v: %w", id, err)
+	}
+
+	if host.Spec.TPM == nil || host.Spec.TPM.EKPublicKeyHash == "" {
+		return nil, fmt.Errorf("host %v did not have spec.tpm.ekPublicKeyHash", id)
+	}
+	if !strings.EqualFold(host.Spec.TPM.EKPublicKeyHash, ekHash) {
+		return nil, fmt.Errorf("endorsement key %s is not registered for host %v", ekHash, id)
+	}
+	instanceGroup := host.Spec.InstanceGroup
+	if instanceGroup == "" {
+		return nil, fmt.Errorf("host %v did not have spec.instanceGroup", id)
+	}
+
+	return &bootstrap.VerifyResult{
+		NodeName:          nodeName,
+		InstanceGroupName: instanceGroup,
+	}, nil
+}
+
+// verifyQuote checks the quote signature, that the quote covers the token data, and that the PCRs match the policy.
+func (v *Verifier) verifyQuote(token *AuthToken, akPub *rsa.PublicKey) error {
+	sig, err := tpm2.DecodeSignature(bytes.NewBuffer(token.QuoteSignature))
+	if err != nil {
+		return fmt.Errorf("decoding quote signature: %w", err)
+	}
+	if sig.Alg != tpm2.AlgRSASSA || sig.RSA == nil || sig.RSA.HashAlg != tpm2.AlgSHA256 {
+		return fmt.Errorf("quote signature algorithm %v not supported", sig.Alg)
+	}
+	quoteHash := sha256.Sum256(token.Quote)
+	if err := rsa.VerifyPKCS1v15(akPub, crypto.SHA256, quoteHash[:], sig.RSA.Signature); err != nil {
+		return fmt.Errorf("verifying quote signature: %w", err)
+	}
+
+	attestation, err := tpm2.DecodeAttestationData(token.Quote)
+	if err != nil {
+		return fmt.Errorf("decoding quote: %w", err)
+	}
+	if attestation.Type != tpm2.TagAttestQuote || attestation.AttestedQuoteInfo == nil {
+		return fmt.Errorf("attestation is not a quote")
+	}
+	dataHash := sha256.Sum256(token.Data)
+	if !bytes.Equal(attestation.ExtraData, dataHash[:]) {
+		return fmt.Errorf("quote does not cover the token data")
+	}
+
+	// The quoted digest covers the PCR values, in the order of the selection.
+	selection := attestation.AttestedQuoteInfo.PCRSelection
+	if selection.Hash != tpm2.AlgSHA256 {
+		return fmt.Errorf("quote of PCR bank %v not supported", selection.Hash)
+	}
+	indexes := slices.Clone(selection.PCRs)
+	slices.Sort(indexes)
+	digest := sha256.New()
+	for _, index := range indexes {
+		value, found := token.PCRs[index]
+		if !found {
+			return fmt.Errorf("value of quoted PCR %d not provided", index)
+		}
+		digest.Write(value)
+	}
+	if !bytes.Equal(digest.Sum(nil), attestation.AttestedQuoteInfo.PCRDigest) {
+		return fmt.Errorf("PCR values do not match the quote")
+	}
+
+	for index, allowed := range v.pcrs {
+		if !slices.Contains(indexes, index) {
+			return fmt.Errorf("PCR %d was not quoted", index)
+		}
+		if !slices.ContainsFunc(allowed, func(b []byte) bool { return bytes.Equal(b, token.PCRs[index]) }) {
+			return fmt.Errorf("PCR %d has unexpected value %x", index, token.PCRs[index])
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,269 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tpmbootstrap
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"strings"
+	"testing"
+
+	"github.com/google/go-tpm/legacy/tpm2"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kops "k8s.io/kops/pkg/apis/kops/v1alpha2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// fakeHostClient serves Host objects.
+type fakeHostClient struct {
+	client.Client
+	hosts map[string]*kops.Host
+}
+
+func (c *fakeHostClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	host, found := c.hosts[key.String()]
+	if !found {
+		return apierrors.NewNotFound(schema.GroupResource{Group: "kops.k8s.io", Resource: "hosts"}, key.Name)
+	}
+	*obj.(*kops.Host) = *host
+	return nil
+}
+
+// quoteToken builds a token with a quote of the given PCRs over extraData, signed by signer.
+func quoteToken(t *testing.T, data []byte, extraData []byte, pcrs map[int][]byte, signer *rsa.PrivateKey) *AuthToken {
+	selection := tpm2.PCRSelection{Hash: tpm2.AlgSHA256}
+	digest := sha256.New()
+	for i := 0; i < len(pcrs); i++ {
+		selection.PCRs = append(selection.PCRs, i)
+		digest.Write(pcrs[i])
+	}
+	quote, err := tpm2.AttestationData{
+		Magic:     0xff544347,
+		Type:      tpm2.TagAttestQuote,
+		ExtraData: extraData,
+		AttestedQuoteInfo: &tpm2.QuoteInfo{
+			PCRSelection: selection,
+			PCRDigest:    digest.Sum(nil),
+		},
+	}.Encode()
+	if err != nil {
+		t.Fatalf("encoding quote: %v", err)
+	}
+	quoteHash := sha256.Sum256(quote)
+	rawSig, err := rsa.SignPKCS1v15(rand.Reader, signer, crypto.SHA256, quoteHash[:])
+	if err != nil {
+		t.Fatalf("signing quote: %v", err)
+	}
+	sig, err := tpm2.Signature{
+		Alg: tpm2.AlgRSASSA,
+		RSA: &tpm2.SignatureRSA{HashAlg: tpm2.AlgSHA256, Signature: rawSig},
+	}.Encode()
+	if err != nil {
+		t.Fatalf("encoding signature: %v", err)
+	}
+	return &AuthToken{
+		Data:           data,
+		Quote:          quote,
+		QuoteSignature: sig,
+		PCRs:           pcrs,
+	}
+}
+
+func TestVerifyQuote(t *testing.T) {
+	ak, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating AK: %v", err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+
+	data := []byte(`{"nodeName":"node1"}`)
+	dataHash := sha256.Sum256(data)
+	pcrs := func() map[int][]byte {
+		pcrs := make(map[int][]byte)
+		for i := 0; i < 8; i++ {
+			pcrs[i] = make([]byte, sha256.Size)
+		}
+		return pcrs
+	}
+	zeroPCR := strings.Repeat("00", sha256.Size)
+	otherPCR := strings.Repeat("ab", sha256.Size)
+
+	grid := []struct {
+		name        string
+		options     Options
+		token       func() *AuthToken
+		expectError string
+	}{
+		{
+			name:    "success",
+			options: Options{PCRs: []PCR{{Index: 7, Values: []string{otherPCR, zeroPCR}}}},
+			token: func() *AuthToken {
+				return quoteToken(t, data, dataHash[:], pcrs(), ak)
+			},
+		},
+		{
+			name: "bad signature",
+			token: func() *AuthToken {
+				return quoteToken(t, data, dataHash[:], pcrs(), otherKey)
+			},
+			expectError: "verifying quote signature",
+		},
+		{
+			name: "tampered quote",
+			token: func() *AuthToken {
+				token := quoteToken(t, data, dataHash[:], pcrs(), ak)
+				token.Quote[len(token.Quote)-1] ^= 0xff
+				return token
+			},
+			expectError: "verifying quote signature",
+		},
+		{
+			name: "wrong extra data",
+			token: func() *AuthToken {
+				otherHash := sha256.Sum256([]byte(`{"nodeName":"node2"}`))
+				return quoteToken(t, data, otherHash[:], pcrs(), ak)
+			},
+			expectError: "quote does not cover the token data",
+		},
+		{
+			name: "pcr values do not match digest",
+			token: func() *AuthToken {
+				token := quoteToken(t, data, dataHash[:], pcrs(), ak)
+				token.PCRs[7] = []byte(strings.Repeat("x", sha256.Size))
+				return token
+			},
+			expectError: "PCR values do not match the quote",
+		},
+		{
+			name: "pcr value missing",
+			token: func() *AuthToken {
+				token := quoteToken(t, data, dataHash[:], pcrs(), ak)
+				delete(token.PCRs, 3)
+				return token
+			},
+			expectError: "value of quoted PCR 3 not provided",
+		},
+		{
+			name:    "pcr mismatch with policy",
+			options: Options{PCRs: []PCR{{Index: 7, Values: []string{otherPCR}}}},
+			token: func() *AuthToken {
+				return quoteToken(t, data, dataHash[:], pcrs(), ak)
+			},
+			expectError: "PCR 7 has unexpected value",
+		},
+		{
+			name:    "pcr not quoted",
+			options: Options{PCRs: []PCR{{Index: 16, Values: []string{zeroPCR}}}},
+			token: func() *AuthToken {
+				return quoteToken(t, data, dataHash[:], pcrs(), ak)
+			},
+			expectError: "PCR 16 was not quoted",
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			verifier, err := NewVerifier(&g.options, &fakeHostClient{}, []byte("credential-key"))
+			if err != nil {
+				t.Fatalf("building verifier: %v", err)
+			}
+			err = verifier.verifyQuote(g.token(), &ak.PublicKey)
+			if g.expectError != "" {
+				if err == nil || !strings.Contains(err.Error(), g.expectError) {
+					t.Fatalf("expected error containing %q, got %v", g.expectError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("verifying quote: %v", err)
+			}
+		})
+	}
+}
+
+func TestGetHost(t *testing.T) {
+	ekHash := strings.Repeat("ab", sha256.Size)
+
+	grid := []struct {
+		name        string
+		host        *kops.Host
+		ekHash      string
+		expectError string
+	}{
+		{
+			name:   "success",
+			host:   &kops.Host{Spec: kops.HostSpec{InstanceGroup: "nodes", TPM: &kops.HostTPMSpec{EKPublicKeyHash: ekHash}}},
+			ekHash: strings.ToUpper(ekHash),
+		},
+		{
+			name:        "ek hash mismatch",
+			host:        &kops.Host{Spec: kops.HostSpec{InstanceGroup: "nodes", TPM: &kops.HostTPMSpec{EKPublicKeyHash: ekHash}}},
+			ekHash:      strings.Repeat("cd", sha256.Size),
+			expectError: "is not registered for host",
+		},
+		{
+			name:        "no ek hash",
+			host:        &kops.Host{Spec: kops.HostSpec{InstanceGroup: "nodes"}},
+			ekHash:      ekHash,
+			expectError: "did not have spec.tpm.ekPublicKeyHash",
+		},
+		{
+			name:        "no instance group",
+			host:        &kops.Host{Spec: kops.HostSpec{TPM: &kops.HostTPMSpec{EKPublicKeyHash: ekHash}}},
+			ekHash:      ekHash,
+			expectError: "did not have spec.instanceGroup",
+		},
+		{
+			name:        "host not found",
+			ekHash:      ekHash,
+			expectError: "host not found",
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			kube := &fakeHostClient{hosts: map[string]*kops.Host{}}
+			if g.host != nil {
+				kube.hosts["kops-system/node1"] = g.host
+			}
+			verifier, err := NewVerifier(&Options{}, kube, []byte("credential-key"))
+			if err != nil {
+				t.Fatalf("building verifier: %v", err)
+			}
+			result, err := verifier.getHost(context.Background(), "node1", g.ekHash)
+			if g.expectError != "" {
+				if err == nil || !strings.Contains(err.Error(), g.expectError) {
+					t.Fatalf("expected error containing %q, got %v", g.expectError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("getting host: %v", err)
+			}
+			if result.NodeName != "node1" || result.InstanceGroupName != "nodes" {
+				t.Errorf("unexpected result %+v", result)
+			}
+		})
+	}
+}
@@ -0,0 +1,242 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package carotation records the progress of a guided rotation of the cluster's keypairs,
+// so that "kops rotate ca" can be resumed after it stops or fails.
+package carotation
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"k8s.io/kops/util/pkg/vfs"
+	"sigs.k8s.io/yaml"
+)
+
+// Step is a step of a rotation.
+type Step string
+
+const (
+	// StepCreateKeypairs adds a new, secondary keypair to each keyset.
+	StepCreateKeypairs Step = "create-keypairs"
+	// StepStageUpdate applies the new trusted keypairs to the cloud resources.
+	StepStageUpdate Step = "stage-update"
+	// StepStageRollingUpdate replaces the instances, so that they trust the new keypairs.
+	StepStageRollingUpdate Step = "stage-rolling-update"
+	// StepStageValidate validates the cluster.
+	StepStageValidate Step = "stage-validate"
+	// StepDistributeCA waits for the new CA certificate to be distributed to the clients of the API.
+	StepDistributeCA Step = "distribute-ca"
+	// StepPromoteKeypairs promotes the new keypairs to primary.
+	StepPromoteKeypairs Step = "promote-keypairs"
+	// StepPromoteUpdate applies the new primary keypairs to the cloud resources.
+	StepPromoteUpdate Step = "promote-update"
+	// StepPromoteRollingUpdate replaces the instances, so that they use the new keypairs.
+	StepPromoteRollingUpdate Step = "promote-rolling-update"
+	// StepPromoteValidate validates the cluster.
+	StepPromoteValidate Step = "promote-validate"
+	// StepDistributeCredentials waits for new admin credentials to be distributed.
+	StepDistributeCredentials Step = "distribute-credentials"
+	// StepDistrustKeypairs distrusts the previous keypairs.
+	StepDistrustKeypairs Step = "distrust-keypairs"
+	// StepDistrustUpdate applies the distrust to the cloud resources.
+	StepDistrustUpdate Step = "distrust-update"
+	// StepDistrustRollingUpdate replaces the instances, so that they no longer trust the previous keypairs.
+	StepDistrustRollingUpdate Step = "distrust-rolling-update"
+	// StepDistrustValidate validates the cluster.
+	StepDistrustValidate Step = "distrust-validate"
+	// StepDistributeFinalCA reminds the user to remove the previous CA certificate from the clients of the API.
+	StepDistributeFinalCA Step = "distribute-final-ca"
+)
+
+// KeysetKubernetesCA is the keyset of the CA whose certificate is distributed to the clients of the API.
+const KeysetKubernetesCA = "kubernetes-ca"
+
+// KeysetServiceAccount is the keyset of the service account token signing keys.
+const KeysetServiceAccount = "service-account"
+
+// ServiceAccountTokenRefreshPeriod is how long we wait after promoting a new service account signing key
+// before distrusting the previous one, so that the kubelet has refreshed the projected tokens of the pods.
+const ServiceAccountTokenRefreshPeriod = time.Hour
+
+var steps = []struct {
+	step        Step
+	description string
+	// pause is true if the rotation stops after the step, until the user has acted
+	pause bool
+	// clientFacing is true if the step is only needed when the CA of the API is rotated
+	clientFacing bool
+}{
+	{step: StepCreateKeypairs, description: "Create a new keypair in each keyset"},
+	{step: StepStageUpdate, description: "Update the cluster to trust the new keypairs"},
+	{step: StepStageRollingUpdate, description: "Rolling-update the cluster to trust the new keypairs"},
+	{step: StepStageValidate, description: "Validate the cluster"},
+	{step: StepDistributeCA, description: "Distribute the new CA certificate to clients of the Kubernetes API", pause: true, clientFacing: true},
+	{step: StepPromoteKeypairs, description: "Promote the new keypairs to primary"},
+	{step: StepPromoteUpdate, description: "Update the cluster to use the new keypairs"},
+	{step: StepPromoteRollingUpdate, description: "Rolling-update the cluster to use the new keypairs"},
+	{step: StepPromoteValidate, description: "Validate the cluster"},
+	{step: StepDistributeCredentials, description: "Distribute new admin credentials to clients of the Kubernetes API", pause: true, clientFacing: true},
+	{step: StepDistrustKeypairs, description: "Distrust the previous keypairs"},
+	{step: StepDistrustUpdate, description: "Update the cluster to no longer trust the previous keypairs"},
+	{step: StepDistrustRollingUpdate, description: "Rolling-update the cluster to no longer trust the previous keypairs"},
+	{step: StepDistrustValidate, description: "Validate the cluster"},
+	{step: StepDistributeFinalCA, description: "Remove the previous CA certificate from clients of the Kubernetes API", pause: true, clientFacing: true},
+}
+
+// Description returns a description of the step.
+func Description(step Step) string {
+	for _, s := range steps {
+		if s.step == step {
+			return s.description
+		}
+	}
+	return string(step)
+}
+
+// IsPause returns true if the rotation stops after the step, until the user has acted.
+func IsPause(step Step) bool {
+	for _, s := range steps {
+		if s.step == step {
+			return s.pause
+		}
+	}
+	return false
+}
+
+// Steps returns the steps of a rotation of the keysets, in order.
+func Steps(keysets []string) []Step {
+	clientFacing := slices.Contains(keysets, KeysetKubernetesCA)
+
+	var result []Step
+	for _, s := range steps {
+		if s.clientFacing && !clientFacing {
+			continue
+		}
+		result = append(result, s.step)
+	}
+	return result
+}
+
+// State is the progress of a rotation, as recorded in the state store.
+type State struct {
+	// Keysets are the names of the keysets being rotated.
+	Keysets []string `json:"keysets"`
+	// StartedAt is when the rotation started.
+	StartedAt time.Time `json:"startedAt"`
+	// NewKeypairs holds the ID of the keypair created in each keyset.
+	NewKeypairs map[string]string `json:"newKeypairs,omitempty"`
+	// Completed lists the completed steps, in order.
+	Completed []CompletedStep `json:"completed,omitempty"`
+}
+
+// CompletedStep records the completion of a step.
+type CompletedStep struct {
+	Step        Step      `json:"step"`
+	CompletedAt time.Time `json:"completedAt"`
+}
+
+// New returns the state of a rotation of the keysets that has not started.
+func New(keysets []string, now time.Time) *State {
+	keysets = slices.Clone(keysets)
+	slices.Sort(keysets)
+	return &State{
+		Keysets:     keysets,
+		StartedAt:   now.UTC(),
+		NewKeypairs: make(map[string]string),
+	}
+}
+
+// Remaining returns the steps that have not been completed, in order.
+func (s *State) Remaining() []Step {
+	var remaining []Step
+	for _, step := range Steps(s.Keysets) {
+		if s.CompletedAt(step) == nil {
+			remaining = append(remaining, step)
+		}
+	}
+	return remaining
+}
+
+// CompletedAt returns when the step was completed, or nil if it has not been completed.
+func (s *State) CompletedAt(step Step) *time.Time {
+	for i := range s.Completed {
+		if s.Completed[i].Step == step {
+			return &s.Completed[i].CompletedAt
+		}
+	}
+	return nil
+}
+
+// MarkCompleted records that the step has been completed.
+func (s *State) MarkCompleted(step Step, now time.Time) {
+	if s.CompletedAt(step) != nil {
+		return
+	}
+	s.Completed = append(s.Completed, CompletedStep{Step: step, CompletedAt: now.UTC()})
+}
+
+// DistrustDelay returns how long we must still wait before distrusting the previous keypairs.
+// Pods keep using service account tokens signed with the previous key until the kubelet refreshes them.
+func (s *State) DistrustDelay(now time.Time) time.Duration {
+	if !slices.Contains(s.Keysets, KeysetServiceAccount) {
+		return 0
+	}
+	promoted := s.CompletedAt(StepPromoteValidate)
+	if promoted == nil {
+		return ServiceAccountTokenRefreshPeriod
+	}
+	if delay := promoted.Add(ServiceAccountTokenRefreshPeriod).Sub(now); delay > 0 {
+		return delay
+	}
+	return 0
+}
+
+// Load reads the state of the rotation from the path; it returns nil if no rotation is in progress.
+func Load(ctx context.Context, p vfs.Path) (*State, error) {
+	data, err := p.ReadFile(ctx)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading rotation state %q: %w", p, err)
+	}
+
+	state := &State{}
+	if err := yaml.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("error parsing rotation state %q: %w", p, err)
+	}
+	if state.NewKeypairs == nil {
+		state.NewKeypairs = make(map[string]string)
+	}
+	return state, nil
+}
+
+// Save writes the state of the rotation to the path.
+func Save(ctx context.Context, p vfs.Path, acl vfs.ACL, state *State) error {
+	data, err := yaml.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("error serializing rotation state: %w", err)
+	}
+	if err := p.WriteFile(ctx, bytes.NewReader(data), acl); err != nil {
+		return fmt.Errorf("error writing rotation state %q: %w", p, err)
+	}
+	return nil
+}
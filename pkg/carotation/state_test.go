@@ -0,0 +1,126 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package carotation
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/kops/pkg/testutils/testcontext"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func TestSteps(t *testing.T) {
+	grid := []struct {
+		keysets  []string
+		expected int
+		pauses   []Step
+	}{
+		{
+			keysets:  []string{"etcd-manager-ca-main", "service-account"},
+			expected: 12,
+		},
+		{
+			keysets:  []string{"kubernetes-ca"},
+			expected: 15,
+			pauses:   []Step{StepDistributeCA, StepDistributeCredentials, StepDistributeFinalCA},
+		},
+	}
+	for _, g := range grid {
+		steps := Steps(g.keysets)
+		if len(steps) != g.expected {
+			t.Errorf("keysets %v: expected %d steps, got %v", g.keysets, g.expected, steps)
+		}
+		var pauses []Step
+		for _, step := range steps {
+			if IsPause(step) {
+				pauses = append(pauses, step)
+			}
+		}
+		if !reflect.DeepEqual(pauses, g.pauses) {
+			t.Errorf("keysets %v: expected pauses %v, got %v", g.keysets, g.pauses, pauses)
+		}
+	}
+}
+
+func TestRemaining(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := New([]string{"service-account", "etcd-manager-ca-main"}, now)
+
+	if remaining := s.Remaining(); len(remaining) != 12 || remaining[0] != StepCreateKeypairs {
+		t.Fatalf("unexpected remaining steps %v", remaining)
+	}
+
+	s.MarkCompleted(StepCreateKeypairs, now)
+	s.MarkCompleted(StepStageUpdate, now)
+	s.MarkCompleted(StepStageUpdate, now.Add(time.Hour))
+	if remaining := s.Remaining(); len(remaining) != 10 || remaining[0] != StepStageRollingUpdate {
+		t.Fatalf("unexpected remaining steps %v", remaining)
+	}
+	if completedAt := s.CompletedAt(StepStageUpdate); completedAt == nil || !completedAt.Equal(now) {
+		t.Errorf("unexpected completion time %v", completedAt)
+	}
+}
+
+func TestDistrustDelay(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	s := New([]string{"etcd-manager-ca-main"}, now)
+	if delay := s.DistrustDelay(now); delay != 0 {
+		t.Errorf("expected no delay without the service-account keyset, got %v", delay)
+	}
+
+	s = New([]string{"service-account"}, now)
+	s.MarkCompleted(StepPromoteValidate, now)
+	if delay := s.DistrustDelay(now.Add(20 * time.Minute)); delay != 40*time.Minute {
+		t.Errorf("expected delay of 40m, got %v", delay)
+	}
+	if delay := s.DistrustDelay(now.Add(2 * time.Hour)); delay != 0 {
+		t.Errorf("expected no delay after the refresh period, got %v", delay)
+	}
+}
+
+func TestLoadSave(t *testing.T) {
+	ctx := testcontext.ForTest(t)
+
+	p := vfs.NewMemFSPath(vfs.NewMemFSContext(), "cluster/ca-rotation.yaml")
+
+	state, err := Load(ctx, p)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if state != nil {
+		t.Fatalf("expected no state, got %v", state)
+	}
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	state = New([]string{"kubernetes-ca"}, now)
+	state.NewKeypairs["kubernetes-ca"] = "7300000000000000000"
+	state.MarkCompleted(StepCreateKeypairs, now)
+	if err := Save(ctx, p, nil, state); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	loaded, err := Load(ctx, p)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(loaded, state) {
+		t.Errorf("state did not round-trip: got %v, expected %v", loaded, state)
+	}
+}
@@ -39,6 +39,8 @@ type InstanceGroupsGetter interface {
 type InstanceGroupInterface interface {
 	Create(ctx context.Context, instanceGroup *kops.InstanceGroup, opts v1.CreateOptions) (*kops.InstanceGroup, error)
 	Update(ctx context.Context, instanceGroup *kops.InstanceGroup, opts v1.UpdateOptions) (*kops.InstanceGroup, error)
+	// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+	UpdateStatus(ctx context.Context, instanceGroup *kops.InstanceGroup, opts v1.UpdateOptions) (*kops.InstanceGroup, error)
 	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
 	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
 	Get(ctx context.Context, name string, opts v1.GetOptions) (*kops.InstanceGroup, error)
@@ -39,6 +39,8 @@ type InstanceGroupsGetter interface {
 type InstanceGroupInterface interface {
 	Create(ctx context.Context, instanceGroup *kopsv1alpha2.InstanceGroup, opts v1.CreateOptions) (*kopsv1alpha2.InstanceGroup, error)
 	Update(ctx context.Context, instanceGroup *kopsv1alpha2.InstanceGroup, opts v1.UpdateOptions) (*kopsv1alpha2.InstanceGroup, error)
+	// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+	UpdateStatus(ctx context.Context, instanceGroup *kopsv1alpha2.InstanceGroup, opts v1.UpdateOptions) (*kopsv1alpha2.InstanceGroup, error)
 	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
 	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
 	Get(ctx context.Context, name string, opts v1.GetOptions) (*kopsv1alpha2.InstanceGroup, error)
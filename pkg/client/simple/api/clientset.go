@@ -24,6 +24,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/apis/kops/registry"
@@ -41,6 +42,10 @@ type RESTClientset struct {
 	vfsContext *vfs.VFSContext
 	BaseURL    *url.URL
 	KopsClient kopsinternalversion.KopsInterface
+
+	// namespace holds the objects of the cluster, if the state store URL names one.
+	// Otherwise every cluster has its own namespace, named after the cluster.
+	namespace string
 }
 
 func NewRESTClientset(vfsContext *vfs.VFSContext, baseURL *url.URL, kopsClient kopsinternalversion.KopsInterface) *RESTClientset {
@@ -48,7 +53,31 @@ func NewRESTClientset(vfsContext *vfs.VFSContext, baseURL *url.URL, kopsClient k
 		vfsContext: vfsContext,
 		BaseURL:    baseURL,
 		KopsClient: kopsClient,
+		namespace:  strings.Trim(baseURL.Path, "/"),
+	}
+}
+
+// ParseStateStoreURL parses a kubernetes-API state store URL of the form k8s://<context>/<namespace>.
+// The context defaults to the current kubeconfig context, the namespace to one namespace per cluster.
+func ParseStateStoreURL(registryPath string) (*url.URL, error) {
+	u, err := url.Parse(registryPath)
+	if err != nil || u.Scheme != "k8s" {
+		return nil, fmt.Errorf("invalid kubernetes state store %q", registryPath)
+	}
+	namespace := strings.Trim(u.Path, "/")
+	if namespace != "" {
+		if errs := k8svalidation.IsDNS1123Label(namespace); len(errs) != 0 {
+			return nil, fmt.Errorf("invalid namespace %q in kubernetes state store %q: %s", namespace, registryPath, strings.Join(errs, ", "))
+		}
+	}
+	baseURL := &url.URL{
+		Scheme: "k8s",
+		Host:   u.Host,
+	}
+	if namespace != "" {
+		baseURL.Path = "/" + namespace
 	}
+	return baseURL, nil
 }
 
 func (c *RESTClientset) VFSContext() *vfs.VFSContext {
@@ -57,7 +86,7 @@ func (c *RESTClientset) VFSContext() *vfs.VFSContext {
 
 // GetCluster implements the GetCluster method of Clientset for a kubernetes-API state store
 func (c *RESTClientset) GetCluster(ctx context.Context, name string) (*kops.Cluster, error) {
-	namespace := restNamespaceForClusterName(name)
+	namespace := c.namespaceForClusterName(name)
 	return c.KopsClient.Clusters(namespace).Get(ctx, name, metav1.GetOptions{})
 }
 
@@ -68,9 +97,52 @@ func (c *RESTClientset) AddonsFor(cluster *kops.Cluster) simple.AddonsClient {
 	return nil
 }
 
+// HistoryFor fetches the ClusterHistoryClient for the cluster
+func (c *RESTClientset) HistoryFor(cluster *kops.Cluster) simple.ClusterHistoryClient {
+	// The API server keeps no history of the objects
+	return unsupportedHistory{}
+}
+
+// unsupportedHistory implements simple.ClusterHistoryClient for state stores that keep no history
+type unsupportedHistory struct{}
+
+var _ simple.ClusterHistoryClient = unsupportedHistory{}
+
+// errHistoryUnsupported is returned by every method of unsupportedHistory
+var errHistoryUnsupported = fmt.Errorf("cluster history is not supported for this state store")
+
+func (unsupportedHistory) List(ctx context.Context) ([]*simple.ClusterRevision, error) {
+	return nil, errHistoryUnsupported
+}
+
+func (unsupportedHistory) Get(ctx context.Context, revision int) (*simple.ClusterRevision, error) {
+	return nil, errHistoryUnsupported
+}
+
+func (unsupportedHistory) Rollback(ctx context.Context, revision int) (*simple.ClusterRevision, error) {
+	return nil, errHistoryUnsupported
+}
+
+// InterruptedRollback returns 0, as there cannot be a rollback without a history
+func (unsupportedHistory) InterruptedRollback(ctx context.Context) (int, error) {
+	return 0, nil
+}
+
 // CreateCluster implements the CreateCluster method of Clientset for a kubernetes-API state store
 func (c *RESTClientset) CreateCluster(ctx context.Context, cluster *kops.Cluster) (*kops.Cluster, error) {
-	namespace := restNamespaceForClusterName(cluster.Name)
+	namespace := c.namespaceForClusterName(cluster.Name)
+	if c.namespace != "" {
+		// Keysets and secrets are not named after the cluster, so a namespace holds a single cluster
+		clusters, err := c.KopsClient.Clusters(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("error listing clusters in namespace %q: %w", namespace, err)
+		}
+		for i := range clusters.Items {
+			if existing := clusters.Items[i].Name; existing != cluster.Name {
+				return nil, fmt.Errorf("namespace %q already holds cluster %q; use a namespace for each cluster", namespace, existing)
+			}
+		}
+	}
 	return c.KopsClient.Clusters(namespace).Create(ctx, cluster, metav1.CreateOptions{})
 }
 
@@ -85,7 +157,7 @@ func (c *RESTClientset) UpdateCluster(ctx context.Context, cluster *kops.Cluster
 		return nil, err
 	}
 
-	namespace := restNamespaceForClusterName(cluster.Name)
+	namespace := c.namespaceForClusterName(cluster.Name)
 	return c.KopsClient.Clusters(namespace).Update(ctx, cluster, metav1.UpdateOptions{})
 }
 
@@ -94,34 +166,38 @@ func (c *RESTClientset) ConfigBaseFor(cluster *kops.Cluster) (vfs.Path, error) {
 	if cluster.Spec.ConfigStore.Base != "" {
 		return c.VFSContext().BuildVfsPath(cluster.Spec.ConfigStore.Base)
 	}
-	// URL for clusters looks like https://<server>/apis/kops/v1alpha2/namespaces/<cluster>/clusters/<cluster>
-	// We probably want to add a subresource for full resources
-	return c.VFSContext().BuildVfsPath(c.BaseURL.String())
+	// Nodes cannot read from the kubernetes API of the management cluster, so the configuration
+	// they need is mirrored to a separate cluster-readable location
+	return nil, fmt.Errorf("a kubernetes state store requires a cluster-readable location for the configuration of the nodes of cluster %q, set spec.configStore.base or specify it with --config-base", cluster.Name)
 }
 
 // ListClusters implements the ListClusters method of Clientset for a kubernetes-API state store
 func (c *RESTClientset) ListClusters(ctx context.Context, options metav1.ListOptions) (*kops.ClusterList, error) {
-	return c.KopsClient.Clusters(metav1.NamespaceAll).List(ctx, options)
+	namespace := c.namespace
+	if namespace == "" {
+		namespace = metav1.NamespaceAll
+	}
+	return c.KopsClient.Clusters(namespace).List(ctx, options)
 }
 
 // InstanceGroupsFor implements the InstanceGroupsFor method of Clientset for a kubernetes-API state store
 func (c *RESTClientset) InstanceGroupsFor(cluster *kops.Cluster) kopsinternalversion.InstanceGroupInterface {
-	namespace := restNamespaceForClusterName(cluster.Name)
+	namespace := c.namespaceForClusterName(cluster.Name)
 	return c.KopsClient.InstanceGroups(namespace)
 }
 
 func (c *RESTClientset) SecretStore(cluster *kops.Cluster) (fi.SecretStore, error) {
-	namespace := restNamespaceForClusterName(cluster.Name)
+	namespace := c.namespaceForClusterName(cluster.Name)
 	return secrets.NewClientsetSecretStore(cluster, c.KopsClient, namespace), nil
 }
 
 func (c *RESTClientset) KeyStore(cluster *kops.Cluster) (fi.CAStore, error) {
-	namespace := restNamespaceForClusterName(cluster.Name)
+	namespace := c.namespaceForClusterName(cluster.Name)
 	return fi.NewClientsetCAStore(cluster, c.KopsClient, namespace), nil
 }
 
 func (c *RESTClientset) SSHCredentialStore(cluster *kops.Cluster) (fi.SSHCredentialStore, error) {
-	namespace := restNamespaceForClusterName(cluster.Name)
+	namespace := c.namespaceForClusterName(cluster.Name)
 	return fi.NewClientsetSSHCredentialStore(cluster, c.KopsClient, namespace), nil
 }
 
@@ -137,7 +213,7 @@ func (c *RESTClientset) DeleteCluster(ctx context.Context, cluster *kops.Cluster
 	}
 
 	name := cluster.Name
-	namespace := restNamespaceForClusterName(name)
+	namespace := c.namespaceForClusterName(name)
 
 	{
 		keysets, err := c.KopsClient.Keysets(namespace).List(ctx, metav1.ListOptions{})
@@ -192,6 +268,13 @@ func (c *RESTClientset) DeleteCluster(ctx context.Context, cluster *kops.Cluster
 	return nil
 }
 
+func (c *RESTClientset) namespaceForClusterName(clusterName string) string {
+	if c.namespace != "" {
+		return c.namespace
+	}
+	return restNamespaceForClusterName(clusterName)
+}
+
 func restNamespaceForClusterName(clusterName string) string {
 	// We are not allowed dots, so we map them to dashes
 	// This can conflict, but this will simply be a limitation that we pass on to the user
@@ -0,0 +1,131 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/client/clientset_generated/clientset/fake"
+	"k8s.io/kops/pkg/testutils"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func TestParseStateStoreURL(t *testing.T) {
+	grid := []struct {
+		Input     string
+		Context   string
+		Namespace string
+		Error     bool
+	}{
+		{Input: "k8s://"},
+		{Input: "k8s://management", Context: "management"},
+		{Input: "k8s://management/", Context: "management"},
+		{Input: "k8s://management/kops-prod", Context: "management", Namespace: "kops-prod"},
+		{Input: "k8s:///kops-prod", Namespace: "kops-prod"},
+		{Input: "k8s://management/kops/prod", Error: true},
+		{Input: "k8s://management/Kops", Error: true},
+		{Input: "s3://bucket", Error: true},
+	}
+	for _, g := range grid {
+		t.Run(g.Input, func(t *testing.T) {
+			u, err := ParseStateStoreURL(g.Input)
+			if g.Error {
+				if err == nil {
+					t.Fatalf("expected error parsing %q", g.Input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error parsing %q: %v", g.Input, err)
+			}
+			if u.Host != g.Context {
+				t.Errorf("expected context %q, got %q", g.Context, u.Host)
+			}
+			clientset := NewRESTClientset(vfs.Context, u, nil)
+			if clientset.namespace != g.Namespace {
+				t.Errorf("expected namespace %q, got %q", g.Namespace, clientset.namespace)
+			}
+		})
+	}
+}
+
+func TestRESTClientsetNamespace(t *testing.T) {
+	ctx := context.TODO()
+	vfs.Context.ResetMemfsContext(true)
+
+	grid := []struct {
+		StateStore string
+		Namespace  string
+	}{
+		{StateStore: "k8s://management", Namespace: "a-example-com"},
+		{StateStore: "k8s://management/kops-prod", Namespace: "kops-prod"},
+	}
+	for _, g := range grid {
+		t.Run(g.StateStore, func(t *testing.T) {
+			u, err := ParseStateStoreURL(g.StateStore)
+			if err != nil {
+				t.Fatalf("error parsing %q: %v", g.StateStore, err)
+			}
+			kopsClient := fake.NewSimpleClientset().Kops()
+			clientset := NewRESTClientset(vfs.Context, u, kopsClient)
+
+			cluster := testutils.BuildMinimalCluster("a.example.com")
+			if _, err := clientset.CreateCluster(ctx, cluster); err != nil {
+				t.Fatalf("error creating cluster: %v", err)
+			}
+			if _, err := kopsClient.Clusters(g.Namespace).Get(ctx, cluster.Name, metav1.GetOptions{}); err != nil {
+				t.Errorf("expected cluster in namespace %q: %v", g.Namespace, err)
+			}
+
+			clusters, err := clientset.ListClusters(ctx, metav1.ListOptions{})
+			if err != nil {
+				t.Fatalf("error listing clusters: %v", err)
+			}
+			if len(clusters.Items) != 1 {
+				t.Errorf("expected 1 cluster, got %d", len(clusters.Items))
+			}
+
+			cluster.Spec.ConfigStore.Base = ""
+			if _, err := clientset.ConfigBaseFor(cluster); err == nil {
+				t.Errorf("expected error building config base without spec.configStore.base")
+			}
+			cluster.Spec.ConfigStore.Base = "memfs://config/a.example.com"
+			if _, err := clientset.ConfigBaseFor(cluster); err != nil {
+				t.Errorf("unexpected error building config base: %v", err)
+			}
+		})
+	}
+}
+
+func TestRESTClientsetNamespaceHoldsOneCluster(t *testing.T) {
+	ctx := context.TODO()
+
+	u, err := ParseStateStoreURL("k8s://management/kops-prod")
+	if err != nil {
+		t.Fatalf("error parsing state store: %v", err)
+	}
+	clientset := NewRESTClientset(vfs.Context, u, fake.NewSimpleClientset().Kops())
+
+	if _, err := clientset.CreateCluster(ctx, testutils.BuildMinimalCluster("a.example.com")); err != nil {
+		t.Fatalf("error creating cluster: %v", err)
+	}
+	if _, err := clientset.CreateCluster(ctx, testutils.BuildMinimalCluster("b.example.com")); err == nil {
+		t.Errorf("expected error creating a second cluster in the namespace")
+	}
+}
@@ -18,6 +18,7 @@ package simple
 
 import (
 	"context"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/kops/pkg/apis/kops"
@@ -52,6 +53,9 @@ type Clientset interface {
 	// AddonsFor returns the client for addon objects for a particular Cluster
 	AddonsFor(cluster *kops.Cluster) AddonsClient
 
+	// HistoryFor returns the client for the revisions of the specs of a particular Cluster
+	HistoryFor(cluster *kops.Cluster) ClusterHistoryClient
+
 	// SecretStore builds the secret store for the specified cluster
 	SecretStore(cluster *kops.Cluster) (fi.SecretStore, error)
 
@@ -74,3 +78,35 @@ type AddonsClient interface {
 	// List returns all the addon objects
 	List(ctx context.Context) (kubemanifest.ObjectList, error)
 }
+
+// ClusterRevision is a recorded revision of the cluster and instance group specs
+type ClusterRevision struct {
+	// Revision is the number of the revision, starting at 1
+	Revision int
+	// Timestamp is when the revision was recorded
+	Timestamp time.Time
+	// Author is the user and host that made the change
+	Author string
+	// KopsVersion is the version of kOps that made the change
+	KopsVersion string
+
+	// Cluster is the cluster at this revision
+	Cluster *kops.Cluster
+	// InstanceGroups are the instance groups at this revision
+	InstanceGroups []*kops.InstanceGroup
+}
+
+// ClusterHistoryClient reads and restores the recorded revisions of a cluster
+type ClusterHistoryClient interface {
+	// List returns all the recorded revisions, oldest first
+	List(ctx context.Context) ([]*ClusterRevision, error)
+
+	// Get returns the specified revision
+	Get(ctx context.Context, revision int) (*ClusterRevision, error)
+
+	// Rollback restores the cluster and instance groups of the specified revision, and returns the new revision
+	Rollback(ctx context.Context, revision int) (*ClusterRevision, error)
+
+	// InterruptedRollback returns the revision of a rollback that was interrupted before it completed, or 0 if there is none
+	InterruptedRollback(ctx context.Context) (int, error)
+}
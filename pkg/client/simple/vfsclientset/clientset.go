@@ -91,6 +91,11 @@ func (c *VFSClientset) AddonsFor(cluster *kops.Cluster) simple.AddonsClient {
 	return newAddonsVFS(c, cluster)
 }
 
+// HistoryFor implements the HistoryFor method of simple.Clientset for a VFS-backed state store
+func (c *VFSClientset) HistoryFor(cluster *kops.Cluster) simple.ClusterHistoryClient {
+	return newClusterHistoryVFS(c, cluster)
+}
+
 func (c *VFSClientset) SecretStore(cluster *kops.Cluster) (fi.SecretStore, error) {
 	if cluster.Spec.ConfigStore.Secrets == "" {
 		configBase, err := registry.ConfigBase(c.VFSContext(), cluster)
@@ -154,7 +159,7 @@ func DeleteAllClusterState(ctx context.Context, basePath vfs.Path) error {
 		}
 
 		// "cluster.spec" was written by kOps 1.21 and earlier.
-		if relativePath == "config" || relativePath == "cluster.spec" || relativePath == "cluster-completed.spec" || relativePath == registry.PathKopsVersionUpdated {
+		if relativePath == "config" || relativePath == "cluster.spec" || relativePath == "cluster-completed.spec" || relativePath == registry.PathKopsVersionUpdated || relativePath == registry.PathCARotation || relativePath == registry.PathLock {
 			continue
 		}
 		if strings.HasPrefix(relativePath, "addons/") {
@@ -163,6 +168,18 @@ func DeleteAllClusterState(ctx context.Context, basePath vfs.Path) error {
 		if strings.HasPrefix(relativePath, "clusteraddons/") {
 			continue
 		}
+		if strings.HasPrefix(relativePath, "history/") {
+			continue
+		}
+		if strings.HasPrefix(relativePath, "audit/") {
+			continue
+		}
+		if strings.HasPrefix(relativePath, "node-identity/") {
+			continue
+		}
+		if strings.HasPrefix(relativePath, "join-tokens/") {
+			continue
+		}
 		if strings.HasPrefix(relativePath, "pki/") {
 			continue
 		}
@@ -118,6 +118,7 @@ func (r *ClusterVFS) Create(c *api.Cluster) (*api.Cluster, error) {
 		}
 		return nil, fmt.Errorf("error writing Cluster %q: %v", c.ObjectMeta.Name, err)
 	}
+	recordClusterRevision(ctx, r.basePath.Join(clusterName), c)
 
 	return c, nil
 }
@@ -147,12 +148,18 @@ func (r *ClusterVFS) Update(c *api.Cluster, status *api.ClusterStatus) (*api.Clu
 		c.SetGeneration(old.GetGeneration() + 1)
 	}
 
+	// Fail with a conflict if the cluster changes after we read it, unless the caller read it earlier
+	if c.ResourceVersion == "" {
+		c.ResourceVersion = old.ResourceVersion
+	}
+
 	if err := r.writeConfig(ctx, c, r.basePath.Join(clusterName, registry.PathCluster), c, vfs.WriteOptionOnlyIfExists); err != nil {
-		if os.IsNotExist(err) {
+		if os.IsNotExist(err) || errors.IsConflict(err) {
 			return nil, err
 		}
 		return nil, fmt.Errorf("error writing Cluster: %v", err)
 	}
+	recordClusterRevision(ctx, r.basePath.Join(clusterName), c)
 
 	return c, nil
 }
@@ -0,0 +1,103 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfsclientset
+
+import (
+	"context"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/testutils"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func TestUpdateConflict(t *testing.T) {
+	ctx := context.TODO()
+	vfs.Context.ResetMemfsContext(true)
+	basePath, err := vfs.Context.BuildVfsPath("memfs://tests")
+	if err != nil {
+		t.Fatalf("error building vfs path: %v", err)
+	}
+	clientset := NewVFSClientset(vfs.Context, basePath)
+
+	cluster := testutils.BuildMinimalCluster("conflict.example.com")
+	cluster.Spec.ConfigStore.Base = basePath.Join(cluster.Name).Path()
+	if _, err := clientset.CreateCluster(ctx, cluster); err != nil {
+		t.Fatalf("error creating cluster: %v", err)
+	}
+	nodes := testutils.BuildMinimalNodeInstanceGroup("nodes", "subnet-us-test-1a")
+	igs := clientset.InstanceGroupsFor(cluster)
+	if _, err := igs.Create(ctx, &nodes, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error creating instance group: %v", err)
+	}
+
+	// Two clients read the cluster, then both update it
+	first, err := clientset.GetCluster(ctx, cluster.Name)
+	if err != nil {
+		t.Fatalf("error getting cluster: %v", err)
+	}
+	second, err := clientset.GetCluster(ctx, cluster.Name)
+	if err != nil {
+		t.Fatalf("error getting cluster: %v", err)
+	}
+	if first.ResourceVersion == "" {
+		t.Fatalf("expected cluster to have a resource version")
+	}
+
+	first.Spec.KubernetesVersion = "1.35.0"
+	first, err = clientset.UpdateCluster(ctx, first, nil)
+	if err != nil {
+		t.Fatalf("error updating cluster: %v", err)
+	}
+	second.Spec.KubernetesVersion = "1.34.0"
+	if _, err := clientset.UpdateCluster(ctx, second, nil); !apierrors.IsConflict(err) {
+		t.Errorf("expected conflict updating cluster read before the last update, got %v", err)
+	}
+
+	// The updated object can be updated again
+	first.Spec.KubernetesVersion = "1.35.1"
+	if _, err := clientset.UpdateCluster(ctx, first, nil); err != nil {
+		t.Errorf("error updating cluster again: %v", err)
+	}
+
+	firstIG, err := igs.Get(ctx, "nodes", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("error getting instance group: %v", err)
+	}
+	secondIG, err := igs.Get(ctx, "nodes", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("error getting instance group: %v", err)
+	}
+	firstIG.Spec.MaxSize = fi.PtrTo(int32(5))
+	if _, err := igs.Update(ctx, firstIG, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error updating instance group: %v", err)
+	}
+	secondIG.Spec.MaxSize = fi.PtrTo(int32(7))
+	if _, err := igs.Update(ctx, secondIG, metav1.UpdateOptions{}); !apierrors.IsConflict(err) {
+		t.Errorf("expected conflict updating instance group read before the last update, got %v", err)
+	}
+
+	stored, err := clientset.GetCluster(ctx, cluster.Name)
+	if err != nil {
+		t.Fatalf("error getting cluster: %v", err)
+	}
+	if stored.Spec.KubernetesVersion != "1.35.1" {
+		t.Errorf("expected kubernetes version %q, got %q", "1.35.1", stored.Spec.KubernetesVersion)
+	}
+}
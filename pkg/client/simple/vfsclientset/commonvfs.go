@@ -19,15 +19,18 @@ package vfsclientset
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"reflect"
 	"sort"
 	"time"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/acls"
 	"k8s.io/kops/pkg/apis/kops"
@@ -115,8 +118,9 @@ func (c *VFSClientBase) serialize(o runtime.Object) ([]byte, error) {
 	return b.Bytes(), nil
 }
 
+// readConfig reads the object, setting its ResourceVersion to the version of the file if the store supports conditional writes
 func (c *VFSClientBase) readConfig(ctx context.Context, configPath vfs.Path) (runtime.Object, error) {
-	data, err := configPath.ReadFile(ctx)
+	data, version, err := vfs.ReadFileWithVersion(ctx, configPath)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, err
@@ -128,11 +132,30 @@ func (c *VFSClientBase) readConfig(ctx context.Context, configPath vfs.Path) (ru
 	if err != nil {
 		return nil, fmt.Errorf("error parsing %s: %v", configPath, err)
 	}
+	if version != "" {
+		objectMeta, err := meta.Accessor(object)
+		if err != nil {
+			return nil, err
+		}
+		objectMeta.SetResourceVersion(version)
+	}
 	return object, nil
 }
 
+// writeConfig writes the object. If the store supports conditional writes, an update only succeeds
+// if the file was not changed since the object's ResourceVersion was read, or since the write started
+// if the object has no ResourceVersion; otherwise a Conflict error is returned.
 func (c *VFSClientBase) writeConfig(ctx context.Context, cluster *kops.Cluster, configPath vfs.Path, o runtime.Object, writeOptions ...vfs.WriteOption) error {
+	objectMeta, err := meta.Accessor(o)
+	if err != nil {
+		return err
+	}
+
+	// The version identifies the stored file, it is not stored in it
+	version := objectMeta.GetResourceVersion()
+	objectMeta.SetResourceVersion("")
 	data, err := c.serialize(o)
+	objectMeta.SetResourceVersion(version)
 	if err != nil {
 		return fmt.Errorf("error marshaling object: %v", err)
 	}
@@ -143,13 +166,17 @@ func (c *VFSClientBase) writeConfig(ctx context.Context, cluster *kops.Cluster,
 		case vfs.WriteOptionCreate:
 			create = true
 		case vfs.WriteOptionOnlyIfExists:
-			_, err = configPath.ReadFile(ctx)
+			var current string
+			_, current, err = vfs.ReadFileWithVersion(ctx, configPath)
 			if err != nil {
 				if os.IsNotExist(err) {
 					return fmt.Errorf("cannot update configuration file %s: does not exist", configPath)
 				}
 				return fmt.Errorf("error checking if configuration file %s exists already: %v", configPath, err)
 			}
+			if version == "" {
+				version = current
+			}
 		default:
 			return fmt.Errorf("unknown write option: %q", writeOption)
 		}
@@ -162,9 +189,19 @@ func (c *VFSClientBase) writeConfig(ctx context.Context, cluster *kops.Cluster,
 
 	rs := bytes.NewReader(data)
 	if create {
-		err = configPath.CreateFile(ctx, rs, acl)
+		if _, ok := configPath.(vfs.HasVersion); ok {
+			version, err = vfs.WriteFileIfVersion(ctx, configPath, rs, acl, "")
+			if errors.Is(err, vfs.ErrVersionConflict) {
+				err = os.ErrExist
+			}
+		} else {
+			err = configPath.CreateFile(ctx, rs, acl)
+		}
 	} else {
-		err = configPath.WriteFile(ctx, rs, acl)
+		version, err = vfs.WriteFileIfVersion(ctx, configPath, rs, acl, version)
+		if errors.Is(err, vfs.ErrVersionConflict) {
+			return apierrors.NewConflict(schema.GroupResource{Group: kops.GroupName, Resource: c.kind}, objectMeta.GetName(), fmt.Errorf("it was changed since it was read, read it again and retry"))
+		}
 	}
 	if err != nil {
 		if create && os.IsExist(err) {
@@ -173,6 +210,7 @@ func (c *VFSClientBase) writeConfig(ctx context.Context, cluster *kops.Cluster,
 		}
 		return fmt.Errorf("error writing configuration file %s: %v", configPath, err)
 	}
+	objectMeta.SetResourceVersion(version)
 	return nil
 }
 
@@ -196,6 +234,9 @@ func (c *VFSClientBase) update(ctx context.Context, cluster *kops.Cluster, i run
 
 	err = c.writeConfig(ctx, cluster, c.basePath.Join(objectMeta.GetName()), i, vfs.WriteOptionOnlyIfExists)
 	if err != nil {
+		if apierrors.IsConflict(err) {
+			return err
+		}
 		return fmt.Errorf("error writing %s: %v", c.kind, err)
 	}
 
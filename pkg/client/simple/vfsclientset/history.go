@@ -0,0 +1,452 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfsclientset
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	kopsbase "k8s.io/kops"
+	"k8s.io/kops/pkg/acls"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/registry"
+	"k8s.io/kops/pkg/apis/kops/validation"
+	"k8s.io/kops/pkg/client/simple"
+	"k8s.io/kops/pkg/kopscodecs"
+	"k8s.io/kops/pkg/util/localuser"
+	"k8s.io/kops/util/pkg/vfs"
+	"sigs.k8s.io/yaml"
+)
+
+// pathHistory is the directory, relative to the cluster's config base, holding its revisions
+const pathHistory = "history"
+
+// pathRollback is the marker, relative to the cluster's config base, recording a rollback in progress.
+// It is written before a rollback changes any object and removed once the rollback is recorded,
+// so that an interrupted rollback is detected and completed.
+const pathRollback = pathHistory + "/rollback.yaml"
+
+// rollbackMarker is the stored form of a rollback in progress
+type rollbackMarker struct {
+	Revision  int       `json:"revision"`
+	Timestamp time.Time `json:"timestamp"`
+	Author    string    `json:"author,omitempty"`
+}
+
+// revisionRecord is the stored form of a revision.
+// The objects are kept as they were serialized in the state store, so that a revision
+// can be restored even after the API has changed.
+type revisionRecord struct {
+	Revision       int               `json:"revision"`
+	Timestamp      time.Time         `json:"timestamp"`
+	Author         string            `json:"author,omitempty"`
+	KopsVersion    string            `json:"kopsVersion,omitempty"`
+	Cluster        string            `json:"cluster"`
+	InstanceGroups map[string]string `json:"instanceGroups,omitempty"`
+}
+
+// ClusterHistoryVFS implements simple.ClusterHistoryClient for a VFS-backed state store
+type ClusterHistoryVFS struct {
+	clientset *VFSClientset
+	cluster   *kops.Cluster
+}
+
+var _ simple.ClusterHistoryClient = &ClusterHistoryVFS{}
+
+func newClusterHistoryVFS(c *VFSClientset, cluster *kops.Cluster) *ClusterHistoryVFS {
+	if cluster == nil || cluster.Name == "" {
+		klog.Fatalf("cluster / cluster.Name is required")
+	}
+
+	return &ClusterHistoryVFS{
+		clientset: c,
+		cluster:   cluster,
+	}
+}
+
+func (h *ClusterHistoryVFS) clusterBasePath() vfs.Path {
+	return h.clientset.basePath.Join(h.cluster.Name)
+}
+
+// List implements simple.ClusterHistoryClient
+func (h *ClusterHistoryVFS) List(ctx context.Context) ([]*simple.ClusterRevision, error) {
+	historyPath := h.clusterBasePath().Join(pathHistory)
+	revisions, err := listRevisions(ctx, historyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*simple.ClusterRevision
+	for _, revision := range revisions {
+		record, err := readRevisionRecord(ctx, historyPath, revision)
+		if err != nil {
+			return nil, err
+		}
+		r, err := record.decode()
+		if err != nil {
+			return nil, fmt.Errorf("error parsing revision %d: %w", revision, err)
+		}
+		result = append(result, r)
+	}
+	return result, nil
+}
+
+// Get implements simple.ClusterHistoryClient
+func (h *ClusterHistoryVFS) Get(ctx context.Context, revision int) (*simple.ClusterRevision, error) {
+	record, err := readRevisionRecord(ctx, h.clusterBasePath().Join(pathHistory), revision)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("revision %d of cluster %q not found", revision, h.cluster.Name)
+		}
+		return nil, err
+	}
+	r, err := record.decode()
+	if err != nil {
+		return nil, fmt.Errorf("error parsing revision %d: %w", revision, err)
+	}
+	return r, nil
+}
+
+// Rollback implements simple.ClusterHistoryClient.
+// All the objects are validated before any is written, and a single revision is recorded for the rollback.
+// The state store has no transactions, so a marker is written before any object is changed;
+// if the rollback is interrupted, it is completed by the next rollback, before that one starts.
+// Rolling back to the revision of the interrupted rollback only completes it.
+func (h *ClusterHistoryVFS) Rollback(ctx context.Context, revision int) (*simple.ClusterRevision, error) {
+	marker, err := h.readRollbackMarker(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if marker != nil {
+		klog.Warningf("completing the interrupted rollback of cluster %q to revision %d, started by %s at %s", h.cluster.Name, marker.Revision, marker.Author, marker.Timestamp.Format(time.RFC3339))
+		record, err := h.rollback(ctx, marker.Revision, true)
+		if err != nil {
+			return nil, err
+		}
+		if marker.Revision == revision {
+			return record, nil
+		}
+	}
+	return h.rollback(ctx, revision, false)
+}
+
+// InterruptedRollback implements simple.ClusterHistoryClient
+func (h *ClusterHistoryVFS) InterruptedRollback(ctx context.Context) (int, error) {
+	marker, err := h.readRollbackMarker(ctx)
+	if err != nil || marker == nil {
+		return 0, err
+	}
+	return marker.Revision, nil
+}
+
+// readRollbackMarker returns the marker of a rollback that was interrupted after it started changing objects,
+// or nil if there is none
+func (h *ClusterHistoryVFS) readRollbackMarker(ctx context.Context) (*rollbackMarker, error) {
+	p := h.clusterBasePath().Join(pathRollback)
+	data, err := p.ReadFile(ctx)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading %s: %w", p, err)
+	}
+	marker := &rollbackMarker{}
+	if err := yaml.Unmarshal(data, marker); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", p, err)
+	}
+	return marker, nil
+}
+
+// rollback restores the given revision. When resuming, the rollback marker has already been written.
+func (h *ClusterHistoryVFS) rollback(ctx context.Context, revision int, resuming bool) (*simple.ClusterRevision, error) {
+	target, err := h.Get(ctx, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	clusters := newClusterVFS(h.clientset.VFSContext(), h.clientset.basePath)
+	current, err := clusters.Get(ctx, h.cluster.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	igs := newInstanceGroupVFS(h.clientset, current)
+	currentIGs, err := igs.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	currentIGsByName := make(map[string]*kops.InstanceGroup)
+	for i := range currentIGs.Items {
+		currentIGsByName[currentIGs.Items[i].Name] = &currentIGs.Items[i]
+	}
+
+	cluster := target.Cluster
+	if cluster.Spec.ConfigStore.Base == "" {
+		cluster.Spec.ConfigStore.Base = current.Spec.ConfigStore.Base
+	}
+	cluster.SetGeneration(current.GetGeneration())
+	if !apiequality.Semantic.DeepEqual(current.Spec, cluster.Spec) {
+		cluster.SetGeneration(current.GetGeneration() + 1)
+	}
+	if err := validation.ValidateClusterUpdate(cluster, nil, current, h.clientset.VFSContext()).ToAggregate(); err != nil {
+		return nil, fmt.Errorf("revision %d is not valid for the current cluster: %w", revision, err)
+	}
+	for _, ig := range target.InstanceGroups {
+		if old := currentIGsByName[ig.Name]; old != nil {
+			ig.SetGeneration(old.GetGeneration())
+			if !apiequality.Semantic.DeepEqual(old.Spec, ig.Spec) {
+				ig.SetGeneration(old.GetGeneration() + 1)
+			}
+		}
+		if err := validation.ValidateInstanceGroup(ig, nil, false).ToAggregate(); err != nil {
+			return nil, fmt.Errorf("instance group %q of revision %d is not valid: %w", ig.Name, revision, err)
+		}
+	}
+
+	markerPath := h.clusterBasePath().Join(pathRollback)
+	if !resuming {
+		data, err := yaml.Marshal(&rollbackMarker{
+			Revision:  revision,
+			Timestamp: time.Now().UTC(),
+			Author:    localuser.Current().String(),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error serializing rollback marker: %w", err)
+		}
+		acl, err := acls.GetACL(ctx, markerPath, cluster)
+		if err != nil {
+			return nil, err
+		}
+		if err := markerPath.CreateFile(ctx, bytes.NewReader(data), acl); err != nil {
+			if os.IsExist(err) {
+				return nil, fmt.Errorf("another rollback of cluster %q is in progress", h.cluster.Name)
+			}
+			return nil, fmt.Errorf("error writing %s: %w", markerPath, err)
+		}
+	}
+
+	incomplete := func(err error) error {
+		return fmt.Errorf("rollback to revision %d was interrupted, complete it with `kops rollback cluster %s --to rev%d --yes`: %w", revision, h.cluster.Name, revision, err)
+	}
+	for _, ig := range target.InstanceGroups {
+		if currentIGsByName[ig.Name] != nil {
+			err = igs.update(ctx, current, ig)
+		} else {
+			err = igs.create(ctx, current, ig)
+		}
+		if err != nil {
+			return nil, incomplete(err)
+		}
+	}
+	for name := range currentIGsByName {
+		if !slices.ContainsFunc(target.InstanceGroups, func(ig *kops.InstanceGroup) bool { return ig.Name == name }) {
+			if err := igs.delete(ctx, name, metav1.DeleteOptions{}); err != nil {
+				return nil, incomplete(err)
+			}
+		}
+	}
+	if err := clusters.writeConfig(ctx, cluster, h.clusterBasePath().Join(registry.PathCluster), cluster, vfs.WriteOptionOnlyIfExists); err != nil {
+		return nil, incomplete(err)
+	}
+
+	record, err := writeClusterRevision(ctx, h.clusterBasePath(), cluster)
+	if err != nil {
+		return nil, incomplete(fmt.Errorf("recording the revision failed: %w", err))
+	}
+	if err := markerPath.Remove(ctx); err != nil && !os.IsNotExist(err) {
+		return nil, incomplete(fmt.Errorf("error removing %s: %w", markerPath, err))
+	}
+	return record.decode()
+}
+
+// recordClusterRevision records the current cluster and instance group specs as a new revision.
+// Failures are logged, as the change itself has already been written.
+func recordClusterRevision(ctx context.Context, clusterBasePath vfs.Path, cluster *kops.Cluster) {
+	if _, err := writeClusterRevision(ctx, clusterBasePath, cluster); err != nil {
+		klog.Warningf("failed to record revision of cluster %q: %v", cluster.Name, err)
+	}
+}
+
+// writeClusterRevision records the current cluster and instance group specs as a new revision,
+// unless they are unchanged since the latest revision, which is returned instead.
+func writeClusterRevision(ctx context.Context, clusterBasePath vfs.Path, cluster *kops.Cluster) (*revisionRecord, error) {
+	record, err := snapshotCluster(ctx, clusterBasePath)
+	if err != nil {
+		return nil, err
+	}
+	record.Timestamp = time.Now().UTC()
+	record.Author = localuser.Current().String()
+	record.KopsVersion = kopsbase.Version
+
+	historyPath := clusterBasePath.Join(pathHistory)
+
+	// Revisions are created exclusively, so a concurrent writer makes us retry with the next number
+	for attempt := 0; attempt < 5; attempt++ {
+		revisions, err := listRevisions(ctx, historyPath)
+		if err != nil {
+			return nil, err
+		}
+		record.Revision = 1
+		if len(revisions) != 0 {
+			latest, err := readRevisionRecord(ctx, historyPath, revisions[len(revisions)-1])
+			if err != nil {
+				return nil, err
+			}
+			if latest.Cluster == record.Cluster && maps.Equal(latest.InstanceGroups, record.InstanceGroups) {
+				return latest, nil
+			}
+			record.Revision = latest.Revision + 1
+		}
+
+		data, err := yaml.Marshal(record)
+		if err != nil {
+			return nil, fmt.Errorf("error serializing revision: %w", err)
+		}
+		p := historyPath.Join(revisionFileName(record.Revision))
+		acl, err := acls.GetACL(ctx, p, cluster)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.CreateFile(ctx, bytes.NewReader(data), acl); err != nil {
+			if os.IsExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("error writing revision %s: %w", p, err)
+		}
+		return record, nil
+	}
+	return nil, fmt.Errorf("could not record revision: concurrent changes to the cluster")
+}
+
+// snapshotCluster reads the stored cluster and instance group objects
+func snapshotCluster(ctx context.Context, clusterBasePath vfs.Path) (*revisionRecord, error) {
+	configPath := clusterBasePath.Join(registry.PathCluster)
+	config, err := configPath.ReadFile(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", configPath, err)
+	}
+	record := &revisionRecord{
+		Cluster: string(config),
+	}
+
+	igPath := clusterBasePath.Join("instancegroup")
+	names, err := listChildNames(ctx, igPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		p := igPath.Join(name)
+		data, err := p.ReadFile(ctx)
+		if err != nil {
+			if os.IsNotExist(err) {
+				// Deleted since it was listed
+				continue
+			}
+			return nil, fmt.Errorf("error reading %s: %w", p, err)
+		}
+		if record.InstanceGroups == nil {
+			record.InstanceGroups = make(map[string]string)
+		}
+		record.InstanceGroups[name] = string(data)
+	}
+	return record, nil
+}
+
+func revisionFileName(revision int) string {
+	return fmt.Sprintf("%08d.yaml", revision)
+}
+
+// listRevisions returns the numbers of the recorded revisions, in ascending order
+func listRevisions(ctx context.Context, historyPath vfs.Path) ([]int, error) {
+	names, err := listChildNames(ctx, historyPath)
+	if err != nil {
+		return nil, err
+	}
+	var revisions []int
+	for _, name := range names {
+		if name == path.Base(pathRollback) {
+			continue
+		}
+		revision, err := strconv.Atoi(strings.TrimSuffix(name, ".yaml"))
+		if err != nil || revision <= 0 {
+			klog.V(2).Infof("ignoring unexpected file %q in %s", name, historyPath)
+			continue
+		}
+		revisions = append(revisions, revision)
+	}
+	sort.Ints(revisions)
+	return revisions, nil
+}
+
+func readRevisionRecord(ctx context.Context, historyPath vfs.Path, revision int) (*revisionRecord, error) {
+	p := historyPath.Join(revisionFileName(revision))
+	data, err := p.ReadFile(ctx)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("error reading %s: %w", p, err)
+	}
+	record := &revisionRecord{}
+	if err := yaml.Unmarshal(data, record); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", p, err)
+	}
+	return record, nil
+}
+
+func (r *revisionRecord) decode() (*simple.ClusterRevision, error) {
+	revision := &simple.ClusterRevision{
+		Revision:    r.Revision,
+		Timestamp:   r.Timestamp,
+		Author:      r.Author,
+		KopsVersion: r.KopsVersion,
+	}
+
+	o, _, err := kopscodecs.Decode([]byte(r.Cluster), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing cluster: %w", err)
+	}
+	cluster, ok := o.(*kops.Cluster)
+	if !ok {
+		return nil, fmt.Errorf("unexpected object of type %T for cluster", o)
+	}
+	revision.Cluster = cluster
+
+	for _, name := range slices.Sorted(maps.Keys(r.InstanceGroups)) {
+		o, _, err := kopscodecs.Decode([]byte(r.InstanceGroups[name]), nil)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing instance group %q: %w", name, err)
+		}
+		ig, ok := o.(*kops.InstanceGroup)
+		if !ok {
+			return nil, fmt.Errorf("unexpected object of type %T for instance group %q", o, name)
+		}
+		revision.InstanceGroups = append(revision.InstanceGroups, ig)
+	}
+	return revision, nil
+}
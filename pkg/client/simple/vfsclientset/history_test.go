@@ -0,0 +1,236 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfsclientset
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/testutils"
+	"k8s.io/kops/util/pkg/vfs"
+	"sigs.k8s.io/yaml"
+)
+
+func TestClusterHistory(t *testing.T) {
+	ctx := context.TODO()
+	vfs.Context.ResetMemfsContext(true)
+	basePath, err := vfs.Context.BuildVfsPath("memfs://tests")
+	if err != nil {
+		t.Fatalf("error building vfs path: %v", err)
+	}
+	clientset := NewVFSClientset(vfs.Context, basePath)
+
+	cluster := testutils.BuildMinimalCluster("history.example.com")
+	cluster.Spec.ConfigStore.Base = basePath.Join(cluster.Name).Path()
+	cluster, err = clientset.CreateCluster(ctx, cluster)
+	if err != nil {
+		t.Fatalf("error creating cluster: %v", err)
+	}
+
+	nodes := testutils.BuildMinimalNodeInstanceGroup("nodes", "subnet-us-test-1a")
+	if _, err := clientset.InstanceGroupsFor(cluster).Create(ctx, &nodes, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error creating instance group: %v", err)
+	}
+
+	// The first update stores the defaulted spec, a repeated update without
+	// changes does not record another revision
+	for i := 0; i < 2; i++ {
+		cluster, err = clientset.GetCluster(ctx, cluster.Name)
+		if err != nil {
+			t.Fatalf("error getting cluster: %v", err)
+		}
+		if _, err := clientset.UpdateCluster(ctx, cluster, nil); err != nil {
+			t.Fatalf("error updating cluster: %v", err)
+		}
+	}
+
+	cluster.Spec.KubernetesVersion = "1.35.0"
+	if _, err := clientset.UpdateCluster(ctx, cluster, nil); err != nil {
+		t.Fatalf("error updating cluster: %v", err)
+	}
+	extra := testutils.BuildMinimalNodeInstanceGroup("extra", "subnet-us-test-1a")
+	if _, err := clientset.InstanceGroupsFor(cluster).Create(ctx, &extra, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error creating instance group: %v", err)
+	}
+
+	history := clientset.HistoryFor(cluster)
+	revisions, err := history.List(ctx)
+	if err != nil {
+		t.Fatalf("error listing revisions: %v", err)
+	}
+	if len(revisions) != 5 {
+		t.Fatalf("expected 5 revisions, got %d", len(revisions))
+	}
+	for i, revision := range revisions {
+		if revision.Revision != i+1 {
+			t.Errorf("expected revision %d, got %d", i+1, revision.Revision)
+		}
+		if revision.Author == "" || revision.KopsVersion == "" || revision.Timestamp.IsZero() {
+			t.Errorf("revision %d is missing metadata: %+v", revision.Revision, revision)
+		}
+	}
+	if len(revisions[0].InstanceGroups) != 0 || len(revisions[1].InstanceGroups) != 1 || len(revisions[4].InstanceGroups) != 2 {
+		t.Errorf("unexpected instance groups in revisions")
+	}
+
+	rollback, err := history.Rollback(ctx, 2)
+	if err != nil {
+		t.Fatalf("error rolling back: %v", err)
+	}
+	if rollback.Revision != 6 {
+		t.Errorf("expected rollback to record revision 6, got %d", rollback.Revision)
+	}
+
+	restored, err := clientset.GetCluster(ctx, cluster.Name)
+	if err != nil {
+		t.Fatalf("error getting cluster: %v", err)
+	}
+	if restored.Spec.KubernetesVersion != revisions[1].Cluster.Spec.KubernetesVersion {
+		t.Errorf("expected kubernetes version %q after rollback, got %q", revisions[1].Cluster.Spec.KubernetesVersion, restored.Spec.KubernetesVersion)
+	}
+	if restored.Generation <= revisions[4].Cluster.Generation {
+		t.Errorf("expected generation to increase on rollback, got %d", restored.Generation)
+	}
+	// memfs keeps listing removed files, so check the instance groups by name
+	igs := clientset.InstanceGroupsFor(cluster)
+	if _, err := igs.Get(ctx, "nodes", metav1.GetOptions{}); err != nil {
+		t.Errorf("expected instance group %q after rollback: %v", "nodes", err)
+	}
+	if _, err := igs.Get(ctx, "extra", metav1.GetOptions{}); err == nil {
+		t.Errorf("expected instance group %q to be deleted by rollback", "extra")
+	}
+
+	if _, err := history.Get(ctx, 42); err == nil {
+		t.Errorf("expected error getting missing revision")
+	}
+}
+
+func TestClusterHistoryResumesRollback(t *testing.T) {
+	ctx := context.TODO()
+	vfs.Context.ResetMemfsContext(true)
+	basePath, err := vfs.Context.BuildVfsPath("memfs://tests")
+	if err != nil {
+		t.Fatalf("error building vfs path: %v", err)
+	}
+	clientset := NewVFSClientset(vfs.Context, basePath)
+
+	cluster := testutils.BuildMinimalCluster("resume.example.com")
+	cluster.Spec.ConfigStore.Base = basePath.Join(cluster.Name).Path()
+	cluster, err = clientset.CreateCluster(ctx, cluster)
+	if err != nil {
+		t.Fatalf("error creating cluster: %v", err)
+	}
+	initialVersion := cluster.Spec.KubernetesVersion
+	cluster.Spec.KubernetesVersion = "1.35.0"
+	if _, err := clientset.UpdateCluster(ctx, cluster, nil); err != nil {
+		t.Fatalf("error updating cluster: %v", err)
+	}
+
+	// Simulate a rollback to the first revision that was interrupted before writing any object
+	data, err := yaml.Marshal(&rollbackMarker{Revision: 1, Author: "test"})
+	if err != nil {
+		t.Fatalf("error serializing marker: %v", err)
+	}
+	markerPath := basePath.Join(cluster.Name, pathRollback)
+	if err := markerPath.WriteFile(ctx, bytes.NewReader(data), nil); err != nil {
+		t.Fatalf("error writing marker: %v", err)
+	}
+
+	if _, err := clientset.HistoryFor(cluster).Rollback(ctx, 2); err != nil {
+		t.Fatalf("error rolling back: %v", err)
+	}
+	if _, err := markerPath.ReadFile(ctx); !os.IsNotExist(err) {
+		t.Errorf("expected rollback marker to be removed, got %v", err)
+	}
+	revisions, err := clientset.HistoryFor(cluster).List(ctx)
+	if err != nil {
+		t.Fatalf("error listing revisions: %v", err)
+	}
+	if len(revisions) != 4 {
+		t.Fatalf("expected the resumed and the requested rollback to be recorded, got %d revisions", len(revisions))
+	}
+	if revisions[2].Cluster.Spec.KubernetesVersion != initialVersion {
+		t.Errorf("expected the resumed rollback to restore version %q, got %q", initialVersion, revisions[2].Cluster.Spec.KubernetesVersion)
+	}
+
+	// Reading the cluster does not complete an interrupted rollback
+	if err := markerPath.WriteFile(ctx, bytes.NewReader(data), nil); err != nil {
+		t.Fatalf("error writing marker: %v", err)
+	}
+	current, err := clientset.GetCluster(ctx, cluster.Name)
+	if err != nil {
+		t.Fatalf("error getting cluster: %v", err)
+	}
+	if current.Spec.KubernetesVersion != "1.35.0" {
+		t.Errorf("expected reading the cluster not to change kubernetes version, got %q", current.Spec.KubernetesVersion)
+	}
+	interrupted, err := clientset.HistoryFor(cluster).InterruptedRollback(ctx)
+	if err != nil {
+		t.Fatalf("error reading interrupted rollback: %v", err)
+	}
+	if interrupted != 1 {
+		t.Errorf("expected interrupted rollback to revision 1, got %d", interrupted)
+	}
+
+	// Rolling back to the same revision only completes the interrupted rollback
+	if _, err := clientset.HistoryFor(cluster).Rollback(ctx, 1); err != nil {
+		t.Fatalf("error rolling back: %v", err)
+	}
+	restored, err := clientset.GetCluster(ctx, cluster.Name)
+	if err != nil {
+		t.Fatalf("error getting cluster: %v", err)
+	}
+	if restored.Spec.KubernetesVersion != initialVersion {
+		t.Errorf("expected kubernetes version %q after the rollback is resumed, got %q", initialVersion, restored.Spec.KubernetesVersion)
+	}
+	if _, err := markerPath.ReadFile(ctx); !os.IsNotExist(err) {
+		t.Errorf("expected rollback marker to be removed, got %v", err)
+	}
+	revisions, err = clientset.HistoryFor(cluster).List(ctx)
+	if err != nil {
+		t.Fatalf("error listing revisions: %v", err)
+	}
+	if len(revisions) != 5 {
+		t.Errorf("expected only the resumed rollback to be recorded, got %d revisions", len(revisions))
+	}
+}
+
+func TestDeleteAllClusterStateWithHistory(t *testing.T) {
+	ctx := context.TODO()
+	vfs.Context.ResetMemfsContext(true)
+	basePath, err := vfs.Context.BuildVfsPath("memfs://tests")
+	if err != nil {
+		t.Fatalf("error building vfs path: %v", err)
+	}
+	clientset := NewVFSClientset(vfs.Context, basePath)
+
+	cluster := testutils.BuildMinimalCluster("history.example.com")
+	cluster.Spec.ConfigStore.Base = basePath.Join(cluster.Name).Path()
+	if _, err := clientset.CreateCluster(ctx, cluster); err != nil {
+		t.Fatalf("error creating cluster: %v", err)
+	}
+	nodes := testutils.BuildMinimalNodeInstanceGroup("nodes", "subnet-us-test-1a")
+	if _, err := clientset.InstanceGroupsFor(cluster).Create(ctx, &nodes, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error creating instance group: %v", err)
+	}
+	if err := DeleteAllClusterState(ctx, basePath.Join(cluster.Name)); err != nil {
+		t.Fatalf("error deleting cluster state: %v", err)
+	}
+}
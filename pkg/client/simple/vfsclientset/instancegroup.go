@@ -31,13 +31,15 @@ import (
 	kopsapi "k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/apis/kops/validation"
 	kopsinternalversion "k8s.io/kops/pkg/client/clientset_generated/clientset/typed/kops/internalversion"
+	"k8s.io/kops/util/pkg/vfs"
 )
 
 type InstanceGroupVFS struct {
 	VFSClientBase
 
-	clusterName string
-	cluster     *kopsapi.Cluster
+	clusterName     string
+	cluster         *kopsapi.Cluster
+	clusterBasePath vfs.Path
 }
 
 func newInstanceGroupVFS(c *VFSClientset, cluster *kopsapi.Cluster) *InstanceGroupVFS {
@@ -49,10 +51,11 @@ func newInstanceGroupVFS(c *VFSClientset, cluster *kopsapi.Cluster) *InstanceGro
 	kind := "InstanceGroup"
 
 	r := &InstanceGroupVFS{
-		cluster:     cluster,
-		clusterName: clusterName,
+		cluster:         cluster,
+		clusterName:     clusterName,
+		clusterBasePath: c.basePath.Join(clusterName),
 	}
-	r.Init(kind, c.VFSContext(), c.basePath.Join(clusterName, "instancegroup"), StoreVersion)
+	r.Init(kind, c.VFSContext(), r.clusterBasePath.Join("instancegroup"), StoreVersion)
 	r.validate = func(o runtime.Object) error {
 		return validation.ValidateInstanceGroup(o.(*kopsapi.InstanceGroup), nil, false).ToAggregate()
 	}
@@ -105,6 +108,7 @@ func (c *InstanceGroupVFS) Create(ctx context.Context, g *kopsapi.InstanceGroup,
 	if err != nil {
 		return nil, err
 	}
+	recordClusterRevision(ctx, c.clusterBasePath, c.cluster)
 	return g, nil
 }
 
@@ -118,16 +122,30 @@ func (c *InstanceGroupVFS) Update(ctx context.Context, g *kopsapi.InstanceGroup,
 		g.SetGeneration(old.GetGeneration() + 1)
 	}
 
+	// Fail with a conflict if the instance group changes after we read it, unless the caller read it earlier
+	if g.ResourceVersion == "" {
+		g.ResourceVersion = old.ResourceVersion
+	}
+
 	validation.ValidateInstanceGroup(g, nil, true)
 	err = c.update(ctx, c.cluster, g)
 	if err != nil {
 		return nil, err
 	}
+	recordClusterRevision(ctx, c.clusterBasePath, c.cluster)
 	return g, nil
 }
 
+func (c *InstanceGroupVFS) UpdateStatus(ctx context.Context, g *kopsapi.InstanceGroup, opts metav1.UpdateOptions) (*kopsapi.InstanceGroup, error) {
+	return nil, fmt.Errorf("InstanceGroupVFS UpdateStatus not implemented for vfs store")
+}
+
 func (c *InstanceGroupVFS) Delete(ctx context.Context, name string, options metav1.DeleteOptions) error {
-	return c.delete(ctx, name, options)
+	if err := c.delete(ctx, name, options); err != nil {
+		return err
+	}
+	recordClusterRevision(ctx, c.clusterBasePath, c.cluster)
+	return nil
 }
 
 func (r *InstanceGroupVFS) DeleteCollection(ctx context.Context, options metav1.DeleteOptions, listOptions metav1.ListOptions) error {
@@ -45,6 +45,7 @@ func NewCmdHelpers(f *util.Factory, out io.Writer) *cobra.Command {
 	}
 
 	cmd.AddCommand(helpers.NewCmdHelperKubectlAuth(f, out))
+	cmd.AddCommand(helpers.NewCmdHelperKubectlOIDCLogin(out))
 
 	return cmd
 }
@@ -20,13 +20,11 @@ import (
 	"bytes"
 	"context"
 	"crypto/sha256"
-	"crypto/x509/pkix"
 	"encoding/json"
 	"fmt"
 	"io"
 	"math/big"
 	"os"
-	"os/user"
 	"path/filepath"
 	"strings"
 	"time"
@@ -36,9 +34,7 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/kops/cmd/kops/util"
 	"k8s.io/kops/pkg/commands/commandutils"
-	"k8s.io/kops/pkg/pki"
-	"k8s.io/kops/pkg/rbac"
-	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/pkg/kubeconfig"
 	"k8s.io/kubectl/pkg/util/i18n"
 )
 
@@ -165,6 +161,7 @@ type ExecCredential struct {
 type ExecCredentialStatus struct {
 	ClientCertificateData string    `json:"clientCertificateData,omitempty"`
 	ClientKeyData         string    `json:"clientKeyData,omitempty"`
+	Token                 string    `json:"token,omitempty"`
 	ExpirationTimestamp   time.Time `json:"expirationTimestamp,omitempty"`
 }
 
@@ -245,25 +242,7 @@ func buildCredentials(ctx context.Context, f *util.Factory, options *HelperKubec
 		return nil, fmt.Errorf("unable to get cluster keystore: %v", err)
 	}
 
-	cn := "kubecfg"
-	user, err := user.Current()
-	if err != nil || user == nil {
-		klog.Infof("unable to get user: %v", err)
-	} else {
-		cn += "-" + user.Name
-	}
-
-	req := pki.IssueCertRequest{
-		Signer: fi.CertificateIDCA,
-		Type:   "client",
-		Subject: pkix.Name{
-			CommonName: cn,
-
-			Organization: []string{rbac.SystemPrivilegedGroup},
-		},
-		Validity: options.Lifetime,
-	}
-	cert, privateKey, _, err := pki.IssueCert(ctx, &req, fi.NewPKIKeystoreAdapter(keyStore))
+	cert, privateKey, err := kubeconfig.IssueAdminCertificate(ctx, cluster, keyStore, options.Lifetime)
 	if err != nil {
 		return nil, fmt.Errorf("unable to issue certificate: %v", err)
 	}
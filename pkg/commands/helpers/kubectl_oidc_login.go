@@ -0,0 +1,470 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/util/homedir"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kubectl/pkg/util/i18n"
+)
+
+var kubectlOIDCLoginShort = i18n.T(`kubectl authentication plugin using an OIDC device-code login`)
+
+// HelperKubectlOIDCLoginOptions holds the options for logging in to an OIDC issuer
+type HelperKubectlOIDCLoginOptions struct {
+	// IssuerURL is the URL of the OpenID issuer
+	IssuerURL string
+
+	// ClientID is the client ID of the OpenID Connect client
+	ClientID string
+
+	// ExtraScopes are requested in addition to openid and offline_access
+	ExtraScopes []string
+
+	// APIVersion specifies the version of the client.authentication.k8s.io schema in use
+	APIVersion string
+}
+
+// InitDefaults populates the default values of options
+func (o *HelperKubectlOIDCLoginOptions) InitDefaults() {
+	o.APIVersion = "v1beta1"
+}
+
+// NewCmdHelperKubectlOIDCLogin builds a cobra command for the kubectl-oidc-login command
+func NewCmdHelperKubectlOIDCLogin(out io.Writer) *cobra.Command {
+	options := &HelperKubectlOIDCLoginOptions{}
+	options.InitDefaults()
+
+	cmd := &cobra.Command{
+		Use:   "kubectl-oidc-login",
+		Short: kubectlOIDCLoginShort,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := cmd.Context()
+
+			err := RunKubectlOIDCLoginHelper(ctx, out, os.Stderr, options)
+			if err != nil {
+				commandutils.ExitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&options.APIVersion, "api-version", options.APIVersion, "version of client.authentication.k8s.io schema in use")
+	cmd.Flags().StringVar(&options.IssuerURL, "issuer-url", options.IssuerURL, "URL of the OpenID issuer")
+	cmd.Flags().StringVar(&options.ClientID, "client-id", options.ClientID, "client ID of the OpenID Connect client")
+	cmd.Flags().StringSliceVar(&options.ExtraScopes, "extra-scope", options.ExtraScopes, "additional scopes to request")
+
+	return cmd
+}
+
+// RunKubectlOIDCLoginHelper implements the kubectl OIDC login helper, which obtains an ID token
+// using the OAuth 2.0 device authorization grant (RFC 8628).
+// The user is prompted on errOut, as kubectl passes stderr through to the terminal.
+func RunKubectlOIDCLoginHelper(ctx context.Context, out io.Writer, errOut io.Writer, options *HelperKubectlOIDCLoginOptions) error {
+	l := &oidcLogin{
+		httpClient: http.DefaultClient,
+		errOut:     errOut,
+		now:        time.Now,
+		sleep:      sleepContext,
+	}
+	return l.run(ctx, out, options)
+}
+
+// oidcLogin performs the device-code login; the clock and HTTP client are replaceable for tests.
+type oidcLogin struct {
+	httpClient *http.Client
+	errOut     io.Writer
+	now        func() time.Time
+	sleep      func(ctx context.Context, d time.Duration) error
+}
+
+// oidcCachedToken is the token state we keep between invocations
+type oidcCachedToken struct {
+	IDToken      string    `json:"idToken"`
+	RefreshToken string    `json:"refreshToken,omitempty"`
+	Expiry       time.Time `json:"expiry"`
+}
+
+// oidcProviderMetadata holds the fields we use from the OpenID provider configuration
+type oidcProviderMetadata struct {
+	DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint"`
+	TokenEndpoint               string `json:"token_endpoint"`
+}
+
+// oidcDeviceAuthorization is the response of the device authorization endpoint
+type oidcDeviceAuthorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+// oidcTokenResponse is the response of the token endpoint, successful or not
+type oidcTokenResponse struct {
+	IDToken          string `json:"id_token"`
+	RefreshToken     string `json:"refresh_token"`
+	ExpiresIn        int    `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+func (l *oidcLogin) run(ctx context.Context, out io.Writer, options *HelperKubectlOIDCLoginOptions) error {
+	if options.IssuerURL == "" {
+		return fmt.Errorf("issuer-url is required")
+	}
+	if options.ClientID == "" {
+		return fmt.Errorf("client-id is required")
+	}
+
+	execCredential := &ExecCredential{
+		Kind: "ExecCredential",
+	}
+	switch options.APIVersion {
+	case "":
+		return fmt.Errorf("api-version must be specified")
+	case "v1alpha1":
+		execCredential.APIVersion = "client.authentication.k8s.io/v1alpha1"
+	case "v1beta1":
+		execCredential.APIVersion = "client.authentication.k8s.io/v1beta1"
+	default:
+		return fmt.Errorf("api-version %q is not supported", options.APIVersion)
+	}
+
+	scopes := append([]string{"openid", "offline_access"}, options.ExtraScopes...)
+
+	cacheFilePath := oidcCacheFilePath(options.IssuerURL, options.ClientID, scopes)
+	cached, err := loadCachedOIDCToken(cacheFilePath)
+	if err != nil {
+		klog.Infof("cached token %q was not valid: %v", cacheFilePath, err)
+		cached = nil
+	}
+
+	token := cached
+	isCached := token != nil
+	if token != nil && !token.Expiry.After(l.now().Add(time.Minute)) {
+		token = nil
+		isCached = false
+		if cached.RefreshToken != "" {
+			refreshed, err := l.refresh(ctx, options, cached.RefreshToken)
+			if err != nil {
+				klog.Infof("unable to refresh OIDC token: %v", err)
+			} else {
+				token = refreshed
+			}
+		}
+	}
+	if token == nil {
+		token, err = l.deviceLogin(ctx, options, scopes)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !isCached {
+		b, err := json.Marshal(token)
+		if err != nil {
+			return fmt.Errorf("error marshaling json: %v", err)
+		}
+		if err := os.MkdirAll(filepath.Dir(cacheFilePath), 0o700); err != nil {
+			klog.Warningf("failed to make cache directory for %q: %v", cacheFilePath, err)
+		}
+		if err := os.WriteFile(cacheFilePath, b, 0o600); err != nil {
+			klog.Warningf("failed to write cache file %q: %v", cacheFilePath, err)
+		}
+	}
+
+	execCredential.Status.Token = token.IDToken
+	execCredential.Status.ExpirationTimestamp = token.Expiry
+
+	b, err := json.MarshalIndent(execCredential, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling json: %v", err)
+	}
+	_, err = out.Write(b)
+	if err != nil {
+		return fmt.Errorf("error writing to stdout: %v", err)
+	}
+
+	return nil
+}
+
+// deviceLogin prompts the user to authorize this device and polls the token endpoint until they do.
+func (l *oidcLogin) deviceLogin(ctx context.Context, options *HelperKubectlOIDCLoginOptions, scopes []string) (*oidcCachedToken, error) {
+	metadata, err := l.discover(ctx, options.IssuerURL)
+	if err != nil {
+		return nil, err
+	}
+	if metadata.DeviceAuthorizationEndpoint == "" {
+		return nil, fmt.Errorf("OIDC issuer %q does not support the device authorization grant", options.IssuerURL)
+	}
+
+	authorization := &oidcDeviceAuthorization{}
+	form := url.Values{
+		"client_id": {options.ClientID},
+		"scope":     {strings.Join(scopes, " ")},
+	}
+	if err := l.postForm(ctx, metadata.DeviceAuthorizationEndpoint, form, authorization); err != nil {
+		return nil, fmt.Errorf("error requesting device authorization: %w", err)
+	}
+	if authorization.DeviceCode == "" || authorization.UserCode == "" || authorization.VerificationURI == "" {
+		return nil, fmt.Errorf("device authorization response from %q is incomplete", metadata.DeviceAuthorizationEndpoint)
+	}
+
+	if authorization.VerificationURIComplete != "" {
+		fmt.Fprintf(l.errOut, "To log in to %s, open %s and confirm the code %s\n", options.IssuerURL, authorization.VerificationURIComplete, authorization.UserCode)
+	} else {
+		fmt.Fprintf(l.errOut, "To log in to %s, open %s and enter the code %s\n", options.IssuerURL, authorization.VerificationURI, authorization.UserCode)
+	}
+
+	// RFC 8628 section 3.2: the interval defaults to 5 seconds
+	interval := 5 * time.Second
+	if authorization.Interval > 0 {
+		interval = time.Duration(authorization.Interval) * time.Second
+	}
+	var deadline time.Time
+	if authorization.ExpiresIn > 0 {
+		deadline = l.now().Add(time.Duration(authorization.ExpiresIn) * time.Second)
+	}
+
+	form = url.Values{
+		"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+		"device_code": {authorization.DeviceCode},
+		"client_id":   {options.ClientID},
+	}
+	for {
+		if err := l.sleep(ctx, interval); err != nil {
+			return nil, err
+		}
+		if !deadline.IsZero() && l.now().After(deadline) {
+			return nil, fmt.Errorf("device code expired before the login was completed")
+		}
+
+		response := &oidcTokenResponse{}
+		if err := l.postForm(ctx, metadata.TokenEndpoint, form, response); err != nil {
+			return nil, fmt.Errorf("error requesting token: %w", err)
+		}
+		switch response.Error {
+		case "":
+			return l.tokenFromResponse(response, "")
+		case "authorization_pending":
+			continue
+		case "slow_down":
+			interval += 5 * time.Second
+			continue
+		case "access_denied":
+			return nil, fmt.Errorf("login was denied")
+		case "expired_token":
+			return nil, fmt.Errorf("device code expired before the login was completed")
+		default:
+			return nil, response.asError()
+		}
+	}
+}
+
+// refresh exchanges a refresh token for a new ID token.
+func (l *oidcLogin) refresh(ctx context.Context, options *HelperKubectlOIDCLoginOptions, refreshToken string) (*oidcCachedToken, error) {
+	metadata, err := l.discover(ctx, options.IssuerURL)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &oidcTokenResponse{}
+	form := url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+		"client_id":     {options.ClientID},
+	}
+	if err := l.postForm(ctx, metadata.TokenEndpoint, form, response); err != nil {
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, response.asError()
+	}
+
+	// Issuers are not required to rotate the refresh token
+	return l.tokenFromResponse(response, refreshToken)
+}
+
+func (l *oidcLogin) tokenFromResponse(response *oidcTokenResponse, refreshToken string) (*oidcCachedToken, error) {
+	if response.IDToken == "" {
+		return nil, fmt.Errorf("token response did not include an id_token")
+	}
+
+	token := &oidcCachedToken{
+		IDToken:      response.IDToken,
+		RefreshToken: response.RefreshToken,
+	}
+	if token.RefreshToken == "" {
+		token.RefreshToken = refreshToken
+	}
+
+	// The API server checks the exp claim of the ID token, which may differ from the access token lifetime
+	expiry, err := idTokenExpiry(response.IDToken)
+	if err != nil {
+		return nil, err
+	}
+	token.Expiry = expiry
+
+	return token, nil
+}
+
+func (l *oidcLogin) discover(ctx context.Context, issuerURL string) (*oidcProviderMetadata, error) {
+	u := strings.TrimSuffix(issuerURL, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := l.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching OIDC discovery document: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %q fetching %q", resp.Status, u)
+	}
+
+	metadata := &oidcProviderMetadata{}
+	if err := json.NewDecoder(resp.Body).Decode(metadata); err != nil {
+		return nil, fmt.Errorf("error parsing OIDC discovery document %q: %w", u, err)
+	}
+	if metadata.TokenEndpoint == "" {
+		return nil, fmt.Errorf("OIDC discovery document %q does not include a token_endpoint", u)
+	}
+	return metadata, nil
+}
+
+// postForm posts a form and decodes the JSON response.
+// OAuth 2.0 errors are returned with a 400 status and a JSON body, so those are decoded too.
+func (l *oidcLogin) postForm(ctx context.Context, endpoint string, form url.Values, into any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := l.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading response from %q: %w", endpoint, err)
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusBadRequest && resp.StatusCode != http.StatusUnauthorized {
+		return fmt.Errorf("unexpected status %q from %q", resp.Status, endpoint)
+	}
+	if err := json.Unmarshal(b, into); err != nil {
+		return fmt.Errorf("error parsing response from %q: %w", endpoint, err)
+	}
+	return nil
+}
+
+func (r *oidcTokenResponse) asError() error {
+	if r.ErrorDescription != "" {
+		return fmt.Errorf("OIDC issuer returned error %q: %s", r.Error, r.ErrorDescription)
+	}
+	return fmt.Errorf("OIDC issuer returned error %q", r.Error)
+}
+
+// idTokenExpiry returns the exp claim of a JWT, without verifying it; the API server does that.
+func idTokenExpiry(idToken string) (time.Time, error) {
+	parts := strings.Split(idToken, ".")
+	if len(parts) != 3 {
+		return time.Time{}, fmt.Errorf("id_token is not a JWT")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error decoding id_token payload: %w", err)
+	}
+	claims := struct {
+		Exp int64 `json:"exp"`
+	}{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return time.Time{}, fmt.Errorf("error parsing id_token payload: %w", err)
+	}
+	if claims.Exp == 0 {
+		return time.Time{}, fmt.Errorf("id_token does not have an exp claim")
+	}
+	return time.Unix(claims.Exp, 0), nil
+}
+
+func oidcCacheFilePath(issuerURL string, clientID string, scopes []string) string {
+	var b bytes.Buffer
+	b.WriteString(issuerURL)
+	b.WriteByte(0)
+	b.WriteString(clientID)
+	b.WriteByte(0)
+	b.WriteString(strings.Join(scopes, " "))
+	b.WriteByte(0)
+
+	var i big.Int
+	hb := sha256.Sum224(b.Bytes())
+	i.SetBytes(hb[:])
+	return filepath.Join(homedir.HomeDir(), ".kube", "cache", "kops-oidc", i.Text(62))
+}
+
+func loadCachedOIDCToken(cacheFilePath string) (*oidcCachedToken, error) {
+	b, err := os.ReadFile(cacheFilePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// expected - a cache miss
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	token := &oidcCachedToken{}
+	if err := json.Unmarshal(b, token); err != nil {
+		return nil, fmt.Errorf("error parsing: %v", err)
+	}
+	if token.IDToken == "" {
+		return nil, fmt.Errorf("no token in cached file")
+	}
+	return token, nil
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
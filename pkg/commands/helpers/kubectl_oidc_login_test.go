@@ -0,0 +1,194 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func fakeIDToken(exp time.Time) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256"}`))
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"sub":"alice","exp":%d}`, exp.Unix())))
+	return header + "." + payload + ".c2ln"
+}
+
+func TestKubectlOIDCLogin(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	now := time.Unix(1700000000, 0)
+	pending := 2
+	var deviceRequests, refreshRequests int
+	var issuedToken string
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	writeJSON := func(w http.ResponseWriter, status int, v any) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(v)
+	}
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]any{
+			"issuer":                        server.URL,
+			"device_authorization_endpoint": server.URL + "/device",
+			"token_endpoint":                server.URL + "/token",
+		})
+	})
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		deviceRequests++
+		if got := r.FormValue("scope"); got != "openid offline_access groups" {
+			t.Errorf("unexpected scope %q", got)
+		}
+		writeJSON(w, http.StatusOK, map[string]any{
+			"device_code":      "device-code",
+			"user_code":        "ABCD-EFGH",
+			"verification_uri": server.URL + "/activate",
+			"expires_in":       600,
+			"interval":         1,
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("client_id") != "kubernetes" {
+			t.Errorf("unexpected client_id %q", r.FormValue("client_id"))
+		}
+		switch r.FormValue("grant_type") {
+		case "urn:ietf:params:oauth:grant-type:device_code":
+			if pending > 0 {
+				pending--
+				writeJSON(w, http.StatusBadRequest, map[string]any{"error": "authorization_pending"})
+				return
+			}
+			issuedToken = fakeIDToken(now.Add(time.Hour))
+			writeJSON(w, http.StatusOK, map[string]any{"id_token": issuedToken, "refresh_token": "refresh-1"})
+		case "refresh_token":
+			refreshRequests++
+			if r.FormValue("refresh_token") != "refresh-1" {
+				writeJSON(w, http.StatusBadRequest, map[string]any{"error": "invalid_grant"})
+				return
+			}
+			issuedToken = fakeIDToken(now.Add(time.Hour))
+			writeJSON(w, http.StatusOK, map[string]any{"id_token": issuedToken})
+		default:
+			t.Errorf("unexpected grant_type %q", r.FormValue("grant_type"))
+		}
+	})
+
+	var prompt bytes.Buffer
+	l := &oidcLogin{
+		httpClient: server.Client(),
+		errOut:     &prompt,
+		now:        func() time.Time { return now },
+		sleep:      func(ctx context.Context, d time.Duration) error { return nil },
+	}
+	options := &HelperKubectlOIDCLoginOptions{
+		IssuerURL:   server.URL,
+		ClientID:    "kubernetes",
+		ExtraScopes: []string{"groups"},
+	}
+	options.InitDefaults()
+
+	login := func() *ExecCredential {
+		t.Helper()
+		var out bytes.Buffer
+		if err := l.run(context.Background(), &out, options); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		execCredential := &ExecCredential{}
+		if err := json.Unmarshal(out.Bytes(), execCredential); err != nil {
+			t.Fatalf("error parsing output: %v", err)
+		}
+		return execCredential
+	}
+
+	// The first login uses the device flow
+	execCredential := login()
+	if execCredential.APIVersion != "client.authentication.k8s.io/v1beta1" {
+		t.Errorf("unexpected apiVersion %q", execCredential.APIVersion)
+	}
+	if execCredential.Status.Token != issuedToken {
+		t.Errorf("unexpected token %q", execCredential.Status.Token)
+	}
+	if !execCredential.Status.ExpirationTimestamp.Equal(now.Add(time.Hour)) {
+		t.Errorf("unexpected expiration %v", execCredential.Status.ExpirationTimestamp)
+	}
+	if !strings.Contains(prompt.String(), "ABCD-EFGH") {
+		t.Errorf("prompt did not include the user code: %q", prompt.String())
+	}
+
+	// The second login is served from the cache
+	login()
+	if deviceRequests != 1 || refreshRequests != 0 {
+		t.Errorf("expected cached token, got %d device and %d refresh requests", deviceRequests, refreshRequests)
+	}
+
+	// Once the token has expired, it is refreshed
+	now = now.Add(2 * time.Hour)
+	execCredential = login()
+	if deviceRequests != 1 || refreshRequests != 1 {
+		t.Errorf("expected refreshed token, got %d device and %d refresh requests", deviceRequests, refreshRequests)
+	}
+	if !execCredential.Status.ExpirationTimestamp.Equal(now.Add(time.Hour)) {
+		t.Errorf("unexpected expiration %v", execCredential.Status.ExpirationTimestamp)
+	}
+}
+
+func TestKubectlOIDCLoginDenied(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"device_authorization_endpoint":%q,"token_endpoint":%q}`, server.URL+"/device", server.URL+"/token")
+	})
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"device_code":"d","user_code":"u","verification_uri":"https://example.com/activate"}`)
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, `{"error":"access_denied"}`)
+	})
+
+	l := &oidcLogin{
+		httpClient: server.Client(),
+		errOut:     &bytes.Buffer{},
+		now:        time.Now,
+		sleep:      func(ctx context.Context, d time.Duration) error { return nil },
+	}
+	options := &HelperKubectlOIDCLoginOptions{
+		IssuerURL: server.URL,
+		ClientID:  "kubernetes",
+	}
+	options.InitDefaults()
+
+	err := l.run(context.Background(), &bytes.Buffer{}, options)
+	if err == nil || !strings.Contains(err.Error(), "denied") {
+		t.Errorf("expected access denied error, got %v", err)
+	}
+}
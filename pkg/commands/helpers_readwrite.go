@@ -68,34 +68,51 @@ func UpdateCluster(ctx context.Context, clientset simple.Clientset, cluster *kop
 
 // UpdateInstanceGroup writes the updated instance group to the state store after performing validation
 func UpdateInstanceGroup(ctx context.Context, clientset simple.Clientset, cluster *kops.Cluster, allInstanceGroups []*kops.InstanceGroup, instanceGroupToUpdate *kops.InstanceGroup) error {
-	cloud, err := cloudup.BuildCloud(cluster)
-	if err != nil {
+	if err := validateInstanceGroup(ctx, clientset, cluster, allInstanceGroups, instanceGroupToUpdate); err != nil {
 		return err
 	}
 
-	err = cloudup.PerformAssignments(cluster, clientset.VFSContext(), cloud)
+	// Validation was successful so commit the changed instance group.
+	_, err := clientset.InstanceGroupsFor(cluster).Update(ctx, instanceGroupToUpdate, metav1.UpdateOptions{})
 	if err != nil {
-		return fmt.Errorf("error populating configuration: %v", err)
+		return err
 	}
 
-	assetBuilder := assets.NewAssetBuilder(clientset.VFSContext(), cluster.Spec.Assets, false)
-	fullCluster, err := cloudup.PopulateClusterSpec(ctx, clientset, cluster, allInstanceGroups, cloud, assetBuilder)
+	return nil
+}
+
+// CreateInstanceGroup writes a new instance group to the state store after performing validation
+func CreateInstanceGroup(ctx context.Context, clientset simple.Clientset, cluster *kops.Cluster, allInstanceGroups []*kops.InstanceGroup, instanceGroupToCreate *kops.InstanceGroup) error {
+	if err := validateInstanceGroup(ctx, clientset, cluster, allInstanceGroups, instanceGroupToCreate); err != nil {
+		return err
+	}
+
+	_, err := clientset.InstanceGroupsFor(cluster).Create(ctx, instanceGroupToCreate, metav1.CreateOptions{})
 	if err != nil {
 		return err
 	}
 
-	err = validation.CrossValidateInstanceGroup(instanceGroupToUpdate, fullCluster, cloud, false).ToAggregate()
+	return nil
+}
+
+func validateInstanceGroup(ctx context.Context, clientset simple.Clientset, cluster *kops.Cluster, allInstanceGroups []*kops.InstanceGroup, instanceGroup *kops.InstanceGroup) error {
+	cloud, err := cloudup.BuildCloud(cluster)
 	if err != nil {
 		return err
 	}
 
-	// Validation was successful so commit the changed instance group.
-	_, err = clientset.InstanceGroupsFor(cluster).Update(ctx, instanceGroupToUpdate, metav1.UpdateOptions{})
+	err = cloudup.PerformAssignments(cluster, clientset.VFSContext(), cloud)
+	if err != nil {
+		return fmt.Errorf("error populating configuration: %v", err)
+	}
+
+	assetBuilder := assets.NewAssetBuilder(clientset.VFSContext(), cluster.Spec.Assets, false)
+	fullCluster, err := cloudup.PopulateClusterSpec(ctx, clientset, cluster, allInstanceGroups, cloud, assetBuilder)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return validation.CrossValidateInstanceGroup(instanceGroup, fullCluster, cloud, false).ToAggregate()
 }
 
 // ReadAllInstanceGroups reads all the instance groups for the cluster
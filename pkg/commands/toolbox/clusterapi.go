@@ -27,10 +27,12 @@ import (
 
 func BuildClusterAPICommand(f commandutils.Factory, out io.Writer) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "clusterapi",
-		Short: i18n.T(`ClusterAPI commands`),
+		Use:     "clusterapi",
+		Aliases: []string{"capi"},
+		Short:   i18n.T(`ClusterAPI commands`),
 	}
 
+	cmd.AddCommand(clusterapi.BuildExportCommand(f, out))
 	cmd.AddCommand(clusterapi.BuildGenerateCommand(f, out))
 
 	return cmd
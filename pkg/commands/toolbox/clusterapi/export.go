@@ -0,0 +1,218 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterapi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/klog/v2"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"k8s.io/kops/clusterapi/pkg/builders"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+type ExportOptions struct {
+	ClusterName string
+
+	// InstanceGroups are the names of the InstanceGroups to export; all the node InstanceGroups if empty
+	InstanceGroups []string
+
+	// Namespace is the namespace for the Cluster API objects
+	Namespace string
+
+	// HealthCheck creates a MachineHealthCheck for each InstanceGroup
+	HealthCheck bool
+	// MaxUnhealthy stops remediation while more than this many machines of an InstanceGroup are unhealthy
+	MaxUnhealthy string
+	// UnhealthyTimeout is how long a node can be not Ready before its machine is remediated
+	UnhealthyTimeout time.Duration
+	// NodeStartupTimeout is how long a machine can take to join the cluster before it is remediated
+	NodeStartupTimeout time.Duration
+}
+
+func (o *ExportOptions) InitDefaults() {
+	o.Namespace = "kube-system"
+	o.HealthCheck = true
+	o.MaxUnhealthy = "40%"
+	o.UnhealthyTimeout = 5 * time.Minute
+	o.NodeStartupTimeout = 10 * time.Minute
+}
+
+func BuildExportCommand(f commandutils.Factory, out io.Writer) *cobra.Command {
+	options := &ExportOptions{}
+	options.InitDefaults()
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: i18n.T(`Export instance groups as Cluster API MachineDeployments`),
+		Long: templates.LongDesc(i18n.T(`
+			Export the node instance groups of a cluster as Cluster API objects:
+			a MachineDeployment per zone, with a rollout strategy mapped from the rolling update settings,
+			and a MachineHealthCheck per instance group.
+
+			The machines get their configuration from the instance group, which must be kept
+			(scaled to zero) after the machines have replaced its instances.`)),
+		Example: templates.Examples(i18n.T(`
+			kops toolbox clusterapi export --cluster k8s-cluster.example.com --instance-group nodes | kubectl apply --server-side -f -
+		`)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunExport(cmd.Context(), f, out, options)
+		},
+	}
+
+	cmd.Flags().StringVar(&options.ClusterName, "cluster", options.ClusterName, "Name of cluster to export")
+	cmd.Flags().StringSliceVar(&options.InstanceGroups, "instance-group", options.InstanceGroups, "Instance groups to export (if not specified, all the node instance groups)")
+	cmd.Flags().StringVar(&options.Namespace, "namespace", options.Namespace, "Namespace for objects")
+	cmd.Flags().BoolVar(&options.HealthCheck, "health-check", options.HealthCheck, "Create a MachineHealthCheck for each instance group")
+	cmd.Flags().StringVar(&options.MaxUnhealthy, "max-unhealthy", options.MaxUnhealthy, "Stop remediation while more than this number or percentage of the machines of an instance group are unhealthy")
+	cmd.Flags().DurationVar(&options.UnhealthyTimeout, "unhealthy-timeout", options.UnhealthyTimeout, "How long a node can be not Ready before its machine is remediated")
+	cmd.Flags().DurationVar(&options.NodeStartupTimeout, "node-startup-timeout", options.NodeStartupTimeout, "How long a machine can take to join the cluster before it is remediated")
+	return cmd
+}
+
+func RunExport(ctx context.Context, f commandutils.Factory, out io.Writer, options *ExportOptions) error {
+	if options.ClusterName == "" {
+		return fmt.Errorf("cluster is required")
+	}
+
+	clientset, err := f.KopsClient()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := clientset.GetCluster(ctx, options.ClusterName)
+	if err != nil {
+		return err
+	}
+	if cluster.GetCloudProvider() != kops.CloudProviderGCE {
+		return fmt.Errorf("exporting to Cluster API is only supported on GCE")
+	}
+
+	instanceGroups, err := clientset.InstanceGroupsFor(cluster).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	var healthCheck *builders.HealthCheck
+	if options.HealthCheck {
+		healthCheck = &builders.HealthCheck{
+			UnhealthyTimeout:   options.UnhealthyTimeout,
+			NodeStartupTimeout: options.NodeStartupTimeout,
+			MaxUnhealthy:       intstr.Parse(options.MaxUnhealthy),
+		}
+	}
+
+	var objects []*unstructured.Unstructured
+	found := 0
+	for i := range instanceGroups.Items {
+		ig := &instanceGroups.Items[i]
+		if len(options.InstanceGroups) != 0 && !slices.Contains(options.InstanceGroups, ig.Name) {
+			continue
+		}
+		found++
+		if ig.Spec.Role != kops.InstanceGroupRoleNode {
+			if len(options.InstanceGroups) != 0 {
+				return fmt.Errorf("instance group %q has role %q; only node instance groups can be exported", ig.Name, ig.Spec.Role)
+			}
+			continue
+		}
+
+		b, err := machineDeploymentBuilderForInstanceGroup(cluster, ig)
+		if err != nil {
+			return err
+		}
+		b.Namespace = options.Namespace
+		b.HealthCheck = healthCheck
+
+		igObjects, err := b.BuildObjects(ctx)
+		if err != nil {
+			return fmt.Errorf("building objects for instance group %q: %w", ig.Name, err)
+		}
+		objects = append(objects, igObjects...)
+	}
+	if found < len(options.InstanceGroups) {
+		return fmt.Errorf("instance groups %v not all found in cluster %q", options.InstanceGroups, options.ClusterName)
+	}
+	if len(objects) == 0 {
+		return fmt.Errorf("no node instance groups found in cluster %q", options.ClusterName)
+	}
+
+	return writeObjects(out, objects)
+}
+
+// machineDeploymentBuilderForInstanceGroup builds the Cluster API objects equivalent to a node InstanceGroup.
+func machineDeploymentBuilderForInstanceGroup(cluster *kops.Cluster, ig *kops.InstanceGroup) (*builders.MachineDeploymentBuilder, error) {
+	if len(ig.Spec.Zones) == 0 {
+		return nil, fmt.Errorf("instance group %q has no zones", ig.Name)
+	}
+	if len(ig.Spec.Subnets) == 0 {
+		return nil, fmt.Errorf("instance group %q has no subnets", ig.Name)
+	}
+	if len(ig.Spec.Subnets) > 1 {
+		klog.Warningf("instance group %q has multiple subnets; using %q", ig.Name, ig.Spec.Subnets[0])
+	}
+
+	replicas := int(fi.ValueOf(ig.Spec.MinSize))
+	if ig.Spec.MinSize == nil {
+		replicas = 1
+	}
+	if ig.Spec.MaxSize != nil && int(*ig.Spec.MaxSize) > replicas {
+		klog.Warningf("instance group %q can scale up to %d instances; the MachineDeployments are created with its minimum size of %d", ig.Name, *ig.Spec.MaxSize, replicas)
+	}
+
+	rollingUpdate := &kops.RollingUpdate{}
+	if cluster.Spec.RollingUpdate != nil {
+		*rollingUpdate = *cluster.Spec.RollingUpdate
+	}
+	if igRollingUpdate := ig.Spec.RollingUpdate; igRollingUpdate != nil {
+		if igRollingUpdate.DrainAndTerminate != nil {
+			rollingUpdate.DrainAndTerminate = igRollingUpdate.DrainAndTerminate
+		}
+		if igRollingUpdate.MaxSurge != nil {
+			rollingUpdate.MaxSurge = igRollingUpdate.MaxSurge
+		}
+		if igRollingUpdate.MaxUnavailable != nil {
+			rollingUpdate.MaxUnavailable = igRollingUpdate.MaxUnavailable
+		}
+	}
+
+	return &builders.MachineDeploymentBuilder{
+		ClusterName:       cluster.GetName(),
+		Name:              ig.Name,
+		Replicas:          replicas,
+		Zones:             ig.Spec.Zones,
+		MachineType:       ig.Spec.MachineType,
+		Subnet:            ig.Spec.Subnets[0],
+		Image:             ig.Spec.Image,
+		Role:              ig.Spec.Role,
+		KubernetesVersion: cluster.Spec.KubernetesVersion,
+		InstanceGroupName: ig.Name,
+		RollingUpdate:     rollingUpdate,
+	}, nil
+}
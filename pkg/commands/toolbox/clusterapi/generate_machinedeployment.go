@@ -23,6 +23,7 @@ import (
 
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 	"k8s.io/kubectl/pkg/util/i18n"
@@ -228,6 +229,11 @@ func RunGenerateMachineDeployment(ctx context.Context, f commandutils.Factory, o
 		return err
 	}
 
+	return writeObjects(out, objects)
+}
+
+// writeObjects writes the objects as a multi-document YAML stream.
+func writeObjects(out io.Writer, objects []*unstructured.Unstructured) error {
 	for i, obj := range objects {
 		b, err := yaml.Marshal(obj.Object)
 		if err != nil {
@@ -0,0 +1,78 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envelope
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"k8s.io/kops/util/pkg/awslog"
+)
+
+// awsKMS wraps data keys with an AWS KMS key.
+type awsKMS struct {
+	keyID  string
+	client *kms.Client
+}
+
+func newAWSKMS(ctx context.Context, keyID string) (*awsKMS, error) {
+	var opts []func(*awsconfig.LoadOptions) error
+	opts = append(opts, awslog.WithAWSLogger())
+	// The region is taken from the key ARN, so that nodes in another region can decrypt.
+	if strings.HasPrefix(keyID, "arn:") {
+		parsed, err := arn.Parse(keyID)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing KMS key ARN %q: %w", keyID, err)
+		}
+		opts = append(opts, awsconfig.WithRegion(parsed.Region))
+	} else if region := os.Getenv("AWS_REGION"); region != "" {
+		opts = append(opts, awsconfig.WithRegion(region))
+	}
+
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error loading AWS config: %w", err)
+	}
+	return &awsKMS{keyID: keyID, client: kms.NewFromConfig(cfg)}, nil
+}
+
+func (p *awsKMS) WrapKey(ctx context.Context, dataKey []byte) (string, []byte, error) {
+	resp, err := p.client.Encrypt(ctx, &kms.EncryptInput{
+		KeyId:     &p.keyID,
+		Plaintext: dataKey,
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	return p.keyID, resp.CiphertextBlob, nil
+}
+
+func (p *awsKMS) UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	resp, err := p.client.Decrypt(ctx, &kms.DecryptInput{
+		KeyId:          &keyID,
+		CiphertextBlob: wrapped,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Plaintext, nil
+}
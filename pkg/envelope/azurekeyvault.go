@@ -0,0 +1,103 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envelope
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+// azureKeyVault wraps data keys with an RSA key in Azure Key Vault, identified by its URL:
+// https://<vault>.vault.azure.net/keys/<name>[/<version>]
+type azureKeyVault struct {
+	keyID      string
+	credential *azidentity.DefaultAzureCredential
+}
+
+const azureKeyVaultAPIVersion = "7.4"
+
+func newAzureKeyVault(keyID string) (*azureKeyVault, error) {
+	// The key ID is the URL that the Azure token is sent to, so it must be a key vault.
+	u, err := url.Parse(keyID)
+	if err != nil || u.Scheme != "https" || !strings.HasSuffix(u.Hostname(), ".vault.azure.net") || u.Port() != "" || !strings.HasPrefix(u.Path, "/keys/") || u.RawQuery != "" || u.Fragment != "" {
+		return nil, fmt.Errorf("azure key vault key ID must be the URL of the key, https://<vault>.vault.azure.net/keys/<name>, was %q", keyID)
+	}
+	credential, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		return nil, fmt.Errorf("error building Azure credential: %w", err)
+	}
+	return &azureKeyVault{keyID: strings.TrimSuffix(keyID, "/"), credential: credential}, nil
+}
+
+type azureKeyOperation struct {
+	Alg   string `json:"alg,omitempty"`
+	Value string `json:"value"`
+	KID   string `json:"kid,omitempty"`
+}
+
+func (p *azureKeyVault) do(ctx context.Context, keyID string, operation string, value []byte) (*azureKeyOperation, error) {
+	token, err := p.credential.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{"https://vault.azure.net/.default"}})
+	if err != nil {
+		return nil, fmt.Errorf("error getting Azure token: %w", err)
+	}
+	request := &azureKeyOperation{
+		Alg:   "RSA-OAEP-256",
+		Value: base64.RawURLEncoding.EncodeToString(value),
+	}
+	response := &azureKeyOperation{}
+	u := keyID + "/" + operation + "?api-version=" + azureKeyVaultAPIVersion
+	if err := postJSON(ctx, httpClient, u, map[string]string{"Authorization": "Bearer " + token.Token}, request, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (p *azureKeyVault) WrapKey(ctx context.Context, dataKey []byte) (string, []byte, error) {
+	response, err := p.do(ctx, p.keyID, "wrapkey", dataKey)
+	if err != nil {
+		return "", nil, err
+	}
+	wrapped, err := base64.RawURLEncoding.DecodeString(response.Value)
+	if err != nil {
+		return "", nil, fmt.Errorf("error decoding wrapped key: %w", err)
+	}
+	// The response identifies the key version, which is needed to unwrap.
+	keyID := p.keyID
+	if response.KID != "" {
+		keyID = response.KID
+	}
+	return keyID, wrapped, nil
+}
+
+func (p *azureKeyVault) UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	// The key ID must be a version of the configured key, because the Azure token is sent to it.
+	if !keyMatches(&kops.ConfigStoreEncryptionSpec{Provider: ProviderAzureKeyVault, KeyID: p.keyID}, keyID) {
+		return nil, fmt.Errorf("data key was wrapped with azure key vault key %q", keyID)
+	}
+	response, err := p.do(ctx, keyID, "unwrapkey", wrapped)
+	if err != nil {
+		return nil, err
+	}
+	return base64.RawURLEncoding.DecodeString(response.Value)
+}
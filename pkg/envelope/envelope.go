@@ -0,0 +1,249 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package envelope implements the client-side envelope encryption of the objects in the state store.
+//
+// Each object is encrypted with AES-256-GCM under its own random data key, and the data key is wrapped
+// with a key encryption key held by a provider such as AWS KMS. The encrypted object records the provider
+// and key that wrapped its data key, but readers only use the provider and key configured for the cluster,
+// and the path of the object is authenticated with its content.
+package envelope
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+// header is the first line of an encrypted object.
+var header = []byte("kops-envelope:v1\n")
+
+// Provider wraps and unwraps data keys with a key encryption key.
+type Provider interface {
+	// WrapKey wraps the data key, returning the ID of the key that wrapped it.
+	WrapKey(ctx context.Context, dataKey []byte) (string, []byte, error)
+	// UnwrapKey unwraps a data key that was wrapped by the key with the ID.
+	UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error)
+}
+
+// Supported providers
+const (
+	ProviderAWSKMS        = "aws-kms"
+	ProviderGCPKMS        = "gcp-kms"
+	ProviderAzureKeyVault = "azure-keyvault"
+	ProviderVaultTransit  = "vault-transit"
+	ProviderLocal         = "local"
+)
+
+// Providers are the supported providers.
+var Providers = []string{ProviderAWSKMS, ProviderGCPKMS, ProviderAzureKeyVault, ProviderVaultTransit, ProviderLocal}
+
+// NewProvider builds the provider with the name, for the key encryption key with the ID.
+func NewProvider(ctx context.Context, name string, keyID string) (Provider, error) {
+	if keyID == "" {
+		return nil, fmt.Errorf("key ID is required for %s", name)
+	}
+	switch name {
+	case ProviderAWSKMS:
+		return newAWSKMS(ctx, keyID)
+	case ProviderGCPKMS:
+		return newGCPKMS(ctx, keyID)
+	case ProviderAzureKeyVault:
+		return newAzureKeyVault(keyID)
+	case ProviderVaultTransit:
+		return newVaultTransit(keyID)
+	case ProviderLocal:
+		return newLocal(keyID)
+	default:
+		return nil, fmt.Errorf("unknown state store encryption provider %q", name)
+	}
+}
+
+// envelope is the encoding of an encrypted object, after the header.
+type envelope struct {
+	Provider   string `json:"provider"`
+	KeyID      string `json:"keyID"`
+	WrappedKey []byte `json:"wrappedKey"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// IsEncrypted returns true if the data is an encrypted object.
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, header)
+}
+
+// Encrypt encrypts the data of the object at path p as configured by the spec; the data is returned as is if the spec is nil.
+// The path is authenticated with the data, so that the encrypted object cannot be copied over another object.
+func Encrypt(ctx context.Context, spec *kops.ConfigStoreEncryptionSpec, p string, data []byte) ([]byte, error) {
+	if spec == nil {
+		return data, nil
+	}
+
+	provider, err := NewProvider(ctx, spec.Provider, spec.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, fmt.Errorf("error generating data key: %w", err)
+	}
+
+	keyID, wrapped, err := provider.WrapKey(ctx, dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("error wrapping data key with %s key %q: %w", spec.Provider, spec.KeyID, err)
+	}
+
+	e := &envelope{
+		Provider:   spec.Provider,
+		KeyID:      keyID,
+		WrappedKey: wrapped,
+	}
+	e.Nonce, e.Ciphertext, err = seal(dataKey, data, []byte(p))
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		return nil, fmt.Errorf("error serializing encrypted object: %w", err)
+	}
+	return append(append([]byte{}, header...), b...), nil
+}
+
+// Decrypt decrypts the data of the object at path p, which must be encrypted with the key configured by the spec.
+// If the spec is nil, the object must not be encrypted, and is returned as is.
+// Plaintext objects are rejected when encryption is configured, so that they cannot replace encrypted objects.
+func Decrypt(ctx context.Context, spec *kops.ConfigStoreEncryptionSpec, p string, data []byte) ([]byte, error) {
+	if !IsEncrypted(data) {
+		if spec != nil {
+			return nil, fmt.Errorf("object %q is not encrypted, but state store encryption is configured; run \"kops toolbox encrypt-state-store\" to encrypt it", p)
+		}
+		return data, nil
+	}
+	if spec == nil {
+		return nil, fmt.Errorf("object %q is encrypted, but state store encryption is not configured", p)
+	}
+
+	e := &envelope{}
+	if err := json.Unmarshal(data[len(header):], e); err != nil {
+		return nil, fmt.Errorf("error parsing encrypted object: %w", err)
+	}
+	if e.Provider != spec.Provider || !keyMatches(spec, e.KeyID) {
+		return nil, fmt.Errorf("object %q was encrypted with %s key %q, but the configured key is %s key %q", p, e.Provider, e.KeyID, spec.Provider, spec.KeyID)
+	}
+
+	dataKey, err := unwrapKey(ctx, spec, e)
+	if err != nil {
+		return nil, err
+	}
+
+	return open(dataKey, e.Nonce, e.Ciphertext, []byte(p))
+}
+
+// keyMatches returns true if the key with the ID recorded in an envelope is the key configured by the spec.
+// Azure Key Vault records the version of the key, which must be a version of the configured key.
+func keyMatches(spec *kops.ConfigStoreEncryptionSpec, keyID string) bool {
+	if keyID == spec.KeyID {
+		return true
+	}
+	if spec.Provider == ProviderAzureKeyVault {
+		version, found := strings.CutPrefix(keyID, strings.TrimSuffix(spec.KeyID, "/")+"/")
+		return found && version != "" && !strings.Contains(version, "/")
+	}
+	return false
+}
+
+// maxDataKeys bounds the number of cached data keys.
+const maxDataKeys = 1024
+
+// dataKeys caches the unwrapped data keys, by provider, key and wrapped key,
+// so that reading an object repeatedly does not call the provider each time.
+var dataKeys = struct {
+	sync.Mutex
+	keys map[string][]byte
+}{keys: make(map[string][]byte)}
+
+func unwrapKey(ctx context.Context, spec *kops.ConfigStoreEncryptionSpec, e *envelope) ([]byte, error) {
+	cacheKey := spec.Provider + "\x00" + e.KeyID + "\x00" + string(e.WrappedKey)
+	dataKeys.Lock()
+	dataKey, found := dataKeys.keys[cacheKey]
+	dataKeys.Unlock()
+	if found {
+		return dataKey, nil
+	}
+
+	provider, err := NewProvider(ctx, spec.Provider, spec.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	dataKey, err = provider.UnwrapKey(ctx, e.KeyID, e.WrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("error unwrapping data key with %s key %q: %w", spec.Provider, e.KeyID, err)
+	}
+
+	dataKeys.Lock()
+	if len(dataKeys.keys) >= maxDataKeys {
+		dataKeys.keys = make(map[string][]byte)
+	}
+	dataKeys.keys[cacheKey] = dataKey
+	dataKeys.Unlock()
+	return dataKey, nil
+}
+
+func seal(key []byte, plaintext []byte, additionalData []byte) ([]byte, []byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, fmt.Errorf("error generating nonce: %w", err)
+	}
+	return nonce, aead.Seal(nil, nonce, plaintext, additionalData), nil
+}
+
+func open(key []byte, nonce []byte, ciphertext []byte, additionalData []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("invalid nonce in encrypted object")
+	}
+	plaintext, err := aead.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting object: %w", err)
+	}
+	return plaintext, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid data key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
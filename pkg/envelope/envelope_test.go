@@ -0,0 +1,250 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envelope
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+const testPath = "memfs://tests/cluster.example.com/secrets/admin"
+
+func writeLocalKey(t *testing.T) string {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+	p := filepath.Join(t.TempDir(), "state-store.key")
+	if err := os.WriteFile(p, []byte(base64.StdEncoding.EncodeToString(key)+"\n"), 0600); err != nil {
+		t.Fatalf("error writing key: %v", err)
+	}
+	return p
+}
+
+func TestRoundTripLocal(t *testing.T) {
+	ctx := context.Background()
+	spec := &kops.ConfigStoreEncryptionSpec{Provider: ProviderLocal, KeyID: writeLocalKey(t)}
+	plaintext := []byte(`{"Data":"c2VjcmV0"}`)
+
+	encrypted, err := Encrypt(ctx, spec, testPath, plaintext)
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if !IsEncrypted(encrypted) {
+		t.Errorf("expected encrypted object to have header")
+	}
+	if bytes.Contains(encrypted, plaintext) {
+		t.Errorf("encrypted object contains the plaintext")
+	}
+
+	decrypted, err := Decrypt(ctx, spec, testPath, encrypted)
+	if err != nil {
+		t.Fatalf("Decrypt failed: %v", err)
+	}
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Errorf("unexpected plaintext %q", decrypted)
+	}
+}
+
+func TestPlaintextPassthrough(t *testing.T) {
+	ctx := context.Background()
+	plaintext := []byte("apiVersion: kops.k8s.io/v1alpha2\nkind: Keyset\n")
+
+	encrypted, err := Encrypt(ctx, nil, testPath, plaintext)
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if !bytes.Equal(encrypted, plaintext) {
+		t.Errorf("expected data to be unchanged without encryption")
+	}
+
+	decrypted, err := Decrypt(ctx, nil, testPath, plaintext)
+	if err != nil {
+		t.Fatalf("Decrypt failed: %v", err)
+	}
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Errorf("expected plaintext data to be returned as is")
+	}
+}
+
+func TestDecryptOtherPath(t *testing.T) {
+	ctx := context.Background()
+	spec := &kops.ConfigStoreEncryptionSpec{Provider: ProviderLocal, KeyID: writeLocalKey(t)}
+
+	encrypted, err := Encrypt(ctx, spec, testPath, []byte("secret"))
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if _, err := Decrypt(ctx, spec, "memfs://tests/cluster.example.com/secrets/kube", encrypted); err == nil {
+		t.Errorf("expected error decrypting object copied to another path")
+	}
+}
+
+func TestDecryptPlaintextWithEncryption(t *testing.T) {
+	ctx := context.Background()
+	spec := &kops.ConfigStoreEncryptionSpec{Provider: ProviderLocal, KeyID: writeLocalKey(t)}
+
+	if _, err := Decrypt(ctx, spec, testPath, []byte(`{"Data":"c2VjcmV0"}`)); err == nil {
+		t.Errorf("expected error reading plaintext object when encryption is configured")
+	}
+}
+
+func TestDecryptWithoutEncryption(t *testing.T) {
+	ctx := context.Background()
+	spec := &kops.ConfigStoreEncryptionSpec{Provider: ProviderLocal, KeyID: writeLocalKey(t)}
+
+	encrypted, err := Encrypt(ctx, spec, testPath, []byte("secret"))
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if _, err := Decrypt(ctx, nil, testPath, encrypted); err == nil {
+		t.Errorf("expected error reading encrypted object when encryption is not configured")
+	}
+}
+
+func TestDecryptOtherKey(t *testing.T) {
+	ctx := context.Background()
+	attackerSpec := &kops.ConfigStoreEncryptionSpec{Provider: ProviderLocal, KeyID: writeLocalKey(t)}
+	spec := &kops.ConfigStoreEncryptionSpec{Provider: ProviderLocal, KeyID: writeLocalKey(t)}
+
+	// The envelope names a key other than the configured one, which must not be used.
+	encrypted, err := Encrypt(ctx, attackerSpec, testPath, []byte("secret"))
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	_, err = Decrypt(ctx, spec, testPath, encrypted)
+	if err == nil || !strings.Contains(err.Error(), "configured key") {
+		t.Errorf("expected error decrypting object encrypted with another key, got %v", err)
+	}
+}
+
+func TestKeyMatches(t *testing.T) {
+	azure := &kops.ConfigStoreEncryptionSpec{Provider: ProviderAzureKeyVault, KeyID: "https://kops.vault.azure.net/keys/state"}
+	aws := &kops.ConfigStoreEncryptionSpec{Provider: ProviderAWSKMS, KeyID: "arn:aws:kms:us-east-1:123456789012:key/abcd"}
+	grid := []struct {
+		spec     *kops.ConfigStoreEncryptionSpec
+		keyID    string
+		expected bool
+	}{
+		{spec: azure, keyID: "https://kops.vault.azure.net/keys/state", expected: true},
+		{spec: azure, keyID: "https://kops.vault.azure.net/keys/state/0123456789abcdef", expected: true},
+		{spec: azure, keyID: "https://kops.vault.azure.net/keys/state/0123/unwrapkey", expected: false},
+		{spec: azure, keyID: "https://kops.vault.azure.net/keys/statefoo", expected: false},
+		{spec: azure, keyID: "https://attacker.example.com/keys/state", expected: false},
+		{spec: aws, keyID: "arn:aws:kms:us-east-1:123456789012:key/abcd", expected: true},
+		{spec: aws, keyID: "arn:aws:kms:us-east-1:123456789012:key/abcd/1", expected: false},
+	}
+	for _, g := range grid {
+		if actual := keyMatches(g.spec, g.keyID); actual != g.expected {
+			t.Errorf("keyMatches(%q, %q) = %v, expected %v", g.spec.KeyID, g.keyID, actual, g.expected)
+		}
+	}
+}
+
+func TestAzureKeyVaultKeyID(t *testing.T) {
+	for _, keyID := range []string{
+		"http://kops.vault.azure.net/keys/state",
+		"https://attacker.example.com/keys/state",
+		"https://kops.vault.azure.net.example.com/keys/state",
+		"https://kops.vault.azure.net:8443/keys/state",
+		"https://kops.vault.azure.net/secrets/state",
+	} {
+		if _, err := newAzureKeyVault(keyID); err == nil {
+			t.Errorf("expected error for azure key vault key ID %q", keyID)
+		}
+	}
+}
+
+func TestDecryptTampered(t *testing.T) {
+	ctx := context.Background()
+	spec := &kops.ConfigStoreEncryptionSpec{Provider: ProviderLocal, KeyID: writeLocalKey(t)}
+
+	encrypted, err := Encrypt(ctx, spec, testPath, []byte("secret"))
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+
+	e := &envelope{}
+	if err := json.Unmarshal(encrypted[len(header):], e); err != nil {
+		t.Fatalf("error parsing envelope: %v", err)
+	}
+	e.Ciphertext[0] ^= 0xff
+	b, err := json.Marshal(e)
+	if err != nil {
+		t.Fatalf("error serializing envelope: %v", err)
+	}
+
+	if _, err := Decrypt(ctx, spec, testPath, append(append([]byte{}, header...), b...)); err == nil {
+		t.Errorf("expected error decrypting tampered object")
+	}
+}
+
+func TestRoundTripVaultTransit(t *testing.T) {
+	ctx := context.Background()
+
+	// The fake transit engine "wraps" by prefixing the base64 plaintext.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		var request map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var data map[string]string
+		switch r.URL.Path {
+		case "/v1/transit/encrypt/kops":
+			data = map[string]string{"ciphertext": "vault:v1:" + request["plaintext"]}
+		case "/v1/transit/decrypt/kops":
+			data = map[string]string{"plaintext": strings.TrimPrefix(request["ciphertext"], "vault:v1:")}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"data": data})
+	}))
+	defer server.Close()
+
+	t.Setenv("VAULT_ADDR", server.URL)
+	t.Setenv("VAULT_TOKEN", "token")
+
+	spec := &kops.ConfigStoreEncryptionSpec{Provider: ProviderVaultTransit, KeyID: "transit/kops"}
+	encrypted, err := Encrypt(ctx, spec, testPath, []byte("secret"))
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	decrypted, err := Decrypt(ctx, spec, testPath, encrypted)
+	if err != nil {
+		t.Fatalf("Decrypt failed: %v", err)
+	}
+	if string(decrypted) != "secret" {
+		t.Errorf("unexpected plaintext %q", decrypted)
+	}
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envelope
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"golang.org/x/oauth2/google"
+)
+
+// gcpKMS wraps data keys with a GCP Cloud KMS crypto key, identified by its resource name:
+// projects/<project>/locations/<location>/keyRings/<keyring>/cryptoKeys/<key>
+type gcpKMS struct {
+	keyID  string
+	client *http.Client
+}
+
+func newGCPKMS(ctx context.Context, keyID string) (*gcpKMS, error) {
+	client, err := google.DefaultClient(ctx, "https://www.googleapis.com/auth/cloudkms")
+	if err != nil {
+		return nil, fmt.Errorf("error building GCP client: %w", err)
+	}
+	client.Timeout = httpTimeout
+	return &gcpKMS{keyID: keyID, client: client}, nil
+}
+
+func (p *gcpKMS) WrapKey(ctx context.Context, dataKey []byte) (string, []byte, error) {
+	var response struct {
+		Ciphertext []byte `json:"ciphertext"`
+	}
+	request := map[string]any{"plaintext": dataKey}
+	if err := postJSON(ctx, p.client, "https://cloudkms.googleapis.com/v1/"+p.keyID+":encrypt", nil, request, &response); err != nil {
+		return "", nil, err
+	}
+	return p.keyID, response.Ciphertext, nil
+}
+
+func (p *gcpKMS) UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	var response struct {
+		Plaintext []byte `json:"plaintext"`
+	}
+	request := map[string]any{"ciphertext": wrapped}
+	if err := postJSON(ctx, p.client, "https://cloudkms.googleapis.com/v1/"+keyID+":decrypt", nil, request, &response); err != nil {
+		return nil, err
+	}
+	return response.Plaintext, nil
+}
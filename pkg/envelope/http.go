@@ -0,0 +1,67 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envelope
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// httpTimeout bounds the requests to the providers, so that an unresponsive provider does not block reading the state store.
+const httpTimeout = 30 * time.Second
+
+// httpClient is the client for the providers that are called with plain HTTP requests.
+var httpClient = &http.Client{Timeout: httpTimeout}
+
+// postJSON sends a JSON request to a REST API, and decodes the JSON response.
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, request any, response any) error {
+	b, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		httpRequest.Header.Set(k, v)
+	}
+
+	httpResponse, err := client.Do(httpRequest)
+	if err != nil {
+		return err
+	}
+	defer httpResponse.Body.Close()
+
+	body, err := io.ReadAll(httpResponse.Body)
+	if err != nil {
+		return fmt.Errorf("error reading response from %s: %w", url, err)
+	}
+	if httpResponse.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from %s: %s", httpResponse.StatusCode, url, string(body))
+	}
+	if err := json.Unmarshal(body, response); err != nil {
+		return fmt.Errorf("error parsing response from %s: %w", url, err)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envelope
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// local wraps data keys with an AES-256 key read from a local file, which is intended for testing.
+type local struct {
+	path string
+	key  []byte
+}
+
+func newLocal(path string) (*local, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading local key: %w", err)
+	}
+	key, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(b)))
+	if err != nil {
+		return nil, fmt.Errorf("local key %q is not base64 encoded: %w", path, err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("local key %q must be 32 bytes, was %d", path, len(key))
+	}
+	return &local{path: path, key: key}, nil
+}
+
+func (p *local) WrapKey(ctx context.Context, dataKey []byte) (string, []byte, error) {
+	nonce, ciphertext, err := seal(p.key, dataKey, nil)
+	if err != nil {
+		return "", nil, err
+	}
+	return p.path, append(nonce, ciphertext...), nil
+}
+
+func (p *local) UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	if filepath.Clean(keyID) != filepath.Clean(p.path) {
+		return nil, fmt.Errorf("data key was wrapped with local key %q", keyID)
+	}
+	const nonceSize = 12
+	if len(wrapped) < nonceSize {
+		return nil, fmt.Errorf("wrapped key is too short")
+	}
+	return open(p.key, wrapped[:nonceSize], wrapped[nonceSize:], nil)
+}
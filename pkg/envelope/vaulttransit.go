@@ -0,0 +1,89 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envelope
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// vaultTransit wraps data keys with a key of the HashiCorp Vault Transit secrets engine, identified as <mount>/<name>.
+// The Vault server and token are read from the VAULT_ADDR, VAULT_TOKEN and (optionally) VAULT_NAMESPACE environment variables.
+type vaultTransit struct {
+	mount   string
+	name    string
+	address string
+	headers map[string]string
+}
+
+func newVaultTransit(keyID string) (*vaultTransit, error) {
+	i := strings.LastIndex(keyID, "/")
+	if i <= 0 || i == len(keyID)-1 {
+		return nil, fmt.Errorf("vault transit key ID must be <mount>/<name>, was %q", keyID)
+	}
+
+	address := strings.TrimSuffix(os.Getenv("VAULT_ADDR"), "/")
+	if address == "" {
+		return nil, fmt.Errorf("VAULT_ADDR must be set to use vault transit keys")
+	}
+	token := os.Getenv("VAULT_TOKEN")
+	if token == "" {
+		return nil, fmt.Errorf("VAULT_TOKEN must be set to use vault transit keys")
+	}
+	headers := map[string]string{"X-Vault-Token": token}
+	if namespace := os.Getenv("VAULT_NAMESPACE"); namespace != "" {
+		headers["X-Vault-Namespace"] = namespace
+	}
+
+	return &vaultTransit{
+		mount:   keyID[:i],
+		name:    keyID[i+1:],
+		address: address,
+		headers: headers,
+	}, nil
+}
+
+func (p *vaultTransit) WrapKey(ctx context.Context, dataKey []byte) (string, []byte, error) {
+	var response struct {
+		Data struct {
+			Ciphertext string `json:"ciphertext"`
+		} `json:"data"`
+	}
+	request := map[string]string{"plaintext": base64.StdEncoding.EncodeToString(dataKey)}
+	url := p.address + "/v1/" + p.mount + "/encrypt/" + p.name
+	if err := postJSON(ctx, httpClient, url, p.headers, request, &response); err != nil {
+		return "", nil, err
+	}
+	return p.mount + "/" + p.name, []byte(response.Data.Ciphertext), nil
+}
+
+func (p *vaultTransit) UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	var response struct {
+		Data struct {
+			Plaintext string `json:"plaintext"`
+		} `json:"data"`
+	}
+	request := map[string]string{"ciphertext": string(wrapped)}
+	url := p.address + "/v1/" + p.mount + "/decrypt/" + p.name
+	if err := postJSON(ctx, httpClient, url, p.headers, request, &response); err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(response.Data.Plaintext)
+}
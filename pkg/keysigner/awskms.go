@@ -0,0 +1,129 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keysigner
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"k8s.io/kops/util/pkg/awslog"
+)
+
+// awsKMSClient is the subset of the AWS KMS API used to sign.
+type awsKMSClient interface {
+	GetPublicKey(ctx context.Context, params *kms.GetPublicKeyInput, optFns ...func(*kms.Options)) (*kms.GetPublicKeyOutput, error)
+	Sign(ctx context.Context, params *kms.SignInput, optFns ...func(*kms.Options)) (*kms.SignOutput, error)
+}
+
+// awsKMSSigner signs with an asymmetric AWS KMS key.
+type awsKMSSigner struct {
+	keyID     string
+	client    awsKMSClient
+	publicKey crypto.PublicKey
+}
+
+var _ crypto.Signer = &awsKMSSigner{}
+
+func newAWSKMSSigner(ctx context.Context, uri string) (crypto.Signer, error) {
+	keyID := strings.TrimPrefix(uri, SchemeAWSKMS+"://")
+	if keyID == "" || keyID == uri {
+		return nil, fmt.Errorf("invalid AWS KMS key URI %q, expected %s://<key ID or ARN>", uri, SchemeAWSKMS)
+	}
+
+	var opts []func(*awsconfig.LoadOptions) error
+	opts = append(opts, awslog.WithAWSLogger())
+	// The region is taken from the key ARN, so that the key can be used from any region.
+	if strings.HasPrefix(keyID, "arn:") {
+		parsed, err := arn.Parse(keyID)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing KMS key ARN %q: %w", keyID, err)
+		}
+		opts = append(opts, awsconfig.WithRegion(parsed.Region))
+	} else if region := os.Getenv("AWS_REGION"); region != "" {
+		opts = append(opts, awsconfig.WithRegion(region))
+	}
+
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error loading AWS config: %w", err)
+	}
+	return buildAWSKMSSigner(ctx, keyID, kms.NewFromConfig(cfg))
+}
+
+func buildAWSKMSSigner(ctx context.Context, keyID string, client awsKMSClient) (*awsKMSSigner, error) {
+	response, err := client.GetPublicKey(ctx, &kms.GetPublicKeyInput{KeyId: &keyID})
+	if err != nil {
+		return nil, fmt.Errorf("error getting public key of AWS KMS key %q: %w", keyID, err)
+	}
+	if response.KeyUsage != kmstypes.KeyUsageTypeSignVerify {
+		return nil, fmt.Errorf("AWS KMS key %q has usage %q, expected %q", keyID, response.KeyUsage, kmstypes.KeyUsageTypeSignVerify)
+	}
+	publicKey, err := x509.ParsePKIXPublicKey(response.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing public key of AWS KMS key %q: %w", keyID, err)
+	}
+	return &awsKMSSigner{keyID: keyID, client: client, publicKey: publicKey}, nil
+}
+
+// Public implements crypto.Signer.
+func (s *awsKMSSigner) Public() crypto.PublicKey {
+	return s.publicKey
+}
+
+// Sign implements crypto.Signer.
+func (s *awsKMSSigner) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	hash, err := hashName(opts.HashFunc())
+	if err != nil {
+		return nil, err
+	}
+
+	var algorithm string
+	switch s.publicKey.(type) {
+	case *rsa.PublicKey:
+		if _, ok := opts.(*rsa.PSSOptions); ok {
+			algorithm = "RSASSA_PSS_" + hash
+		} else {
+			algorithm = "RSASSA_PKCS1_V1_5_" + hash
+		}
+	case *ecdsa.PublicKey:
+		algorithm = "ECDSA_" + hash
+	default:
+		return nil, fmt.Errorf("unsupported AWS KMS key type %T", s.publicKey)
+	}
+
+	response, err := s.client.Sign(context.TODO(), &kms.SignInput{
+		KeyId:            &s.keyID,
+		Message:          digest,
+		MessageType:      kmstypes.MessageTypeDigest,
+		SigningAlgorithm: kmstypes.SigningAlgorithmSpec(algorithm),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error signing with AWS KMS key %q: %w", s.keyID, err)
+	}
+	return response.Signature, nil
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keysigner
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"golang.org/x/oauth2/google"
+)
+
+const gcpKMSEndpoint = "https://cloudkms.googleapis.com/v1/"
+
+// gcpKMSSigner signs with an asymmetric signing key version in GCP Cloud KMS.
+type gcpKMSSigner struct {
+	name      string
+	endpoint  string
+	client    *http.Client
+	hash      crypto.Hash
+	publicKey crypto.PublicKey
+}
+
+var _ crypto.Signer = &gcpKMSSigner{}
+
+func newGCPKMSSigner(ctx context.Context, uri string) (crypto.Signer, error) {
+	name := strings.TrimPrefix(uri, SchemeGCPKMS+"://")
+	if !strings.HasPrefix(name, "projects/") || !strings.Contains(name, "/cryptoKeyVersions/") {
+		return nil, fmt.Errorf("invalid GCP KMS key URI %q, expected %s://projects/.../cryptoKeyVersions/<version>", uri, SchemeGCPKMS)
+	}
+
+	client, err := google.DefaultClient(ctx, "https://www.googleapis.com/auth/cloudkms")
+	if err != nil {
+		return nil, fmt.Errorf("error building GCP client: %w", err)
+	}
+	return buildGCPKMSSigner(ctx, name, gcpKMSEndpoint, client)
+}
+
+func buildGCPKMSSigner(ctx context.Context, name string, endpoint string, client *http.Client) (*gcpKMSSigner, error) {
+	s := &gcpKMSSigner{name: name, endpoint: endpoint, client: client}
+
+	var response struct {
+		PEM       string `json:"pem"`
+		Algorithm string `json:"algorithm"`
+	}
+	if err := s.do(ctx, http.MethodGet, name+"/publicKey", nil, &response); err != nil {
+		return nil, fmt.Errorf("error getting public key of GCP KMS key %q: %w", name, err)
+	}
+
+	// Each key version signs digests of a single hash, given by its algorithm, for example EC_SIGN_P256_SHA256.
+	switch {
+	case strings.HasSuffix(response.Algorithm, "_SHA256"):
+		s.hash = crypto.SHA256
+	case strings.HasSuffix(response.Algorithm, "_SHA384"):
+		s.hash = crypto.SHA384
+	case strings.HasSuffix(response.Algorithm, "_SHA512"):
+		s.hash = crypto.SHA512
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %q of GCP KMS key %q", response.Algorithm, name)
+	}
+	if strings.HasPrefix(response.Algorithm, "RSA_SIGN_PSS_") {
+		return nil, fmt.Errorf("unsupported algorithm %q of GCP KMS key %q, use a PKCS#1 v1.5 or elliptic curve key", response.Algorithm, name)
+	}
+
+	block, _ := pem.Decode([]byte(response.PEM))
+	if block == nil {
+		return nil, fmt.Errorf("error decoding public key of GCP KMS key %q", name)
+	}
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing public key of GCP KMS key %q: %w", name, err)
+	}
+	s.publicKey = publicKey
+	return s, nil
+}
+
+// Public implements crypto.Signer.
+func (s *gcpKMSSigner) Public() crypto.PublicKey {
+	return s.publicKey
+}
+
+// Sign implements crypto.Signer.
+func (s *gcpKMSSigner) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if opts.HashFunc() != s.hash {
+		return nil, fmt.Errorf("GCP KMS key %q signs %v digests, not %v", s.name, s.hash, opts.HashFunc())
+	}
+
+	var digestField string
+	switch s.hash {
+	case crypto.SHA256:
+		digestField = "sha256"
+	case crypto.SHA384:
+		digestField = "sha384"
+	case crypto.SHA512:
+		digestField = "sha512"
+	}
+
+	var response struct {
+		Signature []byte `json:"signature"`
+	}
+	request := map[string]any{"digest": map[string][]byte{digestField: digest}}
+	if err := s.do(context.TODO(), http.MethodPost, s.name+":asymmetricSign", request, &response); err != nil {
+		return nil, fmt.Errorf("error signing with GCP KMS key %q: %w", s.name, err)
+	}
+	return response.Signature, nil
+}
+
+func (s *gcpKMSSigner) do(ctx context.Context, method string, path string, request any, response any) error {
+	var body io.Reader
+	if request != nil {
+		b, err := json.Marshal(request)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+	url := s.endpoint + path
+	httpRequest, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+	if request != nil {
+		httpRequest.Header.Set("Content-Type", "application/json")
+	}
+
+	httpResponse, err := s.client.Do(httpRequest)
+	if err != nil {
+		return err
+	}
+	defer httpResponse.Body.Close()
+
+	b, err := io.ReadAll(httpResponse.Body)
+	if err != nil {
+		return fmt.Errorf("error reading response from %s: %w", url, err)
+	}
+	if httpResponse.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from %s: %s", httpResponse.StatusCode, url, string(b))
+	}
+	if err := json.Unmarshal(b, response); err != nil {
+		return fmt.Errorf("error parsing response from %s: %w", url, err)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package keysigner provides the private keys that are held outside kops, in a PKCS#11 module
+// or a cloud KMS, so that they can be used to issue certificates without being exported.
+//
+// Importing the package registers the providers with pkg/pki, for keys referenced by:
+//
+//	pkcs11:token=<token label>;object=<key label>?module-path=<module>&pin-source=<file>
+//	aws-kms://<key ID or ARN>
+//	gcp-kms://projects/<project>/locations/<location>/keyRings/<key ring>/cryptoKeys/<key>/cryptoKeyVersions/<version>
+package keysigner
+
+import (
+	"crypto"
+	"fmt"
+
+	"k8s.io/kops/pkg/pki"
+)
+
+// URI schemes of the supported keys
+const (
+	SchemePKCS11 = "pkcs11"
+	SchemeAWSKMS = "aws-kms"
+	SchemeGCPKMS = "gcp-kms"
+)
+
+func init() {
+	pki.RegisterSignerProvider(SchemePKCS11, newPKCS11Signer)
+	pki.RegisterSignerProvider(SchemeAWSKMS, newAWSKMSSigner)
+	pki.RegisterSignerProvider(SchemeGCPKMS, newGCPKMSSigner)
+}
+
+// hashName returns the name of the hash used to compute a digest, as used by the KMS APIs.
+func hashName(h crypto.Hash) (string, error) {
+	switch h {
+	case crypto.SHA256:
+		return "SHA_256", nil
+	case crypto.SHA384:
+		return "SHA_384", nil
+	case crypto.SHA512:
+		return "SHA_512", nil
+	default:
+		return "", fmt.Errorf("unsupported hash %v", h)
+	}
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keysigner
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+)
+
+func TestParsePKCS11URI(t *testing.T) {
+	pinFile := filepath.Join(t.TempDir(), "pin")
+	if err := os.WriteFile(pinFile, []byte("1234\n"), 0o600); err != nil {
+		t.Fatalf("writing PIN: %v", err)
+	}
+
+	grid := []struct {
+		URI      string
+		Expected *pkcs11URI
+		Error    string
+	}{
+		{
+			URI:      "pkcs11:token=kops;object=kubernetes-ca?module-path=/usr/lib/softhsm/libsofthsm2.so&pin-value=1234",
+			Expected: &pkcs11URI{Token: "kops", Object: "kubernetes-ca", ModulePath: "/usr/lib/softhsm/libsofthsm2.so", PIN: "1234"},
+		},
+		{
+			URI:      "pkcs11:token=my%20token;id=%01%02;type=private?module-path=/lib/p11.so&pin-source=file:" + pinFile,
+			Expected: &pkcs11URI{Token: "my token", ID: []byte{1, 2}, ModulePath: "/lib/p11.so", PIN: "1234"},
+		},
+		{
+			URI:   "pkcs11:token=kops;object=kubernetes-ca",
+			Error: "does not set module-path",
+		},
+		{
+			URI:   "pkcs11:object=kubernetes-ca?module-path=/lib/p11.so",
+			Error: "does not set the token",
+		},
+		{
+			URI:   "pkcs11:token=kops?module-path=/lib/p11.so",
+			Error: "neither the object nor the id",
+		},
+		{
+			URI:   "pkcs11:token=kops;object=kubernetes-ca;type=public?module-path=/lib/p11.so",
+			Error: "must reference a private key",
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.URI, func(t *testing.T) {
+			t.Setenv("KOPS_PKCS11_MODULE", "")
+			actual, err := parsePKCS11URI(g.URI)
+			if g.Error != "" {
+				if err == nil || !strings.Contains(err.Error(), g.Error) {
+					t.Fatalf("expected error containing %q, got %v", g.Error, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if actual.Token != g.Expected.Token || actual.Object != g.Expected.Object || string(actual.ID) != string(g.Expected.ID) || actual.ModulePath != g.Expected.ModulePath || actual.PIN != g.Expected.PIN {
+				t.Errorf("expected %+v, got %+v", g.Expected, actual)
+			}
+		})
+	}
+}
+
+// fakeAWSKMS implements the AWS KMS signing API with a local key.
+type fakeAWSKMS struct {
+	key *ecdsa.PrivateKey
+}
+
+func (f *fakeAWSKMS) GetPublicKey(ctx context.Context, params *kms.GetPublicKeyInput, optFns ...func(*kms.Options)) (*kms.GetPublicKeyOutput, error) {
+	der, err := x509.MarshalPKIXPublicKey(&f.key.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	return &kms.GetPublicKeyOutput{KeyId: params.KeyId, PublicKey: der, KeyUsage: kmstypes.KeyUsageTypeSignVerify}, nil
+}
+
+func (f *fakeAWSKMS) Sign(ctx context.Context, params *kms.SignInput, optFns ...func(*kms.Options)) (*kms.SignOutput, error) {
+	if params.MessageType != kmstypes.MessageTypeDigest || params.SigningAlgorithm != kmstypes.SigningAlgorithmSpecEcdsaSha256 {
+		return nil, &kmstypes.InvalidKeyUsageException{}
+	}
+	signature, err := f.key.Sign(rand.Reader, params.Message, crypto.SHA256)
+	if err != nil {
+		return nil, err
+	}
+	return &kms.SignOutput{Signature: signature}, nil
+}
+
+func TestAWSKMSSigner(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+
+	signer, err := buildAWSKMSSigner(context.Background(), "alias/kops-ca", &fakeAWSKMS{key: key})
+	if err != nil {
+		t.Fatalf("building signer: %v", err)
+	}
+	testSigner(t, signer, &key.PublicKey)
+}
+
+func TestGCPKMSSigner(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("marshaling public key: %v", err)
+	}
+
+	name := "projects/p/locations/global/keyRings/kops/cryptoKeys/ca/cryptoKeyVersions/1"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/"+name+"/publicKey":
+			json.NewEncoder(w).Encode(map[string]string{
+				"pem":       string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+				"algorithm": "EC_SIGN_P256_SHA256",
+			})
+		case r.Method == http.MethodPost && r.URL.Path == "/"+name+":asymmetricSign":
+			var request struct {
+				Digest struct {
+					SHA256 []byte `json:"sha256"`
+				} `json:"digest"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			signature, err := key.Sign(rand.Reader, request.Digest.SHA256, crypto.SHA256)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string][]byte{"signature": signature})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	signer, err := buildGCPKMSSigner(context.Background(), name, server.URL+"/", server.Client())
+	if err != nil {
+		t.Fatalf("building signer: %v", err)
+	}
+	testSigner(t, signer, &key.PublicKey)
+
+	digest := sha256.Sum256([]byte("message"))
+	if _, err := signer.Sign(rand.Reader, digest[:], crypto.SHA384); err == nil {
+		t.Errorf("expected error signing a digest of another hash")
+	}
+}
+
+func testSigner(t *testing.T, signer crypto.Signer, expected *ecdsa.PublicKey) {
+	public, ok := signer.Public().(*ecdsa.PublicKey)
+	if !ok || !public.Equal(expected) {
+		t.Fatalf("unexpected public key %v", signer.Public())
+	}
+
+	digest := sha256.Sum256([]byte("message"))
+	signature, err := signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	if err != nil {
+		t.Fatalf("signing: %v", err)
+	}
+	if !ecdsa.VerifyASN1(expected, digest[:], signature) {
+		t.Errorf("signature does not verify")
+	}
+}
@@ -0,0 +1,522 @@
+//go:build cgo && pkcs11
+
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keysigner
+
+/*
+#cgo linux LDFLAGS: -ldl
+#include <dlfcn.h>
+#include <stdlib.h>
+#include <string.h>
+
+// The subset of the PKCS#11 types that we need. On Unix platforms the PKCS#11 structures are not packed.
+typedef unsigned long CK_ULONG;
+typedef CK_ULONG CK_RV;
+typedef struct { unsigned char major; unsigned char minor; } CK_VERSION;
+typedef struct { CK_ULONG type; void *pValue; CK_ULONG ulValueLen; } CK_ATTRIBUTE;
+typedef struct { CK_ULONG mechanism; void *pParameter; CK_ULONG ulParameterLen; } CK_MECHANISM;
+typedef struct { void *CreateMutex; void *DestroyMutex; void *LockMutex; void *UnlockMutex; CK_ULONG flags; void *pReserved; } CK_C_INITIALIZE_ARGS;
+
+// The functions of CK_FUNCTION_LIST, in the order defined by the PKCS#11 specification, up to C_Sign.
+enum {
+	fnInitialize, fnFinalize, fnGetInfo, fnGetFunctionList, fnGetSlotList, fnGetSlotInfo, fnGetTokenInfo,
+	fnGetMechanismList, fnGetMechanismInfo, fnInitToken, fnInitPIN, fnSetPIN, fnOpenSession, fnCloseSession,
+	fnCloseAllSessions, fnGetSessionInfo, fnGetOperationState, fnSetOperationState, fnLogin, fnLogout,
+	fnCreateObject, fnCopyObject, fnDestroyObject, fnGetObjectSize, fnGetAttributeValue, fnSetAttributeValue,
+	fnFindObjectsInit, fnFindObjects, fnFindObjectsFinal, fnEncryptInit, fnEncrypt, fnEncryptUpdate,
+	fnEncryptFinal, fnDecryptInit, fnDecrypt, fnDecryptUpdate, fnDecryptFinal, fnDigestInit, fnDigest,
+	fnDigestUpdate, fnDigestKey, fnDigestFinal, fnSignInit, fnSign, fnCount
+};
+
+typedef struct { CK_VERSION version; void *fn[fnCount]; } ck_function_list;
+
+// ck_load loads the module, returning 1 if it cannot be opened and 2 if it does not export C_GetFunctionList.
+static int ck_load(const char *path, ck_function_list **functions, CK_RV *rv) {
+	void *handle = dlopen(path, RTLD_NOW | RTLD_LOCAL);
+	if (handle == NULL) {
+		return 1;
+	}
+	CK_RV (*getFunctionList)(ck_function_list **) = dlsym(handle, "C_GetFunctionList");
+	if (getFunctionList == NULL) {
+		return 2;
+	}
+	*rv = getFunctionList(functions);
+	return 0;
+}
+
+static CK_RV ck_initialize(ck_function_list *f) {
+	CK_C_INITIALIZE_ARGS args;
+	memset(&args, 0, sizeof(args));
+	args.flags = 2; // CKF_OS_LOCKING_OK
+	return ((CK_RV (*)(CK_C_INITIALIZE_ARGS *))f->fn[fnInitialize])(&args);
+}
+
+static CK_RV ck_get_slot_list(ck_function_list *f, CK_ULONG *slots, CK_ULONG *count) {
+	return ((CK_RV (*)(unsigned char, CK_ULONG *, CK_ULONG *))f->fn[fnGetSlotList])(1, slots, count);
+}
+
+// ck_get_token_label copies the label, the first field of CK_TOKEN_INFO, of the token in the slot.
+static CK_RV ck_get_token_label(ck_function_list *f, CK_ULONG slot, char *label) {
+	unsigned char info[1024];
+	CK_RV rv = ((CK_RV (*)(CK_ULONG, void *))f->fn[fnGetTokenInfo])(slot, info);
+	if (rv == 0) {
+		memcpy(label, info, 32);
+	}
+	return rv;
+}
+
+static CK_RV ck_open_session(ck_function_list *f, CK_ULONG slot, CK_ULONG *session) {
+	// CKF_SERIAL_SESSION
+	return ((CK_RV (*)(CK_ULONG, CK_ULONG, void *, void *, CK_ULONG *))f->fn[fnOpenSession])(slot, 4, NULL, NULL, session);
+}
+
+static CK_RV ck_close_session(ck_function_list *f, CK_ULONG session) {
+	return ((CK_RV (*)(CK_ULONG))f->fn[fnCloseSession])(session);
+}
+
+static CK_RV ck_login(ck_function_list *f, CK_ULONG session, char *pin, CK_ULONG pinLen) {
+	// CKU_USER
+	return ((CK_RV (*)(CK_ULONG, CK_ULONG, char *, CK_ULONG))f->fn[fnLogin])(session, 1, pin, pinLen);
+}
+
+static CK_RV ck_find_objects(ck_function_list *f, CK_ULONG session, CK_ATTRIBUTE *template, CK_ULONG count, CK_ULONG *objects, CK_ULONG max, CK_ULONG *found) {
+	CK_RV rv = ((CK_RV (*)(CK_ULONG, CK_ATTRIBUTE *, CK_ULONG))f->fn[fnFindObjectsInit])(session, template, count);
+	if (rv != 0) {
+		return rv;
+	}
+	rv = ((CK_RV (*)(CK_ULONG, CK_ULONG *, CK_ULONG, CK_ULONG *))f->fn[fnFindObjects])(session, objects, max, found);
+	CK_RV rv2 = ((CK_RV (*)(CK_ULONG))f->fn[fnFindObjectsFinal])(session);
+	return rv != 0 ? rv : rv2;
+}
+
+static CK_RV ck_get_attribute_value(ck_function_list *f, CK_ULONG session, CK_ULONG object, CK_ATTRIBUTE *template, CK_ULONG count) {
+	return ((CK_RV (*)(CK_ULONG, CK_ULONG, CK_ATTRIBUTE *, CK_ULONG))f->fn[fnGetAttributeValue])(session, object, template, count);
+}
+
+static CK_RV ck_sign(ck_function_list *f, CK_ULONG session, CK_ULONG mechanism, CK_ULONG key, unsigned char *data, CK_ULONG dataLen, unsigned char *signature, CK_ULONG *signatureLen) {
+	CK_MECHANISM m;
+	memset(&m, 0, sizeof(m));
+	m.mechanism = mechanism;
+	CK_RV rv = ((CK_RV (*)(CK_ULONG, CK_MECHANISM *, CK_ULONG))f->fn[fnSignInit])(session, &m, key);
+	if (rv != 0) {
+		return rv;
+	}
+	return ((CK_RV (*)(CK_ULONG, unsigned char *, CK_ULONG, unsigned char *, CK_ULONG *))f->fn[fnSign])(session, data, dataLen, signature, signatureLen);
+}
+*/
+import "C"
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/asn1"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+// PKCS#11 constants
+const (
+	ckrOK                         = 0x0
+	ckrUserAlreadyLoggedIn        = 0x100
+	ckrCryptokiAlreadyInitialized = 0x191
+	ckaClass                      = 0x0
+	ckaLabel                      = 0x3
+	ckaKeyType                    = 0x100
+	ckaID                         = 0x102
+	ckaModulus                    = 0x120
+	ckaPublicExponent             = 0x122
+	ckaECParams                   = 0x180
+	ckaECPoint                    = 0x181
+	ckoPublicKey                  = 0x2
+	ckoPrivateKey                 = 0x3
+	ckkRSA                        = 0x0
+	ckkEC                         = 0x3
+	ckmRSAPKCS                    = 0x1
+	ckmECDSA                      = 0x1041
+	pkcs11MaxSignatureLength      = 1024
+	pkcs11TokenLabelLength        = 32
+	pkcs11MaxAttributeValueLength = 2048
+)
+
+// pkcs11Module is a loaded PKCS#11 module.
+type pkcs11Module struct {
+	path      string
+	functions *C.ck_function_list
+}
+
+var (
+	pkcs11ModulesMutex sync.Mutex
+	pkcs11Modules      = map[string]*pkcs11Module{}
+)
+
+// loadPKCS11Module loads and initializes the module, which is shared by all the keys it holds.
+func loadPKCS11Module(path string) (*pkcs11Module, error) {
+	pkcs11ModulesMutex.Lock()
+	defer pkcs11ModulesMutex.Unlock()
+
+	if m := pkcs11Modules[path]; m != nil {
+		return m, nil
+	}
+
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+
+	var functions *C.ck_function_list
+	var rv C.CK_RV
+	switch C.ck_load(cPath, &functions, &rv) {
+	case 1:
+		return nil, fmt.Errorf("error loading PKCS#11 module %q: %s", path, C.GoString(C.dlerror()))
+	case 2:
+		return nil, fmt.Errorf("error loading PKCS#11 module %q: C_GetFunctionList not found", path)
+	}
+	if rv != ckrOK {
+		return nil, fmt.Errorf("error loading PKCS#11 module %q: C_GetFunctionList returned 0x%x", path, uint64(rv))
+	}
+
+	if rv := C.ck_initialize(functions); rv != ckrOK && rv != ckrCryptokiAlreadyInitialized {
+		return nil, fmt.Errorf("error initializing PKCS#11 module %q: 0x%x", path, uint64(rv))
+	}
+
+	m := &pkcs11Module{path: path, functions: functions}
+	pkcs11Modules[path] = m
+	return m, nil
+}
+
+// findSlot returns the slot that holds the token with the label.
+func (m *pkcs11Module) findSlot(label string) (C.CK_ULONG, error) {
+	var count C.CK_ULONG
+	if rv := C.ck_get_slot_list(m.functions, nil, &count); rv != ckrOK {
+		return 0, fmt.Errorf("error listing PKCS#11 slots: 0x%x", uint64(rv))
+	}
+	if count == 0 {
+		return 0, fmt.Errorf("no PKCS#11 tokens found in module %q", m.path)
+	}
+	slots := (*C.CK_ULONG)(C.calloc(C.size_t(count), C.size_t(unsafe.Sizeof(C.CK_ULONG(0)))))
+	defer C.free(unsafe.Pointer(slots))
+	if rv := C.ck_get_slot_list(m.functions, slots, &count); rv != ckrOK {
+		return 0, fmt.Errorf("error listing PKCS#11 slots: 0x%x", uint64(rv))
+	}
+
+	cLabel := (*C.char)(C.calloc(pkcs11TokenLabelLength, 1))
+	defer C.free(unsafe.Pointer(cLabel))
+	for _, slot := range unsafe.Slice(slots, int(count)) {
+		if rv := C.ck_get_token_label(m.functions, slot, cLabel); rv != ckrOK {
+			return 0, fmt.Errorf("error getting PKCS#11 token info: 0x%x", uint64(rv))
+		}
+		// Token labels are padded with spaces.
+		if strings.TrimRight(C.GoStringN(cLabel, pkcs11TokenLabelLength), " \x00") == label {
+			return slot, nil
+		}
+	}
+	return 0, fmt.Errorf("PKCS#11 token %q not found in module %q", label, m.path)
+}
+
+// pkcs11Session is an open session, logged in as the user.
+type pkcs11Session struct {
+	module *pkcs11Module
+	handle C.CK_ULONG
+}
+
+func (m *pkcs11Module) openSession(slot C.CK_ULONG, pin string) (*pkcs11Session, error) {
+	s := &pkcs11Session{module: m}
+	if rv := C.ck_open_session(m.functions, slot, &s.handle); rv != ckrOK {
+		return nil, fmt.Errorf("error opening PKCS#11 session: 0x%x", uint64(rv))
+	}
+	if pin != "" {
+		cPIN := C.CString(pin)
+		defer C.free(unsafe.Pointer(cPIN))
+		// The login state is shared by all the sessions of the application.
+		if rv := C.ck_login(m.functions, s.handle, cPIN, C.CK_ULONG(len(pin))); rv != ckrOK && rv != ckrUserAlreadyLoggedIn {
+			s.Close()
+			return nil, fmt.Errorf("error logging in to PKCS#11 token: 0x%x", uint64(rv))
+		}
+	}
+	return s, nil
+}
+
+func (s *pkcs11Session) Close() {
+	C.ck_close_session(s.module.functions, s.handle)
+}
+
+// attributes holds a PKCS#11 template in C memory, as the values are referenced by pointer.
+type attributes struct {
+	template *C.CK_ATTRIBUTE
+	count    int
+}
+
+func newAttributes(count int) *attributes {
+	a := &attributes{count: count}
+	a.template = (*C.CK_ATTRIBUTE)(C.calloc(C.size_t(count), C.size_t(unsafe.Sizeof(C.CK_ATTRIBUTE{}))))
+	return a
+}
+
+func (a *attributes) items() []C.CK_ATTRIBUTE {
+	return unsafe.Slice(a.template, a.count)
+}
+
+func (a *attributes) set(i int, attributeType C.CK_ULONG, value []byte) {
+	item := &a.items()[i]
+	item._type = attributeType
+	item.pValue = C.CBytes(value)
+	item.ulValueLen = C.CK_ULONG(len(value))
+}
+
+func (a *attributes) setULong(i int, attributeType C.CK_ULONG, value C.CK_ULONG) {
+	item := &a.items()[i]
+	item._type = attributeType
+	item.pValue = C.malloc(C.size_t(unsafe.Sizeof(value)))
+	*(*C.CK_ULONG)(item.pValue) = value
+	item.ulValueLen = C.CK_ULONG(unsafe.Sizeof(value))
+}
+
+func (a *attributes) get(i int) []byte {
+	item := a.items()[i]
+	return C.GoBytes(item.pValue, C.int(item.ulValueLen))
+}
+
+func (a *attributes) Free() {
+	for _, item := range a.items() {
+		C.free(item.pValue)
+	}
+	C.free(unsafe.Pointer(a.template))
+}
+
+// findKey returns the handle of the key of the class with the label and ID.
+func (s *pkcs11Session) findKey(class C.CK_ULONG, label string, id []byte) (C.CK_ULONG, error) {
+	var template *attributes
+	switch {
+	case label != "" && len(id) != 0:
+		template = newAttributes(3)
+		template.set(1, ckaLabel, []byte(label))
+		template.set(2, ckaID, id)
+	case label != "":
+		template = newAttributes(2)
+		template.set(1, ckaLabel, []byte(label))
+	default:
+		template = newAttributes(2)
+		template.set(1, ckaID, id)
+	}
+	defer template.Free()
+	template.setULong(0, ckaClass, class)
+
+	objects := (*C.CK_ULONG)(C.calloc(2, C.size_t(unsafe.Sizeof(C.CK_ULONG(0)))))
+	defer C.free(unsafe.Pointer(objects))
+	var found C.CK_ULONG
+	if rv := C.ck_find_objects(s.module.functions, s.handle, template.template, C.CK_ULONG(template.count), objects, 2, &found); rv != ckrOK {
+		return 0, fmt.Errorf("error finding PKCS#11 key: 0x%x", uint64(rv))
+	}
+	switch found {
+	case 0:
+		return 0, fmt.Errorf("PKCS#11 key %s not found", describePKCS11Key(label, id))
+	case 1:
+		return unsafe.Slice(objects, 2)[0], nil
+	default:
+		return 0, fmt.Errorf("more than one PKCS#11 key matches %s", describePKCS11Key(label, id))
+	}
+}
+
+func describePKCS11Key(label string, id []byte) string {
+	if label != "" {
+		return fmt.Sprintf("%q", label)
+	}
+	return fmt.Sprintf("with id %x", id)
+}
+
+// getAttributes reads the attributes of the object.
+func (s *pkcs11Session) getAttributes(object C.CK_ULONG, attributeTypes ...C.CK_ULONG) ([][]byte, error) {
+	template := newAttributes(len(attributeTypes))
+	defer template.Free()
+	for i, attributeType := range attributeTypes {
+		template.set(i, attributeType, make([]byte, pkcs11MaxAttributeValueLength))
+	}
+	if rv := C.ck_get_attribute_value(s.module.functions, s.handle, object, template.template, C.CK_ULONG(template.count)); rv != ckrOK {
+		return nil, fmt.Errorf("error reading PKCS#11 key attributes: 0x%x", uint64(rv))
+	}
+	var values [][]byte
+	for i := range attributeTypes {
+		values = append(values, template.get(i))
+	}
+	return values, nil
+}
+
+// pkcs11Signer signs with a private key in a PKCS#11 token.
+type pkcs11Signer struct {
+	uri       *pkcs11URI
+	module    *pkcs11Module
+	slot      C.CK_ULONG
+	publicKey crypto.PublicKey
+}
+
+var _ crypto.Signer = &pkcs11Signer{}
+
+func newPKCS11Signer(ctx context.Context, uri string) (crypto.Signer, error) {
+	u, err := parsePKCS11URI(uri)
+	if err != nil {
+		return nil, err
+	}
+	module, err := loadPKCS11Module(u.ModulePath)
+	if err != nil {
+		return nil, err
+	}
+	slot, err := module.findSlot(u.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &pkcs11Signer{uri: u, module: module, slot: slot}
+	session, err := module.openSession(slot, u.PIN)
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	// Check that the private key exists, and read its public key.
+	if _, err := session.findKey(ckoPrivateKey, u.Object, u.ID); err != nil {
+		return nil, err
+	}
+	publicKey, err := session.findKey(ckoPublicKey, u.Object, u.ID)
+	if err != nil {
+		return nil, fmt.Errorf("finding public key: %w", err)
+	}
+	values, err := session.getAttributes(publicKey, ckaKeyType)
+	if err != nil {
+		return nil, err
+	}
+	if len(values[0]) != int(unsafe.Sizeof(C.CK_ULONG(0))) {
+		return nil, fmt.Errorf("unexpected PKCS#11 key type attribute of %d bytes", len(values[0]))
+	}
+	switch keyType := *(*C.CK_ULONG)(unsafe.Pointer(&values[0][0])); keyType {
+	case ckkRSA:
+		values, err := session.getAttributes(publicKey, ckaModulus, ckaPublicExponent)
+		if err != nil {
+			return nil, err
+		}
+		s.publicKey = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(values[0]),
+			E: int(new(big.Int).SetBytes(values[1]).Int64()),
+		}
+	case ckkEC:
+		values, err := session.getAttributes(publicKey, ckaECParams, ckaECPoint)
+		if err != nil {
+			return nil, err
+		}
+		s.publicKey, err = parsePKCS11ECPublicKey(values[0], values[1])
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported PKCS#11 key type 0x%x", uint64(keyType))
+	}
+	return s, nil
+}
+
+// parsePKCS11ECPublicKey parses the DER-encoded curve OID and point of an EC public key.
+func parsePKCS11ECPublicKey(params []byte, point []byte) (*ecdsa.PublicKey, error) {
+	var oid asn1.ObjectIdentifier
+	if _, err := asn1.Unmarshal(params, &oid); err != nil {
+		return nil, fmt.Errorf("error parsing PKCS#11 EC parameters: %w", err)
+	}
+	var curve elliptic.Curve
+	switch oid.String() {
+	case "1.2.840.10045.3.1.7":
+		curve = elliptic.P256()
+	case "1.3.132.0.34":
+		curve = elliptic.P384()
+	case "1.3.132.0.35":
+		curve = elliptic.P521()
+	default:
+		return nil, fmt.Errorf("unsupported PKCS#11 EC curve %v", oid)
+	}
+	var raw []byte
+	if _, err := asn1.Unmarshal(point, &raw); err != nil {
+		return nil, fmt.Errorf("error parsing PKCS#11 EC point: %w", err)
+	}
+	return ecdsa.ParseUncompressedPublicKey(curve, raw)
+}
+
+// Public implements crypto.Signer.
+func (s *pkcs11Signer) Public() crypto.PublicKey {
+	return s.publicKey
+}
+
+// Sign implements crypto.Signer.
+func (s *pkcs11Signer) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	var mechanism C.CK_ULONG
+	var data []byte
+	switch s.publicKey.(type) {
+	case *rsa.PublicKey:
+		if _, ok := opts.(*rsa.PSSOptions); ok {
+			return nil, fmt.Errorf("RSA-PSS signatures are not supported with PKCS#11 keys")
+		}
+		prefix, ok := pkcs1DigestInfoPrefixes[opts.HashFunc()]
+		if !ok {
+			return nil, fmt.Errorf("unsupported hash %v", opts.HashFunc())
+		}
+		// CKM_RSA_PKCS signs the DigestInfo structure, which we build from the digest.
+		mechanism = ckmRSAPKCS
+		data = append(append([]byte{}, prefix...), digest...)
+	case *ecdsa.PublicKey:
+		mechanism = ckmECDSA
+		data = digest
+	}
+
+	session, err := s.module.openSession(s.slot, s.uri.PIN)
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	key, err := session.findKey(ckoPrivateKey, s.uri.Object, s.uri.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	cData := (*C.uchar)(C.CBytes(data))
+	defer C.free(unsafe.Pointer(cData))
+	cSignature := (*C.uchar)(C.malloc(pkcs11MaxSignatureLength))
+	defer C.free(unsafe.Pointer(cSignature))
+	signatureLength := C.CK_ULONG(pkcs11MaxSignatureLength)
+	if rv := C.ck_sign(s.module.functions, session.handle, mechanism, key, cData, C.CK_ULONG(len(data)), cSignature, &signatureLength); rv != ckrOK {
+		return nil, fmt.Errorf("error signing with PKCS#11 key: 0x%x", uint64(rv))
+	}
+	signature := C.GoBytes(unsafe.Pointer(cSignature), C.int(signatureLength))
+
+	if _, ok := s.publicKey.(*ecdsa.PublicKey); ok {
+		// CKM_ECDSA returns r and s concatenated, but crypto.Signer returns an ASN.1 signature.
+		half := len(signature) / 2
+		return asn1.Marshal(struct{ R, S *big.Int }{
+			R: new(big.Int).SetBytes(signature[:half]),
+			S: new(big.Int).SetBytes(signature[half:]),
+		})
+	}
+	return signature, nil
+}
+
+// pkcs1DigestInfoPrefixes are the DER prefixes of the DigestInfo structure signed with PKCS#1 v1.5, by hash.
+var pkcs1DigestInfoPrefixes = map[crypto.Hash][]byte{
+	crypto.SHA256: {0x30, 0x31, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x01, 0x05, 0x00, 0x04, 0x20},
+	crypto.SHA384: {0x30, 0x41, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x02, 0x05, 0x00, 0x04, 0x30},
+	crypto.SHA512: {0x30, 0x51, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x03, 0x05, 0x00, 0x04, 0x40},
+}
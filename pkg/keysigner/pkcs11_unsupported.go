@@ -0,0 +1,33 @@
+//go:build !(cgo && pkcs11)
+
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keysigner
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+)
+
+// newPKCS11Signer reports that PKCS#11 keys need kops to be built with cgo and the pkcs11 build tag.
+func newPKCS11Signer(ctx context.Context, uri string) (crypto.Signer, error) {
+	if _, err := parsePKCS11URI(uri); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("PKCS#11 keys are not supported by this build; build with CGO_ENABLED=1 and -tags pkcs11")
+}
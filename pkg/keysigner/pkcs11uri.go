@@ -0,0 +1,111 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keysigner
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// pkcs11URI is a key in a PKCS#11 module, referenced by an RFC 7512 URI.
+type pkcs11URI struct {
+	// Token is the label of the token that holds the key.
+	Token string
+	// Object is the label of the key.
+	Object string
+	// ID is the CKA_ID of the key.
+	ID []byte
+	// ModulePath is the path of the PKCS#11 module; if not set, it is read from the KOPS_PKCS11_MODULE env var.
+	ModulePath string
+	// PIN is the user PIN for the token.
+	PIN string
+}
+
+// parsePKCS11URI parses an RFC 7512 URI such as
+// pkcs11:token=kops;object=kubernetes-ca?module-path=/usr/lib/softhsm/libsofthsm2.so&pin-source=/etc/kops/pin
+func parsePKCS11URI(uri string) (*pkcs11URI, error) {
+	rest, found := strings.CutPrefix(uri, SchemePKCS11+":")
+	if !found {
+		return nil, fmt.Errorf("invalid PKCS#11 URI %q", uri)
+	}
+	path, query, _ := strings.Cut(rest, "?")
+
+	u := &pkcs11URI{}
+	for _, attr := range strings.Split(path, ";") {
+		if attr == "" {
+			continue
+		}
+		k, v, _ := strings.Cut(attr, "=")
+		value, err := url.PathUnescape(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %q in PKCS#11 URI %q: %w", k, uri, err)
+		}
+		switch k {
+		case "token":
+			u.Token = value
+		case "object":
+			u.Object = value
+		case "id":
+			u.ID = []byte(value)
+		case "type":
+			if value != "private" {
+				return nil, fmt.Errorf("PKCS#11 URI %q must reference a private key", uri)
+			}
+		default:
+			// Other attributes, such as the manufacturer, are not needed to find the key.
+		}
+	}
+
+	for _, attr := range strings.Split(query, "&") {
+		if attr == "" {
+			continue
+		}
+		k, v, _ := strings.Cut(attr, "=")
+		value, err := url.PathUnescape(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %q in PKCS#11 URI %q: %w", k, uri, err)
+		}
+		switch k {
+		case "module-path":
+			u.ModulePath = value
+		case "pin-value":
+			u.PIN = value
+		case "pin-source":
+			pin, err := os.ReadFile(strings.TrimPrefix(value, "file:"))
+			if err != nil {
+				return nil, fmt.Errorf("error reading PIN for PKCS#11 URI %q: %w", uri, err)
+			}
+			u.PIN = strings.TrimSpace(string(pin))
+		}
+	}
+
+	if u.ModulePath == "" {
+		u.ModulePath = os.Getenv("KOPS_PKCS11_MODULE")
+	}
+	if u.ModulePath == "" {
+		return nil, fmt.Errorf("PKCS#11 URI %q does not set module-path, and KOPS_PKCS11_MODULE is not set", uri)
+	}
+	if u.Token == "" {
+		return nil, fmt.Errorf("PKCS#11 URI %q does not set the token", uri)
+	}
+	if u.Object == "" && len(u.ID) == 0 {
+		return nil, fmt.Errorf("PKCS#11 URI %q sets neither the object nor the id of the key", uri)
+	}
+	return u, nil
+}
@@ -33,6 +33,7 @@ import (
 
 	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/bootstrap"
+	"k8s.io/kops/pkg/bootstrap/tpmbootstrap"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup"
 )
@@ -42,6 +43,8 @@ type Client struct {
 	Authenticator bootstrap.Authenticator
 	// CAs are the CA certificates for kops-controller.
 	CAs []byte
+	// ClientCertificate, if set, is presented to kops-controller; it is used instead of an Authenticator to renew certificates.
+	ClientCertificate *tls.Certificate
 
 	// BaseURL is the base URL for the server
 	BaseURL url.URL
@@ -49,16 +52,48 @@ type Client struct {
 	httpClient *http.Client
 }
 
+// Query sends a bootstrap request to kops-controller.
 func (b *Client) Query(ctx context.Context, req any, resp any) error {
+	return b.query(ctx, "/bootstrap", true, req, resp)
+}
+
+// Renew sends a certificate renewal request to kops-controller, authenticated with the ClientCertificate.
+func (b *Client) Renew(ctx context.Context, req any, resp any) error {
+	if b.ClientCertificate == nil {
+		return fmt.Errorf("a client certificate is required to renew certificates")
+	}
+	return b.query(ctx, "/renew", true, req, resp)
+}
+
+// GetNodeConfig asks kops-controller for the node's current configuration, authenticated with the ClientCertificate.
+func (b *Client) GetNodeConfig(ctx context.Context, req any, resp any) error {
+	if b.ClientCertificate == nil {
+		return fmt.Errorf("a client certificate is required to get the node configuration")
+	}
+	return b.query(ctx, "/node-config", true, req, resp)
+}
+
+// IssueTPMCredential asks kops-controller for a credential to activate with the node's TPM.
+// The request is not authenticated; the credential can only be used by the TPM it was issued for.
+func (b *Client) IssueTPMCredential(ctx context.Context, req any, resp any) error {
+	return b.query(ctx, tpmbootstrap.CredentialPath, false, req, resp)
+}
+
+func (b *Client) query(ctx context.Context, requestPath string, authenticate bool, req any, resp any) error {
 	if b.httpClient == nil {
 		certPool := x509.NewCertPool()
 		certPool.AppendCertsFromPEM(b.CAs)
 
+		tlsConfig := &tls.Config{
+			RootCAs:    certPool,
+			MinVersion: tls.VersionTLS12,
+		}
+		if b.ClientCertificate != nil {
+			tlsConfig.Certificates = []tls.Certificate{*b.ClientCertificate}
+		}
+
 		transport := &http.Transport{
-			TLSClientConfig: &tls.Config{
-				RootCAs:    certPool,
-				MinVersion: tls.VersionTLS12,
-			},
+			TLSClientConfig: tlsConfig,
 		}
 
 		httpClient := &http.Client{
@@ -84,19 +119,21 @@ func (b *Client) Query(ctx context.Context, req any, resp any) error {
 		return err
 	}
 
-	bootstrapURL := b.BaseURL
-	bootstrapURL.Path = path.Join(bootstrapURL.Path, "/bootstrap")
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", bootstrapURL.String(), bytes.NewReader(reqBytes))
+	requestURL := b.BaseURL
+	requestURL.Path = path.Join(requestURL.Path, requestPath)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", requestURL.String(), bytes.NewReader(reqBytes))
 	if err != nil {
 		return err
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
 
-	token, err := b.Authenticator.CreateToken(reqBytes)
-	if err != nil {
-		return err
+	if authenticate && b.Authenticator != nil {
+		token, err := b.Authenticator.CreateToken(reqBytes)
+		if err != nil {
+			return err
+		}
+		httpReq.Header.Set("Authorization", token)
 	}
-	httpReq.Header.Set("Authorization", token)
 
 	response, err := b.httpClient.Do(httpReq)
 	if err != nil {
@@ -0,0 +1,145 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509/pkix"
+	"fmt"
+	"time"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/acls"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/pkg/rbac"
+	"k8s.io/kops/pkg/util/localuser"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/vfs"
+	"sigs.k8s.io/yaml"
+)
+
+// AdminCredentialAuditRecord records the issuance of a cluster admin certificate
+type AdminCredentialAuditRecord struct {
+	// IssuedAt is when the certificate was issued
+	IssuedAt time.Time `json:"issuedAt"`
+	// User is the local user that requested the certificate
+	User string `json:"user,omitempty"`
+	// Host is the host on which the certificate was requested
+	Host string `json:"host,omitempty"`
+	// CommonName is the subject common name of the certificate
+	CommonName string `json:"commonName"`
+	// SerialNumber is the serial number of the certificate
+	SerialNumber string `json:"serialNumber"`
+	// NotAfter is when the certificate expires
+	NotAfter time.Time `json:"notAfter"`
+	// Signer is the keyset that signed the certificate
+	Signer string `json:"signer"`
+	// SignerKeypairID is the id of the keypair that signed the certificate
+	SignerKeypairID string `json:"signerKeypairID"`
+}
+
+// IssueAdminCertificate issues a cluster admin client certificate, applying the cluster's admin credentials policy:
+// the lifetime is capped, the certificate is signed by the admin CA and the issuance is recorded in the state store.
+func IssueAdminCertificate(ctx context.Context, cluster *kops.Cluster, keyStore fi.KeystoreReader, lifetime time.Duration) (*pki.Certificate, *pki.PrivateKey, error) {
+	var policy *kops.AdminCredentialsSpec
+	if cluster.Spec.Authentication != nil {
+		policy = cluster.Spec.Authentication.AdminCredentials
+	}
+
+	cn := "kubecfg"
+	local := localuser.Current()
+	if local.Username != "" {
+		cn += "-" + local.Name
+	}
+
+	req := pki.IssueCertRequest{
+		Signer: fi.CertificateIDCA,
+		Type:   "client",
+		Subject: pkix.Name{
+			CommonName:   cn,
+			Organization: []string{rbac.SystemPrivilegedGroup},
+		},
+		Validity: lifetime,
+	}
+	if policy != nil {
+		req.Signer = fi.CertificateIDAdminCA
+		if policy.MaxLifetime != nil {
+			// The cap is applied when signing, so that it also covers the default lifetime
+			req.MaxValidity = policy.MaxLifetime.Duration
+			if lifetime > req.MaxValidity {
+				klog.Warningf("reducing the lifetime of the admin credential from %v to the cluster's maximum of %v", lifetime, req.MaxValidity)
+			}
+		}
+	}
+	cert, privateKey, caCertificate, err := pki.IssueCert(ctx, &req, fi.NewPKIKeystoreAdapter(keyStore))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if policy != nil {
+		record := &AdminCredentialAuditRecord{
+			IssuedAt:        time.Now().UTC(),
+			User:            local.Username,
+			Host:            local.Host,
+			CommonName:      cn,
+			SerialNumber:    cert.Certificate.SerialNumber.String(),
+			NotAfter:        cert.Certificate.NotAfter.UTC(),
+			Signer:          req.Signer,
+			SignerKeypairID: caCertificate.Certificate.SerialNumber.String(),
+		}
+
+		// Without the audit record, the credential must not be handed out
+		if err := writeAdminCredentialAuditRecord(ctx, cluster, record); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return cert, privateKey, nil
+}
+
+// AdminCredentialAuditPath returns the state store directory holding the admin credential audit records of a cluster.
+func AdminCredentialAuditPath(cluster *kops.Cluster) (vfs.Path, error) {
+	configBase, err := vfs.Context.BuildVfsPath(cluster.Spec.ConfigStore.Base)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing config base %q: %w", cluster.Spec.ConfigStore.Base, err)
+	}
+	return configBase.Join("audit", "admin-credentials"), nil
+}
+
+// writeAdminCredentialAuditRecord stores one object per issuance, as the state store does not support appending.
+func writeAdminCredentialAuditRecord(ctx context.Context, cluster *kops.Cluster, record *AdminCredentialAuditRecord) error {
+	auditPath, err := AdminCredentialAuditPath(cluster)
+	if err != nil {
+		return err
+	}
+	p := auditPath.Join(record.IssuedAt.Format("20060102T150405Z") + "-" + record.SerialNumber + ".yaml")
+
+	b, err := yaml.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("error serializing admin credential audit record: %w", err)
+	}
+	acl, err := acls.GetACL(ctx, p, cluster)
+	if err != nil {
+		return err
+	}
+	if err := p.CreateFile(ctx, bytes.NewReader(b), acl); err != nil {
+		return fmt.Errorf("error writing admin credential audit record %s: %w", p, err)
+	}
+	return nil
+}
@@ -18,18 +18,15 @@ package kubeconfig
 
 import (
 	"context"
-	"crypto/x509/pkix"
 	"fmt"
 	"net"
-	"os/user"
 	"sort"
 	"time"
 
 	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/apis/kops"
-	"k8s.io/kops/pkg/pki"
-	"k8s.io/kops/pkg/rbac"
 	"k8s.io/kops/upup/pkg/fi"
 )
 
@@ -54,6 +51,10 @@ type CreateKubecfgOptions struct {
 	// UseKopsAuthenticationPlugin controls whether we should use the kOps auth helper instead of a static credential
 	UseKopsAuthenticationPlugin bool
 
+	// UseOIDCDeviceFlow controls whether we should use an OIDC device-code login, configured from the cluster's
+	// OIDC authentication settings, instead of a static credential
+	UseOIDCDeviceFlow bool
+
 	// UseKubeconfig controls whether to use the local kubeconfig instead of generating a new one.
 	// See issue https://github.com/kubernetes/kops/issues/17262
 	UseKubeconfig bool
@@ -167,24 +168,7 @@ func BuildKubecfg(ctx context.Context, cluster *kops.Cluster, keyStore fi.Keysto
 	}
 
 	if options.Admin != 0 {
-		cn := "kubecfg"
-		user, err := user.Current()
-		if err != nil || user == nil {
-			klog.Infof("unable to get user: %v", err)
-		} else {
-			cn += "-" + user.Name
-		}
-
-		req := pki.IssueCertRequest{
-			Signer: fi.CertificateIDCA,
-			Type:   "client",
-			Subject: pkix.Name{
-				CommonName:   cn,
-				Organization: []string{rbac.SystemPrivilegedGroup},
-			},
-			Validity: options.Admin,
-		}
-		cert, privateKey, _, err := pki.IssueCert(ctx, &req, fi.NewPKIKeystoreAdapter(keyStore))
+		cert, privateKey, err := IssueAdminCertificate(ctx, cluster, keyStore, options.Admin)
 		if err != nil {
 			return nil, err
 		}
@@ -212,6 +196,18 @@ func BuildKubecfg(ctx context.Context, cluster *kops.Cluster, keyStore fi.Keysto
 		b.ClientKey = nil
 	}
 
+	if options.UseOIDCDeviceFlow {
+		exec, err := buildOIDCAuthenticationExec(cluster)
+		if err != nil {
+			return nil, err
+		}
+		b.AuthenticationExec = exec
+
+		// The OIDC login replaces any static credential
+		b.ClientCert = nil
+		b.ClientKey = nil
+	}
+
 	b.Server = server
 
 	if options.User == "" {
@@ -223,6 +219,45 @@ func BuildKubecfg(ctx context.Context, cluster *kops.Cluster, keyStore fi.Keysto
 	return b, nil
 }
 
+// buildOIDCAuthenticationExec builds the exec credential plugin invocation that logs in
+// to the cluster's OIDC issuer using the device authorization grant.
+func buildOIDCAuthenticationExec(cluster *kops.Cluster) ([]string, error) {
+	var oidc *kops.OIDCAuthenticationSpec
+	if cluster.Spec.Authentication != nil {
+		oidc = cluster.Spec.Authentication.OIDC
+	}
+	if oidc == nil || fi.ValueOf(oidc.IssuerURL) == "" || fi.ValueOf(oidc.ClientID) == "" {
+		return nil, fmt.Errorf("cluster %q does not have OIDC authentication configured; spec.authentication.oidc.issuerURL and clientID must be set", cluster.ObjectMeta.Name)
+	}
+
+	exec := []string{
+		"kops",
+		"helpers",
+		"kubectl-oidc-login",
+		"--issuer-url=" + fi.ValueOf(oidc.IssuerURL),
+		"--client-id=" + fi.ValueOf(oidc.ClientID),
+	}
+
+	// Request the scopes that carry the claims the API server maps to the username and groups.
+	scopes := sets.New[string]()
+	switch fi.ValueOf(oidc.UsernameClaim) {
+	case "email", "email_verified":
+		scopes.Insert("email")
+	case "name", "preferred_username", "nickname":
+		scopes.Insert("profile")
+	}
+	for _, claim := range oidc.GroupsClaims {
+		if claim == "groups" {
+			scopes.Insert("groups")
+		}
+	}
+	for _, scope := range sets.List(scopes) {
+		exec = append(exec, "--extra-scope="+scope)
+	}
+
+	return exec, nil
+}
+
 // wrapIPv6Address will wrap IPv6 addresses in square brackets,
 // for use in URLs; other endpoints are unchanged.
 func wrapIPv6Address(endpoint string) string {
@@ -18,9 +18,13 @@ package kubeconfig
 
 import (
 	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"testing"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/kops/dnsprovider/pkg/dnsprovider"
 	"k8s.io/kops/pkg/cloudinstances"
 	"k8s.io/kops/pkg/testutils"
@@ -30,6 +34,7 @@ import (
 	"k8s.io/kops/pkg/pki"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/util/pkg/vfs"
+	"sigs.k8s.io/yaml"
 )
 
 const (
@@ -160,6 +165,15 @@ func TestBuildKubecfg(t *testing.T) {
 	certCluster := buildMinimalCluster("testcluster", "testcluster.test.com", true, false)
 	certNLBCluster := buildMinimalCluster("testcluster", "testcluster.test.com", true, true)
 	certGossipNLBCluster := buildMinimalCluster("testgossipcluster.k8s.local", "", true, true)
+	oidcCluster := buildMinimalCluster("testcluster", "testcluster.test.com", false, false)
+	oidcCluster.Spec.Authentication = &kops.AuthenticationSpec{
+		OIDC: &kops.OIDCAuthenticationSpec{
+			IssuerURL:     fi.PtrTo("https://issuer.example.com"),
+			ClientID:      fi.PtrTo("kubernetes"),
+			UsernameClaim: fi.PtrTo("email"),
+			GroupsClaims:  []string{"groups"},
+		},
+	}
 
 	fakeStatus := fakeStatusCloud{
 		GetApiIngressStatusFn: func(cluster *kops.Cluster) ([]fi.ApiIngressStatus, error) {
@@ -330,6 +344,44 @@ func TestBuildKubecfg(t *testing.T) {
 			},
 			wantClientCert: false,
 		},
+		{
+			name: "Public DNS with OIDC device flow",
+			args: args{
+				cluster: oidcCluster,
+				status:  fakeStatus,
+				CreateKubecfgOptions: CreateKubecfgOptions{
+					UseOIDCDeviceFlow: true,
+				},
+			},
+			want: &KubeconfigBuilder{
+				Context:       "testcluster",
+				Server:        "https://testcluster.test.com",
+				TLSServerName: "api.internal.testcluster",
+				CACerts:       []byte(nextCertificate + certData),
+				User:          "testcluster",
+				AuthenticationExec: []string{
+					"kops",
+					"helpers",
+					"kubectl-oidc-login",
+					"--issuer-url=https://issuer.example.com",
+					"--client-id=kubernetes",
+					"--extra-scope=email",
+					"--extra-scope=groups",
+				},
+			},
+			wantClientCert: false,
+		},
+		{
+			name: "OIDC device flow without OIDC authentication",
+			args: args{
+				cluster: publicCluster,
+				status:  fakeStatus,
+				CreateKubecfgOptions: CreateKubecfgOptions{
+					UseOIDCDeviceFlow: true,
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "Test Kube Config Data For internal DNS name with admin",
 			args: args{
@@ -460,3 +512,145 @@ func TestBuildKubecfg(t *testing.T) {
 		})
 	}
 }
+
+func TestIssueAdminCertificatePolicy(t *testing.T) {
+	originalPKIDefaultPrivateKeySize := pki.DefaultPrivateKeySize
+	pki.DefaultPrivateKeySize = 2048
+	defer func() {
+		pki.DefaultPrivateKeySize = originalPKIDefaultPrivateKeySize
+	}()
+
+	ctx := context.TODO()
+	vfs.Context.ResetMemfsContext(true)
+
+	cluster := buildMinimalCluster("testcluster", "testcluster.test.com", false, false)
+	cluster.Spec.Authentication = &kops.AuthenticationSpec{
+		AdminCredentials: &kops.AdminCredentialsSpec{
+			MaxLifetime: &metav1.Duration{Duration: time.Hour},
+		},
+	}
+
+	var signers []string
+	keyStore := fakeKeyStore{
+		FindKeysetFn: func(name string) (*fi.Keyset, error) {
+			signers = append(signers, name)
+			return fakeKeyset(), nil
+		},
+	}
+
+	cert, _, err := IssueAdminCertificate(ctx, cluster, keyStore, 48*time.Hour)
+	if err != nil {
+		t.Fatalf("IssueAdminCertificate() error = %v", err)
+	}
+	if len(signers) != 1 || signers[0] != fi.CertificateIDAdminCA {
+		t.Errorf("expected certificate to be signed by %q, was signed by %v", fi.CertificateIDAdminCA, signers)
+	}
+	if lifetime := time.Until(cert.Certificate.NotAfter); lifetime > time.Hour {
+		t.Errorf("expected lifetime to be capped to 1h, got %v", lifetime)
+	}
+
+	auditPath, err := AdminCredentialAuditPath(cluster)
+	if err != nil {
+		t.Fatalf("AdminCredentialAuditPath() error = %v", err)
+	}
+	files, err := auditPath.ReadDir()
+	if err != nil {
+		t.Fatalf("reading audit records: %v", err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("expected 1 audit record, got %d", len(files))
+	}
+	b, err := files[0].ReadFile(ctx)
+	if err != nil {
+		t.Fatalf("reading audit record: %v", err)
+	}
+	record := &AdminCredentialAuditRecord{}
+	if err := yaml.Unmarshal(b, record); err != nil {
+		t.Fatalf("parsing audit record: %v", err)
+	}
+	if record.SerialNumber != cert.Certificate.SerialNumber.String() {
+		t.Errorf("audit record serial %q does not match certificate serial %q", record.SerialNumber, cert.Certificate.SerialNumber)
+	}
+	if record.Signer != fi.CertificateIDAdminCA || record.SignerKeypairID == "" {
+		t.Errorf("unexpected audit record signer %q/%q", record.Signer, record.SignerKeypairID)
+	}
+
+	// The cap also applies when no lifetime is requested
+	cert, _, err = IssueAdminCertificate(ctx, cluster, keyStore, 0)
+	if err != nil {
+		t.Fatalf("IssueAdminCertificate() error = %v", err)
+	}
+	if lifetime := time.Until(cert.Certificate.NotAfter); lifetime > time.Hour {
+		t.Errorf("expected default lifetime to be capped to 1h, got %v", lifetime)
+	}
+}
+
+func TestIssueAdminCertificateDistrustedCA(t *testing.T) {
+	originalPKIDefaultPrivateKeySize := pki.DefaultPrivateKeySize
+	pki.DefaultPrivateKeySize = 2048
+	defer func() {
+		pki.DefaultPrivateKeySize = originalPKIDefaultPrivateKeySize
+	}()
+
+	ctx := context.TODO()
+	vfs.Context.ResetMemfsContext(true)
+
+	cluster := buildMinimalCluster("testcluster", "testcluster.test.com", false, false)
+	cluster.Spec.Authentication = &kops.AuthenticationSpec{
+		AdminCredentials: &kops.AdminCredentialsSpec{},
+	}
+
+	// Both CAs are self-signed roots, as created by update cluster
+	keysets := map[string]*fi.Keyset{}
+	for _, name := range []string{fi.CertificateIDCA, fi.CertificateIDAdminCA} {
+		cert, key, _, err := pki.IssueCert(ctx, &pki.IssueCertRequest{
+			Type:    "ca",
+			Subject: pkix.Name{CommonName: name},
+		}, nil)
+		if err != nil {
+			t.Fatalf("error issuing %s: %v", name, err)
+		}
+		keysets[name], err = fi.NewKeyset(cert, key)
+		if err != nil {
+			t.Fatalf("error building %s keyset: %v", name, err)
+		}
+	}
+	keyStore := fakeKeyStore{
+		FindKeysetFn: func(name string) (*fi.Keyset, error) {
+			return keysets[name], nil
+		},
+	}
+
+	cert, _, err := IssueAdminCertificate(ctx, cluster, keyStore, time.Hour)
+	if err != nil {
+		t.Fatalf("IssueAdminCertificate() error = %v", err)
+	}
+
+	clusterCA := keysets[fi.CertificateIDCA].Primary.Certificate.Certificate
+	adminCA := keysets[fi.CertificateIDAdminCA].Primary.Certificate.Certificate
+
+	// Presenting the admin CA as an intermediate must not let the certificate chain to the cluster CA
+	intermediates := x509.NewCertPool()
+	intermediates.AddCert(adminCA)
+
+	distrusted := x509.NewCertPool()
+	distrusted.AddCert(clusterCA)
+	if _, err := cert.Certificate.Verify(x509.VerifyOptions{
+		Roots:         distrusted,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}); err == nil {
+		t.Errorf("expected admin certificate to be rejected once the admin CA is distrusted")
+	}
+
+	trusted := x509.NewCertPool()
+	trusted.AddCert(clusterCA)
+	trusted.AddCert(adminCA)
+	if _, err := cert.Certificate.Verify(x509.VerifyOptions{
+		Roots:         trusted,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}); err != nil {
+		t.Errorf("expected admin certificate to be accepted while the admin CA is trusted: %v", err)
+	}
+}
@@ -41,6 +41,8 @@ type IAMModelBuilder struct {
 	*AWSModelContext
 	Lifecycle fi.Lifecycle
 	Cluster   *kops.Cluster
+	// KMSSigningKeys are the IDs of the AWS KMS keys that hold the private keys of keysets.
+	KMSSigningKeys []string
 }
 
 var (
@@ -234,6 +236,7 @@ func (b *IAMModelBuilder) buildIAMRolePolicy(role iam.Subject, iamName string, i
 			Role:                                  role,
 			Region:                                b.Region,
 			Partition:                             b.AWSPartition,
+			KMSSigningKeys:                        b.KMSSigningKeys,
 			UseServiceAccountExternalPermisssions: b.UseServiceAccountExternalPermissions(),
 		},
 	}
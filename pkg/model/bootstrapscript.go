@@ -181,6 +181,14 @@ func KeypairNamesForInstanceGroup(cluster *kops.Cluster, ig *kops.InstanceGroup)
 
 	if ig.HasAPIServer() {
 		keypairs = append(keypairs, "apiserver-aggregator-ca", "service-account", "etcd-clients-ca")
+		if cluster.Spec.Authentication != nil && cluster.Spec.Authentication.AdminCredentials != nil {
+			keypairs = append(keypairs, fi.CertificateIDAdminCA)
+		}
+	}
+
+	// The control plane applies the channels, so needs the keys that sign them
+	if ig.IsControlPlane() && cluster.UsesAddonSigning() {
+		keypairs = append(keypairs, fi.CertificateIDAddonSigning)
 	}
 
 	// Add keypairs for cilium etcd clusters (not the default etcd clusters)
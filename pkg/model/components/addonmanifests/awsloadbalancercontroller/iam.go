@@ -35,12 +35,16 @@ func (r *ServiceAccount) BuildAWSPolicy(b *iam.PolicyBuilder) (*iam.Policy, erro
 	var enableWAF bool
 	var enableWAFv2 bool
 	var enableShield bool
+	var enableGatewayAPI bool
 	if c := b.Cluster.Spec.CloudProvider.AWS.LoadBalancerController; c != nil {
 		enableWAF = c.EnableWAF
 		enableWAFv2 = c.EnableWAFv2
 		enableShield = c.EnableShield
 	}
-	iam.AddAWSLoadbalancerControllerPermissions(p, enableWAF, enableWAFv2, enableShield)
+	if g := b.Cluster.Spec.GatewayAPI; g != nil && g.Enabled != nil {
+		enableGatewayAPI = *g.Enabled
+	}
+	iam.AddAWSLoadbalancerControllerPermissions(p, enableWAF, enableWAFv2, enableShield, enableGatewayAPI)
 
 	return p, nil
 }
@@ -187,3 +187,8 @@ func Image(component string, clusterSpec *kops.ClusterSpec, assetsBuilder *asset
 func IsCertManagerEnabled(cluster *kops.Cluster) bool {
 	return cluster.Spec.CertManager != nil && fi.ValueOf(cluster.Spec.CertManager.Enabled)
 }
+
+// IsGatewayAPIEnabled returns true if the Gateway API CRDs are managed by kOps.
+func IsGatewayAPIEnabled(cluster *kops.Cluster) bool {
+	return cluster.Spec.GatewayAPI != nil && fi.ValueOf(cluster.Spec.GatewayAPI.Enabled)
+}
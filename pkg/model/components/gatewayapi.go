@@ -0,0 +1,73 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/loader"
+)
+
+// GatewayAPIVersions are the Gateway API releases whose CRDs are bundled with kOps.
+var GatewayAPIVersions = []string{"v1.4.0"}
+
+// GatewayAPIOptionsBuilder adds options for the Gateway API CRDs to the model.
+type GatewayAPIOptionsBuilder struct {
+	*OptionsContext
+}
+
+var _ loader.ClusterOptionsBuilder = &GatewayAPIOptionsBuilder{}
+
+func (b *GatewayAPIOptionsBuilder) BuildOptions(o *kops.Cluster) error {
+	clusterSpec := &o.Spec
+	if clusterSpec.GatewayAPI == nil {
+		return nil
+	}
+	gatewayAPI := clusterSpec.GatewayAPI
+
+	if gatewayAPI.Enabled == nil {
+		gatewayAPI.Enabled = fi.PtrTo(false)
+	}
+
+	if gatewayAPI.Channel == "" {
+		if GatewayAPIExperimentalRequiredBy(o) != "" {
+			gatewayAPI.Channel = kops.GatewayAPIChannelExperimental
+		} else {
+			gatewayAPI.Channel = kops.GatewayAPIChannelStandard
+		}
+	}
+
+	if gatewayAPI.Version == "" {
+		gatewayAPI.Version = GatewayAPIVersions[len(GatewayAPIVersions)-1]
+	}
+
+	return nil
+}
+
+// GatewayAPIExperimentalRequiredBy returns the Gateway implementation managed by kOps that requires
+// the experimental channel of the Gateway API CRDs, or "" if the standard channel is sufficient.
+func GatewayAPIExperimentalRequiredBy(cluster *kops.Cluster) string {
+	// Cilium's Gateway API support requires the experimental resources, such as TLSRoute
+	if cilium := cluster.Spec.Networking.Cilium; cilium != nil && cilium.GatewayAPI != nil && fi.ValueOf(cilium.GatewayAPI.Enabled) {
+		return "Cilium Gateway API support"
+	}
+	// NLB gateways of the AWS Load Balancer Controller route TCPRoutes and UDPRoutes
+	if aws := cluster.Spec.CloudProvider.AWS; aws != nil && aws.LoadBalancerController != nil && fi.ValueOf(aws.LoadBalancerController.Enabled) {
+		return "AWS Load Balancer Controller NLB gateway support"
+	}
+	return ""
+}
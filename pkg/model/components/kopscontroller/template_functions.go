@@ -17,12 +17,15 @@ limitations under the License.
 package kopscontroller
 
 import (
+	"strconv"
 	"text/template"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	kopscontrollerconfig "k8s.io/kops/cmd/kops-controller/pkg/config"
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/model"
 	"k8s.io/kops/pkg/featureflag"
 	"k8s.io/kops/pkg/model/components/etcdmanager"
 	"k8s.io/kops/pkg/wellknownports"
@@ -43,6 +46,15 @@ type templateFunctions struct {
 	Cluster *kops.Cluster
 }
 
+// NodeConfigLeaseNames returns the names of the Leases that limit how many nodes apply a configuration change at the same time.
+func (t *templateFunctions) NodeConfigLeaseNames() []string {
+	var names []string
+	for i := 0; i < model.NodeConfigMaxUnavailable(t.Cluster); i++ {
+		names = append(names, kopscontrollerconfig.NodeConfigLeasePrefix+strconv.Itoa(i))
+	}
+	return names
+}
+
 // KopsControllerConfig returns the yaml configuration for kops-controller
 func (t *templateFunctions) GossipServices() ([]*corev1.Service, error) {
 	if !t.Cluster.UsesLegacyGossip() {
@@ -22,6 +22,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	kopsversion "k8s.io/kops"
 	"k8s.io/kops/pkg/apis/kops"
+	apimodel "k8s.io/kops/pkg/apis/kops/model"
 	"k8s.io/kops/pkg/assets"
 	"k8s.io/kops/pkg/k8scodecs"
 	"k8s.io/kops/pkg/model"
@@ -141,5 +142,10 @@ func (b *KubeApiserverBuilder) buildHealthcheckSidecar() (*corev1.Pod, error) {
 	// Remap image via AssetBuilder
 	container.Image = b.AssetBuilder.RemapImage(container.Image)
 
+	// The monitoring addon scrapes the metrics of the sidecar
+	if apimodel.UseMonitoring(b.Cluster) {
+		container.Args = append(container.Args, "--metrics")
+	}
+
 	return pod, nil
 }
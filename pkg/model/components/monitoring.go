@@ -0,0 +1,71 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/loader"
+)
+
+// MonitoringOptionsBuilder adds options for the monitoring addon to the model.
+type MonitoringOptionsBuilder struct {
+	*OptionsContext
+}
+
+var _ loader.ClusterOptionsBuilder = &MonitoringOptionsBuilder{}
+
+func (b *MonitoringOptionsBuilder) BuildOptions(o *kops.Cluster) error {
+	clusterSpec := &o.Spec
+	if clusterSpec.Monitoring == nil {
+		return nil
+	}
+	monitoring := clusterSpec.Monitoring
+
+	if monitoring.Enabled == nil {
+		monitoring.Enabled = fi.PtrTo(false)
+	}
+
+	if monitoring.Image == nil {
+		monitoring.Image = fi.PtrTo("quay.io/prometheus/prometheus:v3.7.3")
+	}
+
+	if monitoring.ScrapeInterval == nil {
+		monitoring.ScrapeInterval = &metav1.Duration{Duration: 30 * time.Second}
+	}
+
+	if monitoring.CPURequest == nil {
+		defaultCPURequest := resource.MustParse("50m")
+		monitoring.CPURequest = &defaultCPURequest
+	}
+
+	if monitoring.MemoryRequest == nil {
+		defaultMemoryRequest := resource.MustParse("200Mi")
+		monitoring.MemoryRequest = &defaultMemoryRequest
+	}
+
+	if monitoring.MemoryLimit == nil {
+		defaultMemoryLimit := resource.MustParse("400Mi")
+		monitoring.MemoryLimit = &defaultMemoryLimit
+	}
+
+	return nil
+}
@@ -193,6 +193,12 @@ func (b *AutoscalingGroupModelBuilder) buildInstanceTemplate(c *fi.CloudupModelB
 			if err != nil {
 				return nil, err
 			}
+			// The individual writeable files need the storage-rw scope too
+			writeableFiles, err := iam.WriteableVFSFiles(b.Cluster, nodeRole)
+			if err != nil {
+				return nil, err
+			}
+			storagePaths = append(storagePaths, writeableFiles...)
 			if len(storagePaths) == 0 {
 				t.Scopes = append(t.Scopes, "storage-ro")
 			} else {
@@ -84,6 +84,12 @@ func (b *StorageAclBuilder) Build(c *fi.CloudupModelBuilderContext) error {
 		if err != nil {
 			return err
 		}
+		// GCS permissions are per bucket, so the individual writeable files need write access to their buckets too
+		writeableFiles, err := iam.WriteableVFSFiles(b.Cluster, nodeRole)
+		if err != nil {
+			return err
+		}
+		writeablePaths = append(writeablePaths, writeableFiles...)
 
 		buckets := sets.NewString()
 		for _, p := range writeablePaths {
@@ -151,6 +157,12 @@ func (b *StorageAclBuilder) Build(c *fi.CloudupModelBuilderContext) error {
 		if err != nil {
 			return err
 		}
+		// GCS permissions are per bucket, so the individual writeable files need write access to their buckets too
+		writeableFiles, err := iam.WriteableVFSFiles(b.Cluster, nodeRole)
+		if err != nil {
+			return err
+		}
+		writeablePaths = append(writeablePaths, writeableFiles...)
 		for _, p := range writeablePaths {
 			gcsPath, ok := p.(*vfs.GSPath)
 			if !ok {
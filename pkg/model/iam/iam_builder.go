@@ -33,11 +33,15 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/apis/kops/model"
+	"k8s.io/kops/pkg/apis/kops/registry"
+	"k8s.io/kops/pkg/bootstrap"
+	"k8s.io/kops/pkg/bootstrap/pkibootstrap"
 	"k8s.io/kops/pkg/util/stringorset"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awstasks"
@@ -307,6 +311,7 @@ type PolicyBuilder struct {
 	Cluster                               *kops.Cluster
 	HostedZoneID                          string
 	KMSKeys                               []string
+	KMSSigningKeys                        []string
 	Region                                string
 	Partition                             string
 	ResourceARN                           *string
@@ -403,6 +408,7 @@ func (r *NodeRoleMaster) BuildAWSPolicy(b *PolicyBuilder) (*Policy, error) {
 	}
 
 	addKMSIAMPolicies(p)
+	b.addKMSSignPolicies(p)
 
 	// Protokube needs dns-controller permissions in instance role even if UseServiceAccountExternalPermissions.
 	AddDNSControllerPermissions(b, p)
@@ -415,7 +421,7 @@ func (r *NodeRoleMaster) BuildAWSPolicy(b *PolicyBuilder) (*Policy, error) {
 		AddCCMPermissions(p, b.Cluster.Spec.Networking.Kubenet != nil)
 
 		if c := b.Cluster.Spec.CloudProvider.AWS.LoadBalancerController; c != nil && fi.ValueOf(b.Cluster.Spec.CloudProvider.AWS.LoadBalancerController.Enabled) {
-			AddAWSLoadbalancerControllerPermissions(p, c.EnableWAF, c.EnableWAFv2, c.EnableShield)
+			AddAWSLoadbalancerControllerPermissions(p, c.EnableWAF, c.EnableWAFv2, c.EnableShield, b.Cluster.Spec.GatewayAPI != nil && fi.ValueOf(b.Cluster.Spec.GatewayAPI.Enabled))
 		}
 
 		var useStaticInstanceList bool
@@ -454,6 +460,10 @@ func (r *NodeRoleMaster) BuildAWSPolicy(b *PolicyBuilder) (*Policy, error) {
 		addKindnetSrcDstCheckPermissions(p)
 	}
 
+	if b.Cluster.Spec.Reconciler != nil {
+		addReconcilerPermissions(p)
+	}
+
 	return p, nil
 }
 
@@ -583,6 +593,13 @@ func (b *PolicyBuilder) AddS3Permissions(p *Policy) error {
 			iamS3path := "placeholder-read-bucket/" + strings.TrimPrefix(path.Path(), "file://")
 			b.buildS3GetStatements(p, iamS3path)
 			s3Buckets.Insert("placeholder-read-bucket")
+		case *vfs.SecretManagerPath:
+			// Only AWS Secrets Manager is authorized with IAM; nodes must be given credentials for other secret managers
+			if path.Scheme() == "awssm" {
+				if err := b.buildSecretsManagerGetStatements(p, path); err != nil {
+					return err
+				}
+			}
 		default:
 			// We could implement this approach, but it seems better to
 			// get all clouds using cluster-readable storage
@@ -611,6 +628,32 @@ func (b *PolicyBuilder) AddS3Permissions(p *Policy) error {
 			iamS3path := "placeholder-read-bucket/" + strings.TrimPrefix(path.Path(), "file://")
 			b.buildS3WriteStatements(p, iamS3path)
 			s3Buckets.Insert("placeholder-read-bucket")
+		case *vfs.SecretManagerPath:
+			if path.Scheme() == "awssm" {
+				b.buildSecretsManagerWriteStatements(p, path)
+			}
+		default:
+			return fmt.Errorf("unknown writeable path, can't apply IAM policy: %q", vfsPath)
+		}
+	}
+
+	writeableFiles, err := WriteableVFSFiles(b.Cluster, b.Role)
+	if err != nil {
+		return err
+	}
+
+	for _, vfsPath := range writeableFiles {
+		switch path := vfsPath.(type) {
+		case *vfs.S3Path:
+			b.buildS3WriteObjectStatements(p, path.Bucket()+"/"+path.Key())
+			s3Buckets.Insert(path.Bucket())
+		case *vfs.MemFSPath:
+			b.buildS3WriteObjectStatements(p, "placeholder-write-bucket/"+path.Location())
+			s3Buckets.Insert("placeholder-write-bucket")
+		case *vfs.SecretManagerPath:
+			if path.Scheme() == "awssm" {
+				b.buildSecretsManagerWriteObjectStatements(p, secretsManagerARN(p, path, ""))
+			}
 		default:
 			return fmt.Errorf("unknown writeable path, can't apply IAM policy: %q", vfsPath)
 		}
@@ -636,6 +679,12 @@ func (b *PolicyBuilder) AddS3Permissions(p *Policy) error {
 }
 
 func (b *PolicyBuilder) buildS3WriteStatements(p *Policy, iamS3Path string) {
+	b.buildS3WriteObjectStatements(p, iamS3Path+"/*")
+}
+
+// buildS3WriteObjectStatements grants write access to the objects matching iamS3Object,
+// which is either the key of a single object or a prefix ending in "*".
+func (b *PolicyBuilder) buildS3WriteObjectStatements(p *Policy, iamS3Object string) {
 	p.Statement = append(p.Statement, &Statement{
 		Effect: StatementEffectAllow,
 		Action: stringorset.Set([]string{
@@ -645,11 +694,63 @@ func (b *PolicyBuilder) buildS3WriteStatements(p *Policy, iamS3Path string) {
 			"s3:PutObject",
 		}),
 		Resource: stringorset.Of(
-			fmt.Sprintf("arn:%v:s3:::%v/*", p.partition, iamS3Path),
+			fmt.Sprintf("arn:%v:s3:::%v", p.partition, iamS3Object),
 		),
 	})
 }
 
+// secretsManagerARN returns the ARN matching the secrets named with the prefix.
+// AWS appends a random suffix to the name in the ARN of each secret.
+func secretsManagerARN(p *Policy, path *vfs.SecretManagerPath, suffix string) string {
+	name := path.Key() + suffix
+	if !strings.HasSuffix(name, "*") {
+		name += "-*"
+	}
+	return fmt.Sprintf("arn:%v:secretsmanager:%v:*:secret:%v", p.partition, path.Host(), name)
+}
+
+func (b *PolicyBuilder) buildSecretsManagerWriteStatements(p *Policy, path *vfs.SecretManagerPath) {
+	b.buildSecretsManagerWriteObjectStatements(p, secretsManagerARN(p, path, "/*"))
+}
+
+// buildSecretsManagerWriteObjectStatements grants write access to the secrets matching the ARN.
+func (b *PolicyBuilder) buildSecretsManagerWriteObjectStatements(p *Policy, arn string) {
+	p.Statement = append(p.Statement, &Statement{
+		Effect: StatementEffectAllow,
+		Action: stringorset.Set([]string{
+			"secretsmanager:CreateSecret",
+			"secretsmanager:DeleteSecret",
+			"secretsmanager:GetSecretValue",
+			"secretsmanager:PutSecretValue",
+		}),
+		Resource: stringorset.Of(arn),
+	})
+	p.unconditionalAction.Insert("secretsmanager:ListSecrets")
+}
+
+func (b *PolicyBuilder) buildSecretsManagerGetStatements(p *Policy, path *vfs.SecretManagerPath) error {
+	resources, err := ReadableStatePaths(b.Cluster, b.Role)
+	if err != nil {
+		return err
+	}
+
+	if len(resources) != 0 {
+		sort.Strings(resources)
+
+		for i, r := range resources {
+			resources[i] = secretsManagerARN(p, path, r)
+		}
+
+		p.Statement = append(p.Statement, &Statement{
+			Effect:   StatementEffectAllow,
+			Action:   stringorset.Set([]string{"secretsmanager:GetSecretValue"}),
+			Resource: stringorset.Of(resources...),
+		})
+		p.unconditionalAction.Insert("secretsmanager:ListSecrets")
+	}
+	return nil
+}
+
 func (b *PolicyBuilder) buildS3GetStatements(p *Policy, iamS3Path string) error {
 	resources, err := ReadableStatePaths(b.Cluster, b.Role)
 	if err != nil {
@@ -695,11 +796,80 @@ func WriteableVFSPaths(cluster *kops.Cluster, role Subject) ([]vfs.Path, error)
 
 			backupStores.Insert(backupStore)
 		}
+
+		// kops-controller writes the bootstrap audit records
+		if auditStore := model.BootstrapAuditStore(cluster); auditStore != "" {
+			vfsPath, err := vfs.Context.BuildVfsPath(auditStore)
+			if err != nil {
+				return nil, fmt.Errorf("cannot parse VFS path %q: %v", auditStore, err)
+			}
+			paths = append(paths, vfsPath)
+		}
+
+		// kops-controller removes revocations when the revoked nodes are deleted
+		if cluster.Spec.NodeIdentityRevocation != nil && cluster.Spec.ConfigStore.Base != "" {
+			configBase, err := vfs.Context.BuildVfsPath(cluster.Spec.ConfigStore.Base)
+			if err != nil {
+				return nil, fmt.Errorf("cannot parse VFS path %q: %v", cluster.Spec.ConfigStore.Base, err)
+			}
+			if _, ok := configBase.(*vfs.KubernetesPath); !ok {
+				paths = append(paths, bootstrap.RevocationListPath(configBase))
+			}
+		}
+
+		// kops-controller records the use of each join token
+		if model.UseJoinTokens(cluster) && cluster.Spec.ConfigStore.Base != "" {
+			configBase, err := vfs.Context.BuildVfsPath(cluster.Spec.ConfigStore.Base)
+			if err != nil {
+				return nil, fmt.Errorf("cannot parse VFS path %q: %v", cluster.Spec.ConfigStore.Base, err)
+			}
+			if _, ok := configBase.(*vfs.KubernetesPath); !ok {
+				paths = append(paths, configBase.Join("join-tokens", "claimed"))
+			}
+		}
+
+		// kops-controller writes the instance groups and their revisions when it reconciles the cluster
+		if cluster.Spec.Reconciler != nil && cluster.Spec.ConfigStore.Base != "" {
+			configBase, err := vfs.Context.BuildVfsPath(cluster.Spec.ConfigStore.Base)
+			if err != nil {
+				return nil, fmt.Errorf("cannot parse VFS path %q: %v", cluster.Spec.ConfigStore.Base, err)
+			}
+			paths = append(paths, configBase.Join("instancegroup"), configBase.Join("history"))
+		}
 	}
 
 	return paths, nil
 }
 
+// WriteableVFSFiles returns the individual files that should be writeable, in addition to the WriteableVFSPaths.
+// A file whose name ends in "*" matches every file with that prefix.
+func WriteableVFSFiles(cluster *kops.Cluster, role Subject) ([]vfs.Path, error) {
+	var files []vfs.Path
+
+	switch role.(type) {
+	case *NodeRoleMaster:
+		// kops-controller writes the cluster, under the state store lock, when it reconciles the cluster
+		if cluster.Spec.Reconciler != nil && cluster.Spec.ConfigStore.Base != "" {
+			configBase, err := vfs.Context.BuildVfsPath(cluster.Spec.ConfigStore.Base)
+			if err != nil {
+				return nil, fmt.Errorf("cannot parse VFS path %q: %v", cluster.Spec.ConfigStore.Base, err)
+			}
+			files = append(files, configBase.Join(registry.PathCluster), configBase.Join(registry.PathLock))
+		}
+
+		// kops-controller deletes each join token from the secret store when it is used
+		if model.UseJoinTokens(cluster) && cluster.Spec.ConfigStore.Secrets != "" {
+			secretsPath, err := vfs.Context.BuildVfsPath(cluster.Spec.ConfigStore.Secrets)
+			if err != nil {
+				return nil, fmt.Errorf("cannot parse VFS path %q: %v", cluster.Spec.ConfigStore.Secrets, err)
+			}
+			files = append(files, secretsPath.Join(pkibootstrap.JoinTokenSecretName("*")))
+		}
+	}
+
+	return files, nil
+}
+
 // ReadableStatePaths returns the file paths that should be readable in the cluster's state store "directory"
 func ReadableStatePaths(cluster *kops.Cluster, role Subject) ([]string, error) {
 	var paths []string
@@ -938,7 +1108,7 @@ func AddCCMPermissions(p *Policy, cloudRoutes bool) {
 }
 
 // AddAWSLoadbalancerControllerPermissions adds the permissions needed for the AWS Load Balancer Controller to the given policy
-func AddAWSLoadbalancerControllerPermissions(p *Policy, enableWAF, enableWAFv2, enableShield bool) {
+func AddAWSLoadbalancerControllerPermissions(p *Policy, enableWAF, enableWAFv2, enableShield, enableGatewayAPI bool) {
 	p.unconditionalAction.Insert(
 		"cognito-idp:DescribeUserPoolClient",
 
@@ -988,6 +1158,13 @@ func AddAWSLoadbalancerControllerPermissions(p *Policy, enableWAF, enableWAFv2,
 		)
 	}
 
+	if enableGatewayAPI {
+		// Gateway listeners reference ELB SSL policies by name, which the controller validates
+		p.unconditionalAction.Insert(
+			"elasticloadbalancing:DescribeSSLPolicies",
+		)
+	}
+
 	if enableShield {
 		p.unconditionalAction.Insert(
 			"shield:GetSubscriptionState",
@@ -1177,6 +1354,74 @@ func addKMSIAMPolicies(p *Policy) {
 	)
 }
 
+// addKMSSignPolicies grants the use of the KMS keys that hold the private keys of keysets,
+// for nodeup and kops-controller to issue certificates with them; the key policy must allow it too.
+// Keys referenced by alias are matched with the kms:ResourceAliases condition, because IAM authorizes the key, not the alias.
+func (b *PolicyBuilder) addKMSSignPolicies(p *Policy) {
+	var keys, aliases []string
+	for _, keyID := range b.KMSSigningKeys {
+		if parsed, err := arn.Parse(keyID); err == nil {
+			if strings.HasPrefix(parsed.Resource, "alias/") {
+				aliases = append(aliases, parsed.Resource)
+			} else {
+				keys = append(keys, keyID)
+			}
+		} else if strings.HasPrefix(keyID, "alias/") {
+			aliases = append(aliases, keyID)
+		} else {
+			keys = append(keys, fmt.Sprintf("arn:%v:kms:%v:*:key/%v", p.partition, b.Region, keyID))
+		}
+	}
+
+	actions := stringorset.Set([]string{
+		"kms:GetPublicKey",
+		"kms:Sign",
+	})
+	if len(keys) != 0 {
+		p.Statement = append(p.Statement, &Statement{
+			Effect:   StatementEffectAllow,
+			Action:   actions,
+			Resource: stringorset.Set(keys),
+		})
+	}
+	if len(aliases) != 0 {
+		p.Statement = append(p.Statement, &Statement{
+			Effect:   StatementEffectAllow,
+			Action:   actions,
+			Resource: stringorset.String(fmt.Sprintf("arn:%v:kms:*:*:key/*", p.partition)),
+			Condition: Condition{
+				"ForAnyValue:StringEquals": map[string]interface{}{
+					"kms:ResourceAliases": aliases,
+				},
+			},
+		})
+	}
+}
+
+func addReconcilerPermissions(p *Policy) {
+	// For kops-controller to compare the cloud resources with the state store, as `kops update cluster` does without `--yes`.
+	// The control plane is only granted read access; the changes are applied with the kops CLI.
+	p.unconditionalAction.Insert(
+		"autoscaling:Describe*",
+		"ec2:Describe*",
+		"elasticloadbalancing:Describe*",
+		"events:DescribeRule",
+		"events:ListTargetsByRule",
+		"iam:GetInstanceProfile",
+		"iam:GetOpenIDConnectProvider",
+		"iam:GetRole",
+		"iam:GetRolePolicy",
+		"iam:ListAttachedRolePolicies",
+		"iam:ListRolePolicies",
+		"route53:GetHostedZone",
+		"route53:ListHostedZones",
+		"route53:ListResourceRecordSets",
+		"sqs:GetQueueAttributes",
+		"sqs:GetQueueUrl",
+		"sqs:ListQueueTags",
+	)
+}
+
 func addKMSGenerateRandomPolicies(p *Policy) {
 	// For nodeup to seed the instance's random number generator.
 	p.unconditionalAction.Insert(
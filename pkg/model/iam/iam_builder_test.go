@@ -18,12 +18,14 @@ package iam
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/featureflag"
 	"k8s.io/kops/pkg/testutils"
 	"k8s.io/kops/pkg/testutils/golden"
 	"k8s.io/kops/pkg/util/stringorset"
@@ -268,3 +270,219 @@ func TestEmptyPolicy(t *testing.T) {
 		t.Errorf("empty policy should result in empty string, but was %q", policy)
 	}
 }
+
+func TestWriteableVFSPathsReconciler(t *testing.T) {
+	cluster := testutils.BuildMinimalCluster("reconciler.example.com")
+	cluster.Spec.ConfigStore.Base = "s3://state-store/reconciler.example.com"
+
+	for _, reconciler := range []bool{false, true} {
+		if reconciler {
+			cluster.Spec.Reconciler = &kops.ReconcilerSpec{}
+		}
+		for _, role := range []Subject{&NodeRoleMaster{}, &NodeRoleNode{}} {
+			paths, err := WriteableVFSPaths(cluster, role)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			files, err := WriteableVFSFiles(cluster, role)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			writeable := make(map[string]bool)
+			for _, p := range append(paths, files...) {
+				writeable[p.Path()] = true
+			}
+			if writeable[cluster.Spec.ConfigStore.Base] {
+				t.Errorf("reconciler=%v role=%T: config base should not be writeable", reconciler, role)
+			}
+			_, isMaster := role.(*NodeRoleMaster)
+			for _, name := range []string{"config", "lock.yaml", "instancegroup", "history"} {
+				p := cluster.Spec.ConfigStore.Base + "/" + name
+				if want := reconciler && isMaster; writeable[p] != want {
+					t.Errorf("reconciler=%v role=%T: %s writeable=%v, want %v", reconciler, role, p, writeable[p], want)
+				}
+			}
+		}
+	}
+}
+
+func TestWriteableVFSPathsNodeIdentityRevocation(t *testing.T) {
+	cluster := testutils.BuildMinimalCluster("revocation.example.com")
+	cluster.Spec.ConfigStore.Base = "s3://state-store/revocation.example.com"
+	revocationList := cluster.Spec.ConfigStore.Base + "/node-identity/revoked"
+
+	for _, revocation := range []bool{false, true} {
+		if revocation {
+			cluster.Spec.NodeIdentityRevocation = &kops.NodeIdentityRevocationSpec{}
+		}
+		for _, role := range []Subject{&NodeRoleMaster{}, &NodeRoleNode{}} {
+			paths, err := WriteableVFSPaths(cluster, role)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			writeable := false
+			for _, p := range paths {
+				if p.Path() == revocationList {
+					writeable = true
+				}
+			}
+			_, isMaster := role.(*NodeRoleMaster)
+			if want := revocation && isMaster; writeable != want {
+				t.Errorf("revocation=%v role=%T: %s writeable=%v, want %v", revocation, role, revocationList, writeable, want)
+			}
+		}
+	}
+}
+
+func TestAddS3PermissionsReconciler(t *testing.T) {
+	cluster := testutils.BuildMinimalCluster("reconciler.example.com")
+	cluster.Spec.ConfigStore.Base = "s3://state-store/reconciler.example.com"
+	cluster.Spec.Reconciler = &kops.ReconcilerSpec{}
+
+	b := &PolicyBuilder{
+		Cluster:   cluster,
+		Role:      &NodeRoleMaster{},
+		Partition: "aws",
+	}
+	p := NewPolicy(cluster.GetName(), b.Partition)
+	if err := b.AddS3Permissions(p); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	policy, err := p.AsJSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, expected := range []string{
+		`"arn:aws:s3:::state-store/reconciler.example.com/config"`,
+		`"arn:aws:s3:::state-store/reconciler.example.com/lock.yaml"`,
+		`"arn:aws:s3:::state-store/reconciler.example.com/instancegroup/*"`,
+		`"arn:aws:s3:::state-store/reconciler.example.com/history/*"`,
+	} {
+		if !strings.Contains(policy, expected) {
+			t.Errorf("expected policy to contain %s, was %s", expected, policy)
+		}
+	}
+}
+
+func TestAddS3PermissionsJoinTokens(t *testing.T) {
+	cluster := testutils.BuildMinimalCluster("jointokens.example.com")
+	cluster.Spec.ConfigStore.Base = "s3://state-store/jointokens.example.com"
+	cluster.Spec.ConfigStore.Secrets = "s3://state-store/jointokens.example.com/secrets"
+
+	for _, metal := range []bool{false, true} {
+		if metal {
+			featureflag.ParseFlags("+Metal")
+			defer featureflag.ParseFlags("-Metal")
+		}
+
+		b := &PolicyBuilder{
+			Cluster:   cluster,
+			Role:      &NodeRoleMaster{},
+			Partition: "aws",
+		}
+		p := NewPolicy(cluster.GetName(), b.Partition)
+		if err := b.AddS3Permissions(p); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		policy, err := p.AsJSON()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		for _, expected := range []string{
+			`"arn:aws:s3:::state-store/jointokens.example.com/join-tokens/claimed/*"`,
+			`"arn:aws:s3:::state-store/jointokens.example.com/secrets/join-token-*"`,
+		} {
+			if contains := strings.Contains(policy, expected); contains != metal {
+				t.Errorf("metal=%v: policy contains %s: %v, want %v; policy was %s", metal, expected, contains, metal, policy)
+			}
+		}
+		if strings.Contains(policy, `"arn:aws:s3:::state-store/jointokens.example.com/secrets/*"`) {
+			t.Errorf("metal=%v: expected policy not to grant write access to all secrets, was %s", metal, policy)
+		}
+	}
+}
+
+func TestKMSSignPolicies(t *testing.T) {
+	cluster := testutils.BuildMinimalCluster("kms.example.com")
+
+	grants := func(keys ...string) string {
+		b := &PolicyBuilder{
+			Cluster:        cluster,
+			Partition:      "aws",
+			Region:         "us-east-1",
+			KMSSigningKeys: keys,
+		}
+		p := NewPolicy(cluster.GetName(), b.Partition)
+		b.addKMSSignPolicies(p)
+		if len(p.Statement) == 0 {
+			return ""
+		}
+		policy, err := p.AsJSON()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return policy
+	}
+
+	if policy := grants(); strings.Contains(policy, "kms:Sign") {
+		t.Errorf("expected no kms:Sign grant without KMS keys, was %s", policy)
+	}
+
+	policy := grants(
+		"arn:aws:kms:us-west-2:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab",
+		"5678abcd-12ab-34cd-56ef-1234567890ab",
+		"alias/kops-ca",
+		"arn:aws:kms:us-west-2:123456789012:alias/kops-sa",
+	)
+	for _, expected := range []string{
+		`"arn:aws:kms:us-west-2:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab"`,
+		`"arn:aws:kms:us-east-1:*:key/5678abcd-12ab-34cd-56ef-1234567890ab"`,
+		`"arn:aws:kms:*:*:key/*"`,
+		`"alias/kops-ca"`,
+		`"alias/kops-sa"`,
+	} {
+		if !strings.Contains(policy, expected) {
+			t.Errorf("expected policy to contain %s, was %s", expected, policy)
+		}
+	}
+	if strings.Contains(policy, `"Resource": "*"`) {
+		t.Errorf("expected kms:Sign to be scoped to the keys, was %s", policy)
+	}
+}
+
+func TestAddS3PermissionsSecretsManager(t *testing.T) {
+	cluster := testutils.BuildMinimalCluster("secrets.example.com")
+	cluster.Spec.ConfigStore.Base = "s3://state-store/secrets.example.com"
+	cluster.Spec.ConfigStore.Keypairs = "awssm://us-east-1/kops/secrets.example.com/pki"
+	cluster.Spec.ConfigStore.Secrets = "vault://vault.example.com:8200/secret/kops/secrets.example.com/secrets"
+
+	b := &PolicyBuilder{
+		Cluster:   cluster,
+		Role:      &NodeRoleMaster{},
+		Partition: "aws",
+	}
+	p := NewPolicy(cluster.GetName(), b.Partition)
+	if err := b.AddS3Permissions(p); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	policy, err := p.AsJSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, expected := range []string{
+		"arn:aws:secretsmanager:us-east-1:*:secret:kops/secrets.example.com/pki/*",
+		"secretsmanager:GetSecretValue",
+		"secretsmanager:ListSecrets",
+		"arn:aws:s3:::state-store/secrets.example.com/*",
+	} {
+		if !strings.Contains(policy, expected) {
+			t.Errorf("expected policy to contain %q, was %s", expected, policy)
+		}
+	}
+	if strings.Contains(policy, "vault.example.com") {
+		t.Errorf("expected no statements for the vault path, was %s", policy)
+	}
+}
@@ -52,6 +52,17 @@ func (b *PKIModelBuilder) Build(c *fi.CloudupModelBuilderContext) error {
 		c.AddTask(aggregatorCA)
 	}
 
+	if b.Cluster.Spec.Authentication != nil && b.Cluster.Spec.Authentication.AdminCredentials != nil {
+		// A separate root CA for admin credentials, so that they can all be revoked by distrusting it
+		adminCA := &fitasks.Keypair{
+			Name:      fi.PtrTo(fi.CertificateIDAdminCA),
+			Lifecycle: b.Lifecycle,
+			Subject:   "cn=" + fi.CertificateIDAdminCA,
+			Type:      "ca",
+		}
+		c.AddTask(adminCA)
+	}
+
 	{
 		serviceAccount := &fitasks.Keypair{
 			// We only need the private key, but it's easier to create a certificate as well.
@@ -68,6 +79,11 @@ func (b *PKIModelBuilder) Build(c *fi.CloudupModelBuilderContext) error {
 		c.AddTask(&fitasks.Secret{Name: fi.PtrTo(x), Lifecycle: b.Lifecycle})
 	}
 
+	if b.Cluster.Spec.TPMAttestation != nil {
+		// kops-controller derives the key for the credentials of TPM attestation from this secret
+		c.AddTask(&fitasks.Secret{Name: fi.PtrTo(fi.SecretNameTPMCredentials), Lifecycle: b.Lifecycle})
+	}
+
 	{
 		mirrorPath, err := vfs.Context.BuildVfsPath(b.Cluster.Spec.ConfigStore.Secrets)
 		if err != nil {
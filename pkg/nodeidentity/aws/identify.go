@@ -20,7 +20,6 @@ import (
 	"context"
 	"fmt"
 	"strings"
-	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
@@ -28,8 +27,6 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	corev1 "k8s.io/api/core/v1"
-	expirationcache "k8s.io/client-go/tools/cache"
-	"k8s.io/klog/v2"
 
 	"k8s.io/kops/pkg/nodeidentity"
 	"k8s.io/kops/util/pkg/awslog"
@@ -41,24 +38,20 @@ const (
 	CloudTagInstanceGroupName = "kops.k8s.io/instancegroup"
 	// ClusterAutoscalerNodeTemplateLabel is the prefix used on node labels when copying to cloud tags.
 	ClusterAutoscalerNodeTemplateLabel = "k8s.io/cluster-autoscaler/node-template/label/"
-	// The expiration time of nodeidentity.Info cache.
-	cacheTTL           = 60 * time.Minute
-	KarpenterNodeLabel = "karpenter.sh/"
+	KarpenterNodeLabel                 = "karpenter.sh/"
 )
 
 // nodeIdentifier identifies a node from EC2
 type nodeIdentifier struct {
 	// client is the ec2 interface
 	ec2Client ec2.DescribeInstancesAPIClient
-
-	// cache is a cache of nodeidentity.Info
-	cache expirationcache.Store
-	// cacheEnabled indicates if caching should be used
-	cacheEnabled bool
 }
 
-// New creates and returns a nodeidentity.Identifier for Nodes running on AWS
-func New(ctx context.Context, cacheNodeidentityInfo bool) (nodeidentity.Identifier, error) {
+var _ nodeidentity.BatchIdentifier = &nodeIdentifier{}
+
+// New creates and returns a nodeidentity.Identifier for Nodes running on AWS.
+// It does not cache the node identities; see the identitycache package.
+func New(ctx context.Context) (nodeidentity.Identifier, error) {
 	config, err := awsconfig.LoadDefaultConfig(ctx, awslog.WithAWSLogger())
 	if err != nil {
 		return nil, fmt.Errorf("error loading AWS config: %v", err)
@@ -75,53 +68,78 @@ func New(ctx context.Context, cacheNodeidentityInfo bool) (nodeidentity.Identifi
 	ec2Client := ec2.NewFromConfig(config)
 
 	return &nodeIdentifier{
-		ec2Client:    ec2Client,
-		cache:        expirationcache.NewTTLStore(stringKeyFunc, cacheTTL),
-		cacheEnabled: cacheNodeidentityInfo,
+		ec2Client: ec2Client,
 	}, nil
 }
 
-// stringKeyFunc is a string as cache key function
-func stringKeyFunc(obj interface{}) (string, error) {
-	key := obj.(*nodeidentity.Info).InstanceID
-	return key, nil
-}
-
 // IdentifyNode queries AWS for the node identity information
 func (i *nodeIdentifier) IdentifyNode(ctx context.Context, node *corev1.Node) (*nodeidentity.Info, error) {
+	results := i.IdentifyNodes(ctx, []*corev1.Node{node})
+	return results[0].Info, results[0].Err
+}
+
+// IdentifyNodes queries AWS for the identity information of the nodes, with a single DescribeInstances request.
+func (i *nodeIdentifier) IdentifyNodes(ctx context.Context, nodes []*corev1.Node) []nodeidentity.BatchResult {
+	results := make([]nodeidentity.BatchResult, len(nodes))
+
+	instanceIDs := make([]string, len(nodes))
+	var lookup []string
+	for n, node := range nodes {
+		instanceID, err := instanceIDForNode(node)
+		if err != nil {
+			results[n].Err = err
+			continue
+		}
+		instanceIDs[n] = instanceID
+		lookup = append(lookup, instanceID)
+	}
+	if len(lookup) == 0 {
+		return results
+	}
+
+	instances, err := i.getInstances(ctx, lookup)
+	for n, instanceID := range instanceIDs {
+		if instanceID == "" {
+			continue
+		}
+		if err != nil {
+			results[n].Err = err
+			continue
+		}
+		results[n].Info, results[n].Err = buildInfo(instanceID, instances[instanceID])
+	}
+	return results
+}
+
+// instanceIDForNode returns the EC2 instance ID from the providerID of the node
+func instanceIDForNode(node *corev1.Node) (string, error) {
 	providerID := node.Spec.ProviderID
 	if providerID == "" {
-		return nil, fmt.Errorf("providerID was not set for node %s", node.Name)
+		return "", fmt.Errorf("providerID was not set for node %s", node.Name)
 	}
 	if !strings.HasPrefix(providerID, "aws://") {
-		return nil, fmt.Errorf("providerID %q not recognized for node %s", providerID, node.Name)
+		return "", fmt.Errorf("providerID %q not recognized for node %s", providerID, node.Name)
 	}
 
 	tokens := strings.Split(strings.TrimPrefix(providerID, "aws://"), "/")
 	if len(tokens) != 3 {
-		return nil, fmt.Errorf("providerID %q not recognized for node %s", providerID, node.Name)
+		return "", fmt.Errorf("providerID %q not recognized for node %s", providerID, node.Name)
 	}
 
 	// zone := tokens[1]
-	instanceID := tokens[2]
+	return tokens[2], nil
+}
 
-	// If caching is enabled try pulling nodeidentity.Info from cache before
-	// doing a EC2 API call.
-	if i.cacheEnabled {
-		obj, exists, err := i.cache.GetByKey(instanceID)
-		if err != nil {
-			klog.Warningf("Nodeidentity info cache lookup failure: %v", err)
-		}
-		if exists {
-			return obj.(*nodeidentity.Info), nil
-		}
+// buildInfo builds the node identity information from the instances found with the instance ID
+func buildInfo(instanceID string, instances []ec2types.Instance) (*nodeidentity.Info, error) {
+	// @check we found some instances
+	if len(instances) == 0 {
+		return nil, fmt.Errorf("missing instance id: %s", instanceID)
 	}
-
-	// Based on node-authorizer code
-	instance, err := i.getInstance(ctx, instanceID)
-	if err != nil {
-		return nil, err
+	if len(instances) > 1 {
+		return nil, fmt.Errorf("found multiple instances with instance id: %s", instanceID)
 	}
+	instance := instances[0]
 
 	var instanceState ec2types.InstanceStateName
 	if instance.State != nil {
@@ -148,35 +166,35 @@ func (i *nodeIdentifier) IdentifyNode(ctx context.Context, node *corev1.Node) (*
 		}
 	}
 
-	// If caching is enabled add the nodeidentity.Info to cache.
-	if i.cacheEnabled {
-		err = i.cache.Add(info)
-		if err != nil {
-			klog.Warningf("Failed to add node identity info to cache: %v", err)
-		}
-	}
-
 	return info, nil
 }
 
-// getInstance queries EC2 for the instance with the specified ID, returning an error if not found
-func (i *nodeIdentifier) getInstance(ctx context.Context, instanceID string) (*ec2types.Instance, error) {
-	// Based on node-authorizer code
-	resp, err := i.ec2Client.DescribeInstances(ctx, &ec2.DescribeInstancesInput{
-		InstanceIds: []string{instanceID},
-	})
-	if err != nil {
-		return nil, fmt.Errorf("error from ec2 DescribeInstances request: %v", err)
-	}
-
-	// @check we found some instances
-	if len(resp.Reservations) == 0 || len(resp.Reservations[0].Instances) == 0 {
-		return nil, fmt.Errorf("missing instance id: %s", instanceID)
-	}
-	if len(resp.Reservations[0].Instances) > 1 {
-		return nil, fmt.Errorf("found multiple instances with instance id: %s", instanceID)
+// getInstances queries EC2 for the instances with the specified IDs, grouped by ID.
+// We filter on the instance-id instead of passing InstanceIds, so that one missing instance does not fail the whole request.
+func (i *nodeIdentifier) getInstances(ctx context.Context, instanceIDs []string) (map[string][]ec2types.Instance, error) {
+	instances := make(map[string][]ec2types.Instance)
+
+	request := &ec2.DescribeInstancesInput{
+		Filters: []ec2types.Filter{
+			{
+				Name:   aws.String("instance-id"),
+				Values: instanceIDs,
+			},
+		},
+	}
+	paginator := ec2.NewDescribeInstancesPaginator(i.ec2Client, request)
+	for paginator.HasMorePages() {
+		resp, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error from ec2 DescribeInstances request: %v", err)
+		}
+		for _, reservation := range resp.Reservations {
+			for _, instance := range reservation.Instances {
+				id := aws.ToString(instance.InstanceId)
+				instances[id] = append(instances[id], instance)
+			}
+		}
 	}
 
-	instance := resp.Reservations[0].Instances[0]
-	return &instance, nil
+	return instances, nil
 }
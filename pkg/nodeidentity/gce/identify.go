@@ -89,21 +89,155 @@ func New(clusterName string, capiManager *clusterapi.Manager) (nodeidentity.Iden
 	}, nil
 }
 
+var _ nodeidentity.BatchIdentifier = &nodeIdentifier{}
+
 // IdentifyNode queries GCE for the node identity information
 func (i *nodeIdentifier) IdentifyNode(ctx context.Context, node *corev1.Node) (*nodeidentity.Info, error) {
-	// log := klog.FromContext(ctx)
+	results := i.IdentifyNodes(ctx, []*corev1.Node{node})
+	return results[0].Info, results[0].Err
+}
+
+// batchNode tracks the identification of one node of a batch.
+type batchNode struct {
+	zone     string
+	instance *compute.Instance
+	mig      *compute.InstanceGroupManager
+	err      error
+}
+
+// IdentifyNodes queries GCE for the identity information of the nodes.
+// Each MIG, its managed instances and each instance template are only fetched once per batch.
+func (i *nodeIdentifier) IdentifyNodes(ctx context.Context, nodes []*corev1.Node) []nodeidentity.BatchResult {
+	batch := make([]batchNode, len(nodes))
+
+	migs := make(map[string]*compute.InstanceGroupManager)
+	migErrors := make(map[string]error)
+	migMembers := make(map[string][]uint64)
+
+	for n, node := range nodes {
+		b := &batch[n]
+
+		var isMachine bool
+		b.zone, b.instance, isMachine, b.err = i.getNodeInstance(ctx, node)
+		if b.err != nil || isMachine {
+			continue
+		}
+
+		// The metadata itself is potentially mutable from the instance
+		// We instead look at the MIG configuration
+		createdBy := getMetadataValue(b.instance.Metadata, "created-by")
+		if createdBy == "" {
+			b.err = fmt.Errorf("cannot find owner for instance %s", b.instance.Name)
+			continue
+		}
+
+		// We need to double-check the MIG configuration, in case created-by was changed
+		key := b.zone + "/" + lastComponent(createdBy)
+		if _, found := migs[key]; !found && migErrors[key] == nil {
+			mig, err := i.getMIG(b.zone, lastComponent(createdBy))
+			if err != nil {
+				migErrors[key] = err
+			} else {
+				migs[key] = mig
+			}
+		}
+		if err := migErrors[key]; err != nil {
+			b.err = err
+			continue
+		}
+		b.mig = migs[key]
+		migMembers[key] = append(migMembers[key], b.instance.Id)
+	}
+
+	// We now double check that the instances are indeed managed by the MIG
+	// this can't be spoofed without GCE API access
+	managedInstances := make(map[string]map[uint64][]*compute.ManagedInstance)
+	managedErrors := make(map[string]error)
+	for key, ids := range migMembers {
+		managed, err := i.getManagedInstances(ctx, migs[key], ids)
+		if err != nil {
+			managedErrors[key] = err
+			continue
+		}
+		managedInstances[key] = managed
+	}
+
+	templates := make(map[string]*compute.InstanceTemplate)
+	templateErrors := make(map[string]error)
+
+	results := make([]nodeidentity.BatchResult, len(nodes))
+	for n := range batch {
+		b := &batch[n]
+		if b.err != nil {
+			results[n].Err = b.err
+			continue
+		}
+
+		if b.mig != nil {
+			key := b.zone + "/" + b.mig.Name
+			if err := managedErrors[key]; err != nil {
+				results[n].Err = err
+				continue
+			}
+
+			matches := managedInstances[key][b.instance.Id]
+			if len(matches) == 0 {
+				results[n].Err = fmt.Errorf("instance %v not managed by mig %s", b.instance.Id, b.mig.Name)
+				continue
+			}
+			if len(matches) > 1 {
+				// Should be impossible - shows that filters / post-filters are not working
+				results[n].Err = fmt.Errorf("found multiple instances with id %v managed by mig %s", b.instance.Id, b.mig.Name)
+				continue
+			}
+			migMember := matches[0]
+
+			if migMember.Version == nil {
+				results[n].Err = fmt.Errorf("instance %s did not have Version set", b.instance.Name)
+				continue
+			}
+
+			templateName := lastComponent(migMember.Version.InstanceTemplate)
+			if _, found := templates[templateName]; !found && templateErrors[templateName] == nil {
+				instanceTemplate, err := i.getInstanceTemplate(templateName)
+				if err != nil {
+					templateErrors[templateName] = err
+				} else {
+					templates[templateName] = instanceTemplate
+				}
+			}
+			if err := templateErrors[templateName]; err != nil {
+				results[n].Err = err
+				continue
+			}
+			instanceTemplate := templates[templateName]
+
+			igName := getMetadataValue(instanceTemplate.Properties.Metadata, MetadataKeyInstanceGroupName)
+			if igName == "" {
+				results[n].Err = fmt.Errorf("ig name not set on instance template %s", instanceTemplate.Name)
+				continue
+			}
+		}
+
+		results[n].Info = i.buildInfo(b.instance)
+	}
+
+	return results
+}
 
+// getNodeInstance finds the GCE instance for the node, and whether it was verified against a Cluster API Machine.
+func (i *nodeIdentifier) getNodeInstance(ctx context.Context, node *corev1.Node) (string, *compute.Instance, bool, error) {
 	providerID := node.Spec.ProviderID
 	if providerID == "" {
-		return nil, fmt.Errorf("providerID was not set for node %s", node.Name)
+		return "", nil, false, fmt.Errorf("providerID was not set for node %s", node.Name)
 	}
 	if !strings.HasPrefix(providerID, "gce://") {
-		return nil, fmt.Errorf("providerID %q not recognized for node %s", providerID, node.Name)
+		return "", nil, false, fmt.Errorf("providerID %q not recognized for node %s", providerID, node.Name)
 	}
 
 	tokens := strings.Split(strings.TrimPrefix(providerID, "gce://"), "/")
 	if len(tokens) != 3 {
-		return nil, fmt.Errorf("providerID %q not recognized for node %s", providerID, node.Name)
+		return "", nil, false, fmt.Errorf("providerID %q not recognized for node %s", providerID, node.Name)
 	}
 
 	project := tokens[0]
@@ -111,71 +245,39 @@ func (i *nodeIdentifier) IdentifyNode(ctx context.Context, node *corev1.Node) (*
 	instanceName := tokens[2]
 
 	if project != i.project {
-		return nil, fmt.Errorf("providerID %q did not match our project %q", providerID, i.project)
+		return "", nil, false, fmt.Errorf("providerID %q did not match our project %q", providerID, i.project)
 	}
 
 	instance, err := i.getInstance(zone, instanceName)
 	if err != nil {
-		return nil, err
+		return "", nil, false, err
 	}
 
 	instanceStatus := instance.Status
 	if instanceStatus != "RUNNING" {
-		return nil, fmt.Errorf("found instance %q, but status is %q", instanceName, instanceStatus)
+		return "", nil, false, fmt.Errorf("found instance %q, but status is %q", instanceName, instanceStatus)
 	}
 
 	capgRole := instance.Labels[LabelKeyCAPIRoleName]
 
-	var capiMachine *clusterapi.Machine
-
 	if i.capiManager != nil && capgRole != "" {
 		providerID := "gce://" + project + "/" + zone + "/" + instanceName
 
 		m, err := i.capiManager.FindMachineByProviderID(ctx, providerID)
 		if err != nil {
-			return nil, fmt.Errorf("error finding Machine with providerID %q: %w", providerID, err)
+			return "", nil, false, fmt.Errorf("error finding Machine with providerID %q: %w", providerID, err)
 		}
-		capiMachine = m
-	}
-
-	if capiMachine == nil {
-		// The metadata itself is potentially mutable from the instance
-		// We instead look at the MIG configuration
-		createdBy := getMetadataValue(instance.Metadata, "created-by")
-		if createdBy == "" {
-			return nil, fmt.Errorf("cannot find owner for instance %s", instance.Name)
-		}
-
-		// We need to double-check the MIG configuration, in case created-by was changed
-		migName := lastComponent(createdBy)
-
-		mig, err := i.getMIG(zone, migName)
-		if err != nil {
-			return nil, err
-		}
-
-		// We now double check that the instance is indeed managed by the MIG
-		// this can't be spoofed without GCE API access
-		migMember, err := i.getManagedInstance(ctx, mig, instance.Id)
-		if err != nil {
-			return nil, err
-		}
-
-		if migMember.Version == nil {
-			return nil, fmt.Errorf("instance %s did not have Version set", instance.Name)
-		}
-
-		instanceTemplate, err := i.getInstanceTemplate(lastComponent(migMember.Version.InstanceTemplate))
-		if err != nil {
-			return nil, err
-		}
-
-		igName := getMetadataValue(instanceTemplate.Properties.Metadata, MetadataKeyInstanceGroupName)
-		if igName == "" {
-			return nil, fmt.Errorf("ig name not set on instance template %s", instanceTemplate.Name)
+		if m != nil {
+			// Identified by the Machine; we don't need to check the MIG
+			return zone, instance, true, nil
 		}
 	}
 
+	return zone, instance, false, nil
+}
+
+// buildInfo builds the node identity information for the instance, from its network tags
+func (i *nodeIdentifier) buildInfo(instance *compute.Instance) *nodeidentity.Info {
 	info := &nodeidentity.Info{}
 	// info.InstanceID TODO: InstanceID is only used by the provider?
 
@@ -186,23 +288,25 @@ func (i *nodeIdentifier) IdentifyNode(ctx context.Context, node *corev1.Node) (*
 	}
 
 	labels := make(map[string]string)
-	for _, tag := range instance.Tags.Items {
-		role, found := tagToRole[tag]
-		if found {
-			switch role {
-			case kops.InstanceGroupRoleControlPlane:
-				labels[nodelabels.RoleLabelControlPlane20] = ""
-			case kops.InstanceGroupRoleNode:
-				labels[nodelabels.RoleLabelNode16] = ""
-			case kops.InstanceGroupRoleAPIServer:
-				labels[nodelabels.RoleLabelAPIServer16] = ""
-			default:
-				klog.Warningf("unknown node role %q for server %q", role, instance.SelfLink)
+	if instance.Tags != nil {
+		for _, tag := range instance.Tags.Items {
+			role, found := tagToRole[tag]
+			if found {
+				switch role {
+				case kops.InstanceGroupRoleControlPlane:
+					labels[nodelabels.RoleLabelControlPlane20] = ""
+				case kops.InstanceGroupRoleNode:
+					labels[nodelabels.RoleLabelNode16] = ""
+				case kops.InstanceGroupRoleAPIServer:
+					labels[nodelabels.RoleLabelAPIServer16] = ""
+				default:
+					klog.Warningf("unknown node role %q for server %q", role, instance.SelfLink)
+				}
 			}
 		}
 	}
 	info.Labels = labels
-	return info, nil
+	return info
 }
 
 // getInstance queries GCE for the instance with the specified name, returning an error if not found
@@ -235,34 +339,33 @@ func (i *nodeIdentifier) getMIG(zone string, migName string) (*compute.InstanceG
 	return mig, nil
 }
 
-// getManagedInstance queries GCE for the instance from the MIG
-func (i *nodeIdentifier) getManagedInstance(ctx context.Context, mig *compute.InstanceGroupManager, instanceID uint64) (*compute.ManagedInstance, error) {
-	var matches []*compute.ManagedInstance
+// getManagedInstances queries GCE for the instances with the specified IDs from the MIG, grouped by ID
+func (i *nodeIdentifier) getManagedInstances(ctx context.Context, mig *compute.InstanceGroupManager, instanceIDs []uint64) (map[uint64][]*compute.ManagedInstance, error) {
+	matches := make(map[uint64][]*compute.ManagedInstance)
+	wanted := make(map[uint64]bool)
+	for _, id := range instanceIDs {
+		wanted[id] = true
+	}
 
-	filter := "id=" + strconv.FormatUint(instanceID, 10)
 	zone := lastComponent(mig.Zone)
-	if err := i.computeService.InstanceGroupManagers.ListManagedInstances(i.project, zone, mig.Name).Filter(filter).Pages(ctx, func(page *compute.InstanceGroupManagersListManagedInstancesResponse) error {
+	call := i.computeService.InstanceGroupManagers.ListManagedInstances(i.project, zone, mig.Name)
+	if len(instanceIDs) == 1 {
+		call = call.Filter("id=" + strconv.FormatUint(instanceIDs[0], 10))
+	}
+	if err := call.Pages(ctx, func(page *compute.InstanceGroupManagersListManagedInstancesResponse) error {
 		// Post-filter... filters aren't implemented (b/27605549)
 		for _, instance := range page.ManagedInstances {
-			if instance.Id != instanceID {
+			if !wanted[instance.Id] {
 				continue
 			}
-			matches = append(matches, instance)
+			matches[instance.Id] = append(matches[instance.Id], instance)
 		}
 		return nil
 	}); err != nil {
 		return nil, fmt.Errorf("error fetching GCE managed instance group members for %q: %v", mig.Name, err)
 	}
 
-	if len(matches) == 0 {
-		return nil, fmt.Errorf("instance %v not managed by mig %s", instanceID, mig.Name)
-	}
-	if len(matches) > 1 {
-		// Should be impossible - shows that filters / post-filters are not working
-		return nil, fmt.Errorf("found multiple instances with id %v managed by mig %s", instanceID, mig.Name)
-	}
-
-	return matches[0], nil
+	return matches, nil
 }
 
 // lastComponent returns the last component of a URL, i.e. anything after the last slash
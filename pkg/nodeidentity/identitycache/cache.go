@@ -0,0 +1,222 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identitycache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/nodeidentity"
+)
+
+// Options configures a Cache.
+type Options struct {
+	// TTL is how long the identity of a node is cached.
+	TTL time.Duration
+	// QPS and Burst configure the token bucket that limits the rate of cloud lookups; a QPS of zero disables the limit.
+	QPS   float64
+	Burst int
+	// BatchSize is the maximum number of nodes identified by one lookup, for identifiers that support batches.
+	BatchSize int
+}
+
+// NodeLister lists the nodes of the cluster, so that the nodes that are not yet cached can be identified together.
+type NodeLister func(ctx context.Context) ([]*corev1.Node, error)
+
+// Cache is a nodeidentity.Identifier that caches the identity of nodes by their providerID.
+// Cloud lookups are rate-limited, and if the identifier implements nodeidentity.BatchIdentifier,
+// a cache miss identifies the other uncached nodes in the same lookup, so that a scale-up of many nodes
+// costs a few cloud requests instead of one (or more) per node.
+type Cache struct {
+	identifier nodeidentity.Identifier
+	lister     NodeLister
+	options    Options
+	limiter    *rate.Limiter
+
+	// now is the clock, which can be replaced in tests
+	now func() time.Time
+
+	mutex   sync.Mutex
+	entries map[string]entry
+}
+
+type entry struct {
+	info    *nodeidentity.Info
+	expires time.Time
+}
+
+var _ nodeidentity.Identifier = &Cache{}
+
+// New creates a Cache in front of the identifier. lister may be nil, in which case nodes are identified one at a time.
+func New(identifier nodeidentity.Identifier, lister NodeLister, options Options) *Cache {
+	if options.BatchSize < 1 {
+		options.BatchSize = 1
+	}
+	if options.Burst < 1 {
+		options.Burst = 1
+	}
+	limit := rate.Limit(options.QPS)
+	if options.QPS <= 0 {
+		limit = rate.Inf
+	}
+	return &Cache{
+		identifier: identifier,
+		lister:     lister,
+		options:    options,
+		limiter:    rate.NewLimiter(limit, options.Burst),
+		now:        time.Now,
+		entries:    make(map[string]entry),
+	}
+}
+
+// IdentifyNode returns the cached identity of the node, or looks it up.
+func (c *Cache) IdentifyNode(ctx context.Context, node *corev1.Node) (*nodeidentity.Info, error) {
+	key := node.Spec.ProviderID
+	if key == "" {
+		// The identifier reports the error, without a cloud request.
+		return c.identifier.IdentifyNode(ctx, node)
+	}
+
+	if info := c.get(key); info != nil {
+		cacheLookups.WithLabelValues("hit").Inc()
+		return info, nil
+	}
+	cacheLookups.WithLabelValues("miss").Inc()
+
+	batchIdentifier, ok := c.identifier.(nodeidentity.BatchIdentifier)
+	if !ok || c.lister == nil || c.options.BatchSize == 1 {
+		if err := c.wait(ctx); err != nil {
+			return nil, err
+		}
+		info, err := c.identifier.IdentifyNode(ctx, node)
+		c.record(1, err)
+		if err != nil {
+			return nil, err
+		}
+		c.put(key, info)
+		return info, nil
+	}
+
+	nodes := append([]*corev1.Node{node}, c.uncachedNodes(ctx, key)...)
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+	results := batchIdentifier.IdentifyNodes(ctx, nodes)
+	failed := 0
+	for i, result := range results {
+		if result.Err != nil {
+			failed++
+			if i > 0 {
+				klog.V(2).Infof("unable to identify node %q in batch: %v", nodes[i].Name, result.Err)
+			}
+			continue
+		}
+		c.put(nodes[i].Spec.ProviderID, result.Info)
+	}
+	c.recordBatch(len(nodes), failed)
+
+	return results[0].Info, results[0].Err
+}
+
+// uncachedNodes returns other nodes that are not cached, up to the batch size.
+func (c *Cache) uncachedNodes(ctx context.Context, skip string) []*corev1.Node {
+	nodes, err := c.lister(ctx)
+	if err != nil {
+		klog.Warningf("unable to list nodes to identify in batch: %v", err)
+		return nil
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.now()
+	var uncached []*corev1.Node
+	seen := map[string]bool{skip: true}
+	for _, node := range nodes {
+		if len(uncached) >= c.options.BatchSize-1 {
+			break
+		}
+		key := node.Spec.ProviderID
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		if e, found := c.entries[key]; found && now.Before(e.expires) {
+			continue
+		}
+		uncached = append(uncached, node)
+	}
+	return uncached
+}
+
+// wait blocks until the rate limiter allows a cloud lookup.
+func (c *Cache) wait(ctx context.Context) error {
+	start := c.now()
+	if err := c.limiter.Wait(ctx); err != nil {
+		return err
+	}
+	rateLimitWait.Observe(c.now().Sub(start).Seconds())
+	return nil
+}
+
+func (c *Cache) get(key string) *nodeidentity.Info {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	e, found := c.entries[key]
+	if !found || !c.now().Before(e.expires) {
+		return nil
+	}
+	return e.info
+}
+
+func (c *Cache) put(key string, info *nodeidentity.Info) {
+	if key == "" || info == nil {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.now()
+	// Drop expired entries, so that deleted nodes do not accumulate.
+	for k, e := range c.entries {
+		if !now.Before(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = entry{info: info, expires: now.Add(c.options.TTL)}
+	cacheEntries.Set(float64(len(c.entries)))
+}
+
+func (c *Cache) record(nodes int, err error) {
+	failed := 0
+	if err != nil {
+		failed = 1
+	}
+	c.recordBatch(nodes, failed)
+}
+
+func (c *Cache) recordBatch(nodes int, failed int) {
+	batchSize.Observe(float64(nodes))
+	identified.WithLabelValues("success").Add(float64(nodes - failed))
+	identified.WithLabelValues("error").Add(float64(failed))
+}
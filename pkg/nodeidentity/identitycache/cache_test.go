@@ -0,0 +1,149 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identitycache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/nodeidentity"
+)
+
+type fakeIdentifier struct {
+	lookups []int
+}
+
+func (f *fakeIdentifier) IdentifyNode(ctx context.Context, node *corev1.Node) (*nodeidentity.Info, error) {
+	f.lookups = append(f.lookups, 1)
+	return identify(node)
+}
+
+type fakeBatchIdentifier struct {
+	fakeIdentifier
+}
+
+func (f *fakeBatchIdentifier) IdentifyNodes(ctx context.Context, nodes []*corev1.Node) []nodeidentity.BatchResult {
+	f.lookups = append(f.lookups, len(nodes))
+	results := make([]nodeidentity.BatchResult, len(nodes))
+	for i, node := range nodes {
+		results[i].Info, results[i].Err = identify(node)
+	}
+	return results
+}
+
+func identify(node *corev1.Node) (*nodeidentity.Info, error) {
+	if node.Name == "unknown" {
+		return nil, fmt.Errorf("instance not found")
+	}
+	return &nodeidentity.Info{InstanceID: node.Name}, nil
+}
+
+func buildNode(name string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       corev1.NodeSpec{ProviderID: "aws:///us-test-1a/" + name},
+	}
+}
+
+func TestCacheTTL(t *testing.T) {
+	ctx := context.Background()
+	identifier := &fakeIdentifier{}
+	cache := New(identifier, nil, Options{TTL: time.Minute, BatchSize: 10})
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	node := buildNode("i-1")
+	for i := 0; i < 3; i++ {
+		info, err := cache.IdentifyNode(ctx, node)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if info.InstanceID != "i-1" {
+			t.Errorf("unexpected instance ID %q", info.InstanceID)
+		}
+	}
+	if len(identifier.lookups) != 1 {
+		t.Errorf("expected 1 lookup, got %d", len(identifier.lookups))
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, err := cache.IdentifyNode(ctx, node); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(identifier.lookups) != 2 {
+		t.Errorf("expected a lookup after expiry, got %d lookups", len(identifier.lookups))
+	}
+}
+
+func TestCacheErrorsNotCached(t *testing.T) {
+	ctx := context.Background()
+	identifier := &fakeIdentifier{}
+	cache := New(identifier, nil, Options{TTL: time.Minute})
+
+	node := buildNode("unknown")
+	for i := 0; i < 2; i++ {
+		if _, err := cache.IdentifyNode(ctx, node); err == nil {
+			t.Fatalf("expected error")
+		}
+	}
+	if len(identifier.lookups) != 2 {
+		t.Errorf("expected 2 lookups, got %d", len(identifier.lookups))
+	}
+}
+
+func TestCacheBatch(t *testing.T) {
+	ctx := context.Background()
+	identifier := &fakeBatchIdentifier{}
+
+	var nodes []*corev1.Node
+	for i := 0; i < 5; i++ {
+		nodes = append(nodes, buildNode(fmt.Sprintf("i-%d", i)))
+	}
+	nodes = append(nodes, buildNode("unknown"))
+	lister := func(ctx context.Context) ([]*corev1.Node, error) {
+		return nodes, nil
+	}
+
+	cache := New(identifier, lister, Options{TTL: time.Minute, BatchSize: 4})
+
+	for _, node := range nodes {
+		info, err := cache.IdentifyNode(ctx, node)
+		if node.Name == "unknown" {
+			if err == nil {
+				t.Errorf("expected error for node %q", node.Name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("unexpected error for node %q: %v", node.Name, err)
+		}
+		if info.InstanceID != node.Name {
+			t.Errorf("unexpected instance ID %q for node %q", info.InstanceID, node.Name)
+		}
+	}
+
+	// The first lookup identifies i-0 with three other uncached nodes, the second i-4 and the unknown node;
+	// errors are not cached, so the unknown node is looked up again.
+	expected := []int{4, 2, 1}
+	if fmt.Sprint(identifier.lookups) != fmt.Sprint(expected) {
+		t.Errorf("unexpected lookups: got %v, expected %v", identifier.lookups, expected)
+	}
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identitycache
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	// cacheLookups counts the node identity lookups, by whether they were answered from the cache
+	cacheLookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kops_controller_node_identity_cache_lookups_total",
+		Help: "Number of node identity lookups, by result (hit or miss).",
+	}, []string{"result"})
+
+	// cacheEntries is the number of cached node identities
+	cacheEntries = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "kops_controller_node_identity_cache_entries",
+		Help: "Number of cached node identities.",
+	})
+
+	// identified counts the nodes identified from the cloud, by result
+	identified = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kops_controller_node_identity_cloud_lookups_total",
+		Help: "Number of nodes identified from the cloud, by result.",
+	}, []string{"result"})
+
+	// batchSize measures the number of nodes identified by each cloud lookup
+	batchSize = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "kops_controller_node_identity_batch_size",
+		Help:    "Number of nodes identified by each cloud lookup.",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 8),
+	})
+
+	// rateLimitWait measures how long cloud lookups were delayed by the rate limiter
+	rateLimitWait = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "kops_controller_node_identity_rate_limit_wait_seconds",
+		Help:    "Time that cloud lookups of node identities waited for the rate limiter.",
+		Buckets: prometheus.ExponentialBuckets(0.001, 4, 10),
+	})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(cacheLookups, cacheEntries, identified, batchSize, rateLimitWait)
+}
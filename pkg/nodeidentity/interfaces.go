@@ -26,6 +26,20 @@ type Identifier interface {
 	IdentifyNode(ctx context.Context, node *corev1.Node) (*Info, error)
 }
 
+// BatchIdentifier is an Identifier that can identify many nodes with fewer cloud requests than identifying them one at a time.
+type BatchIdentifier interface {
+	Identifier
+
+	// IdentifyNodes identifies the nodes, returning a result for each node, in the same order.
+	IdentifyNodes(ctx context.Context, nodes []*corev1.Node) []BatchResult
+}
+
+// BatchResult is the result of identifying one of the nodes of a batch.
+type BatchResult struct {
+	Info *Info
+	Err  error
+}
+
 type Info struct {
 	InstanceID string
 	Labels     map[string]string
@@ -309,6 +309,13 @@ func (n *nodeUpConfigBuilder) BuildConfig(ig *kops.InstanceGroup, wellKnownAddre
 
 		if isMaster || usesLegacyGossip {
 			config.Channels = n.channels
+			if isMaster && cluster.UsesAddonSigning() {
+				channelsVerifyKeys, err := buildChannelsVerifyKeys(cluster, keysets)
+				if err != nil {
+					return nil, nil, err
+				}
+				config.ChannelsVerifyKeys = channelsVerifyKeys
+			}
 			for _, arch := range architectures.GetSupported() {
 				for _, a := range n.protokubeAsset[arch] {
 					config.Assets[arch] = append(config.Assets[arch], a.CompactString())
@@ -458,6 +465,26 @@ func (n *nodeUpConfigBuilder) BuildConfig(ig *kops.InstanceGroup, wellKnownAddre
 	return config, bootConfig, nil
 }
 
+// buildChannelsVerifyKeys returns the public keys trusted to sign the channels: the addon-signing keyset and any additional trusted keys.
+func buildChannelsVerifyKeys(cluster *kops.Cluster, keysets map[string]*fi.Keyset) (string, error) {
+	keyset := keysets[fi.CertificateIDAddonSigning]
+	if keyset == nil {
+		return "", fmt.Errorf("key %q not found", fi.CertificateIDAddonSigning)
+	}
+	publicKeys, err := keyset.ToPublicKeys()
+	if err != nil {
+		return "", fmt.Errorf("encoding %s keys: %w", fi.CertificateIDAddonSigning, err)
+	}
+
+	var b strings.Builder
+	b.WriteString(publicKeys)
+	for _, key := range cluster.Spec.AddonSigning.AdditionalTrustedKeys {
+		b.WriteString(strings.TrimSpace(key))
+		b.WriteString("\n")
+	}
+	return b.String(), nil
+}
+
 func loadCertificates(keysets map[string]*fi.Keyset, name string, config *nodeup.Config, includeKeypairID bool) error {
 	keyset := keysets[name]
 	if keyset == nil {
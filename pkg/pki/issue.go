@@ -51,6 +51,8 @@ type IssueCertRequest struct {
 	PrivateKey *PrivateKey
 	// Validity is the certificate validity. The default is 10 years.
 	Validity time.Duration
+	// MaxValidity, if set, is the longest validity the certificate may be issued with, including the default.
+	MaxValidity time.Duration
 
 	// Serial is the certificate serial number. If nil, a random number will be generated.
 	Serial *big.Int
@@ -145,6 +147,9 @@ func IssueCert(ctx context.Context, request *IssueCertRequest, keystore Keystore
 	if request.Validity != 0 {
 		template.NotAfter = time.Now().Add(request.Validity).UTC()
 	}
+	if request.MaxValidity != 0 && (request.Validity == 0 || request.Validity > request.MaxValidity) {
+		template.NotAfter = time.Now().Add(request.MaxValidity).UTC()
+	}
 
 	certificate, err := signNewCertificate(privateKey, template, signer, caPrivateKey)
 	if err != nil {
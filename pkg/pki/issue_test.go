@@ -127,6 +127,19 @@ func TestIssueCert(t *testing.T) {
 			expectedExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
 			expectedSubject:     pkix.Name{CommonName: "Test client"},
 		},
+		{
+			name: "clientMaxValidity",
+			req: IssueCertRequest{
+				Type: "client",
+				Subject: pkix.Name{
+					CommonName: "Test client",
+				},
+				MaxValidity: time.Hour * 4,
+			},
+			expectedKeyUsage:    x509.KeyUsageDigitalSignature,
+			expectedExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+			expectedSubject:     pkix.Name{CommonName: "Test client"},
+		},
 		{
 			req: IssueCertRequest{
 				Type: "clientServer",
@@ -162,12 +175,14 @@ func TestIssueCert(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			ctx := context.TODO()
 
-			var minExpectedValidity int64
-			if tc.req.Validity == 0 {
-				minExpectedValidity = time.Now().Add(time.Hour * 10 * 365 * 24).Unix()
-			} else {
-				minExpectedValidity = time.Now().Add(tc.req.Validity).Unix()
+			validity := tc.req.Validity
+			if validity == 0 {
+				validity = time.Hour * 10 * 365 * 24
 			}
+			if tc.req.MaxValidity != 0 && validity > tc.req.MaxValidity {
+				validity = tc.req.MaxValidity
+			}
+			minExpectedValidity := time.Now().Add(validity).Unix()
 
 			var keystore Keystore
 			if tc.req.Type != "ca" {
@@ -231,12 +246,7 @@ func TestIssueCert(t *testing.T) {
 			}
 
 			// validity
-			var maxExpectedValidity int64
-			if tc.req.Validity == 0 {
-				maxExpectedValidity = time.Now().Add(time.Hour * 10 * 365 * 24).Unix()
-			} else {
-				maxExpectedValidity = time.Now().Add(tc.req.Validity).Unix()
-			}
+			maxExpectedValidity := time.Now().Add(validity).Unix()
 			assert.Less(t, cert.NotBefore.Unix(), time.Now().Add(time.Hour*-47).Unix(), "NotBefore")
 			assert.GreaterOrEqual(t, cert.NotAfter.Unix(), minExpectedValidity, "NotAfter")
 			assert.LessOrEqual(t, cert.NotAfter.Unix(), maxExpectedValidity, "NotAfter")
@@ -0,0 +1,128 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// pemTypeKeyReference is the PEM block type of a private key that is held outside kops,
+// for example in a PKCS#11 module or a cloud KMS. The block holds the URI of the key.
+const pemTypeKeyReference = "KOPS PRIVATE KEY REFERENCE"
+
+// SignerProvider loads the signer for a private key that is referenced by a URI.
+type SignerProvider func(ctx context.Context, uri string) (crypto.Signer, error)
+
+var (
+	signerProvidersMutex sync.Mutex
+	signerProviders      = map[string]SignerProvider{}
+)
+
+// RegisterSignerProvider registers the provider of the keys referenced by URIs with the scheme.
+func RegisterSignerProvider(scheme string, provider SignerProvider) {
+	signerProvidersMutex.Lock()
+	defer signerProvidersMutex.Unlock()
+	signerProviders[scheme] = provider
+}
+
+func findSignerProvider(uri string) (SignerProvider, error) {
+	scheme, _, found := strings.Cut(uri, ":")
+	if !found || scheme == "" {
+		return nil, fmt.Errorf("invalid key URI %q", uri)
+	}
+
+	signerProvidersMutex.Lock()
+	defer signerProvidersMutex.Unlock()
+	provider := signerProviders[scheme]
+	if provider == nil {
+		return nil, fmt.Errorf("unsupported key URI %q: no provider for %q keys", uri, scheme)
+	}
+	return provider, nil
+}
+
+// NewPrivateKeyReference returns a private key that is held outside kops and referenced by the URI.
+// The key is only loaded when it is first used, so keysets can be read where the key is not available.
+func NewPrivateKeyReference(uri string) (*PrivateKey, error) {
+	provider, err := findSignerProvider(uri)
+	if err != nil {
+		return nil, err
+	}
+	return &PrivateKey{Key: &keyReference{uri: uri, provider: provider}}, nil
+}
+
+// Reference returns the URI of a private key that is held outside kops, or "" if the key material is held by kops.
+func (k *PrivateKey) Reference() string {
+	if ref, ok := k.Key.(*keyReference); ok {
+		return ref.uri
+	}
+	return ""
+}
+
+// Load loads a private key that is held outside kops, to check that it is available.
+// It does nothing for a key whose material is held by kops.
+func (k *PrivateKey) Load() error {
+	if ref, ok := k.Key.(*keyReference); ok {
+		_, err := ref.load()
+		return err
+	}
+	return nil
+}
+
+// keyReference is a crypto.Signer for a key that is referenced by URI, and loaded on first use.
+type keyReference struct {
+	uri      string
+	provider SignerProvider
+
+	once   sync.Once
+	signer crypto.Signer
+	err    error
+}
+
+var _ crypto.Signer = &keyReference{}
+
+func (r *keyReference) load() (crypto.Signer, error) {
+	r.once.Do(func() {
+		r.signer, r.err = r.provider(context.TODO(), r.uri)
+		if r.err != nil {
+			r.err = fmt.Errorf("loading key %q: %w", r.uri, r.err)
+		}
+	})
+	return r.signer, r.err
+}
+
+// Public implements crypto.Signer. It returns nil if the key cannot be loaded.
+func (r *keyReference) Public() crypto.PublicKey {
+	signer, err := r.load()
+	if err != nil {
+		return nil
+	}
+	return signer.Public()
+}
+
+// Sign implements crypto.Signer.
+func (r *keyReference) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	signer, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	return signer.Sign(rand, digest, opts)
+}
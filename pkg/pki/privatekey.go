@@ -152,6 +152,10 @@ func (k *PrivateKey) WriteTo(w io.Writer) (int64, error) {
 	var data bytes.Buffer
 
 	switch pk := k.Key.(type) {
+	case *keyReference:
+		if err := pem.Encode(w, &pem.Block{Type: pemTypeKeyReference, Bytes: []byte(pk.uri)}); err != nil {
+			return 0, fmt.Errorf("error encoding private key reference: %w", err)
+		}
 	case *rsa.PrivateKey:
 		if err := pem.Encode(w, &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(pk)}); err != nil {
 			return 0, fmt.Errorf("error encoding RSA private key: %w", err)
@@ -197,6 +201,13 @@ func parsePEMPrivateKey(pemData []byte) (crypto.Signer, error) {
 		case "EC PRIVATE KEY":
 			klog.V(10).Infof("Parsing pem block: %q", block.Type)
 			return x509.ParseECPrivateKey(block.Bytes)
+		case pemTypeKeyReference:
+			klog.V(10).Infof("Parsing pem block: %q", block.Type)
+			k, err := NewPrivateKeyReference(string(block.Bytes))
+			if err != nil {
+				return nil, err
+			}
+			return k.Key, nil
 		case "PRIVATE KEY":
 			klog.V(10).Infof("Parsing pem block: %q", block.Type)
 			k, err := x509.ParsePKCS8PrivateKey(block.Bytes)
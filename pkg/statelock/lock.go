@@ -0,0 +1,247 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package statelock implements an advisory lock on a cluster's state store, taken by long operations
+// such as "kops update cluster --yes" so that they do not run concurrently.
+package statelock
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/util/localuser"
+	"k8s.io/kops/util/pkg/vfs"
+	"sigs.k8s.io/yaml"
+)
+
+// DefaultTTL is how long a lock is held without being renewed.
+// A held lock is renewed periodically, so a lock only expires if its holder stopped without releasing it.
+const DefaultTTL = 5 * time.Minute
+
+// Lock is the lock, as recorded in the state store.
+type Lock struct {
+	// Holder identifies the user and host holding the lock.
+	Holder string `json:"holder"`
+	// Operation is the operation the lock is held for.
+	Operation string `json:"operation"`
+	// AcquiredAt is when the lock was acquired.
+	AcquiredAt time.Time `json:"acquiredAt"`
+	// ExpiresAt is when the lock expires, unless it is renewed.
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// ErrLost is the cause of the cancellation of the context of a held lock, when the lock is lost before it is released.
+var ErrLost = errors.New("lost the state store lock")
+
+// LockedError is returned when the lock is held by another operation.
+type LockedError struct {
+	Lock *Lock
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("the cluster is locked by %s for %q since %s; the lock expires at %s if it is not renewed",
+		e.Lock.Holder, e.Lock.Operation, e.Lock.AcquiredAt.Format(time.RFC3339), e.Lock.ExpiresAt.Format(time.RFC3339))
+}
+
+// Held is a lock held by this process. It is renewed until it is released.
+// If it is lost, because it was taken over or could not be renewed before it expired, its context is cancelled.
+type Held struct {
+	p   vfs.Path
+	acl vfs.ACL
+	ttl time.Duration
+
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+
+	mutex   sync.Mutex
+	lock    Lock
+	version string
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// Acquire takes the lock at the path for the operation, unless it is held by another operation and has not expired.
+// When the state store does not support conditional writes, two operations starting at the same time can both take the lock.
+func Acquire(ctx context.Context, p vfs.Path, acl vfs.ACL, operation string, ttl time.Duration) (*Held, error) {
+	for attempt := 0; ; attempt++ {
+		data, version, err := vfs.ReadFileWithVersion(ctx, p)
+		create := false
+		if err != nil {
+			if !os.IsNotExist(err) {
+				return nil, fmt.Errorf("error reading lock %q: %w", p, err)
+			}
+			create = true
+		} else {
+			existing := &Lock{}
+			if err := yaml.Unmarshal(data, existing); err != nil {
+				return nil, fmt.Errorf("error parsing lock %q: %w", p, err)
+			}
+			if time.Now().Before(existing.ExpiresAt) {
+				return nil, &LockedError{Lock: existing}
+			}
+			klog.Warningf("taking over the expired lock held by %s for %q", existing.Holder, existing.Operation)
+		}
+
+		now := time.Now().UTC()
+		h := &Held{
+			p:   p,
+			acl: acl,
+			ttl: ttl,
+			lock: Lock{
+				Holder:     localuser.Current().String(),
+				Operation:  operation,
+				AcquiredAt: now,
+				ExpiresAt:  now.Add(ttl),
+			},
+			stop: make(chan struct{}),
+			done: make(chan struct{}),
+		}
+		h.version, err = h.write(ctx, version, create)
+		if err != nil {
+			if errors.Is(err, vfs.ErrVersionConflict) && attempt < 2 {
+				// Another operation changed the lock since we read it; read it again
+				continue
+			}
+			return nil, err
+		}
+
+		h.ctx, h.cancel = context.WithCancelCause(ctx)
+		go h.renew(ctx)
+		return h, nil
+	}
+}
+
+// Lock returns the lock, as recorded in the state store.
+func (h *Held) Lock() Lock {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return h.lock
+}
+
+// Context returns a context that is cancelled when the lock is lost or released.
+// The operation the lock is held for should run with it, so that it stops if the lock is lost.
+func (h *Held) Context() context.Context {
+	return h.ctx
+}
+
+// Err returns an error wrapping ErrLost if the lock was lost, or nil.
+func (h *Held) Err() error {
+	if err := context.Cause(h.ctx); errors.Is(err, ErrLost) {
+		return err
+	}
+	return nil
+}
+
+// Release stops renewing the lock, and removes it unless another operation has taken it over.
+func (h *Held) Release(ctx context.Context) {
+	close(h.stop)
+	<-h.done
+	defer h.cancel(context.Canceled)
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	data, version, err := vfs.ReadFileWithVersion(ctx, h.p)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			klog.Warningf("error reading lock %q to release it: %v", h.p, err)
+		}
+		return
+	}
+	current := &Lock{}
+	if err := yaml.Unmarshal(data, current); err != nil {
+		klog.Warningf("error parsing lock %q to release it: %v", h.p, err)
+		return
+	}
+	if version != h.version || current.Holder != h.lock.Holder || !current.AcquiredAt.Equal(h.lock.AcquiredAt) {
+		klog.Warningf("not releasing lock %q, it was taken over by %s for %q", h.p, current.Holder, current.Operation)
+		return
+	}
+	if err := h.p.Remove(ctx); err != nil {
+		klog.Warningf("error releasing lock %q: %v", h.p, err)
+	}
+}
+
+// renew extends the expiry of the lock until it is released
+func (h *Held) renew(ctx context.Context) {
+	defer close(h.done)
+
+	ticker := time.NewTicker(h.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-h.stop:
+			return
+		case <-ticker.C:
+		}
+
+		h.mutex.Lock()
+		previous := h.lock
+		h.lock.ExpiresAt = time.Now().UTC().Add(h.ttl)
+		version, err := h.write(ctx, h.version, false)
+		if err != nil {
+			h.lock = previous
+		} else {
+			h.version = version
+		}
+		h.mutex.Unlock()
+
+		if err != nil {
+			if errors.Is(err, vfs.ErrVersionConflict) {
+				h.cancel(fmt.Errorf("%w %q: it was taken over by another operation", ErrLost, h.p))
+				return
+			}
+			if time.Now().After(previous.ExpiresAt) {
+				h.cancel(fmt.Errorf("%w %q: it expired before it could be renewed: %w", ErrLost, h.p, err))
+				return
+			}
+			klog.Warningf("error renewing lock %q: %v", h.p, err)
+		}
+	}
+}
+
+// write writes the lock, if the lock file is still at the version, or does not exist if create is true
+func (h *Held) write(ctx context.Context, version string, create bool) (string, error) {
+	data, err := yaml.Marshal(h.lock)
+	if err != nil {
+		return "", fmt.Errorf("error serializing lock: %w", err)
+	}
+
+	if _, ok := h.p.(vfs.HasVersion); !ok && create {
+		if err := h.p.CreateFile(ctx, bytes.NewReader(data), h.acl); err != nil {
+			if os.IsExist(err) {
+				return "", fmt.Errorf("error writing lock %q: %w", h.p, vfs.ErrVersionConflict)
+			}
+			return "", fmt.Errorf("error writing lock %q: %w", h.p, err)
+		}
+		return "", nil
+	}
+
+	version, err = vfs.WriteFileIfVersion(ctx, h.p, bytes.NewReader(data), h.acl, version)
+	if err != nil {
+		return "", fmt.Errorf("error writing lock %q: %w", h.p, err)
+	}
+	return version, nil
+}
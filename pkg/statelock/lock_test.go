@@ -0,0 +1,149 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statelock
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"k8s.io/kops/pkg/testutils/testcontext"
+	"k8s.io/kops/util/pkg/vfs"
+	"sigs.k8s.io/yaml"
+)
+
+func TestAcquireRelease(t *testing.T) {
+	ctx := testcontext.ForTest(t)
+	p := vfs.NewMemFSPath(vfs.NewMemFSContext(), "cluster/lock.yaml")
+
+	held, err := Acquire(ctx, p, nil, "update cluster", DefaultTTL)
+	if err != nil {
+		t.Fatalf("unexpected error acquiring lock: %v", err)
+	}
+	if lock := held.Lock(); lock.Operation != "update cluster" || lock.Holder == "" {
+		t.Errorf("unexpected lock %+v", lock)
+	}
+
+	_, err = Acquire(ctx, p, nil, "rolling-update cluster", DefaultTTL)
+	var lockedErr *LockedError
+	if !errors.As(err, &lockedErr) {
+		t.Fatalf("expected LockedError acquiring held lock, got %v", err)
+	}
+	if lockedErr.Lock.Operation != "update cluster" {
+		t.Errorf("expected lock held for %q, got %q", "update cluster", lockedErr.Lock.Operation)
+	}
+
+	held.Release(ctx)
+	if _, err := p.ReadFile(ctx); err == nil {
+		t.Errorf("expected lock to be removed on release")
+	}
+
+	held, err = Acquire(ctx, p, nil, "rolling-update cluster", DefaultTTL)
+	if err != nil {
+		t.Fatalf("unexpected error acquiring released lock: %v", err)
+	}
+	held.Release(ctx)
+}
+
+func TestAcquireExpired(t *testing.T) {
+	ctx := testcontext.ForTest(t)
+	p := vfs.NewMemFSPath(vfs.NewMemFSContext(), "cluster/lock.yaml")
+
+	expired := Lock{
+		Holder:     "someone@elsewhere",
+		Operation:  "update cluster",
+		AcquiredAt: time.Now().Add(-time.Hour),
+		ExpiresAt:  time.Now().Add(-time.Minute),
+	}
+	data, err := yaml.Marshal(expired)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := p.WriteFile(ctx, bytes.NewReader(data), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	held, err := Acquire(ctx, p, nil, "rolling-update cluster", DefaultTTL)
+	if err != nil {
+		t.Fatalf("unexpected error taking over expired lock: %v", err)
+	}
+	if lock := held.Lock(); lock.Holder == expired.Holder || lock.Operation != "rolling-update cluster" {
+		t.Errorf("expected lock to be taken over, got %+v", lock)
+	}
+	held.Release(ctx)
+}
+
+func TestRenew(t *testing.T) {
+	ctx := testcontext.ForTest(t)
+	p := vfs.NewMemFSPath(vfs.NewMemFSContext(), "cluster/lock.yaml")
+
+	ttl := 300 * time.Millisecond
+	held, err := Acquire(ctx, p, nil, "rolling-update cluster", ttl)
+	if err != nil {
+		t.Fatalf("unexpected error acquiring lock: %v", err)
+	}
+	defer held.Release(ctx)
+	acquired := held.Lock()
+
+	// The lock is still held after its initial expiry
+	time.Sleep(2 * ttl)
+	if _, err := Acquire(ctx, p, nil, "update cluster", ttl); err == nil {
+		t.Fatalf("expected renewed lock to still be held")
+	}
+	if renewed := held.Lock(); !renewed.ExpiresAt.After(acquired.ExpiresAt) {
+		t.Errorf("expected lock expiry to be extended, got %v", renewed.ExpiresAt)
+	}
+}
+
+func TestLost(t *testing.T) {
+	ctx := testcontext.ForTest(t)
+	p := vfs.NewMemFSPath(vfs.NewMemFSContext(), "cluster/lock.yaml")
+
+	ttl := 300 * time.Millisecond
+	held, err := Acquire(ctx, p, nil, "rolling-update cluster", ttl)
+	if err != nil {
+		t.Fatalf("unexpected error acquiring lock: %v", err)
+	}
+	defer held.Release(ctx)
+	if err := held.Err(); err != nil {
+		t.Fatalf("unexpected error for held lock: %v", err)
+	}
+
+	// Another operation takes over the lock
+	data, err := yaml.Marshal(Lock{
+		Holder:     "someone@elsewhere",
+		Operation:  "update cluster",
+		AcquiredAt: time.Now(),
+		ExpiresAt:  time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := p.WriteFile(ctx, bytes.NewReader(data), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case <-held.Context().Done():
+	case <-time.After(2 * ttl):
+		t.Fatalf("expected the context to be cancelled when the lock is lost")
+	}
+	if err := held.Err(); !errors.Is(err, ErrLost) {
+		t.Errorf("expected ErrLost, got %v", err)
+	}
+}
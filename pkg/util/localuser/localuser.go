@@ -0,0 +1,64 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package localuser identifies the local user and host, for the records kOps keeps of who changed a cluster.
+package localuser
+
+import (
+	"os"
+	"os/user"
+
+	"k8s.io/klog/v2"
+)
+
+// Identity is the local user and host
+type Identity struct {
+	// Username is the login name of the local user, or empty if it is not known
+	Username string
+	// Name is the display name of the local user
+	Name string
+	// Host is the hostname, or empty if it is not known
+	Host string
+}
+
+// Current returns the identity of the local user and host
+func Current() Identity {
+	var identity Identity
+	if u, err := user.Current(); err != nil || u == nil {
+		klog.V(2).Infof("unable to get user: %v", err)
+	} else {
+		identity.Username = u.Username
+		identity.Name = u.Name
+	}
+	if host, err := os.Hostname(); err != nil {
+		klog.V(2).Infof("unable to get hostname: %v", err)
+	} else {
+		identity.Host = host
+	}
+	return identity
+}
+
+// String returns user@host, with "unknown" for an unknown user
+func (i Identity) String() string {
+	s := i.Username
+	if s == "" {
+		s = "unknown"
+	}
+	if i.Host != "" {
+		s += "@" + i.Host
+	}
+	return s
+}
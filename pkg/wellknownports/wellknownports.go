@@ -20,6 +20,15 @@ const (
 	// KubeAPIServer is the port where kube-apiserver listens.
 	KubeAPIServer = 443
 
+	// KopsMonitoringAgent is the port where the monitoring agent serves its own web interface, on localhost only.
+	KopsMonitoringAgent = 3984
+
+	// KopsControllerMetrics is the port where kops-controller serves metrics, on localhost only.
+	KopsControllerMetrics = 3985
+
+	// DNSControllerMetrics is the port where dns-controller serves metrics, on localhost only.
+	DNSControllerMetrics = 3986
+
 	// NodeupChallenge is the port where nodeup listens for challenges.
 	NodeupChallenge = 3987
 
@@ -41,4 +41,11 @@ const (
 
 	// KubeApiserverHealthcheckName is the username for the kube-apiserver-healthcheck user
 	KubeApiserverHealthcheckName = "kube-apiserver-healthcheck"
+
+	// KopsMonitoringID is the user id for the monitoring agent, which needs to read the local client certificates
+	// This should match the monitoring DaemonSet's runAsUser
+	KopsMonitoringID = 10013
+
+	// KopsMonitoringName is the username for the monitoring agent user
+	KopsMonitoringName = "kops-monitoring"
 )
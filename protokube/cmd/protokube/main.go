@@ -56,7 +56,7 @@ func run() error {
 	var zones []string
 	var containerized, master, gossip bool
 	var cloud, clusterID, dnsInternalSuffix, gossipSecret, gossipListen, gossipProtocol, gossipSecretSecondary, gossipListenSecondary, gossipProtocolSecondary string
-	var flagChannels string
+	var flagChannels, channelsVerifyKeys string
 	var dnsUpdateInterval int
 
 	flag.BoolVar(&containerized, "containerized", containerized, "Set if we are running containerized")
@@ -67,6 +67,7 @@ func run() error {
 	flag.StringVar(&dnsInternalSuffix, "dns-internal-suffix", dnsInternalSuffix, "DNS suffix for internal domain names")
 	flags.IntVar(&dnsUpdateInterval, "dns-update-interval", 5, "Configure interval at which to update DNS records.")
 	flag.StringVar(&flagChannels, "channels", flagChannels, "channels to install")
+	flag.StringVar(&channelsVerifyKeys, "channels-verify-keys", channelsVerifyKeys, "File of PEM-encoded public keys that must have signed the channels and their manifests")
 	flag.StringVar(&gossipProtocol, "gossip-protocol", "mesh", "mesh/memberlist")
 	flag.StringVar(&gossipListen, "gossip-listen", fmt.Sprintf("0.0.0.0:%d", wellknownports.ProtokubeGossipWeaveMesh), "address:port on which to bind for gossip")
 	flags.StringVar(&gossipSecret, "gossip-secret", gossipSecret, "Secret to use to secure gossip")
@@ -204,6 +205,7 @@ func run() error {
 		BootstrapMasterNodeLabels: bootstrapMasterNodeLabels,
 		NodeName:                  nodeName,
 		Channels:                  channels,
+		ChannelsVerifyKeys:        channelsVerifyKeys,
 		InternalDNSSuffix:         dnsInternalSuffix,
 		Kubernetes:                protokube.NewKubernetesContext(),
 		Master:                    master,
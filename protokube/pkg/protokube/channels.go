@@ -26,11 +26,15 @@ import (
 )
 
 // applyChannel is responsible for applying the channel manifests
-func applyChannel(channel string) error {
+func applyChannel(channel string, verifyKeys string) error {
 	// We don't embed the channels code because we expect this will eventually be part of kubectl
 	klog.Infof("checking channel: %q", channel)
 
-	out, err := execChannels("apply", "channel", channel, "--v=4", "--yes")
+	args := []string{"apply", "channel", channel, "--v=4", "--yes"}
+	if verifyKeys != "" {
+		args = append(args, "--verify-keys="+verifyKeys)
+	}
+	out, err := execChannels(args...)
 	klog.V(4).Infof("apply channel output was: %v", out)
 	return err
 }
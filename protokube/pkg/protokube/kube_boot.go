@@ -32,6 +32,8 @@ var RootFS = "/"
 type KubeBoot struct {
 	// Channels is a list of channel to apply
 	Channels []string
+	// ChannelsVerifyKeys is the path of the public keys used to verify the channels, if signing is required
+	ChannelsVerifyKeys string
 	// InternalDNSSuffix is the dns zone we are living in
 	InternalDNSSuffix string
 	// Kubernetes holds a kubernetes client
@@ -82,7 +84,7 @@ func (k *KubeBoot) RunSyncLoop() {
 func (k *KubeBoot) syncOnce(ctx context.Context) error {
 	if k.Master {
 		for _, channel := range k.Channels {
-			if err := applyChannel(channel); err != nil {
+			if err := applyChannel(channel, k.ChannelsVerifyKeys); err != nil {
 				klog.Warningf("error applying channel %q: %v", channel, err)
 			}
 		}
@@ -724,6 +724,14 @@ resource "aws_s3_object" "many-addons-example-com-addons-dns-controller-addons-k
   server_side_encryption = "AES256"
 }
 
+resource "aws_s3_object" "many-addons-example-com-addons-gateway-api-addons-k8s-io-v1-4-0-experimental" {
+  bucket                 = "testingBucket"
+  content                = file("${path.module}/data/aws_s3_object_many-addons.example.com-addons-gateway-api.addons.k8s.io-v1.4.0-experimental_content")
+  key                    = "tests/many-addons.example.com/addons/gateway-api.addons.k8s.io/v1.4.0-experimental.yaml"
+  provider               = aws.files
+  server_side_encryption = "AES256"
+}
+
 resource "aws_s3_object" "many-addons-example-com-addons-kops-controller-addons-k8s-io-k8s-1-16" {
   bucket                 = "testingBucket"
   content                = file("${path.module}/data/aws_s3_object_many-addons.example.com-addons-kops-controller.addons.k8s.io-k8s-1.16_content")
@@ -756,6 +764,22 @@ resource "aws_s3_object" "many-addons-example-com-addons-metrics-server-addons-k
   server_side_encryption = "AES256"
 }
 
+resource "aws_s3_object" "many-addons-example-com-addons-monitoring-addons-k8s-io-k8s-1-25" {
+  bucket                 = "testingBucket"
+  content                = file("${path.module}/data/aws_s3_object_many-addons.example.com-addons-monitoring.addons.k8s.io-k8s-1.25_content")
+  key                    = "tests/many-addons.example.com/addons/monitoring.addons.k8s.io/k8s-1.25.yaml"
+  provider               = aws.files
+  server_side_encryption = "AES256"
+}
+
+resource "aws_s3_object" "many-addons-example-com-addons-monitoring-servicemonitors-addons-k8s-io-k8s-1-25" {
+  bucket                 = "testingBucket"
+  content                = file("${path.module}/data/aws_s3_object_many-addons.example.com-addons-monitoring-servicemonitors.addons.k8s.io-k8s-1.25_content")
+  key                    = "tests/many-addons.example.com/addons/monitoring-servicemonitors.addons.k8s.io/k8s-1.25.yaml"
+  provider               = aws.files
+  server_side_encryption = "AES256"
+}
+
 resource "aws_s3_object" "many-addons-example-com-addons-networking-amazon-vpc-routed-eni-k8s-1-16" {
   bucket                 = "testingBucket"
   content                = file("${path.module}/data/aws_s3_object_many-addons.example.com-addons-networking.amazon-vpc-routed-eni-k8s-1.16_content")
@@ -34,9 +34,17 @@ import (
 
 const CertificateIDCA = "kubernetes-ca"
 
+// CertificateIDAddonSigning is the keyset that signs the kOps-managed channel and addon manifests.
+const CertificateIDAddonSigning = "addon-signing"
+
+// CertificateIDAdminCA is the keyset that signs cluster admin credentials, when the cluster has an admin credentials policy.
+const CertificateIDAdminCA = "kubernetes-admin-ca"
+
 const (
 	// SecretNameSSHPrimary is the Name for the primary SSH key
 	SecretNameSSHPrimary = "admin"
+	// SecretNameTPMCredentials is the random secret that the key for the credentials of TPM attestation is derived from
+	SecretNameTPMCredentials = "tpm-credentials"
 )
 
 const (
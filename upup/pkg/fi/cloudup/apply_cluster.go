@@ -27,6 +27,7 @@ import (
 
 	"github.com/blang/semver/v4"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 	kopsbase "k8s.io/kops"
 	"k8s.io/kops/pkg/apis/kops"
@@ -106,6 +107,9 @@ type ApplyClusterCmd struct {
 	// DryRun is true if this is only a dry run
 	DryRun bool
 
+	// DryRunOutput receives the report of the changes for the dry-run target; defaults to stdout.
+	DryRunOutput io.Writer
+
 	// AllowKopsDowngrade permits applying with a kops version older than what was last used to apply to the cluster.
 	AllowKopsDowngrade bool
 
@@ -580,6 +584,11 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) (*ApplyResults, error) {
 				KopsModelContext: modelContext,
 			}
 
+			kmsSigningKeys, err := awsKMSSigningKeys(keyStore)
+			if err != nil {
+				return nil, err
+			}
+
 			l.Builders = append(l.Builders,
 				&awsmodel.APILoadBalancerBuilder{AWSModelContext: awsModelContext, Lifecycle: clusterLifecycle, SecurityLifecycle: securityLifecycle},
 				&awsmodel.BastionModelBuilder{AWSModelContext: awsModelContext, Lifecycle: clusterLifecycle, SecurityLifecycle: securityLifecycle},
@@ -588,7 +597,7 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) (*ApplyResults, error) {
 				&awsmodel.FirewallModelBuilder{AWSModelContext: awsModelContext, Lifecycle: securityLifecycle},
 				&awsmodel.SSHKeyModelBuilder{AWSModelContext: awsModelContext, Lifecycle: securityLifecycle},
 				&awsmodel.NetworkModelBuilder{AWSModelContext: awsModelContext, Lifecycle: networkLifecycle},
-				&awsmodel.IAMModelBuilder{AWSModelContext: awsModelContext, Lifecycle: securityLifecycle, Cluster: cluster},
+				&awsmodel.IAMModelBuilder{AWSModelContext: awsModelContext, Lifecycle: securityLifecycle, Cluster: cluster, KMSSigningKeys: kmsSigningKeys},
 				&awsmodel.OIDCProviderBuilder{AWSModelContext: awsModelContext, Lifecycle: securityLifecycle},
 			)
 
@@ -771,6 +780,9 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) (*ApplyResults, error) {
 
 	case TargetDryRun:
 		var out io.Writer = os.Stdout
+		if c.DryRunOutput != nil {
+			out = c.DryRunOutput
+		}
 		checkExisting := true
 		if c.GetAssets {
 			out = io.Discard
@@ -1045,6 +1057,28 @@ func (c *ApplyClusterCmd) validateKubernetesVersion() error {
 }
 
 // buildPermalink returns a link to our "permalink docs", to further explain an error message
+// awsKMSSigningKeys returns the IDs of the AWS KMS keys that hold the private keys of keysets,
+// which the control plane signs certificates with.
+func awsKMSSigningKeys(keyStore fi.CAStore) ([]string, error) {
+	keysets, err := keyStore.ListKeysets()
+	if err != nil {
+		return nil, fmt.Errorf("error listing keysets: %w", err)
+	}
+
+	keys := sets.New[string]()
+	for _, keyset := range keysets {
+		for _, item := range keyset.Items {
+			if item.PrivateKey == nil {
+				continue
+			}
+			if keyID, found := strings.CutPrefix(item.PrivateKey.Reference(), "aws-kms://"); found {
+				keys.Insert(keyID)
+			}
+		}
+	}
+	return sets.List(keys), nil
+}
+
 func buildPermalink(key, anchor string) string {
 	url := "https://github.com/kubernetes/kops/blob/master/permalinks/" + key + ".md"
 	if anchor != "" {
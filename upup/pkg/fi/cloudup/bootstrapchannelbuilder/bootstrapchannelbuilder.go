@@ -30,6 +30,7 @@ import (
 	"k8s.io/kops/pkg/kubemanifest"
 	"k8s.io/kops/pkg/model"
 	"k8s.io/kops/pkg/model/awsmodel"
+	"k8s.io/kops/pkg/model/components"
 	"k8s.io/kops/pkg/model/components/addonmanifests"
 	"k8s.io/kops/pkg/model/components/addonmanifests/awscloudcontrollermanager"
 	"k8s.io/kops/pkg/model/components/addonmanifests/awsebscsidriver"
@@ -45,7 +46,6 @@ import (
 	"k8s.io/kops/pkg/templates"
 	"k8s.io/kops/pkg/wellknownoperators"
 	"k8s.io/kops/upup/pkg/fi"
-	"k8s.io/kops/upup/pkg/fi/fitasks"
 	"k8s.io/kops/upup/pkg/fi/utils"
 	"k8s.io/kops/util/pkg/vfs"
 )
@@ -106,6 +106,8 @@ func (b *BootstrapChannelBuilder) Build(c *fi.CloudupModelBuilderContext) error
 		return err
 	}
 
+	signingKey := b.addSigningKeypair(c)
+
 	for _, a := range addons.Items {
 		// Older versions of channels that may be running on the upgrading cluster requires Version to be set
 		// We hardcode version to a high version to ensure an update is triggered on first run, and from then on
@@ -153,12 +155,7 @@ func (b *BootstrapChannelBuilder) Build(c *fi.CloudupModelBuilderContext) error
 		}
 		a.Spec.ManifestHash = manifestHash
 
-		c.AddTask(&fitasks.ManagedFile{
-			Contents:  fi.NewBytesResource(manifestBytes),
-			Lifecycle: b.Lifecycle,
-			Location:  fi.PtrTo(manifestPath),
-			Name:      fi.PtrTo(name),
-		})
+		b.addManagedFile(c, name, manifestPath, manifestBytes, signingKey)
 	}
 
 	if featureflag.UseAddonOperators.Enabled() {
@@ -201,12 +198,7 @@ func (b *BootstrapChannelBuilder) Build(c *fi.CloudupModelBuilderContext) error
 			}
 			a.Spec.ManifestHash = manifestHash
 
-			c.AddTask(&fitasks.ManagedFile{
-				Contents:  fi.NewBytesResource(manifestBytes),
-				Lifecycle: b.Lifecycle,
-				Location:  fi.PtrTo(manifestPath),
-				Name:      fi.PtrTo(name),
-			})
+			b.addManagedFile(c, name, manifestPath, manifestBytes, signingKey)
 
 			addon := addons.Add(&a.Spec)
 			addon.ManifestData = manifestBytes
@@ -260,12 +252,7 @@ func (b *BootstrapChannelBuilder) Build(c *fi.CloudupModelBuilderContext) error
 		}
 		a.ManifestHash = manifestHash
 
-		c.AddTask(&fitasks.ManagedFile{
-			Contents:  fi.NewBytesResource(manifestBytes),
-			Lifecycle: b.Lifecycle,
-			Location:  fi.PtrTo(manifestPath),
-			Name:      fi.PtrTo(name),
-		})
+		b.addManagedFile(c, name, manifestPath, manifestBytes, signingKey)
 
 		addons.Add(a)
 	}
@@ -274,6 +261,8 @@ func (b *BootstrapChannelBuilder) Build(c *fi.CloudupModelBuilderContext) error
 		return err
 	}
 
+	b.addDependencies(addons)
+
 	addonsObject := &channelsapi.Addons{}
 	addonsObject.Kind = "Addons"
 	addonsObject.ObjectMeta.Name = "bootstrap"
@@ -292,12 +281,7 @@ func (b *BootstrapChannelBuilder) Build(c *fi.CloudupModelBuilderContext) error
 
 	name := b.Cluster.ObjectMeta.Name + "-addons-bootstrap"
 
-	c.AddTask(&fitasks.ManagedFile{
-		Contents:  fi.NewBytesResource(addonsYAML),
-		Lifecycle: b.Lifecycle,
-		Location:  fi.PtrTo("addons/bootstrap-channel.yaml"),
-		Name:      fi.PtrTo(name),
-	})
+	b.addManagedFile(c, name, "addons/bootstrap-channel.yaml", addonsYAML, signingKey)
 
 	return nil
 }
@@ -611,6 +595,41 @@ func (b *BootstrapChannelBuilder) buildAddons(c *fi.CloudupModelBuilderContext)
 		}
 	}
 
+	monitoring := b.Cluster.Spec.Monitoring
+
+	if monitoring != nil && fi.ValueOf(monitoring.Enabled) {
+		key := "monitoring.addons.k8s.io"
+
+		{
+			location := key + "/k8s-1.25.yaml"
+			id := "k8s-1.25"
+
+			addon := addons.Add(&channelsapi.AddonSpec{
+				Name:     fi.PtrTo(key),
+				Selector: map[string]string{"k8s-addon": key},
+				Manifest: fi.PtrTo(location),
+				Id:       id,
+			})
+			addon.BuildPrune = true
+		}
+
+		if fi.ValueOf(monitoring.ServiceMonitors) {
+			key := "monitoring-servicemonitors.addons.k8s.io"
+
+			location := key + "/k8s-1.25.yaml"
+			id := "k8s-1.25"
+
+			addon := addons.Add(&channelsapi.AddonSpec{
+				Name:         fi.PtrTo(key),
+				Selector:     map[string]string{"k8s-addon": key},
+				Manifest:     fi.PtrTo(location),
+				Id:           id,
+				RequiresCRDs: []string{"servicemonitors.monitoring.coreos.com"},
+			})
+			addon.BuildPrune = true
+		}
+	}
+
 	nvidia := b.Cluster.Spec.Containerd.NvidiaGPU
 	igNvidia := false
 	for _, ig := range b.KopsModelContext.InstanceGroups {
@@ -637,6 +656,23 @@ func (b *BootstrapChannelBuilder) buildAddons(c *fi.CloudupModelBuilderContext)
 		}
 	}
 
+	if components.IsGatewayAPIEnabled(b.Cluster) {
+		key := "gateway-api.addons.k8s.io"
+
+		{
+			id := b.Cluster.Spec.GatewayAPI.Version + "-" + b.Cluster.Spec.GatewayAPI.Channel
+			location := key + "/" + id + ".yaml"
+
+			// We don't prune: removing the CRDs would delete every Gateway and route in the cluster.
+			addons.Add(&channelsapi.AddonSpec{
+				Name:     fi.PtrTo(key),
+				Selector: map[string]string{"k8s-addon": key},
+				Manifest: fi.PtrTo(location),
+				Id:       id,
+			})
+		}
+	}
+
 	if b.Cluster.Spec.CloudProvider.AWS != nil {
 		if b.Cluster.Spec.CloudProvider.AWS.LoadBalancerController != nil && fi.ValueOf(b.Cluster.Spec.CloudProvider.AWS.LoadBalancerController.Enabled) {
 
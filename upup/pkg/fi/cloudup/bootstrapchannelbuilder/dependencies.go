@@ -0,0 +1,58 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapchannelbuilder
+
+import (
+	"strings"
+)
+
+// addDependencies records the well-known ordering constraints between addons,
+// so that channels applies them in an order that lets them become ready.
+func (b *BootstrapChannelBuilder) addDependencies(addons *AddonList) {
+	var networking, certManager, gatewayAPI string
+	for _, addon := range addons.Items {
+		name := *addon.Spec.Name
+		switch {
+		case strings.HasPrefix(name, "networking."):
+			networking = name
+		case name == "certmanager.io":
+			certManager = name
+		case name == "gateway-api.addons.k8s.io":
+			gatewayAPI = name
+		}
+	}
+
+	for _, addon := range addons.Items {
+		name := *addon.Spec.Name
+
+		// DNS pods cannot start until the CNI is ready.
+		if networking != "" && (name == "coredns.addons.k8s.io" || name == "kube-dns.addons.k8s.io") {
+			addon.Spec.DependsOn = append(addon.Spec.DependsOn, networking)
+		}
+
+		// Addons that need PKI create cert-manager issuers, which need the cert-manager CRDs.
+		// The cert-manager pods cannot start before the CNI, which may itself need PKI, so we don't wait for them.
+		if certManager != "" && addon.Spec.NeedsPKI {
+			addon.Spec.DependsOnCRDs = append(addon.Spec.DependsOnCRDs, certManager)
+		}
+
+		// Gateway implementations watch the Gateway API resources, so the CRDs must be established first.
+		if gatewayAPI != "" && (name == "aws-load-balancer-controller.addons.k8s.io" || name == "networking.cilium.io") {
+			addon.Spec.DependsOn = append(addon.Spec.DependsOn, gatewayAPI)
+		}
+	}
+}
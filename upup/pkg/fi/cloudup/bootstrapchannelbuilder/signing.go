@@ -0,0 +1,97 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapchannelbuilder
+
+import (
+	"bytes"
+	"io"
+	"strings"
+
+	"k8s.io/kops/channels/pkg/signature"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/fitasks"
+)
+
+// addSigningKeypair adds the keypair used to sign the channel and addon manifests, if addon signing is enabled.
+func (b *BootstrapChannelBuilder) addSigningKeypair(c *fi.CloudupModelBuilderContext) *fitasks.Keypair {
+	if !b.Cluster.UsesAddonSigning() {
+		return nil
+	}
+
+	keypair := &fitasks.Keypair{
+		// We only need the private key, but it's easier to create a certificate as well.
+		Name:      fi.PtrTo(fi.CertificateIDAddonSigning),
+		Lifecycle: b.Lifecycle,
+		Subject:   "cn=" + fi.CertificateIDAddonSigning,
+		Type:      "ca",
+	}
+	c.AddTask(keypair)
+	return keypair
+}
+
+// addManagedFile adds the file to the state store, along with its detached signature if signingKey is not nil.
+func (b *BootstrapChannelBuilder) addManagedFile(c *fi.CloudupModelBuilderContext, name string, location string, contents []byte, signingKey *fitasks.Keypair) {
+	c.AddTask(&fitasks.ManagedFile{
+		Contents:  fi.NewBytesResource(contents),
+		Lifecycle: b.Lifecycle,
+		Location:  fi.PtrTo(location),
+		Name:      fi.PtrTo(name),
+	})
+
+	if signingKey == nil {
+		return
+	}
+
+	c.AddTask(&fitasks.ManagedFile{
+		Contents: &addonSignature{
+			signingKey: signingKey,
+			contents:   contents,
+		},
+		Lifecycle: b.Lifecycle,
+		Location:  fi.PtrTo(location + signature.Extension),
+		Name:      fi.PtrTo(name + signature.Extension),
+	})
+}
+
+// addonSignature is the detached signature of a channel or manifest, made with the primary addon-signing key.
+type addonSignature struct {
+	signingKey *fitasks.Keypair
+	contents   []byte
+}
+
+var (
+	_ fi.Resource               = &addonSignature{}
+	_ fi.CloudupHasDependencies = &addonSignature{}
+)
+
+func (s *addonSignature) GetDependencies(tasks map[string]fi.CloudupTask) []fi.CloudupTask {
+	return []fi.CloudupTask{s.signingKey}
+}
+
+func (s *addonSignature) Open() (io.Reader, error) {
+	keyset := s.signingKey.Keyset()
+	if keyset == nil || keyset.Primary == nil || keyset.Primary.PrivateKey == nil {
+		// The keypair has not been created yet, e.g. in a dry-run.
+		return strings.NewReader("<< TO BE GENERATED >>\n"), nil
+	}
+
+	sig, err := signature.Sign(keyset.Primary.PrivateKey.Key, s.contents)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(sig), nil
+}
@@ -88,6 +88,9 @@ func (c *populateClusterSpec) run(ctx context.Context, clientset simple.Clientse
 
 	reflectutils.JSONMergeStruct(cluster, c.InputCluster)
 
+	// The completed spec is not the object in the state store, so it has no version there
+	cluster.ResourceVersion = ""
+
 	err := c.assignSubnets(cluster)
 	if err != nil {
 		return err
@@ -323,6 +326,8 @@ func (c *populateClusterSpec) run(ctx context.Context, clientset simple.Clientse
 			codeModels = append(codeModels, &components.ClusterAutoscalerOptionsBuilder{OptionsContext: optionsContext})
 			codeModels = append(codeModels, &components.NodeTerminationHandlerOptionsBuilder{OptionsContext: optionsContext})
 			codeModels = append(codeModels, &components.NodeProblemDetectorOptionsBuilder{OptionsContext: optionsContext})
+			codeModels = append(codeModels, &components.MonitoringOptionsBuilder{OptionsContext: optionsContext})
+			codeModels = append(codeModels, &components.GatewayAPIOptionsBuilder{OptionsContext: optionsContext})
 			codeModels = append(codeModels, &components.AWSOptionsBuilder{OptionsContext: optionsContext})
 			codeModels = append(codeModels, &components.AWSEBSCSIDriverOptionsBuilder{OptionsContext: optionsContext})
 			codeModels = append(codeModels, &components.AWSCloudControllerManagerOptionsBuilder{OptionsContext: optionsContext})
@@ -32,6 +32,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net"
+	"net/url"
 	"os"
 	"path"
 	"sort"
@@ -50,14 +51,18 @@ import (
 	apiModel "k8s.io/kops/pkg/apis/kops/model"
 	"k8s.io/kops/pkg/apis/kops/util"
 	"k8s.io/kops/pkg/bootstrap/pkibootstrap"
+	"k8s.io/kops/pkg/bootstrap/tpmbootstrap"
 	"k8s.io/kops/pkg/featureflag"
 	"k8s.io/kops/pkg/flagbuilder"
 	"k8s.io/kops/pkg/kubemanifest"
 	"k8s.io/kops/pkg/model"
+	"k8s.io/kops/pkg/model/components"
+	"k8s.io/kops/pkg/model/components/etcdmanager"
 	"k8s.io/kops/pkg/model/components/kopscontroller"
 	"k8s.io/kops/pkg/model/iam"
 	"k8s.io/kops/pkg/resources/spotinst"
 	"k8s.io/kops/pkg/wellknownports"
+	"k8s.io/kops/pkg/wellknownusers"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/azure"
@@ -158,6 +163,14 @@ func (tf *TemplateFunctions) AddTo(dest template.FuncMap, secretStore fi.SecretS
 
 	dest["KopsControllerArgv"] = tf.KopsControllerArgv
 	dest["KopsControllerConfig"] = tf.KopsControllerConfig
+	dest["MonitoringConfig"] = tf.MonitoringConfig
+	dest["MonitoringTargets"] = tf.MonitoringTargets
+	dest["MonitoringListenAddress"] = func() string {
+		return fmt.Sprintf("127.0.0.1:%d", wellknownports.KopsMonitoringAgent)
+	}
+	dest["MonitoringUserID"] = func() int {
+		return wellknownusers.KopsMonitoringID
+	}
 	kopscontroller.AddTemplateFunctions(cluster, dest)
 	dest["DnsControllerArgv"] = tf.DNSControllerArgv
 	dest["ExternalDnsArgv"] = tf.ExternalDNSArgv
@@ -412,6 +425,10 @@ func (tf *TemplateFunctions) AddTo(dest template.FuncMap, secretStore fi.SecretS
 		return sc != nil && fi.ValueOf(sc.Enabled)
 	}
 
+	dest["IsGatewayAPIEnabled"] = func() bool {
+		return components.IsGatewayAPIEnabled(cluster)
+	}
+
 	dest["IsKubernetesGTE"] = tf.IsKubernetesGTE
 	dest["IsKubernetesLT"] = tf.IsKubernetesLT
 
@@ -670,6 +687,10 @@ func (tf *TemplateFunctions) DNSControllerArgv() ([]string, error) {
 
 	// permit wildcard updates
 	argv = append(argv, "--zone=*/*")
+
+	if apiModel.UseMonitoring(cluster) {
+		argv = append(argv, "--metrics-listen="+metricsListenAddress(cluster, wellknownports.DNSControllerMetrics))
+	}
 	// Verbose, but not crazy logging
 	argv = append(argv, "-v=2")
 
@@ -685,12 +706,18 @@ func (tf *TemplateFunctions) KopsControllerConfig() (string, error) {
 		Cloud:       string(cluster.GetCloudProvider()),
 		ConfigBase:  cluster.Spec.ConfigStore.Base,
 		SecretStore: cluster.Spec.ConfigStore.Secrets,
+
+		NodeIdentityRevocation: cluster.Spec.NodeIdentityRevocation != nil,
 	}
 
 	if featureflag.CacheNodeidentityInfo.Enabled() {
 		config.CacheNodeidentityInfo = true
 	}
 
+	if apiModel.UseMonitoring(cluster) {
+		config.MetricsAddress = metricsListenAddress(cluster, wellknownports.KopsControllerMetrics)
+	}
+
 	if featureflag.ClusterAPI.Enabled() {
 		enabled := true
 		config.CAPI = &kopscontrollerconfig.CAPIOptions{
@@ -698,6 +725,10 @@ func (tf *TemplateFunctions) KopsControllerConfig() (string, error) {
 		}
 	}
 
+	if cluster.Spec.Reconciler != nil {
+		config.Reconciler = &kopscontrollerconfig.ReconcilerOptions{}
+	}
+
 	{
 		certNames := []string{"kubelet", "kubelet-server"}
 		signingCAs := []string{fi.CertificateIDCA}
@@ -720,12 +751,31 @@ func (tf *TemplateFunctions) KopsControllerConfig() (string, error) {
 			CABasePath:            pkiDir,
 			SigningCAs:            signingCAs,
 			CertNames:             certNames,
+			AuditLog: &kopscontrollerconfig.AuditLogOptions{
+				Path:  "/var/log/kops-controller/audit.log",
+				Store: apiModel.BootstrapAuditStore(cluster),
+			},
 		}
 
 		if featureflag.Metal.Enabled() {
 			config.Server.PKI = &pkibootstrap.Options{}
 		}
 
+		config.Server.NodeConfigMaxUnavailable = apiModel.NodeConfigMaxUnavailable(cluster)
+
+		if tpm := cluster.Spec.TPMAttestation; tpm != nil {
+			config.Server.TPM = &tpmbootstrap.Options{
+				MaxTimeSkew: 300,
+				EKRootCAs:   tpm.EKRootCAs,
+			}
+			for _, pcr := range tpm.PCRs {
+				config.Server.TPM.PCRs = append(config.Server.TPM.PCRs, tpmbootstrap.PCR{
+					Index:  int(pcr.Index),
+					Values: pcr.Values,
+				})
+			}
+		}
+
 		switch cluster.GetCloudProvider() {
 		case kops.CloudProviderAWS:
 			nodesRoles := sets.String{}
@@ -796,6 +846,18 @@ func (tf *TemplateFunctions) KopsControllerConfig() (string, error) {
 
 	if cluster.Spec.IsKopsControllerIPAM() {
 		config.EnableCloudIPAM = true
+
+		// Bare-metal nodes are allocated pod CIDRs from the cluster's pod CIDR, unless their Host sets them.
+		if cluster.GetCloudProvider() == kops.CloudProviderMetal && cluster.Spec.Networking.PodCIDR != "" {
+			maskSize, err := nodeCIDRMaskSize(cluster)
+			if err != nil {
+				return "", err
+			}
+			config.MetalIPAM = &kopscontrollerconfig.MetalIPAMOptions{
+				PodCIDR:          cluster.Spec.Networking.PodCIDR,
+				NodeCIDRMaskSize: maskSize,
+			}
+		}
 	}
 
 	if cluster.UsesLegacyGossip() {
@@ -813,6 +875,244 @@ func (tf *TemplateFunctions) KopsControllerConfig() (string, error) {
 	return string(b), nil
 }
 
+// nodeCIDRMaskSize returns the prefix length of the pod CIDR allocated to each node.
+// As for kube-controller-manager, this defaults to /24 for IPv4, and for IPv6 to half of
+// the remaining bits of the cluster's pod CIDR, to a maximum of 16 bits.
+func nodeCIDRMaskSize(cluster *kops.Cluster) (int, error) {
+	if kcm := cluster.Spec.KubeControllerManager; kcm != nil && kcm.NodeCIDRMaskSize != nil {
+		return int(*kcm.NodeCIDRMaskSize), nil
+	}
+	_, podCIDR, err := net.ParseCIDR(cluster.Spec.Networking.PodCIDR)
+	if err != nil {
+		return 0, fmt.Errorf("parsing podCIDR %q: %w", cluster.Spec.Networking.PodCIDR, err)
+	}
+	ones, bits := podCIDR.Mask.Size()
+	if bits == 32 {
+		return max(ones, 24), nil
+	}
+	return ones + min((bits-ones)/2, 16), nil
+}
+
+// prometheusScrapeConfig is the subset of the Prometheus scrape_config we generate for the monitoring addon.
+type prometheusScrapeConfig struct {
+	JobName       string                   `json:"job_name"`
+	Scheme        string                   `json:"scheme,omitempty"`
+	MetricsPath   string                   `json:"metrics_path,omitempty"`
+	TLSConfig     *prometheusTLSConfig     `json:"tls_config,omitempty"`
+	StaticConfigs []prometheusStaticConfig `json:"static_configs"`
+}
+
+type prometheusTLSConfig struct {
+	CAFile   string `json:"ca_file"`
+	CertFile string `json:"cert_file"`
+	KeyFile  string `json:"key_file"`
+}
+
+type prometheusStaticConfig struct {
+	Targets []string `json:"targets"`
+}
+
+// MonitoringConfig returns the Prometheus agent configuration for the monitoring addon.
+// All the components are scraped on localhost, using the client certificates that nodeup
+// issues into secretsDir for the endpoints that require authentication.
+func (tf *TemplateFunctions) MonitoringConfig(secretsDir string) (string, error) {
+	cluster := tf.Cluster
+	monitoring := cluster.Spec.Monitoring
+	if monitoring == nil {
+		return "", fmt.Errorf("monitoring is not configured")
+	}
+
+	apiserverTLS := &prometheusTLSConfig{
+		CAFile:   path.Join(secretsDir, "ca.crt"),
+		CertFile: path.Join(secretsDir, "client.crt"),
+		KeyFile:  path.Join(secretsDir, "client.key"),
+	}
+	etcdTLS := &prometheusTLSConfig{
+		CAFile:   path.Join(secretsDir, "etcd-ca.crt"),
+		CertFile: path.Join(secretsDir, "etcd-client.crt"),
+		KeyFile:  path.Join(secretsDir, "etcd-client.key"),
+	}
+
+	scrapeConfigs := []prometheusScrapeConfig{
+		{
+			JobName:       "kube-apiserver",
+			Scheme:        "https",
+			TLSConfig:     apiserverTLS,
+			StaticConfigs: []prometheusStaticConfig{{Targets: []string{fmt.Sprintf("127.0.0.1:%d", wellknownports.KubeAPIServer)}}},
+		},
+		{
+			JobName:       "kube-apiserver-healthcheck",
+			MetricsPath:   "/.kube-apiserver-healthcheck/metrics",
+			StaticConfigs: []prometheusStaticConfig{{Targets: []string{fmt.Sprintf("127.0.0.1:%d", wellknownports.KubeAPIServerHealthCheck)}}},
+		},
+	}
+
+	for _, etcdCluster := range cluster.Spec.EtcdClusters {
+		scrapeConfig := prometheusScrapeConfig{
+			JobName: "etcd-" + etcdCluster.Name,
+		}
+		if etcdCluster.Manager != nil && len(etcdCluster.Manager.ListenMetricsURLs) > 0 {
+			// Prefer the dedicated metrics listeners, which serve plain http
+			for _, s := range etcdCluster.Manager.ListenMetricsURLs {
+				u, err := url.Parse(s)
+				if err != nil {
+					return "", fmt.Errorf("parsing etcd metrics URL %q: %w", s, err)
+				}
+				scrapeConfig.Scheme = u.Scheme
+				host := u.Hostname()
+				if host == "0.0.0.0" || host == "" {
+					host = "127.0.0.1"
+				}
+				scrapeConfig.StaticConfigs = append(scrapeConfig.StaticConfigs, prometheusStaticConfig{Targets: []string{net.JoinHostPort(host, u.Port())}})
+			}
+		} else if etcdCluster.Name == "cilium" {
+			// The cilium etcd cluster uses its own client CA, so we only scrape it through its metrics listeners
+			continue
+		} else {
+			ports, err := etcdmanager.PortsForCluster(etcdCluster)
+			if err != nil {
+				return "", err
+			}
+			scrapeConfig.Scheme = "https"
+			scrapeConfig.TLSConfig = etcdTLS
+			scrapeConfig.StaticConfigs = []prometheusStaticConfig{{Targets: []string{fmt.Sprintf("127.0.0.1:%d", ports.ClientPort)}}}
+		}
+		scrapeConfigs = append(scrapeConfigs, scrapeConfig)
+	}
+
+	scrapeConfigs = append(scrapeConfigs, prometheusScrapeConfig{
+		JobName:       "kops-controller",
+		StaticConfigs: []prometheusStaticConfig{{Targets: []string{fmt.Sprintf("127.0.0.1:%d", wellknownports.KopsControllerMetrics)}}},
+	})
+
+	if usesDNSController(cluster) {
+		scrapeConfigs = append(scrapeConfigs, prometheusScrapeConfig{
+			JobName:       "dns-controller",
+			StaticConfigs: []prometheusStaticConfig{{Targets: []string{fmt.Sprintf("127.0.0.1:%d", wellknownports.DNSControllerMetrics)}}},
+		})
+	}
+
+	scrapeInterval := "30s"
+	if monitoring.ScrapeInterval != nil {
+		scrapeInterval = monitoring.ScrapeInterval.Duration.String()
+	}
+
+	config := map[string]interface{}{
+		"global": map[string]interface{}{
+			"scrape_interval": scrapeInterval,
+			"external_labels": map[string]string{
+				"cluster": cluster.Name,
+				// Expanded by prometheus from the pod environment
+				"node": "${NODE_NAME}",
+			},
+		},
+		"scrape_configs": scrapeConfigs,
+		"remote_write": []map[string]string{
+			{"url": monitoring.RemoteWriteURL},
+		},
+	}
+
+	// To avoid indentation problems, we marshal as json.  json is a subset of yaml
+	b, err := json.Marshal(config)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize monitoring config: %w", err)
+	}
+
+	return string(b), nil
+}
+
+// MonitoringTarget is a kOps-managed component that is scraped through a ServiceMonitor.
+type MonitoringTarget struct {
+	// Name is the name of the Service and ServiceMonitor for the component.
+	Name string
+	// App is the k8s-app label of the component's pods.
+	App string
+	// Port is the port that serves the metrics.
+	Port int
+	// Scheme is the scheme of the metrics endpoint.
+	Scheme string
+	// Path is the path of the metrics endpoint.
+	Path string
+}
+
+// MonitoringTargets returns the components that the monitoring ServiceMonitors scrape on their node addresses.
+// kube-apiserver is scraped through the kubernetes Service instead, and etcd only through metrics listeners
+// on non-loopback addresses, because its client port requires an etcd client certificate.
+func (tf *TemplateFunctions) MonitoringTargets() ([]MonitoringTarget, error) {
+	cluster := tf.Cluster
+
+	targets := []MonitoringTarget{
+		{
+			Name:   "kube-apiserver-healthcheck",
+			App:    "kube-apiserver",
+			Port:   wellknownports.KubeAPIServerHealthCheck,
+			Scheme: "http",
+			Path:   "/.kube-apiserver-healthcheck/metrics",
+		},
+	}
+
+	for _, etcdCluster := range cluster.Spec.EtcdClusters {
+		if etcdCluster.Manager == nil {
+			continue
+		}
+		for _, s := range etcdCluster.Manager.ListenMetricsURLs {
+			u, err := url.Parse(s)
+			if err != nil {
+				return nil, fmt.Errorf("parsing etcd metrics URL %q: %w", s, err)
+			}
+			if ip := net.ParseIP(u.Hostname()); u.Hostname() == "localhost" || (ip != nil && ip.IsLoopback()) {
+				continue
+			}
+			port, err := strconv.Atoi(u.Port())
+			if err != nil {
+				return nil, fmt.Errorf("parsing port of etcd metrics URL %q: %w", s, err)
+			}
+			targets = append(targets, MonitoringTarget{
+				Name:   "etcd-" + etcdCluster.Name,
+				App:    "etcd-manager-" + etcdCluster.Name,
+				Port:   port,
+				Scheme: u.Scheme,
+				Path:   "/metrics",
+			})
+			break
+		}
+	}
+
+	targets = append(targets, MonitoringTarget{
+		Name:   "kops-controller",
+		App:    "kops-controller",
+		Port:   wellknownports.KopsControllerMetrics,
+		Scheme: "http",
+		Path:   "/metrics",
+	})
+
+	if usesDNSController(cluster) {
+		targets = append(targets, MonitoringTarget{
+			Name:   "dns-controller",
+			App:    "dns-controller",
+			Port:   wellknownports.DNSControllerMetrics,
+			Scheme: "http",
+			Path:   "/metrics",
+		})
+	}
+
+	return targets, nil
+}
+
+// metricsListenAddress returns the address a kOps-managed component serves its metrics on.
+// The monitoring agent scrapes localhost; ServiceMonitors need the node address.
+func metricsListenAddress(cluster *kops.Cluster, port int) string {
+	if apiModel.UseMonitoringServiceMonitors(cluster) {
+		return fmt.Sprintf(":%d", port)
+	}
+	return fmt.Sprintf("127.0.0.1:%d", port)
+}
+
+// usesDNSController is true if dns-controller manages the cluster's DNS records.
+func usesDNSController(cluster *kops.Cluster) bool {
+	return !cluster.UsesNoneDNS() && (cluster.Spec.ExternalDNS == nil || cluster.Spec.ExternalDNS.Provider == kops.ExternalDNSProviderDNSController)
+}
+
 // KopsControllerArgv returns the args to kops-controller
 func (tf *TemplateFunctions) KopsControllerArgv() ([]string, error) {
 	var argv []string
@@ -313,3 +313,64 @@ func TestKopsFeatureEnabled(t *testing.T) {
 		})
 	}
 }
+
+func TestNodeCIDRMaskSize(t *testing.T) {
+	tests := []struct {
+		podCIDR          string
+		nodeCIDRMaskSize *int32
+		expected         int
+	}{
+		{podCIDR: "100.96.0.0/11", expected: 24},
+		{podCIDR: "10.0.0.0/26", expected: 26},
+		{podCIDR: "fd00:10:96::/64", expected: 80},
+		{podCIDR: "fd00:10::/48", expected: 64},
+		{podCIDR: "fd00:10:96::/64", nodeCIDRMaskSize: fi.PtrTo(int32(112)), expected: 112},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.podCIDR, func(t *testing.T) {
+			cluster := &kops.Cluster{}
+			cluster.Spec.Networking.PodCIDR = tc.podCIDR
+			if tc.nodeCIDRMaskSize != nil {
+				cluster.Spec.KubeControllerManager = &kops.KubeControllerManagerConfig{NodeCIDRMaskSize: tc.nodeCIDRMaskSize}
+			}
+			actual, err := nodeCIDRMaskSize(cluster)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if actual != tc.expected {
+				t.Errorf("expected /%d, got /%d", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestMonitoringTargets(t *testing.T) {
+	cluster := &kops.Cluster{}
+	cluster.Spec.ExternalDNS = &kops.ExternalDNSConfig{Provider: kops.ExternalDNSProviderExternalDNS}
+	cluster.Spec.EtcdClusters = []kops.EtcdClusterSpec{
+		{Name: "main", Manager: &kops.EtcdManagerSpec{ListenMetricsURLs: []string{"http://localhost:8081", "http://0.0.0.0:8081"}}},
+		{Name: "events", Manager: &kops.EtcdManagerSpec{ListenMetricsURLs: []string{"http://127.0.0.1:8082"}}},
+		{Name: "cilium"},
+	}
+
+	tf := &TemplateFunctions{}
+	tf.Cluster = cluster
+	targets, err := tf.MonitoringTargets()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var actual []string
+	for _, target := range targets {
+		actual = append(actual, fmt.Sprintf("%s=%s:%d", target.Name, target.App, target.Port))
+	}
+	expected := []string{
+		"kube-apiserver-healthcheck=kube-apiserver:3990",
+		"etcd-main=etcd-manager-main:8081",
+		"kops-controller=kops-controller:3985",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
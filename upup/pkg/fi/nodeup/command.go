@@ -77,6 +77,11 @@ type NodeUpCommand struct {
 	CacheDir       string
 	ConfigLocation string
 	Target         string
+
+	// NodeConfig is the node configuration to apply, if it was already obtained from kops-controller.
+	NodeConfig *nodeup.NodeConfig
+	// Live only applies the tasks that are safe on a running node, for the node configuration agent.
+	Live bool
 }
 
 // Run is responsible for perform the nodeup process
@@ -106,8 +111,10 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 	if err != nil {
 		return err
 	}
-	if err = seedRNG(ctx, &bootConfig, region); err != nil {
-		return err
+	if !c.Live {
+		if err = seedRNG(ctx, &bootConfig, region); err != nil {
+			return err
+		}
 	}
 
 	var configBase vfs.Path
@@ -115,7 +122,9 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 	// If we're using a config server instead of vfs, nodeConfig will hold our configuration
 	var nodeConfig *nodeup.NodeConfig
 
-	if bootConfig.ConfigServer != nil && len(bootConfig.ConfigServer.Servers) > 0 {
+	if c.NodeConfig != nil {
+		nodeConfig = c.NodeConfig
+	} else if bootConfig.ConfigServer != nil && len(bootConfig.ConfigServer.Servers) > 0 {
 		response, err := getNodeConfigFromServers(ctx, &bootConfig, region)
 		if err != nil {
 			return fmt.Errorf("failed to get node config from server: %w", err)
@@ -159,7 +168,9 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 		return fmt.Errorf("no instance group defined in nodeup config")
 	}
 
-	if bootConfig.NodeupConfigHash != "" {
+	// The hash in the boot configuration is for the configuration the node was created with,
+	// whereas the node configuration agent applies later changes.
+	if bootConfig.NodeupConfigHash != "" && !c.Live {
 		if want, got := bootConfig.NodeupConfigHash, base64.StdEncoding.EncodeToString(nodeupConfigHash[:]); got != want {
 			return fmt.Errorf("nodeup config hash mismatch (was %q, expected %q)", got, want)
 		}
@@ -221,7 +232,7 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 			return fmt.Errorf("error building secret store path: %v", err)
 		}
 
-		secretStore = secrets.NewVFSSecretStoreReader(p)
+		secretStore = secrets.NewVFSSecretStoreReader(p, nodeupConfig.ConfigStore.Encryption)
 		modelContext.SecretStore = secretStore
 	default:
 		return fmt.Errorf("SecretStore not set")
@@ -236,7 +247,7 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 			return fmt.Errorf("error building key store path: %v", err)
 		}
 
-		modelContext.KeyStore = fi.NewVFSKeystoreReader(p)
+		modelContext.KeyStore = fi.NewVFSKeystoreReader(p, nodeupConfig.ConfigStore.Encryption)
 		keyStore = modelContext.KeyStore
 	} else {
 		return fmt.Errorf("KeyStore not set")
@@ -320,6 +331,7 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 	loader.Builders = append(loader.Builders, &model.EtcdManagerTLSBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.KubeProxyBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.KopsControllerBuilder{NodeupModelContext: modelContext})
+	loader.Builders = append(loader.Builders, &model.MonitoringBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.WarmPoolBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.PrefixBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.NerdctlBuilder{NodeupModelContext: modelContext})
@@ -340,10 +352,14 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 		return fmt.Errorf("error building loader: %v", err)
 	}
 
-	for i, image := range nodeupConfig.Images[architecture] {
-		taskMap["LoadImage."+strconv.Itoa(i)] = &nodetasks.LoadImageTask{
-			Sources: image.Sources,
-			Hash:    image.Hash,
+	if c.Live {
+		taskMap = liveTasks(taskMap)
+	} else {
+		for i, image := range nodeupConfig.Images[architecture] {
+			taskMap["LoadImage."+strconv.Itoa(i)] = &nodetasks.LoadImageTask{
+				Sources: image.Sources,
+				Hash:    image.Hash,
+			}
 		}
 	}
 	// Protokube load image task is in ProtokubeBuilder
@@ -370,6 +386,10 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 
 	var options fi.RunTasksOptions
 	options.InitDefaults()
+	if c.Live {
+		// The node configuration agent tries again at its next poll.
+		options.MaxTaskDuration = 5 * time.Minute
+	}
 
 	err = context.RunTasks(options)
 	if err != nil {
@@ -381,7 +401,7 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 		klog.Exitf("error closing target: %v", err)
 	}
 
-	if nodeupConfig.EnableLifecycleHook {
+	if nodeupConfig.EnableLifecycleHook && !c.Live {
 		if bootConfig.CloudProvider == api.CloudProviderAWS {
 			err := completeWarmingLifecycleAction(ctx, cloud.(awsup.AWSCloud), modelContext)
 			if err != nil {
@@ -665,7 +685,7 @@ func getNodeConfigFromServers(ctx context.Context, bootConfig *nodeup.BootConfig
 		authenticator = a
 
 	case "metal":
-		a, err := pkibootstrap.NewAuthenticatorFromFile("/etc/kubernetes/kops/pki/machine/private.pem")
+		a, err := pkibootstrap.NewMachineAuthenticator()
 		if err != nil {
 			return nil, err
 		}
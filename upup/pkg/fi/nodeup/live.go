@@ -0,0 +1,55 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeup
+
+import (
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+// liveTasks returns the tasks that are safe to apply to a running node: files, and the services that use them,
+// which are restarted if their files changed. Files that depend on any other task, such as the certificates
+// obtained from kops-controller at bootstrap or the users created by packages, are left alone.
+func liveTasks(tasks map[string]fi.NodeupTask) map[string]fi.NodeupTask {
+	live := make(map[string]fi.NodeupTask)
+	for key, task := range tasks {
+		switch task.(type) {
+		case *nodetasks.File, *nodetasks.Service:
+			live[key] = task
+		}
+	}
+
+	// Services depend on everything, so we only check the dependencies of files;
+	// the services only see the remaining tasks when they are run.
+	dependencies := fi.FindTaskDependencies(tasks)
+	for removed := true; removed; {
+		removed = false
+		for key, task := range live {
+			if _, ok := task.(*nodetasks.File); !ok {
+				continue
+			}
+			for _, dependency := range dependencies[key] {
+				if _, found := live[dependency]; !found {
+					delete(live, key)
+					removed = true
+					break
+				}
+			}
+		}
+	}
+	return live
+}
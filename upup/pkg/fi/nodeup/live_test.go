@@ -0,0 +1,68 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeup
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+func TestLiveTasks(t *testing.T) {
+	tasks := map[string]fi.NodeupTask{
+		"Package/containerd": &nodetasks.Package{Name: "containerd"},
+		"UserTask/etcd":      &nodetasks.UserTask{Name: "etcd"},
+		"File/etc/kubernetes": &nodetasks.File{
+			Path: "/etc/kubernetes",
+			Type: nodetasks.FileType_Directory,
+		},
+		"File/etc/kubernetes/config": &nodetasks.File{
+			Path:     "/etc/kubernetes/config",
+			Contents: fi.NewStringResource("config"),
+			Type:     nodetasks.FileType_File,
+		},
+		// Depends on a task that is not safe to run live
+		"File/etc/kubernetes/etcd": &nodetasks.File{
+			Path:     "/etc/kubernetes/etcd",
+			Contents: fi.NewStringResource("etcd"),
+			Type:     nodetasks.FileType_File,
+			Owner:    fi.PtrTo("etcd"),
+		},
+		// Depends on a file that is not applied live
+		"File/etc/kubernetes/etcd-client": &nodetasks.File{
+			Path:       "/etc/kubernetes/etcd-client",
+			Contents:   fi.NewStringResource("etcd-client"),
+			Type:       nodetasks.FileType_File,
+			AfterFiles: []string{"/etc/kubernetes/etcd"},
+		},
+		"Service/kubelet.service": &nodetasks.Service{Name: "kubelet.service"},
+	}
+
+	live := liveTasks(tasks)
+
+	want := []string{
+		"File/etc/kubernetes",
+		"File/etc/kubernetes/config",
+		"Service/kubelet.service",
+	}
+	if got := sets.List(sets.KeySet(live)); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected live tasks; got %v, want %v", got, want)
+	}
+}
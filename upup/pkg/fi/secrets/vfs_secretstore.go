@@ -26,6 +26,7 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/acls"
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/envelope"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/util/pkg/vfs"
 )
@@ -40,7 +41,8 @@ var _ fi.SecretStore = &VFSSecretStore{}
 func NewVFSSecretStore(cluster *kops.Cluster, basedir vfs.Path) fi.SecretStore {
 	c := &VFSSecretStore{
 		VFSSecretStoreReader: VFSSecretStoreReader{
-			basedir: basedir,
+			basedir:    basedir,
+			encryption: fi.ConfigStoreEncryption(cluster),
 		},
 		cluster: cluster,
 	}
@@ -78,7 +80,7 @@ func (c *VFSSecretStore) MirrorTo(ctx context.Context, basedir vfs.Path) error {
 
 		klog.Infof("mirroring secret %s -> %s", name, p)
 
-		err = createSecret(ctx, secret, p, acl, true)
+		err = createSecret(ctx, c.encryption, secret, p, acl, true)
 		if err != nil {
 			return fmt.Errorf("error writing secret %q for mirror: %v", name, err)
 		}
@@ -129,7 +131,7 @@ func (c *VFSSecretStore) GetOrCreateSecret(ctx context.Context, id string, secre
 			return nil, false, err
 		}
 
-		err = createSecret(ctx, secret, p, acl, false)
+		err = createSecret(ctx, c.encryption, secret, p, acl, false)
 		if err != nil {
 			if os.IsExist(err) && i == 0 {
 				klog.Infof("Got already-exists error when writing secret; likely due to concurrent creation.  Will retry")
@@ -163,7 +165,7 @@ func (c *VFSSecretStore) ReplaceSecret(id string, secret *fi.Secret) (*fi.Secret
 		return nil, err
 	}
 
-	err = createSecret(ctx, secret, p, acl, true)
+	err = createSecret(ctx, c.encryption, secret, p, acl, true)
 	if err != nil {
 		return nil, fmt.Errorf("unable to write secret: %v", err)
 	}
@@ -177,12 +179,17 @@ func (c *VFSSecretStore) ReplaceSecret(id string, secret *fi.Secret) (*fi.Secret
 }
 
 // createSecret will create the Secret, overwriting an existing secret if replace is true
-func createSecret(ctx context.Context, s *fi.Secret, p vfs.Path, acl vfs.ACL, replace bool) error {
+func createSecret(ctx context.Context, encryption *kops.ConfigStoreEncryptionSpec, s *fi.Secret, p vfs.Path, acl vfs.ACL, replace bool) error {
 	data, err := json.Marshal(s)
 	if err != nil {
 		return fmt.Errorf("error serializing secret: %v", err)
 	}
 
+	data, err = envelope.Encrypt(ctx, encryption, p.Path(), data)
+	if err != nil {
+		return fmt.Errorf("error encrypting secret: %w", err)
+	}
+
 	rs := bytes.NewReader(data)
 	if replace {
 		return p.WriteFile(ctx, rs, acl)
@@ -22,19 +22,24 @@ import (
 	"fmt"
 	"os"
 
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/envelope"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/util/pkg/vfs"
 )
 
 type VFSSecretStoreReader struct {
 	basedir vfs.Path
+	// encryption is the envelope encryption of the secrets, if any.
+	encryption *kops.ConfigStoreEncryptionSpec
 }
 
 var _ fi.SecretStoreReader = &VFSSecretStoreReader{}
 
-func NewVFSSecretStoreReader(basedir vfs.Path) fi.SecretStoreReader {
+func NewVFSSecretStoreReader(basedir vfs.Path, encryption *kops.ConfigStoreEncryptionSpec) fi.SecretStoreReader {
 	c := &VFSSecretStoreReader{
-		basedir: basedir,
+		basedir:    basedir,
+		encryption: encryption,
 	}
 	return c
 }
@@ -80,6 +85,10 @@ func (c *VFSSecretStoreReader) loadSecret(ctx context.Context, p vfs.Path) (*fi.
 			return nil, nil
 		}
 	}
+	data, err = envelope.Decrypt(ctx, c.encryption, p.Path(), data)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting secret from %q: %w", p, err)
+	}
 	s := &fi.Secret{}
 	err = json.Unmarshal(data, s)
 	if err != nil {
@@ -30,6 +30,7 @@ import (
 	"k8s.io/kops/pkg/acls"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/apis/kops/v1alpha2"
+	"k8s.io/kops/pkg/envelope"
 	"k8s.io/kops/pkg/kopscodecs"
 	"k8s.io/kops/pkg/sshcredentials"
 	"k8s.io/kops/util/pkg/vfs"
@@ -48,7 +49,8 @@ var (
 func NewVFSCAStore(cluster *kops.Cluster, basedir vfs.Path) *VFSCAStore {
 	c := &VFSCAStore{
 		VFSKeystoreReader: VFSKeystoreReader{
-			basedir: basedir,
+			basedir:    basedir,
+			encryption: ConfigStoreEncryption(cluster),
 		},
 		cluster: cluster,
 	}
@@ -56,12 +58,21 @@ func NewVFSCAStore(cluster *kops.Cluster, basedir vfs.Path) *VFSCAStore {
 	return c
 }
 
+// ConfigStoreEncryption returns the envelope encryption configured for the secrets and private keys of the cluster, if any.
+func ConfigStoreEncryption(cluster *kops.Cluster) *kops.ConfigStoreEncryptionSpec {
+	if cluster == nil {
+		return nil
+	}
+	return cluster.Spec.ConfigStore.Encryption
+}
+
 // NewVFSSSHCredentialStore creates a SSHCredentialStore backed by VFS
 func NewVFSSSHCredentialStore(cluster *kops.Cluster, basedir vfs.Path) SSHCredentialStore {
 	// Note currently identical to NewVFSCAStore
 	c := &VFSCAStore{
 		VFSKeystoreReader: VFSKeystoreReader{
-			basedir: basedir,
+			basedir:    basedir,
+			encryption: ConfigStoreEncryption(cluster),
 		},
 		cluster: cluster,
 	}
@@ -132,6 +143,11 @@ func writeKeysetBundle(ctx context.Context, cluster *kops.Cluster, p vfs.Path, n
 		return err
 	}
 
+	objectData, err = envelope.Encrypt(ctx, ConfigStoreEncryption(cluster), p.Path(), objectData)
+	if err != nil {
+		return fmt.Errorf("error encrypting keyset %q: %w", name, err)
+	}
+
 	acl, err := acls.GetACL(ctx, p, cluster)
 	if err != nil {
 		return err
@@ -227,7 +243,7 @@ func (c *VFSCAStore) MirrorTo(ctx context.Context, basedir vfs.Path) error {
 
 // mirrorKeyset writes Keyset bundles for the certificates & privatekeys.
 func mirrorKeyset(ctx context.Context, cluster *kops.Cluster, basedir vfs.Path, name string, keyset *Keyset) error {
-	if err := writeKeysetBundle(ctx, cluster, basedir.Join("private"), name, keyset); err != nil {
+	if err := writeKeysetBundle(ctx, cluster, basedir.Join("private", name), name, keyset); err != nil {
 		return fmt.Errorf("writing private bundle: %v", err)
 	}
 
@@ -18,12 +18,20 @@ package fi
 
 import (
 	"context"
+	"crypto"
+	crand "crypto/rand"
+	"crypto/x509/pkix"
+	"encoding/base64"
 	"math/big"
 	"math/rand"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/envelope"
 	"k8s.io/kops/pkg/pki"
 	"k8s.io/kops/util/pkg/vfs"
 )
@@ -157,3 +165,244 @@ spec:
 		}
 	}
 }
+
+func TestVFSCAStoreEncryptedRoundTrip(t *testing.T) {
+	ctx := context.TODO()
+
+	vfs.Context.ResetMemfsContext(true)
+
+	basePath, err := vfs.Context.BuildVfsPath("memfs://tests")
+	if err != nil {
+		t.Fatalf("error building vfspath: %v", err)
+	}
+
+	key := make([]byte, 32)
+	if _, err := crand.Read(key); err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+	keyPath := filepath.Join(t.TempDir(), "state-store.key")
+	if err := os.WriteFile(keyPath, []byte(base64.StdEncoding.EncodeToString(key)), 0600); err != nil {
+		t.Fatalf("error writing key: %v", err)
+	}
+
+	cluster := &kops.Cluster{}
+	cluster.Spec.ConfigStore.Encryption = &kops.ConfigStoreEncryptionSpec{
+		Provider: envelope.ProviderLocal,
+		KeyID:    keyPath,
+	}
+	s := NewVFSCAStore(cluster, basePath)
+
+	cert, privateKey, _, err := pki.IssueCert(ctx, &pki.IssueCertRequest{
+		Type:    "ca",
+		Subject: pkix.Name{CommonName: "kubernetes-ca"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("error issuing CA: %v", err)
+	}
+	keyset, err := NewKeyset(cert, privateKey)
+	if err != nil {
+		t.Fatalf("error building keyset: %v", err)
+	}
+	if err := s.StoreKeyset(ctx, "kubernetes-ca", keyset); err != nil {
+		t.Fatalf("error from StoreKeyset: %v", err)
+	}
+
+	data, err := basePath.Join("private", "kubernetes-ca", "keyset.yaml").ReadFile(ctx)
+	if err != nil {
+		t.Fatalf("error reading keyset: %v", err)
+	}
+	if !envelope.IsEncrypted(data) || strings.Contains(string(data), "privateMaterial") {
+		t.Fatalf("keyset was not encrypted: %q", string(data))
+	}
+
+	// The reader does not decrypt without the encryption configuration.
+	if _, err := NewVFSKeystoreReader(basePath, nil).FindKeyset(ctx, "kubernetes-ca"); err == nil {
+		t.Fatalf("expected error reading encrypted keyset without the encryption configuration")
+	}
+
+	reader := NewVFSKeystoreReader(basePath, cluster.Spec.ConfigStore.Encryption)
+	roundTrip, err := reader.FindKeyset(ctx, "kubernetes-ca")
+	if err != nil {
+		t.Fatalf("error reading keyset: %v", err)
+	}
+	if roundTrip == nil || roundTrip.Primary == nil || roundTrip.Primary.PrivateKey == nil {
+		t.Fatalf("private key did not round-trip")
+	}
+	expected, _ := privateKey.AsString()
+	actual, _ := roundTrip.Primary.PrivateKey.AsString()
+	if actual != expected {
+		t.Errorf("unexpected round-tripped private key")
+	}
+}
+
+func TestVFSCAStoreMirrorTo(t *testing.T) {
+	ctx := context.TODO()
+
+	vfs.Context.ResetMemfsContext(true)
+
+	basePath, err := vfs.Context.BuildVfsPath("memfs://tests")
+	if err != nil {
+		t.Fatalf("error building vfspath: %v", err)
+	}
+	mirrorPath, err := vfs.Context.BuildVfsPath("memfs://mirror/pki")
+	if err != nil {
+		t.Fatalf("error building vfspath: %v", err)
+	}
+
+	s := NewVFSCAStore(&kops.Cluster{}, basePath)
+
+	for _, name := range []string{"kubernetes-ca", "etcd-clients-ca"} {
+		cert, privateKey, _, err := pki.IssueCert(ctx, &pki.IssueCertRequest{
+			Type:    "ca",
+			Subject: pkix.Name{CommonName: name},
+		}, nil)
+		if err != nil {
+			t.Fatalf("error issuing CA: %v", err)
+		}
+		keyset, err := NewKeyset(cert, privateKey)
+		if err != nil {
+			t.Fatalf("error building keyset: %v", err)
+		}
+		if err := s.StoreKeyset(ctx, name, keyset); err != nil {
+			t.Fatalf("error from StoreKeyset: %v", err)
+		}
+	}
+
+	if err := s.MirrorTo(ctx, mirrorPath); err != nil {
+		t.Fatalf("error from MirrorTo: %v", err)
+	}
+
+	// Each keyset is mirrored to its own private directory, where nodes read it
+	reader := NewVFSKeystoreReader(mirrorPath, nil)
+	for _, name := range []string{"kubernetes-ca", "etcd-clients-ca"} {
+		cert, privateKey, err := reader.FindPrimaryKeypair(ctx, name)
+		if err != nil {
+			t.Fatalf("error reading mirrored keyset %q: %v", name, err)
+		}
+		if cert == nil || privateKey == nil {
+			t.Fatalf("keyset %q was not mirrored", name)
+		}
+		if cert.Subject.CommonName != name {
+			t.Errorf("expected mirrored keyset %q, got %q", name, cert.Subject.CommonName)
+		}
+	}
+}
+
+func TestVFSCAStoreMirrorToSecretManager(t *testing.T) {
+	ctx := context.TODO()
+
+	vfs.Context.ResetMemfsContext(true)
+	vfs.Context.SetSecretManager("awssm", "us-test-1", vfs.NewMemSecretManager())
+
+	basePath, err := vfs.Context.BuildVfsPath("memfs://tests")
+	if err != nil {
+		t.Fatalf("error building vfspath: %v", err)
+	}
+	mirrorPath, err := vfs.Context.BuildVfsPath("awssm://us-test-1/kops/tests/pki")
+	if err != nil {
+		t.Fatalf("error building vfspath: %v", err)
+	}
+
+	s := NewVFSCAStore(&kops.Cluster{}, basePath)
+
+	cert, privateKey, _, err := pki.IssueCert(ctx, &pki.IssueCertRequest{
+		Type:    "ca",
+		Subject: pkix.Name{CommonName: "kubernetes-ca"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("error issuing CA: %v", err)
+	}
+	keyset, err := NewKeyset(cert, privateKey)
+	if err != nil {
+		t.Fatalf("error building keyset: %v", err)
+	}
+	if err := s.StoreKeyset(ctx, "kubernetes-ca", keyset); err != nil {
+		t.Fatalf("error from StoreKeyset: %v", err)
+	}
+
+	if err := s.MirrorTo(ctx, mirrorPath); err != nil {
+		t.Fatalf("error from MirrorTo: %v", err)
+	}
+
+	reader := NewVFSKeystoreReader(mirrorPath, nil)
+	roundTrip, err := reader.FindKeyset(ctx, "kubernetes-ca")
+	if err != nil {
+		t.Fatalf("error reading mirrored keyset: %v", err)
+	}
+	if roundTrip == nil || roundTrip.Primary == nil || roundTrip.Primary.PrivateKey == nil {
+		t.Fatalf("private key was not mirrored")
+	}
+	expected, _ := privateKey.AsString()
+	actual, _ := roundTrip.Primary.PrivateKey.AsString()
+	if actual != expected {
+		t.Errorf("unexpected mirrored private key")
+	}
+}
+
+func TestVFSCAStoreKeyReferenceRoundTrip(t *testing.T) {
+	ctx := context.TODO()
+
+	caKey, err := pki.GeneratePrivateKey()
+	if err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+	pki.RegisterSignerProvider("test-kms", func(ctx context.Context, uri string) (crypto.Signer, error) {
+		return caKey.Key, nil
+	})
+
+	vfs.Context.ResetMemfsContext(true)
+	basePath, err := vfs.Context.BuildVfsPath("memfs://tests")
+	if err != nil {
+		t.Fatalf("error building vfspath: %v", err)
+	}
+	s := NewVFSCAStore(&kops.Cluster{}, basePath)
+
+	privateKey, err := pki.NewPrivateKeyReference("test-kms://kubernetes-ca")
+	if err != nil {
+		t.Fatalf("error building key reference: %v", err)
+	}
+	cert, _, _, err := pki.IssueCert(ctx, &pki.IssueCertRequest{
+		Type:       "ca",
+		Subject:    pkix.Name{CommonName: "kubernetes-ca"},
+		PrivateKey: privateKey,
+	}, nil)
+	if err != nil {
+		t.Fatalf("error issuing CA: %v", err)
+	}
+	keyset, err := NewKeyset(cert, privateKey)
+	if err != nil {
+		t.Fatalf("error building keyset: %v", err)
+	}
+	if err := s.StoreKeyset(ctx, "kubernetes-ca", keyset); err != nil {
+		t.Fatalf("error from StoreKeyset: %v", err)
+	}
+
+	// Only the reference is stored, not the key material.
+	b, err := basePath.Join("private", "kubernetes-ca", "keyset.yaml").ReadFile(ctx)
+	if err != nil {
+		t.Fatalf("error reading keyset: %v", err)
+	}
+	if strings.Contains(string(b), "RSA PRIVATE KEY") {
+		t.Errorf("key material was stored in the keyset: %s", b)
+	}
+
+	roundTrip, err := s.FindKeyset(ctx, "kubernetes-ca")
+	if err != nil {
+		t.Fatalf("error from FindKeyset: %v", err)
+	}
+	if ref := roundTrip.Primary.PrivateKey.Reference(); ref != "test-kms://kubernetes-ca" {
+		t.Fatalf("unexpected key reference %q", ref)
+	}
+
+	issued, _, _, err := pki.IssueCert(ctx, &pki.IssueCertRequest{
+		Signer:  "kubernetes-ca",
+		Type:    "client",
+		Subject: pkix.Name{CommonName: "client"},
+	}, s)
+	if err != nil {
+		t.Fatalf("error issuing certificate: %v", err)
+	}
+	if err := issued.Certificate.CheckSignatureFrom(cert.Certificate); err != nil {
+		t.Errorf("certificate not signed by the referenced key: %v", err)
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/apis/kops/v1alpha2"
+	"k8s.io/kops/pkg/envelope"
 	"k8s.io/kops/pkg/kopscodecs"
 	"k8s.io/kops/pkg/pki"
 	"k8s.io/kops/util/pkg/vfs"
@@ -32,6 +33,8 @@ import (
 
 type VFSKeystoreReader struct {
 	basedir vfs.Path
+	// encryption is the envelope encryption of the private keys, if any.
+	encryption *kops.ConfigStoreEncryptionSpec
 
 	mutex    sync.Mutex
 	cachedCA *Keyset
@@ -39,9 +42,10 @@ type VFSKeystoreReader struct {
 
 var _ KeystoreReader = &VFSKeystoreReader{}
 
-func NewVFSKeystoreReader(basedir vfs.Path) *VFSKeystoreReader {
+func NewVFSKeystoreReader(basedir vfs.Path, encryption *kops.ConfigStoreEncryptionSpec) *VFSKeystoreReader {
 	k := &VFSKeystoreReader{
-		basedir: basedir,
+		basedir:    basedir,
+		encryption: encryption,
 	}
 
 	return k
@@ -88,6 +92,11 @@ func (c *VFSKeystoreReader) loadKeyset(ctx context.Context, p vfs.Path) (*Keyset
 		return nil, fmt.Errorf("unable to read bundle %q: %v", p, err)
 	}
 
+	data, err = envelope.Decrypt(ctx, c.encryption, bundlePath.Path(), data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt bundle %q: %w", p, err)
+	}
+
 	o, legacyFormat, err := c.parseKeysetYaml(data)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing bundle %q: %v", p, err)
@@ -130,6 +139,10 @@ func (c *VFSKeystoreReader) FindPrimaryKeypair(ctx context.Context, name string)
 
 func (c *VFSKeystoreReader) FindKeyset(ctx context.Context, id string) (*Keyset, error) {
 	keys, err := c.findPrivateKeyset(ctx, id)
+	if err != nil && !os.IsNotExist(err) {
+		// A keyset that cannot be read, for example because it cannot be decrypted, must not be taken as missing.
+		return nil, err
+	}
 	if keys == nil || os.IsNotExist(err) {
 		if legacyId := legacyKeysetMappings[id]; legacyId != "" {
 			keys, err = c.findPrivateKeyset(ctx, legacyId)
@@ -0,0 +1,209 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfs
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+)
+
+// awsSecretManager stores secrets in AWS Secrets Manager, named by their key.
+type awsSecretManager struct {
+	region      string
+	endpoint    string
+	credentials aws.CredentialsProvider
+	signer      *v4.Signer
+	client      *http.Client
+}
+
+var _ SecretManager = &awsSecretManager{}
+
+func newAWSSecretManager(ctx context.Context, region string) (*awsSecretManager, error) {
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(region))
+	if err != nil {
+		return nil, fmt.Errorf("error loading AWS config: %w", err)
+	}
+
+	endpoint := "https://secretsmanager." + region + ".amazonaws.com/"
+	if strings.HasPrefix(region, "cn-") {
+		endpoint = "https://secretsmanager." + region + ".amazonaws.com.cn/"
+	}
+
+	return &awsSecretManager{
+		region:      region,
+		endpoint:    endpoint,
+		credentials: cfg.Credentials,
+		signer:      v4.NewSigner(),
+		client:      http.DefaultClient,
+	}, nil
+}
+
+// awsSecretManagerError is the error returned by the Secrets Manager API.
+type awsSecretManagerError struct {
+	Type    string `json:"__type"`
+	Message string `json:"message"`
+}
+
+func (e *awsSecretManagerError) Error() string {
+	return e.Type + ": " + e.Message
+}
+
+func (m *awsSecretManager) do(ctx context.Context, operation string, request any, response any) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, m.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpRequest.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	httpRequest.Header.Set("X-Amz-Target", "secretsmanager."+operation)
+
+	credentials, err := m.credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting AWS credentials: %w", err)
+	}
+	payloadHash := sha256.Sum256(body)
+	if err := m.signer.SignHTTP(ctx, credentials, httpRequest, hex.EncodeToString(payloadHash[:]), "secretsmanager", m.region, time.Now()); err != nil {
+		return fmt.Errorf("error signing request: %w", err)
+	}
+
+	httpResponse, err := m.client.Do(httpRequest)
+	if err != nil {
+		return err
+	}
+	defer httpResponse.Body.Close()
+
+	b, err := io.ReadAll(httpResponse.Body)
+	if err != nil {
+		return err
+	}
+	if httpResponse.StatusCode != http.StatusOK {
+		apiError := &awsSecretManagerError{}
+		if err := json.Unmarshal(b, apiError); err != nil || apiError.Type == "" {
+			return fmt.Errorf("unexpected status %d from secrets manager: %s", httpResponse.StatusCode, string(b))
+		}
+		// The type may be qualified with a namespace
+		apiError.Type = apiError.Type[strings.LastIndex(apiError.Type, "#")+1:]
+		return apiError
+	}
+	if response != nil {
+		if err := json.Unmarshal(b, response); err != nil {
+			return fmt.Errorf("error parsing secrets manager response: %w", err)
+		}
+	}
+	return nil
+}
+
+func isAWSSecretManagerError(err error, errorType string) bool {
+	apiError, ok := err.(*awsSecretManagerError)
+	return ok && apiError.Type == errorType
+}
+
+func (m *awsSecretManager) Get(ctx context.Context, key string) ([]byte, error) {
+	var response struct {
+		SecretBinary []byte
+	}
+	if err := m.do(ctx, "GetSecretValue", map[string]any{"SecretId": key}, &response); err != nil {
+		if isAWSSecretManagerError(err, "ResourceNotFoundException") {
+			return nil, os.ErrNotExist
+		}
+		return nil, err
+	}
+	return response.SecretBinary, nil
+}
+
+func (m *awsSecretManager) Put(ctx context.Context, key string, data []byte, create bool) error {
+	if !create {
+		err := m.do(ctx, "PutSecretValue", map[string]any{"SecretId": key, "SecretBinary": data}, nil)
+		if err == nil || !isAWSSecretManagerError(err, "ResourceNotFoundException") {
+			return err
+		}
+	}
+
+	request := map[string]any{
+		"Name":         key,
+		"SecretBinary": data,
+	}
+	if err := m.do(ctx, "CreateSecret", request, nil); err != nil {
+		if create && isAWSSecretManagerError(err, "ResourceExistsException") {
+			return os.ErrExist
+		}
+		return err
+	}
+	return nil
+}
+
+func (m *awsSecretManager) Delete(ctx context.Context, key string) error {
+	request := map[string]any{
+		"SecretId":                   key,
+		"ForceDeleteWithoutRecovery": true,
+	}
+	if err := m.do(ctx, "DeleteSecret", request, nil); err != nil {
+		if isAWSSecretManagerError(err, "ResourceNotFoundException") {
+			return os.ErrNotExist
+		}
+		return err
+	}
+	return nil
+}
+
+func (m *awsSecretManager) List(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	nextToken := ""
+	for {
+		request := map[string]any{
+			// The name filter matches the prefix of the name
+			"Filters": []map[string]any{{"Key": "name", "Values": []string{prefix}}},
+		}
+		if nextToken != "" {
+			request["NextToken"] = nextToken
+		}
+		var response struct {
+			SecretList []struct {
+				Name string
+			}
+			NextToken string
+		}
+		if err := m.do(ctx, "ListSecrets", request, &response); err != nil {
+			return nil, err
+		}
+		for _, secret := range response.SecretList {
+			if strings.HasPrefix(secret.Name, prefix) {
+				keys = append(keys, secret.Name)
+			}
+		}
+		if response.NextToken == "" {
+			return keys, nil
+		}
+		nextToken = response.NextToken
+	}
+}
@@ -27,8 +27,10 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
 	"k8s.io/klog/v2"
 	"k8s.io/kops/util/pkg/hashing"
@@ -43,8 +45,9 @@ type AzureBlobPath struct {
 }
 
 var (
-	_ Path    = &AzureBlobPath{}
-	_ HasHash = &AzureBlobPath{}
+	_ Path       = &AzureBlobPath{}
+	_ HasHash    = &AzureBlobPath{}
+	_ HasVersion = &AzureBlobPath{}
 )
 
 // NewAzureBlobPath returns a new AzureBlobPath.
@@ -103,29 +106,39 @@ func (p *AzureBlobPath) Join(relativePath ...string) Path {
 
 // ReadFile returns the content of the blob.
 func (p *AzureBlobPath) ReadFile(ctx context.Context) ([]byte, error) {
+	b, _, err := p.ReadFileWithVersion(ctx)
+	return b, err
+}
+
+// ReadFileWithVersion implements HasVersion::ReadFileWithVersion, returning the ETag as the version
+func (p *AzureBlobPath) ReadFileWithVersion(ctx context.Context) ([]byte, string, error) {
 	klog.V(8).Infof("Reading file: %s - %s", p.container, p.key)
 
 	client, err := p.getClient(ctx)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	get, err := client.DownloadStream(ctx, p.container, p.key, nil)
 	if err != nil {
 		if bloberror.HasCode(err, bloberror.ContainerNotFound) || bloberror.HasCode(err, bloberror.BlobNotFound) {
-			return nil, os.ErrNotExist
+			return nil, "", os.ErrNotExist
 		}
-		return nil, err
+		return nil, "", err
 	}
 
 	b := &bytes.Buffer{}
 	retryReader := get.NewRetryReader(ctx, &azblob.RetryReaderOptions{})
 	_, err = b.ReadFrom(retryReader)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
-	return b.Bytes(), nil
+	var version string
+	if get.ETag != nil {
+		version = string(*get.ETag)
+	}
+	return b.Bytes(), version, nil
 }
 
 // WriteTo writes the content of the blob to the writer.
@@ -171,20 +184,48 @@ func (p *AzureBlobPath) CreateFile(ctx context.Context, data io.ReadSeeker, acl
 
 // WriteFile writes the blob to the reader.
 func (p *AzureBlobPath) WriteFile(ctx context.Context, data io.ReadSeeker, acl ACL) error {
+	_, err := p.upload(ctx, data, nil)
+	return err
+}
+
+// WriteFileIfVersion implements HasVersion::WriteFileIfVersion, using a condition on the ETag
+func (p *AzureBlobPath) WriteFileIfVersion(ctx context.Context, data io.ReadSeeker, acl ACL, version string) (string, error) {
+	conditions := &blob.ModifiedAccessConditions{}
+	if version == "" {
+		conditions.IfNoneMatch = to.Ptr(azcore.ETagAny)
+	} else {
+		conditions.IfMatch = to.Ptr(azcore.ETag(version))
+	}
+
+	resp, err := p.upload(ctx, data, &azblob.UploadStreamOptions{
+		AccessConditions: &blob.AccessConditions{ModifiedAccessConditions: conditions},
+	})
+	if err != nil {
+		if bloberror.HasCode(err, bloberror.ConditionNotMet, bloberror.BlobAlreadyExists) {
+			return "", fmt.Errorf("error writing %s: %w", p.Path(), ErrVersionConflict)
+		}
+		return "", err
+	}
+	if resp.ETag == nil {
+		return "", nil
+	}
+	return string(*resp.ETag), nil
+}
+
+func (p *AzureBlobPath) upload(ctx context.Context, data io.ReadSeeker, options *azblob.UploadStreamOptions) (azblob.UploadStreamResponse, error) {
 	klog.V(8).Infof("Writing file: %s - %s", p.container, p.key)
 
 	client, err := p.getClient(ctx)
 	if err != nil {
-		return err
+		return azblob.UploadStreamResponse{}, err
 	}
 
 	_, err = client.CreateContainer(ctx, p.container, nil)
 	if err != nil && !bloberror.HasCode(err, bloberror.ContainerAlreadyExists) {
-		return err
+		return azblob.UploadStreamResponse{}, err
 	}
 
-	_, err = client.UploadStream(ctx, p.container, p.key, data, nil)
-	return err
+	return client.UploadStream(ctx, p.container, p.key, data, options)
 }
 
 // Remove deletes the blob.
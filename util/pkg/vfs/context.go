@@ -59,6 +59,9 @@ type vfsContextState struct {
 	swiftClient *gophercloud.ServiceClient
 
 	azureClient *azblob.Client
+
+	// secretManagers holds the secret manager clients, keyed by scheme and host
+	secretManagers map[string]SecretManager
 }
 
 // Context holds the global VFS state.
@@ -213,6 +216,10 @@ func (c *VFSContext) BuildVfsPath(p string) (Path, error) {
 		return c.buildSCWPath(p)
 	}
 
+	if strings.HasPrefix(p, "vault://") || strings.HasPrefix(p, "awssm://") || strings.HasPrefix(p, "gsm://") {
+		return c.buildSecretManagerPath(p)
+	}
+
 	return nil, fmt.Errorf("unknown / unhandled path type: %q", p)
 }
 
@@ -396,6 +403,72 @@ func (c *VFSContext) buildKubernetesPath(p string) (*KubernetesPath, error) {
 	return k8sPath, nil
 }
 
+func (c *VFSContext) buildSecretManagerPath(p string) (*SecretManagerPath, error) {
+	u, err := url.Parse(p)
+	if err != nil {
+		return nil, fmt.Errorf("invalid secret manager path: %q", p)
+	}
+
+	host := strings.TrimSuffix(u.Host, "/")
+	if host == "" {
+		return nil, fmt.Errorf("invalid secret manager path: %q", p)
+	}
+	if u.Scheme == "vault" && len(strings.Split(strings.Trim(u.Path, "/"), "/")) < 2 {
+		return nil, fmt.Errorf("vault path must include the secrets engine mount and a path within it: %q", p)
+	}
+
+	scheme := u.Scheme
+	return newSecretManagerPath(scheme, host, u.Path, func(ctx context.Context) (SecretManager, error) {
+		return c.getSecretManager(ctx, scheme, host)
+	}), nil
+}
+
+// getSecretManager returns the client for the secret manager, caching it for future calls
+func (c *VFSContext) getSecretManager(ctx context.Context, scheme string, host string) (SecretManager, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	id := scheme + "://" + host
+	if sm := c.secretManagers[id]; sm != nil {
+		return sm, nil
+	}
+
+	var sm SecretManager
+	var err error
+	switch scheme {
+	case "vault":
+		sm, err = newVaultSecretManager(host)
+	case "awssm":
+		sm, err = newAWSSecretManager(ctx, host)
+	case "gsm":
+		sm, err = newGCPSecretManager(ctx, host)
+	default:
+		err = fmt.Errorf("unknown secret manager %q", scheme)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if c.secretManagers == nil {
+		c.secretManagers = make(map[string]SecretManager)
+	}
+	c.secretManagers[id] = sm
+	return sm, nil
+}
+
+// SetSecretManager overrides the client for the secret manager paths with the scheme and host, for tests.
+func (c *VFSContext) SetSecretManager(scheme string, host string, sm SecretManager) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	secretManagers := make(map[string]SecretManager)
+	for k, v := range c.secretManagers {
+		secretManagers[k] = v
+	}
+	secretManagers[scheme+"://"+host] = sm
+	c.secretManagers = secretManagers
+}
+
 func (c *VFSContext) buildMemFSPath(p string) (*MemFSPath, error) {
 	if !strings.HasPrefix(p, "memfs://") {
 		return nil, fmt.Errorf("memfs path not recognized: %q", p)
@@ -0,0 +1,203 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfs
+
+import (
+	"bytes"
+	"context"
+	"encoding/base32"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"golang.org/x/oauth2/google"
+)
+
+// gcpSecretManager stores secrets in GCP Secret Manager.
+// Secret IDs only allow letters, digits, dashes and underscores, so the ID of each secret is
+// the encoded key, with a prefix; the key is also recorded in an annotation.
+type gcpSecretManager struct {
+	project string
+	client  *http.Client
+}
+
+var _ SecretManager = &gcpSecretManager{}
+
+const (
+	gcpSecretManagerIDPrefix      = "kops-"
+	gcpSecretManagerKeyAnnotation = "kops.k8s.io/key"
+)
+
+var gcpSecretManagerEncoding = base32.HexEncoding.WithPadding(base32.NoPadding)
+
+func newGCPSecretManager(ctx context.Context, project string) (*gcpSecretManager, error) {
+	client, err := google.DefaultClient(ctx, "https://www.googleapis.com/auth/cloud-platform")
+	if err != nil {
+		return nil, fmt.Errorf("error building GCP client: %w", err)
+	}
+	return &gcpSecretManager{project: project, client: client}, nil
+}
+
+func gcpSecretID(key string) string {
+	return gcpSecretManagerIDPrefix + strings.ToLower(gcpSecretManagerEncoding.EncodeToString([]byte(key)))
+}
+
+func gcpSecretKey(id string) (string, bool) {
+	if !strings.HasPrefix(id, gcpSecretManagerIDPrefix) {
+		return "", false
+	}
+	b, err := gcpSecretManagerEncoding.DecodeString(strings.ToUpper(strings.TrimPrefix(id, gcpSecretManagerIDPrefix)))
+	if err != nil {
+		return "", false
+	}
+	return string(b), true
+}
+
+func (m *gcpSecretManager) do(ctx context.Context, method string, urlPath string, request any, response any) (int, error) {
+	var body io.Reader
+	if request != nil {
+		b, err := json.Marshal(request)
+		if err != nil {
+			return 0, err
+		}
+		body = bytes.NewReader(b)
+	}
+	httpRequest, err := http.NewRequestWithContext(ctx, method, "https://secretmanager.googleapis.com/v1/projects/"+m.project+"/"+urlPath, body)
+	if err != nil {
+		return 0, err
+	}
+	if request != nil {
+		httpRequest.Header.Set("Content-Type", "application/json")
+	}
+
+	httpResponse, err := m.client.Do(httpRequest)
+	if err != nil {
+		return 0, err
+	}
+	defer httpResponse.Body.Close()
+
+	b, err := io.ReadAll(httpResponse.Body)
+	if err != nil {
+		return httpResponse.StatusCode, err
+	}
+	switch httpResponse.StatusCode {
+	case http.StatusOK:
+		if response != nil {
+			if err := json.Unmarshal(b, response); err != nil {
+				return httpResponse.StatusCode, fmt.Errorf("error parsing secret manager response: %w", err)
+			}
+		}
+		return httpResponse.StatusCode, nil
+	case http.StatusNotFound, http.StatusConflict:
+		return httpResponse.StatusCode, nil
+	default:
+		return httpResponse.StatusCode, fmt.Errorf("unexpected status %d from secret manager: %s", httpResponse.StatusCode, strings.TrimSpace(string(b)))
+	}
+}
+
+func (m *gcpSecretManager) Get(ctx context.Context, key string) ([]byte, error) {
+	var response struct {
+		Payload struct {
+			Data []byte `json:"data"`
+		} `json:"payload"`
+	}
+	status, err := m.do(ctx, http.MethodGet, "secrets/"+gcpSecretID(key)+"/versions/latest:access", nil, &response)
+	if err != nil {
+		return nil, err
+	}
+	if status == http.StatusNotFound {
+		return nil, os.ErrNotExist
+	}
+	return response.Payload.Data, nil
+}
+
+func (m *gcpSecretManager) Put(ctx context.Context, key string, data []byte, create bool) error {
+	id := gcpSecretID(key)
+
+	secret := map[string]any{
+		"replication": map[string]any{"automatic": map[string]any{}},
+		"annotations": map[string]string{gcpSecretManagerKeyAnnotation: key},
+	}
+	status, err := m.do(ctx, http.MethodPost, "secrets?secretId="+url.QueryEscape(id), secret, nil)
+	if err != nil {
+		return err
+	}
+	if status == http.StatusConflict && create {
+		// The secret may exist without a version, if a previous write failed
+		if _, err := m.Get(ctx, key); !os.IsNotExist(err) {
+			return os.ErrExist
+		}
+	}
+
+	version := map[string]any{
+		"payload": map[string]any{"data": data},
+	}
+	status, err = m.do(ctx, http.MethodPost, "secrets/"+id+":addVersion", version, nil)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("unexpected status %d adding version of secret %q", status, id)
+	}
+	return nil
+}
+
+func (m *gcpSecretManager) Delete(ctx context.Context, key string) error {
+	status, err := m.do(ctx, http.MethodDelete, "secrets/"+gcpSecretID(key), nil, nil)
+	if err != nil {
+		return err
+	}
+	if status == http.StatusNotFound {
+		return os.ErrNotExist
+	}
+	return nil
+}
+
+func (m *gcpSecretManager) List(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	pageToken := ""
+	for {
+		query := url.Values{}
+		query.Set("filter", "name:"+gcpSecretManagerIDPrefix)
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+		var response struct {
+			Secrets []struct {
+				Name string `json:"name"`
+			} `json:"secrets"`
+			NextPageToken string `json:"nextPageToken"`
+		}
+		if _, err := m.do(ctx, http.MethodGet, "secrets?"+query.Encode(), nil, &response); err != nil {
+			return nil, err
+		}
+		for _, secret := range response.Secrets {
+			key, ok := gcpSecretKey(secret.Name[strings.LastIndex(secret.Name, "/")+1:])
+			if ok && strings.HasPrefix(key, prefix) {
+				keys = append(keys, key)
+			}
+		}
+		if response.NextPageToken == "" {
+			return keys, nil
+		}
+		pageToken = response.NextPageToken
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"net/http"
 	"os"
 	"path"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -52,6 +53,7 @@ var (
 	_ Path          = &GSPath{}
 	_ TerraformPath = &GSPath{}
 	_ HasHash       = &GSPath{}
+	_ HasVersion    = &GSPath{}
 )
 
 // gcsReadBackoff is the backoff strategy for GCS read retries
@@ -173,11 +175,37 @@ func (p *GSPath) Join(relativePath ...string) Path {
 }
 
 func (p *GSPath) WriteFile(ctx context.Context, data io.ReadSeeker, acl ACL) error {
+	_, err := p.insert(ctx, data, acl, nil)
+	return err
+}
+
+// WriteFileIfVersion implements HasVersion::WriteFileIfVersion, using a generation precondition
+func (p *GSPath) WriteFileIfVersion(ctx context.Context, data io.ReadSeeker, acl ACL, version string) (string, error) {
+	// Generation 0 means the object must not exist
+	var generation int64
+	if version != "" {
+		var err error
+		generation, err = strconv.ParseInt(version, 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid version %q for %s: %w", version, p, err)
+		}
+	}
+
+	obj, err := p.insert(ctx, data, acl, &generation)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(obj.Generation, 10), nil
+}
+
+// insert writes the object, only if its current generation matches ifGenerationMatch when it is not nil
+func (p *GSPath) insert(ctx context.Context, data io.ReadSeeker, acl ACL, ifGenerationMatch *int64) (*storage.Object, error) {
 	md5Hash, err := hashing.HashAlgorithmMD5.Hash(data)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	var written *storage.Object
 	done, err := RetryWithBackoff(gcsWriteBackoff, func() (bool, error) {
 		obj := &storage.Object{
 			Name:    p.key,
@@ -204,20 +232,28 @@ func (p *GSPath) WriteFile(ctx context.Context, data io.ReadSeeker, acl ACL) err
 			return false, err
 		}
 
-		_, err = client.Objects.Insert(p.bucket, obj).Context(ctx).Media(data).Do()
+		call := client.Objects.Insert(p.bucket, obj).Context(ctx).Media(data)
+		if ifGenerationMatch != nil {
+			call = call.IfGenerationMatch(*ifGenerationMatch)
+		}
+		written, err = call.Do()
 		if err != nil {
+			if isGCSPreconditionFailed(err) {
+				// Not recoverable
+				return true, fmt.Errorf("error writing %s: %w", p, ErrVersionConflict)
+			}
 			return false, fmt.Errorf("error writing %s: %v", p, err)
 		}
 
 		return true, nil
 	})
 	if err != nil {
-		return err
+		return nil, err
 	} else if done {
-		return nil
+		return written, nil
 	} else {
 		// Shouldn't happen - we always return a non-nil error with false
-		return wait.ErrWaitTimeout
+		return nil, wait.ErrWaitTimeout
 	}
 }
 
@@ -270,30 +306,65 @@ func (p *GSPath) ReadFile(ctx context.Context) ([]byte, error) {
 	}
 }
 
+// ReadFileWithVersion implements HasVersion::ReadFileWithVersion, returning the generation as the version
+func (p *GSPath) ReadFileWithVersion(ctx context.Context) ([]byte, string, error) {
+	var b bytes.Buffer
+	var generation string
+	done, err := RetryWithBackoff(gcsReadBackoff, func() (bool, error) {
+		b.Reset()
+		var err error
+		_, generation, err = p.download(ctx, &b)
+		if err != nil {
+			if os.IsNotExist(err) {
+				// Not recoverable
+				return true, err
+			}
+			return false, err
+		}
+		// Success!
+		return true, nil
+	})
+	if err != nil {
+		return nil, "", err
+	} else if done {
+		return b.Bytes(), generation, nil
+	} else {
+		// Shouldn't happen - we always return a non-nil error with false
+		return nil, "", wait.ErrWaitTimeout
+	}
+}
+
 // WriteTo implements io.WriterTo::WriteTo
 func (p *GSPath) WriteTo(out io.Writer) (int64, error) {
 	ctx := context.TODO()
 
+	n, _, err := p.download(ctx, out)
+	return n, err
+}
+
+// download copies the contents of the object to out, and returns its generation
+func (p *GSPath) download(ctx context.Context, out io.Writer) (int64, string, error) {
 	klog.V(4).Infof("Reading file %q", p)
 
 	client, err := p.getStorageClient(ctx)
 	if err != nil {
-		return 0, err
+		return 0, "", err
 	}
 
 	response, err := client.Objects.Get(p.bucket, p.key).Context(ctx).Download()
 	if err != nil {
 		if isGCSNotFound(err) {
-			return 0, os.ErrNotExist
+			return 0, "", os.ErrNotExist
 		}
-		return 0, fmt.Errorf("error reading %s: %v", p, err)
+		return 0, "", fmt.Errorf("error reading %s: %v", p, err)
 	}
 	if response == nil {
-		return 0, fmt.Errorf("no response returned from reading %s", p)
+		return 0, "", fmt.Errorf("no response returned from reading %s", p)
 	}
 	defer response.Body.Close()
 
-	return io.Copy(out, response.Body)
+	n, err := io.Copy(out, response.Body)
+	return n, response.Header.Get("X-Goog-Generation"), err
 }
 
 // ReadDir implements Path::ReadDir
@@ -525,6 +596,14 @@ func isGCSNotFound(err error) bool {
 	return ok && ae.Code == http.StatusNotFound
 }
 
+func isGCSPreconditionFailed(err error) bool {
+	if err == nil {
+		return false
+	}
+	ae, ok := err.(*googleapi.Error)
+	return ok && ae.Code == http.StatusPreconditionFailed
+}
+
 func (p *GSPath) getStorageClient(ctx context.Context) (*storage.Service, error) {
 	return p.vfsContext.getGCSClient(ctx)
 }
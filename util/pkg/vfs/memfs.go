@@ -22,6 +22,7 @@ import (
 	"io"
 	"os"
 	"path"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -36,11 +37,14 @@ type MemFSPath struct {
 	mutex    sync.Mutex
 	contents []byte
 	children map[string]*MemFSPath
+	// version is incremented on each write, for conditional writes
+	version int
 }
 
 var (
 	_ Path          = &MemFSPath{}
 	_ TerraformPath = &MemFSPath{}
+	_ HasVersion    = &MemFSPath{}
 )
 
 type MemFSContext struct {
@@ -112,9 +116,39 @@ func (p *MemFSPath) WriteFile(ctx context.Context, r io.ReadSeeker, acl ACL) err
 	}
 	p.contents = data
 	p.acl = acl
+	p.version++
 	return nil
 }
 
+// ReadFileWithVersion implements HasVersion::ReadFileWithVersion
+func (p *MemFSPath) ReadFileWithVersion(ctx context.Context) ([]byte, string, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.contents == nil {
+		return nil, "", os.ErrNotExist
+	}
+	return p.contents, strconv.Itoa(p.version), nil
+}
+
+// WriteFileIfVersion implements HasVersion::WriteFileIfVersion
+func (p *MemFSPath) WriteFileIfVersion(ctx context.Context, data io.ReadSeeker, acl ACL, version string) (string, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	current := ""
+	if p.contents != nil {
+		current = strconv.Itoa(p.version)
+	}
+	if current != version {
+		return "", fmt.Errorf("error writing %s: %w", p, ErrVersionConflict)
+	}
+	if err := p.WriteFile(ctx, data, acl); err != nil {
+		return "", err
+	}
+	return strconv.Itoa(p.version), nil
+}
+
 func (p *MemFSPath) CreateFile(ctx context.Context, data io.ReadSeeker, acl ACL) error {
 	// Check if exists
 	if p.contents != nil {
@@ -18,6 +18,7 @@ package vfs
 
 import (
 	"bytes"
+	"errors"
 	"os"
 	"reflect"
 	"sort"
@@ -65,6 +66,49 @@ func TestMemFsCreateFile(t *testing.T) {
 	}
 }
 
+func TestMemFsWriteFileIfVersion(t *testing.T) {
+	ctx := testcontext.ForTest(t)
+
+	memfspath := NewMemFSPath(NewMemFSContext(), "/root/test.data")
+
+	// An empty version creates the file
+	created, err := memfspath.WriteFileIfVersion(ctx, bytes.NewReader([]byte("v1")), nil, "")
+	if err != nil {
+		t.Fatalf("Failed creating file: %v", err)
+	}
+	if _, err := memfspath.WriteFileIfVersion(ctx, bytes.NewReader([]byte("v1")), nil, ""); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("Expected ErrVersionConflict creating existing file, got: %v", err)
+	}
+
+	data, version, err := memfspath.ReadFileWithVersion(ctx)
+	if err != nil {
+		t.Fatalf("Failed reading file: %v", err)
+	}
+	if string(data) != "v1" || version != created {
+		t.Errorf("Expected contents %q at version %q, got %q at version %q", "v1", created, data, version)
+	}
+
+	updated, err := memfspath.WriteFileIfVersion(ctx, bytes.NewReader([]byte("v2")), nil, version)
+	if err != nil {
+		t.Fatalf("Failed updating file: %v", err)
+	}
+	if updated == version {
+		t.Errorf("Expected version to change on write, got %q", updated)
+	}
+
+	// A write based on the previous version conflicts
+	if _, err := memfspath.WriteFileIfVersion(ctx, bytes.NewReader([]byte("v3")), nil, version); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("Expected ErrVersionConflict, got: %v", err)
+	}
+	data, err = memfspath.ReadFile(ctx)
+	if err != nil {
+		t.Fatalf("Failed reading file: %v", err)
+	}
+	if string(data) != "v2" {
+		t.Errorf("Expected contents %q after conflict, got %q", "v2", data)
+	}
+}
+
 func TestMemFsReadDir(t *testing.T) {
 	tests := []struct {
 		path     string
@@ -57,6 +57,7 @@ var (
 	_ Path          = &S3Path{}
 	_ TerraformPath = &S3Path{}
 	_ HasHash       = &S3Path{}
+	_ HasVersion    = &S3Path{}
 )
 
 // S3Acl is an ACL implementation for objects on S3
@@ -315,24 +316,69 @@ func (p *S3Path) WriteFile(ctx context.Context, data io.ReadSeeker, aclObj ACL)
 	ctx, span := tracer.Start(ctx, "S3Path::WriteFile", trace.WithAttributes(attribute.String("path", p.String())))
 	defer span.End()
 
+	_, err := p.putObject(ctx, data, aclObj, nil)
+	return err
+}
+
+// WriteFileIfVersion implements HasVersion::WriteFileIfVersion, using S3 conditional writes on the ETag
+func (p *S3Path) WriteFileIfVersion(ctx context.Context, data io.ReadSeeker, aclObj ACL, version string) (string, error) {
+	ctx, span := tracer.Start(ctx, "S3Path::WriteFileIfVersion", trace.WithAttributes(attribute.String("path", p.String())))
+	defer span.End()
+
+	response, err := p.putObject(ctx, data, aclObj, func(request *s3.PutObjectInput) {
+		if version == "" {
+			request.IfNoneMatch = aws.String("*")
+		} else {
+			request.IfMatch = aws.String(version)
+		}
+	})
+	if err != nil {
+		switch AWSErrorCode(err) {
+		case "PreconditionFailed", "ConditionalRequestConflict":
+			return "", fmt.Errorf("error writing %s: %w", p, ErrVersionConflict)
+		case "NotImplemented":
+			// Some S3-compatible stores do not support conditional writes.
+			// Writing unconditionally can overwrite concurrent changes, so it must be allowed explicitly.
+			if os.Getenv("KOPS_STATE_S3_UNCONDITIONAL_WRITES") != "true" {
+				return "", fmt.Errorf("error writing %s: %w; set KOPS_STATE_S3_UNCONDITIONAL_WRITES=true to write without detecting concurrent changes", p, ErrConditionalWritesUnsupported)
+			}
+			klog.Warningf("conditional writes not supported for %s, writing unconditionally as KOPS_STATE_S3_UNCONDITIONAL_WRITES is set", p)
+			if _, err := p.putObject(ctx, data, aclObj, nil); err != nil {
+				return "", err
+			}
+			return "", nil
+		}
+		return "", err
+	}
+	return aws.ToString(response.ETag), nil
+}
+
+func (p *S3Path) putObject(ctx context.Context, data io.ReadSeeker, aclObj ACL, precondition func(request *s3.PutObjectInput)) (*s3.PutObjectOutput, error) {
 	client, err := p.client(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	klog.V(4).Infof("Writing file %q", p)
 
+	if _, err := data.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("error seeking to start of data stream for write to %s: %v", p, err)
+	}
+
 	request := &s3.PutObjectInput{}
 	request.Body = data
 	request.Bucket = aws.String(p.bucket)
 	request.Key = aws.String(p.key)
+	if precondition != nil {
+		precondition(request)
+	}
 
 	var sseLog string
 	request.ServerSideEncryption, sseLog, _ = p.getServerSideEncryption(ctx)
 
 	acl, err := p.getRequestACL(aclObj)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if acl != nil {
 		request.ACL = *acl
@@ -342,15 +388,15 @@ func (p *S3Path) WriteFile(ctx context.Context, data io.ReadSeeker, aclObj ACL)
 
 	klog.V(8).Infof("Calling S3 PutObject Bucket=%q Key=%q SSE=%q ACL=%q", p.bucket, p.key, sseLog, request.ACL)
 
-	_, err = client.PutObject(ctx, request)
+	response, err := client.PutObject(ctx, request)
 	if err != nil {
 		if len(request.ACL) > 0 {
-			return fmt.Errorf("error writing %s (with ACL=%q): %v", p, request.ACL, err)
+			return nil, fmt.Errorf("error writing %s (with ACL=%q): %w", p, request.ACL, err)
 		}
-		return fmt.Errorf("error writing %s: %v", p, err)
+		return nil, fmt.Errorf("error writing %s: %w", p, err)
 	}
 
-	return nil
+	return response, nil
 }
 
 // To prevent concurrent creates on the same file while maintaining atomicity of writes,
@@ -389,6 +435,19 @@ func (p *S3Path) ReadFile(ctx context.Context) ([]byte, error) {
 	return b.Bytes(), nil
 }
 
+// ReadFileWithVersion implements HasVersion::ReadFileWithVersion, returning the ETag as the version
+func (p *S3Path) ReadFileWithVersion(ctx context.Context) ([]byte, string, error) {
+	ctx, span := tracer.Start(ctx, "S3Path::ReadFileWithVersion", trace.WithAttributes(attribute.String("path", p.String())))
+	defer span.End()
+
+	var b bytes.Buffer
+	_, response, err := p.getObject(ctx, &b)
+	if err != nil {
+		return nil, "", err
+	}
+	return b.Bytes(), aws.ToString(response.ETag), nil
+}
+
 // WriteTo implements io.WriterTo
 func (p *S3Path) WriteTo(out io.Writer) (int64, error) {
 	ctx := context.TODO()
@@ -397,9 +456,15 @@ func (p *S3Path) WriteTo(out io.Writer) (int64, error) {
 
 // WriteToWithContext implements io.WriterTo, but adds a context
 func (p *S3Path) WriteToWithContext(ctx context.Context, out io.Writer) (int64, error) {
+	n, _, err := p.getObject(ctx, out)
+	return n, err
+}
+
+// getObject copies the contents of the object to out
+func (p *S3Path) getObject(ctx context.Context, out io.Writer) (int64, *s3.GetObjectOutput, error) {
 	client, err := p.client(ctx)
 	if err != nil {
-		return 0, err
+		return 0, nil, err
 	}
 
 	klog.V(4).Infof("Reading file %q", p)
@@ -411,17 +476,17 @@ func (p *S3Path) WriteToWithContext(ctx context.Context, out io.Writer) (int64,
 	response, err := client.GetObject(ctx, request)
 	if err != nil {
 		if AWSErrorCode(err) == "NoSuchKey" {
-			return 0, os.ErrNotExist
+			return 0, nil, os.ErrNotExist
 		}
-		return 0, fmt.Errorf("error fetching %s: %v", p, err)
+		return 0, nil, fmt.Errorf("error fetching %s: %v", p, err)
 	}
 	defer response.Body.Close()
 
 	n, err := io.Copy(out, response.Body)
 	if err != nil {
-		return n, fmt.Errorf("error reading %s: %v", p, err)
+		return n, nil, fmt.Errorf("error reading %s: %v", p, err)
 	}
-	return n, nil
+	return n, response, nil
 }
 
 func (p *S3Path) ReadDir() ([]Path, error) {
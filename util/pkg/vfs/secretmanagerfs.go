@@ -0,0 +1,296 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// SecretManager is a store of small secret objects, such as HashiCorp Vault or a cloud secret manager.
+// Keys are slash-separated paths.
+type SecretManager interface {
+	// Get returns the contents of the secret, or os.ErrNotExist if it does not exist.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Put writes the secret. If create is true, it returns os.ErrExist if the secret already exists.
+	Put(ctx context.Context, key string, data []byte, create bool) error
+	// Delete deletes the secret, with all its versions.
+	Delete(ctx context.Context, key string) error
+	// List returns the keys of all the secrets below the prefix, recursively.
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// SecretManagerPath is a path in a SecretManager.
+// Secret managers are used for the keystore and secrets, so that they are not kept in object storage.
+type SecretManagerPath struct {
+	scheme string
+	host   string
+	key    string
+
+	// secretManager returns the client, which is only built when first used
+	secretManager func(ctx context.Context) (SecretManager, error)
+}
+
+var _ Path = &SecretManagerPath{}
+
+func newSecretManagerPath(scheme string, host string, key string, secretManager func(ctx context.Context) (SecretManager, error)) *SecretManagerPath {
+	return &SecretManagerPath{
+		scheme:        scheme,
+		host:          strings.TrimSuffix(host, "/"),
+		key:           strings.Trim(key, "/"),
+		secretManager: secretManager,
+	}
+}
+
+// Scheme returns the scheme of the path, which identifies the secret manager.
+func (p *SecretManagerPath) Scheme() string {
+	return p.scheme
+}
+
+// Host returns the host of the path: the Vault server, the AWS region or the GCP project.
+func (p *SecretManagerPath) Host() string {
+	return p.host
+}
+
+// Key returns the key of the path within the secret manager.
+func (p *SecretManagerPath) Key() string {
+	return p.key
+}
+
+// IsClusterReadable returns true only for AWS Secrets Manager, where the control plane role is granted read access.
+// kops does not grant nodes access to Vault or GCP Secret Manager.
+func (p *SecretManagerPath) IsClusterReadable() bool {
+	return p.scheme == "awssm"
+}
+
+func (p *SecretManagerPath) Path() string {
+	return p.scheme + "://" + p.host + "/" + p.key
+}
+
+func (p *SecretManagerPath) String() string {
+	return p.Path()
+}
+
+func (p *SecretManagerPath) Base() string {
+	return path.Base(p.key)
+}
+
+func (p *SecretManagerPath) Join(relativePath ...string) Path {
+	args := []string{p.key}
+	args = append(args, relativePath...)
+	joined := path.Join(args...)
+	return newSecretManagerPath(p.scheme, p.host, joined, p.secretManager)
+}
+
+func (p *SecretManagerPath) ReadFile(ctx context.Context) ([]byte, error) {
+	sm, err := p.secretManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+	data, err := sm.Get(ctx, p.key)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("error reading %s: %w", p, err)
+	}
+	return data, nil
+}
+
+// WriteTo implements io.WriterTo
+func (p *SecretManagerPath) WriteTo(out io.Writer) (int64, error) {
+	data, err := p.ReadFile(context.TODO())
+	if err != nil {
+		return 0, err
+	}
+	n, err := out.Write(data)
+	return int64(n), err
+}
+
+func (p *SecretManagerPath) WriteFile(ctx context.Context, data io.ReadSeeker, acl ACL) error {
+	return p.put(ctx, data, false)
+}
+
+func (p *SecretManagerPath) CreateFile(ctx context.Context, data io.ReadSeeker, acl ACL) error {
+	return p.put(ctx, data, true)
+}
+
+func (p *SecretManagerPath) put(ctx context.Context, r io.ReadSeeker, create bool) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("error reading data: %w", err)
+	}
+	sm, err := p.secretManager(ctx)
+	if err != nil {
+		return err
+	}
+	if err := sm.Put(ctx, p.key, data, create); err != nil {
+		if os.IsExist(err) {
+			return err
+		}
+		return fmt.Errorf("error writing %s: %w", p, err)
+	}
+	return nil
+}
+
+func (p *SecretManagerPath) Remove(ctx context.Context) error {
+	sm, err := p.secretManager(ctx)
+	if err != nil {
+		return err
+	}
+	if err := sm.Delete(ctx, p.key); err != nil {
+		if os.IsNotExist(err) {
+			return err
+		}
+		return fmt.Errorf("error deleting %s: %w", p, err)
+	}
+	return nil
+}
+
+func (p *SecretManagerPath) RemoveAll(ctx context.Context) error {
+	tree, err := p.ReadTree(ctx)
+	if err != nil {
+		return err
+	}
+	for _, child := range tree {
+		if err := child.Remove(ctx); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *SecretManagerPath) RemoveAllVersions(ctx context.Context) error {
+	return p.Remove(ctx)
+}
+
+func (p *SecretManagerPath) ReadDir() ([]Path, error) {
+	ctx := context.TODO()
+
+	keys, err := p.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := p.key + "/"
+	seen := make(map[string]bool)
+	var children []Path
+	for _, key := range keys {
+		name := strings.SplitN(strings.TrimPrefix(key, prefix), "/", 2)[0]
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		children = append(children, p.Join(name))
+	}
+	if len(children) == 0 {
+		return nil, os.ErrNotExist
+	}
+	return children, nil
+}
+
+func (p *SecretManagerPath) ReadTree(ctx context.Context) ([]Path, error) {
+	keys, err := p.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var children []Path
+	for _, key := range keys {
+		children = append(children, newSecretManagerPath(p.scheme, p.host, key, p.secretManager))
+	}
+	return children, nil
+}
+
+// list returns the keys below the path, in order.
+func (p *SecretManagerPath) list(ctx context.Context) ([]string, error) {
+	sm, err := p.secretManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+	prefix := p.key + "/"
+	keys, err := sm.List(ctx, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("error listing %s: %w", p, err)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// MemSecretManager is an in-memory SecretManager, for tests.
+type MemSecretManager struct {
+	mutex   sync.Mutex
+	secrets map[string][]byte
+}
+
+var _ SecretManager = &MemSecretManager{}
+
+// NewMemSecretManager builds an empty MemSecretManager.
+func NewMemSecretManager() *MemSecretManager {
+	return &MemSecretManager{secrets: make(map[string][]byte)}
+}
+
+func (m *MemSecretManager) Get(ctx context.Context, key string) ([]byte, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	data, found := m.secrets[key]
+	if !found {
+		return nil, os.ErrNotExist
+	}
+	return append([]byte{}, data...), nil
+}
+
+func (m *MemSecretManager) Put(ctx context.Context, key string, data []byte, create bool) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, found := m.secrets[key]; found && create {
+		return os.ErrExist
+	}
+	m.secrets[key] = append([]byte{}, data...)
+	return nil
+}
+
+func (m *MemSecretManager) Delete(ctx context.Context, key string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, found := m.secrets[key]; !found {
+		return os.ErrNotExist
+	}
+	delete(m.secrets, key)
+	return nil
+}
+
+func (m *MemSecretManager) List(ctx context.Context, prefix string) ([]string, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var keys []string
+	for key := range m.secrets {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
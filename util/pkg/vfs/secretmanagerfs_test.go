@@ -0,0 +1,243 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfs
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"k8s.io/kops/pkg/testutils/testcontext"
+)
+
+// testSecretManagerPath exercises a secret manager path, which must be empty.
+func testSecretManagerPath(t *testing.T, base Path) {
+	ctx := testcontext.ForTest(t)
+
+	p := base.Join("pki", "private", "kubernetes-ca", "keyset.yaml")
+	if _, err := p.ReadFile(ctx); !os.IsNotExist(err) {
+		t.Fatalf("expected not found reading %s, got %v", p, err)
+	}
+
+	if err := p.CreateFile(ctx, bytes.NewReader([]byte("v1")), nil); err != nil {
+		t.Fatalf("failed creating %s: %v", p, err)
+	}
+	if err := p.CreateFile(ctx, bytes.NewReader([]byte("v2")), nil); !os.IsExist(err) {
+		t.Errorf("expected os.ErrExist creating %s again, got %v", p, err)
+	}
+	if err := p.WriteFile(ctx, bytes.NewReader([]byte("v3")), nil); err != nil {
+		t.Fatalf("failed writing %s: %v", p, err)
+	}
+	data, err := p.ReadFile(ctx)
+	if err != nil {
+		t.Fatalf("failed reading %s: %v", p, err)
+	}
+	if string(data) != "v3" {
+		t.Errorf("unexpected contents of %s: %q", p, data)
+	}
+
+	if err := base.Join("secrets", "admin").WriteFile(ctx, bytes.NewReader([]byte("secret")), nil); err != nil {
+		t.Fatalf("failed writing secret: %v", err)
+	}
+
+	children, err := base.ReadDir()
+	if err != nil {
+		t.Fatalf("failed reading dir %s: %v", base, err)
+	}
+	var names []string
+	for _, child := range children {
+		names = append(names, child.Base())
+	}
+	sort.Strings(names)
+	if !reflect.DeepEqual(names, []string{"pki", "secrets"}) {
+		t.Errorf("unexpected children of %s: %v", base, names)
+	}
+
+	tree, err := base.ReadTree(ctx)
+	if err != nil {
+		t.Fatalf("failed reading tree %s: %v", base, err)
+	}
+	var paths []string
+	for _, child := range tree {
+		paths = append(paths, child.Path())
+	}
+	expected := []string{
+		base.Join("pki", "private", "kubernetes-ca", "keyset.yaml").Path(),
+		base.Join("secrets", "admin").Path(),
+	}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("unexpected tree of %s: got %v, expected %v", base, paths, expected)
+	}
+
+	if err := base.RemoveAll(ctx); err != nil {
+		t.Fatalf("failed removing %s: %v", base, err)
+	}
+	if _, err := p.ReadFile(ctx); !os.IsNotExist(err) {
+		t.Errorf("expected not found reading %s after removal, got %v", p, err)
+	}
+	if _, err := base.ReadDir(); !os.IsNotExist(err) {
+		t.Errorf("expected not found reading dir %s after removal, got %v", base, err)
+	}
+}
+
+func TestSecretManagerPath(t *testing.T) {
+	vfsContext := NewVFSContext()
+	vfsContext.SetSecretManager("awssm", "us-east-1", NewMemSecretManager())
+
+	base, err := vfsContext.BuildVfsPath("awssm://us-east-1/clusters/example.com")
+	if err != nil {
+		t.Fatalf("failed building path: %v", err)
+	}
+	if base.Path() != "awssm://us-east-1/clusters/example.com" {
+		t.Errorf("unexpected path %q", base.Path())
+	}
+	testSecretManagerPath(t, base)
+}
+
+func TestBuildSecretManagerPathInvalid(t *testing.T) {
+	vfsContext := NewVFSContext()
+	for _, p := range []string{"vault:///secret/kops", "vault://vault.example.com:8200/secret", "gsm:///kops"} {
+		if _, err := vfsContext.BuildVfsPath(p); err == nil {
+			t.Errorf("expected error building %q", p)
+		}
+	}
+}
+
+func TestSecretManagerPathIsClusterReadable(t *testing.T) {
+	vfsContext := NewVFSContext()
+	grid := map[string]bool{
+		"awssm://us-east-1/clusters/example.com":                     true,
+		"gsm://example-project/clusters/example.com":                 false,
+		"vault://vault.example.com:8200/secret/clusters/example.com": false,
+	}
+	for p, expected := range grid {
+		base, err := vfsContext.BuildVfsPath(p)
+		if err != nil {
+			t.Fatalf("failed building path %q: %v", p, err)
+		}
+		if actual := IsClusterReadable(base); actual != expected {
+			t.Errorf("unexpected IsClusterReadable for %q: expected %v, got %v", p, expected, actual)
+		}
+	}
+}
+
+// fakeVaultKV is a minimal implementation of the Vault KV version 2 API.
+type fakeVaultKV struct {
+	mutex   sync.Mutex
+	secrets map[string]json.RawMessage
+}
+
+func (f *fakeVaultKV) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if r.Header.Get("X-Vault-Token") != "test-token" {
+		http.Error(w, `{"errors":["permission denied"]}`, http.StatusForbidden)
+		return
+	}
+
+	mount, rest, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/v1/"), "/")
+	kind, secretPath, _ := strings.Cut(rest, "/")
+	if mount != "secret" {
+		http.NotFound(w, r)
+		return
+	}
+	key := secretPath
+
+	switch {
+	case kind == "data" && r.Method == http.MethodGet:
+		data, found := f.secrets[key]
+		if !found {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"data": data}})
+
+	case kind == "data" && r.Method == http.MethodPost:
+		var request struct {
+			Data    json.RawMessage `json:"data"`
+			Options *struct {
+				CAS int `json:"cas"`
+			} `json:"options"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if _, found := f.secrets[key]; found && request.Options != nil && request.Options.CAS == 0 {
+			http.Error(w, `{"errors":["check-and-set parameter did not match the current version"]}`, http.StatusBadRequest)
+			return
+		}
+		f.secrets[key] = request.Data
+		json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"version": 1}})
+
+	case kind == "metadata" && r.Method == http.MethodDelete:
+		delete(f.secrets, key)
+		w.WriteHeader(http.StatusNoContent)
+
+	case kind == "metadata" && r.Method == "LIST":
+		seen := make(map[string]bool)
+		var keys []string
+		for k := range f.secrets {
+			if !strings.HasPrefix(k, key) {
+				continue
+			}
+			name := strings.TrimPrefix(k, key)
+			if i := strings.Index(name, "/"); i != -1 {
+				name = name[:i+1]
+			}
+			if !seen[name] {
+				seen[name] = true
+				keys = append(keys, name)
+			}
+		}
+		if len(keys) == 0 {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"keys": keys}})
+
+	default:
+		http.Error(w, "unexpected request", http.StatusMethodNotAllowed)
+	}
+}
+
+func TestVaultSecretManagerPath(t *testing.T) {
+	server := httptest.NewServer(&fakeVaultKV{secrets: make(map[string]json.RawMessage)})
+	defer server.Close()
+
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("failed parsing server URL: %v", err)
+	}
+	t.Setenv("VAULT_ADDR", server.URL)
+	t.Setenv("VAULT_TOKEN", "test-token")
+
+	base, err := NewVFSContext().BuildVfsPath("vault://" + u.Host + "/secret/kops/example.com")
+	if err != nil {
+		t.Fatalf("failed building path: %v", err)
+	}
+	testSecretManagerPath(t, base)
+}
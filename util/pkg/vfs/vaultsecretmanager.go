@@ -0,0 +1,232 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfs
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// vaultSecretManager stores secrets in the KV version 2 secrets engine of HashiCorp Vault.
+// The first component of each key is the mount of the secrets engine.
+// The token is read from VAULT_TOKEN, or else from ~/.vault-token.
+type vaultSecretManager struct {
+	address   string
+	token     string
+	namespace string
+	client    *http.Client
+}
+
+var _ SecretManager = &vaultSecretManager{}
+
+// newVaultSecretManager builds a client for the Vault server at host.
+// The server is reached over https, unless VAULT_ADDR refers to the same server with another scheme.
+func newVaultSecretManager(host string) (*vaultSecretManager, error) {
+	address := "https://" + host
+	if vaultAddr := os.Getenv("VAULT_ADDR"); vaultAddr != "" {
+		if u, err := url.Parse(vaultAddr); err == nil && u.Host == host {
+			address = u.Scheme + "://" + u.Host
+		}
+	}
+
+	token := os.Getenv("VAULT_TOKEN")
+	if token == "" {
+		home, err := os.UserHomeDir()
+		if err == nil {
+			b, err := os.ReadFile(filepath.Join(home, ".vault-token"))
+			if err == nil {
+				token = strings.TrimSpace(string(b))
+			}
+		}
+	}
+	if token == "" {
+		return nil, fmt.Errorf("a vault token is required; set VAULT_TOKEN")
+	}
+
+	return &vaultSecretManager{
+		address:   address,
+		token:     token,
+		namespace: os.Getenv("VAULT_NAMESPACE"),
+		client:    http.DefaultClient,
+	}, nil
+}
+
+type vaultKVData struct {
+	Value string `json:"value"`
+}
+
+// splitKey splits the key into the mount and the path of the secret within the mount.
+func (v *vaultSecretManager) splitKey(key string) (string, string, error) {
+	tokens := strings.SplitN(strings.Trim(key, "/"), "/", 2)
+	if len(tokens) != 2 || tokens[0] == "" || tokens[1] == "" {
+		return "", "", fmt.Errorf("vault key %q must be <mount>/<path>", key)
+	}
+	return tokens[0], tokens[1], nil
+}
+
+func (v *vaultSecretManager) do(ctx context.Context, method string, urlPath string, request any, response any) (int, error) {
+	var body io.Reader
+	if request != nil {
+		b, err := json.Marshal(request)
+		if err != nil {
+			return 0, err
+		}
+		body = bytes.NewReader(b)
+	}
+	httpRequest, err := http.NewRequestWithContext(ctx, method, v.address+"/v1/"+urlPath, body)
+	if err != nil {
+		return 0, err
+	}
+	httpRequest.Header.Set("X-Vault-Token", v.token)
+	if v.namespace != "" {
+		httpRequest.Header.Set("X-Vault-Namespace", v.namespace)
+	}
+	if request != nil {
+		httpRequest.Header.Set("Content-Type", "application/json")
+	}
+
+	httpResponse, err := v.client.Do(httpRequest)
+	if err != nil {
+		return 0, err
+	}
+	defer httpResponse.Body.Close()
+
+	b, err := io.ReadAll(httpResponse.Body)
+	if err != nil {
+		return httpResponse.StatusCode, err
+	}
+	switch httpResponse.StatusCode {
+	case http.StatusOK:
+		if response != nil {
+			if err := json.Unmarshal(b, response); err != nil {
+				return httpResponse.StatusCode, fmt.Errorf("error parsing vault response: %w", err)
+			}
+		}
+		return httpResponse.StatusCode, nil
+	case http.StatusNoContent, http.StatusNotFound:
+		return httpResponse.StatusCode, nil
+	default:
+		return httpResponse.StatusCode, fmt.Errorf("unexpected status %d from vault: %s", httpResponse.StatusCode, strings.TrimSpace(string(b)))
+	}
+}
+
+func (v *vaultSecretManager) Get(ctx context.Context, key string) ([]byte, error) {
+	mount, secretPath, err := v.splitKey(key)
+	if err != nil {
+		return nil, err
+	}
+	var response struct {
+		Data struct {
+			Data *vaultKVData `json:"data"`
+		} `json:"data"`
+	}
+	status, err := v.do(ctx, http.MethodGet, mount+"/data/"+secretPath, nil, &response)
+	if err != nil {
+		return nil, err
+	}
+	// A deleted version is returned with no data
+	if status == http.StatusNotFound || response.Data.Data == nil {
+		return nil, os.ErrNotExist
+	}
+	return base64.StdEncoding.DecodeString(response.Data.Data.Value)
+}
+
+func (v *vaultSecretManager) Put(ctx context.Context, key string, data []byte, create bool) error {
+	mount, secretPath, err := v.splitKey(key)
+	if err != nil {
+		return err
+	}
+	request := map[string]any{
+		"data": &vaultKVData{Value: base64.StdEncoding.EncodeToString(data)},
+	}
+	if create {
+		// A check-and-set version of 0 only allows the write if the secret does not exist
+		request["options"] = map[string]any{"cas": 0}
+	}
+	status, err := v.do(ctx, http.MethodPost, mount+"/data/"+secretPath, request, nil)
+	if err != nil {
+		if create && status == http.StatusBadRequest && strings.Contains(err.Error(), "check-and-set") {
+			return os.ErrExist
+		}
+		return err
+	}
+	if status == http.StatusNotFound {
+		return fmt.Errorf("vault KV v2 secrets engine not found at %q", mount)
+	}
+	return nil
+}
+
+func (v *vaultSecretManager) Delete(ctx context.Context, key string) error {
+	mount, secretPath, err := v.splitKey(key)
+	if err != nil {
+		return err
+	}
+	status, err := v.do(ctx, http.MethodDelete, mount+"/metadata/"+secretPath, nil, nil)
+	if err != nil {
+		return err
+	}
+	if status == http.StatusNotFound {
+		return os.ErrNotExist
+	}
+	return nil
+}
+
+func (v *vaultSecretManager) List(ctx context.Context, prefix string) ([]string, error) {
+	mount, dir, err := v.splitKey(prefix)
+	if err != nil {
+		return nil, err
+	}
+	return v.list(ctx, mount, strings.TrimSuffix(dir, "/")+"/")
+}
+
+func (v *vaultSecretManager) list(ctx context.Context, mount string, dir string) ([]string, error) {
+	var response struct {
+		Data struct {
+			Keys []string `json:"keys"`
+		} `json:"data"`
+	}
+	status, err := v.do(ctx, "LIST", mount+"/metadata/"+dir, nil, &response)
+	if err != nil {
+		return nil, err
+	}
+	if status == http.StatusNotFound {
+		return nil, nil
+	}
+
+	var keys []string
+	for _, name := range response.Data.Keys {
+		if strings.HasSuffix(name, "/") {
+			children, err := v.list(ctx, mount, dir+name)
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, children...)
+		} else {
+			keys = append(keys, mount+"/"+dir+name)
+		}
+	}
+	return keys, nil
+}
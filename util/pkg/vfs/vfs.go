@@ -18,6 +18,7 @@ package vfs
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"strings"
@@ -92,6 +93,44 @@ type HasHash interface {
 	Hash(algorithm hashing.HashAlgorithm) (*hashing.Hash, error)
 }
 
+// ErrVersionConflict is returned by conditional writes when the file was changed since it was read
+var ErrVersionConflict = errors.New("file was changed since it was read")
+
+// ErrConditionalWritesUnsupported is returned by a conditional write to a store that does not support them
+var ErrConditionalWritesUnsupported = errors.New("conditional writes are not supported by the store")
+
+// HasVersion is implemented by Paths that support conditional writes, for optimistic concurrency
+type HasVersion interface {
+	// ReadFileWithVersion returns the contents of the file, and an opaque version of the contents.
+	// If the file did not exist, err = os.ErrNotExist
+	ReadFileWithVersion(ctx context.Context) ([]byte, string, error)
+
+	// WriteFileIfVersion writes the file contents, but only if the current version of the file is version;
+	// an empty version means the file must not exist. It returns the version of the new contents.
+	// If the file was changed, err wraps ErrVersionConflict
+	WriteFileIfVersion(ctx context.Context, data io.ReadSeeker, acl ACL, version string) (string, error)
+}
+
+// ReadFileWithVersion reads the file and its version, if the Path supports conditional writes.
+// Otherwise the version is empty.
+func ReadFileWithVersion(ctx context.Context, p Path) ([]byte, string, error) {
+	if hv, ok := p.(HasVersion); ok {
+		return hv.ReadFileWithVersion(ctx)
+	}
+	data, err := p.ReadFile(ctx)
+	return data, "", err
+}
+
+// WriteFileIfVersion writes the file only if its current version is version, if the Path supports conditional writes.
+// Otherwise the file is written unconditionally, and the version returned is empty.
+func WriteFileIfVersion(ctx context.Context, p Path, data io.ReadSeeker, acl ACL, version string) (string, error) {
+	if hv, ok := p.(HasVersion); ok {
+		return hv.WriteFileIfVersion(ctx, data, acl, version)
+	}
+	klog.V(2).Infof("conditional writes not supported for %s, writing unconditionally", p)
+	return "", p.WriteFile(ctx, data, acl)
+}
+
 func RelativePath(base Path, child Path) (string, error) {
 	basePath := base.Path()
 	childPath := child.Path()
@@ -117,7 +156,8 @@ func IsClusterReadable(p Path) bool {
 		return true
 
 	case *KubernetesPath:
-		return true
+		// Nodes have no credentials for the kubernetes API holding the state
+		return false
 
 	case *SSHPath:
 		return false